@@ -15,8 +15,10 @@ package gofsutil
 import (
 	"context"
 	"errors"
-	"path/filepath"
+	"os"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // FSinterface has the methods support by gofsutils.
@@ -25,15 +27,20 @@ type FSinterface interface {
 	getDiskFormat(ctx context.Context, disk string) (string, error)
 	format(ctx context.Context, source, target, fsType string, opts ...string) error
 	formatAndMount(ctx context.Context, source, target, fsType string, opts ...string) error
+	formatAndMountMPath(ctx context.Context, mpathDevice, target, fsType string, opts MPathFormatOptions) error
 	bindMount(ctx context.Context, source, target string, opts ...string) error
 	getMounts(ctx context.Context) ([]Info, error)
+	getMountsByKind(ctx context.Context, kinds ...MountKind) ([]Info, error)
 	readProcMounts(ctx context.Context, path string, info bool) ([]Info, uint32, error)
+	scanProcMounts(ctx context.Context, fields MountEntryField, fn MountEntryFunc) error
 	mount(ctx context.Context, source, target, fsType string, opts ...string) error
+	mountWithOptions(ctx context.Context, source, target, fsType string, opts MountOptions) error
 	unmount(ctx context.Context, target string) error
 	getDevMounts(ctx context.Context, dev string) ([]Info, error)
 	validateDevice(ctx context.Context, source string) (string, error)
 	wwnToDevicePath(ctx context.Context, wwn string) (string, string, error)
 	rescanSCSIHost(ctx context.Context, targets []string, lun string) error
+	rescanSCSIHostX(ctx context.Context, targets []string, lun string) (*RescanReport, error)
 	removeBlockDevice(ctx context.Context, blockDevicePath string) error
 	targetIPLUNToDevicePath(ctx context.Context, targetIP string, lunID int) (map[string]string, error)
 	multipathCommand(ctx context.Context, timeoutSeconds time.Duration, chroot string, arguments ...string) ([]byte, error)
@@ -41,26 +48,112 @@ type FSinterface interface {
 	issueLIPToAllFCHosts(ctx context.Context) error
 	getSysBlockDevicesForVolumeWWN(ctx context.Context, volumeWWN string) ([]string, error)
 	deviceRescan(ctx context.Context, devicePath string) error
+	waitForDeviceToSettle(ctx context.Context, device string) error
 	resizeFS(ctx context.Context, volumePath, devicePath, ppathDevice, mpathDevice, fsType string) error
+	resizeFSWithOptions(ctx context.Context, volumePath, devicePath, ppathDevice, mpathDevice, fsType string, opts ResizeFSOptions) (int64, error)
 	getMountInfoFromDevice(ctx context.Context, devID string) (*DeviceMountInfo, error)
 	resizeMultipath(ctx context.Context, deviceName string) error
 	findFSType(ctx context.Context, mountpoint string) (fsType string, err error)
 	getMpathNameFromDevice(ctx context.Context, device string) (string, error)
 	fsInfo(ctx context.Context, path string) (int64, int64, int64, int64, int64, int64, error)
 	getNVMeController(device string) (string, error)
+	connectNVMeTarget(ctx context.Context, transport, traddr, trsvcid, nqn string, opts NVMeConnectOptions) error
+	disconnectNVMeTarget(ctx context.Context, nqn string) error
+	getNVMeControllersForSubsystemNQN(ctx context.Context, nqn string) ([]NVMeController, error)
+	dmSuspend(ctx context.Context, name string) error
+	dmResume(ctx context.Context, name string) error
+	getDMTable(ctx context.Context, name string) (*DMTable, error)
+	isMountPoint(ctx context.Context, path string) (bool, error)
+	isLikelyNotMountPoint(ctx context.Context, path string) (bool, error)
+	isCorruptedMount(ctx context.Context, target string) (bool, error)
+	multipathdStatus(ctx context.Context) (*MultipathdHealth, error)
+	regenerateXFSUUID(ctx context.Context, device string) error
+	regenerateExtUUID(ctx context.Context, device string) error
+	makeBlockFile(ctx context.Context, target string) error
+	bindMountBlockDevice(ctx context.Context, device, target string, opts ...string) error
+	unmountBlockDevice(ctx context.Context, target string) error
+	checkNFSExport(ctx context.Context, server, exportPath string) (*NFSExportStatus, error)
+	diskUsage(ctx context.Context, path string) (*DiskUsageInfo, error)
+	setProjectQuota(ctx context.Context, path string, projID uint32, limitBytes int64) error
+	getProjectQuota(ctx context.Context, path string, projID uint32) (*ProjectQuota, error)
+	getPartitions(ctx context.Context, device string) (*PartitionTable, error)
+	createPartition(ctx context.Context, device string, spec PartitionSpec) (*Partition, error)
+	deletePartition(ctx context.Context, device string, number int) error
+	growPartition(ctx context.Context, device string, number int) error
+	getMountsInto(ctx context.Context, infos []Info) ([]Info, error)
+	getNPIVPorts(ctx context.Context) ([]NPIVPort, error)
+	createNPIVPort(ctx context.Context, host, wwpn, wwnn string) (*NPIVPort, error)
+	deleteNPIVPort(ctx context.Context, host, wwpn string) error
+	getFCHostInfo(ctx context.Context) ([]FCHostInfo, error)
+	getISCSISessionState(ctx context.Context, targetIQN string) ([]ISCSISession, error)
+	getHostNQN(ctx context.Context, create bool) (string, error)
+	getNQNHostID(ctx context.Context, create bool) (string, error)
+	getHostIQN(ctx context.Context, create bool) (string, error)
+	ensureDeviceUnused(ctx context.Context, device string) error
+	smbMount(ctx context.Context, source, target string, creds SMBCredentials, opts ...string) error
+	getMpathDeviceForWWN(ctx context.Context, wwn string) (*MpathDevice, error)
+	blockDevSetRO(ctx context.Context, device string, readOnly bool) error
+	blockDevGetRO(ctx context.Context, device string) (bool, error)
+	cleanupDeviceForWWN(ctx context.Context, wwn string) (*DeviceCleanupReport, error)
+	isPMEMDevice(ctx context.Context, device string) (bool, error)
+	mountDAX(ctx context.Context, source, target, fsType string, opts ...string) error
+	getISCSITargetsFromByPath(ctx context.Context) ([]ISCSITargetDevice, error)
+	fcTargetWWPNLUNToDevicePath(ctx context.Context, wwpn string, lunID int) (map[string]string, error)
+	rescanSCSIHostsForLUNs(ctx context.Context, targets []string, luns []string) (*RescanReport, error)
+	setTargetPermissions(ctx context.Context, target string, uid, gid int, mode os.FileMode, recursive bool) error
+	setTargetImmutable(ctx context.Context, target string, immutable bool) error
+	getLoopDeviceForFile(ctx context.Context, file string) (string, error)
+	attachLoopDevice(ctx context.Context, file string, readOnly bool) (string, bool, error)
+	detachLoopDevice(ctx context.Context, device string) error
+	formatAndMountFile(ctx context.Context, file, target, fsType string, opts ...string) (string, error)
+	mountTmpfs(ctx context.Context, target string, sizeBytes int64, mode os.FileMode, opts ...string) error
+	getMultipathKind(ctx context.Context, wwn string) (MultipathKind, error)
+	fsHealthCheck(ctx context.Context, mountpoint string) (*FsHealth, error)
+	checkClusterStack(ctx context.Context, fsType string, args ...string) error
+	getDeviceTopology(ctx context.Context, device string) (*DeviceTopology, error)
+	getBlockDeviceHolders(ctx context.Context, device string) ([]string, error)
+	getNVMePathStates(ctx context.Context, nqn string) ([]NVMePathState, error)
+	mountSensitive(ctx context.Context, source, target, fsType string, opts, sensitiveOpts []string) error
+	mountWithSpec(ctx context.Context, spec MountSpec) error
+	getHostSCSIHosts(ctx context.Context) ([]SCSIHost, error)
+	dmNameToDevPath(ctx context.Context, name string) (*DMDevice, error)
+	devPathToDMName(ctx context.Context, devPath string) (*DMDevice, error)
+	getDevicePathsForMountPoint(ctx context.Context, target string) ([]string, error)
+	getMountsByDevID(ctx context.Context, major, minor uint32) ([]Info, error)
+	getNFSCapabilities(ctx context.Context) (*NFSCapabilities, error)
+	getISCSIHostsForTargetPortal(ctx context.Context, portal string) ([]ISCSIPortalHost, error)
+	replayIncompleteOperations(ctx context.Context) ([]JournalReplay, error)
+	removeBlockDeviceWithOptions(ctx context.Context, blockDevicePath string, opts RemoveBlockDeviceOptions) error
+	getNativeMultipathSetting(ctx context.Context) (bool, error)
+	subscribeMountChanges(ctx context.Context) (<-chan MountEvent, error)
+	sysfsReadAttr(ctx context.Context, attr string) (string, error)
+	sysfsWriteAttr(ctx context.Context, attr, value string) error
+	getDeviceForPublishPath(ctx context.Context, publishPath string) (*PublishPathDevice, error)
+	getFSGeometry(ctx context.Context, path string) (*FSGeometry, error)
+	shrinkFS(ctx context.Context, device string, newSizeBytes int64) error
+	multipathReconfigure(ctx context.Context) (*MultipathdResult, error)
+	reinstatePath(ctx context.Context, device string) (*MultipathdResult, error)
+	failPath(ctx context.Context, device string) (*MultipathdResult, error)
+	verifyDeviceReadable(ctx context.Context, device string, offset, length int64) error
+	verifyMountedCapacity(ctx context.Context, target string, expected int64, tolerance float64) error
 
 	// Architecture agnostic implementations, generally just wrappers
 	GetDiskFormat(ctx context.Context, disk string) (string, error)
 	Format(ctx context.Context, source, target, fsType string, options ...string) error
 	FormatAndMount(ctx context.Context, source, target, fsType string, options ...string) error
+	FormatAndMountMPath(ctx context.Context, mpathDevice, target, fsType string, opts MPathFormatOptions) error
 	Mount(ctx context.Context, source, target, fsType string, options ...string) error
+	MountWithOptions(ctx context.Context, source, target, fsType string, opts MountOptions) error
 	BindMount(ctx context.Context, source, target string, options ...string) error
 	Unmount(ctx context.Context, target string) error
 	GetMounts(ctx context.Context) ([]Info, error)
+	GetMountsByKind(ctx context.Context, kinds ...MountKind) ([]Info, error)
+	ScanProcMounts(ctx context.Context, fields MountEntryField, fn MountEntryFunc) error
 	GetDevMounts(ctx context.Context, dev string) ([]Info, error)
 	ValidateDevice(ctx context.Context, source string) (string, error)
 	WWNToDevicePath(ctx context.Context, wwn string) (string, string, error)
 	RescanSCSIHost(ctx context.Context, targets []string, lun string) error
+	RescanSCSIHostX(ctx context.Context, targets []string, lun string) (*RescanReport, error)
 	RemoveBlockDevice(ctx context.Context, blockDevicePath string) error
 	TargetIPLUNToDevicePath(ctx context.Context, targetIP string, lunID int) (map[string]string, error)
 	MultipathCommand(ctx context.Context, timeoutSeconds time.Duration, chroot string, arguments ...string) ([]byte, error)
@@ -68,13 +161,110 @@ type FSinterface interface {
 	IssueLIPToAllFCHosts(ctx context.Context) error
 	GetSysBlockDevicesForVolumeWWN(ctx context.Context, volumeWWN string) ([]string, error)
 	DeviceRescan(ctx context.Context, devicePath string) error
+	WaitForDeviceToSettle(ctx context.Context, device string) error
 	ResizeFS(ctx context.Context, volumePath, devicePath, ppathDevice, mpathDevice, fsType string) error
+	ResizeFSWithOptions(ctx context.Context, volumePath, devicePath, ppathDevice, mpathDevice, fsType string, opts ResizeFSOptions) (int64, error)
 	GetMountInfoFromDevice(ctx context.Context, devID string) (*DeviceMountInfo, error)
 	ResizeMultipath(ctx context.Context, deviceName string) error
 	FindFSType(ctx context.Context, mountpoint string) (fsType string, err error)
 	GetMpathNameFromDevice(ctx context.Context, device string) (string, error)
 	FsInfo(ctx context.Context, path string) (int64, int64, int64, int64, int64, int64, error)
 	GetNVMeController(device string) (string, error)
+	ConnectNVMeTarget(ctx context.Context, transport, traddr, trsvcid, nqn string, opts NVMeConnectOptions) error
+	DisconnectNVMeTarget(ctx context.Context, nqn string) error
+	GetNVMeControllersForSubsystemNQN(ctx context.Context, nqn string) ([]NVMeController, error)
+	DMSuspend(ctx context.Context, name string) error
+	DMResume(ctx context.Context, name string) error
+	GetDMTable(ctx context.Context, name string) (*DMTable, error)
+	IsMountPoint(ctx context.Context, path string) (bool, error)
+	IsLikelyNotMountPoint(ctx context.Context, path string) (bool, error)
+	IsCorruptedMount(ctx context.Context, target string) (bool, error)
+	MultipathdStatus(ctx context.Context) (*MultipathdHealth, error)
+	RegenerateXFSUUID(ctx context.Context, device string) error
+	RegenerateExtUUID(ctx context.Context, device string) error
+	MakeBlockFile(ctx context.Context, target string) error
+	BindMountBlockDevice(ctx context.Context, device, target string, options ...string) error
+	UnmountBlockDevice(ctx context.Context, target string) error
+	CheckNFSExport(ctx context.Context, server, exportPath string) (*NFSExportStatus, error)
+	MountMany(ctx context.Context, reqs []MountRequest) []MountResult
+	DiskUsage(ctx context.Context, path string) (*DiskUsageInfo, error)
+	SetProjectQuota(ctx context.Context, path string, projID uint32, limitBytes int64) error
+	GetProjectQuota(ctx context.Context, path string, projID uint32) (*ProjectQuota, error)
+	GetPartitions(ctx context.Context, device string) (*PartitionTable, error)
+	CreatePartition(ctx context.Context, device string, spec PartitionSpec) (*Partition, error)
+	DeletePartition(ctx context.Context, device string, number int) error
+	GrowPartition(ctx context.Context, device string, number int) error
+	GetMountsInto(ctx context.Context, infos []Info) ([]Info, error)
+	GetNPIVPorts(ctx context.Context) ([]NPIVPort, error)
+	CreateNPIVPort(ctx context.Context, host, wwpn, wwnn string) (*NPIVPort, error)
+	DeleteNPIVPort(ctx context.Context, host, wwpn string) error
+	GetFCHostInfo(ctx context.Context) ([]FCHostInfo, error)
+	GetISCSISessionState(ctx context.Context, targetIQN string) ([]ISCSISession, error)
+	GetHostNQN(ctx context.Context, create bool) (string, error)
+	GetNQNHostID(ctx context.Context, create bool) (string, error)
+	GetHostIQN(ctx context.Context, create bool) (string, error)
+	EnsureDeviceUnused(ctx context.Context, device string) error
+	RegisterSupportedFsType(fsTypes ...string)
+	SetSupportedFsTypes(fsTypes ...string)
+	SetDefaultMountOptions(fsType string, opts ...string)
+	DefaultMountOptions(fsType string) []string
+	SupportedFsTypes() []string
+	SMBMount(ctx context.Context, source, target string, creds SMBCredentials, opts ...string) error
+	GetCommandHistory() []CommandRecord
+	UnmountTree(ctx context.Context, root string) ([]UnmountResult, error)
+	GetMpathDeviceForWWN(ctx context.Context, wwn string) (*MpathDevice, error)
+	BlockDevSetRO(ctx context.Context, device string, readOnly bool) error
+	BlockDevGetRO(ctx context.Context, device string) (bool, error)
+	CleanupDeviceForWWN(ctx context.Context, wwn string) (*DeviceCleanupReport, error)
+	IsPMEMDevice(ctx context.Context, device string) (bool, error)
+	MountDAX(ctx context.Context, source, target, fsType string, opts ...string) error
+	GetISCSITargetsFromByPath(ctx context.Context) ([]ISCSITargetDevice, error)
+	FCTargetWWPNLUNToDevicePath(ctx context.Context, wwpn string, lunID int) (map[string]string, error)
+	RescanSCSIHostsForLUNs(ctx context.Context, targets []string, luns []string) (*RescanReport, error)
+	SetTargetPermissions(ctx context.Context, target string, uid, gid int, mode os.FileMode, recursive bool) error
+	SetTargetImmutable(ctx context.Context, target string, immutable bool) error
+	GetLoopDeviceForFile(ctx context.Context, file string) (string, error)
+	AttachLoopDevice(ctx context.Context, file string, readOnly bool) (string, error)
+	DetachLoopDevice(ctx context.Context, device string) error
+	FormatAndMountFile(ctx context.Context, file, target, fsType string, opts ...string) (string, error)
+	MountTmpfs(ctx context.Context, target string, sizeBytes int64, mode os.FileMode, opts ...string) error
+	GetMultipathKind(ctx context.Context, wwn string) (MultipathKind, error)
+	FsHealthCheck(ctx context.Context, mountpoint string) (*FsHealth, error)
+	CheckClusterStack(ctx context.Context, fsType string, opts ...string) error
+	GetDryRunActions() []DryRunAction
+	ClearDryRunActions()
+	SetTracerProvider(tp trace.TracerProvider)
+	GetDeviceTopology(ctx context.Context, device string) (*DeviceTopology, error)
+	GetBlockDeviceHolders(ctx context.Context, device string) ([]string, error)
+	GetNVMePathStates(ctx context.Context, nqn string) ([]NVMePathState, error)
+	MountSensitive(ctx context.Context, source, target, fsType string, opts, sensitiveOpts []string) error
+	MountWithSpec(ctx context.Context, spec MountSpec) error
+	StageBlockVolume(ctx context.Context, req StageRequest) (*StageResult, error)
+	UnstageBlockVolume(ctx context.Context, req UnstageRequest) (*UnstageResult, error)
+	GetHostSCSIHosts(ctx context.Context) ([]SCSIHost, error)
+	DMNameToDevPath(ctx context.Context, name string) (*DMDevice, error)
+	DevPathToDMName(ctx context.Context, devPath string) (*DMDevice, error)
+	GetDevicePathsForMountPoint(ctx context.Context, target string) ([]string, error)
+	GetMountsByDevID(ctx context.Context, major, minor uint32) ([]Info, error)
+	GetNFSCapabilities(ctx context.Context) (*NFSCapabilities, error)
+	MountNFS(ctx context.Context, source, target string, nfsOpts NFSMountOptions, opts ...string) error
+	GetISCSIHostsForTargetPortal(ctx context.Context, portal string) ([]ISCSIPortalHost, error)
+	ReplayIncompleteOperations(ctx context.Context) ([]JournalReplay, error)
+	RemoveBlockDeviceWithOptions(ctx context.Context, blockDevicePath string, opts RemoveBlockDeviceOptions) error
+	GetNativeMultipathSetting(ctx context.Context) (bool, error)
+	CheckNVMeMultipathMode(ctx context.Context, mode NVMeMultipathMode) error
+	SubscribeMountChanges(ctx context.Context) (<-chan MountEvent, error)
+	SysfsReadAttr(ctx context.Context, attr string) (string, error)
+	SysfsWriteAttr(ctx context.Context, attr, value string) error
+	GetDeviceForPublishPath(ctx context.Context, publishPath string) (*PublishPathDevice, error)
+	GetFSGeometry(ctx context.Context, path string) (*FSGeometry, error)
+	ShrinkFS(ctx context.Context, device string, newSizeBytes int64) error
+	MultipathReconfigure(ctx context.Context) (*MultipathdResult, error)
+	ReinstatePath(ctx context.Context, device string) (*MultipathdResult, error)
+	FailPath(ctx context.Context, device string) (*MultipathdResult, error)
+	VerifyDeviceReadable(ctx context.Context, device string, offset, length int64) error
+	UnmountDevice(ctx context.Context, device string) ([]UnmountResult, error)
+	VerifyMountedCapacity(ctx context.Context, target string, expected int64, tolerance float64) error
 }
 
 // MultipathDevDiskByIDPrefix is a pathname prefix for items located in /dev/disk/by-id
@@ -107,7 +297,15 @@ func UseMockSysBlockDir(mockSysBlockDir string) {
 	fs = &FS{ScanEntry: defaultEntryScanFunc, SysBlockDir: mockSysBlockDir}
 }
 
+// UseFSOptions replaces the default file system with one that uses the
+// provided options, e.g. to read the host's sysfs from /noderoot/sys.
+func UseFSOptions(opts FSOptions) {
+	fs = NewFS(opts)
+}
+
 // GetDiskFormat uses 'lsblk' to see if the given disk is unformatted.
+// On Linux, a disk lsblk reports no format for is checked for a LUKS or
+// LVM signature, reported as DiskFormatLUKS or DiskFormatLVM.
 func GetDiskFormat(ctx context.Context, disk string) (string, error) {
 	return fs.GetDiskFormat(ctx, disk)
 }
@@ -121,6 +319,17 @@ func FormatAndMount(
 	return fs.FormatAndMount(ctx, source, target, fsType, opts...)
 }
 
+// FormatAndMountMPath waits until the multipath device has the required
+// number of active paths, then formats and mounts it like FormatAndMount.
+// The device may be given by name, e.g. mpatha, or path.
+func FormatAndMountMPath(
+	ctx context.Context,
+	mpathDevice, target, fsType string,
+	opts MPathFormatOptions,
+) error {
+	return fs.FormatAndMountMPath(ctx, mpathDevice, target, fsType, opts)
+}
+
 // Format uses unix utils to format the given disk.
 func Format(
 	ctx context.Context,
@@ -147,6 +356,14 @@ func Mount(
 	return fs.Mount(ctx, source, target, fsType, opts...)
 }
 
+// MountWithOptions mounts source onto target like Mount. When
+// opts.CreateTarget is set the target directory is created first, and
+// removed again if the mount fails. A *TargetNotDirectoryError is returned
+// if the target exists but is not a directory.
+func MountWithOptions(ctx context.Context, source, target, fsType string, opts MountOptions) error {
+	return fs.MountWithOptions(ctx, source, target, fsType, opts)
+}
+
 // BindMount behaves like Mount was called with a "bind" flag set
 // in the options list.
 func BindMount(
@@ -178,26 +395,46 @@ func ResizeFS(
 	volumePath, devicePath, ppathDevice,
 	mpathDevice, fsType string,
 ) error {
-	return fs.resizeFS(ctx, volumePath, devicePath, ppathDevice, mpathDevice, fsType)
+	return fs.ResizeFS(ctx, volumePath, devicePath, ppathDevice, mpathDevice, fsType)
+}
+
+// ResizeFSWithOptions expands the filesystem to the new size of underlying
+// device unless it already fills the device, in which case it returns
+// ErrNoResizeNeeded. It returns the size of the filesystem in bytes.
+func ResizeFSWithOptions(
+	ctx context.Context,
+	volumePath, devicePath, ppathDevice,
+	mpathDevice, fsType string,
+	opts ResizeFSOptions,
+) (int64, error) {
+	return fs.ResizeFSWithOptions(ctx, volumePath, devicePath, ppathDevice, mpathDevice, fsType, opts)
 }
 
 // ResizeMultipath expands the multipath volumes
 func ResizeMultipath(ctx context.Context, deviceName string) error {
-	return fs.resizeMultipath(ctx, deviceName)
+	return fs.ResizeMultipath(ctx, deviceName)
 }
 
 // FindFSType fetches the filesystem type on mountpoint
 func FindFSType(
 	ctx context.Context, mountpoint string,
 ) (fsType string, err error) {
-	return fs.findFSType(ctx, mountpoint)
+	return fs.FindFSType(ctx, mountpoint)
 }
 
 // DeviceRescan rescan the device for size alterations
 func DeviceRescan(ctx context.Context,
 	devicePath string,
 ) error {
-	return fs.deviceRescan(ctx, devicePath)
+	return fs.DeviceRescan(ctx, devicePath)
+}
+
+// WaitForDeviceToSettle waits until follow-up operations on the device,
+// e.g. a filesystem resize, are safe after a rescan or resize: udev has
+// no pending events, the size of the device is stable and the device can
+// be opened. It waits DefaultDeviceSettleTimeout if ctx has no deadline.
+func WaitForDeviceToSettle(ctx context.Context, device string) error {
+	return fs.WaitForDeviceToSettle(ctx, device)
 }
 
 // GetMounts returns a slice of all the mounted filesystems.
@@ -217,6 +454,20 @@ func GetMounts(ctx context.Context) ([]Info, error) {
 	return fs.GetMounts(ctx)
 }
 
+// GetMountsByKind returns the mounts of the given kinds, or of all kinds if
+// none is given. Unlike GetMounts it considers every entry of the mount
+// table, e.g. overlay and tmpfs mounts, and on Linux it also returns the
+// active swaps, with an empty Path.
+func GetMountsByKind(ctx context.Context, kinds ...MountKind) ([]Info, error) {
+	return fs.GetMountsByKind(ctx, kinds...)
+}
+
+// ScanProcMounts calls fn for each entry of the mount table, with only
+// the selected fields filled in, until fn returns false or an error.
+func ScanProcMounts(ctx context.Context, fields MountEntryField, fn MountEntryFunc) error {
+	return fs.ScanProcMounts(ctx, fields, fn)
+}
+
 // GetDevMounts returns a slice of all mounts for the provided device.
 func GetDevMounts(ctx context.Context, dev string) ([]Info, error) {
 	return fs.GetDevMounts(ctx, dev)
@@ -224,14 +475,10 @@ func GetDevMounts(ctx context.Context, dev string) ([]Info, error) {
 
 // EvalSymlinks evaluates the provided path and updates it to remove
 // any symlinks in its structure, replacing them with the actual path
-// components.
-func EvalSymlinks(_ context.Context, symPath *string) error {
-	realPath, err := filepath.EvalSymlinks(*symPath)
-	if err != nil {
-		return err
-	}
-	*symPath = realPath
-	return nil
+// components. It stops when the context is done and fails with
+// ErrTooManySymlinks after following DefaultMaxSymlinkDepth symlinks.
+func EvalSymlinks(ctx context.Context, symPath *string) error {
+	return EvalSymlinksWithDepth(ctx, symPath, DefaultMaxSymlinkDepth)
 }
 
 // ValidateDevice evalutes the specified path and determines whether
@@ -244,6 +491,8 @@ func ValidateDevice(ctx context.Context, source string) (string, error) {
 
 // WWNToDevicePath returns the device path corresponding to a LUN's WWN
 // (World Wide Name). A null path is returned if the device isn't found.
+// PowerFlex volumes are looked up by volume ID, or by
+// <system ID>-<volume ID>, instead of the WWN.
 func WWNToDevicePath(ctx context.Context, wwn string) (string, error) {
 	_, path, err := fs.WWNToDevicePath(ctx, wwn)
 	return path, err
@@ -264,6 +513,13 @@ func RescanSCSIHost(ctx context.Context, targets []string, lun string) error {
 	return fs.RescanSCSIHost(ctx, targets, lun)
 }
 
+// RescanSCSIHostX performs the same rescan as RescanSCSIHost and returns
+// a report of the hosts that were rescanned, the scan strings written
+// and the new sd devices that appeared in /sys/block.
+func RescanSCSIHostX(ctx context.Context, targets []string, lun string) (*RescanReport, error) {
+	return fs.RescanSCSIHostX(ctx, targets, lun)
+}
+
 // RemoveBlockDevice removes a block device by getting the device name
 // from the last component of the blockDevicePath and then removing the
 // device by writing '1' to /sys/block{deviceName}/device/delete
@@ -274,6 +530,7 @@ func RemoveBlockDevice(ctx context.Context, blockDevicePath string) error {
 // MultipathCommand executes the multipath command with a timeout and various arguments.
 // Optionally a chroot directory can be specified for changing root directory.
 // This only works in a container or another environment where it can chroot to /noderoot.
+// The chroot is ignored if FSOptions.Chroot or HostMountNamespace is set.
 func MultipathCommand(ctx context.Context, timeoutSeconds time.Duration, chroot string, arguments ...string) ([]byte, error) {
 	return fs.MultipathCommand(ctx, timeoutSeconds, chroot, arguments...)
 }
@@ -295,6 +552,7 @@ func IssueLIPToAllFCHosts(ctx context.Context) error {
 }
 
 // GetSysBlockDevicesForVolumeWWN given a volumeWWN will return a list of devices in /sys/block for that WWN (e.g. sdx, sdaa)
+// PowerFlex scini devices are matched by volume ID, or by <system ID>-<volume ID>.
 func GetSysBlockDevicesForVolumeWWN(ctx context.Context, volumeWWN string) ([]string, error) {
 	return fs.GetSysBlockDevicesForVolumeWWN(ctx, volumeWWN)
 }
@@ -308,3 +566,755 @@ func FsInfo(ctx context.Context, path string) (int64, int64, int64, int64, int64
 func GetNVMeController(device string) (string, error) {
 	return fs.getNVMeController(device)
 }
+
+// ConnectNVMeTarget connects to the NVMe over fabrics subsystem nqn at
+// traddr:trsvcid using the tcp, fc or rdma transport. Connecting to a
+// subsystem that is already connected is not an error.
+func ConnectNVMeTarget(ctx context.Context, transport, traddr, trsvcid, nqn string, opts NVMeConnectOptions) error {
+	return fs.ConnectNVMeTarget(ctx, transport, traddr, trsvcid, nqn, opts)
+}
+
+// DisconnectNVMeTarget disconnects all controllers of the NVMe subsystem nqn.
+func DisconnectNVMeTarget(ctx context.Context, nqn string) error {
+	return fs.DisconnectNVMeTarget(ctx, nqn)
+}
+
+// GetNVMeControllersForSubsystemNQN returns the controllers, with their
+// transport, address and state, of the NVMe subsystem with the given NQN.
+func GetNVMeControllersForSubsystemNQN(ctx context.Context, nqn string) ([]NVMeController, error) {
+	return fs.GetNVMeControllersForSubsystemNQN(ctx, nqn)
+}
+
+// DMSuspend suspends I/O to the device mapper device with the given name
+// or /dev/mapper path. I/O is queued until DMResume is called.
+func DMSuspend(ctx context.Context, name string) error {
+	return fs.DMSuspend(ctx, name)
+}
+
+// DMResume resumes I/O to a suspended device mapper device.
+func DMResume(ctx context.Context, name string) error {
+	return fs.DMResume(ctx, name)
+}
+
+// GetDMTable returns the table of the device mapper device with the
+// given name or /dev/mapper path.
+func GetDMTable(ctx context.Context, name string) (*DMTable, error) {
+	return fs.GetDMTable(ctx, name)
+}
+
+// IsMountPoint returns true if path is a mount point, including bind
+// mounts of a directory that resides on the same filesystem.
+func IsMountPoint(ctx context.Context, path string) (bool, error) {
+	return fs.IsMountPoint(ctx, path)
+}
+
+// IsLikelyNotMountPoint quickly determines whether path is not a mount
+// point by comparing its device with the device of its parent directory.
+// Bind mounts within the same filesystem are not detected, use
+// IsMountPoint when they must be.
+func IsLikelyNotMountPoint(ctx context.Context, path string) (bool, error) {
+	return fs.IsLikelyNotMountPoint(ctx, path)
+}
+
+// IsCorruptedMount returns true if the mount at target is corrupted or
+// stale, e.g. a dead NFS server or a dropped iSCSI session, and should be
+// unmounted and mounted again. An error is returned if target could not
+// be checked for other reasons, e.g. because it does not exist.
+func IsCorruptedMount(ctx context.Context, target string) (bool, error) {
+	return fs.IsCorruptedMount(ctx, target)
+}
+
+// MultipathdStatus reports whether multipathd is installed, running and
+// responsive. Problems with the daemon are reported in the returned
+// health, not as an error.
+func MultipathdStatus(ctx context.Context) (*MultipathdHealth, error) {
+	return fs.MultipathdStatus(ctx)
+}
+
+// RegenerateXFSUUID gives the unmounted xfs filesystem on device a new
+// random UUID, e.g. so that a clone can be mounted alongside the original.
+func RegenerateXFSUUID(ctx context.Context, device string) error {
+	return fs.RegenerateXFSUUID(ctx, device)
+}
+
+// RegenerateExtUUID gives the unmounted ext3 or ext4 filesystem on device
+// a new random UUID, checking the filesystem first when tune2fs requires
+// it, e.g. so that a clone can be mounted alongside the original.
+func RegenerateExtUUID(ctx context.Context, device string) error {
+	return fs.RegenerateExtUUID(ctx, device)
+}
+
+// MakeBlockFile creates the file target, and its parent directories, for
+// a raw block volume to be published to.
+func MakeBlockFile(ctx context.Context, target string) error {
+	return fs.MakeBlockFile(ctx, target)
+}
+
+// BindMountBlockDevice validates the block device and bind mounts it onto
+// the file target, creating the file if needed.
+func BindMountBlockDevice(ctx context.Context, device, target string, options ...string) error {
+	return fs.BindMountBlockDevice(ctx, device, target, options...)
+}
+
+// UnmountBlockDevice unmounts the block device bind mounted onto target,
+// if any, and removes the file. It is the inverse of BindMountBlockDevice.
+func UnmountBlockDevice(ctx context.Context, target string) error {
+	return fs.UnmountBlockDevice(ctx, target)
+}
+
+// CheckNFSExport probes whether the NFS service of server is reachable
+// and, if the server provides an export list, whether exportPath is
+// exported to this host, so that network problems can be told apart from
+// export problems before mounting. An export whose clients include none
+// of the addresses and the hostname of this host is reported with
+// ClientAllowed false and a Problem.
+func CheckNFSExport(ctx context.Context, server, exportPath string) (*NFSExportStatus, error) {
+	return fs.CheckNFSExport(ctx, server, exportPath)
+}
+
+// MountMany performs the mount requests concurrently and returns a result
+// per request, in the order of the requests, so that failed requests can
+// be retried individually. Requests for the same target are performed one
+// after another in the order given.
+func MountMany(ctx context.Context, reqs []MountRequest) []MountResult {
+	return fs.MountMany(ctx, reqs)
+}
+
+// DiskUsage computes the disk space and inodes used under path, like
+// du -x, e.g. for volumes that are directories of a shared filesystem,
+// where statfs does not report per volume usage.
+func DiskUsage(ctx context.Context, path string) (*DiskUsageInfo, error) {
+	return fs.DiskUsage(ctx, path)
+}
+
+// SetProjectQuota assigns the directory path to the project projID and
+// limits the disk space of the project to limitBytes, e.g. to enforce the
+// capacity of a volume that is a directory of a shared xfs or ext4
+// filesystem. The filesystem must be mounted with project quotas, see
+// ProjectQuotaMountOptions.
+func SetProjectQuota(ctx context.Context, path string, projID uint32, limitBytes int64) error {
+	return fs.SetProjectQuota(ctx, path, projID, limitBytes)
+}
+
+// GetProjectQuota returns the usage and limit of the project projID on
+// the filesystem of path.
+func GetProjectQuota(ctx context.Context, path string, projID uint32) (*ProjectQuota, error) {
+	return fs.GetProjectQuota(ctx, path, projID)
+}
+
+// GetPartitions returns the partition table of the disk.
+func GetPartitions(ctx context.Context, device string) (*PartitionTable, error) {
+	return fs.GetPartitions(ctx, device)
+}
+
+// CreatePartition creates a GPT partition on the disk, and a GPT partition
+// table if the disk has none, e.g. to format a partition rather than the
+// whole LUN. It returns the partition created.
+func CreatePartition(ctx context.Context, device string, spec PartitionSpec) (*Partition, error) {
+	return fs.CreatePartition(ctx, device, spec)
+}
+
+// DeletePartition deletes the partition with the given number.
+func DeletePartition(ctx context.Context, device string, number int) error {
+	return fs.DeletePartition(ctx, device, number)
+}
+
+// GrowPartition grows the partition with the given number into the free
+// space that follows it, e.g. after the LUN was expanded.
+func GrowPartition(ctx context.Context, device string, number int) error {
+	return fs.GrowPartition(ctx, device, number)
+}
+
+// GetMountsInto returns the mounted filesystems like GetMounts, but reuses
+// the capacity of infos, which is overwritten. Callers that poll the mount
+// table can pass the slice returned by the previous call to avoid
+// reallocating it.
+func GetMountsInto(ctx context.Context, infos []Info) ([]Info, error) {
+	return fs.GetMountsInto(ctx, infos)
+}
+
+// GetNPIVPorts returns the NPIV virtual ports of the local FC hosts.
+func GetNPIVPorts(ctx context.Context) ([]NPIVPort, error) {
+	return fs.GetNPIVPorts(ctx)
+}
+
+// CreateNPIVPort creates an NPIV virtual port on the FC host, given as
+// hostN or N. The port and node names are generated if empty.
+func CreateNPIVPort(ctx context.Context, host, wwpn, wwnn string) (*NPIVPort, error) {
+	return fs.CreateNPIVPort(ctx, host, wwpn, wwnn)
+}
+
+// DeleteNPIVPort deletes the NPIV virtual port with the given port name
+// from the FC host.
+func DeleteNPIVPort(ctx context.Context, host, wwpn string) error {
+	return fs.DeleteNPIVPort(ctx, host, wwpn)
+}
+
+// GetFCHostInfo returns the port names, port state, speed and fabric name
+// of the local FC hosts, e.g. to skip hosts whose link is down.
+func GetFCHostInfo(ctx context.Context) ([]FCHostInfo, error) {
+	return fs.GetFCHostInfo(ctx)
+}
+
+// GetISCSISessionState returns the state, connections and I/O error
+// counters of the iSCSI sessions to the target, one per portal, or of all
+// the sessions if targetIQN is empty.
+func GetISCSISessionState(ctx context.Context, targetIQN string) ([]ISCSISession, error) {
+	return fs.GetISCSISessionState(ctx, targetIQN)
+}
+
+// GetHostNQN returns the NVMe host NQN read from /etc/nvme/hostnqn. If the
+// file is missing and create is set, it is created with a new UUID based
+// NQN.
+func GetHostNQN(ctx context.Context, create bool) (string, error) {
+	return fs.GetHostNQN(ctx, create)
+}
+
+// GetNQNHostID returns the NVMe host ID read from /etc/nvme/hostid. If the
+// file is missing and create is set, it is created with the UUID of the
+// host NQN, or a new UUID.
+func GetNQNHostID(ctx context.Context, create bool) (string, error) {
+	return fs.GetNQNHostID(ctx, create)
+}
+
+// GetHostIQN returns the iSCSI initiator name read from
+// /etc/iscsi/initiatorname.iscsi. If the file is missing and create is
+// set, it is created with a new IQN.
+func GetHostIQN(ctx context.Context, create bool) (string, error) {
+	return fs.GetHostIQN(ctx, create)
+}
+
+// EnsureDeviceUnused returns an InUseError if the device is in use, i.e. a
+// filesystem on it or on one of its partitions is mounted, it or one of
+// its partitions is held by a device mapper or MD RAID device, or another
+// process has it open exclusively. It is meant to be called before
+// formatting the device.
+func EnsureDeviceUnused(ctx context.Context, device string) error {
+	return fs.EnsureDeviceUnused(ctx, device)
+}
+
+// RegisterSupportedFsType adds filesystem types, e.g. btrfs or cifs, to
+// those accepted by Mount, FormatAndMount and Format.
+func RegisterSupportedFsType(fsTypes ...string) {
+	fs.RegisterSupportedFsType(fsTypes...)
+}
+
+// SetSupportedFsTypes replaces the filesystem types accepted by Mount,
+// FormatAndMount and Format. DefaultSupportedFsTypes are restored if none
+// are given.
+func SetSupportedFsTypes(fsTypes ...string) {
+	fs.SetSupportedFsTypes(fsTypes...)
+}
+
+// SupportedFsTypes returns the filesystem types accepted by Mount,
+// FormatAndMount and Format.
+func SupportedFsTypes() []string {
+	return fs.SupportedFsTypes()
+}
+
+// SetDefaultMountOptions sets the options merged into every mount of
+// fsType by Mount and FormatAndMount, e.g. nouuid for xfs or hard for nfs,
+// so that option policies are kept in one place. A default is left out if
+// the options of a mount set the same option, e.g. vers=4 for vers=3, or
+// a conflicting one, e.g. soft for hard or atime for noatime. The
+// defaults of fsType are removed if no options are given.
+func SetDefaultMountOptions(fsType string, opts ...string) {
+	fs.SetDefaultMountOptions(fsType, opts...)
+}
+
+// DefaultMountOptions returns the options merged into the mounts of
+// fsType.
+func DefaultMountOptions(fsType string) []string {
+	return fs.DefaultMountOptions(fsType)
+}
+
+// SMBMount mounts the SMB share source, e.g. //server/share, on target as
+// a cifs filesystem. The credentials are handed to mount.cifs in a
+// temporary file and never appear in logs or errors.
+func SMBMount(ctx context.Context, source, target string, creds SMBCredentials, opts ...string) error {
+	return fs.SMBMount(ctx, source, target, creds, opts...)
+}
+
+// GetCommandHistory returns the last external commands run, oldest first,
+// if FSOptions.CommandHistorySize is set.
+func GetCommandHistory() []CommandRecord {
+	return fs.GetCommandHistory()
+}
+
+// UnmountTree unmounts every mount at or below root, deepest first, and
+// returns the outcome for each mount, including the tmpfs, overlay and
+// other mounts that GetMounts omits. A mount that cannot be unmounted
+// after UnmountTreeRetries attempts is detached with a lazy unmount. An
+// error is returned only if the mounts cannot be listed.
+func UnmountTree(ctx context.Context, root string) ([]UnmountResult, error) {
+	return fs.UnmountTree(ctx, root)
+}
+
+// GetMpathDeviceForWWN returns the multipath device of the volume with the
+// given WWN, both its dm-X node and its /dev/mapper name. It reads the
+// device mapper UUIDs from sysfs instead of relying on the
+// /dev/disk/by-id symlinks, which may be created late or not at all. It
+// returns nil if there is no multipath device for the volume.
+func GetMpathDeviceForWWN(ctx context.Context, wwn string) (*MpathDevice, error) {
+	return fs.GetMpathDeviceForWWN(ctx, wwn)
+}
+
+// BlockDevSetRO makes the block device read-only, or read-write if
+// readOnly is false, like blockdev --setro and --setrw.
+func BlockDevSetRO(ctx context.Context, device string, readOnly bool) error {
+	return fs.BlockDevSetRO(ctx, device, readOnly)
+}
+
+// BlockDevGetRO returns true if the block device is read-only, like
+// blockdev --getro.
+func BlockDevGetRO(ctx context.Context, device string) (bool, error) {
+	return fs.BlockDevGetRO(ctx, device)
+}
+
+// CleanupDeviceForWWN removes the devices of the volume with the given
+// WWN from the host. It finds the sd and nvme path devices and the
+// multipath device of the volume, fails with an InUseError if any of them
+// is mounted, flushes the multipath map, deletes the SCSI path devices and
+// waits for the devices to disappear. The report describes how far the
+// cleanup got, also when an error is returned.
+func CleanupDeviceForWWN(ctx context.Context, wwn string) (*DeviceCleanupReport, error) {
+	return fs.CleanupDeviceForWWN(ctx, wwn)
+}
+
+// IsPMEMDevice returns true if device is a persistent memory block device,
+// e.g. /dev/pmem0.
+func IsPMEMDevice(ctx context.Context, device string) (bool, error) {
+	return fs.IsPMEMDevice(ctx, device)
+}
+
+// MountDAX mounts the ext4 or xfs filesystem on source with direct access
+// (DAX). The dax=always option is added unless opts have a dax option,
+// falling back to the dax option of older kernels. A DAXError is returned
+// if the filesystem or device does not support DAX, or if the kernel
+// mounted the filesystem without DAX, in which case it is unmounted again.
+func MountDAX(ctx context.Context, source, target, fsType string, opts ...string) error {
+	return fs.MountDAX(ctx, source, target, fsType, opts...)
+}
+
+// GetISCSITargetsFromByPath returns the portal, target IQN, LUN and device
+// of every iSCSI LUN in /dev/disk/by-path. It is the reverse of
+// TargetIPLUNToDevicePath, to reconcile the devices of the host with the
+// mappings reported by the arrays.
+func GetISCSITargetsFromByPath(ctx context.Context) ([]ISCSITargetDevice, error) {
+	return fs.GetISCSITargetsFromByPath(ctx)
+}
+
+// FCTargetWWPNLUNToDevicePath returns the /dev/disk/by-path entries of an FC
+// LUN, given the WWPN of the target port and the LUN id, and their device
+// paths. It is a quick alternative to looking up the devices by WWN when
+// the target port of the LUN is known. The WWPN may have a 0x prefix and
+// colon separators.
+func FCTargetWWPNLUNToDevicePath(ctx context.Context, wwpn string, lunID int) (map[string]string, error) {
+	return fs.FCTargetWWPNLUNToDevicePath(ctx, wwpn, lunID)
+}
+
+// RescanSCSIHostsForLUNs rescans the scsi hosts related to targets for
+// each of luns in one pass, like RescanSCSIHostX does for one lun. The
+// hosts are deduplicated and rescanned in parallel. The scan strings
+// written to a scan file are reported separated by newlines.
+func RescanSCSIHostsForLUNs(ctx context.Context, targets []string, luns []string) (*RescanReport, error) {
+	return fs.RescanSCSIHostsForLUNs(ctx, targets, luns)
+}
+
+// SetTargetPermissions sets the owner, group and mode of target, typically
+// a mount point after it was mounted, and of the files below it if
+// recursive is set. A uid or gid of -1 and a mode of 0 leave the owner,
+// group or mode unchanged. Only what differs is changed, and an ownership
+// change rejected by an NFS server, e.g. because the export squashes
+// root, is logged and skipped instead of failing.
+func SetTargetPermissions(ctx context.Context, target string, uid, gid int, mode os.FileMode, recursive bool) error {
+	return fs.SetTargetPermissions(ctx, target, uid, gid, mode, recursive)
+}
+
+// SetTargetImmutable sets or clears the immutable attribute of target, like
+// chattr +i and chattr -i. Making an unmounted staging or publish directory
+// immutable prevents writes into the root filesystem of the node when a
+// mount on it silently failed; mounts on the directory are still
+// possible. A directory that is a mount point is not made immutable.
+func SetTargetImmutable(ctx context.Context, target string, immutable bool) error {
+	return fs.SetTargetImmutable(ctx, target, immutable)
+}
+
+// GetLoopDeviceForFile returns the loop device, e.g. /dev/loop0, backed by
+// file, or an empty string if the file is not attached to a loop device.
+func GetLoopDeviceForFile(ctx context.Context, file string) (string, error) {
+	return fs.GetLoopDeviceForFile(ctx, file)
+}
+
+// AttachLoopDevice attaches file to a free loop device with losetup, read
+// only if readOnly is set, and returns the device, e.g. /dev/loop0. The
+// loop device the file is attached to is returned if it already is.
+func AttachLoopDevice(ctx context.Context, file string, readOnly bool) (string, error) {
+	return fs.AttachLoopDevice(ctx, file, readOnly)
+}
+
+// DetachLoopDevice detaches the loop device, e.g. /dev/loop0, from its
+// backing file.
+func DetachLoopDevice(ctx context.Context, device string) error {
+	return fs.DetachLoopDevice(ctx, device)
+}
+
+// FormatAndMountFile attaches file to a loop device and formats and mounts
+// the device on target like FormatAndMount, e.g. for file-backed ephemeral
+// volumes. It returns the loop device, which is to be detached with
+// DetachLoopDevice after the target is unmounted. A loop device attached
+// by FormatAndMountFile is detached again if the mount fails. With
+// Chroot or HostMountNamespace, file must be within the host root or be a
+// path of the host.
+func FormatAndMountFile(ctx context.Context, file, target, fsType string, opts ...string) (string, error) {
+	return fs.FormatAndMountFile(ctx, file, target, fsType, opts...)
+}
+
+// MountTmpfs mounts a tmpfs, an in-memory filesystem, on target, e.g. for
+// ephemeral volumes. The filesystem is limited to sizeBytes and its root
+// directory gets mode, e.g. 0o750 or 0o777|os.ModeSticky; a size or mode
+// of 0 leaves the kernel defaults, half of the memory and 1777. The
+// options, e.g. noexec, may not set the size or mode. The tmpfs filesystem
+// type does not have to be registered with RegisterSupportedFsType.
+func MountTmpfs(ctx context.Context, target string, sizeBytes int64, mode os.FileMode, opts ...string) error {
+	return fs.MountTmpfs(ctx, target, sizeBytes, mode, opts...)
+}
+
+// GetMultipathKind returns whether the volume with the given WWN uses a
+// device mapper multipath map, native NVMe multipathing or neither. With
+// native NVMe multipathing the device is /dev/nvmeXnY, there is no map
+// for ResizeMultipath to resize, and ResizeFS rescans the controllers of
+// the namespace itself.
+func GetMultipathKind(ctx context.Context, wwn string) (MultipathKind, error) {
+	return fs.GetMultipathKind(ctx, wwn)
+}
+
+// FsHealthCheck runs a lightweight health probe of the filesystem mounted
+// on mountpoint: statfs has to succeed, the mount point directory has to
+// be readable, and, unless the filesystem is read-only or the mount point
+// is not writable by the caller, an unnamed temporary file (O_TMPFILE),
+// or a hidden file where that is not supported, has to be writable. The
+// report tells whether the filesystem is abnormal and how long the read
+// took. A filesystem that does not answer before ctx is done is reported
+// as abnormal. An error is only returned if mountpoint is invalid.
+func FsHealthCheck(ctx context.Context, mountpoint string) (*FsHealth, error) {
+	return fs.FsHealthCheck(ctx, mountpoint)
+}
+
+// CheckClusterStack returns an error wrapping ErrClusterStackNotRunning
+// if fsType is a shared cluster filesystem, ocfs2 or gfs2, whose cluster
+// stack is not up: for ocfs2 the module has to be loaded and either its
+// o2cb cluster online or dlm running, for gfs2 dlm has to be running
+// unless the mount options select lock_nolock. Mount, FormatAndMount and
+// Format run the same check, so that a shared disk is never formatted
+// because it could not be mounted for lack of a cluster stack. It
+// returns nil for other filesystem types.
+func CheckClusterStack(ctx context.Context, fsType string, opts ...string) error {
+	return fs.CheckClusterStack(ctx, fsType, opts...)
+}
+
+// GetDryRunActions returns the actions recorded with FSOptions.DryRun
+// instead of being taken, in the order the operations would have taken
+// them.
+func GetDryRunActions() []DryRunAction {
+	return fs.GetDryRunActions()
+}
+
+// ClearDryRunActions forgets the actions recorded with FSOptions.DryRun,
+// e.g. before previewing the next operation.
+func ClearDryRunActions() {
+	fs.ClearDryRunActions()
+}
+
+// SetTracerProvider makes Mount, Unmount, FormatAndMount, ResizeFS,
+// RescanSCSIHost and MultipathCommand create OpenTelemetry spans with a
+// tracer of tp, named TracerName, so that node operations appear in
+// distributed traces as children of the spans in their contexts, e.g. of
+// the CSI gRPC handlers. The spans carry the device, filesystem type,
+// target and duration of the operation and its error. No spans are
+// created until a provider is set, or after nil is set.
+func SetTracerProvider(tp trace.TracerProvider) {
+	fs.SetTracerProvider(tp)
+}
+
+// GetDeviceTopology returns the block device, given as a device path or
+// a kernel name, with the devices stacked on it and under it: its
+// partitions, the device mapper and MD devices holding it or one of its
+// partitions, the devices it is built on and the backing files of loop
+// devices. Cleanup and resize logic can use it to handle stacks other
+// than a plain path under a multipath device, e.g. LVM on multipath.
+func GetDeviceTopology(ctx context.Context, device string) (*DeviceTopology, error) {
+	return fs.GetDeviceTopology(ctx, device)
+}
+
+// GetBlockDeviceHolders returns the device nodes of the devices that
+// directly hold the block device or one of its partitions, e.g.
+// /dev/dm-3 for a path of a multipath device. A device with holders is in
+// use and cannot be removed.
+func GetBlockDeviceHolders(ctx context.Context, device string) ([]string, error) {
+	return fs.GetBlockDeviceHolders(ctx, device)
+}
+
+// GetNVMePathStates returns the paths of the namespaces of the NVMe
+// subsystem with the given NQN through each of its controllers, with
+// their Asymmetric Namespace Access (ANA) state, read from sysfs. With
+// native NVMe multipathing, drivers can use HasOptimizedNVMePath to wait
+// for an optimized path before reporting a volume as ready.
+func GetNVMePathStates(ctx context.Context, nqn string) ([]NVMePathState, error) {
+	return fs.GetNVMePathStates(ctx, nqn)
+}
+
+// MountSensitive mounts source onto target like Mount, with the mount
+// options opts and sensitiveOpts. The sensitive options, e.g. the
+// credentials of a CIFS share or the secret of a Ceph client, are passed
+// to the mount command but redacted from the logs, the command history,
+// the dry run actions and the returned errors.
+func MountSensitive(ctx context.Context, source, target, fsType string, opts, sensitiveOpts []string) error {
+	return fs.MountSensitive(ctx, source, target, fsType, opts, sensitiveOpts)
+}
+
+// MountWithSpec mounts the mount described by spec, with its typed flags
+// and data instead of the option strings of Mount. The spec is validated
+// first; an error wrapping ErrInvalidMountSpec is returned if it is not
+// valid. ParseMountSpec converts the arguments of Mount to a spec.
+func MountWithSpec(ctx context.Context, spec MountSpec) error {
+	return fs.MountWithSpec(ctx, spec)
+}
+
+// StageBlockVolume stages a volume on a node in one call: it waits for
+// the device of the volume to appear, and with RequireMultipath for it to
+// be multipathed, waits for the device to settle, and then either bind
+// mounts the device onto the target file of a raw block volume, or checks
+// that the device is unused and formats and mounts it. The mount is
+// verified afterwards. A volume already staged on the target is left as
+// is. The result describes the device used and the steps run, and is
+// returned even if staging fails.
+func StageBlockVolume(ctx context.Context, req StageRequest) (*StageResult, error) {
+	return fs.StageBlockVolume(ctx, req)
+}
+
+// UnstageBlockVolume is the inverse of StageBlockVolume: it unmounts the
+// targets, removing the target files of a raw block volume, flushes the
+// multipath device and removes the path devices of the volume like
+// CleanupDeviceForWWN, waiting for them to disappear, and optionally
+// rescans the SCSI hosts. It can be called again after a partial unstage:
+// targets that are not mounted and devices that are gone are skipped.
+// The result describes the steps run, and is returned even if unstaging
+// fails.
+func UnstageBlockVolume(ctx context.Context, req UnstageRequest) (*UnstageResult, error) {
+	return fs.UnstageBlockVolume(ctx, req)
+}
+
+// GetHostSCSIHosts returns the SCSI hosts of the node from
+// /sys/class/scsi_host, with their driver, model, state and transport,
+// and the FC host of the Fibre Channel HBAs. Rescans and support bundles
+// can use it to tell FC HBAs, iSCSI hosts and SAS controllers apart.
+func GetHostSCSIHosts(ctx context.Context) ([]SCSIHost, error) {
+	return fs.GetHostSCSIHosts(ctx)
+}
+
+// DMNameToDevPath returns the device mapper device with the given name
+// or /dev/mapper path, with its kernel device node, e.g. /dev/dm-3, and
+// its UUID.
+func DMNameToDevPath(ctx context.Context, name string) (*DMDevice, error) {
+	return fs.DMNameToDevPath(ctx, name)
+}
+
+// DevPathToDMName returns the device mapper device of a kernel device
+// node, e.g. /dev/dm-3, or a /dev/mapper path, with its name and UUID.
+func DevPathToDMName(ctx context.Context, devPath string) (*DMDevice, error) {
+	return fs.DevPathToDMName(ctx, devPath)
+}
+
+// GetDevicePathsForMountPoint returns the device paths of the block
+// devices mounted at target, including raw block volumes published by
+// bind mounting a device file, which appear as devtmpfs mounts that
+// GetDevMounts cannot find by device. Unpublish logic can use it to
+// locate the device of a published target path.
+func GetDevicePathsForMountPoint(ctx context.Context, target string) ([]string, error) {
+	return fs.GetDevicePathsForMountPoint(ctx, target)
+}
+
+// GetMountsByDevID returns the mounts of the filesystem with the given
+// device number, the major:minor column of the mount table. Unlike
+// matching by device path, it is not fooled by recycled device names or
+// stale symlinks.
+func GetMountsByDevID(ctx context.Context, major, minor uint32) ([]Info, error) {
+	return fs.GetMountsByDevID(ctx, major, minor)
+}
+
+// GetNFSCapabilities returns the NFS mount options supported by the host:
+// nconnect, from the kernel version, and RDMA, from the rpcrdma module.
+func GetNFSCapabilities(ctx context.Context) (*NFSCapabilities, error) {
+	return fs.GetNFSCapabilities(ctx)
+}
+
+// MountNFS mounts the NFS export source, e.g. server:/ifs/data, onto
+// target with opts and the validated performance options nfsOpts, e.g.
+// nconnect or RDMA. Options the host does not support are dropped, with
+// RDMA falling back to TCP, and a mount that fails with them is tried
+// once more without them, unless nfsOpts.Strict is set.
+func MountNFS(ctx context.Context, source, target string, nfsOpts NFSMountOptions, opts ...string) error {
+	return fs.MountNFS(ctx, source, target, nfsOpts, opts...)
+}
+
+// GetISCSIHostsForTargetPortal returns the scsi hosts, with the channel
+// and target of their SCSI target, of the iSCSI sessions connected to the
+// target portal, given as an address, e.g. 10.0.0.1, or an address and
+// port, e.g. 10.0.0.1:3260. It lets drivers that only know the portal
+// target rescans and session checks without the IQN.
+func GetISCSIHostsForTargetPortal(ctx context.Context, portal string) ([]ISCSIPortalHost, error) {
+	return fs.GetISCSIHostsForTargetPortal(ctx, portal)
+}
+
+// ReplayIncompleteOperations finishes or rolls back the operations
+// recorded in FSOptions.JournalDir that were interrupted, e.g. by a crash
+// of the driver: an interrupted format is wiped so that the device is
+// formatted again, a formatted device is mounted, and an interrupted
+// device cleanup is run again so that no multipath map is left behind.
+// A format is replayed on the device of the WWN recorded when it started,
+// whatever its kernel name is now, and only wiped if
+// FSOptions.JournalRollback is set and blkid finds a signature of the
+// filesystem type of the format. Operations that fail to replay are kept
+// for the next call unless they are older than FSOptions.JournalMaxAge.
+// It should be called on start, before new operations are accepted.
+func ReplayIncompleteOperations(ctx context.Context) ([]JournalReplay, error) {
+	return fs.ReplayIncompleteOperations(ctx)
+}
+
+// RemoveBlockDeviceWithOptions removes a block device like
+// RemoveBlockDevice. Depending on opts, it first returns a
+// DeviceBusyError if the device is held by other block devices or open by
+// processes, and flushes the buffers of the device so that no dirty pages
+// are lost.
+func RemoveBlockDeviceWithOptions(ctx context.Context, blockDevicePath string, opts RemoveBlockDeviceOptions) error {
+	return fs.RemoveBlockDeviceWithOptions(ctx, blockDevicePath, opts)
+}
+
+// GetNativeMultipathSetting returns true if native NVMe multipathing is
+// enabled, as read from /sys/module/nvme_core/parameters/multipath. An
+// error wrapping os.ErrNotExist is returned if the nvme_core module is
+// not loaded.
+func GetNativeMultipathSetting(ctx context.Context) (bool, error) {
+	return fs.GetNativeMultipathSetting(ctx)
+}
+
+// CheckNVMeMultipathMode returns an NVMeMultipathConflictError, which
+// matches ErrNVMeMultipathConflict, if the NVMe flow relying on mode
+// conflicts with the native multipath setting of the kernel, e.g. if
+// dm-multipath is requested while native multipathing is enabled.
+func CheckNVMeMultipathMode(ctx context.Context, mode NVMeMultipathMode) error {
+	return fs.CheckNVMeMultipathMode(ctx, mode)
+}
+
+// SubscribeMountChanges returns a channel of the changes of the mount
+// table, so that published volumes can be reconciled when they are
+// mounted or unmounted instead of polling GetMounts. Unlike GetMounts,
+// the changes of every entry of the mount table are reported on Linux,
+// e.g. of tmpfs and overlay mounts. On Linux the
+// changes are signaled by the kernel; the mount table is read again at
+// least every FSOptions.MountWatchInterval. The channel is closed once
+// ctx is done, and must be read until then.
+func SubscribeMountChanges(ctx context.Context) (<-chan MountEvent, error) {
+	return fs.SubscribeMountChanges(ctx)
+}
+
+// SysfsReadAttr returns the value of the sysfs attribute attr, without
+// its trailing newline. attr is a path relative to /sys, e.g.
+// block/sdb/device/state, or an absolute path within /sys. Its symlinks
+// are evaluated within sysfs, FSOptions.SysRoot if set, and an error
+// wrapping ErrOutsideRoot is returned if it resolves outside of it. A
+// read failing with EAGAIN or EINTR is retried.
+func SysfsReadAttr(ctx context.Context, attr string) (string, error) {
+	return fs.SysfsReadAttr(ctx, attr)
+}
+
+// SysfsWriteAttr writes value to the sysfs attribute attr, which must
+// exist, in a single write. attr is confined to sysfs like for
+// SysfsReadAttr. A write failing with EAGAIN or EINTR is retried, and
+// nothing is written in dry-run mode.
+func SysfsWriteAttr(ctx context.Context, attr, value string) error {
+	return fs.SysfsWriteAttr(ctx, attr, value)
+}
+
+// GetDeviceForPublishPath returns the device backing the volume published
+// at a kubelet pod volume path: the staging mount the publish path is
+// bind mounted from, the device, and for a SCSI or NVMe volume its
+// multipath device, path devices and WWN. It works for filesystem
+// volumes and raw block volumes, whose device file is bind mounted, and
+// lets diagnostics and cleanup tooling find which LUN backs a pod volume.
+func GetDeviceForPublishPath(ctx context.Context, publishPath string) (*PublishPathDevice, error) {
+	return fs.GetDeviceForPublishPath(ctx, publishPath)
+}
+
+// GetFSGeometry returns the geometry of the filesystem mounted at path or
+// on the device at path: its block size and count, and for ext
+// filesystems the reserved blocks, read with tune2fs, or for xfs the
+// allocation groups and log size, read with xfs_info. It can be used to
+// validate the result of a resize or to compute a safe shrink margin.
+func GetFSGeometry(ctx context.Context, path string) (*FSGeometry, error) {
+	return fs.GetFSGeometry(ctx, path)
+}
+
+// ShrinkFS shrinks the ext filesystem on device to newSizeBytes, rounded
+// down to whole filesystem blocks, e.g. to migrate a volume to a smaller
+// LUN. The device must not be mounted, held or open exclusively. The
+// filesystem is checked with e2fsck first, and an error wrapping
+// ErrShrinkBelowMinimum is returned if its data does not fit in the new
+// size. An error wrapping errors.ErrUnsupported is returned for other
+// filesystems, e.g. xfs, which cannot be shrunk.
+func ShrinkFS(ctx context.Context, device string, newSizeBytes int64) error {
+	return fs.ShrinkFS(ctx, device, newSizeBytes)
+}
+
+// MultipathReconfigure makes multipathd reload its configuration and
+// rebuild the multipath maps, with multipathd reconfigure.
+func MultipathReconfigure(ctx context.Context) (*MultipathdResult, error) {
+	return fs.MultipathReconfigure(ctx)
+}
+
+// ReinstatePath makes multipathd reinstate a failed path of a multipath
+// device, with multipathd reinstate path. device is the path device, e.g.
+// sdb or /dev/sdb. The result reports the state of the path afterwards.
+func ReinstatePath(ctx context.Context, device string) (*MultipathdResult, error) {
+	return fs.ReinstatePath(ctx, device)
+}
+
+// FailPath makes multipathd fail a path of a multipath device, with
+// multipathd fail path, e.g. to test failover. device is the path device,
+// e.g. sdb or /dev/sdb. The result reports the state of the path
+// afterwards.
+func FailPath(ctx context.Context, device string) (*MultipathdResult, error) {
+	return fs.FailPath(ctx, device)
+}
+
+// VerifyDeviceReadable reads length bytes at offset of the device with
+// O_DIRECT to verify that the device returns data, e.g. before mounting
+// it, catching a device node that exists while the array no longer maps
+// the volume. DefaultDeviceProbeLength bytes are read if length is not
+// positive. The read is abandoned once ctx is done. A device that could
+// not be read is reported with a DeviceReadError matching
+// ErrDeviceUnreadable.
+func VerifyDeviceReadable(ctx context.Context, device string, offset, length int64) error {
+	return fs.VerifyDeviceReadable(ctx, device, offset, length)
+}
+
+// UnmountDevice unmounts every mount of device, as returned by
+// GetDevMounts, deepest first, and returns the outcome for each mount. A
+// mount is tried UnmountTreeRetries times, and is never detached with a
+// lazy unmount. An error is returned if a mount could not be unmounted, or
+// an InUseError if the device is still mounted afterwards.
+func UnmountDevice(ctx context.Context, device string) ([]UnmountResult, error) {
+	return fs.UnmountDevice(ctx, device)
+}
+
+// VerifyMountedCapacity checks that target is a mount point and that the
+// capacity of its filesystem, as reported by statfs, matches the expected
+// size in bytes, e.g. of the LUN, after a FormatAndMount or a resize.
+// tolerance is the fraction of expected the capacity may differ by,
+// DefaultCapacityTolerance if not positive. A mismatch, e.g. of a partial
+// resize or of the wrong device mounted, is reported with a
+// CapacityMismatchError matching ErrCapacityMismatch.
+func VerifyMountedCapacity(ctx context.Context, target string, expected int64, tolerance float64) error {
+	return fs.VerifyMountedCapacity(ctx, target, expected, tolerance)
+}
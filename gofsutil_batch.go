@@ -0,0 +1,103 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+)
+
+// DefaultMaxConcurrentMounts is the default number of mounts MountMany
+// runs concurrently.
+const DefaultMaxConcurrentMounts = 8
+
+// MountRequest is a mount to be performed by MountMany.
+type MountRequest struct {
+	// Source is the device or path to mount.
+	Source string
+	// Target is the path to mount onto.
+	Target string
+	// FSType is the filesystem type, empty for bind mounts.
+	FSType string
+	// Options are the mount options.
+	Options []string
+	// Bind requests a bind mount of Source onto Target.
+	Bind bool
+}
+
+// MountResult is the result of a MountRequest.
+type MountResult struct {
+	// Request is the request the result is for.
+	Request MountRequest
+	// Err is the error of the mount, nil if it succeeded.
+	Err error
+}
+
+// mountMany runs the requests with at most workers concurrent calls of
+// mountFunc. Requests for the same target run one after another in the
+// order given. The results are in the order of the requests.
+func mountMany(
+	ctx context.Context,
+	reqs []MountRequest,
+	workers int,
+	mountFunc func(context.Context, MountRequest) error,
+) []MountResult {
+	results := make([]MountResult, len(reqs))
+
+	// Group the requests by target so that conflicting requests are
+	// handled by one worker in order.
+	groups := make([][]int, 0, len(reqs))
+	groupOf := make(map[string]int)
+	for i, req := range reqs {
+		results[i].Request = req
+		target := filepath.Clean(req.Target)
+		g, ok := groupOf[target]
+		if !ok {
+			g = len(groups)
+			groupOf[target] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+
+	if workers <= 0 {
+		workers = DefaultMaxConcurrentMounts
+	}
+	if workers > len(groups) {
+		workers = len(groups)
+	}
+	work := make(chan []int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range work {
+				for _, i := range group {
+					if err := ctx.Err(); err != nil {
+						results[i].Err = err
+						continue
+					}
+					results[i].Err = mountFunc(ctx, reqs[i])
+				}
+			}
+		}()
+	}
+	for _, group := range groups {
+		work <- group
+	}
+	close(work)
+	wg.Wait()
+	return results
+}
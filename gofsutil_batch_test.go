@@ -0,0 +1,89 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMountMany(t *testing.T) {
+	reqs := make([]MountRequest, 0)
+	for i := 0; i < 10; i++ {
+		reqs = append(reqs, MountRequest{Source: fmt.Sprintf("/dev/sd%c", 'a'+i), Target: fmt.Sprintf("/mnt/%d", i)})
+	}
+	// Two requests for the same target, the second one must run after
+	// the first one.
+	reqs = append(reqs,
+		MountRequest{Source: "/src/first", Target: "/mnt/shared"},
+		MountRequest{Source: "/src/second", Target: "/mnt/shared/"},
+	)
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	order := make([]string, 0)
+	results := mountMany(context.Background(), reqs, 3, func(_ context.Context, req MountRequest) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		if strings.HasPrefix(req.Source, "/src/") {
+			order = append(order, req.Source)
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		if req.Source == "/dev/sdc" {
+			return errors.New("mount failed")
+		}
+		return nil
+	})
+
+	require.Len(t, results, len(reqs))
+	assert.LessOrEqual(t, maxRunning, 3)
+	assert.Equal(t, []string{"/src/first", "/src/second"}, order)
+	for i, result := range results {
+		assert.Equal(t, reqs[i], result.Request)
+		if reqs[i].Source == "/dev/sdc" {
+			assert.Error(t, result.Err)
+		} else {
+			assert.NoError(t, result.Err)
+		}
+	}
+}
+
+func TestMountManyCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := false
+	results := mountMany(ctx, []MountRequest{{Target: "/mnt/a"}, {Target: "/mnt/b"}}, 0, func(context.Context, MountRequest) error {
+		called = true
+		return nil
+	})
+	assert.False(t, called)
+	for _, result := range results {
+		assert.ErrorIs(t, result.Err, context.Canceled)
+	}
+	assert.Empty(t, mountMany(context.Background(), nil, 0, nil))
+}
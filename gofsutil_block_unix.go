@@ -0,0 +1,109 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// makeBlockFile creates the regular file target, and its parent
+// directories, for a block device to be bind mounted onto. An existing
+// file is left in place.
+func (fs *FS) makeBlockFile(_ context.Context, target string) error {
+	path := filepath.Clean(target)
+	if err := validatePath(path); err != nil {
+		return err
+	}
+	st, err := os.Lstat(path)
+	if err == nil {
+		if st.IsDir() {
+			return fmt.Errorf("block file target %s is a directory", path)
+		}
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+	if fs.DryRun {
+		if _, err := os.Stat(filepath.Dir(path)); os.IsNotExist(err) {
+			fs.dryRun(DryRunMkdir, filepath.Dir(path), os.FileMode(0o750).String())
+		}
+		fs.dryRun(DryRunCreate, path, os.FileMode(0o600).String())
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create parent directory of %s: %v", path, err)
+	}
+	/* #nosec G304 */
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		if os.IsExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to create block file %s: %v", path, err)
+	}
+	return f.Close()
+}
+
+// bindMountBlockDevice bind mounts the block device onto the file target,
+// creating the file if needed.
+func (fs *FS) bindMountBlockDevice(ctx context.Context, device, target string, opts ...string) error {
+	dev, err := fs.validateDevice(ctx, device)
+	if err != nil {
+		return err
+	}
+	if err := fs.makeBlockFile(ctx, target); err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{
+		"device": dev,
+		"target": target,
+	}).Info("bind mounting block device")
+	return fs.mount(ctx, dev, target, "", append(opts[:len(opts):len(opts)], "bind")...)
+}
+
+// unmountBlockDevice unmounts the block device bind mounted onto target,
+// if any, and removes the file.
+func (fs *FS) unmountBlockDevice(ctx context.Context, target string) error {
+	path := filepath.Clean(target)
+	if err := validatePath(path); err != nil {
+		return err
+	}
+	if _, err := os.Lstat(path); os.IsNotExist(err) {
+		return nil
+	}
+	mounted, err := fs.isMountPoint(ctx, path)
+	if err != nil {
+		return err
+	}
+	if mounted {
+		if err := fs.unmount(ctx, path); err != nil {
+			return err
+		}
+	}
+	if fs.dryRun(DryRunRemove, path, "") {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove block file %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// getDevicePathsForMountPoint returns the block devices mounted at
+// target. A device file bind mounted from devtmpfs is found from the root
+// of its mount, e.g. /sdc, and from the device number of target, which
+// also catches devices renamed since they were published. Filesystems
+// mounted from a device report their mount source.
+func (fs *FS) getDevicePathsForMountPoint(ctx context.Context, target string) ([]string, error) {
+	target = filepath.Clean(target)
+	var paths []string
+	add := func(p string) {
+		if !stringInSlice(p, paths) {
+			paths = append(paths, p)
+		}
+	}
+
+	if p, ok := fs.blockDevicePathOf(target); ok {
+		add(p)
+	}
+	err := fs.scanProcMounts(ctx, MountEntryRoot|MountEntryMountPoint|MountEntryFSType|MountEntryMountSource,
+		func(_ context.Context, e Entry) (bool, error) {
+			if filepath.Clean(e.MountPoint) != target {
+				return true, nil
+			}
+			switch {
+			case e.FSType == "devtmpfs":
+				if e.Root != "" && e.Root != "/" {
+					add(fs.devPath(path.Join(defaultDevRoot, e.Root)))
+				}
+			case strings.HasPrefix(e.MountSource, "/dev/"):
+				add(e.MountSource)
+			}
+			return true, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no block device mounted at %s: %w", target, os.ErrNotExist)
+	}
+	return paths, nil
+}
+
+// blockDevicePathOf returns the device node of the block device file p,
+// found from its device number in /sys/dev/block.
+func (fs *FS) blockDevicePathOf(p string) (string, bool) {
+	var st unix.Stat_t
+	if err := unix.Stat(p, &st); err != nil || st.Mode&unix.S_IFMT != unix.S_IFBLK {
+		return "", false
+	}
+	link := fs.sysPath(fmt.Sprintf("/sys/dev/block/%d:%d", unix.Major(st.Rdev), unix.Minor(st.Rdev)))
+	dir, err := filepath.EvalSymlinks(link)
+	if err != nil {
+		return "", false
+	}
+	return fs.devPath(path.Join(defaultDevRoot, filepath.Base(dir))), true
+}
@@ -0,0 +1,74 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// blockDevSetRO sets the read-only state of the block device with the
+// BLKROSET ioctl, like blockdev --setro and --setrw.
+func (fs *FS) blockDevSetRO(_ context.Context, device string, readOnly bool) error {
+	path := filepath.Clean(device)
+	if err := validatePath(path); err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{
+		"device":   path,
+		"readOnly": readOnly,
+	}).Info("setting block device read-only state")
+	value := 0
+	if readOnly {
+		value = 1
+	}
+	if fs.dryRun(DryRunIoctl, path, fmt.Sprintf("BLKROSET %d", value)) {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close() // #nosec G307
+	if err := unix.IoctlSetPointerInt(int(f.Fd()), unix.BLKROSET, value); err != nil {
+		return fmt.Errorf("failed to set read-only state of %s to %t: %v", path, readOnly, err)
+	}
+	return nil
+}
+
+// blockDevGetRO returns the read-only state of the block device with the
+// BLKROGET ioctl, like blockdev --getro.
+func (fs *FS) blockDevGetRO(_ context.Context, device string) (bool, error) {
+	path := filepath.Clean(device)
+	if err := validatePath(path); err != nil {
+		return false, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close() // #nosec G307
+	value, err := unix.IoctlGetInt(int(f.Fd()), unix.BLKROGET)
+	if err != nil {
+		return false, fmt.Errorf("failed to get read-only state of %s: %v", path, err)
+	}
+	return value != 0, nil
+}
@@ -0,0 +1,80 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockDevSetRO(t *testing.T) {
+	const device = "/dev/loop0"
+	fs := NewFS(FSOptions{})
+	ctx := context.Background()
+	readOnly, err := fs.BlockDevGetRO(ctx, device)
+	if err != nil {
+		t.Skipf("cannot get read-only state of %s: %v", device, err)
+	}
+	defer fs.BlockDevSetRO(ctx, device, readOnly) // #nosec G104
+
+	require.NoError(t, fs.BlockDevSetRO(ctx, device, true))
+	readOnly, err = fs.BlockDevGetRO(ctx, device)
+	require.NoError(t, err)
+	assert.True(t, readOnly)
+
+	require.NoError(t, fs.BlockDevSetRO(ctx, device, false))
+	readOnly, err = fs.BlockDevGetRO(ctx, device)
+	require.NoError(t, err)
+	assert.False(t, readOnly)
+
+	// Regular files are not block devices.
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, fs.makeBlockFile(ctx, file))
+	_, err = fs.BlockDevGetRO(ctx, file)
+	assert.Error(t, err)
+	assert.Error(t, fs.BlockDevSetRO(ctx, file, true))
+}
+
+func TestMockBlockDevSetRO(t *testing.T) {
+	UseMockFS()
+	ResetMockFS()
+	defer UseFSOptions(FSOptions{})
+	defer ResetMockFS()
+	ctx := context.Background()
+
+	require.NoError(t, BlockDevSetRO(ctx, "/dev/sdx", true))
+	readOnly, err := BlockDevGetRO(ctx, "/dev/sdx")
+	require.NoError(t, err)
+	assert.True(t, readOnly)
+
+	GOFSMock.InduceBlockDevSetRWError = true
+	assert.Error(t, BlockDevSetRO(ctx, "/dev/sdx", false))
+	GOFSMock.InduceBlockDevSetRWError = false
+	require.NoError(t, BlockDevSetRO(ctx, "/dev/sdx", false))
+	readOnly, err = BlockDevGetRO(ctx, "/dev/sdx")
+	require.NoError(t, err)
+	assert.False(t, readOnly)
+
+	GOFSMock.InduceBlockDevSetROError = true
+	assert.Error(t, BlockDevSetRO(ctx, "/dev/sdx", true))
+	GOFSMock.InduceBlockDevGetROError = true
+	_, err = BlockDevGetRO(ctx, "/dev/sdx")
+	assert.Error(t, err)
+}
@@ -0,0 +1,64 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"sync"
+	"time"
+)
+
+// diskFormatCache remembers the filesystem type of devices, keyed by
+// the device number (major:minor) so that a device node recycled for a
+// different device or reached through another path is handled correctly.
+// The zero value is ready to use.
+type diskFormatCache struct {
+	mu      sync.Mutex
+	entries map[uint64]diskFormatEntry
+}
+
+type diskFormatEntry struct {
+	format  string
+	expires time.Time
+}
+
+// get returns the cached format of the device dev.
+func (c *diskFormatCache) get(dev uint64) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[dev]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(e.expires) {
+		delete(c.entries, dev)
+		return "", false
+	}
+	return e.format, true
+}
+
+// put caches the format of the device dev for ttl.
+func (c *diskFormatCache) put(dev uint64, format string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[uint64]diskFormatEntry)
+	}
+	c.entries[dev] = diskFormatEntry{format: format, expires: time.Now().Add(ttl)}
+}
+
+// invalidate forgets the format of the device dev.
+func (c *diskFormatCache) invalidate(dev uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, dev)
+}
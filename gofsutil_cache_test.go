@@ -0,0 +1,45 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskFormatCache(t *testing.T) {
+	var c diskFormatCache
+
+	_, ok := c.get(2064)
+	assert.False(t, ok)
+
+	c.put(2064, "xfs", time.Minute)
+	format, ok := c.get(2064)
+	assert.True(t, ok)
+	assert.Equal(t, "xfs", format)
+
+	// Another device number is a different device.
+	_, ok = c.get(2080)
+	assert.False(t, ok)
+
+	c.invalidate(2064)
+	_, ok = c.get(2064)
+	assert.False(t, ok)
+
+	c.put(2064, "ext4", -time.Second)
+	_, ok = c.get(2064)
+	assert.False(t, ok)
+	assert.Empty(t, c.entries)
+}
@@ -0,0 +1,97 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DefaultCapacityTolerance is the default fraction of the expected size
+// by which the capacity of a mounted filesystem may differ from it and
+// still match. The capacity reported by statfs excludes the metadata of
+// the filesystem, e.g. the journal and inode tables of ext4, which take a
+// few percent of the device.
+const DefaultCapacityTolerance = 0.1
+
+// ErrCapacityMismatch is matched by the error of VerifyMountedCapacity
+// when the capacity of the filesystem does not match the expected size.
+var ErrCapacityMismatch = errors.New("filesystem capacity does not match the expected size")
+
+// CapacityMismatchError is the error returned by VerifyMountedCapacity
+// when the capacity of the filesystem mounted at the target differs from
+// the expected size by more than the tolerance. It matches
+// ErrCapacityMismatch.
+type CapacityMismatchError struct {
+	// Target is the mount point.
+	Target string
+	// Expected is the expected size, in bytes.
+	Expected int64
+	// Capacity is the capacity of the filesystem reported by statfs, in
+	// bytes.
+	Capacity int64
+	// Tolerance is the fraction of Expected the capacity was allowed to
+	// differ by.
+	Tolerance float64
+}
+
+func (e *CapacityMismatchError) Error() string {
+	return fmt.Sprintf("capacity of %s is %d bytes, expected %d bytes within %g%%",
+		e.Target, e.Capacity, e.Expected, e.Tolerance*100)
+}
+
+// Is returns true if target is ErrCapacityMismatch.
+func (e *CapacityMismatchError) Is(target error) bool {
+	return target == ErrCapacityMismatch
+}
+
+// checkCapacity returns a CapacityMismatchError if capacity differs from
+// expected by more than tolerance, a fraction of expected.
+// DefaultCapacityTolerance is used if tolerance is not positive.
+func checkCapacity(target string, capacity, expected int64, tolerance float64) error {
+	if expected <= 0 {
+		return fmt.Errorf("invalid expected size %d of %s", expected, target)
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultCapacityTolerance
+	}
+	if tolerance >= 1 {
+		return fmt.Errorf("invalid capacity tolerance %g of %s", tolerance, target)
+	}
+	diff := capacity - expected
+	if diff < 0 {
+		diff = -diff
+	}
+	if float64(diff) > tolerance*float64(expected) {
+		return &CapacityMismatchError{Target: target, Expected: expected, Capacity: capacity, Tolerance: tolerance}
+	}
+	return nil
+}
+
+// verifyMountedCapacity checks that target is a mount point and that the
+// capacity of its filesystem matches expected within tolerance.
+func (fs *FS) verifyMountedCapacity(ctx context.Context, target string, expected int64, tolerance float64) error {
+	mounted, err := fs.isMountPoint(ctx, target)
+	if err != nil {
+		return err
+	}
+	if !mounted {
+		return fmt.Errorf("%s is not a mount point", target)
+	}
+	_, capacity, _, _, _, _, err := fs.fsInfo(ctx, target)
+	if err != nil {
+		return err
+	}
+	return checkCapacity(target, capacity, expected, tolerance)
+}
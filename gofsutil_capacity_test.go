@@ -0,0 +1,59 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCapacity(t *testing.T) {
+	const gib = 1 << 30
+	assert.NoError(t, checkCapacity("/mnt", gib, gib, 0))
+	// The metadata of ext4 takes a few percent of a 1 GiB device.
+	assert.NoError(t, checkCapacity("/mnt", 973<<20, gib, 0))
+	assert.NoError(t, checkCapacity("/mnt", 973<<20, gib, 0.05))
+
+	err := checkCapacity("/mnt", 512<<20, gib, 0)
+	assert.ErrorIs(t, err, ErrCapacityMismatch)
+	var mismatch *CapacityMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, &CapacityMismatchError{
+		Target: "/mnt", Expected: gib, Capacity: 512 << 20, Tolerance: DefaultCapacityTolerance,
+	}, mismatch)
+	assert.EqualError(t, err, "capacity of /mnt is 536870912 bytes, expected 1073741824 bytes within 10%")
+
+	// A filesystem larger than expected is not the expected device.
+	assert.ErrorIs(t, checkCapacity("/mnt", 2*gib, gib, 0), ErrCapacityMismatch)
+	assert.ErrorIs(t, checkCapacity("/mnt", 973<<20, gib, 0.01), ErrCapacityMismatch)
+
+	assert.Error(t, checkCapacity("/mnt", gib, 0, 0))
+	assert.Error(t, checkCapacity("/mnt", gib, gib, 1))
+}
+
+func TestVerifyMountedCapacity(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("statfs is only checked on linux")
+	}
+	ctx := context.Background()
+	fs := NewFS(FSOptions{})
+	_, capacity, _, _, _, _, err := fs.FsInfo(ctx, "/")
+	require.NoError(t, err)
+	assert.NoError(t, fs.VerifyMountedCapacity(ctx, "/", capacity, 0))
+	assert.ErrorIs(t, fs.VerifyMountedCapacity(ctx, "/", 2*capacity, 0), ErrCapacityMismatch)
+	assert.ErrorContains(t, fs.VerifyMountedCapacity(ctx, t.TempDir(), capacity, 0), "not a mount point")
+}
@@ -0,0 +1,48 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import "time"
+
+const (
+	// DefaultDeviceCleanupTimeout is how long CleanupDeviceForWWN waits
+	// for the devices to disappear when the context has no deadline.
+	DefaultDeviceCleanupTimeout = 30 * time.Second
+	// DeviceCleanupPollInterval is the interval between the checks of
+	// CleanupDeviceForWWN for the devices to disappear.
+	DeviceCleanupPollInterval = 500 * time.Millisecond
+	// deviceCleanupFlushTimeout is the timeout, in seconds, of the
+	// multipath flush of CleanupDeviceForWWN.
+	deviceCleanupFlushTimeout = 10
+)
+
+// DeviceCleanupReport describes the outcome of CleanupDeviceForWWN.
+type DeviceCleanupReport struct {
+	// WWN is the WWN of the volume.
+	WWN string
+	// Devices are the path devices of the volume that were found, e.g.
+	// sdx or nvme0n1.
+	Devices []string
+	// MpathDevice is the multipath device of the volume, if any.
+	MpathDevice *MpathDevice
+	// Flushed is set if the multipath map was flushed.
+	Flushed bool
+	// Removed are the path devices that were deleted. NVMe namespaces
+	// are not deleted, they go away when their controllers are
+	// disconnected.
+	Removed []string
+	// Remaining are the devices, including the multipath device, that
+	// were still present when the cleanup gave up waiting for them to
+	// disappear.
+	Remaining []string
+}
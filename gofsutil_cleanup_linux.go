@@ -0,0 +1,144 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// cleanupDeviceForWWN removes the multipath and path devices of the
+// volume with the given WWN once it is no longer mounted.
+func (fs *FS) cleanupDeviceForWWN(ctx context.Context, wwn string) (*DeviceCleanupReport, error) {
+	report := &DeviceCleanupReport{WWN: wwn}
+	devices, err := fs.getSysBlockDevicesForVolumeWWN(ctx, wwn)
+	if err != nil {
+		return report, err
+	}
+	report.Devices = devices
+	mpath, err := fs.getMpathDeviceForWWN(ctx, wwn)
+	if err != nil {
+		return report, err
+	}
+	report.MpathDevice = mpath
+	if len(devices) == 0 && mpath == nil {
+		return report, nil
+	}
+	f := log.Fields{
+		"wwn":     wwn,
+		"devices": devices,
+	}
+
+	// Refuse to remove devices with mounted filesystems, the mounts would
+	// become stale.
+	var names, present []string
+	for _, dev := range devices {
+		if !strings.HasPrefix(dev, "nvme") {
+			present = append(present, dev)
+		}
+		if n, err := fs.blockDeviceNames(fs.devPath("/dev/" + dev)); err == nil {
+			names = append(names, n...)
+		}
+	}
+	if mpath != nil {
+		f["mpath"] = mpath.Name
+		present = append(present, filepath.Base(mpath.DMNode))
+		if n, err := fs.blockDeviceNames(fs.devPath(mpath.DMNode)); err == nil {
+			names = append(names, n...)
+		}
+	}
+	mountPoints, err := fs.blockDeviceMountPoints(ctx, names)
+	if err != nil {
+		return report, err
+	}
+	if len(mountPoints) > 0 {
+		return report, &InUseError{Device: wwn, Reason: DeviceMounted, Users: mountPoints}
+	}
+
+	intent := JournalEntry{Op: JournalOpDeviceCleanup, Step: JournalStepFlushing, WWN: wwn}
+	if mpath != nil {
+		intent.Device = mpath.Name
+	}
+	entry := fs.journalBegin(intent)
+	defer fs.journalEnd(entry)
+
+	if mpath != nil {
+		log.WithFields(f).Info("flushing multipath device")
+		out, err := fs.multipathCommand(ctx, deviceCleanupFlushTimeout, "", "-f", mpath.Name)
+		if err != nil {
+			return report, fmt.Errorf("failed to flush multipath device %s: %v: %s",
+				mpath.Name, err, strings.TrimSpace(string(out)))
+		}
+		report.Flushed = true
+		fs.journalStep(entry, JournalStepFlushed)
+	}
+
+	var errs []error
+	for _, dev := range devices {
+		if strings.HasPrefix(dev, "nvme") {
+			continue
+		}
+		log.WithFields(f).WithField("device", dev).Info("removing path device")
+		if err := fs.removeBlockDevice(ctx, "/dev/"+dev); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		report.Removed = append(report.Removed, dev)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return report, err
+	}
+
+	report.Remaining, err = fs.waitForBlockDevicesGone(ctx, present)
+	return report, err
+}
+
+// waitForBlockDevicesGone waits until the block devices with the given
+// sysfs names disappear from sysfs, and returns the devices that remain
+// if they do not.
+func (fs *FS) waitForBlockDevicesGone(ctx context.Context, names []string) ([]string, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultDeviceCleanupTimeout)
+		defer cancel()
+	}
+	ticker := time.NewTicker(DeviceCleanupPollInterval)
+	defer ticker.Stop()
+	for {
+		var remaining []string
+		for _, name := range names {
+			if _, err := os.Stat(filepath.Join(fs.sysBlockDir(), name)); err == nil {
+				remaining = append(remaining, name)
+			}
+		}
+		if len(remaining) == 0 {
+			return nil, nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return remaining, fmt.Errorf("devices %s did not disappear: %w",
+				strings.Join(remaining, ", "), ctx.Err())
+		}
+	}
+}
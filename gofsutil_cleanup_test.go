@@ -0,0 +1,114 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanupDeviceForWWN(t *testing.T) {
+	const wwn = "60000970000120001263533030313434"
+	sysRoot, devRoot, procRoot := t.TempDir(), t.TempDir(), t.TempDir()
+	deviceDir := filepath.Join(sysRoot, "block", "sdx", "device")
+	require.NoError(t, os.MkdirAll(deviceDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(deviceDir, "wwid"), []byte(wwn+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(deviceDir, "state"), []byte("running\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(deviceDir, "delete"), nil, 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "class", "block", "sdx"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(devRoot, "sdx"), nil, 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(procRoot, "self"), 0o750))
+	mountInfo := filepath.Join(procRoot, "self", "mountinfo")
+	mounted := mountKindMountInfo + "30 22 8:16 / /mnt/data rw,relatime - xfs /dev/sdx rw\n"
+	require.NoError(t, os.WriteFile(mountInfo, []byte(mounted), 0o600))
+	fs := NewFS(FSOptions{SysRoot: sysRoot, DevRoot: devRoot, ProcRoot: procRoot})
+	ctx := context.Background()
+
+	// A mounted device is left alone.
+	report, err := fs.CleanupDeviceForWWN(ctx, wwn)
+	var inUse *InUseError
+	require.True(t, errors.As(err, &inUse), err)
+	assert.Equal(t, []string{"/mnt/data"}, inUse.Users)
+	assert.Equal(t, []string{"sdx"}, report.Devices)
+	assert.Empty(t, report.Removed)
+	require.NoError(t, os.WriteFile(mountInfo, []byte(mountKindMountInfo), 0o600))
+
+	// The device is deleted but does not disappear.
+	timeout, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	report, err = fs.CleanupDeviceForWWN(timeout, wwn)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []string{"sdx"}, report.Removed)
+	assert.Equal(t, []string{"sdx"}, report.Remaining)
+	buf, err := os.ReadFile(filepath.Join(deviceDir, "delete"))
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(buf))
+
+	// The device disappears.
+	require.NoError(t, os.WriteFile(filepath.Join(deviceDir, "delete"), nil, 0o600))
+	go func() {
+		for {
+			if buf, _ := os.ReadFile(filepath.Join(deviceDir, "delete")); len(buf) > 0 {
+				os.RemoveAll(filepath.Dir(deviceDir)) // #nosec G104
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	report, err = fs.CleanupDeviceForWWN(ctx, wwn)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sdx"}, report.Removed)
+	assert.Empty(t, report.Remaining)
+	assert.False(t, report.Flushed)
+
+	// Nothing is left to clean up.
+	report, err = fs.CleanupDeviceForWWN(ctx, wwn)
+	require.NoError(t, err)
+	assert.Empty(t, report.Devices)
+	assert.Nil(t, report.MpathDevice)
+}
+
+func TestMockCleanupDeviceForWWN(t *testing.T) {
+	const wwn = "60000970000120001263533030313434"
+	UseMockFS()
+	ResetMockFS()
+	defer UseFSOptions(FSOptions{})
+	defer ResetMockFS()
+	ctx := context.Background()
+
+	GOFSMockWWNToDevice = map[string]string{wwn: "/dev/sdx"}
+	GOFSMockMounts = []Info{{Device: "/dev/sdx", Path: "/mnt/data"}}
+	_, err := CleanupDeviceForWWN(ctx, wwn)
+	var inUse *InUseError
+	require.True(t, errors.As(err, &inUse), err)
+
+	GOFSMockMounts = nil
+	report, err := CleanupDeviceForWWN(ctx, wwn)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sdx"}, report.Removed)
+	assert.Empty(t, GOFSMockWWNToDevice)
+
+	GOFSMock.InduceCleanupDeviceError = true
+	_, err = CleanupDeviceForWWN(ctx, wwn)
+	assert.Error(t, err)
+}
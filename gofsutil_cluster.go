@@ -0,0 +1,79 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// FsTypeOCFS2 is the OCFS2 shared cluster filesystem.
+	FsTypeOCFS2 = "ocfs2"
+	// FsTypeGFS2 is the GFS2 shared cluster filesystem.
+	FsTypeGFS2 = "gfs2"
+
+	// gfs2NoLock is the GFS2 lock protocol of a filesystem that is only
+	// mounted on a single node and needs no cluster stack.
+	gfs2NoLock = "lock_nolock"
+)
+
+// ErrClusterStackNotRunning is returned when an OCFS2 or GFS2 filesystem
+// is mounted or formatted while the cluster stack it needs, o2cb or dlm,
+// is not up.
+var ErrClusterStackNotRunning = errors.New("cluster stack is not running")
+
+// isClusterFsType returns true if fsType is a shared cluster filesystem.
+func isClusterFsType(fsType string) bool {
+	return fsType == FsTypeOCFS2 || fsType == FsTypeGFS2
+}
+
+// gfs2LockNoLock returns true if the mount or mkfs arguments select the
+// lock_nolock protocol, e.g. lockproto=lock_nolock or -p lock_nolock.
+func gfs2LockNoLock(args []string) bool {
+	for _, arg := range args {
+		if strings.Contains(arg, gfs2NoLock) {
+			return true
+		}
+	}
+	return false
+}
+
+// clusterMkfsArgs returns the arguments of mkfs formatting source with
+// the cluster filesystem fsType. The ext and xfs defaults do not apply:
+// the user provided format options are passed as is, mkfs.gfs2 is kept
+// from prompting, and nodiscard is given in the form each mkfs expects.
+// A clustered GFS2 filesystem needs its lock table, e.g. -t
+// mycluster:vol1, in the format options.
+func clusterMkfsArgs(fsType, source string, formatOpts []string, noDiscard bool) ([]string, error) {
+	var args []string
+	switch fsType {
+	case FsTypeGFS2:
+		if !stringInSlice("-t", formatOpts) && !gfs2LockNoLock(formatOpts) {
+			return nil, fmt.Errorf("formatting %s as gfs2 needs the lock table, e.g. -t <cluster>:<name>, in the format options", source)
+		}
+		args = append(args, "-O")
+		if noDiscard {
+			args = append(args, "-K")
+		}
+	case FsTypeOCFS2:
+		if noDiscard {
+			args = append(args, "--nodiscard")
+		}
+	default:
+		return nil, fmt.Errorf("%s is not a cluster filesystem", fsType)
+	}
+	args = append(args, formatOpts...)
+	return append(args, source), nil
+}
@@ -0,0 +1,98 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterMkfsArgs(t *testing.T) {
+	args, err := clusterMkfsArgs("gfs2", "/dev/sdb", []string{"-t", "mycluster:vol1", "-j", "3"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-O", "-t", "mycluster:vol1", "-j", "3", "/dev/sdb"}, args)
+
+	args, err = clusterMkfsArgs("gfs2", "/dev/sdb", []string{"-p", "lock_nolock"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-O", "-K", "-p", "lock_nolock", "/dev/sdb"}, args)
+
+	_, err = clusterMkfsArgs("gfs2", "/dev/sdb", nil, false)
+	assert.ErrorContains(t, err, "lock table")
+
+	args, err = clusterMkfsArgs("ocfs2", "/dev/sdb", nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--nodiscard", "/dev/sdb"}, args)
+
+	args, err = clusterMkfsArgs("ocfs2", "/dev/sdb", []string{"-N", "4"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-N", "4", "/dev/sdb"}, args)
+
+	_, err = clusterMkfsArgs("xfs", "/dev/sdb", nil, false)
+	assert.Error(t, err)
+}
+
+func TestSplitFormatOption(t *testing.T) {
+	opts, formatOpts := splitFormatOption([]string{"noatime", "fsFormatOption:-t c:v -j 2"})
+	assert.Equal(t, []string{"noatime"}, opts)
+	assert.Equal(t, []string{"-t", "c:v", "-j", "2"}, formatOpts)
+
+	opts, formatOpts = splitFormatOption([]string{"noatime"})
+	assert.Equal(t, []string{"noatime"}, opts)
+	assert.Nil(t, formatOpts)
+}
+
+func TestCheckClusterStack(t *testing.T) {
+	ctx := context.Background()
+	sys := filepath.Join(t.TempDir(), "sys")
+	fs := NewFS(FSOptions{SysRoot: sys})
+	writeAttr := func(path, value string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(value+"\n"), 0o600))
+	}
+
+	assert.NoError(t, fs.CheckClusterStack(ctx, "xfs"))
+	err := fs.CheckClusterStack(ctx, "ocfs2")
+	assert.ErrorIs(t, err, ErrClusterStackNotRunning)
+	assert.ErrorContains(t, err, "module is not loaded")
+
+	writeAttr(filepath.Join(sys, "fs", "ocfs2", "cluster_stack"), "o2cb")
+	assert.ErrorContains(t, fs.CheckClusterStack(ctx, "ocfs2"), "o2cb cluster is not online")
+	require.NoError(t, os.MkdirAll(filepath.Join(sys, "kernel", "config", "cluster", "mycluster"), 0o755))
+	assert.NoError(t, fs.CheckClusterStack(ctx, "ocfs2"))
+
+	writeAttr(filepath.Join(sys, "fs", "ocfs2", "cluster_stack"), "pcmk")
+	assert.ErrorContains(t, fs.CheckClusterStack(ctx, "ocfs2"), "needs dlm")
+
+	err = fs.CheckClusterStack(ctx, "gfs2", "noatime")
+	assert.ErrorIs(t, err, ErrClusterStackNotRunning)
+	assert.NoError(t, fs.CheckClusterStack(ctx, "gfs2", "lockproto=lock_nolock"))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(sys, "kernel", "config", "dlm", "cluster"), 0o755))
+	assert.NoError(t, fs.CheckClusterStack(ctx, "gfs2"))
+	assert.NoError(t, fs.CheckClusterStack(ctx, "ocfs2"))
+
+	// A mount fails before mount is run.
+	fs = NewFS(FSOptions{SysRoot: filepath.Join(sys, "missing")})
+	err = fs.mount(ctx, "/dev/sdb", "/mnt/shared", "gfs2")
+	assert.ErrorIs(t, err, ErrClusterStackNotRunning)
+	err = fs.formatAndMount(ctx, "/dev/sdb", "/mnt/shared", "ocfs2")
+	assert.ErrorIs(t, err, ErrClusterStackNotRunning)
+}
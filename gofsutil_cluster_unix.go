@@ -0,0 +1,70 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+const (
+	// ocfs2ClusterStackPath holds the cluster stack of the ocfs2 module,
+	// o2cb or pcmk.
+	ocfs2ClusterStackPath = "/sys/fs/ocfs2/cluster_stack"
+	// o2cbClusterDir holds the configfs directory of the online o2cb
+	// cluster.
+	o2cbClusterDir = "/sys/kernel/config/cluster"
+	// dlmClusterDir is created in configfs by dlm_controld.
+	dlmClusterDir = "/sys/kernel/config/dlm/cluster"
+)
+
+// checkClusterStack returns an error wrapping ErrClusterStackNotRunning
+// if fsType is a cluster filesystem whose cluster stack is not up. The
+// mount or mkfs arguments are used to tell a GFS2 filesystem using
+// lock_nolock, which needs no cluster stack.
+func (fs *FS) checkClusterStack(_ context.Context, fsType string, args ...string) error {
+	switch fsType {
+	case FsTypeOCFS2:
+		stack := readSysfsAttr(fs.sysPath(ocfs2ClusterStackPath))
+		if stack == "" {
+			return fmt.Errorf("%w: the ocfs2 kernel module is not loaded", ErrClusterStackNotRunning)
+		}
+		if stack != "o2cb" {
+			return fs.checkDLM(fsType)
+		}
+		clusters, _ := os.ReadDir(fs.sysPath(o2cbClusterDir))
+		if len(clusters) == 0 {
+			return fmt.Errorf("%w: the o2cb cluster is not online, run o2cb.init online", ErrClusterStackNotRunning)
+		}
+	case FsTypeGFS2:
+		if gfs2LockNoLock(args) {
+			return nil
+		}
+		return fs.checkDLM(fsType)
+	}
+	return nil
+}
+
+// checkDLM returns an error if the distributed lock manager, which the
+// cluster filesystem fsType uses for locking, is not running.
+func (fs *FS) checkDLM(fsType string) error {
+	if _, err := os.Stat(fs.sysPath(dlmClusterDir)); err != nil {
+		return fmt.Errorf("%w: %s needs dlm, but %s does not exist, is dlm_controld running?",
+			ErrClusterStackNotRunning, fsType, dlmClusterDir)
+	}
+	return nil
+}
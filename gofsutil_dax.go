@@ -0,0 +1,83 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// daxOption enables DAX for all files, supported by xfs since Linux
+	// 5.8 and by ext4 since Linux 5.10.
+	daxOption = "dax=always"
+	// daxLegacyOption is the DAX option of older kernels.
+	daxLegacyOption = "dax"
+)
+
+// daxFsTypes are the filesystems that support DAX.
+var daxFsTypes = []string{"ext4", "xfs"}
+
+// pmemDeviceRegexp matches the names of persistent memory block devices,
+// e.g. pmem0, pmem0.1 for an additional namespace of a region, or pmem0s
+// for a namespace in sector mode.
+var pmemDeviceRegexp = regexp.MustCompile(`^pmem\d+(\.\d+)?s?$`)
+
+// DAXError is returned by MountDAX when the filesystem cannot be mounted
+// with direct access.
+type DAXError struct {
+	// Device is the device that was to be mounted.
+	Device string
+	// FsType is the filesystem type.
+	FsType string
+	// Reason describes why DAX cannot be used.
+	Reason string
+	// Err is the underlying error, if any.
+	Err error
+}
+
+func (e *DAXError) Error() string {
+	msg := fmt.Sprintf("cannot mount %s (%s) with DAX: %s", e.Device, e.FsType, e.Reason)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *DAXError) Unwrap() error {
+	return e.Err
+}
+
+// isPMEMDeviceName returns true if name is the name of a persistent
+// memory block device.
+func isPMEMDeviceName(name string) bool {
+	return pmemDeviceRegexp.MatchString(name)
+}
+
+// withDAXOption returns opts with dax=always added, unless opts already
+// have a dax option. The second result tells if the option was added.
+func withDAXOption(opts []string) ([]string, bool) {
+	for _, o := range opts {
+		if o == daxLegacyOption || strings.HasPrefix(o, daxLegacyOption+"=") {
+			return opts, false
+		}
+	}
+	return append(opts[:len(opts):len(opts)], daxOption), true
+}
+
+// hasDAXEnabled returns true if the mount options enable DAX for all
+// files.
+func hasDAXEnabled(opts []string) bool {
+	return stringInSlice(daxOption, opts) || stringInSlice(daxLegacyOption, opts)
+}
@@ -0,0 +1,118 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// isPMEMDevice returns true if device is a persistent memory block
+// device, e.g. /dev/pmem0.
+func (fs *FS) isPMEMDevice(_ context.Context, device string) (bool, error) {
+	dev, err := filepath.EvalSymlinks(device)
+	if err != nil {
+		return false, err
+	}
+	return isPMEMDeviceName(filepath.Base(dev)), nil
+}
+
+// mountDAX mounts source with the dax option after checking that the
+// filesystem and device support DAX, and checks that DAX is enabled on
+// the mounted filesystem.
+func (fs *FS) mountDAX(ctx context.Context, source, target, fsType string, opts ...string) error {
+	if !stringInSlice(fsType, daxFsTypes) {
+		return &DAXError{Device: source, FsType: fsType, Reason: "the filesystem does not support DAX"}
+	}
+	dev, err := filepath.EvalSymlinks(source)
+	if err != nil {
+		return err
+	}
+	name := filepath.Base(dev)
+	if readSysfsAttr(filepath.Join(fs.sysBlockDir(), name, "queue", "dax")) != "1" {
+		return &DAXError{Device: source, FsType: fsType, Reason: "the device does not support DAX"}
+	}
+
+	daxOpts, added := withDAXOption(opts)
+	err = fs.mount(ctx, source, target, fsType, daxOpts...)
+	if err != nil && added {
+		// Kernels before 5.8 (xfs) and 5.10 (ext4) only accept dax.
+		log.WithField("target", target).WithError(err).Info("retrying mount with the legacy dax option")
+		err = fs.mount(ctx, source, target, fsType, append(opts[:len(opts):len(opts)], daxLegacyOption)...)
+	}
+	if err != nil {
+		return &DAXError{Device: source, FsType: fsType, Reason: "the mount with DAX failed", Err: err}
+	}
+
+	// ext4 mounts the filesystem without DAX when it cannot use it.
+	if !hasDAXEnabled(daxOpts) {
+		return nil
+	}
+	superOpts, err := fs.mountSuperOptions(target)
+	if err != nil {
+		return err
+	}
+	if !hasDAXEnabled(superOpts) {
+		if err := fs.unmount(ctx, target); err != nil {
+			log.WithField("target", target).WithError(err).Error("failed to unmount filesystem mounted without DAX")
+		}
+		return &DAXError{Device: source, FsType: fsType, Reason: "the kernel mounted the filesystem without DAX"}
+	}
+	return nil
+}
+
+// mountSuperOptions returns the per-mount and per-superblock options of
+// the last filesystem mounted on target.
+func (fs *FS) mountSuperOptions(target string) ([]string, error) {
+	file, err := os.Open(filepath.Clean(fs.mountInfoPath()))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() // #nosec G307
+	target = filepath.Clean(target)
+	var opts []string
+	found := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+		fields := strings.Fields(scanner.Text())
+		sep := -1
+		for i, f := range fields {
+			if f == "-" {
+				sep = i
+				break
+			}
+		}
+		if sep < 6 || len(fields) < sep+4 || unescapeMountPath(fields[4]) != target {
+			continue
+		}
+		opts = append(strings.Split(fields[5], ","), strings.Split(fields[sep+3], ",")...)
+		found = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%s is not mounted", target)
+	}
+	return opts, nil
+}
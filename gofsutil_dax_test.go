@@ -0,0 +1,113 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPMEMDeviceName(t *testing.T) {
+	for _, name := range []string{"pmem0", "pmem12", "pmem0.1", "pmem1s"} {
+		assert.True(t, isPMEMDeviceName(name), name)
+	}
+	for _, name := range []string{"pmem", "pmem0p1", "sda", "dax0.0"} {
+		assert.False(t, isPMEMDeviceName(name), name)
+	}
+}
+
+func TestWithDAXOption(t *testing.T) {
+	opts, added := withDAXOption([]string{"noatime"})
+	assert.True(t, added)
+	assert.Equal(t, []string{"noatime", "dax=always"}, opts)
+	for _, o := range []string{"dax", "dax=inode"} {
+		opts, added = withDAXOption([]string{o})
+		assert.False(t, added)
+		assert.Equal(t, []string{o}, opts)
+	}
+	assert.True(t, hasDAXEnabled([]string{"rw", "dax"}))
+	assert.False(t, hasDAXEnabled([]string{"rw", "dax=inode"}))
+}
+
+func TestMountDAX(t *testing.T) {
+	tmp := t.TempDir()
+	devDir := filepath.Join(tmp, "dev")
+	require.NoError(t, os.MkdirAll(devDir, 0o755))
+	pmem := filepath.Join(devDir, "pmem0")
+	require.NoError(t, os.WriteFile(pmem, nil, 0o600))
+	sda := filepath.Join(devDir, "sda")
+	require.NoError(t, os.WriteFile(sda, nil, 0o600))
+	daxAttr := filepath.Join(tmp, "sys", "block", "pmem0", "queue", "dax")
+	require.NoError(t, os.MkdirAll(filepath.Dir(daxAttr), 0o755))
+	require.NoError(t, os.WriteFile(daxAttr, []byte("1\n"), 0o600))
+	mountInfo := filepath.Join(tmp, "proc", "self", "mountinfo")
+	require.NoError(t, os.MkdirAll(filepath.Dir(mountInfo), 0o755))
+	bin := t.TempDir()
+	log := filepath.Join(t.TempDir(), "log")
+	writeRecorder(t, filepath.Join(bin, "mount"), log)
+	writeRecorder(t, filepath.Join(bin, "umount"), log)
+	fs := NewFS(FSOptions{
+		SysRoot:      filepath.Join(tmp, "sys"),
+		ProcRoot:     filepath.Join(tmp, "proc"),
+		MountBinary:  filepath.Join(bin, "mount"),
+		UmountBinary: filepath.Join(bin, "umount"),
+	})
+	ctx := context.Background()
+	target := "/mnt/pmem0"
+
+	isPMEM, err := fs.IsPMEMDevice(ctx, pmem)
+	require.NoError(t, err)
+	assert.True(t, isPMEM)
+	isPMEM, err = fs.IsPMEMDevice(ctx, sda)
+	require.NoError(t, err)
+	assert.False(t, isPMEM)
+
+	var daxErr *DAXError
+	err = fs.MountDAX(ctx, pmem, target, "nfs")
+	require.True(t, errors.As(err, &daxErr), err)
+	assert.Equal(t, "the filesystem does not support DAX", daxErr.Reason)
+	err = fs.MountDAX(ctx, sda, target, "xfs")
+	require.True(t, errors.As(err, &daxErr), err)
+	assert.Equal(t, "the device does not support DAX", daxErr.Reason)
+
+	// The filesystem is mounted with DAX.
+	mounted := "30 22 259:0 / /mnt/pmem0 rw,relatime - xfs " + pmem + " rw,attr2,dax=always\n"
+	require.NoError(t, os.WriteFile(mountInfo, []byte(mountKindMountInfo+mounted), 0o600))
+	require.NoError(t, fs.MountDAX(ctx, pmem, target, "xfs", "noatime"))
+	out, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "noatime,dax=always")
+
+	// The kernel mounted the filesystem without DAX.
+	require.NoError(t, os.Remove(log))
+	mounted = "30 22 259:0 / /mnt/pmem0 rw,relatime - ext4 " + pmem + " rw\n"
+	require.NoError(t, os.WriteFile(mountInfo, []byte(mountKindMountInfo+mounted), 0o600))
+	err = fs.MountDAX(ctx, pmem, target, "ext4")
+	require.True(t, errors.As(err, &daxErr), err)
+	assert.Equal(t, "the kernel mounted the filesystem without DAX", daxErr.Reason)
+	out, err = os.ReadFile(log)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, target, strings.TrimSpace(lines[1]))
+}
@@ -0,0 +1,93 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// windowsDiskPathPrefix is the prefix of the device path of a Windows disk.
+const windowsDiskPathPrefix = `\\.\PhysicalDrive`
+
+// windowsDisk is a disk as reported by the Get-Disk PowerShell cmdlet.
+type windowsDisk struct {
+	Number       int
+	SerialNumber string
+	UniqueID     string `json:"UniqueId"`
+	IsOffline    bool
+}
+
+// parseWindowsDisks parses the output of Get-Disk piped to ConvertTo-Json,
+// which is a single object rather than an array when there is one disk.
+func parseWindowsDisks(data []byte) ([]windowsDisk, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var disks []windowsDisk
+	if data[0] != '[' {
+		var disk windowsDisk
+		if err := json.Unmarshal(data, &disk); err != nil {
+			return nil, fmt.Errorf("parseWindowsDisks: %v", err)
+		}
+		return append(disks, disk), nil
+	}
+	if err := json.Unmarshal(data, &disks); err != nil {
+		return nil, fmt.Errorf("parseWindowsDisks: %v", err)
+	}
+	return disks, nil
+}
+
+// findWindowsDiskByWWN returns the disk whose UniqueId is the volume WWN,
+// or the NGUID of the volume for NVMe disks, or whose serial number is
+// the WWN.
+func findWindowsDiskByWWN(disks []windowsDisk, wwn string) (windowsDisk, bool) {
+	want, err := NormalizeWWN(wwn)
+	if err != nil {
+		want = CanonicalWWN(strings.ToLower(strings.TrimSpace(wwn)))
+	}
+	nguid, _ := WWNToNGUID(string(want))
+	for _, d := range disks {
+		if id, err := NormalizeWWN(d.UniqueID); err == nil {
+			if id == want || (nguid != "" && string(id) == nguid) {
+				return d, true
+			}
+		}
+		if strings.EqualFold(strings.TrimSpace(d.SerialNumber), string(want)) {
+			return d, true
+		}
+	}
+	return windowsDisk{}, false
+}
+
+// windowsDiskPath returns the device path of the disk with the given number.
+func windowsDiskPath(number int) string {
+	return windowsDiskPathPrefix + strconv.Itoa(number)
+}
+
+// parseWindowsDiskPath returns the disk number of a device path returned
+// by windowsDiskPath.
+func parseWindowsDiskPath(path string) (int, error) {
+	if !strings.HasPrefix(path, windowsDiskPathPrefix) {
+		return 0, fmt.Errorf("invalid disk path: %s", path)
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(path, windowsDiskPathPrefix))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid disk path: %s", path)
+	}
+	return n, nil
+}
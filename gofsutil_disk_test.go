@@ -0,0 +1,82 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWindowsDisks(t *testing.T) {
+	disks, err := parseWindowsDisks([]byte(`[
+  {"Number": 0, "SerialNumber": "S4EVNF0M", "UniqueId": "{1}\\\\SERVER\\\\SCSI\\\\DISK", "IsOffline": false},
+  {"Number": 2, "SerialNumber": "", "UniqueId": "60000970000120001263533030313434", "IsOffline": true}
+]`))
+	require.NoError(t, err)
+	require.Len(t, disks, 2)
+	assert.Equal(t, 2, disks[1].Number)
+	assert.True(t, disks[1].IsOffline)
+
+	disks, err = parseWindowsDisks([]byte(`{"Number": 1, "UniqueId": "68ccf098001111a2222b3d4444a1b23c"}`))
+	require.NoError(t, err)
+	require.Len(t, disks, 1)
+	assert.Equal(t, "68ccf098001111a2222b3d4444a1b23c", disks[0].UniqueID)
+
+	disks, err = parseWindowsDisks([]byte("\r\n"))
+	assert.NoError(t, err)
+	assert.Empty(t, disks)
+
+	_, err = parseWindowsDisks([]byte("Get-Disk : Access denied"))
+	assert.Error(t, err)
+}
+
+func TestFindWindowsDiskByWWN(t *testing.T) {
+	disks := []windowsDisk{
+		{Number: 0, SerialNumber: "S4EVNF0M", UniqueID: "{1}\\SERVER\\SCSI\\DISK"},
+		{Number: 1, UniqueID: "60000970000120001263533030313434"},
+		{Number: 2, UniqueID: "eui.1111a2222b3d44448ccf096800a1b23c"},
+		{Number: 3, SerialNumber: "68ccf098009999a2222b3d4444a1b23c"},
+	}
+	tests := []struct {
+		wwn    string
+		number int
+		found  bool
+	}{
+		{"naa.60000970000120001263533030313434", 1, true},
+		{"60000970000120001263533030313434", 1, true},
+		{"68ccf098001111a2222b3d4444a1b23c", 2, true},
+		{"68CCF098009999A2222B3D4444A1B23C", 3, true},
+		{"s4evnf0m", 0, true},
+		{"60000970000120001263533030319999", 0, false},
+	}
+	for _, tt := range tests {
+		disk, ok := findWindowsDiskByWWN(disks, tt.wwn)
+		assert.Equal(t, tt.found, ok, tt.wwn)
+		assert.Equal(t, tt.number, disk.Number, tt.wwn)
+	}
+}
+
+func TestWindowsDiskPath(t *testing.T) {
+	path := windowsDiskPath(12)
+	assert.Equal(t, `\\.\PhysicalDrive12`, path)
+	n, err := parseWindowsDiskPath(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, n)
+
+	for _, p := range []string{`/dev/sda`, `\\.\PhysicalDrive`, `\\.\PhysicalDrive-1`} {
+		_, err := parseWindowsDiskPath(p)
+		assert.Error(t, err, p)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// getDisksScript lists the disks as a JSON array, even when there is
+// only one.
+const getDisksScript = "ConvertTo-Json -InputObject @(Get-Disk | " +
+	"Select-Object Number,SerialNumber,UniqueId,IsOffline)"
+
+// powershell runs a PowerShell script and returns its standard output.
+func (fs *FS) powershell(ctx context.Context, script string) ([]byte, error) {
+	var stderr bytes.Buffer
+	/* #nosec G204 */
+	cmd := fs.commandContext(ctx, "powershell.exe",
+		"-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return out, fmt.Errorf("powershell %q failed: %v: %s",
+			script, err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// getDisks returns the disks known to the storage cache.
+func (fs *FS) getDisks(ctx context.Context) ([]windowsDisk, error) {
+	out, err := fs.powershell(ctx, getDisksScript)
+	if err != nil {
+		return nil, err
+	}
+	return parseWindowsDisks(out)
+}
+
+// wwnToDevicePath looks up a volume WWN in the disks reported by Get-Disk
+// and returns a) the UniqueId of the disk and b) its device path, e.g.
+// \\.\PhysicalDrive2.
+func (fs *FS) wwnToDevicePath(ctx context.Context, wwn string) (string, string, error) {
+	disks, err := fs.getDisks(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	disk, ok := findWindowsDiskByWWN(disks, wwn)
+	if !ok {
+		log.Printf("Check for disk with WWN %s not found", wwn)
+		return "", "", fmt.Errorf("disk with WWN %s not found", wwn)
+	}
+	devPath := windowsDiskPath(disk.Number)
+	log.Printf("Check for disk with WWN %s found: %s", wwn, devPath)
+	return disk.UniqueID, devPath, nil
+}
+
+// getSysBlockDevicesForVolumeWWN returns the device paths of the disks
+// with the given WWN.
+func (fs *FS) getSysBlockDevicesForVolumeWWN(ctx context.Context, volumeWWN string) ([]string, error) {
+	disks, err := fs.getDisks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, 0)
+	for _, d := range disks {
+		if _, ok := findWindowsDiskByWWN([]windowsDisk{d}, volumeWWN); ok {
+			result = append(result, windowsDiskPath(d.Number))
+		}
+	}
+	return result, nil
+}
+
+// rescanSCSIHost updates the storage cache. Windows rescans all the
+// adapters, so targets and lun are ignored.
+func (fs *FS) rescanSCSIHost(ctx context.Context, _ []string, _ string) error {
+	_, err := fs.powershell(ctx, "Update-HostStorageCache")
+	return err
+}
+
+// rescanSCSIHostX performs the same rescan as rescanSCSIHost and reports
+// the device paths of the disks that appeared.
+func (fs *FS) rescanSCSIHostX(ctx context.Context, targets []string, lun string) (*RescanReport, error) {
+	before, err := fs.getDisks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := fs.rescanSCSIHost(ctx, targets, lun); err != nil {
+		return nil, err
+	}
+	after, err := fs.getDisks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[int]bool, len(before))
+	for _, d := range before {
+		known[d.Number] = true
+	}
+	report := &RescanReport{ScanStrings: map[string][]string{}}
+	for _, d := range after {
+		if !known[d.Number] {
+			report.NewDevices = append(report.NewDevices, windowsDiskPath(d.Number))
+		}
+	}
+	return report, nil
+}
+
+// removeBlockDevice takes the disk with the given device path offline.
+func (fs *FS) removeBlockDevice(ctx context.Context, blockDevicePath string) error {
+	number, err := parseWindowsDiskPath(blockDevicePath)
+	if err != nil {
+		return err
+	}
+	_, err = fs.powershell(ctx, fmt.Sprintf("Set-Disk -Number %d -IsOffline $true", number))
+	return err
+}
+
+// getFCHostPortWWNs returns the port WWNs of the Fibre Channel initiator
+// ports.
+func (fs *FS) getFCHostPortWWNs(ctx context.Context) ([]string, error) {
+	out, err := fs.powershell(ctx, "Get-InitiatorPort | "+
+		"Where-Object ConnectionType -eq 'Fibre Channel' | "+
+		"ForEach-Object { $_.PortAddress }")
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, 0)
+	for _, line := range strings.Fields(string(out)) {
+		result = append(result, "0x"+strings.ToLower(line))
+	}
+	return result, nil
+}
+
+// issueLIPToAllFCHosts updates the storage cache, which rediscovers the
+// Fibre Channel targets.
+func (fs *FS) issueLIPToAllFCHosts(ctx context.Context) error {
+	return fs.rescanSCSIHost(ctx, nil, "")
+}
@@ -0,0 +1,228 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Device mapper target types.
+const (
+	DMTargetMultipath = "multipath"
+	DMTargetLinear    = "linear"
+	DMTargetStriped   = "striped"
+	DMTargetCrypt     = "crypt"
+)
+
+// DMTarget is one line of a device mapper table as reported by
+// "dmsetup table", e.g.
+//
+//	0 2097152 multipath 1 queue_if_no_path 1 alua 2 1 service-time 0 1 2 8:16 1 1
+type DMTarget struct {
+	// Start is the first sector of the target.
+	Start uint64
+	// Length is the number of sectors of the target.
+	Length uint64
+	// Type is the target type, e.g. multipath, linear or crypt.
+	Type string
+	// Params are the target specific parameters.
+	Params string
+}
+
+// DMTable is the table of a device mapper device.
+type DMTable struct {
+	// Name is the device mapper name, e.g. mpatha.
+	Name string
+	// Targets are the lines of the table.
+	Targets []DMTarget
+}
+
+// IsType returns true if all the targets of the table are of targetType.
+func (t *DMTable) IsType(targetType string) bool {
+	if len(t.Targets) == 0 {
+		return false
+	}
+	for _, target := range t.Targets {
+		if target.Type != targetType {
+			return false
+		}
+	}
+	return true
+}
+
+// DMDevice are the names of a device mapper device.
+type DMDevice struct {
+	// Name is the device mapper name, e.g. mpathb.
+	Name string
+	// DevPath is the device node of the kernel name, e.g. /dev/dm-3.
+	DevPath string
+	// MapperPath is the /dev/mapper path of the name, e.g.
+	// /dev/mapper/mpathb.
+	MapperPath string
+	// UUID is the device mapper UUID, e.g.
+	// mpath-360000970000120000549533030354435, empty if the device has
+	// none.
+	UUID string
+}
+
+// dmName returns the device mapper name of name, which may be given
+// as either the plain name or the /dev/mapper path.
+func dmName(name string) (string, error) {
+	if strings.HasPrefix(name, "/dev/mapper/") {
+		name = filepath.Base(name)
+	}
+	if name == "" || strings.ContainsAny(name, "/ \t\n") {
+		return "", fmt.Errorf("device mapper name: %q is invalid", name)
+	}
+	return name, nil
+}
+
+// parseDMTable parses the output of "dmsetup table <name>".
+func parseDMTable(name, table string) (*DMTable, error) {
+	result := &DMTable{Name: name, Targets: make([]DMTarget, 0)}
+	scan := bufio.NewScanner(strings.NewReader(table))
+	for scan.Scan() {
+		line := strings.TrimSpace(scan.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 4)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("invalid device mapper table line for %s: %s", name, line)
+		}
+		start, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid device mapper table start for %s: %s", name, line)
+		}
+		length, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid device mapper table length for %s: %s", name, line)
+		}
+		target := DMTarget{Start: start, Length: length, Type: fields[2]}
+		if len(fields) == 4 {
+			target.Params = fields[3]
+		}
+		result.Targets = append(result.Targets, target)
+	}
+	return result, scan.Err()
+}
+
+// parseMultipathActivePaths returns the number of paths the multipath
+// targets of "dmsetup status <name>" report as active (A), e.g.
+//
+//	0 2097152 multipath 2 0 0 0 2 1 A 0 1 2 8:16 A 0 0 1 E 0 1 2 8:32 F 1 0 1
+//
+// Failed paths (F) are not counted.
+func parseMultipathActivePaths(name, status string) (int, error) {
+	active := 0
+	scan := bufio.NewScanner(strings.NewReader(status))
+	for scan.Scan() {
+		fields := strings.Fields(scan.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 3 || fields[2] != DMTargetMultipath {
+			return 0, fmt.Errorf("%s is not a multipath device: %s", name, scan.Text())
+		}
+		n, err := parseMultipathPathStates(fields[3:])
+		if err != nil {
+			return 0, fmt.Errorf("invalid multipath status of %s: %v: %s", name, err, scan.Text())
+		}
+		active += n
+	}
+	return active, scan.Err()
+}
+
+// parseMultipathPathStates returns the number of active paths of the
+// status of a multipath target: the features, the hardware handler
+// status, the number of path groups and the next group, then for each
+// group its state, its selector status, its number of paths and of
+// selector arguments per path, and for each path its device, A or F,
+// its fail count and the selector arguments.
+func parseMultipathPathStates(fields []string) (int, error) {
+	next := func() (int, error) {
+		if len(fields) == 0 {
+			return 0, errors.New("status is truncated")
+		}
+		n, err := strconv.Atoi(fields[0])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%q is not a count", fields[0])
+		}
+		fields = fields[1:]
+		return n, nil
+	}
+	skip := func(n int) error {
+		if n > len(fields) {
+			return errors.New("status is truncated")
+		}
+		fields = fields[n:]
+		return nil
+	}
+	// The features and the hardware handler status.
+	for i := 0; i < 2; i++ {
+		n, err := next()
+		if err != nil {
+			return 0, err
+		}
+		if err := skip(n); err != nil {
+			return 0, err
+		}
+	}
+	groups, err := next()
+	if err != nil {
+		return 0, err
+	}
+	// The next path group.
+	if err := skip(1); err != nil {
+		return 0, err
+	}
+	active := 0
+	for g := 0; g < groups; g++ {
+		// The state of the group.
+		if err := skip(1); err != nil {
+			return 0, err
+		}
+		n, err := next()
+		if err != nil {
+			return 0, err
+		}
+		if err := skip(n); err != nil {
+			return 0, err
+		}
+		paths, err := next()
+		if err != nil {
+			return 0, err
+		}
+		args, err := next()
+		if err != nil {
+			return 0, err
+		}
+		for p := 0; p < paths; p++ {
+			if len(fields) < 3 {
+				return 0, errors.New("status is truncated")
+			}
+			if fields[1] == "A" {
+				active++
+			}
+			if err := skip(3 + args); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return active, nil
+}
@@ -0,0 +1,117 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDMTable(t *testing.T) {
+	tests := []struct {
+		name      string
+		table     string
+		expect    []DMTarget
+		isType    string
+		errString string
+	}{
+		{
+			name:  "multipath",
+			table: "0 2097152 multipath 1 queue_if_no_path 1 alua 2 1 service-time 0 1 2 8:16 1 1\n",
+			expect: []DMTarget{
+				{Start: 0, Length: 2097152, Type: DMTargetMultipath, Params: "1 queue_if_no_path 1 alua 2 1 service-time 0 1 2 8:16 1 1"},
+			},
+			isType: DMTargetMultipath,
+		},
+		{
+			name:  "linear with two segments",
+			table: "0 1024 linear 8:16 2048\n1024 1024 linear 8:32 2048\n",
+			expect: []DMTarget{
+				{Start: 0, Length: 1024, Type: DMTargetLinear, Params: "8:16 2048"},
+				{Start: 1024, Length: 1024, Type: DMTargetLinear, Params: "8:32 2048"},
+			},
+			isType: DMTargetLinear,
+		},
+		{
+			name:   "empty table",
+			table:  "\n",
+			expect: []DMTarget{},
+		},
+		{
+			name:      "invalid start",
+			table:     "x 1024 linear 8:16 2048\n",
+			errString: "invalid device mapper table start for dm: x 1024 linear 8:16 2048",
+		},
+		{
+			name:      "short line",
+			table:     "0 1024\n",
+			errString: "invalid device mapper table line for dm: 0 1024",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := parseDMTable("dm", tt.table)
+			if tt.errString != "" {
+				assert.EqualError(t, err, tt.errString)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, table.Targets)
+			if tt.isType != "" {
+				assert.True(t, table.IsType(tt.isType))
+				assert.False(t, table.IsType(DMTargetCrypt))
+			}
+		})
+	}
+}
+
+func TestDMName(t *testing.T) {
+	name, err := dmName("/dev/mapper/mpatha")
+	assert.NoError(t, err)
+	assert.Equal(t, "mpatha", name)
+
+	name, err = dmName("3600601601234")
+	assert.NoError(t, err)
+	assert.Equal(t, "3600601601234", name)
+
+	_, err = dmName("mpatha; rm -rf /")
+	assert.Error(t, err)
+}
+
+func TestParseMultipathActivePaths(t *testing.T) {
+	tests := []struct {
+		status    string
+		active    int
+		errString string
+	}{
+		// round-robin has no selector arguments per path.
+		{status: "0 2097152 multipath 2 0 0 0 1 1 A 0 2 0 8:16 A 0 8:32 A 0\n", active: 2},
+		// queue_if_no_path with all the paths failed.
+		{status: "0 2097152 multipath 3 queue_if_no_path 0 0 1 alua 1 1 E 0 1 2 8:16 F 5 0 1\n", active: 0},
+		{status: "0 2097152 multipath 2 0 0 0 1 1 A 0 2 2 8:16 A 0 0 1\n", errString: "truncated"},
+		{status: "0 2097152 multipath 2 0 0 0 x\n", errString: "not a count"},
+		{status: "0 2097152 linear\n", errString: "not a multipath device"},
+	}
+	for _, tt := range tests {
+		active, err := parseMultipathActivePaths("mpatha", tt.status)
+		if tt.errString != "" {
+			assert.ErrorContains(t, err, tt.errString, tt.status)
+			continue
+		}
+		require.NoError(t, err, tt.status)
+		assert.Equal(t, tt.active, active, tt.status)
+	}
+}
@@ -0,0 +1,131 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const dmsetupCmd = "dmsetup"
+
+// dmsetup runs dmsetup with the given arguments and returns its output.
+func (fs *FS) dmsetup(ctx context.Context, args ...string) ([]byte, error) {
+	log.Printf("%s %v", dmsetupCmd, args)
+	/* #nosec G204 */
+	out, err := fs.commandContext(ctx, dmsetupCmd, args...).CombinedOutput()
+	if err != nil {
+		return out, fmt.Errorf("dmsetup %v failed: %v: %s", args, err, string(out))
+	}
+	return out, nil
+}
+
+// dmSuspend suspends I/O to the device mapper device.
+func (fs *FS) dmSuspend(ctx context.Context, name string) error {
+	name, err := dmName(name)
+	if err != nil {
+		return err
+	}
+	if _, err := fs.dmsetup(ctx, "suspend", name); err != nil {
+		log.WithField("name", name).WithError(err).Error("Failed to suspend device mapper device")
+		return err
+	}
+	log.Infof("Device mapper device %s suspended", name)
+	return nil
+}
+
+// dmResume resumes I/O to the device mapper device.
+func (fs *FS) dmResume(ctx context.Context, name string) error {
+	name, err := dmName(name)
+	if err != nil {
+		return err
+	}
+	if _, err := fs.dmsetup(ctx, "resume", name); err != nil {
+		log.WithField("name", name).WithError(err).Error("Failed to resume device mapper device")
+		return err
+	}
+	log.Infof("Device mapper device %s resumed", name)
+	return nil
+}
+
+// getDMTable returns the parsed table of the device mapper device.
+func (fs *FS) getDMTable(ctx context.Context, name string) (*DMTable, error) {
+	name, err := dmName(name)
+	if err != nil {
+		return nil, err
+	}
+	out, err := fs.dmsetup(ctx, "table", name)
+	if err != nil {
+		return nil, err
+	}
+	return parseDMTable(name, string(out))
+}
+
+// dmNameToDevPath returns the device mapper device with the given name,
+// found by reading the dm/name attribute of the dm-N block devices.
+func (fs *FS) dmNameToDevPath(_ context.Context, name string) (*DMDevice, error) {
+	name, err := dmName(name)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(fs.sysBlockDir())
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "dm-") {
+			continue
+		}
+		if readSysfsAttr(filepath.Join(fs.sysBlockDir(), entry.Name(), "dm", "name")) == name {
+			return fs.dmDevice(entry.Name())
+		}
+	}
+	return nil, fmt.Errorf("device mapper device %s: %w", name, os.ErrNotExist)
+}
+
+// devPathToDMName returns the device mapper device of devPath, which is
+// either a kernel device, e.g. /dev/dm-3 or dm-3, or a /dev/mapper path.
+func (fs *FS) devPathToDMName(ctx context.Context, devPath string) (*DMDevice, error) {
+	if strings.HasPrefix(devPath, "/dev/mapper/") {
+		return fs.dmNameToDevPath(ctx, devPath)
+	}
+	kernelName := filepath.Base(devPath)
+	if !strings.HasPrefix(kernelName, "dm-") {
+		return nil, fmt.Errorf("%s is not a device mapper device", devPath)
+	}
+	return fs.dmDevice(kernelName)
+}
+
+// dmDevice returns the device mapper device with the given kernel name,
+// e.g. dm-3.
+func (fs *FS) dmDevice(kernelName string) (*DMDevice, error) {
+	dir := filepath.Join(fs.sysBlockDir(), kernelName, "dm")
+	name := readSysfsAttr(filepath.Join(dir, "name"))
+	if name == "" {
+		return nil, fmt.Errorf("device mapper device %s: %w", kernelName, os.ErrNotExist)
+	}
+	return &DMDevice{
+		Name:       name,
+		DevPath:    fs.devPath("/dev/" + kernelName),
+		MapperPath: fs.devPath("/dev/mapper/" + name),
+		UUID:       readSysfsAttr(filepath.Join(dir, "uuid")),
+	}, nil
+}
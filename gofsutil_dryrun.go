@@ -0,0 +1,168 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	// DryRunExec is the operation of a command that would have run.
+	DryRunExec = "exec"
+	// DryRunWrite is the operation of a write to a file, e.g. a sysfs
+	// attribute.
+	DryRunWrite = "write"
+	// DryRunUnmount is the operation of an unmount with the umount2
+	// system call.
+	DryRunUnmount = "umount"
+	// DryRunIoctl is the operation of an ioctl changing a device or file,
+	// e.g. BLKROSET.
+	DryRunIoctl = "ioctl"
+	// DryRunChown is the operation of a change of the owner of a file.
+	DryRunChown = "chown"
+	// DryRunChmod is the operation of a change of the mode of a file.
+	DryRunChmod = "chmod"
+	// DryRunMkdir is the operation of the creation of a directory, with
+	// its mode.
+	DryRunMkdir = "mkdir"
+	// DryRunCreate is the operation of the creation of a file, with its
+	// mode.
+	DryRunCreate = "create"
+	// DryRunRemove is the operation of the removal of a file or an empty
+	// directory.
+	DryRunRemove = "remove"
+)
+
+// DryRunAction is an action that a mutating operation would have taken
+// with FSOptions.DryRun.
+type DryRunAction struct {
+	// Op is the kind of action, e.g. DryRunExec or DryRunWrite.
+	Op string
+	// Command is the command that would have run, as it would have been
+	// run, e.g. mount or nsenter.
+	Command string
+	// Args are the arguments of the command, with the values of secret
+	// mount options replaced.
+	Args []string
+	// Path is the file that would have been changed, e.g. a sysfs
+	// attribute or a mount point.
+	Path string
+	// Data is what would have been written to Path, e.g. 1, or the
+	// ioctl, owner or mode that would have been set.
+	Data string
+}
+
+// String returns the action as a command line or, for actions that are
+// not commands, as the operation, its path and data.
+func (a DryRunAction) String() string {
+	if a.Op == DryRunExec {
+		return strings.Join(append([]string{a.Command}, a.Args...), " ")
+	}
+	s := a.Op + " " + a.Path
+	if a.Data != "" {
+		s += " " + a.Data
+	}
+	return s
+}
+
+// dryRunLog holds the actions recorded with DryRun.
+type dryRunLog struct {
+	mu      sync.Mutex
+	actions []DryRunAction
+}
+
+func (l *dryRunLog) add(a DryRunAction) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.actions = append(l.actions, a)
+}
+
+func (l *dryRunLog) list() []DryRunAction {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]DryRunAction{}, l.actions...)
+}
+
+func (l *dryRunLog) clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.actions = nil
+}
+
+// dryRun records the action and returns true if DryRun is set, in which
+// case the caller must not take the action.
+func (fs *FS) dryRun(op, path, data string) bool {
+	if !fs.DryRun {
+		return false
+	}
+	fs.dryRunActions.add(DryRunAction{Op: op, Path: path, Data: data})
+	return true
+}
+
+// mutatingCommand returns true if running name with args changes the
+// state of the host, so that it is not run with DryRun. Commands that are
+// not known to only report state are assumed to change it.
+func mutatingCommand(name string, args []string) bool {
+	first := ""
+	if len(args) > 0 {
+		first = args[0]
+	}
+	switch filepath.Base(name) {
+	case "chroot":
+		// An explicit chroot, e.g. of MultipathCommand.
+		return len(args) < 2 || mutatingCommand(args[1], args[2:])
+	case "lsblk", "findmnt", "blkid", "dumpe2fs", "xfs_info", "showmount",
+		"repquota", "pp_inq":
+		return false
+	case "udevadm":
+		return first == "trigger"
+	case "sfdisk":
+		return !stringInSlice("--json", args) && !stringInSlice("-J", args)
+	case "multipath":
+		for _, arg := range args {
+			if arg == "-l" || arg == "-ll" || arg == "-c" || arg == "-C" || arg == "-t" || arg == "-T" {
+				return false
+			}
+		}
+		return true
+	case "multipathd":
+		return first != "show" && first != "list"
+	case "losetup":
+		for _, arg := range args {
+			if stringInSlice(arg, []string{"-j", "--associated", "-l", "--list", "-a", "--all"}) {
+				return false
+			}
+		}
+		return len(args) > 0
+	case "nvme":
+		return !stringInSlice(first, []string{"list", "list-subsys", "id-ctrl", "id-ns", "show-hostnqn", "version"})
+	case "dmsetup":
+		return !stringInSlice(first, []string{"table", "info", "status", "ls", "deps"})
+	case "xfs_quota":
+		for _, arg := range args {
+			if strings.HasPrefix(arg, "report") || strings.HasPrefix(arg, "print") || strings.HasPrefix(arg, "state") {
+				return false
+			}
+		}
+		return true
+	case "e2fsck":
+		return !stringInSlice("-n", args)
+	case "tune2fs":
+		return len(args) != 2 || first != "-l"
+	case "resize2fs":
+		return first != "-P"
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutatingCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		mutating bool
+	}{
+		{"mount", []string{"-t", "xfs", "/dev/sdb", "/mnt"}, true},
+		{"mkfs.ext4", []string{"-F", "/dev/sdb"}, true},
+		{"lsblk", []string{"-n", "-o", "FSTYPE", "/dev/sdb"}, false},
+		{"/usr/bin/findmnt", []string{"-n", "/mnt"}, false},
+		{"multipath", []string{"-ll"}, false},
+		{"multipath", []string{"-f", "mpatha"}, true},
+		{"/usr/sbin/chroot", []string{"/noderoot", "multipath", "-ll"}, false},
+		{"/usr/sbin/chroot", []string{"/noderoot", "multipath", "-f", "mpatha"}, true},
+		{"multipathd", []string{"show", "status"}, false},
+		{"multipathd", []string{"resize", "map", "mpatha"}, true},
+		{"losetup", []string{"-j", "/var/a.img"}, false},
+		{"losetup", []string{"-f", "--show", "/var/a.img"}, true},
+		{"nvme", []string{"list-subsys"}, false},
+		{"nvme", []string{"connect", "-t", "tcp"}, true},
+		{"dmsetup", []string{"table", "mpatha"}, false},
+		{"dmsetup", []string{"suspend", "mpatha"}, true},
+		{"sfdisk", []string{"--json", "/dev/sdb"}, false},
+		{"xfs_quota", []string{"-x", "-c", "report -p -n -N -b", "/mnt"}, false},
+		{"xfs_quota", []string{"-x", "-c", "limit -p bhard=1g 7", "/mnt"}, true},
+		{"udevadm", []string{"settle"}, false},
+		{"tune2fs", []string{"-l", "/dev/sdb"}, false},
+		{"tune2fs", []string{"-U", "random", "/dev/sdb"}, true},
+		{"resize2fs", []string{"-P", "/dev/sdb"}, false},
+		{"resize2fs", []string{"/dev/sdb", "8192"}, true},
+		{"gofsutil-unknown", nil, true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.mutating, mutatingCommand(tt.name, tt.args), "%s %v", tt.name, tt.args)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	bin := t.TempDir()
+	log := filepath.Join(t.TempDir(), "log")
+	// lsblk reports no filesystem, the other commands must not run.
+	writeRecorder(t, filepath.Join(bin, "lsblk"), log)
+	for _, name := range []string{"mount", "mkfs.ext4", "umount"} {
+		writeRecorder(t, filepath.Join(bin, name), log)
+	}
+	sys := filepath.Join(t.TempDir(), "sys")
+	rescan := filepath.Join(sys, "block", "sdz", "device", "rescan")
+	require.NoError(t, os.MkdirAll(filepath.Dir(rescan), 0o755))
+	require.NoError(t, os.WriteFile(rescan, nil, 0o600))
+	target := t.TempDir()
+	fs := NewFS(FSOptions{DryRun: true, SysRoot: sys, ExtraEnv: []string{"PATH=" + bin}})
+
+	require.NoError(t, fs.formatAndMount(ctx, "/dev/sdz", target, "ext4"))
+	actions := fs.GetDryRunActions()
+	require.Len(t, actions, 2)
+	assert.Equal(t, DryRunAction{
+		Op:      DryRunExec,
+		Command: filepath.Join(bin, "mkfs.ext4"),
+		Args:    []string{"-F", "/dev/sdz"},
+	}, actions[0])
+	assert.Equal(t, DryRunExec, actions[1].Op)
+	assert.Equal(t, filepath.Join(bin, "mount"), actions[1].Command)
+	assert.Contains(t, actions[1].Args, target)
+	assert.Equal(t, filepath.Join(bin, "mkfs.ext4")+" -F /dev/sdz", actions[0].String())
+
+	fs.ClearDryRunActions()
+	assert.Empty(t, fs.GetDryRunActions())
+
+	require.NoError(t, fs.unmount(ctx, target))
+	require.NoError(t, fs.deviceRescan(ctx, "/sys/block/sdz"))
+	assert.Equal(t, []DryRunAction{
+		{Op: DryRunUnmount, Path: target},
+		{Op: DryRunWrite, Path: rescan, Data: "1"},
+	}, fs.GetDryRunActions())
+	assert.Equal(t, "write "+rescan+" 1", fs.GetDryRunActions()[1].String())
+
+	// Only lsblk ran, twice while planning the format.
+	out, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat(" -n -o FSTYPE /dev/sdz\n", 2), string(out))
+	buf, err := os.ReadFile(rescan)
+	require.NoError(t, err)
+	assert.Empty(t, buf)
+}
+
+func TestDryRunFiles(t *testing.T) {
+	ctx := context.Background()
+	bin := t.TempDir()
+	log := filepath.Join(t.TempDir(), "log")
+	writeRecorder(t, filepath.Join(bin, "mount"), log)
+	tmp := t.TempDir()
+	etc := filepath.Join(tmp, "etc")
+	fs := NewFS(FSOptions{DryRun: true, EtcRoot: etc, ExtraEnv: []string{"PATH=" + bin}})
+
+	target := filepath.Join(tmp, "staging", "vol")
+	require.NoError(t, fs.MountWithOptions(ctx, "/dev/sdz", target, "ext4", MountOptions{
+		CreateTarget: &CreateTargetOptions{Mode: 0o750, Owner: &TargetOwner{UID: 1000, GID: 1000}},
+	}))
+	actions := fs.GetDryRunActions()
+	require.Len(t, actions, 5)
+	assert.Equal(t, []DryRunAction{
+		{Op: DryRunMkdir, Path: filepath.Dir(target), Data: "-rwxr-x---"},
+		{Op: DryRunChown, Path: filepath.Dir(target), Data: "1000:1000"},
+		{Op: DryRunMkdir, Path: target, Data: "-rwxr-x---"},
+		{Op: DryRunChown, Path: target, Data: "1000:1000"},
+	}, actions[:4])
+	assert.Equal(t, filepath.Join(bin, "mount"), actions[4].Command)
+	assert.NoDirExists(t, filepath.Dir(target))
+
+	fs.ClearDryRunActions()
+	blockFile := filepath.Join(tmp, "publish", "vol")
+	require.NoError(t, fs.BindMountBlockDevice(ctx, "/dev/null", blockFile))
+	actions = fs.GetDryRunActions()
+	// The bind mount is followed by its remount.
+	require.Len(t, actions, 4)
+	assert.Equal(t, []DryRunAction{
+		{Op: DryRunMkdir, Path: filepath.Dir(blockFile), Data: "-rwxr-x---"},
+		{Op: DryRunCreate, Path: blockFile, Data: "-rw-------"},
+	}, actions[:2])
+	assert.Equal(t, filepath.Join(bin, "mount"), actions[2].Command)
+	assert.Equal(t, filepath.Join(bin, "mount"), actions[3].Command)
+	assert.NoFileExists(t, blockFile)
+
+	fs.ClearDryRunActions()
+	blockFile = filepath.Join(tmp, "block")
+	require.NoError(t, os.WriteFile(blockFile, nil, 0o600))
+	require.NoError(t, fs.UnmountBlockDevice(ctx, blockFile))
+	assert.Equal(t, []DryRunAction{{Op: DryRunRemove, Path: blockFile}}, fs.GetDryRunActions())
+	assert.FileExists(t, blockFile)
+
+	fs.ClearDryRunActions()
+	nqn, err := fs.GetHostNQN(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, []DryRunAction{
+		{Op: DryRunWrite, Path: filepath.Join(etc, "nvme", "hostnqn"), Data: nqn + "\n"},
+	}, fs.GetDryRunActions())
+	assert.NoDirExists(t, etc)
+
+	_, err = os.Stat(log)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDryRunUnmountTree(t *testing.T) {
+	procRoot := t.TempDir()
+	mountinfo := "21 1 8:1 / / rw,relatime - ext4 /dev/sda1 rw\n" +
+		"3002 21 8:16 / /mnt/data rw,relatime - xfs /dev/sdb rw\n" +
+		"3003 3002 0:52 / /mnt/data/secret rw,relatime - tmpfs tmpfs rw\n" +
+		"3004 3002 0:53 / /mnt/data/proc rw,relatime - proc proc rw\n"
+	require.NoError(t, os.MkdirAll(filepath.Join(procRoot, "self"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(procRoot, "self", "mountinfo"), []byte(mountinfo), 0o600))
+	fs := NewFS(FSOptions{ProcRoot: procRoot, DryRun: true})
+
+	// The tmpfs and proc mounts that GetMounts omits are unmounted first.
+	results, err := fs.UnmountTree(context.Background(), "/mnt/data")
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, []DryRunAction{
+		{Op: DryRunUnmount, Path: "/mnt/data/proc"},
+		{Op: DryRunUnmount, Path: "/mnt/data/secret"},
+		{Op: DryRunUnmount, Path: "/mnt/data"},
+	}, fs.GetDryRunActions())
+}
@@ -0,0 +1,147 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+)
+
+// inodeKey identifies an inode.
+type inodeKey struct {
+	dev, ino uint64
+}
+
+// duWalker sums up the disk usage of a tree. Files with several hard
+// links are counted once, and other filesystems mounted in the tree are
+// not descended into.
+type duWalker struct {
+	dev uint64
+
+	mu    sync.Mutex
+	seen  map[inodeKey]struct{}
+	usage DiskUsageInfo
+}
+
+// add accounts for the file described by st.
+func (w *duWalker) add(st *syscall.Stat_t) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if st.Nlink > 1 {
+		key := inodeKey{dev: uint64(st.Dev), ino: st.Ino} // #nosec G115
+		if _, ok := w.seen[key]; ok {
+			return
+		}
+		w.seen[key] = struct{}{}
+	}
+	// Blocks is in 512 byte units regardless of the filesystem block size.
+	w.usage.Bytes += st.Blocks * 512
+	w.usage.Inodes++
+}
+
+// walk adds up the usage of the tree at root, root included.
+func (w *duWalker) walk(ctx context.Context, root string) error {
+	return filepath.WalkDir(root, func(path string, d iofs.DirEntry, err error) error {
+		if err != nil {
+			// Files may disappear while walking.
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		st, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			return fmt.Errorf("no stat information for %s", path)
+		}
+		if uint64(st.Dev) != w.dev { // #nosec G115
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		w.add(st)
+		return nil
+	})
+}
+
+// diskUsage computes the disk space and inodes used under path, like
+// du -x. With DiskUsageWorkers greater than one, the subdirectories of
+// path are walked in parallel.
+func (fs *FS) diskUsage(ctx context.Context, path string) (*DiskUsageInfo, error) {
+	path = filepath.Clean(path)
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil, fmt.Errorf("no stat information for %s", path)
+	}
+	w := &duWalker{dev: uint64(st.Dev), seen: make(map[inodeKey]struct{})} // #nosec G115
+
+	if fs.DiskUsageWorkers <= 1 || !info.IsDir() {
+		if err := w.walk(ctx, path); err != nil {
+			return nil, err
+		}
+		return &w.usage, nil
+	}
+
+	w.add(st)
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	sem := make(chan struct{}, fs.DiskUsageWorkers)
+	errs := make(chan error, len(entries))
+	var wg sync.WaitGroup
+	for _, entry := range entries {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+		wg.Add(1)
+		go func(p string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := w.walk(ctx, p); err != nil {
+				errs <- err
+			}
+		}(filepath.Join(path, entry.Name()))
+	}
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+	return &w.usage, nil
+}
@@ -0,0 +1,204 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// ErrorCode is a stable code classifying the errors of gofsutil, e.g. to
+// label failure metrics or to choose the gRPC code of a CSI response.
+// The values are part of the API and do not change.
+type ErrorCode string
+
+const (
+	// CodeOK is the code of a nil error.
+	CodeOK ErrorCode = "ok"
+	// CodeUnknown is the code of an error that could not be classified.
+	CodeUnknown ErrorCode = "unknown"
+	// CodeInvalidArgument is the code of an invalid argument, e.g. an
+	// invalid MountSpec or a mount target that is not a directory.
+	CodeInvalidArgument ErrorCode = "invalid_argument"
+	// CodeNotSupported is the code of an operation that is not supported
+	// on the platform or by the system.
+	CodeNotSupported ErrorCode = "not_supported"
+	// CodePermission is the code of an operation that is not permitted.
+	CodePermission ErrorCode = "permission_denied"
+	// CodeTimeout is the code of an operation that timed out.
+	CodeTimeout ErrorCode = "timeout"
+	// CodeCanceled is the code of an operation whose context was
+	// canceled.
+	CodeCanceled ErrorCode = "canceled"
+	// CodeDeviceNotFound is the code of a device, or another path, that
+	// does not exist.
+	CodeDeviceNotFound ErrorCode = "device_not_found"
+	// CodeDeviceInUse is the code of a device that is mounted, held, busy
+	// or holds a LUKS or LVM signature.
+	CodeDeviceInUse ErrorCode = "device_in_use"
+	// CodeUnavailable is the code of a service the operation depends on
+	// that is not running, e.g. the cluster stack of a cluster
+	// filesystem.
+	CodeUnavailable ErrorCode = "unavailable"
+	// CodeCommandFailed is the code of an external command that failed.
+	CodeCommandFailed ErrorCode = "command_failed"
+	// CodeMountFailed is the code of a mount that failed.
+	CodeMountFailed ErrorCode = "mount_failed"
+	// CodeUnmountFailed is the code of an unmount that failed.
+	CodeUnmountFailed ErrorCode = "unmount_failed"
+	// CodeFormatFailed is the code of a format that failed.
+	CodeFormatFailed ErrorCode = "format_failed"
+	// CodeResizeFailed is the code of a resize that failed.
+	CodeResizeFailed ErrorCode = "resize_failed"
+	// CodeRescanFailed is the code of a rescan that failed.
+	CodeRescanFailed ErrorCode = "rescan_failed"
+)
+
+// GRPCCode returns the number of the gRPC status code matching the error
+// code, e.g. 5 (NotFound) for CodeDeviceNotFound, to be converted with
+// codes.Code.
+func (c ErrorCode) GRPCCode() uint32 {
+	switch c {
+	case CodeOK:
+		return 0 // OK
+	case CodeCanceled:
+		return 1 // Canceled
+	case CodeInvalidArgument:
+		return 3 // InvalidArgument
+	case CodeTimeout:
+		return 4 // DeadlineExceeded
+	case CodeDeviceNotFound:
+		return 5 // NotFound
+	case CodePermission:
+		return 7 // PermissionDenied
+	case CodeDeviceInUse:
+		return 9 // FailedPrecondition
+	case CodeNotSupported:
+		return 12 // Unimplemented
+	case CodeCommandFailed, CodeMountFailed, CodeUnmountFailed,
+		CodeFormatFailed, CodeResizeFailed, CodeRescanFailed:
+		return 13 // Internal
+	case CodeUnavailable:
+		return 14 // Unavailable
+	default:
+		return 2 // Unknown
+	}
+}
+
+// OpError is the error of an operation on a target, e.g. a mount. It is
+// returned by Mount, Unmount, Format, FormatAndMount, ResizeFS,
+// ResizeMultipath, RescanSCSIHost and the other mounts of FS.
+type OpError struct {
+	// Code is the code of the error.
+	Code ErrorCode
+	// Op is the operation, e.g. mount.
+	Op string
+	// Target is the target of the operation, e.g. the mount point.
+	Target string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the message of Err followed by the operation and its
+// target, so that the message starts as it did before the operations
+// returned an OpError.
+func (e *OpError) Error() string {
+	if e.Target == "" {
+		return e.Err.Error() + " (" + e.Op + ")"
+	}
+	return e.Err.Error() + " (" + e.Op + " " + e.Target + ")"
+}
+
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode returns the code of the error.
+func (e *OpError) ErrorCode() ErrorCode {
+	return e.Code
+}
+
+// ErrorCodeOf returns the code of err: CodeOK if err is nil, the code of
+// the OpError it wraps, if any, or else a code classifying the errors it
+// wraps, CodeUnknown if none matches.
+func ErrorCodeOf(err error) ErrorCode {
+	if err == nil {
+		return CodeOK
+	}
+	var coded interface{ ErrorCode() ErrorCode }
+	if errors.As(err, &coded) {
+		return coded.ErrorCode()
+	}
+	return classifyError(err)
+}
+
+// classifyError returns the code of the errors err wraps, CodeUnknown if
+// none matches.
+func classifyError(err error) ErrorCode {
+	var (
+		inUse   *InUseError
+		notDir  *TargetNotDirectoryError
+		nvmeErr *NVMeCommandError
+		busyErr *DeviceBusyError
+		exitErr *exec.ExitError
+		timeout interface{ Timeout() bool }
+	)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeTimeout
+	case errors.Is(err, context.Canceled):
+		return CodeCanceled
+	case errors.As(err, &inUse), errors.As(err, &busyErr), errors.Is(err, syscall.EBUSY),
+		errors.Is(err, ErrForeignSignature):
+		return CodeDeviceInUse
+	case errors.Is(err, ErrInvalidMountSpec), errors.As(err, &notDir),
+		errors.Is(err, ErrTooManySymlinks), errors.Is(err, ErrOutsideRoot),
+		errors.Is(err, ErrInvalidNFSOptions), errors.Is(err, ErrInvalidFormatOptions),
+		errors.Is(err, ErrShrinkBelowMinimum):
+		return CodeInvalidArgument
+	case errors.Is(err, ErrNotImplemented), errors.Is(err, errors.ErrUnsupported),
+		errors.Is(err, ErrNFSOptionNotSupported), errors.Is(err, ErrNVMeMultipathConflict),
+		errors.Is(err, ErrFormatOptionNotSupported):
+		return CodeNotSupported
+	case errors.Is(err, ErrShellDisallowed), errors.Is(err, os.ErrPermission):
+		return CodePermission
+	case errors.Is(err, ErrClusterStackNotRunning):
+		return CodeUnavailable
+	case errors.Is(err, os.ErrNotExist):
+		return CodeDeviceNotFound
+	case errors.Is(err, ErrDeviceUnreadable):
+		return CodeUnavailable
+	case errors.As(err, &timeout) && timeout.Timeout():
+		return CodeTimeout
+	case errors.As(err, &nvmeErr), errors.As(err, &exitErr):
+		return CodeCommandFailed
+	}
+	return CodeUnknown
+}
+
+// wrapOpError replaces *err, if not nil and not already an OpError, with
+// an OpError of op on target. Its code classifies the wrapped errors, and
+// is code if none matches.
+func wrapOpError(err *error, op, target string, code ErrorCode) {
+	var opErr *OpError
+	if *err == nil || errors.As(*err, &opErr) {
+		return
+	}
+	if c := classifyError(*err); c != CodeUnknown {
+		code = c
+	}
+	*err = &OpError{Code: code, Op: op, Target: target, Err: *err}
+}
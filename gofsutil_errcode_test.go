@@ -0,0 +1,90 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCodeOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want ErrorCode
+	}{
+		{nil, CodeOK},
+		{errors.New("boom"), CodeUnknown},
+		{fmt.Errorf("waiting: %w", context.DeadlineExceeded), CodeTimeout},
+		{context.Canceled, CodeCanceled},
+		{&InUseError{Device: "/dev/sdb", Reason: DeviceHeld}, CodeDeviceInUse},
+		{&os.PathError{Op: "open", Path: "/dev/sdb", Err: syscall.EBUSY}, CodeDeviceInUse},
+		{fmt.Errorf("%w: no target", ErrInvalidMountSpec), CodeInvalidArgument},
+		{&TargetNotDirectoryError{Target: "/mnt"}, CodeInvalidArgument},
+		{ErrNotImplemented, CodeNotSupported},
+		{syscall.EOPNOTSUPP, CodeNotSupported},
+		{ErrShellDisallowed, CodePermission},
+		{&os.PathError{Op: "open", Path: "/dev/sdb", Err: syscall.EACCES}, CodePermission},
+		{ErrClusterStackNotRunning, CodeUnavailable},
+		{&DeviceReadError{Device: "/dev/sdb", Err: syscall.EIO}, CodeUnavailable},
+		{&ForeignSignatureError{Device: "/dev/sdb", Signature: DiskFormatLUKS}, CodeDeviceInUse},
+		{&os.PathError{Op: "stat", Path: "/dev/sdz", Err: syscall.ENOENT}, CodeDeviceNotFound},
+		{&NVMeCommandError{Op: "connect", Err: errors.New("exit status 1")}, CodeCommandFailed},
+		{&OpError{Code: CodeFormatFailed, Op: "format", Err: context.Canceled}, CodeFormatFailed},
+		{fmt.Errorf("staging: %w", &OpError{Code: CodeMountFailed, Op: "mount", Err: errors.New("boom")}), CodeMountFailed},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ErrorCodeOf(tt.err), "%v", tt.err)
+	}
+}
+
+func TestWrapOpError(t *testing.T) {
+	var err error
+	wrapOpError(&err, "mount", "/mnt", CodeMountFailed)
+	assert.NoError(t, err)
+
+	inner := errors.New("mount failed: exit status 32")
+	err = inner
+	wrapOpError(&err, "mount", "/mnt", CodeMountFailed)
+	var opErr *OpError
+	assert.ErrorAs(t, err, &opErr)
+	assert.Equal(t, CodeMountFailed, opErr.Code)
+	assert.ErrorIs(t, err, inner)
+	assert.Equal(t, "mount failed: exit status 32 (mount /mnt)", err.Error())
+
+	// A wrapped error is not wrapped again.
+	outer := err
+	wrapOpError(&err, "format and mount", "/mnt", CodeFormatFailed)
+	assert.Same(t, outer, err)
+
+	// A more specific code is kept.
+	err = context.DeadlineExceeded
+	wrapOpError(&err, "rescan", "", CodeRescanFailed)
+	assert.Equal(t, CodeTimeout, ErrorCodeOf(err))
+	assert.Equal(t, "context deadline exceeded (rescan)", err.Error())
+}
+
+func TestErrorCodeGRPCCode(t *testing.T) {
+	assert.Equal(t, uint32(0), CodeOK.GRPCCode())
+	assert.Equal(t, uint32(2), CodeUnknown.GRPCCode())
+	assert.Equal(t, uint32(4), CodeTimeout.GRPCCode())
+	assert.Equal(t, uint32(5), CodeDeviceNotFound.GRPCCode())
+	assert.Equal(t, uint32(9), CodeDeviceInUse.GRPCCode())
+	assert.Equal(t, uint32(13), CodeMountFailed.GRPCCode())
+	assert.Equal(t, uint32(14), CodeUnavailable.GRPCCode())
+}
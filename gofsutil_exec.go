@@ -0,0 +1,380 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	mountCmd   = "mount"
+	umountCmd  = "umount"
+	chrootCmd  = "/usr/sbin/chroot"
+	nsenterCmd = "nsenter"
+)
+
+// ErrShellDisallowed is the error of commands run through a shell when
+// FSOptions.DisallowShell is set.
+var ErrShellDisallowed = errors.New("running commands through a shell is disallowed")
+
+// shells are the commands that run command strings.
+var shells = []string{"sh", "bash", "dash", "ash", "ksh", "zsh"}
+
+// shellCommandHook, if set, is called with every shell command created.
+var shellCommandHook atomic.Pointer[func(name string, args []string)]
+
+// SetShellCommandHook makes every command created to run through a shell,
+// e.g. sh -c, call hook with the shell and its arguments, whether or not
+// FSOptions.DisallowShell refuses it, so that callers can assert that no
+// shell is used. nil removes the hook.
+func SetShellCommandHook(hook func(name string, args []string)) {
+	if hook == nil {
+		shellCommandHook.Store(nil)
+		return
+	}
+	shellCommandHook.Store(&hook)
+}
+
+// execCmd is an exec.Cmd that is recorded in the command history of its
+// FS when FSOptions.CommandHistorySize is set.
+type execCmd struct {
+	*exec.Cmd
+	fs *FS
+	// dryRun is set if the command changes the host and FSOptions.DryRun
+	// is set, so that it is recorded instead of run.
+	dryRun bool
+	// sensitive are the mount options to redact from the records of the
+	// command.
+	sensitive []string
+}
+
+// command returns the exec.Cmd that runs name with args. It is the
+// counterpart of exec.Command that applies FSOptions.ExtraEnv, Chroot and
+// HostMountNamespace.
+func (fs *FS) command(name string, args ...string) *execCmd {
+	shellErr := fs.checkShell(name, args)
+	dryRun := fs.DryRun && mutatingCommand(name, args)
+	name, args = fs.hostCommand(name, args)
+	/* #nosec G204 */
+	cmd := exec.Command(name, args...)
+	fs.setCommandEnv(cmd)
+	if shellErr != nil {
+		cmd.Err = shellErr
+	}
+	return &execCmd{Cmd: cmd, fs: fs, dryRun: dryRun}
+}
+
+// commandContext is the counterpart of exec.CommandContext that applies
+// FSOptions.ExtraEnv, Chroot and HostMountNamespace.
+func (fs *FS) commandContext(ctx context.Context, name string, args ...string) *execCmd {
+	shellErr := fs.checkShell(name, args)
+	dryRun := fs.DryRun && mutatingCommand(name, args)
+	name, args = fs.hostCommand(name, args)
+	/* #nosec G204 */
+	cmd := exec.CommandContext(ctx, name, args...)
+	fs.setCommandEnv(cmd)
+	if shellErr != nil {
+		cmd.Err = shellErr
+	}
+	return &execCmd{Cmd: cmd, fs: fs, dryRun: dryRun}
+}
+
+// checkShell returns ErrShellDisallowed if name is a shell and
+// DisallowShell is set.
+func (fs *FS) checkShell(name string, args []string) error {
+	if !stringInSlice(filepath.Base(name), shells) {
+		return nil
+	}
+	if hook := shellCommandHook.Load(); hook != nil {
+		(*hook)(name, args)
+	}
+	if fs.DisallowShell {
+		return fmt.Errorf("%s: %w", name, ErrShellDisallowed)
+	}
+	return nil
+}
+
+// hostCommand returns the command and arguments that run name with args
+// in the mount namespace of the host, if HostMountNamespace is set, or in
+// the Chroot directory. Otherwise name is looked up with lookPath.
+func (fs *FS) hostCommand(name string, args []string) (string, []string) {
+	switch {
+	case fs.HostMountNamespace:
+		nsArgs := []string{"--target", strconv.Itoa(fs.hostNamespacePID()), "--mount", "--", name}
+		return fs.lookPath(nsenterCmd), append(nsArgs, args...)
+	case fs.Chroot != "":
+		return chrootCmd, append([]string{fs.Chroot, name}, args...)
+	}
+	return fs.lookPath(name), args
+}
+
+// hostNamespacePID returns the process whose mount namespace is entered
+// with HostMountNamespace.
+func (fs *FS) hostNamespacePID() int {
+	if fs.HostNamespacePID > 0 {
+		return fs.HostNamespacePID
+	}
+	return 1
+}
+
+// umountBinary returns the command used to unmount, empty to use the
+// umount2 system call. The system call would unmount in the namespace of
+// the process, so the umount command is used with HostMountNamespace.
+func (fs *FS) umountBinary() string {
+	if fs.UmountBinary == "" && fs.HostMountNamespace {
+		return umountCmd
+	}
+	return fs.UmountBinary
+}
+
+// runsOnHost returns true if the commands run in the host root set with
+// Chroot or HostMountNamespace.
+func (fs *FS) runsOnHost() bool {
+	return fs.HostMountNamespace || fs.Chroot != ""
+}
+
+// hostRoot returns the directory in which the process sees the root the
+// commands run in with Chroot or HostMountNamespace, the root of the
+// process of the host namespace for the latter, and an empty string if
+// the commands run in the root of the process.
+func (fs *FS) hostRoot() string {
+	switch {
+	case fs.HostMountNamespace:
+		return fs.procPath(fmt.Sprintf("/proc/%d/root", fs.hostNamespacePID()))
+	case fs.Chroot != "":
+		return fs.Chroot
+	}
+	return ""
+}
+
+// hostPath returns the path by which the commands see the file at path.
+// A path within the host root is returned relative to it. A path outside
+// of it is returned as is if it is the same file in the host root, e.g. a
+// host directory mounted at the same path in the container, as the
+// commands resolve it there. An error is returned for any other path when
+// the commands run in the host root, as they would open another file or
+// none.
+func (fs *FS) hostPath(path string) (string, error) {
+	root := fs.hostRoot()
+	if root == "" {
+		return path, nil
+	}
+	if rel, err := filepath.Rel(root, path); err == nil && filepath.IsLocal(rel) {
+		return "/" + rel, nil
+	}
+	st, err := os.Stat(path)
+	if err == nil {
+		if hostSt, err := os.Stat(filepath.Join(root, path)); err == nil && os.SameFile(st, hostSt) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s is not visible in the host root %s", path, root)
+}
+
+// createHostTemp creates a new temporary file, readable only by its owner,
+// in the temporary directory of the root the commands run in. It returns
+// the file and the path by which the commands see it.
+func (fs *FS) createHostTemp(pattern string) (*os.File, string, error) {
+	root := fs.hostRoot()
+	dir := ""
+	if root != "" {
+		dir = filepath.Join(root, "tmp")
+	}
+	f, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return nil, "", err
+	}
+	if root == "" {
+		return f, f.Name(), nil
+	}
+	return f, "/tmp/" + filepath.Base(f.Name()), nil
+}
+
+// commandAvailable returns true if the command name can be run. Commands
+// run on the host are assumed to be available there.
+func (fs *FS) commandAvailable(name string) bool {
+	if fs.runsOnHost() {
+		return true
+	}
+	_, err := exec.LookPath(fs.lookPath(name))
+	return err == nil
+}
+
+// recordedArgs returns the arguments of the command to record, without
+// secrets.
+func (c *execCmd) recordedArgs() []string {
+	args := scrubMountArgs(c.Args[1:])
+	for i, arg := range args {
+		args[i] = redactOptions(arg, c.sensitive)
+	}
+	return args
+}
+
+// Run runs the command like exec.Cmd.Run and records it. With DryRun a
+// command that changes the host is only recorded as a DryRunAction.
+func (c *execCmd) Run() error {
+	if c.dryRun {
+		if errors.Is(c.Err, ErrShellDisallowed) {
+			return c.Err
+		}
+		c.fs.dryRunActions.add(DryRunAction{
+			Op:      DryRunExec,
+			Command: c.Args[0],
+			Args:    c.recordedArgs(),
+		})
+		return nil
+	}
+	if c.fs.CommandHistorySize <= 0 {
+		return c.Cmd.Run()
+	}
+	out := &limitedBuffer{limit: CommandHistoryOutputLimit}
+	stdout, stderr := c.Stdout, c.Stderr
+	c.Stdout = teeWriter(stdout, out)
+	if sameWriter(stdout, stderr) {
+		// Keep a single writer so that exec still serializes the writes.
+		c.Stderr = c.Stdout
+	} else {
+		c.Stderr = teeWriter(stderr, out)
+	}
+	start := time.Now()
+	err := c.Cmd.Run()
+	record := CommandRecord{
+		Command:   c.Path,
+		Args:      c.recordedArgs(),
+		Start:     start,
+		Duration:  time.Since(start),
+		ExitCode:  -1,
+		Output:    redactOptions(string(out.buf), c.sensitive),
+		Truncated: out.truncated,
+	}
+	if c.ProcessState != nil {
+		record.ExitCode = c.ProcessState.ExitCode()
+	}
+	if err != nil {
+		record.Err = err.Error()
+	}
+	c.fs.history.add(c.fs.CommandHistorySize, record)
+	return err
+}
+
+// Output runs the command like exec.Cmd.Output and records it.
+func (c *execCmd) Output() ([]byte, error) {
+	if c.Stdout != nil {
+		return nil, errors.New("exec: Stdout already set")
+	}
+	var stdout, stderr bytes.Buffer
+	c.Stdout = &stdout
+	captureStderr := c.Stderr == nil
+	if captureStderr {
+		c.Stderr = &stderr
+	}
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if captureStderr && errors.As(err, &exitErr) {
+		exitErr.Stderr = stderr.Bytes()
+	}
+	return stdout.Bytes(), err
+}
+
+// CombinedOutput runs the command like exec.Cmd.CombinedOutput and
+// records it.
+func (c *execCmd) CombinedOutput() ([]byte, error) {
+	if c.Stdout != nil {
+		return nil, errors.New("exec: Stdout already set")
+	}
+	if c.Stderr != nil {
+		return nil, errors.New("exec: Stderr already set")
+	}
+	var b bytes.Buffer
+	c.Stdout = &b
+	c.Stderr = &b
+	err := c.Run()
+	return b.Bytes(), err
+}
+
+// sameWriter returns true if a and b are the same writer.
+func sameWriter(a, b io.Writer) (same bool) {
+	defer func() {
+		// Writers of uncomparable types are not the same.
+		if recover() != nil {
+			same = false
+		}
+	}()
+	return a == b
+}
+
+// teeWriter returns a writer that writes to w, if any, and to out.
+func teeWriter(w io.Writer, out io.Writer) io.Writer {
+	if w == nil {
+		return out
+	}
+	return io.MultiWriter(w, out)
+}
+
+func (fs *FS) setCommandEnv(cmd *exec.Cmd) {
+	if len(fs.ExtraEnv) > 0 {
+		cmd.Env = append(os.Environ(), fs.ExtraEnv...)
+	}
+}
+
+// lookPath returns the path of the command name in the PATH of ExtraEnv,
+// if any. Otherwise name is returned and exec looks it up in the PATH of
+// the process.
+func (fs *FS) lookPath(name string) string {
+	if strings.ContainsRune(name, filepath.Separator) {
+		return name
+	}
+	path, ok := extraEnvValue(fs.ExtraEnv, "PATH")
+	if !ok {
+		return name
+	}
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+		p := filepath.Join(dir, name)
+		if st, err := os.Stat(p); err == nil && st.Mode().IsRegular() && st.Mode()&0o111 != 0 {
+			return p
+		}
+	}
+	return name
+}
+
+// extraEnvValue returns the value of the last assignment of key in env.
+func extraEnvValue(env []string, key string) (string, bool) {
+	for i := len(env) - 1; i >= 0; i-- {
+		if v, ok := strings.CutPrefix(env[i], key+"="); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// mountBinary returns the mount command.
+func (fs *FS) mountBinary() string {
+	if fs.MountBinary != "" {
+		return fs.MountBinary
+	}
+	return mountCmd
+}
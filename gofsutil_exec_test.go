@@ -0,0 +1,200 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRecorder writes a script that appends its arguments and the value
+// of GOFSUTIL_TEST to log.
+func writeRecorder(t *testing.T, path, log string) {
+	script := "#!/bin/sh\necho \"$GOFSUTIL_TEST $*\" >> " + log + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o700)) // #nosec G306
+}
+
+func TestCommandExtraEnv(t *testing.T) {
+	bin := t.TempDir()
+	log := filepath.Join(t.TempDir(), "log")
+	writeRecorder(t, filepath.Join(bin, "gofsutil-tool"), log)
+
+	fs := NewFS(FSOptions{ExtraEnv: []string{"PATH=/nonexistent:" + bin, "GOFSUTIL_TEST=extra"}})
+	assert.Equal(t, filepath.Join(bin, "gofsutil-tool"), fs.lookPath("gofsutil-tool"))
+	assert.Equal(t, "/bin/true", fs.lookPath("/bin/true"))
+	assert.Equal(t, "not-found", fs.lookPath("not-found"))
+
+	require.NoError(t, fs.commandContext(context.Background(), "gofsutil-tool", "a", "b").Run())
+	out, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, "extra a b", strings.TrimSpace(string(out)))
+
+	// Without ExtraEnv the ambient environment is used.
+	assert.Nil(t, NewFS(FSOptions{}).command("true").Env)
+}
+
+func TestMountUmountBinary(t *testing.T) {
+	bin := t.TempDir()
+	log := filepath.Join(t.TempDir(), "log")
+	writeRecorder(t, filepath.Join(bin, "mount"), log)
+	writeRecorder(t, filepath.Join(bin, "umount"), log)
+	target := t.TempDir()
+
+	fs := NewFS(FSOptions{
+		MountBinary:  filepath.Join(bin, "mount"),
+		UmountBinary: filepath.Join(bin, "umount"),
+		ExtraEnv:     []string{"GOFSUTIL_TEST=env"},
+	})
+	ctx := context.Background()
+	require.NoError(t, fs.Mount(ctx, "/dev/sdx", target, "ext4", "ro"))
+	require.NoError(t, fs.Unmount(ctx, target))
+	out, err := os.ReadFile(log)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "env ")
+	assert.Contains(t, lines[0], "/dev/sdx "+target)
+	assert.Equal(t, "env "+target, lines[1])
+}
+
+func TestCommandHistory(t *testing.T) {
+	bin := t.TempDir()
+	script := "#!/bin/sh\necho out\necho err >&2\nexit $1\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "gofsutil-tool"), []byte(script), 0o700)) // #nosec G306
+
+	fs := NewFS(FSOptions{ExtraEnv: []string{"PATH=" + bin}})
+	ctx := context.Background()
+	require.NoError(t, fs.commandContext(ctx, "gofsutil-tool", "0").Run())
+	assert.Empty(t, fs.GetCommandHistory())
+
+	fs.CommandHistorySize = 2
+	out, err := fs.commandContext(ctx, "gofsutil-tool", "0").CombinedOutput()
+	require.NoError(t, err)
+	assert.Equal(t, "out\nerr\n", string(out))
+	out, err = fs.command("gofsutil-tool", "3", "password=s3cret").Output()
+	assert.Equal(t, "out\n", string(out))
+	var exitErr *exec.ExitError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, "err\n", string(exitErr.Stderr))
+	require.Error(t, fs.command("gofsutil-missing").Run())
+
+	history := fs.GetCommandHistory()
+	require.Len(t, history, 2)
+	assert.Equal(t, filepath.Join(bin, "gofsutil-tool"), history[0].Command)
+	assert.Equal(t, []string{"3", "password=****"}, history[0].Args)
+	assert.Equal(t, 3, history[0].ExitCode)
+	assert.Contains(t, history[0].Output, "out\n")
+	assert.Contains(t, history[0].Output, "err\n")
+	assert.Equal(t, "exit status 3", history[0].Err)
+	assert.False(t, history[0].Start.IsZero())
+	assert.Equal(t, "gofsutil-missing", history[1].Command)
+	assert.Equal(t, -1, history[1].ExitCode)
+	assert.NotEmpty(t, history[1].Err)
+}
+
+func TestCommandOnHost(t *testing.T) {
+	fs := NewFS(FSOptions{Chroot: "/noderoot"})
+	assert.Equal(t, []string{chrootCmd, "/noderoot", "mkfs.ext4", "-F", "/dev/sdc"},
+		fs.command("mkfs.ext4", "-F", "/dev/sdc").Args)
+	assert.True(t, fs.commandAvailable("gofsutil-missing-tool"))
+
+	fs = NewFS(FSOptions{Chroot: "/noderoot", HostMountNamespace: true})
+	cmd := fs.commandContext(context.Background(), "/usr/sbin/multipath", "-ll")
+	assert.Equal(t, []string{nsenterCmd, "--target", "1", "--mount", "--", "/usr/sbin/multipath", "-ll"}, cmd.Args)
+	assert.Equal(t, umountCmd, fs.umountBinary())
+
+	fs = NewFS(FSOptions{HostMountNamespace: true, HostNamespacePID: 42, UmountBinary: "/usr/bin/umount"})
+	assert.Equal(t, []string{nsenterCmd, "--target", "42", "--mount", "--", "umount", "/mnt"}, fs.command("umount", "/mnt").Args)
+	assert.Equal(t, "/usr/bin/umount", fs.umountBinary())
+
+	fs = NewFS(FSOptions{})
+	assert.Equal(t, []string{"mkfs.ext4", "/dev/sdc"}, fs.command("mkfs.ext4", "/dev/sdc").Args)
+	assert.Empty(t, fs.umountBinary())
+	assert.False(t, fs.commandAvailable("gofsutil-missing-tool"))
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	b := &limitedBuffer{limit: 4}
+	n, err := b.Write([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	n, _ = b.Write([]byte("def"))
+	assert.Equal(t, 3, n)
+	assert.Equal(t, "abcd", string(b.buf))
+	assert.True(t, b.truncated)
+}
+
+func TestHostPath(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "var", "lib", "images"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "var", "lib", "images", "a.img"), nil, 0o600))
+
+	// shared is a file of the container that the host root also has,
+	// other a file of the container that the host root has another of.
+	containerDir := t.TempDir()
+	shared := filepath.Join(containerDir, "shared.img")
+	other := filepath.Join(containerDir, "other.img")
+	require.NoError(t, os.WriteFile(shared, nil, 0o600))
+	require.NoError(t, os.WriteFile(other, nil, 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, containerDir), 0o750))
+	require.NoError(t, os.Symlink(shared, filepath.Join(root, shared)))
+	require.NoError(t, os.WriteFile(filepath.Join(root, other), nil, 0o600))
+
+	path, err := NewFS(FSOptions{}).hostPath("/data/a.img")
+	require.NoError(t, err)
+	assert.Equal(t, "/data/a.img", path)
+
+	for _, fs := range []*FS{
+		NewFS(FSOptions{Chroot: root}),
+		NewFS(FSOptions{HostMountNamespace: true, HostNamespacePID: 7, ProcRoot: filepath.Dir(root)}),
+	} {
+		if fs.HostMountNamespace {
+			// The host root is /proc/7/root.
+			require.NoError(t, os.MkdirAll(filepath.Join(filepath.Dir(root), "7"), 0o750))
+			require.NoError(t, os.Symlink(root, filepath.Join(filepath.Dir(root), "7", "root")))
+		}
+		hostRoot := fs.hostRoot()
+		path, err := fs.hostPath(filepath.Join(hostRoot, "var", "lib", "images", "a.img"))
+		require.NoError(t, err)
+		assert.Equal(t, "/var/lib/images/a.img", path)
+		// A file of the container is only used if the host root has the
+		// same file at the same path.
+		_, err = fs.hostPath("/var/lib/images/a.img")
+		assert.ErrorContains(t, err, "not visible in the host root")
+		path, err = fs.hostPath(shared)
+		require.NoError(t, err)
+		assert.Equal(t, shared, path)
+		_, err = fs.hostPath(other)
+		assert.ErrorContains(t, err, "not visible in the host root")
+		_, err = fs.hostPath("/data/a.img")
+		assert.ErrorContains(t, err, "not visible in the host root")
+
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "tmp"), 0o750))
+		f, name, err := fs.createHostTemp("gofsutil-test-")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		assert.Equal(t, filepath.Join(root, name), filepath.Join(root, "tmp", filepath.Base(f.Name())))
+		assert.FileExists(t, filepath.Join(root, name))
+	}
+}
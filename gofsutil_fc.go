@@ -0,0 +1,148 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	fcHostsPath  = "/sys/class/fc_host"
+	fcVPortsPath = "/sys/class/fc_vports"
+)
+
+// FCPortStateOnline is the port state of an FC host whose link is up.
+const FCPortStateOnline = "Online"
+
+// FCHostInfo describes a local FC host.
+type FCHostInfo struct {
+	// Host is the name of the FC host, e.g. host5.
+	Host string
+	// PortName is the WWPN of the host, e.g. 0x10000090fa1b2c3d.
+	PortName string
+	// NodeName is the WWNN of the host.
+	NodeName string
+	// PortState is the port state, e.g. Online or Linkdown.
+	PortState string
+	// Speed is the negotiated link speed, e.g. 16 Gbit.
+	Speed string
+	// FabricName is the name of the fabric the host is logged into.
+	FabricName string
+}
+
+// Online returns true if the link of the FC host is up.
+func (h FCHostInfo) Online() bool {
+	return h.PortState == FCPortStateOnline
+}
+
+// NPIVPort is an NPIV virtual port created on a physical FC host.
+type NPIVPort struct {
+	// Name is the name of the vport, e.g. vport-5:0-0.
+	Name string
+	// Host is the FC host the vport was created on, e.g. host5.
+	Host string
+	// SCSIHost is the SCSI host of the vport, e.g. host7, once the vport
+	// has logged into the fabric.
+	SCSIHost string
+	// PortName is the WWPN of the vport, e.g. 0x2101001b32a9da4e.
+	PortName string
+	// NodeName is the WWNN of the vport.
+	NodeName string
+	// State is the vport state, e.g. Active.
+	State string
+}
+
+var (
+	fcWWNRegex   = regexp.MustCompile(`^[0-9a-f]{16}$`)
+	fcHostRegex  = regexp.MustCompile(`^(?:host)?([0-9]+)$`)
+	fcVPortRegex = regexp.MustCompile(`^vport-([0-9]+):[0-9]+-[0-9]+$`)
+
+	// fcByPathRegex matches the /dev/disk/by-path names of FC LUNs, e.g.
+	// pci-0000:41:00.0-fc-0x500601663ce0213c-lun-1, but not of their
+	// partitions.
+	fcByPathRegex = regexp.MustCompile(`-fc-0x([0-9a-fA-F]{16})-lun-([0-9]+|0x[0-9a-fA-F]{16})$`)
+)
+
+// normalizeFCWWN returns an FC port or node name as 16 lower case hex
+// digits, the form written to vport_create and vport_delete. The name may
+// have a 0x prefix and colon separators.
+func normalizeFCWWN(wwn string) (string, error) {
+	s := strings.ToLower(strings.TrimSpace(wwn))
+	s = strings.TrimPrefix(s, "0x")
+	s = strings.ReplaceAll(s, ":", "")
+	if !fcWWNRegex.MatchString(s) {
+		return "", fmt.Errorf("invalid FC WWN: %s", wwn)
+	}
+	return s, nil
+}
+
+// generateFCWWN returns a random locally assigned (NAA 3) FC WWN.
+func generateFCWWN() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[0] = 0x30 | (b[0] & 0x0f)
+	return hex.EncodeToString(b), nil
+}
+
+// fcHostName returns the name of an FC host given as hostN or N.
+func fcHostName(host string) (string, error) {
+	m := fcHostRegex.FindStringSubmatch(host)
+	if m == nil {
+		return "", fmt.Errorf("invalid FC host: %s", host)
+	}
+	return "host" + m[1], nil
+}
+
+// fcVPortHost returns the FC host of a vport given its name.
+func fcVPortHost(name string) string {
+	m := fcVPortRegex.FindStringSubmatch(name)
+	if m == nil {
+		return ""
+	}
+	return "host" + m[1]
+}
+
+// npivPortWWNs returns the value written to vport_create and
+// vport_delete for a vport.
+func npivPortWWNs(wwpn, wwnn string) string {
+	return wwpn + ":" + wwnn
+}
+
+// parseFCByPathName returns the target WWPN, as 16 lower case hex digits,
+// and the LUN of the /dev/disk/by-path name of an FC LUN. LUNs in 64-bit
+// notation are converted like in parseISCSIByPathName.
+func parseFCByPathName(name string) (wwpn string, lun int, ok bool) {
+	m := fcByPathRegex.FindStringSubmatch(name)
+	if m == nil {
+		return "", 0, false
+	}
+	if hex, isHex := strings.CutPrefix(m[2], "0x"); isHex {
+		v, err := strconv.ParseUint(hex, 16, 64)
+		if err != nil {
+			return "", 0, false
+		}
+		return strings.ToLower(m[1]), int(v >> 48), true
+	}
+	n, err := strconv.Atoi(m[2])
+	if err != nil {
+		return "", 0, false
+	}
+	return strings.ToLower(m[1]), n, true
+}
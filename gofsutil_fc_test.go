@@ -0,0 +1,207 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeFCWWN(t *testing.T) {
+	for in, want := range map[string]string{
+		"0x2101001B32A9DA4E":      "2101001b32a9da4e",
+		"21:01:00:1b:32:a9:da:4e": "2101001b32a9da4e",
+		"2101001b32a9da4e\n":      "2101001b32a9da4e",
+	} {
+		got, err := normalizeFCWWN(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "0x2101", "2101001b32a9da4g"} {
+		_, err := normalizeFCWWN(in)
+		assert.Error(t, err, in)
+	}
+
+	wwn, err := generateFCWWN()
+	require.NoError(t, err)
+	assert.Regexp(t, `^3[0-9a-f]{15}$`, wwn)
+}
+
+func TestNPIVPorts(t *testing.T) {
+	sysRoot := t.TempDir()
+	hostDir := filepath.Join(sysRoot, "class", "fc_host", "host5")
+	require.NoError(t, os.MkdirAll(hostDir, 0o750))
+	for _, attr := range []string{"vport_create", "vport_delete"} {
+		require.NoError(t, os.WriteFile(filepath.Join(hostDir, attr), nil, 0o600))
+	}
+	fs := NewFS(FSOptions{SysRoot: sysRoot})
+	ctx := context.Background()
+
+	vports, err := fs.GetNPIVPorts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, vports)
+
+	_, err = fs.CreateNPIVPort(ctx, "scsi5", "", "")
+	assert.Error(t, err)
+
+	vport, err := fs.CreateNPIVPort(ctx, "5", "0x2101001B32A9DA4E", "")
+	require.NoError(t, err)
+	assert.Equal(t, "host5", vport.Host)
+	assert.Equal(t, "0x2101001b32a9da4e", vport.PortName)
+	created, err := os.ReadFile(filepath.Join(hostDir, "vport_create"))
+	require.NoError(t, err)
+	assert.Regexp(t, `^2101001b32a9da4e:3[0-9a-f]{15}$`, string(created))
+
+	// Populate the vport the kernel would have created.
+	vportDir := filepath.Join(sysRoot, "class", "fc_vports", "vport-5:0-0")
+	require.NoError(t, os.MkdirAll(filepath.Join(vportDir, "device", "host7"), 0o750))
+	for attr, value := range map[string]string{
+		"port_name":   "0x2101001b32a9da4e\n",
+		"node_name":   "0x2001001b32a9da4e\n",
+		"vport_state": "Active\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(vportDir, attr), []byte(value), 0o600))
+	}
+
+	vports, err = fs.GetNPIVPorts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []NPIVPort{{
+		Name:     "vport-5:0-0",
+		Host:     "host5",
+		SCSIHost: "host7",
+		PortName: "0x2101001b32a9da4e",
+		NodeName: "0x2001001b32a9da4e",
+		State:    "Active",
+	}}, vports)
+
+	assert.Error(t, fs.DeleteNPIVPort(ctx, "host5", "0x2101001b32a9da4f"))
+	assert.Error(t, fs.DeleteNPIVPort(ctx, "host6", "0x2101001b32a9da4e"))
+	require.NoError(t, fs.DeleteNPIVPort(ctx, "host5", "21:01:00:1b:32:a9:da:4e"))
+	deleted, err := os.ReadFile(filepath.Join(hostDir, "vport_delete"))
+	require.NoError(t, err)
+	assert.Equal(t, "2101001b32a9da4e:2001001b32a9da4e", string(deleted))
+}
+
+func TestGetFCHostInfo(t *testing.T) {
+	sysRoot := t.TempDir()
+	fs := NewFS(FSOptions{SysRoot: sysRoot})
+	ctx := context.Background()
+
+	_, err := fs.GetFCHostInfo(ctx)
+	assert.Error(t, err)
+
+	hostsDir := filepath.Join(sysRoot, "class", "fc_host")
+	for host, attrs := range map[string]map[string]string{
+		"host5": {
+			"port_name":   "0x10000090fa1b2c3d\n",
+			"node_name":   "0x20000090fa1b2c3d\n",
+			"port_state":  "Online\n",
+			"speed":       "16 Gbit\n",
+			"fabric_name": "0x100000051e0c1a2b\n",
+		},
+		"host6": {
+			"port_name":  "0x10000090fa1b2c3e\n",
+			"port_state": "Linkdown\n",
+			"speed":      "unknown\n",
+		},
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(hostsDir, host), 0o750))
+		for attr, value := range attrs {
+			require.NoError(t, os.WriteFile(filepath.Join(hostsDir, host, attr), []byte(value), 0o600))
+		}
+	}
+
+	hosts, err := fs.GetFCHostInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []FCHostInfo{
+		{
+			Host:       "host5",
+			PortName:   "0x10000090fa1b2c3d",
+			NodeName:   "0x20000090fa1b2c3d",
+			PortState:  "Online",
+			Speed:      "16 Gbit",
+			FabricName: "0x100000051e0c1a2b",
+		},
+		{
+			Host:      "host6",
+			PortName:  "0x10000090fa1b2c3e",
+			PortState: "Linkdown",
+			Speed:     "unknown",
+		},
+	}, hosts)
+	assert.True(t, hosts[0].Online())
+	assert.False(t, hosts[1].Online())
+}
+
+func TestParseFCByPathName(t *testing.T) {
+	tests := []struct {
+		name string
+		wwpn string
+		lun  int
+		ok   bool
+	}{
+		{name: "pci-0000:41:00.0-fc-0x500601663CE0213C-lun-1", wwpn: "500601663ce0213c", lun: 1, ok: true},
+		{name: "pci-0000:41:00.0-fc-0x500601663ce0213c-lun-0x0101000000000000", wwpn: "500601663ce0213c", lun: 257, ok: true},
+		{name: "pci-0000:41:00.0-fc-0x500601663ce0213c-lun-1-part1"},
+		{name: "pci-0000:41:00.0-fc-0x5006016-lun-1"},
+		{name: "ip-1.1.1.1:3260-iscsi-iqn.1992-04.com.emc:6000097-lun-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wwpn, lun, ok := parseFCByPathName(tt.name)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.wwpn, wwpn)
+			assert.Equal(t, tt.lun, lun)
+		})
+	}
+}
+
+func TestFCTargetWWPNLUNToDevicePath(t *testing.T) {
+	devRoot := t.TempDir()
+	fs := NewFS(FSOptions{DevRoot: devRoot})
+	ctx := context.Background()
+
+	_, err := fs.FCTargetWWPNLUNToDevicePath(ctx, "0x500601663ce0213c", 1)
+	assert.Error(t, err)
+
+	byPath := filepath.Join(devRoot, "disk", "by-path")
+	require.NoError(t, os.MkdirAll(byPath, 0o750))
+	links := map[string]string{
+		"pci-0000:41:00.0-fc-0x500601663ce0213c-lun-1":       "../../sdc",
+		"pci-0000:41:00.0-fc-0x500601663ce0213c-lun-1-part1": "../../sdc1",
+		"pci-0000:41:00.1-fc-0x500601663ce0213c-lun-1":       "../../sdd",
+		"pci-0000:41:00.0-fc-0x500601663ce0213c-lun-2":       "../../sde",
+		"pci-0000:41:00.0-fc-0x500601673ce0213c-lun-1":       "../../sdf",
+	}
+	for name, target := range links {
+		require.NoError(t, os.Symlink(target, filepath.Join(byPath, name)))
+	}
+
+	devices, err := fs.FCTargetWWPNLUNToDevicePath(ctx, "50:06:01:66:3c:e0:21:3c", 1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		filepath.Join(byPath, "pci-0000:41:00.0-fc-0x500601663ce0213c-lun-1"): filepath.Join(devRoot, "sdc"),
+		filepath.Join(byPath, "pci-0000:41:00.1-fc-0x500601663ce0213c-lun-1"): filepath.Join(devRoot, "sdd"),
+	}, devices)
+
+	_, err = fs.FCTargetWWPNLUNToDevicePath(ctx, "invalid", 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,205 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// getFCHostInfo returns the port names, state, speed and fabric of the
+// local FC hosts.
+func (fs *FS) getFCHostInfo(_ context.Context) ([]FCHostInfo, error) {
+	hosts := make([]FCHostInfo, 0)
+	fcHostsDir := fs.sysPath(fcHostsPath)
+	entries, err := os.ReadDir(fcHostsDir)
+	if err != nil {
+		log.WithField("error", err).Error("Cannot read directory: " + fcHostsDir)
+		return hosts, err
+	}
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), "host") {
+			continue
+		}
+		hosts = append(hosts, fs.readFCHostInfo(e.Name()))
+	}
+	return hosts, nil
+}
+
+// readFCHostInfo reads the attributes of the FC host, e.g. host5.
+func (fs *FS) readFCHostInfo(host string) FCHostInfo {
+	dir := filepath.Join(fs.sysPath(fcHostsPath), host)
+	return FCHostInfo{
+		Host:       host,
+		PortName:   readSysfsAttr(filepath.Join(dir, "port_name")),
+		NodeName:   readSysfsAttr(filepath.Join(dir, "node_name")),
+		PortState:  readSysfsAttr(filepath.Join(dir, "port_state")),
+		Speed:      readSysfsAttr(filepath.Join(dir, "speed")),
+		FabricName: readSysfsAttr(filepath.Join(dir, "fabric_name")),
+	}
+}
+
+// getNPIVPorts returns the NPIV vports of the local FC hosts.
+func (fs *FS) getNPIVPorts(_ context.Context) ([]NPIVPort, error) {
+	vports := make([]NPIVPort, 0)
+	vportsDir := fs.sysPath(fcVPortsPath)
+	entries, err := os.ReadDir(vportsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return vports, nil
+		}
+		return vports, err
+	}
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), "vport-") {
+			continue
+		}
+		dir := filepath.Join(vportsDir, e.Name())
+		vport := NPIVPort{
+			Name:     e.Name(),
+			Host:     fcVPortHost(e.Name()),
+			PortName: readSysfsAttr(filepath.Join(dir, "port_name")),
+			NodeName: readSysfsAttr(filepath.Join(dir, "node_name")),
+			State:    readSysfsAttr(filepath.Join(dir, "vport_state")),
+		}
+		// The SCSI host of the vport is a child of the vport device.
+		children, _ := os.ReadDir(filepath.Join(dir, "device"))
+		for _, c := range children {
+			if strings.HasPrefix(c.Name(), "host") {
+				vport.SCSIHost = c.Name()
+				break
+			}
+		}
+		vports = append(vports, vport)
+	}
+	return vports, nil
+}
+
+// findNPIVPort returns the vport with the given port name.
+func (fs *FS) findNPIVPort(ctx context.Context, wwpn string) (*NPIVPort, error) {
+	vports, err := fs.getNPIVPorts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range vports {
+		if name, err := normalizeFCWWN(vports[i].PortName); err == nil && name == wwpn {
+			return &vports[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// writeFCHostAttr writes value to the attribute of an FC host.
+func (fs *FS) writeFCHostAttr(host, attr, value string) error {
+	path := filepath.Join(fs.sysPath(fcHostsPath), host, attr)
+	log.Infof("writing %s to %s", value, path)
+	if err := fs.writeSysfsFile(path, value); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// createNPIVPort creates an NPIV vport on the FC host. A random port or
+// node name is generated if none is given.
+func (fs *FS) createNPIVPort(ctx context.Context, host, wwpn, wwnn string) (*NPIVPort, error) {
+	host, err := fcHostName(host)
+	if err != nil {
+		return nil, err
+	}
+	for _, wwn := range []*string{&wwpn, &wwnn} {
+		if *wwn == "" {
+			*wwn, err = generateFCWWN()
+		} else {
+			*wwn, err = normalizeFCWWN(*wwn)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := fs.writeFCHostAttr(host, "vport_create", npivPortWWNs(wwpn, wwnn)); err != nil {
+		return nil, err
+	}
+	vport, err := fs.findNPIVPort(ctx, wwpn)
+	if err != nil || vport == nil {
+		// The vport was created but is not listed yet.
+		return &NPIVPort{Host: host, PortName: "0x" + wwpn, NodeName: "0x" + wwnn}, nil
+	}
+	return vport, nil
+}
+
+// deleteNPIVPort deletes the NPIV vport with the given port name from the
+// FC host.
+func (fs *FS) deleteNPIVPort(ctx context.Context, host, wwpn string) error {
+	host, err := fcHostName(host)
+	if err != nil {
+		return err
+	}
+	if wwpn, err = normalizeFCWWN(wwpn); err != nil {
+		return err
+	}
+	vport, err := fs.findNPIVPort(ctx, wwpn)
+	if err != nil {
+		return err
+	}
+	if vport == nil {
+		return fmt.Errorf("NPIV port 0x%s not found", wwpn)
+	}
+	if vport.Host != "" && vport.Host != host {
+		return fmt.Errorf("NPIV port 0x%s is on %s, not %s", wwpn, vport.Host, host)
+	}
+	wwnn, err := normalizeFCWWN(vport.NodeName)
+	if err != nil {
+		return err
+	}
+	return fs.writeFCHostAttr(host, "vport_delete", npivPortWWNs(wwpn, wwnn))
+}
+
+// fcTargetWWPNLUNToDevicePath returns the /dev/disk/by-path entries of the
+// LUN lunID of the FC target port wwpn and their device paths.
+func (fs *FS) fcTargetWWPNLUNToDevicePath(_ context.Context, wwpn string, lunID int) (map[string]string, error) {
+	result := make(map[string]string)
+	wwpn, err := normalizeFCWWN(wwpn)
+	if err != nil {
+		return result, err
+	}
+	bypathdir := fs.devPath("/dev/disk/by-path")
+	entries, err := os.ReadDir(bypathdir)
+	if err != nil {
+		return result, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		// Looking for entries of these forms:
+		// pci-0000:41:00.0-fc-0x500601663ce0213c-lun-1 -> ../../sdc
+		// pci-0000:41:00.0-fc-0x500601663ce0213c-lun-0x0101000000000000 -> ../../sdro
+		entryWWPN, entryLUN, ok := parseFCByPathName(name)
+		if !ok || entryWWPN != wwpn || entryLUN != lunID {
+			continue
+		}
+		path := filepath.Join(bypathdir, name)
+		devPath, err := os.Readlink(path)
+		if err != nil {
+			return result, err
+		}
+		result[path] = fs.devPath("/dev/" + filepath.Base(devPath))
+	}
+	return result, nil
+}
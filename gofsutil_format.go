@@ -0,0 +1,165 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FormatOptions control how mkfs is run by FormatAndMount, Format and
+// FormatAndMountMPath. They are passed in the context with
+// WithFormatOptions.
+type FormatOptions struct {
+	// Timeout is how long mkfs may run before it is killed,
+	// FSOptions.FormatTimeout if zero.
+	Timeout time.Duration
+	// Output, if set, is called with each line mkfs writes to its
+	// standard output or standard error, e.g. to report the progress of
+	// formatting a large volume. Calls are serialized.
+	Output func(line string)
+	// UUID, if set, is the UUID of the new filesystem, e.g. to keep the
+	// UUID stable across snapshot restores. It is only supported for
+	// ext3, ext4 and xfs.
+	UUID string
+}
+
+var (
+	// ErrInvalidFormatOptions is returned when FormatOptions are not
+	// valid.
+	ErrInvalidFormatOptions = errors.New("invalid format options")
+
+	// ErrFormatOptionNotSupported is returned when a format option is not
+	// supported for the filesystem type.
+	ErrFormatOptionNotSupported = errors.New("format option not supported")
+)
+
+// mkfsArgs returns the mkfs arguments applying the options to a
+// filesystem of type fsType, to be passed before the device.
+func (o FormatOptions) mkfsArgs(fsType string) ([]string, error) {
+	if o.UUID == "" {
+		return nil, nil
+	}
+	uuid := strings.ToLower(o.UUID)
+	if !uuidRegex.MatchString(uuid) {
+		return nil, fmt.Errorf("%w: UUID %q is not of the form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx", ErrInvalidFormatOptions, o.UUID)
+	}
+	switch fsType {
+	case "ext3", "ext4":
+		return []string{"-U", uuid}, nil
+	case "xfs":
+		return []string{"-m", "uuid=" + uuid}, nil
+	}
+	return nil, fmt.Errorf("%w: cannot set the UUID of a %s filesystem", ErrFormatOptionNotSupported, fsType)
+}
+
+type formatOptionsKey struct{}
+
+// WithFormatOptions returns a copy of ctx that carries the format options.
+func WithFormatOptions(ctx context.Context, opts FormatOptions) context.Context {
+	return context.WithValue(ctx, formatOptionsKey{}, opts)
+}
+
+// formatOptionsFromContext returns the format options carried by ctx.
+func formatOptionsFromContext(ctx context.Context) FormatOptions {
+	opts, _ := ctx.Value(formatOptionsKey{}).(FormatOptions)
+	return opts
+}
+
+// checkFormatOptions returns an error if the format options of ctx are
+// not valid for formatting a filesystem of type fsType, ext4 if empty.
+func checkFormatOptions(ctx context.Context, fsType string) error {
+	if fsType == "" {
+		fsType = "ext4"
+	}
+	_, err := formatOptionsFromContext(ctx).mkfsArgs(fsType)
+	return err
+}
+
+// lineWriter is an io.Writer that calls a function for each line written.
+// Carriage returns and backspaces, which mkfs uses to redraw its progress,
+// end lines as well. The lineWriters sharing a mutex call their function
+// one at a time.
+type lineWriter struct {
+	mu   *sync.Mutex
+	fn   func(string)
+	line bytes.Buffer
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, b := range p {
+		switch b {
+		case '\n', '\r', '\b':
+			w.flushLocked()
+		default:
+			w.line.WriteByte(b)
+		}
+	}
+	return len(p), nil
+}
+
+// Flush calls the function with the last line if it is not terminated.
+func (w *lineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushLocked()
+}
+
+func (w *lineWriter) flushLocked() {
+	if line := strings.TrimSpace(w.line.String()); line != "" {
+		w.fn(line)
+	}
+	w.line.Reset()
+}
+
+// FormatError is returned when formatting a device fails.
+type FormatError struct {
+	// Device is the device that was formatted.
+	Device string
+	// Command is the mkfs command, e.g. mkfs.xfs.
+	Command string
+	// Args are the arguments passed to the command.
+	Args []string
+	// ExitCode is the exit code of the command, -1 if it did not run.
+	ExitCode int
+	// Stderr is the captured standard error of the command.
+	Stderr string
+	// Err is the underlying error.
+	Err error
+	// MountErr is the error of the mount attempted after the failed
+	// format, if any.
+	MountErr error
+}
+
+func (e *FormatError) Error() string {
+	msg := fmt.Sprintf("format of %s failed: %s %s: exit code %d: %v",
+		e.Device, e.Command, strings.Join(e.Args, " "), e.ExitCode, e.Err)
+	if e.Stderr != "" {
+		msg += ": " + e.Stderr
+	}
+	if e.MountErr != nil {
+		msg += fmt.Sprintf("; mount after format failed: %v", e.MountErr)
+	}
+	return msg
+}
+
+func (e *FormatError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,242 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunMkfs(t *testing.T) {
+	bin := t.TempDir()
+	script := "#!/bin/sh\necho \"mkfs.fake: $1 is apparently in use by the system\" >&2\nexit 1\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "mkfs.fake"), []byte(script), 0o700)) // #nosec G306
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	fs := NewFS(FSOptions{})
+	formatErr := fs.runMkfs(context.Background(), "/dev/sdz", "fake", []string{"-F", "/dev/sdz"})
+	require.NotNil(t, formatErr)
+	assert.Equal(t, "/dev/sdz", formatErr.Device)
+	assert.Equal(t, "mkfs.fake", formatErr.Command)
+	assert.Equal(t, 1, formatErr.ExitCode)
+	assert.Equal(t, "mkfs.fake: -F is apparently in use by the system", formatErr.Stderr)
+	var exitErr *exec.ExitError
+	assert.True(t, errors.As(formatErr, &exitErr))
+	assert.Contains(t, formatErr.Error(), "apparently in use")
+
+	formatErr = fs.runMkfs(context.Background(), "/dev/sdz", "missing", []string{"/dev/sdz"})
+	require.NotNil(t, formatErr)
+	assert.Equal(t, -1, formatErr.ExitCode)
+
+	formatErr.MountErr = errors.New("mount failed")
+	assert.Contains(t, formatErr.Error(), "mount after format failed: mount failed")
+}
+
+func TestRunMkfsOptions(t *testing.T) {
+	bin := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"echo \"meta-data=$1\"\n" +
+		"printf 'Writing inode tables: 1/4\\b\\b\\b2/4\\rdone' >&2\n" +
+		"[ \"$1\" = slow ] && exec sleep 5\n" +
+		"exit 0\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "mkfs.fake"), []byte(script), 0o700)) // #nosec G306
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	var lines []string
+	ctx := WithFormatOptions(context.Background(), FormatOptions{
+		Output: func(line string) { lines = append(lines, line) },
+	})
+	fs := NewFS(FSOptions{})
+	require.Nil(t, fs.runMkfs(ctx, "/dev/sdz", "fake", []string{"/dev/sdz"}))
+	assert.ElementsMatch(t, []string{"meta-data=/dev/sdz", "Writing inode tables: 1/4", "2/4", "done"}, lines)
+
+	fs.FormatTimeout = 100 * time.Millisecond
+	start := time.Now()
+	formatErr := fs.runMkfs(context.Background(), "/dev/sdz", "fake", []string{"slow"})
+	require.NotNil(t, formatErr)
+	assert.True(t, errors.Is(formatErr, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), 4*time.Second)
+
+	// The timeout of the operation overrides the default.
+	ctx = WithFormatOptions(context.Background(), FormatOptions{Timeout: 10 * time.Second})
+	fs.FormatTimeout = time.Nanosecond
+	assert.Nil(t, fs.runMkfs(ctx, "/dev/sdz", "fake", []string{"/dev/sdz"}))
+}
+
+func TestFormatAndMountForeignSignature(t *testing.T) {
+	ctx := context.Background()
+	bin := t.TempDir()
+	log := filepath.Join(t.TempDir(), "log")
+	// lsblk reports no format, as without a udev database, and the mount
+	// fails.
+	writeRecorder(t, filepath.Join(bin, "lsblk"), log)
+	writeRecorder(t, filepath.Join(bin, "mkfs.ext4"), log)
+	script := "#!/bin/sh\necho \"$*\" >> " + log + "\nexit 32\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "mount"), []byte(script), 0o700)) // #nosec G306
+	fs := NewFS(FSOptions{ExtraEnv: []string{"PATH=" + bin}})
+
+	for name, data := range map[string][]byte{
+		DiskFormatLUKS: append([]byte("LUKS\xba\xbe\x00\x02"), make([]byte, 4096)...),
+		DiskFormatLVM:  lvmLabel(1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			device := filepath.Join(t.TempDir(), "sdz")
+			require.NoError(t, os.WriteFile(device, data, 0o600))
+			require.NoError(t, os.WriteFile(log, nil, 0o600))
+
+			format, err := fs.getDiskFormat(ctx, device)
+			require.NoError(t, err)
+			assert.Equal(t, name, format)
+
+			err = fs.formatAndMount(ctx, device, t.TempDir(), "ext4")
+			require.ErrorIs(t, err, ErrForeignSignature)
+			var sigErr *ForeignSignatureError
+			require.True(t, errors.As(err, &sigErr))
+			assert.Equal(t, device, sigErr.Device)
+			assert.Equal(t, name, sigErr.Signature)
+			out, err := os.ReadFile(log)
+			require.NoError(t, err)
+			assert.NotContains(t, string(out), "-F "+device)
+		})
+	}
+}
+
+func TestRunMkfsUUID(t *testing.T) {
+	const uuid = "3f1b2c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d"
+	fs := NewFS(FSOptions{DryRun: true})
+	ctx := WithFormatOptions(context.Background(), FormatOptions{UUID: strings.ToUpper(uuid)})
+
+	tests := []struct {
+		fsType string
+		args   []string
+		want   []string
+	}{
+		{"ext4", []string{"-F", "/dev/sdz"}, []string{"-F", "-U", uuid, "/dev/sdz"}},
+		{"ext3", []string{"-F", "-E", "nodiscard", "/dev/sdz"}, []string{"-F", "-E", "nodiscard", "-U", uuid, "/dev/sdz"}},
+		{"xfs", []string{"/dev/sdz", "-m", "crc=0"}, []string{"-m", "uuid=" + uuid, "/dev/sdz", "-m", "crc=0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fsType, func(t *testing.T) {
+			fs.ClearDryRunActions()
+			require.Nil(t, fs.runMkfs(ctx, "/dev/sdz", tt.fsType, tt.args))
+			actions := fs.GetDryRunActions()
+			require.Len(t, actions, 1)
+			assert.Equal(t, tt.want, actions[0].Args)
+		})
+	}
+
+	fs.ClearDryRunActions()
+	formatErr := fs.runMkfs(ctx, "/dev/sdz", FsTypeGFS2, []string{"/dev/sdz"})
+	require.NotNil(t, formatErr)
+	assert.ErrorIs(t, formatErr, ErrFormatOptionNotSupported)
+	assert.Equal(t, CodeNotSupported, classifyError(formatErr))
+
+	ctx = WithFormatOptions(context.Background(), FormatOptions{UUID: "not-a-uuid"})
+	formatErr = fs.runMkfs(ctx, "/dev/sdz", "ext4", []string{"/dev/sdz"})
+	require.NotNil(t, formatErr)
+	assert.ErrorIs(t, formatErr, ErrInvalidFormatOptions)
+	assert.Empty(t, fs.GetDryRunActions())
+
+	// FormatAndMount fails before mounting or formatting.
+	err := fs.formatAndMount(ctx, "/dev/sdz", t.TempDir(), "")
+	assert.ErrorIs(t, err, ErrInvalidFormatOptions)
+	assert.Empty(t, fs.GetDryRunActions())
+}
+
+func TestXFSNoUUIDRetry(t *testing.T) {
+	bin := t.TempDir()
+	log := filepath.Join(t.TempDir(), "log")
+	// mount(8) only reports an invalid argument for a duplicate UUID.
+	mount := "#!/bin/sh\n" +
+		"case \"$*\" in *nouuid*) echo nouuid >> " + log + "; exit 0;; esac\n" +
+		"echo failed >> " + log + "\n" +
+		"echo 'mount: /mnt: wrong fs type, bad option, bad superblock on /dev/sdz.'\n" +
+		"exit 32\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "mount"), []byte(mount), 0o700)) // #nosec G306
+	blkid := "#!/bin/sh\nfor a; do dev=$a; done\ncase $dev in\n" +
+		"/dev/sdb|/dev/sdz) echo 0f6e5c4d-0000-4000-8000-000000000001 ;;\n" +
+		"/dev/sdy) echo 0f6e5c4d-0000-4000-8000-000000000002 ;;\n" +
+		"*) exit 2 ;;\nesac\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "blkid"), []byte(blkid), 0o700)) // #nosec G306
+	procRoot := t.TempDir()
+	mountinfo := "21 1 8:1 / / rw,relatime - ext4 /dev/sda1 rw\n" +
+		"3002 21 8:16 / /mnt/data rw,relatime - xfs /dev/sdb rw\n"
+	require.NoError(t, os.MkdirAll(filepath.Join(procRoot, "self"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(procRoot, "self", "mountinfo"), []byte(mountinfo), 0o600))
+
+	tests := []struct {
+		source string
+		mounts string
+	}{
+		// A clone of the mounted /dev/sdb is mounted with nouuid.
+		{"/dev/sdz", "failed\nnouuid\n"},
+		// Other failures are not retried.
+		{"/dev/sdy", "failed\n"},
+		{"/dev/sdx", "failed\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(log, nil, 0o600))
+			fs := NewFS(FSOptions{
+				MountBinary:    filepath.Join(bin, "mount"),
+				ExtraEnv:       []string{"PATH=" + bin},
+				ProcRoot:       procRoot,
+				XFSNoUUIDRetry: true,
+			})
+			err := fs.Mount(context.Background(), tt.source, t.TempDir(), "xfs")
+			if strings.Contains(tt.mounts, "nouuid") {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, "wrong fs type")
+			}
+			out, err := os.ReadFile(log)
+			require.NoError(t, err)
+			assert.Equal(t, tt.mounts, string(out))
+		})
+	}
+}
+
+func TestDiskFormatCacheSkipsFiles(t *testing.T) {
+	bin := t.TempDir()
+	lsblk := "#!/bin/sh\nfor a; do dev=$a; done\ncase $dev in\n*/a.img) echo xfs ;;\n*/b.img) echo ext4 ;;\nesac\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "lsblk"), []byte(lsblk), 0o700)) // #nosec G306
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.img"), filepath.Join(dir, "b.img")
+	require.NoError(t, os.WriteFile(a, nil, 0o600))
+	require.NoError(t, os.WriteFile(b, nil, 0o600))
+	fs := NewFS(FSOptions{ExtraEnv: []string{"PATH=" + bin}, DiskFormatCacheTTL: time.Minute})
+	ctx := context.Background()
+
+	// Files all have device number 0 and are not cached.
+	_, err := deviceNumber(a)
+	assert.Error(t, err)
+	format, err := fs.getDiskFormat(ctx, a)
+	require.NoError(t, err)
+	assert.Equal(t, "xfs", format)
+	format, err = fs.getDiskFormat(ctx, b)
+	require.NoError(t, err)
+	assert.Equal(t, "ext4", format)
+	assert.Empty(t, fs.formatCache.entries)
+}
@@ -14,17 +14,216 @@ package gofsutil
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"time"
 
-	"golang.org/x/sys/unix"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const (
+	defaultSysRoot  = "/sys"
+	defaultDevRoot  = "/dev"
+	defaultProcRoot = "/proc"
+	defaultEtcRoot  = "/etc"
+)
+
+// FSOptions contains the settings that control how an FS instance
+// accesses the host.
+//
+// The root directories allow a process that has the host filesystem
+// mounted somewhere other than "/", e.g. a CSI node plugin with the host
+// bind-mounted at /noderoot, to inspect the host's sysfs, devfs, procfs and
+// configuration without having to chroot. An empty value means the
+// standard location.
+type FSOptions struct {
+	// SysRoot is the location of the sysfs mount, e.g. /noderoot/sys.
+	SysRoot string
+	// DevRoot is the location of the devfs mount, e.g. /noderoot/dev.
+	DevRoot string
+	// ProcRoot is the location of the procfs mount, e.g. /noderoot/proc.
+	ProcRoot string
+	// EtcRoot is the location of the host's /etc, e.g. /noderoot/etc.
+	EtcRoot string
+	// DisableOperationLocks turns off the serialization of mount, unmount,
+	// format and resize operations that target the same path or device.
+	DisableOperationLocks bool
+	// DiskFormatCacheTTL enables caching the filesystem type reported by
+	// GetDiskFormat for formatted devices for the given duration. Caching
+	// is disabled when zero. Formatting a device invalidates its entry.
+	DiskFormatCacheTTL time.Duration
+	// SystemdRunScope runs mount commands in a transient systemd scope
+	// with systemd-run --scope, so that mount helpers, e.g. FUSE daemons,
+	// are not part of the caller's cgroup and survive its restart. It has
+	// no effect on hosts that do not run systemd.
+	SystemdRunScope bool
+	// XFSNoUUIDRetry retries an xfs mount that failed because of a
+	// duplicate UUID with the nouuid option, so that clones and snapshots
+	// of a mounted xfs filesystem, which share its UUID, can be mounted.
+	XFSNoUUIDRetry bool
+	// AbortOnFormatError makes FormatAndMount return as soon as formatting
+	// the device fails, instead of attempting to mount it once more.
+	AbortOnFormatError bool
+	// MaxConcurrentMounts is the number of mounts MountMany runs
+	// concurrently, DefaultMaxConcurrentMounts if zero.
+	MaxConcurrentMounts int
+	// DiskUsageWorkers is the number of subdirectories DiskUsage walks in
+	// parallel. DiskUsage walks sequentially if it is zero or one.
+	DiskUsageWorkers int
+	// CheckDeviceUnused makes FormatAndMount and Format check with
+	// EnsureDeviceUnused that the device is not in use before formatting
+	// it, and fail with an InUseError if it is.
+	CheckDeviceUnused bool
+	// FormatTimeout is how long mkfs may run before it is killed. There is
+	// no limit if zero. It is overridden by FormatOptions.Timeout.
+	FormatTimeout time.Duration
+	// EnableQuotaOnMount makes FormatAndMount mount xfs and ext4
+	// filesystems with project quotas enabled, and format ext4 filesystems
+	// with the quota and project features, see SetProjectQuota.
+	EnableQuotaOnMount bool
+	// MountBinary is the mount command, e.g. /usr/sbin/mount. When empty,
+	// mount is looked up in the PATH.
+	MountBinary string
+	// UmountBinary, when set, is the command used to unmount, e.g.
+	// /usr/sbin/umount, instead of the umount2 system call.
+	UmountBinary string
+	// CommandHistorySize is the number of external commands, with their
+	// arguments, duration, exit code and output, kept for
+	// GetCommandHistory. No history is kept if zero.
+	CommandHistorySize int
+	// ExtraEnv are environment variables, in the form "KEY=value", added
+	// to the environment of all the commands run. A PATH set here is also
+	// used to look up the commands.
+	ExtraEnv []string
+	// Chroot is the root directory of the host, e.g. /noderoot, in which
+	// all the commands, e.g. mount, mkfs and resize2fs, are run with
+	// /usr/sbin/chroot, so that a container without these utilities can
+	// use those of the host. The paths passed to the commands are
+	// resolved in the chroot.
+	Chroot string
+	// HostMountNamespace runs all the commands in the mount namespace of
+	// the host with nsenter --target 1 --mount, so that mounts land in the
+	// host namespace even without mount propagation. Unmounts then use the
+	// umount command, and the mounts are read from the mountinfo of the
+	// target process. It requires the host PID namespace and takes
+	// precedence over Chroot.
+	HostMountNamespace bool
+	// HostNamespacePID is the process whose mount namespace is entered
+	// with HostMountNamespace, 1 if zero.
+	HostNamespacePID int
+	// DisallowShell makes commands run through a shell, e.g. bash -c,
+	// fail with ErrShellDisallowed instead of running. The package itself
+	// runs all commands with their arguments and never uses a shell.
+	DisallowShell bool
+	// DryRun makes the operations record the actions that change the
+	// host, e.g. the mount, mkfs and umount commands, unmounts, sysfs
+	// writes and the mount targets and files created or removed, instead
+	// of taking them; GetDryRunActions returns them.
+	// Commands that only report state, e.g. lsblk, still run so that the
+	// operations can plan their actions.
+	DryRun bool
+	// JournalDir is the directory in which FormatAndMount and
+	// CleanupDeviceForWWN record their steps until they return, so that
+	// ReplayIncompleteOperations can finish or roll back the operations
+	// interrupted by a crash or restart. There is no journal if empty.
+	JournalDir string
+	// JournalRollback lets ReplayIncompleteOperations wipe the partial
+	// filesystem of an interrupted format. Without it such operations
+	// fail to replay and are kept in the journal.
+	JournalRollback bool
+	// JournalMaxAge is how long ReplayIncompleteOperations keeps an
+	// operation that fails to replay, e.g. because its volume was deleted
+	// or is not attached anymore. Older ones are removed from the journal.
+	// They are kept until they are replayed if zero.
+	JournalMaxAge time.Duration
+	// DisableFullRescan stops RescanSCSIHost and the other rescans from
+	// rescanning all the scsi hosts when no host is related to the
+	// targets; nothing is rescanned instead.
+	DisableFullRescan bool
+	// FullRescanInterval is the minimum time between two rescans of all
+	// the scsi hosts, DefaultFullRescanInterval if zero. A full rescan
+	// requested sooner is skipped. There is no limit if negative.
+	FullRescanInterval time.Duration
+	// MountWatchInterval is how often SubscribeMountChanges reads the
+	// mount table when the kernel does not signal a change,
+	// DefaultMountWatchInterval if zero.
+	MountWatchInterval time.Duration
+}
+
 // FS provides many filesystem-specific functions, such as mount, format, etc.
 type FS struct {
+	FSOptions
 	// ScanEntry is the function used to process mount table entries.
 	ScanEntry EntryScanFunc
 	// SysBlockDir is used to set the directory of block devices.
+	// When empty, the block directory under SysRoot is used.
 	SysBlockDir string
+
+	// locks serializes operations on the same target path or device.
+	locks keyedMutex
+	// formatCache holds the formats of devices when DiskFormatCacheTTL is set.
+	formatCache diskFormatCache
+	// fsTypes are the filesystem types that may be mounted and formatted.
+	fsTypes fsTypeSet
+	// mountDefaults are the default mount options per filesystem type.
+	mountDefaults mountOptionDefaults
+	// history holds the last CommandHistorySize commands run.
+	history commandHistory
+	// dryRunActions holds the actions recorded with DryRun.
+	dryRunActions dryRunLog
+	// fullRescans limits the rescans of all the scsi hosts.
+	fullRescans fullRescanLimiter
+	// tracer creates the spans of the operations, nil until
+	// SetTracerProvider is called.
+	tracer atomic.Pointer[fsTracer]
+}
+
+// NewFS returns an FS that uses the provided options.
+func NewFS(opts FSOptions) *FS {
+	return &FS{FSOptions: opts, ScanEntry: defaultEntryScanFunc}
+}
+
+// rootedPath moves p, an absolute path below defaultRoot, below root.
+// Paths outside of defaultRoot and empty roots leave p unchanged.
+func rootedPath(root, defaultRoot, p string) string {
+	if root == "" || root == defaultRoot {
+		return p
+	}
+	if p != defaultRoot && !strings.HasPrefix(p, defaultRoot+"/") {
+		return p
+	}
+	return filepath.Join(root, strings.TrimPrefix(p, defaultRoot))
+}
+
+// sysPath returns the location of the sysfs path p, e.g. /sys/class/fc_host.
+func (fs *FS) sysPath(p string) string {
+	return rootedPath(fs.SysRoot, defaultSysRoot, p)
+}
+
+// devPath returns the location of the devfs path p, e.g. /dev/disk/by-id.
+func (fs *FS) devPath(p string) string {
+	return rootedPath(fs.DevRoot, defaultDevRoot, p)
+}
+
+// procPath returns the location of the procfs path p, e.g. /proc/self/mountinfo.
+func (fs *FS) procPath(p string) string {
+	return rootedPath(fs.ProcRoot, defaultProcRoot, p)
+}
+
+// etcPath returns the location of the configuration file p, e.g.
+// /etc/nvme/hostnqn.
+func (fs *FS) etcPath(p string) string {
+	return rootedPath(fs.EtcRoot, defaultEtcRoot, p)
+}
+
+// sysBlockDir returns the directory containing the block devices.
+func (fs *FS) sysBlockDir() string {
+	if fs.SysBlockDir != "" {
+		return fs.SysBlockDir
+	}
+	return fs.sysPath("/sys/block")
 }
 
 // GetDiskFormat uses 'lsblk' to see if the given disk is unformatted.
@@ -37,16 +236,42 @@ func (fs *FS) FormatAndMount(
 	ctx context.Context,
 	source, target, fsType string,
 	options ...string,
-) error {
+) (err error) {
+	ctx, end := fs.startSpan(ctx, "FormatAndMount",
+		attrDevice.String(source), attrTarget.String(target), attrFsType.String(fsType))
+	defer end(&err)
+	defer wrapOpError(&err, "format and mount", target, CodeMountFailed)
+	unlock, err := fs.lockPaths(ctx, target, deviceKey(source))
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	return fs.formatAndMount(ctx, source, target, fsType, options...)
 }
 
+// FormatAndMountMPath waits until the multipath device has the required
+// number of active paths, then formats and mounts it like FormatAndMount.
+// The device may be given by name, e.g. mpatha, or path.
+func (fs *FS) FormatAndMountMPath(
+	ctx context.Context,
+	mpathDevice, target, fsType string,
+	opts MPathFormatOptions,
+) error {
+	return fs.formatAndMountMPath(ctx, mpathDevice, target, fsType, opts)
+}
+
 // Format uses unix utils to format the given disk.
 func (fs *FS) Format(
 	ctx context.Context,
 	source, target, fsType string,
 	options ...string,
-) error {
+) (err error) {
+	defer wrapOpError(&err, "format", source, CodeFormatFailed)
+	unlock, err := fs.lockPaths(ctx, deviceKey(source))
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	return fs.format(ctx, source, target, fsType, options...)
 }
 
@@ -63,27 +288,64 @@ func (fs *FS) Mount(
 	ctx context.Context,
 	source, target, fsType string,
 	options ...string,
-) error {
+) (err error) {
+	ctx, end := fs.startSpan(ctx, "Mount",
+		attrDevice.String(source), attrTarget.String(target), attrFsType.String(fsType))
+	defer end(&err)
+	defer wrapOpError(&err, "mount", target, CodeMountFailed)
+	unlock, err := fs.lockPaths(ctx, target, deviceKey(source))
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	return fs.mount(ctx, source, target, fsType, options...)
 }
 
+// MountWithOptions mounts source onto target like Mount. When
+// opts.CreateTarget is set the target directory is created first, and
+// removed again if the mount fails. A *TargetNotDirectoryError is returned
+// if the target exists but is not a directory.
+func (fs *FS) MountWithOptions(ctx context.Context, source, target, fsType string, opts MountOptions) (err error) {
+	defer wrapOpError(&err, "mount", target, CodeMountFailed)
+	unlock, err := fs.lockPaths(ctx, target, deviceKey(source))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.mountWithOptions(ctx, source, target, fsType, opts)
+}
+
 // BindMount behaves like Mount was called with a "bind" flag set
 // in the options list.
 func (fs *FS) BindMount(
 	ctx context.Context,
 	source, target string,
 	options ...string,
-) error {
+) (err error) {
+	defer wrapOpError(&err, "bind mount", target, CodeMountFailed)
 	if options == nil {
 		options = []string{"bind"}
 	} else {
 		options = append(options, "bind")
 	}
+	unlock, err := fs.lockPaths(ctx, target, deviceKey(source))
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	return fs.mount(ctx, source, target, "", options...)
 }
 
 // Unmount unmounts the target.
-func (fs *FS) Unmount(ctx context.Context, target string) error {
+func (fs *FS) Unmount(ctx context.Context, target string) (err error) {
+	ctx, end := fs.startSpan(ctx, "Unmount", attrTarget.String(target))
+	defer end(&err)
+	defer wrapOpError(&err, "unmount", target, CodeUnmountFailed)
+	unlock, err := fs.lockPaths(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	return fs.unmount(ctx, target)
 }
 
@@ -102,10 +364,37 @@ func (fs *FS) ResizeFS(
 	ctx context.Context,
 	volumePath, devicePath, ppathDevice,
 	mpathDevice, fsType string,
-) error {
+) (err error) {
+	ctx, end := fs.startSpan(ctx, "ResizeFS",
+		attrDevice.String(devicePath), attrTarget.String(volumePath), attrFsType.String(fsType))
+	defer end(&err)
+	defer wrapOpError(&err, "resize", volumePath, CodeResizeFailed)
+	unlock, err := fs.lockPaths(ctx, volumePath, devicePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	return fs.resizeFS(ctx, volumePath, devicePath, ppathDevice, mpathDevice, fsType)
 }
 
+// ResizeFSWithOptions expands the filesystem to the new size of underlying
+// device unless it already fills the device, in which case it returns
+// ErrNoResizeNeeded. It returns the size of the filesystem in bytes.
+func (fs *FS) ResizeFSWithOptions(
+	ctx context.Context,
+	volumePath, devicePath, ppathDevice,
+	mpathDevice, fsType string,
+	opts ResizeFSOptions,
+) (size int64, err error) {
+	defer wrapOpError(&err, "resize", volumePath, CodeResizeFailed)
+	unlock, err := fs.lockPaths(ctx, volumePath, devicePath)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	return fs.resizeFSWithOptions(ctx, volumePath, devicePath, ppathDevice, mpathDevice, fsType, opts)
+}
+
 // FindFSType fetches the filesystem type on mountpoint
 func (fs *FS) FindFSType(
 	ctx context.Context, mountpoint string,
@@ -114,17 +403,27 @@ func (fs *FS) FindFSType(
 }
 
 // ResizeMultipath resizes the multipath devices mounted on FS
-func (fs *FS) ResizeMultipath(ctx context.Context, deviceName string) error {
+func (fs *FS) ResizeMultipath(ctx context.Context, deviceName string) (err error) {
+	defer wrapOpError(&err, "resize multipath", deviceName, CodeResizeFailed)
 	return fs.resizeMultipath(ctx, deviceName)
 }
 
 // DeviceRescan rescan the device for size alterations
 func (fs *FS) DeviceRescan(ctx context.Context,
 	devicePath string,
-) error {
+) (err error) {
+	defer wrapOpError(&err, "rescan", devicePath, CodeRescanFailed)
 	return fs.deviceRescan(ctx, devicePath)
 }
 
+// WaitForDeviceToSettle waits until follow-up operations on the device,
+// e.g. a filesystem resize, are safe after a rescan or resize: udev has
+// no pending events, the size of the device is stable and the device can
+// be opened. It waits DefaultDeviceSettleTimeout if ctx has no deadline.
+func (fs *FS) WaitForDeviceToSettle(ctx context.Context, device string) error {
+	return fs.waitForDeviceToSettle(ctx, device)
+}
+
 // GetMounts returns a slice of all the mounted filesystems.
 //
 // * Linux hosts use mount_namespaces to obtain mount information.
@@ -142,6 +441,20 @@ func (fs *FS) GetMounts(ctx context.Context) ([]Info, error) {
 	return fs.getMounts(ctx)
 }
 
+// GetMountsByKind returns the mounts of the given kinds, or of all kinds if
+// none is given. Unlike GetMounts it considers every entry of the mount
+// table, e.g. overlay and tmpfs mounts, and on Linux it also returns the
+// active swaps, with an empty Path.
+func (fs *FS) GetMountsByKind(ctx context.Context, kinds ...MountKind) ([]Info, error) {
+	return fs.getMountsByKind(ctx, kinds...)
+}
+
+// ScanProcMounts calls fn for each entry of the mount table, with only
+// the selected fields filled in, until fn returns false or an error.
+func (fs *FS) ScanProcMounts(ctx context.Context, fields MountEntryField, fn MountEntryFunc) error {
+	return fs.scanProcMounts(ctx, fields, fn)
+}
+
 // GetDevMounts returns a slice of all mounts for the provided device.
 func (fs *FS) GetDevMounts(ctx context.Context, dev string) ([]Info, error) {
 	return fs.getDevMounts(ctx, dev)
@@ -166,10 +479,19 @@ func (fs *FS) WWNToDevicePath(ctx context.Context, wwn string) (string, string,
 // If targets are specified, only hosts who are related to the specified
 // iqn target(s) are rescanned.
 // If lun is specified, then the rescan is for that particular volume.
-func (fs *FS) RescanSCSIHost(ctx context.Context, targets []string, lun string) error {
+func (fs *FS) RescanSCSIHost(ctx context.Context, targets []string, lun string) (err error) {
+	ctx, end := fs.startSpan(ctx, "RescanSCSIHost", attrTargets.StringSlice(targets), attrLUN.String(lun))
+	defer end(&err)
+	defer wrapOpError(&err, "rescan", strings.Join(targets, ","), CodeRescanFailed)
 	return fs.rescanSCSIHost(ctx, targets, lun)
 }
 
+// RescanSCSIHostX performs the same rescan as RescanSCSIHost and returns
+// a report of the hosts rescanned and the new devices discovered.
+func (fs *FS) RescanSCSIHostX(ctx context.Context, targets []string, lun string) (*RescanReport, error) {
+	return fs.rescanSCSIHostX(ctx, targets, lun)
+}
+
 // RemoveBlockDevice removes a block device by getting the device name
 // from the last component of the blockDevicePath and then removing the
 // device by writing '1' to /sys/block{deviceName}/device/delete
@@ -180,41 +502,12 @@ func (fs *FS) RemoveBlockDevice(ctx context.Context, blockDevicePath string) err
 // MultipathCommand executes the multipath command with a timeout and various arguments.
 // Optionally a chroot directory can be specified for changing root directory.
 // This only works in a container or another environment where it can chroot to /noderoot.
-func (fs *FS) MultipathCommand(ctx context.Context, timeoutSeconds time.Duration, chroot string, arguments ...string) ([]byte, error) {
+func (fs *FS) MultipathCommand(ctx context.Context, timeoutSeconds time.Duration, chroot string, arguments ...string) (_ []byte, err error) {
+	ctx, end := fs.startSpan(ctx, "MultipathCommand", attrArgs.StringSlice(arguments))
+	defer end(&err)
 	return fs.multipathCommand(ctx, timeoutSeconds, chroot, arguments...)
 }
 
-// fsInfo linux returns (available bytes, byte capacity, byte usage, total inodes, inodes free, inode usage, error)
-// for the filesystem that path resides upon.
-func (fs *FS) fsInfo(_ context.Context, path string) (int64, int64, int64, int64, int64, int64, error) {
-	statfs := &unix.Statfs_t{}
-	err := unix.Statfs(path, statfs)
-	if err != nil {
-		return 0, 0, 0, 0, 0, 0, err
-	}
-
-	// Available is blocks available * fragment size
-	// #nosec G115
-	available := int64(statfs.Bavail) * statfs.Bsize
-
-	// Capacity is total block count * fragment size
-	// #nosec G115
-	capacity := int64(statfs.Blocks) * statfs.Bsize
-
-	// Usage is block being used * fragment size (aka block size).
-	// #nosec G115
-	usage := (int64(statfs.Blocks) - int64(statfs.Bfree)) * statfs.Bsize
-
-	// #nosec G115
-	inodes := int64(statfs.Files)
-
-	// #nosec G115
-	inodesFree := int64(statfs.Ffree)
-	inodesUsed := inodes - inodesFree
-
-	return available, capacity, usage, inodes, inodesFree, inodesUsed, nil
-}
-
 // TargetIPLUNToDevicePath returns the /dev/devxxx path when presented with an ISCSI target IP
 // and a LUN id. It returns the entry name in /dev/disk/by-path and the device path, along with error.
 func (fs *FS) TargetIPLUNToDevicePath(ctx context.Context, targetIP string, lunID int) (map[string]string, error) {
@@ -245,3 +538,717 @@ func (fs *FS) FsInfo(ctx context.Context, path string) (int64, int64, int64, int
 func (fs *FS) GetNVMeController(device string) (string, error) {
 	return fs.getNVMeController(device)
 }
+
+// ConnectNVMeTarget connects to an NVMe over fabrics subsystem.
+func (fs *FS) ConnectNVMeTarget(ctx context.Context, transport, traddr, trsvcid, nqn string, opts NVMeConnectOptions) error {
+	return fs.connectNVMeTarget(ctx, transport, traddr, trsvcid, nqn, opts)
+}
+
+// DisconnectNVMeTarget disconnects all controllers of an NVMe subsystem.
+func (fs *FS) DisconnectNVMeTarget(ctx context.Context, nqn string) error {
+	return fs.disconnectNVMeTarget(ctx, nqn)
+}
+
+// GetNVMeControllersForSubsystemNQN returns the controllers of the NVMe
+// subsystem with the given NQN.
+func (fs *FS) GetNVMeControllersForSubsystemNQN(ctx context.Context, nqn string) ([]NVMeController, error) {
+	return fs.getNVMeControllersForSubsystemNQN(ctx, nqn)
+}
+
+// DMSuspend suspends I/O to a device mapper device.
+func (fs *FS) DMSuspend(ctx context.Context, name string) error {
+	return fs.dmSuspend(ctx, name)
+}
+
+// DMResume resumes I/O to a device mapper device.
+func (fs *FS) DMResume(ctx context.Context, name string) error {
+	return fs.dmResume(ctx, name)
+}
+
+// GetDMTable returns the table of a device mapper device.
+func (fs *FS) GetDMTable(ctx context.Context, name string) (*DMTable, error) {
+	return fs.getDMTable(ctx, name)
+}
+
+// IsMountPoint returns true if path is a mount point.
+func (fs *FS) IsMountPoint(ctx context.Context, path string) (bool, error) {
+	return fs.isMountPoint(ctx, path)
+}
+
+// IsLikelyNotMountPoint quickly determines whether path is not a mount point.
+func (fs *FS) IsLikelyNotMountPoint(ctx context.Context, path string) (bool, error) {
+	return fs.isLikelyNotMountPoint(ctx, path)
+}
+
+// IsCorruptedMount returns true if the mount at target is corrupted or stale.
+func (fs *FS) IsCorruptedMount(ctx context.Context, target string) (bool, error) {
+	return fs.isCorruptedMount(ctx, target)
+}
+
+// MultipathdStatus reports whether multipathd is installed, running and
+// responsive.
+func (fs *FS) MultipathdStatus(ctx context.Context) (*MultipathdHealth, error) {
+	return fs.multipathdStatus(ctx)
+}
+
+// RegenerateXFSUUID gives the unmounted xfs filesystem on device a new
+// random UUID.
+func (fs *FS) RegenerateXFSUUID(ctx context.Context, device string) error {
+	unlock, err := fs.lockPaths(ctx, deviceKey(device))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.regenerateXFSUUID(ctx, device)
+}
+
+// RegenerateExtUUID gives the unmounted ext3 or ext4 filesystem on device
+// a new random UUID.
+func (fs *FS) RegenerateExtUUID(ctx context.Context, device string) error {
+	unlock, err := fs.lockPaths(ctx, deviceKey(device))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.regenerateExtUUID(ctx, device)
+}
+
+// MakeBlockFile creates the file target, and its parent directories, for
+// a raw block volume to be published to.
+func (fs *FS) MakeBlockFile(ctx context.Context, target string) error {
+	return fs.makeBlockFile(ctx, target)
+}
+
+// BindMountBlockDevice validates the block device and bind mounts it onto
+// the file target, creating the file if needed.
+func (fs *FS) BindMountBlockDevice(ctx context.Context, device, target string, options ...string) error {
+	unlock, err := fs.lockPaths(ctx, target, deviceKey(device))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.bindMountBlockDevice(ctx, device, target, options...)
+}
+
+// UnmountBlockDevice unmounts the block device bind mounted onto target,
+// if any, and removes the file.
+func (fs *FS) UnmountBlockDevice(ctx context.Context, target string) error {
+	unlock, err := fs.lockPaths(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.unmountBlockDevice(ctx, target)
+}
+
+// CheckNFSExport probes whether the NFS service of server is reachable
+// and exportPath is exported.
+func (fs *FS) CheckNFSExport(ctx context.Context, server, exportPath string) (*NFSExportStatus, error) {
+	return fs.checkNFSExport(ctx, server, exportPath)
+}
+
+// MountMany performs the mount requests concurrently and returns a result
+// per request, in the order of the requests.
+func (fs *FS) MountMany(ctx context.Context, reqs []MountRequest) []MountResult {
+	return mountMany(ctx, reqs, fs.MaxConcurrentMounts, func(ctx context.Context, req MountRequest) error {
+		if req.Bind {
+			return fs.BindMount(ctx, req.Source, req.Target, req.Options...)
+		}
+		return fs.Mount(ctx, req.Source, req.Target, req.FSType, req.Options...)
+	})
+}
+
+// DiskUsage computes the disk space and inodes used under path.
+func (fs *FS) DiskUsage(ctx context.Context, path string) (*DiskUsageInfo, error) {
+	return fs.diskUsage(ctx, path)
+}
+
+// SetProjectQuota assigns the directory path to the project projID and
+// limits the disk space of the project to limitBytes.
+func (fs *FS) SetProjectQuota(ctx context.Context, path string, projID uint32, limitBytes int64) error {
+	unlock, err := fs.lockPaths(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.setProjectQuota(ctx, path, projID, limitBytes)
+}
+
+// GetProjectQuota returns the usage and limit of the project projID on
+// the filesystem of path.
+func (fs *FS) GetProjectQuota(ctx context.Context, path string, projID uint32) (*ProjectQuota, error) {
+	return fs.getProjectQuota(ctx, path, projID)
+}
+
+// GetPartitions returns the partition table of the disk.
+func (fs *FS) GetPartitions(ctx context.Context, device string) (*PartitionTable, error) {
+	return fs.getPartitions(ctx, device)
+}
+
+// CreatePartition creates a GPT partition on the disk, and a GPT partition
+// table if the disk has none. It returns the partition created.
+func (fs *FS) CreatePartition(ctx context.Context, device string, spec PartitionSpec) (*Partition, error) {
+	unlock, err := fs.lockPaths(ctx, deviceKey(device))
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return fs.createPartition(ctx, device, spec)
+}
+
+// DeletePartition deletes the partition with the given number.
+func (fs *FS) DeletePartition(ctx context.Context, device string, number int) error {
+	unlock, err := fs.lockPaths(ctx, deviceKey(device))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.deletePartition(ctx, device, number)
+}
+
+// GrowPartition grows the partition with the given number into the free
+// space that follows it.
+func (fs *FS) GrowPartition(ctx context.Context, device string, number int) error {
+	unlock, err := fs.lockPaths(ctx, deviceKey(device))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.growPartition(ctx, device, number)
+}
+
+// GetMountsInto returns the mounted filesystems like GetMounts, but reuses
+// the capacity of infos, which is overwritten.
+func (fs *FS) GetMountsInto(ctx context.Context, infos []Info) ([]Info, error) {
+	return fs.getMountsInto(ctx, infos)
+}
+
+// GetNPIVPorts returns the NPIV virtual ports of the local FC hosts.
+func (fs *FS) GetNPIVPorts(ctx context.Context) ([]NPIVPort, error) {
+	return fs.getNPIVPorts(ctx)
+}
+
+// CreateNPIVPort creates an NPIV virtual port on the FC host. The port and
+// node names are generated if empty.
+func (fs *FS) CreateNPIVPort(ctx context.Context, host, wwpn, wwnn string) (*NPIVPort, error) {
+	unlock, err := fs.lockPaths(ctx, fcHostsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return fs.createNPIVPort(ctx, host, wwpn, wwnn)
+}
+
+// DeleteNPIVPort deletes the NPIV virtual port with the given port name
+// from the FC host.
+func (fs *FS) DeleteNPIVPort(ctx context.Context, host, wwpn string) error {
+	unlock, err := fs.lockPaths(ctx, fcHostsPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.deleteNPIVPort(ctx, host, wwpn)
+}
+
+// GetFCHostInfo returns the port names, port state, speed and fabric name
+// of the local FC hosts.
+func (fs *FS) GetFCHostInfo(ctx context.Context) ([]FCHostInfo, error) {
+	return fs.getFCHostInfo(ctx)
+}
+
+// GetISCSISessionState returns the state, connections and I/O error
+// counters of the iSCSI sessions to the target, or of all the sessions if
+// targetIQN is empty.
+func (fs *FS) GetISCSISessionState(ctx context.Context, targetIQN string) ([]ISCSISession, error) {
+	return fs.getISCSISessionState(ctx, targetIQN)
+}
+
+// GetHostNQN returns the NVMe host NQN, creating /etc/nvme/hostnqn if it
+// is missing and create is set.
+func (fs *FS) GetHostNQN(ctx context.Context, create bool) (string, error) {
+	unlock, err := fs.lockPaths(ctx, fs.etcPath(hostNQNPath))
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	return fs.getHostNQN(ctx, create)
+}
+
+// GetNQNHostID returns the NVMe host ID, creating /etc/nvme/hostid if it
+// is missing and create is set.
+func (fs *FS) GetNQNHostID(ctx context.Context, create bool) (string, error) {
+	unlock, err := fs.lockPaths(ctx, fs.etcPath(hostIDPath))
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	return fs.getNQNHostID(ctx, create)
+}
+
+// GetHostIQN returns the iSCSI initiator name, creating
+// /etc/iscsi/initiatorname.iscsi if it is missing and create is set.
+func (fs *FS) GetHostIQN(ctx context.Context, create bool) (string, error) {
+	unlock, err := fs.lockPaths(ctx, fs.etcPath(initiatorIQNPath))
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	return fs.getHostIQN(ctx, create)
+}
+
+// EnsureDeviceUnused returns an InUseError if the device is mounted, held
+// by another block device or open exclusively.
+func (fs *FS) EnsureDeviceUnused(ctx context.Context, device string) error {
+	return fs.ensureDeviceUnused(ctx, device)
+}
+
+// RegisterSupportedFsType adds filesystem types to those accepted by
+// Mount, FormatAndMount and Format.
+func (fs *FS) RegisterSupportedFsType(fsTypes ...string) {
+	fs.fsTypes.register(fsTypes...)
+}
+
+// SetSupportedFsTypes replaces the filesystem types accepted by Mount,
+// FormatAndMount and Format, or restores DefaultSupportedFsTypes if none
+// are given.
+func (fs *FS) SetSupportedFsTypes(fsTypes ...string) {
+	fs.fsTypes.set(fsTypes...)
+}
+
+// SupportedFsTypes returns the filesystem types accepted by Mount,
+// FormatAndMount and Format.
+func (fs *FS) SupportedFsTypes() []string {
+	return fs.fsTypes.list()
+}
+
+// SetDefaultMountOptions replaces the options merged into the mounts of
+// fsType, or removes them if none are given.
+func (fs *FS) SetDefaultMountOptions(fsType string, opts ...string) {
+	fs.mountDefaults.set(fsType, opts...)
+}
+
+// DefaultMountOptions returns the options merged into the mounts of
+// fsType.
+func (fs *FS) DefaultMountOptions(fsType string) []string {
+	return fs.mountDefaults.get(fsType)
+}
+
+// SMBMount mounts the SMB share source, e.g. //server/share, on target as
+// a cifs filesystem with the given credentials.
+func (fs *FS) SMBMount(ctx context.Context, source, target string, creds SMBCredentials, opts ...string) error {
+	unlock, err := fs.lockPaths(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.smbMount(ctx, source, target, creds, opts...)
+}
+
+// GetCommandHistory returns the last CommandHistorySize external commands
+// run, oldest first.
+func (fs *FS) GetCommandHistory() []CommandRecord {
+	return fs.history.list()
+}
+
+// UnmountTree unmounts every mount at or below root, deepest first, and
+// returns the outcome for each mount.
+func (fs *FS) UnmountTree(ctx context.Context, root string) ([]UnmountResult, error) {
+	mounts, err := fs.getMountTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return unmountTree(ctx, mountPathsUnder(mounts, root),
+		UnmountTreeRetries, UnmountTreeRetryInterval,
+		fs.lockedPathFunc(fs.unmount), fs.lockedPathFunc(fs.lazyUnmount)), nil
+}
+
+// getMountTable returns every entry of the mount table, unlike getMounts
+// including those that ScanEntry filters, e.g. tmpfs and overlay mounts.
+func (fs *FS) getMountTable(ctx context.Context) ([]Info, error) {
+	return fs.getMountsByKind(ctx, mountTableKinds...)
+}
+
+// lockedPathFunc returns fn holding the lock of its path while it runs.
+func (fs *FS) lockedPathFunc(fn func(context.Context, string) error) func(context.Context, string) error {
+	return func(ctx context.Context, path string) error {
+		unlock, err := fs.lockPaths(ctx, path)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+		return fn(ctx, path)
+	}
+}
+
+// GetMpathDeviceForWWN returns the multipath device of the volume with the
+// given WWN, nil if there is none.
+func (fs *FS) GetMpathDeviceForWWN(ctx context.Context, wwn string) (*MpathDevice, error) {
+	return fs.getMpathDeviceForWWN(ctx, wwn)
+}
+
+// BlockDevSetRO makes the block device read-only, or read-write if
+// readOnly is false.
+func (fs *FS) BlockDevSetRO(ctx context.Context, device string, readOnly bool) error {
+	unlock, err := fs.lockPaths(ctx, device)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.blockDevSetRO(ctx, device, readOnly)
+}
+
+// BlockDevGetRO returns true if the block device is read-only.
+func (fs *FS) BlockDevGetRO(ctx context.Context, device string) (bool, error) {
+	return fs.blockDevGetRO(ctx, device)
+}
+
+// CleanupDeviceForWWN removes the multipath and path devices of the volume
+// with the given WWN from the host.
+func (fs *FS) CleanupDeviceForWWN(ctx context.Context, wwn string) (*DeviceCleanupReport, error) {
+	return fs.cleanupDeviceForWWN(ctx, wwn)
+}
+
+// IsPMEMDevice returns true if device is a persistent memory block device.
+func (fs *FS) IsPMEMDevice(ctx context.Context, device string) (bool, error) {
+	return fs.isPMEMDevice(ctx, device)
+}
+
+// MountDAX mounts the filesystem on source with direct access (DAX).
+func (fs *FS) MountDAX(ctx context.Context, source, target, fsType string, opts ...string) error {
+	unlock, err := fs.lockPaths(ctx, target, deviceKey(source))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.mountDAX(ctx, source, target, fsType, opts...)
+}
+
+// GetISCSITargetsFromByPath returns the devices of the iSCSI LUNs in
+// /dev/disk/by-path.
+func (fs *FS) GetISCSITargetsFromByPath(ctx context.Context) ([]ISCSITargetDevice, error) {
+	return fs.getISCSITargetsFromByPath(ctx)
+}
+
+// FCTargetWWPNLUNToDevicePath returns the /dev/disk/by-path entries of an FC
+// LUN and their device paths.
+func (fs *FS) FCTargetWWPNLUNToDevicePath(ctx context.Context, wwpn string, lunID int) (map[string]string, error) {
+	return fs.fcTargetWWPNLUNToDevicePath(ctx, wwpn, lunID)
+}
+
+// RescanSCSIHostsForLUNs rescans the scsi hosts related to targets for
+// each of luns.
+func (fs *FS) RescanSCSIHostsForLUNs(ctx context.Context, targets []string, luns []string) (*RescanReport, error) {
+	return fs.rescanSCSIHostsForLUNs(ctx, targets, luns)
+}
+
+// SetTargetPermissions sets the owner, group and mode of target, and of the
+// files below it if recursive is set.
+func (fs *FS) SetTargetPermissions(ctx context.Context, target string, uid, gid int, mode os.FileMode, recursive bool) error {
+	unlock, err := fs.lockPaths(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.setTargetPermissions(ctx, target, uid, gid, mode, recursive)
+}
+
+// SetTargetImmutable sets or clears the immutable attribute of target.
+func (fs *FS) SetTargetImmutable(ctx context.Context, target string, immutable bool) error {
+	unlock, err := fs.lockPaths(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.setTargetImmutable(ctx, target, immutable)
+}
+
+// GetLoopDeviceForFile returns the loop device backed by file.
+func (fs *FS) GetLoopDeviceForFile(ctx context.Context, file string) (string, error) {
+	return fs.getLoopDeviceForFile(ctx, file)
+}
+
+// AttachLoopDevice attaches file to a loop device.
+func (fs *FS) AttachLoopDevice(ctx context.Context, file string, readOnly bool) (string, error) {
+	unlock, err := fs.lockPaths(ctx, file)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	device, _, err := fs.attachLoopDevice(ctx, file, readOnly)
+	return device, err
+}
+
+// DetachLoopDevice detaches the loop device from its backing file.
+func (fs *FS) DetachLoopDevice(ctx context.Context, device string) error {
+	unlock, err := fs.lockPaths(ctx, deviceKey(device))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.detachLoopDevice(ctx, device)
+}
+
+// FormatAndMountFile formats and mounts file with a loop device.
+func (fs *FS) FormatAndMountFile(ctx context.Context, file, target, fsType string, opts ...string) (string, error) {
+	unlock, err := fs.lockPaths(ctx, target, file)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	return fs.formatAndMountFile(ctx, file, target, fsType, opts...)
+}
+
+// MountTmpfs mounts a tmpfs of sizeBytes with a root directory of mode on
+// target.
+func (fs *FS) MountTmpfs(ctx context.Context, target string, sizeBytes int64, mode os.FileMode, opts ...string) error {
+	unlock, err := fs.lockPaths(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.mountTmpfs(ctx, target, sizeBytes, mode, opts...)
+}
+
+// GetMultipathKind returns the kind of multipathing of the volume with
+// the given WWN.
+func (fs *FS) GetMultipathKind(ctx context.Context, wwn string) (MultipathKind, error) {
+	return fs.getMultipathKind(ctx, wwn)
+}
+
+// FsHealthCheck probes the health of the filesystem mounted on
+// mountpoint.
+func (fs *FS) FsHealthCheck(ctx context.Context, mountpoint string) (*FsHealth, error) {
+	return fs.fsHealthCheck(ctx, mountpoint)
+}
+
+// CheckClusterStack returns an error if fsType is a cluster filesystem
+// whose cluster stack is not up.
+func (fs *FS) CheckClusterStack(ctx context.Context, fsType string, opts ...string) error {
+	return fs.checkClusterStack(ctx, fsType, opts...)
+}
+
+// GetDryRunActions returns the actions recorded with DryRun, oldest
+// first.
+func (fs *FS) GetDryRunActions() []DryRunAction {
+	return fs.dryRunActions.list()
+}
+
+// ClearDryRunActions forgets the actions recorded with DryRun.
+func (fs *FS) ClearDryRunActions() {
+	fs.dryRunActions.clear()
+}
+
+// SetTracerProvider makes the operations create spans with a tracer of
+// tp, or no spans if tp is nil.
+func (fs *FS) SetTracerProvider(tp trace.TracerProvider) {
+	fs.setTracerProvider(tp)
+}
+
+// GetDeviceTopology returns the topology of the block device.
+func (fs *FS) GetDeviceTopology(ctx context.Context, device string) (*DeviceTopology, error) {
+	return fs.getDeviceTopology(ctx, device)
+}
+
+// GetBlockDeviceHolders returns the direct holders of the block device.
+func (fs *FS) GetBlockDeviceHolders(ctx context.Context, device string) ([]string, error) {
+	return fs.getBlockDeviceHolders(ctx, device)
+}
+
+// GetNVMePathStates returns the ANA state of the paths of the NVMe
+// subsystem with the given NQN.
+func (fs *FS) GetNVMePathStates(ctx context.Context, nqn string) ([]NVMePathState, error) {
+	return fs.getNVMePathStates(ctx, nqn)
+}
+
+// MountSensitive mounts source onto target like Mount, redacting
+// sensitiveOpts from the logs and errors.
+func (fs *FS) MountSensitive(ctx context.Context, source, target, fsType string, opts, sensitiveOpts []string) (err error) {
+	ctx, end := fs.startSpan(ctx, "MountSensitive",
+		attrDevice.String(source), attrTarget.String(target), attrFsType.String(fsType))
+	defer end(&err)
+	defer wrapOpError(&err, "mount", target, CodeMountFailed)
+	unlock, err := fs.lockPaths(ctx, target, deviceKey(source))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.mountSensitive(ctx, source, target, fsType, opts, sensitiveOpts)
+}
+
+// MountWithSpec validates and mounts the mount described by spec.
+func (fs *FS) MountWithSpec(ctx context.Context, spec MountSpec) (err error) {
+	ctx, end := fs.startSpan(ctx, "MountWithSpec",
+		attrDevice.String(spec.Source), attrTarget.String(spec.Target), attrFsType.String(spec.FsType))
+	defer end(&err)
+	defer wrapOpError(&err, "mount", spec.Target, CodeMountFailed)
+	unlock, err := fs.lockPaths(ctx, spec.Target, deviceKey(spec.Source))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.mountWithSpec(ctx, spec)
+}
+
+// StageBlockVolume finds, checks, formats and mounts the device of a
+// volume, or bind mounts it for a raw block volume.
+func (fs *FS) StageBlockVolume(ctx context.Context, req StageRequest) (result *StageResult, err error) {
+	ctx, end := fs.startSpan(ctx, "StageBlockVolume", attrTarget.String(req.Target))
+	defer end(&err)
+	return stageBlockVolume(ctx, fs, req, fs.lockPaths)
+}
+
+// UnstageBlockVolume unmounts the targets of a volume and removes its
+// devices.
+func (fs *FS) UnstageBlockVolume(ctx context.Context, req UnstageRequest) (result *UnstageResult, err error) {
+	ctx, end := fs.startSpan(ctx, "UnstageBlockVolume", attrTargets.StringSlice(req.Targets))
+	defer end(&err)
+	return unstageBlockVolume(ctx, fs, req)
+}
+
+// GetHostSCSIHosts returns the SCSI hosts of the node.
+func (fs *FS) GetHostSCSIHosts(ctx context.Context) ([]SCSIHost, error) {
+	return fs.getHostSCSIHosts(ctx)
+}
+
+// DMNameToDevPath returns the device mapper device with the given name.
+func (fs *FS) DMNameToDevPath(ctx context.Context, name string) (*DMDevice, error) {
+	return fs.dmNameToDevPath(ctx, name)
+}
+
+// DevPathToDMName returns the device mapper device of a device path.
+func (fs *FS) DevPathToDMName(ctx context.Context, devPath string) (*DMDevice, error) {
+	return fs.devPathToDMName(ctx, devPath)
+}
+
+// GetDevicePathsForMountPoint returns the block devices mounted at target.
+func (fs *FS) GetDevicePathsForMountPoint(ctx context.Context, target string) ([]string, error) {
+	return fs.getDevicePathsForMountPoint(ctx, target)
+}
+
+// GetMountsByDevID returns the mounts of the filesystem with the given
+// device number.
+func (fs *FS) GetMountsByDevID(ctx context.Context, major, minor uint32) ([]Info, error) {
+	return fs.getMountsByDevID(ctx, major, minor)
+}
+
+// GetNFSCapabilities returns the NFS mount options supported by the host.
+func (fs *FS) GetNFSCapabilities(ctx context.Context) (*NFSCapabilities, error) {
+	return fs.getNFSCapabilities(ctx)
+}
+
+// MountNFS mounts an NFS export with the supported performance options.
+func (fs *FS) MountNFS(ctx context.Context, source, target string, nfsOpts NFSMountOptions, opts ...string) (err error) {
+	ctx, end := fs.startSpan(ctx, "MountNFS",
+		attrDevice.String(source), attrTarget.String(target), attrFsType.String("nfs"))
+	defer end(&err)
+	return mountNFS(ctx, fs, source, target, nfsOpts, opts...)
+}
+
+// GetISCSIHostsForTargetPortal returns the scsi hosts of the iSCSI
+// sessions connected to the target portal.
+func (fs *FS) GetISCSIHostsForTargetPortal(ctx context.Context, portal string) ([]ISCSIPortalHost, error) {
+	return fs.getISCSIHostsForTargetPortal(ctx, portal)
+}
+
+// ReplayIncompleteOperations finishes or rolls back the operations left
+// in the journal.
+func (fs *FS) ReplayIncompleteOperations(ctx context.Context) (replays []JournalReplay, err error) {
+	ctx, end := fs.startSpan(ctx, "ReplayIncompleteOperations")
+	defer end(&err)
+	return fs.replayIncompleteOperations(ctx)
+}
+
+// RemoveBlockDeviceWithOptions removes a block device like
+// RemoveBlockDevice, checking that it is not busy and flushing it first
+// as requested by opts.
+func (fs *FS) RemoveBlockDeviceWithOptions(ctx context.Context, blockDevicePath string, opts RemoveBlockDeviceOptions) (err error) {
+	ctx, end := fs.startSpan(ctx, "RemoveBlockDeviceWithOptions", attrDevice.String(blockDevicePath))
+	defer end(&err)
+	return fs.removeBlockDeviceWithOptions(ctx, blockDevicePath, opts)
+}
+
+// GetNativeMultipathSetting returns true if native NVMe multipathing is
+// enabled.
+func (fs *FS) GetNativeMultipathSetting(ctx context.Context) (bool, error) {
+	return fs.getNativeMultipathSetting(ctx)
+}
+
+// CheckNVMeMultipathMode returns an error if mode conflicts with the
+// native NVMe multipath setting.
+func (fs *FS) CheckNVMeMultipathMode(ctx context.Context, mode NVMeMultipathMode) error {
+	return checkNVMeMultipathMode(ctx, fs, mode)
+}
+
+// SubscribeMountChanges returns a channel of the changes of the mount
+// table, closed once ctx is done.
+func (fs *FS) SubscribeMountChanges(ctx context.Context) (<-chan MountEvent, error) {
+	return fs.subscribeMountChanges(ctx)
+}
+
+// SysfsReadAttr returns the value of the sysfs attribute attr.
+func (fs *FS) SysfsReadAttr(ctx context.Context, attr string) (string, error) {
+	return fs.sysfsReadAttr(ctx, attr)
+}
+
+// SysfsWriteAttr writes value to the sysfs attribute attr.
+func (fs *FS) SysfsWriteAttr(ctx context.Context, attr, value string) error {
+	return fs.sysfsWriteAttr(ctx, attr, value)
+}
+
+// GetDeviceForPublishPath returns the device backing the volume published
+// at publishPath.
+func (fs *FS) GetDeviceForPublishPath(ctx context.Context, publishPath string) (*PublishPathDevice, error) {
+	return fs.getDeviceForPublishPath(ctx, publishPath)
+}
+
+// GetFSGeometry returns the geometry of the filesystem mounted at path or
+// on the device at path.
+func (fs *FS) GetFSGeometry(ctx context.Context, path string) (*FSGeometry, error) {
+	return fs.getFSGeometry(ctx, path)
+}
+
+// ShrinkFS shrinks the unmounted ext filesystem on device to
+// newSizeBytes.
+func (fs *FS) ShrinkFS(ctx context.Context, device string, newSizeBytes int64) (err error) {
+	defer wrapOpError(&err, "shrink", device, CodeResizeFailed)
+	unlock, err := fs.lockPaths(ctx, device)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fs.shrinkFS(ctx, device, newSizeBytes)
+}
+
+// MultipathReconfigure makes multipathd reload its configuration.
+func (fs *FS) MultipathReconfigure(ctx context.Context) (*MultipathdResult, error) {
+	return fs.multipathReconfigure(ctx)
+}
+
+// ReinstatePath makes multipathd reinstate the path device.
+func (fs *FS) ReinstatePath(ctx context.Context, device string) (*MultipathdResult, error) {
+	return fs.reinstatePath(ctx, device)
+}
+
+// FailPath makes multipathd fail the path device.
+func (fs *FS) FailPath(ctx context.Context, device string) (*MultipathdResult, error) {
+	return fs.failPath(ctx, device)
+}
+
+// VerifyDeviceReadable reads a range of the device with O_DIRECT.
+func (fs *FS) VerifyDeviceReadable(ctx context.Context, device string, offset, length int64) error {
+	return fs.verifyDeviceReadable(ctx, device, offset, length)
+}
+
+// UnmountDevice unmounts every mount of device, deepest first, and
+// checks that none remains.
+func (fs *FS) UnmountDevice(ctx context.Context, device string) (results []UnmountResult, err error) {
+	defer wrapOpError(&err, "unmount", device, CodeUnmountFailed)
+	return unmountDevice(ctx, device, UnmountTreeRetries, UnmountTreeRetryInterval,
+		fs.getDevMounts, fs.lockedPathFunc(fs.unmount))
+}
+
+// VerifyMountedCapacity checks that the capacity of the filesystem
+// mounted at target matches the expected size within tolerance.
+func (fs *FS) VerifyMountedCapacity(ctx context.Context, target string, expected int64, tolerance float64) error {
+	return fs.verifyMountedCapacity(ctx, target, expected, tolerance)
+}
@@ -0,0 +1,91 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// DefaultSupportedFsTypes are the filesystem types accepted by Mount,
+// FormatAndMount and Format unless changed with RegisterSupportedFsType
+// or SetSupportedFsTypes.
+var DefaultSupportedFsTypes = []string{"ext3", "ext4", "xfs", "nfs", "cifs", FsTypeOCFS2, FsTypeGFS2}
+
+// fsTypeSet is the set of supported filesystem types. The zero value
+// holds DefaultSupportedFsTypes.
+type fsTypeSet struct {
+	mu sync.RWMutex
+	// types is nil until the set is changed from the defaults.
+	types map[string]bool
+}
+
+// validate returns an error if fsType is not supported.
+func (s *fsTypeSet) validate(fsType string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.types == nil {
+		return validateFsType(fsType)
+	}
+	if !s.types[fsType] {
+		return errors.New("FsType: " + fsType + " is invalid")
+	}
+	return nil
+}
+
+// register adds the filesystem types to the set.
+func (s *fsTypeSet) register(fsTypes ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.types == nil {
+		s.types = make(map[string]bool)
+		for _, t := range DefaultSupportedFsTypes {
+			s.types[t] = true
+		}
+	}
+	for _, t := range fsTypes {
+		s.types[t] = true
+	}
+}
+
+// set replaces the set with the filesystem types, or the defaults if none
+// are given.
+func (s *fsTypeSet) set(fsTypes ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(fsTypes) == 0 {
+		s.types = nil
+		return
+	}
+	s.types = make(map[string]bool, len(fsTypes))
+	for _, t := range fsTypes {
+		s.types[t] = true
+	}
+}
+
+// list returns the filesystem types in the set, sorted.
+func (s *fsTypeSet) list() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var types []string
+	if s.types == nil {
+		types = append(types, DefaultSupportedFsTypes...)
+	} else {
+		for t := range s.types {
+			types = append(types, t)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
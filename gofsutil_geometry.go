@@ -0,0 +1,165 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FSGeometry is the geometry of a filesystem, as returned by
+// GetFSGeometry. Fields that do not apply to the filesystem type are
+// zero.
+type FSGeometry struct {
+	// FsType is the filesystem type, e.g. ext4 or xfs.
+	FsType string `json:"fsType" yaml:"fsType"`
+	// BlockSize is the size in bytes of a filesystem block.
+	BlockSize int64 `json:"blockSize" yaml:"blockSize"`
+	// BlockCount is the number of blocks of the filesystem, of its data
+	// section for xfs.
+	BlockCount int64 `json:"blockCount" yaml:"blockCount"`
+	// FreeBlocks is the number of free blocks of an ext filesystem.
+	FreeBlocks int64 `json:"freeBlocks,omitempty" yaml:"freeBlocks,omitempty"`
+	// ReservedBlockCount is the number of blocks of an ext filesystem
+	// reserved for the super user.
+	ReservedBlockCount int64 `json:"reservedBlockCount,omitempty" yaml:"reservedBlockCount,omitempty"`
+	// ReservedBlockPercent is ReservedBlockCount as a percentage of
+	// BlockCount, e.g. 5 for the mke2fs default.
+	ReservedBlockPercent float64 `json:"reservedBlockPercent,omitempty" yaml:"reservedBlockPercent,omitempty"`
+	// InodeCount is the number of inodes of an ext filesystem.
+	InodeCount int64 `json:"inodeCount,omitempty" yaml:"inodeCount,omitempty"`
+	// AGCount is the number of allocation groups of an xfs filesystem.
+	AGCount int64 `json:"agCount,omitempty" yaml:"agCount,omitempty"`
+	// AGBlocks is the size in blocks of the allocation groups of an xfs
+	// filesystem.
+	AGBlocks int64 `json:"agBlocks,omitempty" yaml:"agBlocks,omitempty"`
+	// LogBlockSize is the size in bytes of a block of the log of an xfs
+	// filesystem.
+	LogBlockSize int64 `json:"logBlockSize,omitempty" yaml:"logBlockSize,omitempty"`
+	// LogBlocks is the number of blocks of the log of an xfs filesystem.
+	LogBlocks int64 `json:"logBlocks,omitempty" yaml:"logBlocks,omitempty"`
+	// LogInternal is set if the log of an xfs filesystem is in its data
+	// section rather than on an external device.
+	LogInternal bool `json:"logInternal,omitempty" yaml:"logInternal,omitempty"`
+}
+
+// Size returns the size in bytes of the filesystem, of its data section
+// for xfs.
+func (g *FSGeometry) Size() int64 {
+	return g.BlockSize * g.BlockCount
+}
+
+// LogSize returns the size in bytes of the log of an xfs filesystem.
+func (g *FSGeometry) LogSize() int64 {
+	return g.LogBlockSize * g.LogBlocks
+}
+
+// parseTune2fsGeometry returns the geometry of an ext filesystem from the
+// output of tune2fs -l.
+func parseTune2fsGeometry(out string) (*FSGeometry, error) {
+	g := new(FSGeometry)
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		var field *int64
+		switch strings.TrimSpace(key) {
+		case "Block count":
+			field = &g.BlockCount
+		case "Block size":
+			field = &g.BlockSize
+		case "Free blocks":
+			field = &g.FreeBlocks
+		case "Reserved block count":
+			field = &g.ReservedBlockCount
+		case "Inode count":
+			field = &g.InodeCount
+		default:
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse tune2fs output line %q: %v", scanner.Text(), err)
+		}
+		*field = n
+	}
+	if g.BlockCount == 0 || g.BlockSize == 0 {
+		return nil, errors.New("block count or block size not found in tune2fs output")
+	}
+	g.ReservedBlockPercent = float64(g.ReservedBlockCount) * 100 / float64(g.BlockCount)
+	return g, nil
+}
+
+// parseXFSInfoGeometry returns the geometry of an xfs filesystem from the
+// output of xfs_info, e.g.
+//
+//	meta-data=/dev/sdb               isize=512    agcount=4, agsize=65536 blks
+//	data     =                       bsize=4096   blocks=262144, imaxpct=25
+//	log      =internal log           bsize=4096   blocks=2560, version=2
+//
+// Lines starting with a space continue the section of the previous line.
+func parseXFSInfoGeometry(out string) (*FSGeometry, error) {
+	g := new(FSGeometry)
+	var section string
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		name, rest, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if name = strings.TrimSpace(name); name != "" {
+			section = name
+			if section == "log" {
+				g.LogInternal = strings.HasPrefix(strings.TrimSpace(rest), "internal")
+			}
+		}
+		for _, field := range strings.Fields(strings.ReplaceAll(rest, ",", " ")) {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			var dst *int64
+			switch section + "." + key {
+			case "meta-data.agcount":
+				dst = &g.AGCount
+			case "meta-data.agsize":
+				dst = &g.AGBlocks
+			case "data.bsize":
+				dst = &g.BlockSize
+			case "data.blocks":
+				dst = &g.BlockCount
+			case "log.bsize":
+				dst = &g.LogBlockSize
+			case "log.blocks":
+				dst = &g.LogBlocks
+			default:
+				continue
+			}
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse xfs_info output line %q: %v", line, err)
+			}
+			*dst = n
+		}
+	}
+	if g.BlockCount == 0 || g.BlockSize == 0 {
+		return nil, errors.New("data section not found in xfs_info output")
+	}
+	return g, nil
+}
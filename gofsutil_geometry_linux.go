@@ -0,0 +1,70 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+)
+
+// getFSGeometry returns the geometry of the filesystem mounted at path or
+// on the device at path, read with tune2fs or xfs_info.
+func (fs *FS) getFSGeometry(ctx context.Context, path string) (*FSGeometry, error) {
+	path = filepath.Clean(path)
+	if err := validatePath(path); err != nil {
+		return nil, fmt.Errorf("Failed to validate path: %s error %v", path, err)
+	}
+	// A mount point is read through the device mounted on it.
+	device, fsType := path, ""
+	fields := MountEntryMountPoint | MountEntryFSType | MountEntryMountSource
+	err := fs.scanProcMounts(ctx, fields, func(_ context.Context, e Entry) (bool, error) {
+		if filepath.Clean(e.MountPoint) == path {
+			device, fsType = e.MountSource, e.FSType
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if fsType == "" {
+		if fsType, err = fs.getDiskFormat(ctx, path); err != nil {
+			return nil, err
+		}
+	}
+
+	var name string
+	var args []string
+	var parse func(string) (*FSGeometry, error)
+	switch fsType {
+	case "ext2", "ext3", "ext4":
+		name, args, parse = "tune2fs", []string{"-l", device}, parseTune2fsGeometry
+	case "xfs":
+		name, args, parse = "xfs_info", []string{path}, parseXFSInfoGeometry
+	case "":
+		return nil, fmt.Errorf("no filesystem found on %s", path)
+	default:
+		return nil, fmt.Errorf("filesystem geometry of %s is not supported", fsType)
+	}
+	/* #nosec G204 */
+	out, err := fs.commandContext(ctx, name, args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed for (%s) error (%v)", name, path, err)
+	}
+	g, err := parse(string(out))
+	if err != nil {
+		return nil, err
+	}
+	g.FsType = fsType
+	return g, nil
+}
@@ -0,0 +1,129 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tune2fsOutput = `tune2fs 1.47.0 (5-Feb-2023)
+Filesystem volume name:   <none>
+Filesystem UUID:          804746a2-ccd4-4cc0-916f-e3f27a921737
+Inode count:              16384
+Block count:              65536
+Reserved block count:     3276
+Overhead clusters:        9499
+Free blocks:              56023
+Free inodes:              16373
+First block:              1
+Block size:               1024
+Reserved blocks uid:      0 (user root)
+`
+
+const xfsInfoOutput = `meta-data=/dev/sdb               isize=512    agcount=4, agsize=65536 blks
+         =                       sectsz=512   attr=2, projid32bit=1
+         =                       crc=1        finobt=1, sparse=1, rmapbt=0
+data     =                       bsize=4096   blocks=262144, imaxpct=25
+         =                       sunit=0      swidth=0 blks
+naming   =version 2              bsize=4096   ascii-ci=0, ftype=1
+log      =internal log           bsize=4096   blocks=2560, version=2
+         =                       sectsz=512   sunit=0 blks, lazy-count=1
+realtime =none                   extsz=4096   blocks=0, rtextents=0
+`
+
+func TestParseTune2fsGeometry(t *testing.T) {
+	g, err := parseTune2fsGeometry(tune2fsOutput)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1024), g.BlockSize)
+	assert.Equal(t, int64(65536), g.BlockCount)
+	assert.Equal(t, int64(56023), g.FreeBlocks)
+	assert.Equal(t, int64(3276), g.ReservedBlockCount)
+	assert.InDelta(t, 5, g.ReservedBlockPercent, 0.01)
+	assert.Equal(t, int64(16384), g.InodeCount)
+	assert.Equal(t, int64(64<<20), g.Size())
+
+	_, err = parseTune2fsGeometry("Block count: 10\n")
+	assert.Error(t, err)
+	_, err = parseTune2fsGeometry("Block count: ten\nBlock size: 4096\n")
+	assert.Error(t, err)
+}
+
+func TestParseXFSInfoGeometry(t *testing.T) {
+	g, err := parseXFSInfoGeometry(xfsInfoOutput)
+	require.NoError(t, err)
+	assert.Equal(t, &FSGeometry{
+		BlockSize:    4096,
+		BlockCount:   262144,
+		AGCount:      4,
+		AGBlocks:     65536,
+		LogBlockSize: 4096,
+		LogBlocks:    2560,
+		LogInternal:  true,
+	}, g)
+	assert.Equal(t, int64(1<<30), g.Size())
+	assert.Equal(t, int64(2560*4096), g.LogSize())
+
+	g, err = parseXFSInfoGeometry(strings.Replace(xfsInfoOutput, "internal log    ", "/dev/sdc        ", 1))
+	require.NoError(t, err)
+	assert.False(t, g.LogInternal)
+
+	_, err = parseXFSInfoGeometry("naming   =version 2              bsize=4096\n")
+	assert.Error(t, err)
+	_, err = parseXFSInfoGeometry("data     =                       bsize=4096   blocks=many\n")
+	assert.Error(t, err)
+}
+
+func TestGetFSGeometry(t *testing.T) {
+	bin := t.TempDir()
+	log := filepath.Join(t.TempDir(), "log")
+	procRoot := t.TempDir()
+	for name, out := range map[string]string{"tune2fs": tune2fsOutput, "xfs_info": xfsInfoOutput, "lsblk": "xfs\n"} {
+		script := "#!/bin/sh\necho \"" + name + " $*\" >> " + log + "\n/bin/cat <<'EOF'\n" + out + "EOF\n"
+		require.NoError(t, os.WriteFile(filepath.Join(bin, name), []byte(script), 0o700)) // #nosec G306
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(procRoot, "self"), 0o750))
+	mountInfo := mountKindMountInfo + "30 22 8:16 / /mnt/data rw,relatime - ext4 /dev/sdx rw\n"
+	require.NoError(t, os.WriteFile(filepath.Join(procRoot, "self", "mountinfo"), []byte(mountInfo), 0o600))
+	fs := NewFS(FSOptions{ProcRoot: procRoot, ExtraEnv: []string{"PATH=" + bin}})
+	ctx := context.Background()
+
+	// A mount point is read through its device.
+	g, err := fs.GetFSGeometry(ctx, "/mnt/data/")
+	require.NoError(t, err)
+	assert.Equal(t, "ext4", g.FsType)
+	assert.Equal(t, int64(65536), g.BlockCount)
+
+	// An unmounted device is read according to its format.
+	g, err = fs.GetFSGeometry(ctx, "/dev/sdy")
+	require.NoError(t, err)
+	assert.Equal(t, "xfs", g.FsType)
+	assert.Equal(t, int64(2560), g.LogBlocks)
+
+	out, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, "tune2fs -l /dev/sdx\nlsblk -n -o FSTYPE /dev/sdy\nxfs_info /dev/sdy\n", string(out))
+
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "lsblk"), []byte("#!/bin/sh\necho btrfs\n"), 0o700)) // #nosec G306
+	_, err = fs.GetFSGeometry(ctx, "/dev/sdz")
+	assert.Error(t, err)
+}
@@ -0,0 +1,188 @@
+//go:build integration && (linux || darwin)
+// +build integration
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Harness provisions loop devices backed by sparse files in a temporary
+// directory, so that functional tests can run real format, mount and
+// resize cycles, e.g. in a privileged CI container. It is only built with
+// the integration build tag:
+//
+//	go test -tags integration ./...
+//
+// Everything it provisions is torn down when the test completes.
+type Harness struct {
+	// FS is the FS the harness uses, which tests can use as well.
+	FS *FS
+	// Dir is the temporary directory of the backing files and mount
+	// points.
+	Dir string
+
+	tb      testing.TB
+	mu      sync.Mutex
+	files   map[string]string
+	devices []string
+	mounts  []string
+}
+
+// NewHarness returns a harness using an FS with opts. The test is skipped
+// if it does not run as root or losetup is not installed.
+func NewHarness(tb testing.TB, opts FSOptions) *Harness {
+	tb.Helper()
+	if os.Geteuid() != 0 {
+		tb.Skip("the harness requires root privileges")
+	}
+	if _, err := exec.LookPath("losetup"); err != nil {
+		tb.Skip("the harness requires losetup")
+	}
+	h := &Harness{
+		FS:    NewFS(opts),
+		Dir:   tb.TempDir(),
+		tb:    tb,
+		files: make(map[string]string),
+	}
+	// Registered after TempDir so that it runs before the directory is
+	// removed.
+	tb.Cleanup(h.Teardown)
+	return h
+}
+
+// RequireCommand skips the test if the command, e.g. mkfs.xfs, is not
+// installed.
+func (h *Harness) RequireCommand(name string) {
+	h.tb.Helper()
+	if _, err := exec.LookPath(name); err != nil {
+		h.tb.Skipf("the test requires %s", name)
+	}
+}
+
+// NewLoopDevice attaches a new sparse file of sizeBytes to a loop device
+// and returns the device, e.g. /dev/loop3. The test is skipped if loop
+// devices are not supported.
+func (h *Harness) NewLoopDevice(sizeBytes int64) string {
+	h.tb.Helper()
+	f, err := os.CreateTemp(h.Dir, "disk-*.img")
+	if err != nil {
+		h.tb.Fatal(err)
+	}
+	name := f.Name()
+	err = f.Truncate(sizeBytes)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		h.tb.Fatal(err)
+	}
+	device, _, err := h.FS.attachLoopDevice(context.Background(), name, false)
+	if errors.Is(err, ErrNotImplemented) {
+		h.tb.Skip("loop devices are not supported")
+	}
+	if err != nil {
+		h.tb.Fatal(err)
+	}
+	h.mu.Lock()
+	h.files[device] = name
+	h.devices = append(h.devices, device)
+	h.mu.Unlock()
+	return device
+}
+
+// MountPoint creates and returns a directory to mount on.
+func (h *Harness) MountPoint(name string) string {
+	h.tb.Helper()
+	dir := filepath.Join(h.Dir, "mnt", name)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		h.tb.Fatal(err)
+	}
+	return dir
+}
+
+// FormatAndMount formats device with fsType and mounts it on a new mount
+// point, which it returns. The mount is unmounted by Teardown.
+func (h *Harness) FormatAndMount(device, fsType string, opts ...string) string {
+	h.tb.Helper()
+	target := h.MountPoint(filepath.Base(device))
+	if err := h.FS.FormatAndMount(context.Background(), device, target, fsType, opts...); err != nil {
+		h.tb.Fatal(err)
+	}
+	h.TrackMount(target)
+	return target
+}
+
+// TrackMount has Teardown unmount target, for mounts made by the test.
+func (h *Harness) TrackMount(target string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.mounts = append(h.mounts, target)
+}
+
+// Grow grows the backing file of the loop device to sizeBytes and has the
+// kernel pick up the new capacity, as a volume expansion on an array
+// would. The filesystem can then be expanded with ResizeFS.
+func (h *Harness) Grow(device string, sizeBytes int64) {
+	h.tb.Helper()
+	h.mu.Lock()
+	file, ok := h.files[device]
+	h.mu.Unlock()
+	if !ok {
+		h.tb.Fatalf("%s was not created by the harness", device)
+	}
+	if err := os.Truncate(file, sizeBytes); err != nil {
+		h.tb.Fatal(err)
+	}
+	out, err := h.FS.commandContext(context.Background(), "losetup", "-c", device).CombinedOutput()
+	if err != nil {
+		h.tb.Fatalf("losetup -c %s failed: %v: %s", device, err, strings.TrimSpace(string(out)))
+	}
+}
+
+// Teardown unmounts the tracked mounts, most recent first, and detaches
+// the loop devices. It is run when the test completes and reports what it
+// could not clean up as test errors.
+func (h *Harness) Teardown() {
+	h.mu.Lock()
+	mounts, devices := h.mounts, h.devices
+	h.mounts, h.devices = nil, nil
+	h.mu.Unlock()
+
+	ctx := context.Background()
+	var errs []error
+	for i := len(mounts) - 1; i >= 0; i-- {
+		if err := h.FS.Unmount(ctx, mounts[i]); err != nil {
+			errs = append(errs, fmt.Errorf("unmount %s: %w", mounts[i], err))
+		}
+	}
+	for _, device := range devices {
+		if err := h.FS.detachLoopDevice(ctx, device); err != nil {
+			errs = append(errs, fmt.Errorf("detach %s: %w", device, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		h.tb.Errorf("harness teardown: %v", err)
+	}
+}
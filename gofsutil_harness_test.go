@@ -0,0 +1,61 @@
+//go:build integration && (linux || darwin)
+// +build integration
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dell/gofsutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHarnessFormatMountResize(t *testing.T) {
+	for _, fsType := range []string{"ext4", "xfs"} {
+		t.Run(fsType, func(t *testing.T) {
+			h := gofsutil.NewHarness(t, gofsutil.FSOptions{})
+			h.RequireCommand("mkfs." + fsType)
+			ctx := context.Background()
+
+			device := h.NewLoopDevice(512 << 20)
+			target := h.FormatAndMount(device, fsType)
+
+			mounts, err := h.FS.GetDevMounts(ctx, device)
+			require.NoError(t, err)
+			require.NotEmpty(t, mounts)
+			assert.Equal(t, fsType, mounts[0].Type)
+			require.NoError(t, os.WriteFile(filepath.Join(target, "data"), []byte("data"), 0o600))
+
+			_, before, _, _, _, _, err := h.FS.FsInfo(ctx, target)
+			require.NoError(t, err)
+			h.Grow(device, 1<<30)
+			require.NoError(t, h.FS.ResizeFS(ctx, target, device, "", "", fsType))
+			_, after, _, _, _, _, err := h.FS.FsInfo(ctx, target)
+			require.NoError(t, err)
+			assert.Greater(t, after, before)
+
+			require.NoError(t, h.FS.Unmount(ctx, target))
+			require.NoError(t, h.FS.Mount(ctx, device, target, fsType))
+			data, err := os.ReadFile(filepath.Join(target, "data"))
+			require.NoError(t, err)
+			assert.Equal(t, "data", string(data))
+		})
+	}
+}
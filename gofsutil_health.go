@@ -0,0 +1,48 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"fmt"
+	"time"
+)
+
+// FsHealth is the report of a health check of a mounted filesystem, e.g.
+// for the volume condition of a CSI NodeGetVolumeStats response.
+type FsHealth struct {
+	// Path is the mount point that was checked.
+	Path string
+	// Abnormal is set if a probe of the filesystem failed.
+	Abnormal bool
+	// Message describes the failed probe, empty if the filesystem is
+	// healthy.
+	Message string
+	// Err is the error of the failed probe.
+	Err error
+	// ReadOnly is set if the filesystem is mounted read-only, in which
+	// case it is not written to.
+	ReadOnly bool
+	// Writable is set if a file could be created in and removed from the
+	// mount point. The write probe is skipped if the mount point is not
+	// writable by the caller.
+	Writable bool
+	// ReadLatency is how long reading the mount point directory took.
+	ReadLatency time.Duration
+}
+
+// fail marks the filesystem abnormal because the probe failed with err.
+func (h *FsHealth) fail(probe string, err error) {
+	h.Abnormal = true
+	h.Err = err
+	h.Message = fmt.Sprintf("%s probe of %s failed: %v", probe, h.Path, err)
+}
@@ -0,0 +1,130 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// fsHealthProbePrefix is the prefix of the hidden file created by the
+// write probe when the filesystem does not support O_TMPFILE.
+const fsHealthProbePrefix = ".gofsutil-health-"
+
+// fsHealthCheck probes the filesystem mounted on mountpoint. The probes
+// run in a goroutine so that a hung filesystem, e.g. an unreachable NFS
+// server, is reported as abnormal once ctx is done instead of blocking
+// the caller.
+func (fs *FS) fsHealthCheck(ctx context.Context, mountpoint string) (*FsHealth, error) {
+	path := filepath.Clean(mountpoint)
+	if err := validatePath(path); err != nil {
+		return nil, fmt.Errorf("Failed to validate path: %s error %v", mountpoint, err)
+	}
+	done := make(chan *FsHealth, 1)
+	go func() {
+		done <- probeFsHealth(path)
+	}()
+	select {
+	case health := <-done:
+		return health, nil
+	case <-ctx.Done():
+		health := &FsHealth{Path: path}
+		health.fail("statfs", fmt.Errorf("filesystem did not respond: %w", ctx.Err()))
+		return health, nil
+	}
+}
+
+// probeFsHealth checks that statfs succeeds on path, measures how long
+// reading the directory takes and, unless the filesystem is read-only,
+// creates and removes a file in it.
+func probeFsHealth(path string) *FsHealth {
+	health := &FsHealth{Path: path}
+	var statfs unix.Statfs_t
+	if err := unix.Statfs(path, &statfs); err != nil {
+		health.fail("statfs", err)
+		return health
+	}
+	health.ReadOnly = statfs.Flags&unix.ST_RDONLY != 0
+
+	start := time.Now()
+	if err := readDirProbe(path); err != nil {
+		health.fail("read", err)
+		return health
+	}
+	health.ReadLatency = time.Since(start)
+
+	if health.ReadOnly || unix.Access(path, unix.W_OK) != nil {
+		return health
+	}
+	if err := writeProbe(path); err != nil {
+		health.fail("write", err)
+		return health
+	}
+	health.Writable = true
+	return health
+}
+
+// readDirProbe reads the first entry of the directory path.
+func readDirProbe(path string) error {
+	dir, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close() // #nosec G307
+	_, err = dir.Readdirnames(1)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+// writeProbe writes a byte to an unnamed temporary file in the directory
+// path, which leaves nothing behind. Filesystems that do not support
+// O_TMPFILE, e.g. NFS, get a hidden file that is removed again.
+func writeProbe(path string) error {
+	fd, err := unix.Open(path, unix.O_TMPFILE|unix.O_WRONLY|unix.O_CLOEXEC, 0o600)
+	if err == nil {
+		f := os.NewFile(uintptr(fd), path) // #nosec G115
+		_, err = f.Write([]byte{0})
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}
+	if !errors.Is(err, unix.EOPNOTSUPP) && !errors.Is(err, unix.EISDIR) && !errors.Is(err, unix.EINVAL) {
+		return err
+	}
+
+	f, err := os.CreateTemp(path, fsHealthProbePrefix+"*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write([]byte{0})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if removeErr := os.Remove(f.Name()); err == nil {
+		err = removeErr
+	}
+	return err
+}
@@ -0,0 +1,60 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFsHealthCheck(t *testing.T) {
+	ctx := context.Background()
+	fs := NewFS(FSOptions{})
+	dir := t.TempDir()
+
+	health, err := fs.FsHealthCheck(ctx, dir)
+	require.NoError(t, err)
+	assert.False(t, health.Abnormal, health.Message)
+	assert.Empty(t, health.Message)
+	assert.NoError(t, health.Err)
+	assert.Equal(t, dir, health.Path)
+	assert.True(t, health.Writable)
+	assert.False(t, health.ReadOnly)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	health, err = fs.FsHealthCheck(ctx, filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.True(t, health.Abnormal)
+	assert.ErrorIs(t, health.Err, os.ErrNotExist)
+	assert.Contains(t, health.Message, "statfs probe of "+filepath.Join(dir, "missing"))
+}
+
+func TestWriteProbe(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, writeProbe(dir))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	assert.Error(t, writeProbe(filepath.Join(dir, "missing")))
+}
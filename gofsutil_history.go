@@ -0,0 +1,98 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"sync"
+	"time"
+)
+
+// CommandHistoryOutputLimit is the number of bytes of the output of a
+// command kept in its CommandRecord.
+const CommandHistoryOutputLimit = 4096
+
+// CommandRecord describes an external command run by an FS.
+type CommandRecord struct {
+	// Command is the path or name of the command.
+	Command string
+	// Args are the arguments of the command, with the values of secret
+	// mount options replaced.
+	Args []string
+	// Start is when the command was started.
+	Start time.Time
+	// Duration is how long the command ran.
+	Duration time.Duration
+	// ExitCode is the exit code of the command, -1 if it did not run to
+	// completion.
+	ExitCode int
+	// Output is the combined standard output and error of the command,
+	// truncated to CommandHistoryOutputLimit bytes.
+	Output string
+	// Truncated is set if the output was truncated.
+	Truncated bool
+	// Err is the error returned by the command, if any.
+	Err string
+}
+
+// commandHistory holds the last records added, up to size.
+type commandHistory struct {
+	mu      sync.Mutex
+	records []CommandRecord
+	next    int
+}
+
+// add records a command, dropping the oldest record once size records
+// are held.
+func (h *commandHistory) add(size int, r CommandRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.records) < size {
+		h.records = append(h.records, r)
+		return
+	}
+	if h.next >= len(h.records) {
+		h.next = 0
+	}
+	h.records[h.next] = r
+	h.next++
+}
+
+// list returns the records, oldest first.
+func (h *commandHistory) list() []CommandRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	records := make([]CommandRecord, 0, len(h.records))
+	if h.next < len(h.records) {
+		records = append(records, h.records[h.next:]...)
+	}
+	return append(records, h.records[:min(h.next, len(h.records))]...)
+}
+
+// limitedBuffer keeps the first limit bytes written to it.
+type limitedBuffer struct {
+	mu        sync.Mutex
+	buf       []byte
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := min(len(p), b.limit-len(b.buf))
+	b.buf = append(b.buf, p[:n]...)
+	if n < len(p) {
+		b.truncated = true
+	}
+	return len(p), nil
+}
@@ -0,0 +1,120 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	hostNQNPath      = "/etc/nvme/hostnqn"
+	hostIDPath       = "/etc/nvme/hostid"
+	initiatorIQNPath = "/etc/iscsi/initiatorname.iscsi"
+
+	// HostNQNUUIDPrefix is the prefix of the UUID based host NQNs
+	// generated by GenerateHostNQN, as by nvme gen-hostnqn.
+	HostNQNUUIDPrefix = "nqn.2014-08.org.nvmexpress:uuid:"
+	// HostIQNPrefix is the prefix of the IQNs generated by
+	// GenerateHostIQN, as by iscsi-iname.
+	HostIQNPrefix = "iqn.2005-03.org.open-iscsi"
+)
+
+var (
+	uuidRegex = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	nqnRegex  = regexp.MustCompile(`^nqn\.[0-9]{4}-[0-9]{2}\.[^ ]+$`)
+	iqnRegex  = regexp.MustCompile(`^iqn\.[0-9]{4}-[0-9]{2}\.[^ ]+$`)
+)
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = 0x40 | (b[6] & 0x0f)
+	b[8] = 0x80 | (b[8] & 0x3f)
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+}
+
+// GenerateHostNQN returns a new UUID based host NQN.
+func GenerateHostNQN() (string, error) {
+	id, err := newUUID()
+	if err != nil {
+		return "", err
+	}
+	return HostNQNUUIDPrefix + id, nil
+}
+
+// GenerateHostIQN returns a new host IQN with a random suffix.
+func GenerateHostIQN() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return HostIQNPrefix + ":" + hex.EncodeToString(b), nil
+}
+
+// hostIDFromNQN returns the UUID of a UUID based host NQN, or "".
+func hostIDFromNQN(nqn string) string {
+	id := strings.ToLower(strings.TrimPrefix(nqn, HostNQNUUIDPrefix))
+	if id == nqn || !uuidRegex.MatchString(id) {
+		return ""
+	}
+	return id
+}
+
+// parseHostNQN returns the host NQN in the content of /etc/nvme/hostnqn.
+func parseHostNQN(content string) (string, error) {
+	nqn := strings.TrimSpace(content)
+	if !nqnRegex.MatchString(nqn) {
+		return "", fmt.Errorf("invalid host NQN: %q", nqn)
+	}
+	return nqn, nil
+}
+
+// parseHostID returns the host ID in the content of /etc/nvme/hostid.
+func parseHostID(content string) (string, error) {
+	id := strings.ToLower(strings.TrimSpace(content))
+	if !uuidRegex.MatchString(id) {
+		return "", fmt.Errorf("invalid host ID: %q", id)
+	}
+	return id, nil
+}
+
+// parseInitiatorName returns the IQN set by the InitiatorName line of
+// /etc/iscsi/initiatorname.iscsi.
+func parseInitiatorName(content string) (string, error) {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) != "InitiatorName" {
+			continue
+		}
+		iqn := strings.TrimSpace(value)
+		if !iqnRegex.MatchString(iqn) {
+			return "", fmt.Errorf("invalid initiator name: %q", iqn)
+		}
+		return iqn, nil
+	}
+	return "", fmt.Errorf("no InitiatorName found")
+}
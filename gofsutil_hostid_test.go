@@ -0,0 +1,82 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInitiatorName(t *testing.T) {
+	iqn, err := parseInitiatorName("## DO NOT EDIT\n#InitiatorName=iqn.1994-05.com.redhat:old\nInitiatorName = iqn.1994-05.com.redhat:a1b2c3d4e5f6\n")
+	require.NoError(t, err)
+	assert.Equal(t, "iqn.1994-05.com.redhat:a1b2c3d4e5f6", iqn)
+
+	_, err = parseInitiatorName("# no name\n")
+	assert.Error(t, err)
+	_, err = parseInitiatorName("InitiatorName=bogus\n")
+	assert.Error(t, err)
+}
+
+func TestHostIdentity(t *testing.T) {
+	etcRoot := t.TempDir()
+	fs := NewFS(FSOptions{EtcRoot: etcRoot})
+	ctx := context.Background()
+
+	// Nothing is created unless asked to.
+	_, err := fs.GetHostNQN(ctx, false)
+	assert.True(t, os.IsNotExist(err))
+	_, err = fs.GetHostIQN(ctx, false)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(etcRoot, "nvme"))
+	assert.True(t, os.IsNotExist(err))
+
+	nqn, err := fs.GetHostNQN(ctx, true)
+	require.NoError(t, err)
+	assert.Regexp(t, `^nqn\.2014-08\.org\.nvmexpress:uuid:[0-9a-f-]{36}$`, nqn)
+	content, err := os.ReadFile(filepath.Join(etcRoot, "nvme", "hostnqn"))
+	require.NoError(t, err)
+	assert.Equal(t, nqn+"\n", string(content))
+	again, err := fs.GetHostNQN(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, nqn, again)
+
+	// The host ID is the UUID of the host NQN.
+	id, err := fs.GetNQNHostID(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimPrefix(nqn, HostNQNUUIDPrefix), id)
+	content, err = os.ReadFile(filepath.Join(etcRoot, "nvme", "hostid"))
+	require.NoError(t, err)
+	assert.Equal(t, id+"\n", string(content))
+
+	iqn, err := fs.GetHostIQN(ctx, true)
+	require.NoError(t, err)
+	assert.Regexp(t, `^iqn\.2005-03\.org\.open-iscsi:[0-9a-f]{12}$`, iqn)
+	content, err = os.ReadFile(filepath.Join(etcRoot, "iscsi", "initiatorname.iscsi"))
+	require.NoError(t, err)
+	assert.Equal(t, "InitiatorName="+iqn+"\n", string(content))
+
+	// Malformed files are reported, not replaced.
+	require.NoError(t, os.WriteFile(filepath.Join(etcRoot, "nvme", "hostnqn"), []byte("garbage\n"), 0o600))
+	_, err = fs.GetHostNQN(ctx, true)
+	assert.Error(t, err)
+}
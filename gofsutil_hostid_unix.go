@@ -0,0 +1,116 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// readHostIdentity reads and parses a host identity file. If the file
+// does not exist and create is set, the file is written with the content
+// returned by generate.
+func (fs *FS) readHostIdentity(path string, parse func(string) (string, error), create bool, generate func() (string, string, error)) (string, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err == nil {
+		value, err := parse(string(content))
+		if err != nil {
+			return "", fmt.Errorf("%s: %v", path, err)
+		}
+		return value, nil
+	}
+	if !os.IsNotExist(err) || !create {
+		return "", err
+	}
+	value, data, err := generate()
+	if err != nil {
+		return "", err
+	}
+	if fs.dryRun(DryRunWrite, path, data) {
+		return value, nil
+	}
+	if err := writeFileAtomic(path, []byte(data), 0o644); err != nil {
+		return "", err
+	}
+	log.Infof("created %s with %s", path, value)
+	return value, nil
+}
+
+// writeFileAtomic writes data to a temporary file that is then renamed to
+// path, creating the parent directory if needed.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil { // #nosec G301
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // #nosec G104
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() // #nosec G104
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// getHostNQN returns the host NQN, creating /etc/nvme/hostnqn if it is
+// missing and create is set.
+func (fs *FS) getHostNQN(_ context.Context, create bool) (string, error) {
+	return fs.readHostIdentity(fs.etcPath(hostNQNPath), parseHostNQN, create, func() (string, string, error) {
+		nqn, err := GenerateHostNQN()
+		return nqn, nqn + "\n", err
+	})
+}
+
+// getNQNHostID returns the NVMe host ID. When /etc/nvme/hostid is missing
+// and create is set, it is created with the UUID of the host NQN, or a new
+// UUID if the host NQN is not UUID based.
+func (fs *FS) getNQNHostID(ctx context.Context, create bool) (string, error) {
+	return fs.readHostIdentity(fs.etcPath(hostIDPath), parseHostID, create, func() (string, string, error) {
+		id := ""
+		if nqn, err := fs.getHostNQN(ctx, false); err == nil {
+			id = hostIDFromNQN(nqn)
+		}
+		if id == "" {
+			var err error
+			if id, err = newUUID(); err != nil {
+				return "", "", err
+			}
+		}
+		return id, id + "\n", nil
+	})
+}
+
+// getHostIQN returns the iSCSI initiator name, creating
+// /etc/iscsi/initiatorname.iscsi if it is missing and create is set.
+func (fs *FS) getHostIQN(_ context.Context, create bool) (string, error) {
+	return fs.readHostIdentity(fs.etcPath(initiatorIQNPath), parseInitiatorName, create, func() (string, string, error) {
+		iqn, err := GenerateHostIQN()
+		return iqn, "InitiatorName=" + iqn + "\n", err
+	})
+}
@@ -0,0 +1,52 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeviceInUseReason is the reason a device is in use.
+type DeviceInUseReason string
+
+const (
+	// DeviceMounted is reported when a filesystem on the device, or on
+	// one of its partitions, is mounted.
+	DeviceMounted DeviceInUseReason = "mounted"
+	// DeviceHeld is reported when the device, or one of its partitions,
+	// is held by another block device, e.g. a device mapper (multipath,
+	// LVM) or MD RAID device.
+	DeviceHeld DeviceInUseReason = "held"
+	// DeviceOpenExclusive is reported when another process has the
+	// device open exclusively.
+	DeviceOpenExclusive DeviceInUseReason = "open exclusively"
+)
+
+// InUseError is returned by EnsureDeviceUnused when the device is in use.
+type InUseError struct {
+	// Device is the device that is in use.
+	Device string
+	// Reason is how the device is used.
+	Reason DeviceInUseReason
+	// Users are the mount points or holders using the device.
+	Users []string
+}
+
+func (e *InUseError) Error() string {
+	msg := fmt.Sprintf("device %s is in use: %s", e.Device, e.Reason)
+	if len(e.Users) > 0 {
+		msg += ": " + strings.Join(e.Users, ", ")
+	}
+	return msg
+}
@@ -0,0 +1,113 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// blockDeviceNames returns the sysfs name of the device, e.g. sdb or dm-3,
+// followed by the names of its partitions.
+func (fs *FS) blockDeviceNames(device string) ([]string, error) {
+	dev, err := filepath.EvalSymlinks(device)
+	if err != nil {
+		return nil, err
+	}
+	name := filepath.Base(dev)
+	names := []string{name}
+	dir := fs.sysPath(filepath.Join("/sys/class/block", name))
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), name) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, e.Name(), "partition")); err == nil {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// blockDeviceMountPoints returns the mount points of the filesystems on
+// the block devices with the given sysfs names.
+func (fs *FS) blockDeviceMountPoints(ctx context.Context, names []string) ([]string, error) {
+	isDevice := make(map[string]bool, len(names))
+	for _, name := range names {
+		isDevice[name] = true
+	}
+	mounts, err := fs.getMounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var mountPoints []string
+	for _, m := range mounts {
+		for _, source := range []string{m.Device, m.Source} {
+			if !strings.HasPrefix(source, "/dev/") {
+				continue
+			}
+			if dev, err := filepath.EvalSymlinks(fs.devPath(source)); err == nil && isDevice[filepath.Base(dev)] {
+				mountPoints = append(mountPoints, m.Path)
+				break
+			}
+		}
+	}
+	return mountPoints, nil
+}
+
+// ensureDeviceUnused returns an InUseError if a filesystem on the device
+// or its partitions is mounted, if the device or its partitions are held by
+// other block devices, or if the device is open exclusively.
+func (fs *FS) ensureDeviceUnused(ctx context.Context, device string) error {
+	names, err := fs.blockDeviceNames(device)
+	if err != nil {
+		return err
+	}
+	mountPoints, err := fs.blockDeviceMountPoints(ctx, names)
+	if err != nil {
+		return err
+	}
+	if len(mountPoints) > 0 {
+		return &InUseError{Device: device, Reason: DeviceMounted, Users: mountPoints}
+	}
+
+	var holders []string
+	for _, name := range names {
+		entries, _ := os.ReadDir(fs.sysPath(filepath.Join("/sys/class/block", name, "holders")))
+		for _, e := range entries {
+			holders = append(holders, e.Name())
+		}
+	}
+	if len(holders) > 0 {
+		return &InUseError{Device: device, Reason: DeviceHeld, Users: holders}
+	}
+
+	// The kernel refuses an exclusive open of a block device that is
+	// mounted, held or open exclusively.
+	fd, err := unix.Open(device, unix.O_RDONLY|unix.O_EXCL|unix.O_CLOEXEC, 0)
+	if err != nil {
+		if errors.Is(err, unix.EBUSY) {
+			return &InUseError{Device: device, Reason: DeviceOpenExclusive}
+		}
+		return &os.PathError{Op: "open", Path: device, Err: err}
+	}
+	return unix.Close(fd)
+}
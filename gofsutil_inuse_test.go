@@ -0,0 +1,81 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestEnsureDeviceUnused(t *testing.T) {
+	const device = "/dev/loop0"
+	fd, err := unix.Open(device, unix.O_RDONLY|unix.O_EXCL|unix.O_CLOEXEC, 0)
+	if err != nil {
+		t.Skipf("cannot open %s exclusively: %v", device, err)
+	}
+	require.NoError(t, unix.Close(fd))
+
+	sysRoot, devRoot, procRoot := t.TempDir(), t.TempDir(), t.TempDir()
+	blockDir := filepath.Join(sysRoot, "class", "block", "loop0")
+	require.NoError(t, os.MkdirAll(filepath.Join(blockDir, "holders"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(blockDir, "loop0p1", "holders"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(blockDir, "loop0p1", "partition"), []byte("1\n"), 0o600))
+	require.NoError(t, os.Symlink("loop0/loop0p1", filepath.Join(sysRoot, "class", "block", "loop0p1")))
+	require.NoError(t, os.WriteFile(filepath.Join(devRoot, "loop0p1"), nil, 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(procRoot, "self"), 0o750))
+	mountInfo := filepath.Join(procRoot, "self", "mountinfo")
+	require.NoError(t, os.WriteFile(mountInfo, []byte(mountKindMountInfo), 0o600))
+	fs := NewFS(FSOptions{SysRoot: sysRoot, DevRoot: devRoot, ProcRoot: procRoot})
+	ctx := context.Background()
+
+	assert.NoError(t, fs.EnsureDeviceUnused(ctx, device))
+
+	// A mounted partition.
+	mounted := mountKindMountInfo + "30 22 7:1 / /mnt/data rw,relatime - xfs /dev/loop0p1 rw\n"
+	require.NoError(t, os.WriteFile(mountInfo, []byte(mounted), 0o600))
+	err = fs.EnsureDeviceUnused(ctx, device)
+	var inUse *InUseError
+	require.True(t, errors.As(err, &inUse), err)
+	assert.Equal(t, DeviceMounted, inUse.Reason)
+	assert.Equal(t, []string{"/mnt/data"}, inUse.Users)
+	require.NoError(t, os.WriteFile(mountInfo, []byte(mountKindMountInfo), 0o600))
+
+	// A partition held by a device mapper device.
+	require.NoError(t, os.WriteFile(filepath.Join(blockDir, "loop0p1", "holders", "dm-3"), nil, 0o600))
+	err = fs.EnsureDeviceUnused(ctx, device)
+	require.True(t, errors.As(err, &inUse), err)
+	assert.Equal(t, DeviceHeld, inUse.Reason)
+	assert.Equal(t, []string{"dm-3"}, inUse.Users)
+	assert.Equal(t, "device /dev/loop0 is in use: held: dm-3", err.Error())
+	require.NoError(t, os.Remove(filepath.Join(blockDir, "loop0p1", "holders", "dm-3")))
+
+	// Another process has the device open exclusively.
+	fd, err = unix.Open(device, unix.O_RDONLY|unix.O_EXCL|unix.O_CLOEXEC, 0)
+	require.NoError(t, err)
+	err = fs.EnsureDeviceUnused(ctx, device)
+	require.NoError(t, unix.Close(fd))
+	require.True(t, errors.As(err, &inUse), err)
+	assert.Equal(t, DeviceOpenExclusive, inUse.Reason)
+
+	assert.Error(t, fs.EnsureDeviceUnused(ctx, "/dev/does-not-exist"))
+}
@@ -0,0 +1,162 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	iscsiSessionsPath    = "/sys/class/iscsi_session"
+	iscsiConnectionsPath = "/sys/class/iscsi_connection"
+)
+
+// ISCSISessionState is the state of an iSCSI session.
+type ISCSISessionState string
+
+const (
+	// ISCSISessionLoggedIn is the state of a session that is logged in.
+	ISCSISessionLoggedIn ISCSISessionState = "LOGGED_IN"
+	// ISCSISessionFailed is the state of a session whose connection was
+	// lost and is being recovered.
+	ISCSISessionFailed ISCSISessionState = "FAILED"
+	// ISCSISessionFree is the state of a session that was never logged in
+	// or was logged out.
+	ISCSISessionFree ISCSISessionState = "FREE"
+)
+
+// ISCSIConnection is a connection of an iSCSI session.
+type ISCSIConnection struct {
+	// Name is the name of the connection, e.g. connection3:0.
+	Name string
+	// State is the state of the connection, e.g. up, if the kernel
+	// reports it.
+	State string
+	// Address is the address of the target portal.
+	Address string
+	// Port is the port of the target portal.
+	Port string
+}
+
+// ISCSISession describes an iSCSI session and the health of its devices.
+type ISCSISession struct {
+	// Name is the name of the session, e.g. session3.
+	Name string
+	// TargetName is the IQN of the target.
+	TargetName string
+	// TPGT is the target portal group tag.
+	TPGT string
+	// State is the session state.
+	State ISCSISessionState
+	// Connections are the connections of the session.
+	Connections []ISCSIConnection
+	// IOErrorCount is the number of I/O errors of the session's devices.
+	IOErrorCount int64
+	// IOTimeoutCount is the number of I/O timeouts of the session's
+	// devices.
+	IOTimeoutCount int64
+}
+
+// LoggedIn returns true if the session is logged in.
+func (s ISCSISession) LoggedIn() bool {
+	return s.State == ISCSISessionLoggedIn
+}
+
+// ISCSIPortalHost is a SCSI host with a session to a target portal.
+type ISCSIPortalHost struct {
+	// Host is the scsi host of the session, e.g. host3.
+	Host string
+	// Channel is the channel of the SCSI target of the session.
+	Channel string
+	// Target is the id of the SCSI target of the session.
+	Target string
+	// Session is the name of the session, e.g. session3.
+	Session string
+	// TargetName is the IQN of the target.
+	TargetName string
+	// Address is the address of the portal the session is connected to.
+	Address string
+	// Port is the port of the portal the session is connected to.
+	Port string
+}
+
+// ISCSITargetDevice is a device of an iSCSI LUN found in
+// /dev/disk/by-path.
+type ISCSITargetDevice struct {
+	// Portal is the address of the target portal, e.g. 1.1.1.1:3260 or
+	// [fe80::1]:3260.
+	Portal string
+	// IQN is the name of the target.
+	IQN string
+	// LUN is the logical unit number.
+	LUN int
+	// Device is the device path, e.g. /dev/sdc.
+	Device string
+	// Path is the /dev/disk/by-path link of the device.
+	Path string
+}
+
+// parseISCSIByPathName parses the name of a /dev/disk/by-path link of an
+// iSCSI LUN, e.g.
+// ip-1.1.1.1:3260-iscsi-iqn.1992-04.com.emc:600009700bcbb70e3287017400000000-lun-0.
+// LUNs in 64-bit notation, e.g. lun-0x0101000000000000, are converted to
+// their number using the first level of the LUN. Partitions are not
+// parsed.
+func parseISCSIByPathName(name string) (portal, iqn string, lun int, ok bool) {
+	rest, ok := strings.CutPrefix(name, "ip-")
+	if !ok {
+		return "", "", 0, false
+	}
+	portal, rest, ok = strings.Cut(rest, "-iscsi-")
+	if !ok || portal == "" {
+		return "", "", 0, false
+	}
+	i := strings.LastIndex(rest, "-lun-")
+	if i <= 0 {
+		return "", "", 0, false
+	}
+	iqn, lunStr := rest[:i], rest[i+len("-lun-"):]
+	if hex, isHex := strings.CutPrefix(lunStr, "0x"); isHex {
+		v, err := strconv.ParseUint(hex, 16, 64)
+		if err != nil || len(hex) != 16 {
+			return "", "", 0, false
+		}
+		return portal, iqn, int(v >> 48), true
+	}
+	n, err := strconv.Atoi(lunStr)
+	if err != nil || n < 0 {
+		return "", "", 0, false
+	}
+	return portal, iqn, n, true
+}
+
+// splitPortal returns the address and, if any, the port of a target
+// portal, e.g. 10.0.0.1, 10.0.0.1:3260, fe80::1 or [fe80::1]:3260.
+func splitPortal(portal string) (addr, port string) {
+	if host, p, err := net.SplitHostPort(portal); err == nil {
+		return host, p
+	}
+	return strings.Trim(portal, "[]"), ""
+}
+
+// sameAddress returns true if the addresses a and b are the same IP
+// address, or the same name.
+func sameAddress(a, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA != nil && ipB != nil {
+		return ipA.Equal(ipB)
+	}
+	return a != "" && strings.EqualFold(a, b)
+}
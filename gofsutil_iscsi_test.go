@@ -0,0 +1,197 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSysfsAttrs(t *testing.T, dir string, attrs map[string]string) {
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+	for attr, value := range attrs {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, attr), []byte(value+"\n"), 0o600))
+	}
+}
+
+func TestGetISCSISessionState(t *testing.T) {
+	const (
+		iqn1 = "iqn.2015-10.com.dell:dellemc-powerstore-apm00000000001-a-1"
+		iqn2 = "iqn.2015-10.com.dell:dellemc-powerstore-apm00000000001-b-1"
+	)
+	sysRoot := t.TempDir()
+	fs := NewFS(FSOptions{SysRoot: sysRoot})
+	ctx := context.Background()
+
+	sessions, err := fs.GetISCSISessionState(ctx, iqn1)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	sessionsDir := filepath.Join(sysRoot, "class", "iscsi_session")
+	connsDir := filepath.Join(sysRoot, "class", "iscsi_connection")
+	writeSysfsAttrs(t, filepath.Join(sessionsDir, "session1"), map[string]string{
+		"targetname": iqn1, "tpgt": "1", "state": "LOGGED_IN",
+	})
+	writeSysfsAttrs(t, filepath.Join(sessionsDir, "session1", "device", "target3:0:0", "3:0:0:1"), map[string]string{
+		"ioerr_cnt": "0x2", "iotmo_cnt": "0x1",
+	})
+	writeSysfsAttrs(t, filepath.Join(sessionsDir, "session1", "device", "target3:0:0", "3:0:0:2"), map[string]string{
+		"ioerr_cnt": "0xa", "iotmo_cnt": "0x0",
+	})
+	writeSysfsAttrs(t, filepath.Join(sessionsDir, "session2"), map[string]string{
+		"targetname": iqn2, "tpgt": "2", "state": "FAILED",
+	})
+	writeSysfsAttrs(t, filepath.Join(connsDir, "connection1:0"), map[string]string{
+		"state": "up", "persistent_address": "10.0.0.1", "persistent_port": "3260",
+	})
+	writeSysfsAttrs(t, filepath.Join(connsDir, "connection2:0"), map[string]string{
+		"persistent_address": "10.0.0.2", "persistent_port": "3260",
+	})
+
+	sessions, err = fs.GetISCSISessionState(ctx, iqn1)
+	require.NoError(t, err)
+	assert.Equal(t, []ISCSISession{{
+		Name:       "session1",
+		TargetName: iqn1,
+		TPGT:       "1",
+		State:      ISCSISessionLoggedIn,
+		Connections: []ISCSIConnection{
+			{Name: "connection1:0", State: "up", Address: "10.0.0.1", Port: "3260"},
+		},
+		IOErrorCount:   12,
+		IOTimeoutCount: 1,
+	}}, sessions)
+	assert.True(t, sessions[0].LoggedIn())
+
+	sessions, err = fs.GetISCSISessionState(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.False(t, sessions[1].LoggedIn())
+	assert.Equal(t, []ISCSIConnection{
+		{Name: "connection2:0", Address: "10.0.0.2", Port: "3260"},
+	}, sessions[1].Connections)
+}
+
+func TestParseISCSIByPathName(t *testing.T) {
+	const iqn = "iqn.1992-04.com.emc:600009700bcbb70e3287017400000000"
+	tests := []struct {
+		name   string
+		portal string
+		iqn    string
+		lun    int
+		ok     bool
+	}{
+		{name: "ip-1.1.1.1:3260-iscsi-" + iqn + "-lun-0", portal: "1.1.1.1:3260", iqn: iqn, lun: 0, ok: true},
+		{name: "ip-1.1.1.1:3260-iscsi-" + iqn + "-lun-12", portal: "1.1.1.1:3260", iqn: iqn, lun: 12, ok: true},
+		{name: "ip-[fe80::1]:3260-iscsi-" + iqn + "-lun-0x0101000000000000", portal: "[fe80::1]:3260", iqn: iqn, lun: 257, ok: true},
+		{name: "ip-1.1.1.1:3260-iscsi-" + iqn + "-lun-1-part1"},
+		{name: "ip-1.1.1.1:3260-iscsi-" + iqn + "-lun-0x01"},
+		{name: "ip-1.1.1.1:3260-fc-0x5006016844602198-lun-1"},
+		{name: "pci-0000:00:1f.2-ata-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			portal, iqn, lun, ok := parseISCSIByPathName(tt.name)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.portal, portal)
+			assert.Equal(t, tt.iqn, iqn)
+			assert.Equal(t, tt.lun, lun)
+		})
+	}
+}
+
+func TestGetISCSITargetsFromByPath(t *testing.T) {
+	const iqn = "iqn.2015-10.com.dell:dellemc-powerstore-apm00000000001-a-1"
+	devRoot := t.TempDir()
+	fs := NewFS(FSOptions{DevRoot: devRoot})
+	ctx := context.Background()
+
+	targets, err := fs.GetISCSITargetsFromByPath(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, targets)
+
+	byPath := filepath.Join(devRoot, "disk", "by-path")
+	require.NoError(t, os.MkdirAll(byPath, 0o750))
+	links := map[string]string{
+		"ip-1.1.1.1:3260-iscsi-" + iqn + "-lun-1":       "../../sdc",
+		"ip-1.1.1.1:3260-iscsi-" + iqn + "-lun-1-part1": "../../sdc1",
+		"pci-0000:00:1f.2-ata-1":                        "../../sda",
+	}
+	for name, target := range links {
+		require.NoError(t, os.Symlink(target, filepath.Join(byPath, name)))
+	}
+
+	targets, err = fs.GetISCSITargetsFromByPath(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []ISCSITargetDevice{{
+		Portal: "1.1.1.1:3260",
+		IQN:    iqn,
+		LUN:    1,
+		Device: filepath.Join(devRoot, "sdc"),
+		Path:   filepath.Join(byPath, "ip-1.1.1.1:3260-iscsi-"+iqn+"-lun-1"),
+	}}, targets)
+}
+
+func TestGetISCSIHostsForTargetPortal(t *testing.T) {
+	const iqn = "iqn.2015-10.com.dell:dellemc-powerstore-apm00000000001-a-1"
+	sysRoot := t.TempDir()
+	fs := NewFS(FSOptions{SysRoot: sysRoot})
+	ctx := context.Background()
+
+	hosts, err := fs.GetISCSIHostsForTargetPortal(ctx, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Empty(t, hosts)
+
+	sessionsDir := filepath.Join(sysRoot, "class", "iscsi_session")
+	connsDir := filepath.Join(sysRoot, "class", "iscsi_connection")
+	writeSysfsAttrs(t, filepath.Join(sessionsDir, "session1"), map[string]string{"targetname": iqn})
+	require.NoError(t, os.MkdirAll(filepath.Join(sessionsDir, "session1", "device", "target3:0:0"), 0o755))
+	writeSysfsAttrs(t, filepath.Join(sessionsDir, "session2"), map[string]string{"targetname": iqn})
+	require.NoError(t, os.MkdirAll(filepath.Join(sessionsDir, "session2", "device", "target4:0:1"), 0o755))
+	writeSysfsAttrs(t, filepath.Join(connsDir, "connection1:0"), map[string]string{
+		"address": "10.0.0.1", "port": "3260", "persistent_address": "10.0.0.1", "persistent_port": "3260",
+	})
+	writeSysfsAttrs(t, filepath.Join(connsDir, "connection2:0"), map[string]string{
+		"address": "10.0.0.9", "port": "3260", "persistent_address": "fe80::0:1", "persistent_port": "3261",
+	})
+
+	hosts, err = fs.GetISCSIHostsForTargetPortal(ctx, "10.0.0.1:3260")
+	require.NoError(t, err)
+	assert.Equal(t, []ISCSIPortalHost{{
+		Host: "host3", Channel: "0", Target: "0", Session: "session1",
+		TargetName: iqn, Address: "10.0.0.1", Port: "3260",
+	}}, hosts)
+
+	for _, portal := range []string{"fe80::1", "[fe80::1]:3261"} {
+		hosts, err = fs.GetISCSIHostsForTargetPortal(ctx, portal)
+		require.NoError(t, err)
+		require.Len(t, hosts, 1, portal)
+		assert.Equal(t, "host4", hosts[0].Host)
+		assert.Equal(t, "1", hosts[0].Target)
+	}
+
+	hosts, err = fs.GetISCSIHostsForTargetPortal(ctx, "10.0.0.1:3261")
+	require.NoError(t, err)
+	assert.Empty(t, hosts)
+
+	_, err = fs.GetISCSIHostsForTargetPortal(ctx, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,212 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// getISCSISessionState returns the sessions to the target, or all the
+// sessions if targetIQN is empty.
+func (fs *FS) getISCSISessionState(_ context.Context, targetIQN string) ([]ISCSISession, error) {
+	sessions := make([]ISCSISession, 0)
+	sessionsDir := fs.sysPath(iscsiSessionsPath)
+	entries, err := os.ReadDir(sessionsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// No iSCSI sessions have been established.
+			return sessions, nil
+		}
+		log.WithField("error", err).Error("Cannot read directory: " + sessionsDir)
+		return sessions, err
+	}
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), "session") {
+			continue
+		}
+		dir := filepath.Join(sessionsDir, e.Name())
+		target := readSysfsAttr(filepath.Join(dir, "targetname"))
+		if targetIQN != "" && target != targetIQN {
+			continue
+		}
+		session := ISCSISession{
+			Name:        e.Name(),
+			TargetName:  target,
+			TPGT:        readSysfsAttr(filepath.Join(dir, "tpgt")),
+			State:       ISCSISessionState(readSysfsAttr(filepath.Join(dir, "state"))),
+			Connections: fs.iscsiConnections(strings.TrimPrefix(e.Name(), "session")),
+		}
+		session.IOErrorCount, session.IOTimeoutCount = scsiDeviceErrorCounts(filepath.Join(dir, "device"))
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// iscsiConnections returns the connections of the session with the given
+// number.
+func (fs *FS) iscsiConnections(session string) []ISCSIConnection {
+	conns := make([]ISCSIConnection, 0)
+	connsDir := fs.sysPath(iscsiConnectionsPath)
+	entries, _ := os.ReadDir(connsDir)
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), "connection"+session+":") {
+			continue
+		}
+		dir := filepath.Join(connsDir, e.Name())
+		conns = append(conns, ISCSIConnection{
+			Name:    e.Name(),
+			State:   readSysfsAttr(filepath.Join(dir, "state")),
+			Address: readSysfsAttr(filepath.Join(dir, "persistent_address")),
+			Port:    readSysfsAttr(filepath.Join(dir, "persistent_port")),
+		})
+	}
+	return conns
+}
+
+// getISCSIHostsForTargetPortal returns the hosts of the sessions with a
+// connection to portal, matched on the current or persistent address and,
+// if portal has one, port of the connections.
+func (fs *FS) getISCSIHostsForTargetPortal(_ context.Context, portal string) ([]ISCSIPortalHost, error) {
+	addr, port := splitPortal(portal)
+	if addr == "" {
+		return nil, fmt.Errorf("iSCSI target portal: %q is invalid", portal)
+	}
+	hosts := make([]ISCSIPortalHost, 0)
+	connsDir := fs.sysPath(iscsiConnectionsPath)
+	entries, err := os.ReadDir(connsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// No iSCSI sessions have been established.
+			return hosts, nil
+		}
+		log.WithField("error", err).Error("Cannot read directory: " + connsDir)
+		return hosts, err
+	}
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		number, _, ok := strings.Cut(strings.TrimPrefix(e.Name(), "connection"), ":")
+		if !ok || !strings.HasPrefix(e.Name(), "connection") {
+			continue
+		}
+		session := "session" + number
+		if seen[session] {
+			continue
+		}
+		dir := filepath.Join(connsDir, e.Name())
+		connAddr, connPort, matched := "", "", false
+		for _, attrs := range [][2]string{{"address", "port"}, {"persistent_address", "persistent_port"}} {
+			connAddr = readSysfsAttr(filepath.Join(dir, attrs[0]))
+			connPort = readSysfsAttr(filepath.Join(dir, attrs[1]))
+			if sameAddress(connAddr, addr) && (port == "" || port == connPort) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		sessionDir := filepath.Join(fs.sysPath(iscsiSessionsPath), session)
+		target, ok := iscsiSessionTarget(filepath.Join(sessionDir, "device"))
+		if !ok {
+			continue
+		}
+		seen[session] = true
+		hosts = append(hosts, ISCSIPortalHost{
+			Host:       target.host,
+			Channel:    target.channel,
+			Target:     target.target,
+			Session:    session,
+			TargetName: readSysfsAttr(filepath.Join(sessionDir, "targetname")),
+			Address:    connAddr,
+			Port:       connPort,
+		})
+	}
+	return hosts, nil
+}
+
+// scsiDeviceErrorCounts sums the I/O error and timeout counters of the
+// SCSI devices below the session device directory, which are found in
+// targetH:C:T/H:C:T:L.
+func scsiDeviceErrorCounts(sessionDeviceDir string) (ioErrors, ioTimeouts int64) {
+	targets, _ := os.ReadDir(sessionDeviceDir)
+	for _, target := range targets {
+		if !strings.HasPrefix(target.Name(), "target") {
+			continue
+		}
+		targetDir := filepath.Join(sessionDeviceDir, target.Name())
+		devices, _ := os.ReadDir(targetDir)
+		for _, device := range devices {
+			if strings.Count(device.Name(), ":") != 3 {
+				continue
+			}
+			deviceDir := filepath.Join(targetDir, device.Name())
+			ioErrors += readSysfsCounter(filepath.Join(deviceDir, "ioerr_cnt"))
+			ioTimeouts += readSysfsCounter(filepath.Join(deviceDir, "iotmo_cnt"))
+		}
+	}
+	return ioErrors, ioTimeouts
+}
+
+// readSysfsCounter returns the value of a counter attribute, which the
+// SCSI layer reports in hex, or 0 if it cannot be read.
+func readSysfsCounter(path string) int64 {
+	n, err := strconv.ParseInt(readSysfsAttr(path), 0, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// getISCSITargetsFromByPath returns the devices of the iSCSI LUNs in
+// /dev/disk/by-path.
+func (fs *FS) getISCSITargetsFromByPath(_ context.Context) ([]ISCSITargetDevice, error) {
+	devices := make([]ISCSITargetDevice, 0)
+	byPathDir := fs.devPath("/dev/disk/by-path")
+	entries, err := os.ReadDir(byPathDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return devices, nil
+		}
+		return nil, err
+	}
+	for _, entry := range entries {
+		portal, iqn, lun, ok := parseISCSIByPathName(entry.Name())
+		if !ok {
+			continue
+		}
+		path := filepath.Join(byPathDir, entry.Name())
+		devPath, err := os.Readlink(path)
+		if err != nil {
+			log.WithField("path", path).WithError(err).Debug("cannot read by-path link")
+			continue
+		}
+		devices = append(devices, ISCSITargetDevice{
+			Portal: portal,
+			IQN:    iqn,
+			LUN:    lun,
+			Device: fs.devPath("/dev/" + filepath.Base(devPath)),
+			Path:   path,
+		})
+	}
+	return devices, nil
+}
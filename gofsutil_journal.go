@@ -0,0 +1,387 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// JournalOp is the kind of a multi-step operation recorded in the
+// journal.
+type JournalOp string
+
+const (
+	// JournalOpFormatAndMount is the format and mount of an unformatted
+	// device by FormatAndMount.
+	JournalOpFormatAndMount JournalOp = "format-and-mount"
+	// JournalOpDeviceCleanup is the multipath flush and path device
+	// removal of CleanupDeviceForWWN.
+	JournalOpDeviceCleanup JournalOp = "device-cleanup"
+)
+
+// JournalStep is the last step of an operation recorded in the journal.
+type JournalStep string
+
+const (
+	// JournalStepFormatting is recorded before a device is formatted.
+	JournalStepFormatting JournalStep = "formatting"
+	// JournalStepFormatted is recorded once the device is formatted and
+	// before it is mounted.
+	JournalStepFormatted JournalStep = "formatted"
+	// JournalStepFlushing is recorded before a multipath map is flushed
+	// and its path devices removed.
+	JournalStepFlushing JournalStep = "flushing"
+	// JournalStepFlushed is recorded once the multipath map is flushed
+	// and before the path devices are removed.
+	JournalStepFlushed JournalStep = "flushed"
+)
+
+// JournalReplayAction is what ReplayIncompleteOperations did with an
+// interrupted operation.
+type JournalReplayAction string
+
+const (
+	// JournalCompleted is the action of an operation that was finished.
+	JournalCompleted JournalReplayAction = "completed"
+	// JournalRolledBack is the action of an operation that was undone,
+	// e.g. the partial filesystem of an interrupted format was wiped so
+	// that the next FormatAndMount formats the device again.
+	JournalRolledBack JournalReplayAction = "rolled-back"
+	// JournalFailed is the action of an operation that could not be
+	// replayed. It is kept in the journal.
+	JournalFailed JournalReplayAction = "failed"
+	// JournalDiscarded is the action of an operation that could not be
+	// replayed and was started longer than FSOptions.JournalMaxAge ago,
+	// e.g. because its volume was deleted. It is removed from the journal.
+	JournalDiscarded JournalReplayAction = "discarded"
+)
+
+// journalFileSuffix is the suffix of the journal entry files.
+const journalFileSuffix = ".json"
+
+var (
+	// ErrJournalDeviceMismatch is returned for an interrupted format and
+	// mount whose volume cannot be found by the WWN recorded when the
+	// operation started, or that was recorded without WWN. The operation
+	// is not replayed.
+	ErrJournalDeviceMismatch = errors.New("device does not match the journal entry")
+
+	// ErrJournalRollbackDisabled is returned for an interrupted format
+	// when FSOptions.JournalRollback is not set. The operation is kept in
+	// the journal.
+	ErrJournalRollbackDisabled = errors.New("rollback of interrupted formats is disabled")
+)
+
+// JournalEntry is the intent of a multi-step operation and the last step
+// it completed, recorded in FSOptions.JournalDir until the operation
+// returns.
+type JournalEntry struct {
+	// ID is the identifier of the entry, the name of its file.
+	ID string `json:"id"`
+	// Op is the kind of the operation.
+	Op JournalOp `json:"op"`
+	// Step is the last step recorded.
+	Step JournalStep `json:"step"`
+	// Device is the device of the operation, e.g. /dev/sdc. The kernel
+	// name may belong to another volume after a restart, a format and
+	// mount is replayed on the device of WWN instead.
+	Device string `json:"device,omitempty"`
+	// Target is the mount point of the operation.
+	Target string `json:"target,omitempty"`
+	// FsType is the filesystem type of the operation.
+	FsType string `json:"fsType,omitempty"`
+	// Options are the mount options of the operation.
+	Options []string `json:"options,omitempty"`
+	// WWN is the WWN of the volume of the operation. For a format and
+	// mount it is the WWN the device had when the operation started.
+	WWN string `json:"wwn,omitempty"`
+	// Started is when the operation was recorded.
+	Started time.Time `json:"started"`
+	// Updated is when the last step was recorded.
+	Updated time.Time `json:"updated"`
+}
+
+// JournalReplay is the outcome of replaying an interrupted operation.
+type JournalReplay struct {
+	// Entry is the journal entry of the operation.
+	Entry JournalEntry
+	// Action is what was done with the operation.
+	Action JournalReplayAction
+	// Err is the error of a failed replay.
+	Err error
+}
+
+// journalSeq makes the IDs of the entries created in the same nanosecond
+// unique.
+var journalSeq atomic.Uint64
+
+// journalEntryPath returns the file of the entry with the given ID.
+func (fs *FS) journalEntryPath(id string) string {
+	return filepath.Join(fs.JournalDir, id+journalFileSuffix)
+}
+
+// journalBegin records the intent of an operation and returns its entry,
+// nil if journaling is off. A failure to record it is logged, the
+// operation proceeds without journal.
+func (fs *FS) journalBegin(e JournalEntry) *JournalEntry {
+	if fs.JournalDir == "" || fs.DryRun {
+		return nil
+	}
+	now := time.Now()
+	e.ID = fmt.Sprintf("%s-%s-%d", e.Op, strconv.FormatInt(now.UnixNano(), 36), journalSeq.Add(1))
+	e.Started, e.Updated = now, now
+	if err := fs.journalWrite(&e); err != nil {
+		log.WithField("op", e.Op).WithError(err).Warn("failed to record operation in the journal")
+		return nil
+	}
+	return &e
+}
+
+// journalStep records that the operation of e reached step.
+func (fs *FS) journalStep(e *JournalEntry, step JournalStep) {
+	if e == nil {
+		return
+	}
+	e.Step = step
+	e.Updated = time.Now()
+	if err := fs.journalWrite(e); err != nil {
+		log.WithField("id", e.ID).WithError(err).Warn("failed to record operation step in the journal")
+	}
+}
+
+// journalEnd removes the entry of an operation that returned.
+func (fs *FS) journalEnd(e *JournalEntry) {
+	if e == nil {
+		return
+	}
+	if err := os.Remove(fs.journalEntryPath(e.ID)); err != nil && !os.IsNotExist(err) {
+		log.WithField("id", e.ID).WithError(err).Warn("failed to remove operation from the journal")
+	}
+}
+
+// journalWrite writes the entry to a temporary file that replaces its
+// file once synced, so that a crash leaves either entry intact.
+func (fs *FS) journalWrite(e *JournalEntry) error {
+	if err := os.MkdirAll(fs.JournalDir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	path := fs.journalEntryPath(e.ID)
+	tmp, err := os.CreateTemp(fs.JournalDir, ".tmp-"+e.ID+"-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // #nosec G307
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() // #nosec G104
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close() // #nosec G104
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// journalEntries returns the entries of the journal, oldest first.
+func (fs *FS) journalEntries() ([]JournalEntry, error) {
+	entries := make([]JournalEntry, 0)
+	if fs.JournalDir == "" {
+		return entries, nil
+	}
+	files, err := os.ReadDir(fs.JournalDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		return entries, err
+	}
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") || !strings.HasSuffix(f.Name(), journalFileSuffix) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(fs.JournalDir, f.Name())) // #nosec G304
+		if err != nil {
+			return entries, err
+		}
+		var e JournalEntry
+		if err := json.Unmarshal(data, &e); err != nil {
+			log.WithField("file", f.Name()).WithError(err).Warn("ignoring invalid journal entry")
+			continue
+		}
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Started.Before(entries[j].Started)
+	})
+	return entries, nil
+}
+
+// replayIncompleteOperations finishes or rolls back the operations left
+// in the journal, oldest first. Entries that are replayed are removed,
+// failed ones are kept for the next replay unless they are older than
+// FSOptions.JournalMaxAge.
+func (fs *FS) replayIncompleteOperations(ctx context.Context) ([]JournalReplay, error) {
+	entries, err := fs.journalEntries()
+	if err != nil {
+		return nil, err
+	}
+	replays := make([]JournalReplay, 0, len(entries))
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return replays, err
+		}
+		f := log.Fields{
+			"id":   e.ID,
+			"op":   e.Op,
+			"step": e.Step,
+		}
+		action, err := fs.replayOperation(ctx, e)
+		entry := e
+		if err != nil {
+			if fs.JournalMaxAge > 0 && time.Since(e.Started) > fs.JournalMaxAge {
+				log.WithFields(f).WithError(err).Warn("discarding interrupted operation that failed to replay")
+				fs.journalEnd(&entry)
+				replays = append(replays, JournalReplay{Entry: e, Action: JournalDiscarded, Err: err})
+				continue
+			}
+			log.WithFields(f).WithError(err).Error("failed to replay interrupted operation")
+			replays = append(replays, JournalReplay{Entry: e, Action: JournalFailed, Err: err})
+			continue
+		}
+		log.WithFields(f).WithField("action", action).Info("replayed interrupted operation")
+		fs.journalEnd(&entry)
+		replays = append(replays, JournalReplay{Entry: e, Action: action})
+	}
+	return replays, nil
+}
+
+// replayOperation finishes or rolls back the operation of e.
+func (fs *FS) replayOperation(ctx context.Context, e JournalEntry) (JournalReplayAction, error) {
+	switch e.Op {
+	case JournalOpFormatAndMount:
+		// The kernel name of the device may belong to another volume
+		// since the operation was recorded.
+		device, err := fs.resolveJournalDevice(ctx, e)
+		if err != nil {
+			return JournalFailed, err
+		}
+		mounts, err := fs.getDevMounts(ctx, device)
+		if err != nil {
+			return JournalFailed, err
+		}
+		for _, m := range mounts {
+			if m.Path == e.Target {
+				return JournalCompleted, nil
+			}
+		}
+		if e.Step == JournalStepFormatting {
+			// The format was interrupted: wipe what mkfs wrote so that
+			// the device is formatted again instead of failing to mount.
+			if !fs.JournalRollback {
+				return JournalFailed, fmt.Errorf("%w: not wiping %s", ErrJournalRollbackDisabled, device)
+			}
+			if len(mounts) > 0 {
+				return JournalFailed, &InUseError{Device: device, Reason: DeviceMounted, Users: mountPaths(mounts)}
+			}
+			return fs.rollbackFormat(ctx, e, device)
+		}
+		if err := fs.mount(ctx, device, e.Target, e.FsType, e.Options...); err != nil {
+			return JournalFailed, err
+		}
+		return JournalCompleted, nil
+	case JournalOpDeviceCleanup:
+		if _, err := fs.cleanupDeviceForWWN(ctx, e.WWN); err != nil {
+			return JournalFailed, err
+		}
+		return JournalCompleted, nil
+	}
+	return JournalFailed, errors.New("unknown journal operation: " + string(e.Op))
+}
+
+// resolveJournalDevice returns the device of the volume with the WWN
+// recorded in e, found by its /dev/disk/by-id link, e.g. its multipath
+// map. It returns an error wrapping ErrJournalDeviceMismatch if e has no
+// WWN or the volume has no device that reports it.
+func (fs *FS) resolveJournalDevice(ctx context.Context, e JournalEntry) (string, error) {
+	if e.WWN == "" {
+		return "", fmt.Errorf("%w: no WWN of %s was recorded", ErrJournalDeviceMismatch, e.Device)
+	}
+	_, devPath, err := fs.wwnToDevicePath(ctx, e.WWN)
+	if err != nil {
+		return "", fmt.Errorf("%w: no device with WWN %s: %v", ErrJournalDeviceMismatch, e.WWN, err)
+	}
+	device := "/dev/" + filepath.Base(devPath)
+	// The link may be stale until udev updates it.
+	wwn, err := fs.deviceWWN(device)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrJournalDeviceMismatch, err)
+	}
+	if wwn != e.WWN {
+		return "", fmt.Errorf("%w: %s has WWN %q, the journal recorded %q", ErrJournalDeviceMismatch, device, wwn, e.WWN)
+	}
+	return device, nil
+}
+
+// rollbackFormat wipes the filesystem signature an interrupted format of
+// e wrote on device. The device is only wiped if blkid finds a signature
+// of the filesystem type of e; a device without signature has nothing to
+// roll back.
+func (fs *FS) rollbackFormat(ctx context.Context, e JournalEntry, device string) (JournalReplayAction, error) {
+	// blkid exits with 2 when it finds no signature.
+	out, err := fs.commandContext(ctx, "blkid", "-p", "-s", "TYPE", "-o", "value", device).Output()
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 2) {
+		return JournalFailed, fmt.Errorf("failed to probe %s: %v", device, err)
+	}
+	switch fsType := strings.TrimSpace(string(out)); fsType {
+	case "":
+		return JournalRolledBack, nil
+	case e.FsType:
+	default:
+		return JournalFailed, fmt.Errorf("not wiping %s: it holds a %s signature, the format was of %s", device, fsType, e.FsType)
+	}
+	out, err = fs.commandContext(ctx, "wipefs", "-a", "-t", e.FsType, device).CombinedOutput()
+	if err != nil {
+		return JournalFailed, fmt.Errorf("failed to wipe %s: %v: %s", device, err, strings.TrimSpace(string(out)))
+	}
+	return JournalRolledBack, nil
+}
+
+// mountPaths returns the mount points of mounts.
+func mountPaths(mounts []Info) []string {
+	paths := make([]string, 0, len(mounts))
+	for _, m := range mounts {
+		paths = append(paths, m.Path)
+	}
+	return paths
+}
@@ -0,0 +1,178 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournal(t *testing.T) {
+	assert.Nil(t, NewFS(FSOptions{}).journalBegin(JournalEntry{Op: JournalOpDeviceCleanup}))
+
+	dir := filepath.Join(t.TempDir(), "journal")
+	fs := NewFS(FSOptions{JournalDir: dir})
+	first := fs.journalBegin(JournalEntry{Op: JournalOpFormatAndMount, Step: JournalStepFormatting, Device: "/dev/sdc"})
+	require.NotNil(t, first)
+	time.Sleep(time.Millisecond)
+	second := fs.journalBegin(JournalEntry{Op: JournalOpDeviceCleanup, Step: JournalStepFlushing, WWN: "60000970"})
+	require.NotNil(t, second)
+	assert.NotEqual(t, first.ID, second.ID)
+	fs.journalStep(first, JournalStepFormatted)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.json"), []byte("{"), 0o600))
+
+	entries, err := fs.journalEntries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, first.ID, entries[0].ID)
+	assert.Equal(t, JournalStepFormatted, entries[0].Step)
+	assert.Equal(t, "60000970", entries[1].WWN)
+
+	fs.journalEnd(first)
+	fs.journalEnd(second)
+	fs.journalEnd(nil)
+	entries, err = fs.journalEntries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// Dry runs change nothing, not even the journal.
+	fs.DryRun = true
+	assert.Nil(t, fs.journalBegin(JournalEntry{Op: JournalOpDeviceCleanup}))
+}
+
+func TestReplayIncompleteOperations(t *testing.T) {
+	procRoot := t.TempDir()
+	mountedTarget := t.TempDir()
+	mountinfo := "40 22 8:48 / " + mountedTarget + " rw,relatime - ext4 /dev/sdd rw\n"
+	require.NoError(t, os.MkdirAll(filepath.Join(procRoot, "self"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(procRoot, "self", "mountinfo"), []byte(mountinfo), 0o600))
+
+	// After a restart the volume formatted on sdh is sdc, the link of the
+	// volume formatted on sdf is stale and sdg holds the ext4 filesystem
+	// of another volume.
+	tmp := t.TempDir()
+	sysRoot := filepath.Join(tmp, "sys")
+	devRoot := filepath.Join(tmp, "dev")
+	bin := filepath.Join(tmp, "bin")
+	require.NoError(t, os.MkdirAll(filepath.Join(devRoot, "disk", "by-id"), 0o750))
+	require.NoError(t, os.MkdirAll(bin, 0o750))
+	wwids := map[string]string{
+		"sdc": "naa.60000970000197900046533030300501",
+		"sdd": "naa.60000970000197900046533030300503",
+		"sde": "naa.60000970000197900046533030300502",
+		"sdf": "naa.60000970000197900046533030300509",
+		"sdg": "naa.60000970000197900046533030300504",
+	}
+	wwn := func(wwid string) string {
+		w, ok := wwnFromWWID(wwid)
+		require.True(t, ok)
+		return string(w)
+	}
+	staleWWN := wwn("naa.60000970000197900046533030300505")
+	links := map[string]string{staleWWN: "sdf"}
+	for i, name := range []string{"sdc", "sdd", "sde", "sdf", "sdg"} {
+		writeSysfsAttrs(t, filepath.Join(sysRoot, "class", "block", name), map[string]string{"dev": "8:" + strconv.Itoa(32+16*i)})
+		writeSysfsAttrs(t, filepath.Join(sysRoot, "class", "block", name, "device"), map[string]string{"wwid": wwids[name]})
+		require.NoError(t, os.WriteFile(filepath.Join(devRoot, name), nil, 0o600))
+		if name != "sdf" {
+			links[wwn(wwids[name])] = name
+		}
+	}
+	for w, name := range links {
+		require.NoError(t, os.Symlink("../../"+name, filepath.Join(devRoot, "disk", "by-id", "wwn-0x"+w)))
+	}
+	blkid := "#!/bin/sh\nfor a; do dev=$a; done\ncase $dev in\n/dev/sdc) echo xfs ;;\n/dev/sdg) echo ext4 ;;\n*) exit 2 ;;\nesac\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "blkid"), []byte(blkid), 0o700)) // #nosec G306
+
+	dir := t.TempDir()
+	fs := NewFS(FSOptions{
+		JournalDir: dir, JournalRollback: true, JournalMaxAge: time.Hour, ProcRoot: procRoot, SysRoot: sysRoot, DevRoot: devRoot,
+		ExtraEnv: []string{"PATH=" + bin}, DryRun: true,
+	})
+	ctx := context.Background()
+
+	target := t.TempDir()
+	start := time.Now()
+	entries := []JournalEntry{
+		{ID: "a", Op: JournalOpFormatAndMount, Step: JournalStepFormatting, Device: "/dev/sdh", Target: target, FsType: "xfs",
+			WWN: wwn(wwids["sdc"])},
+		{ID: "b", Op: JournalOpFormatAndMount, Step: JournalStepFormatted, Device: "/dev/sde", Target: target, FsType: "xfs",
+			WWN: wwn(wwids["sde"])},
+		{ID: "c", Op: JournalOpFormatAndMount, Step: JournalStepFormatted, Device: "/dev/sdd", Target: mountedTarget, FsType: "ext4",
+			WWN: wwn(wwids["sdd"])},
+		{ID: "d", Op: "resize", Step: JournalStepFormatted},
+		{ID: "e", Op: JournalOpFormatAndMount, Step: JournalStepFormatting, Device: "/dev/sdf", Target: target, FsType: "xfs",
+			WWN: staleWWN},
+		{ID: "f", Op: JournalOpFormatAndMount, Step: JournalStepFormatting, Device: "/dev/sdg", Target: target, FsType: "xfs",
+			WWN: wwn(wwids["sdg"])},
+		{ID: "g", Op: JournalOpFormatAndMount, Step: JournalStepFormatted, Device: "/dev/sde", Target: target, FsType: "xfs"},
+		{ID: "h", Op: JournalOpFormatAndMount, Step: JournalStepFormatted, Device: "/dev/sdi", Target: target, FsType: "xfs",
+			WWN: wwn("naa.60000970000197900046533030300506")},
+	}
+	for i := range entries {
+		entries[i].Started = start.Add(time.Duration(i) * time.Second)
+		require.NoError(t, fs.journalWrite(&entries[i]))
+	}
+	// The volume of h is gone since long before the restart.
+	entries[7].Started = start.Add(-2 * time.Hour)
+	require.NoError(t, fs.journalWrite(&entries[7]))
+
+	replays, err := fs.ReplayIncompleteOperations(ctx)
+	require.NoError(t, err)
+	require.Len(t, replays, 8)
+	assert.Equal(t, "h", replays[0].Entry.ID)
+	assert.Equal(t, JournalDiscarded, replays[0].Action)
+	assert.ErrorIs(t, replays[0].Err, ErrJournalDeviceMismatch)
+	replays = replays[1:]
+	assert.Equal(t, JournalRolledBack, replays[0].Action)
+	assert.Equal(t, JournalCompleted, replays[1].Action)
+	assert.Equal(t, JournalCompleted, replays[2].Action)
+	assert.Equal(t, JournalFailed, replays[3].Action)
+	assert.Error(t, replays[3].Err)
+	assert.ErrorIs(t, replays[4].Err, ErrJournalDeviceMismatch)
+	assert.ErrorContains(t, replays[5].Err, "holds a ext4 signature")
+	assert.ErrorIs(t, replays[6].Err, ErrJournalDeviceMismatch)
+
+	var commands []string
+	for _, a := range fs.GetDryRunActions() {
+		commands = append(commands, a.Command+" "+strings.Join(a.Args, " "))
+	}
+	assert.Equal(t, []string{"wipefs -a -t xfs /dev/sdc", "mount -t xfs /dev/sde " + target}, commands)
+
+	left, err := fs.journalEntries()
+	require.NoError(t, err)
+	var ids []string
+	for _, e := range left {
+		ids = append(ids, e.ID)
+	}
+	assert.Equal(t, []string{"d", "e", "f", "g"}, ids)
+
+	// Interrupted formats are only wiped when rollback is enabled.
+	fs.JournalRollback = false
+	require.NoError(t, fs.journalWrite(&entries[0]))
+	replays, err = fs.ReplayIncompleteOperations(ctx)
+	require.NoError(t, err)
+	require.Len(t, replays, 5)
+	assert.ErrorIs(t, replays[0].Err, ErrJournalRollbackDisabled)
+}
@@ -0,0 +1,127 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// keyedMutex serializes operations that share a key, e.g. a target path
+// or a device, while letting operations on different keys run in
+// parallel. The zero value is ready to use.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	// ch holds a token while the lock is held.
+	ch chan struct{}
+	// refs is the number of holders and waiters.
+	refs int
+}
+
+// lock acquires the locks of all the given keys, waiting until they are
+// available or ctx is done. Empty and duplicate keys are ignored. The
+// returned function releases the locks.
+func (k *keyedMutex) lock(ctx context.Context, keys ...string) (func(), error) {
+	keys = RemoveDuplicates(append([]string(nil), keys...))
+	// Always acquire in the same order to avoid deadlocks between
+	// operations locking more than one key.
+	sort.Strings(keys)
+
+	acquired := make([]string, 0, len(keys))
+	unlock := func() {
+		for i := len(acquired) - 1; i >= 0; i-- {
+			k.release(acquired[i])
+		}
+	}
+	for _, key := range keys {
+		l := k.ref(key)
+		select {
+		case l.ch <- struct{}{}:
+			acquired = append(acquired, key)
+		case <-ctx.Done():
+			k.unref(key)
+			unlock()
+			return nil, ctx.Err()
+		}
+	}
+	return unlock, nil
+}
+
+// ref returns the lock of key, creating it if needed, and records a
+// new reference to it.
+func (k *keyedMutex) ref(key string) *keyedLock {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.locks == nil {
+		k.locks = make(map[string]*keyedLock)
+	}
+	l, ok := k.locks[key]
+	if !ok {
+		l = &keyedLock{ch: make(chan struct{}, 1)}
+		k.locks[key] = l
+	}
+	l.refs++
+	return l
+}
+
+// unref drops a reference to the lock of key and forgets the lock once
+// nobody holds or waits for it.
+func (k *keyedMutex) unref(key string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	l := k.locks[key]
+	l.refs--
+	if l.refs == 0 {
+		delete(k.locks, key)
+	}
+}
+
+// release unlocks key.
+func (k *keyedMutex) release(key string) {
+	k.mu.Lock()
+	l := k.locks[key]
+	k.mu.Unlock()
+	<-l.ch
+	k.unref(key)
+}
+
+// lockPaths serializes the operation with all other operations on the
+// given paths unless locking is disabled.
+func (fs *FS) lockPaths(ctx context.Context, paths ...string) (func(), error) {
+	if fs.DisableOperationLocks {
+		return func() {}, nil
+	}
+	keys := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if p != "" {
+			keys = append(keys, filepath.Clean(p))
+		}
+	}
+	return fs.locks.lock(ctx, keys...)
+}
+
+// deviceKey returns source if it names a device and should be locked,
+// and an empty string for other mount sources, e.g. NFS exports.
+func deviceKey(source string) string {
+	if strings.HasPrefix(source, "/dev/") {
+		return source
+	}
+	return ""
+}
@@ -0,0 +1,132 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyedMutexSerializesSameKey(t *testing.T) {
+	var (
+		k       keyedMutex
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		active  int
+		overlap bool
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := k.lock(context.Background(), "/mnt/a", "/dev/sdb")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			active++
+			overlap = overlap || active > 1
+			mu.Unlock()
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			active--
+			mu.Unlock()
+			unlock()
+		}()
+	}
+	wg.Wait()
+	assert.False(t, overlap)
+	assert.Empty(t, k.locks)
+}
+
+func TestKeyedMutexDifferentKeys(t *testing.T) {
+	var k keyedMutex
+	unlockA, err := k.lock(context.Background(), "/mnt/a")
+	require.NoError(t, err)
+	defer unlockA()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	unlockB, err := k.lock(ctx, "/mnt/b")
+	require.NoError(t, err)
+	unlockB()
+}
+
+func TestKeyedMutexContextDone(t *testing.T) {
+	var k keyedMutex
+	unlock, err := k.lock(context.Background(), "/mnt/a")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = k.lock(ctx, "/mnt/b", "/mnt/a")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The partially acquired lock must have been released.
+	unlock()
+	assert.Empty(t, k.locks)
+}
+
+func TestLockPathsDisabled(t *testing.T) {
+	fs := NewFS(FSOptions{DisableOperationLocks: true})
+	unlock, err := fs.lockPaths(context.Background(), "/mnt/a")
+	require.NoError(t, err)
+	defer unlock()
+	unlock2, err := fs.lockPaths(context.Background(), "/mnt/a")
+	require.NoError(t, err)
+	unlock2()
+}
+
+func TestStageBlockVolumeLocksTarget(t *testing.T) {
+	ResetMockFS()
+	defer ResetMockFS()
+	var (
+		k       keyedMutex
+		wg      sync.WaitGroup
+		results [5]*StageResult
+		errs    [5]error
+	)
+	// Each mock operation takes long enough for the stages to overlap.
+	GOFSMockLatency = MockLatency{Min: 5 * time.Millisecond, Max: 5 * time.Millisecond}
+	m := &mockfs{ScanEntry: defaultEntryScanFunc}
+	req := StageRequest{DevicePath: "/dev/sdb", Target: "/var/lib/kubelet/staging/vol1", FsType: "ext4"}
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = stageBlockVolume(context.Background(), m, req, func(ctx context.Context, paths ...string) (func(), error) {
+				return k.lock(ctx, paths...)
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	// The target is mounted once and the other stages find the mount.
+	staged := 0
+	for i := range results {
+		require.NoError(t, errs[i])
+		if !results[i].AlreadyStaged {
+			staged++
+		}
+	}
+	assert.Equal(t, 1, staged)
+	mounts, err := m.getMounts(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, mounts, 1)
+}
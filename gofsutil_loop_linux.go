@@ -0,0 +1,134 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const losetupCmd = "losetup"
+
+// getLoopDeviceForFile returns the loop device backed by file, or an empty
+// string if there is none.
+func (fs *FS) getLoopDeviceForFile(_ context.Context, file string) (string, error) {
+	path, err := fs.loopFilePath(file)
+	if err != nil {
+		return "", err
+	}
+	entries, err := os.ReadDir(fs.sysBlockDir())
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, "loop") {
+			continue
+		}
+		backingFile := readSysfsAttr(filepath.Join(fs.sysBlockDir(), name, "loop", "backing_file"))
+		if backingFile == path {
+			return fs.devPath("/dev/" + name), nil
+		}
+	}
+	return "", nil
+}
+
+// loopFilePath returns the absolute path of file as seen by losetup,
+// which is also the backing file the kernel reports for its loop device.
+func (fs *FS) loopFilePath(file string) (string, error) {
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+	if err := validatePath(path); err != nil {
+		return "", err
+	}
+	return fs.hostPath(path)
+}
+
+// attachLoopDevice attaches file to a free loop device with losetup,
+// unless it is already attached, and returns the device and whether it
+// was attached.
+func (fs *FS) attachLoopDevice(ctx context.Context, file string, readOnly bool) (string, bool, error) {
+	device, err := fs.getLoopDeviceForFile(ctx, file)
+	if err != nil {
+		return "", false, err
+	}
+	if device != "" {
+		log.WithFields(log.Fields{"file": file, "device": device}).Info("file is already attached to a loop device")
+		return device, false, nil
+	}
+	path, err := fs.loopFilePath(file)
+	if err != nil {
+		return "", false, err
+	}
+	args := []string{"--find", "--show"}
+	if readOnly {
+		args = append(args, "--read-only")
+	}
+	args = append(args, path)
+	log.WithFields(log.Fields{"cmd": losetupCmd, "args": args}).Info("attaching loop device")
+	out, err := fs.commandContext(ctx, losetupCmd, args...).CombinedOutput()
+	if err != nil {
+		return "", false, fmt.Errorf("losetup failed: %v\nfile: %s\noutput: %s", err, path, out)
+	}
+	device = strings.TrimSpace(string(out))
+	if !strings.HasPrefix(device, "/dev/loop") {
+		return "", false, fmt.Errorf("unexpected losetup output: %s", out)
+	}
+	return fs.devPath("/dev/" + filepath.Base(device)), true, nil
+}
+
+// detachLoopDevice detaches the loop device with losetup -d.
+func (fs *FS) detachLoopDevice(ctx context.Context, device string) error {
+	path := filepath.Clean(device)
+	if err := validatePath(path); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(filepath.Base(path), "loop") {
+		return fmt.Errorf("%s is not a loop device", device)
+	}
+	log.WithField("device", path).Info("detaching loop device")
+	out, err := fs.commandContext(ctx, losetupCmd, "-d", path).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("losetup -d failed: %v\ndevice: %s\noutput: %s", err, path, out)
+	}
+	return nil
+}
+
+// formatAndMountFile attaches file to a loop device and formats and mounts
+// the device on target. A loop device attached for the mount is detached
+// again if the mount fails.
+func (fs *FS) formatAndMountFile(ctx context.Context, file, target, fsType string, opts ...string) (string, error) {
+	device, attached, err := fs.attachLoopDevice(ctx, file, false)
+	if err != nil {
+		return "", err
+	}
+	if err := fs.formatAndMount(ctx, device, target, fsType, opts...); err != nil {
+		if attached {
+			if detachErr := fs.detachLoopDevice(ctx, device); detachErr != nil {
+				log.WithField("device", device).WithError(detachErr).Error("failed to detach loop device")
+			}
+		}
+		return "", err
+	}
+	return device, nil
+}
@@ -0,0 +1,65 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoopDevice(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	bin := filepath.Join(tmp, "bin")
+	log := filepath.Join(tmp, "log")
+	require.NoError(t, os.Mkdir(bin, 0o750))
+	script := "#!/bin/sh\necho \"$*\" >> " + log + "\n[ \"$1\" = -d ] || echo /dev/loop3\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, losetupCmd), []byte(script), 0o700)) // #nosec G306
+	sysRoot := filepath.Join(tmp, "sys")
+	writeSysfsAttrs(t, filepath.Join(sysRoot, "block", "loop0", "loop"), map[string]string{
+		"backing_file": "/var/lib/images/a.img",
+	})
+	writeSysfsAttrs(t, filepath.Join(sysRoot, "block", "sda"), map[string]string{"size": "8"})
+	fs := NewFS(FSOptions{SysRoot: sysRoot, ExtraEnv: []string{"PATH=" + bin}})
+
+	device, err := fs.GetLoopDeviceForFile(ctx, "/var/lib/images/a.img")
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/loop0", device)
+	device, err = fs.GetLoopDeviceForFile(ctx, "/var/lib/images/b.img")
+	require.NoError(t, err)
+	assert.Empty(t, device)
+
+	device, err = fs.AttachLoopDevice(ctx, "/var/lib/images/a.img", false)
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/loop0", device)
+	_, err = os.Stat(log)
+	assert.True(t, os.IsNotExist(err))
+
+	device, err = fs.AttachLoopDevice(ctx, "/var/lib/images/b.img", true)
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/loop3", device)
+	require.NoError(t, fs.DetachLoopDevice(ctx, device))
+	assert.Error(t, fs.DetachLoopDevice(ctx, "/dev/sda"))
+
+	out, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, "--find --show --read-only /var/lib/images/b.img\n-d /dev/loop3\n", string(out))
+}
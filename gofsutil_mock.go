@@ -20,6 +20,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -44,6 +46,103 @@ var (
 	GONVMEDeviceToControllerMap map[string]string
 	// GONVMEValidDevices mocks existing devices
 	GONVMEValidDevices map[string]bool
+	// GOFSMockNVMeTargets maps the connected NVMe subsystem NQNs to their target address.
+	GOFSMockNVMeTargets map[string]string
+	// GOFSMockDMTables maps device mapper names to their "dmsetup table" output.
+	GOFSMockDMTables map[string]string
+	// GOFSMockDMSuspended contains the suspended device mapper devices.
+	GOFSMockDMSuspended map[string]bool
+	// GOFSMockCorruptedMounts contains the targets whose mounts are stale.
+	GOFSMockCorruptedMounts map[string]bool
+	// GOFSMockFilesystemSize is the filesystem size returned by ResizeFSWithOptions.
+	GOFSMockFilesystemSize int64
+	// GOFSMockDiskUsage is the usage returned by DiskUsage.
+	GOFSMockDiskUsage DiskUsageInfo
+	// GOFSMockProjectQuotas maps project ids to their quotas.
+	GOFSMockProjectQuotas map[uint32]*ProjectQuota
+	// GOFSMockPartitions maps disks to their partition tables.
+	GOFSMockPartitions map[string]*PartitionTable
+	// GOFSMockNPIVPorts are the NPIV ports returned by GetNPIVPorts.
+	GOFSMockNPIVPorts []NPIVPort
+	// GOFSMockFCHosts are the FC hosts returned by GetFCHostInfo.
+	GOFSMockFCHosts []FCHostInfo
+	// GOFSMockISCSISessions are the sessions returned by GetISCSISessionState.
+	GOFSMockISCSISessions []ISCSISession
+	// GOFSMockHostNQN is the host NQN returned by GetHostNQN.
+	GOFSMockHostNQN string
+	// GOFSMockHostID is the host ID returned by GetNQNHostID.
+	GOFSMockHostID string
+	// GOFSMockHostIQN is the initiator name returned by GetHostIQN.
+	GOFSMockHostIQN string
+
+	// GOFSMockMpathDevices are the multipath devices returned by
+	// GetMpathDeviceForWWN.
+	GOFSMockMpathDevices []MpathDevice
+
+	// GOFSMockReadOnlyDevices are the devices made read-only with
+	// BlockDevSetRO.
+	GOFSMockReadOnlyDevices map[string]bool
+
+	// GOFSMockISCSITargetDevices are the devices returned by
+	// GetISCSITargetsFromByPath.
+	GOFSMockISCSITargetDevices []ISCSITargetDevice
+
+	// GOFSMockFCTargetLUNToDevice maps keys of the form <wwpn>-lun-<id>,
+	// with the WWPN as 16 lower case hex digits, to device paths.
+	GOFSMockFCTargetLUNToDevice map[string]string
+
+	// GOFSMockImmutableTargets are the targets made immutable with
+	// SetTargetImmutable.
+	GOFSMockImmutableTargets map[string]bool
+
+	// GOFSMockLoopDevices maps the files attached to loop devices to the
+	// devices.
+	GOFSMockLoopDevices map[string]string
+	// GOFSMockMultipathKinds maps WWNs to the multipath kind returned by
+	// GetMultipathKind, MultipathNone for WWNs that are not in the map.
+	GOFSMockMultipathKinds map[string]MultipathKind
+	// GOFSMockDeviceTopologies maps devices to the topology returned by
+	// GetDeviceTopology.
+	GOFSMockDeviceTopologies map[string]*DeviceTopology
+	// GOFSMockNVMePathStates maps subsystem NQNs to the paths returned by
+	// GetNVMePathStates.
+	GOFSMockNVMePathStates map[string][]NVMePathState
+	// GOFSMockSCSIHosts are the SCSI hosts returned by GetHostSCSIHosts.
+	GOFSMockSCSIHosts []SCSIHost
+	// GOFSMockDMDevices are the device mapper devices returned by
+	// DMNameToDevPath and DevPathToDMName.
+	GOFSMockDMDevices []DMDevice
+	// GOFSMockNFSCapabilities are the capabilities returned by
+	// GetNFSCapabilities, all options are supported if nil.
+	GOFSMockNFSCapabilities *NFSCapabilities
+	// GOFSMockISCSIPortalHosts are the hosts returned by
+	// GetISCSIHostsForTargetPortal for their address.
+	GOFSMockISCSIPortalHosts []ISCSIPortalHost
+	// GOFSMockJournalReplays are the replays returned by
+	// ReplayIncompleteOperations.
+	GOFSMockJournalReplays []JournalReplay
+	// GOFSMockBusyDevices are the errors returned by
+	// RemoveBlockDeviceWithOptions for busy devices, by device path.
+	GOFSMockBusyDevices map[string]*DeviceBusyError
+	// GOFSMockNVMeNativeMultipath is the native NVMe multipath setting
+	// returned by GetNativeMultipathSetting.
+	GOFSMockNVMeNativeMultipath bool
+	// GOFSMockSysfsAttrs are the sysfs attributes read and written by
+	// SysfsReadAttr and SysfsWriteAttr, by path relative to /sys.
+	GOFSMockSysfsAttrs map[string]string
+	// GOFSMockFSGeometry are the geometries returned by GetFSGeometry, by
+	// mount point or device.
+	GOFSMockFSGeometry map[string]*FSGeometry
+	// GOFSMockMultipathPaths are the multipath paths changed by FailPath
+	// and ReinstatePath, by device name.
+	GOFSMockMultipathPaths map[string]*MultipathPathState
+	// GOFSMockUnreadableDevices are the devices VerifyDeviceReadable
+	// fails to read.
+	GOFSMockUnreadableDevices map[string]bool
+	// GOFSMockMountCapacities are the capacities of the filesystems
+	// mounted at the mount points checked by VerifyMountedCapacity, which
+	// otherwise uses the capacity reported by FsInfo.
+	GOFSMockMountCapacities map[string]int64
 
 	// GOFSMock allows you to induce errors in the various routine.
 	GOFSMock struct {
@@ -68,18 +167,85 @@ var (
 		InduceResizeMultipathError        bool
 		InduceFSTypeError                 bool
 		InduceResizeFSError               bool
+		InduceNoResizeNeeded              bool
+		InduceMPathNotReady               bool
+		InduceMultipathdStatusError       bool
+		InduceMultipathdUnhealthy         bool
+		InduceRegenerateXFSUUIDError      bool
+		InduceRegenerateExtUUIDError      bool
+		InduceMakeBlockFileError          bool
+		InduceNFSUnreachable              bool
+		InduceDiskUsageError              bool
+		InduceProjectQuotaError           bool
+		InduceDeviceSettleError           bool
 		InduceGetMpathNameFromDeviceError bool
 		InduceFilesystemInfoError         bool
 		InduceGetNVMeControllerError      bool
+		InduceNVMeConnectError            bool
+		InduceNVMeDisconnectError         bool
+		InduceDMSuspendError              bool
+		InduceDMResumeError               bool
+		InduceGetDMTableError             bool
+		InduceIsMountPointError           bool
+		InduceCorruptedMount              bool
+		InduceIsCorruptedMountError       bool
+		InducePartitionError              bool
+		InduceNPIVPortError               bool
+		InduceISCSISessionError           bool
+		InduceHostIdentityError           bool
+		InduceDeviceInUse                 bool
+		InduceGetMpathDeviceError         bool
+		InduceBlockDevSetROError          bool
+		InduceBlockDevSetRWError          bool
+		InduceBlockDevGetROError          bool
+		InduceCleanupDeviceError          bool
+		InduceDAXError                    bool
+		InduceISCSITargetsError           bool
+		InduceFCTargetLUNToDeviceError    bool
+		InduceSetTargetPermissionsError   bool
+		InduceSetTargetImmutableError     bool
+		InduceLoopDeviceError             bool
+		InduceGetMultipathKindError       bool
+		InduceFsHealthCheckAbnormal       bool
+		InduceClusterStackError           bool
+		InduceDeviceTopologyError         bool
+		InduceGetNVMePathStatesError      bool
+		InduceGetSCSIHostsError           bool
+		InduceDMNameToDevPathError        bool
+		InduceDevPathToDMNameError        bool
+		InduceDevicePathsForMountError    bool
+		InduceGetMountsByDevIDError       bool
+		InduceGetNFSCapabilitiesError     bool
+		InduceISCSIPortalHostsError       bool
+		InduceReplayOperationsError       bool
+		InduceNativeMultipathError        bool
+		InduceSubscribeMountChangesError  bool
+		InduceSysfsReadAttrError          bool
+		InduceSysfsWriteAttrError         bool
+		InduceDeviceForPublishPathError   bool
+		InduceGetFSGeometryError          bool
+		InduceShrinkFSError               bool
+		InduceMultipathReconfigureError   bool
+		InduceReinstatePathError          bool
+		InduceFailPathError               bool
+		InduceVerifyDeviceReadableError   bool
+		InduceVerifyMountedCapacityError  bool
 	}
 )
 
 type mockfs struct {
 	// ScanEntry is the function used to process mount table entries.
 	ScanEntry EntryScanFunc
+	// fsTypes are the filesystem types reported by SupportedFsTypes.
+	fsTypes fsTypeSet
+	// mountDefaults are the default mount options per filesystem type.
+	mountDefaults mountOptionDefaults
 }
 
-func (fs *mockfs) getDiskFormat(_ context.Context, disk string) (string, error) {
+func (fs *mockfs) getDiskFormat(ctx context.Context, disk string) (string, error) {
+	if err := mockFault(ctx, "getDiskFormat", GOFSMockSchedules.GetDiskFormat, &GOFSMockCalls.GetDiskFormat); err != nil {
+		return "", err
+	}
 	if GOFSMock.InduceGetDiskFormatError {
 		GOFSMock.InduceMountError = false
 		return "", errors.New("getDiskFormat induced error")
@@ -88,7 +254,8 @@ func (fs *mockfs) getDiskFormat(_ context.Context, disk string) (string, error)
 		GOFSMock.InduceMountError = false
 		return GOFSMock.InduceGetDiskFormatType, nil
 	}
-	for _, info := range GOFSMockMounts {
+	disk = mockDevice(disk)
+	for _, info := range mockMountsSnapshot() {
 		if info.Device == disk {
 			return info.Type, nil
 		}
@@ -96,43 +263,65 @@ func (fs *mockfs) getDiskFormat(_ context.Context, disk string) (string, error)
 	return "", nil
 }
 
-func (fs *mockfs) formatAndMount(_ context.Context, source, target, fsType string, opts ...string) error {
+func (fs *mockfs) formatAndMount(ctx context.Context, source, target, fsType string, opts ...string) error {
+	if err := mockFault(ctx, "formatAndMount", GOFSMockSchedules.FormatAndMount, &GOFSMockCalls.FormatAndMount); err != nil {
+		return err
+	}
 	if GOFSMock.InduceBindMountError {
 		GOFSMock.InduceMountError = false
 		return errors.New("bindMount induced error")
 	}
+	if err := checkFormatOptions(ctx, fsType); err != nil {
+		return err
+	}
+	if isForeignSignature(GOFSMock.InduceGetDiskFormatType) {
+		return &ForeignSignatureError{Device: source, Signature: GOFSMock.InduceGetDiskFormatType}
+	}
+	opts = fs.mountDefaults.merge(fsType, opts)
 	fmt.Printf(">>>formatAndMount source %s target %s fstype %s opts %v\n", source, target, fsType, opts)
-	info := Info{Device: getDevice(source), Path: target, Type: fsType, Opts: make([]string, 0)}
+	info := Info{Device: mockDevice(source), Path: target, Type: fsType, Opts: make([]string, 0)}
 	for _, str := range opts {
 		info.Opts = append(info.Opts, str)
 	}
-	GOFSMockMounts = append(GOFSMockMounts, info)
+	mockAddMount(info)
 	return nil
 }
 
-func (fs *mockfs) format(_ context.Context, source, target, fsType string, opts ...string) error {
+func (fs *mockfs) format(ctx context.Context, source, target, fsType string, opts ...string) error {
+	if err := mockFault(ctx, "format", GOFSMockSchedules.Format, &GOFSMockCalls.Format); err != nil {
+		return err
+	}
 	if GOFSMock.InduceFormatError {
 		return errors.New("format induced error")
 	}
+	if err := checkFormatOptions(ctx, fsType); err != nil {
+		return err
+	}
 	fmt.Printf(">>>format source %s target %s fstype %s opts %v\n", source, target, fsType, opts)
-	for _, info := range GOFSMockMounts {
-		if info.Device == source {
-			info.Type = fsType
+	source = mockDevice(source)
+	mockMountsMu.Lock()
+	defer mockMountsMu.Unlock()
+	for i := range GOFSMockMounts {
+		if GOFSMockMounts[i].Device == source {
+			GOFSMockMounts[i].Type = fsType
 		}
 	}
 	return nil
 }
 
-func (fs *mockfs) bindMount(_ context.Context, source, target string, opts ...string) error {
+func (fs *mockfs) bindMount(ctx context.Context, source, target string, opts ...string) error {
+	if err := mockFault(ctx, "bindMount", GOFSMockSchedules.BindMount, &GOFSMockCalls.BindMount); err != nil {
+		return err
+	}
 	if GOFSMock.InduceBindMountError {
 		return errors.New("bindMount induced error")
 	}
 	fmt.Printf(">>>bindMount source %s target %s opts %v\n", source, target, opts)
-	info := Info{Device: getDevice(source), Path: target, Opts: make([]string, 0)}
+	info := Info{Device: mockDevice(source), Path: target, Opts: make([]string, 0)}
 	for _, str := range opts {
 		info.Opts = append(info.Opts, str)
 	}
-	GOFSMockMounts = append(GOFSMockMounts, info)
+	mockAddMount(info)
 	return nil
 }
 
@@ -140,24 +329,55 @@ func (fs *mockfs) DeviceRescan(ctx context.Context, devicePath string) error {
 	return fs.deviceRescan(ctx, devicePath)
 }
 
-func (fs *mockfs) deviceRescan(_ context.Context, _ string) error {
+func (fs *mockfs) deviceRescan(ctx context.Context, _ string) error {
+	if err := mockFault(ctx, "deviceRescan", GOFSMockSchedules.DeviceRescan, &GOFSMockCalls.DeviceRescan); err != nil {
+		return err
+	}
 	if GOFSMock.InduceDeviceRescanError {
 		return errors.New("DeviceRescan induced error: Failed to rescan device")
 	}
 	return nil
 }
 
+func (fs *mockfs) WaitForDeviceToSettle(ctx context.Context, device string) error {
+	return fs.waitForDeviceToSettle(ctx, device)
+}
+
+func (fs *mockfs) waitForDeviceToSettle(_ context.Context, _ string) error {
+	if GOFSMock.InduceDeviceSettleError {
+		return errors.New("waitForDeviceToSettle induced error")
+	}
+	return nil
+}
+
 func (fs *mockfs) ResizeFS(ctx context.Context, volumePath, devicePath, ppathDevice, mpathDevice, fsType string) error {
 	return fs.resizeFS(ctx, volumePath, devicePath, ppathDevice, mpathDevice, fsType)
 }
 
-func (fs *mockfs) resizeFS(_ context.Context, _, _, _, _, _ string) error {
+func (fs *mockfs) resizeFS(ctx context.Context, _, _, _, _, _ string) error {
+	if err := mockFault(ctx, "resizeFS", GOFSMockSchedules.ResizeFS, &GOFSMockCalls.ResizeFS); err != nil {
+		return err
+	}
 	if GOFSMock.InduceResizeFSError {
 		return errors.New("resizeFS induced error:	Failed to resize device")
 	}
 	return nil
 }
 
+func (fs *mockfs) ResizeFSWithOptions(ctx context.Context, volumePath, devicePath, ppathDevice, mpathDevice, fsType string, opts ResizeFSOptions) (int64, error) {
+	return fs.resizeFSWithOptions(ctx, volumePath, devicePath, ppathDevice, mpathDevice, fsType, opts)
+}
+
+func (fs *mockfs) resizeFSWithOptions(_ context.Context, _, _, _, _, _ string, _ ResizeFSOptions) (int64, error) {
+	if GOFSMock.InduceResizeFSError {
+		return 0, errors.New("resizeFS induced error:	Failed to resize device")
+	}
+	if GOFSMock.InduceNoResizeNeeded {
+		return GOFSMockFilesystemSize, ErrNoResizeNeeded
+	}
+	return GOFSMockFilesystemSize, nil
+}
+
 func (fs *mockfs) FindFSType(ctx context.Context, mountpoint string) (fsType string, err error) {
 	return fs.findFSType(ctx, mountpoint)
 }
@@ -173,15 +393,26 @@ func (fs *mockfs) GetMountInfoFromDevice(ctx context.Context, devID string) (*De
 	return fs.getMountInfoFromDevice(ctx, devID)
 }
 
-func (fs *mockfs) getMountInfoFromDevice(_ context.Context, _ string) (*DeviceMountInfo, error) {
+func (fs *mockfs) getMountInfoFromDevice(ctx context.Context, devID string) (*DeviceMountInfo, error) {
+	if err := mockFault(ctx, "getMountInfoFromDevice", GOFSMockSchedules.GetMountInfoFromDevice, &GOFSMockCalls.GetMountInfoFromDevice); err != nil {
+		return nil, err
+	}
 	if GOFSMock.InduceGetMountInfoFromDeviceError {
 		return GOFSMockMountInfo, errors.New("getMounts induced error: Failed to find mount information")
 	}
+	if info := mockDeviceMountInfo(mockDevice(devID)); info != nil {
+		GOFSMockMountInfo = info
+		return GOFSMockMountInfo, nil
+	}
 	mntPoint := "/noderoot/var/lib/kubelet/pods/abc-123/volumes/k8.io/pmax-0123/mount"
 	GOFSMockMountInfo = &DeviceMountInfo{
 		DeviceNames: []string{"sda", "sdb"},
 		MPathName:   "mpathb",
 		MountPoint:  mntPoint,
+		FsType:      "xfs",
+		MountOpts:   []string{"rw", "relatime"},
+		WWN:         "60000970000120001263533030313434",
+		DMUUID:      "mpath-360000970000120001263533030313434",
 	}
 	return GOFSMockMountInfo, nil
 }
@@ -213,18 +444,57 @@ func (fs *mockfs) ResizeMultipath(ctx context.Context, deviceName string) error
 	return fs.resizeMultipath(ctx, deviceName)
 }
 
-func (fs *mockfs) resizeMultipath(_ context.Context, _ string) error {
+func (fs *mockfs) resizeMultipath(ctx context.Context, _ string) error {
+	if err := mockFault(ctx, "resizeMultipath", GOFSMockSchedules.ResizeMultipath, &GOFSMockCalls.ResizeMultipath); err != nil {
+		return err
+	}
 	if GOFSMock.InduceResizeMultipathError {
 		return errors.New("resize multipath induced error: Failed to resize multipath mount device")
 	}
 	return nil
 }
 
-func (fs *mockfs) getMounts(_ context.Context) ([]Info, error) {
+func (fs *mockfs) getMounts(ctx context.Context) ([]Info, error) {
+	if err := mockFault(ctx, "getMounts", GOFSMockSchedules.GetMounts, &GOFSMockCalls.GetMounts); err != nil {
+		return nil, err
+	}
+	if GOFSMock.InduceGetMountsError {
+		return mockMountsSnapshot(), errors.New("getMounts induced error")
+	}
+	return mockMountsSnapshot(), nil
+}
+
+func (fs *mockfs) getMountsByKind(_ context.Context, kinds ...MountKind) ([]Info, error) {
+	if GOFSMock.InduceGetMountsError {
+		return nil, errors.New("getMountsByKind induced error")
+	}
+	snapshot := mockMountsSnapshot()
+	mounts := make([]Info, 0, len(snapshot))
+	for _, m := range snapshot {
+		if m.Kind == "" {
+			m.Kind = entryMountKind(Entry{FSType: m.Type, MountSource: m.Device})
+		}
+		mounts = append(mounts, m)
+	}
+	return filterMountsByKind(mounts, kinds...), nil
+}
+
+func (fs *mockfs) scanProcMounts(ctx context.Context, _ MountEntryField, fn MountEntryFunc) error {
 	if GOFSMock.InduceGetMountsError {
-		return GOFSMockMounts, errors.New("getMounts induced error")
+		return errors.New("scanProcMounts induced error")
+	}
+	for _, m := range mockMountsSnapshot() {
+		more, err := fn(ctx, Entry{
+			MountPoint:  m.Path,
+			MountOpts:   m.Opts,
+			FSType:      m.Type,
+			MountSource: m.Device,
+		})
+		if err != nil || !more {
+			return err
+		}
 	}
-	return GOFSMockMounts, nil
+	return nil
 }
 
 func (fs *mockfs) readProcMounts(_ context.Context,
@@ -234,46 +504,64 @@ func (fs *mockfs) readProcMounts(_ context.Context,
 	return nil, 0, errors.New("not implemented")
 }
 
-func (fs *mockfs) mount(_ context.Context, source, target, fsType string, opts ...string) error {
+func (fs *mockfs) mount(ctx context.Context, source, target, fsType string, opts ...string) error {
+	if err := mockFault(ctx, "mount", GOFSMockSchedules.Mount, &GOFSMockCalls.Mount); err != nil {
+		return err
+	}
 	if GOFSMock.InduceMountError {
 		return errors.New("mount induced error")
 	}
-	fmt.Printf(">>>mount source %s target %s fstype %s opts %v\n", source, target, fsType, opts)
-	info := Info{Device: getDevice(source), Path: target, Opts: make([]string, 0)}
+	if err := fs.checkClusterStack(ctx, fsType, opts...); err != nil {
+		return err
+	}
+	opts = fs.mountDefaults.merge(fsType, opts)
+	fmt.Printf(">>>mount source %s target %s fstype %s opts %s\n", source, target, fsType,
+		redactOptions(fmt.Sprint(opts), sensitiveOptions(ctx)))
+	info := Info{Device: mockDevice(source), Path: target, Opts: make([]string, 0)}
 	for _, str := range opts {
 		info.Opts = append(info.Opts, str)
 	}
 
 	// Try to determine the root source.
-	for _, infox := range GOFSMockMounts {
+	for _, infox := range mockMountsSnapshot() {
 		if infox.Path == source {
 			info.Source = infox.Device
 			info.Device = "devtmpfs"
 		}
 	}
 	fmt.Printf(">>>mount Device %s Path %s Source %s\n", info.Device, info.Path, info.Source)
-	GOFSMockMounts = append(GOFSMockMounts, info)
+	mockAddMount(info)
 	return nil
 }
 
-func (fs *mockfs) unmount(_ context.Context, target string) error {
+func (fs *mockfs) unmount(ctx context.Context, target string) error {
+	if err := mockFault(ctx, "unmount", GOFSMockSchedules.Unmount, &GOFSMockCalls.Unmount); err != nil {
+		return err
+	}
 	if GOFSMock.InduceUnmountError {
 		return errors.New("unmount induced error")
 	}
-	for i, mnt := range GOFSMockMounts {
-		if mnt.Path == target {
-			copy(GOFSMockMounts[i:], GOFSMockMounts[i+1:])
-			GOFSMockMounts = GOFSMockMounts[:len(GOFSMockMounts)-1]
+	mockMountsMu.Lock()
+	mounts := GOFSMockMounts[:0]
+	for _, mnt := range GOFSMockMounts {
+		if mnt.Path != target {
+			mounts = append(mounts, mnt)
 		}
 	}
+	GOFSMockMounts = mounts
+	mockMountsMu.Unlock()
+	delete(GOFSMockCorruptedMounts, target)
 	return nil
 }
 
-func (fs *mockfs) getDevMounts(_ context.Context, _ string) ([]Info, error) {
+func (fs *mockfs) getDevMounts(ctx context.Context, dev string) ([]Info, error) {
+	if err := mockFault(ctx, "getDevMounts", GOFSMockSchedules.GetDevMounts, &GOFSMockCalls.GetDevMounts); err != nil {
+		return nil, err
+	}
 	if GOFSMock.InduceDevMountsError {
-		return GOFSMockMounts, errors.New("dev mount induced error")
+		return mockMountsSnapshot(), errors.New("dev mount induced error")
 	}
-	return GOFSMockMounts, nil
+	return mockDeviceMounts(mockDevice(dev)), nil
 }
 
 func (fs *mockfs) validateDevice(
@@ -299,6 +587,22 @@ func (fs *mockfs) FormatAndMount(
 	return fs.formatAndMount(ctx, source, target, fsType, options...)
 }
 
+// FormatAndMountMPath formats and mounts the given multipath device.
+func (fs *mockfs) FormatAndMountMPath(
+	ctx context.Context,
+	mpathDevice, target, fsType string,
+	opts MPathFormatOptions,
+) error {
+	return fs.formatAndMountMPath(ctx, mpathDevice, target, fsType, opts)
+}
+
+func (fs *mockfs) formatAndMountMPath(ctx context.Context, mpathDevice, target, fsType string, opts MPathFormatOptions) error {
+	if GOFSMock.InduceMPathNotReady {
+		return fmt.Errorf("multipath device %s is not ready: %w", mpathDevicePath(mpathDevice), context.DeadlineExceeded)
+	}
+	return fs.formatAndMount(ctx, mpathDevicePath(mpathDevice), target, fsType, opts.MountOptions...)
+}
+
 // Format uses unix utils to format the given disk.
 func (fs *mockfs) Format(
 	ctx context.Context,
@@ -325,6 +629,15 @@ func (fs *mockfs) Mount(
 	return fs.mount(ctx, source, target, fsType, options...)
 }
 
+// MountWithOptions mounts source onto target like Mount.
+func (fs *mockfs) MountWithOptions(ctx context.Context, source, target, fsType string, opts MountOptions) error {
+	return fs.mountWithOptions(ctx, source, target, fsType, opts)
+}
+
+func (fs *mockfs) mountWithOptions(ctx context.Context, source, target, fsType string, opts MountOptions) error {
+	return fs.mount(ctx, source, target, fsType, opts.Options...)
+}
+
 // BindMount behaves like Mount was called with a "bind" flag set
 // in the options list.
 func (fs *mockfs) BindMount(
@@ -362,6 +675,16 @@ func (fs *mockfs) GetMounts(ctx context.Context) ([]Info, error) {
 	return fs.getMounts(ctx)
 }
 
+// GetMountsByKind returns the mock mounts of the given kinds.
+func (fs *mockfs) GetMountsByKind(ctx context.Context, kinds ...MountKind) ([]Info, error) {
+	return fs.getMountsByKind(ctx, kinds...)
+}
+
+// ScanProcMounts calls fn for each mock mount until fn returns false or an error.
+func (fs *mockfs) ScanProcMounts(ctx context.Context, fields MountEntryField, fn MountEntryFunc) error {
+	return fs.scanProcMounts(ctx, fields, fn)
+}
+
 // GetDevMounts returns a slice of all mounts for the provided device.
 func (fs *mockfs) GetDevMounts(ctx context.Context, dev string) ([]Info, error) {
 	return fs.getDevMounts(ctx, dev)
@@ -379,8 +702,11 @@ func (fs *mockfs) ValidateDevice(
 
 // wwnToDevicePath lookups a mock WWN (no prefix) to a device path.
 func (fs *mockfs) wwnToDevicePath(
-	_ context.Context, wwn string,
+	ctx context.Context, wwn string,
 ) (string, string, error) {
+	if err := mockFault(ctx, "wwnToDevicePath", GOFSMockSchedules.WWNToDevicePath, &GOFSMockCalls.WWNToDevicePath); err != nil {
+		return "", "", err
+	}
 	if GOFSMockWWNToDevice == nil {
 		GOFSMockWWNToDevice = make(map[string]string)
 	}
@@ -416,15 +742,30 @@ func (fs *mockfs) MultipathCommand(ctx context.Context, timeoutSeconds time.Dura
 // If targets are specified, only hosts who are related to the specified
 // iqn target(s) are rescanned.
 // If lun is specified, then the rescan is for that particular volume.
-func (fs *mockfs) rescanSCSIHost(_ context.Context, _ []string, lun string) error {
+func (fs *mockfs) rescanSCSIHost(ctx context.Context, targets []string, lun string) error {
+	_, err := fs.rescanSCSIHostX(ctx, targets, lun)
+	return err
+}
+
+// RescanSCSIHostX performs the same rescan as RescanSCSIHost and returns
+// a report of the hosts rescanned and the new devices discovered.
+func (fs *mockfs) RescanSCSIHostX(ctx context.Context, targets []string, lun string) (*RescanReport, error) {
+	return fs.rescanSCSIHostX(ctx, targets, lun)
+}
+
+func (fs *mockfs) rescanSCSIHostX(ctx context.Context, _ []string, lun string) (*RescanReport, error) {
+	if err := mockFault(ctx, "rescanSCSIHostX", GOFSMockSchedules.RescanSCSIHost, &GOFSMockCalls.RescanSCSIHost); err != nil {
+		return nil, err
+	}
+	report := &RescanReport{ScanStrings: make(map[string][]string)}
 	if GOFSMock.InduceRescanError {
-		return errors.New("induced rescan error")
+		return report, errors.New("induced rescan error")
 	}
 	if GOFSRescanCallback != nil {
 		scanString := fmt.Sprintf("%s", lun)
 		GOFSRescanCallback(scanString)
 	}
-	return nil
+	return report, nil
 }
 
 // RemoveBlockDevice removes a block device by getting the device name
@@ -440,15 +781,15 @@ func (fs *mockfs) RemoveBlockDevice(ctx context.Context, blockDevicePath string)
 // removeBlockDevice removes a block device by getting the device name
 // from the last component of the blockDevicePath and then removing the
 // device by writing '1' to /sys/block{deviceName}/device/delete
-func (fs *mockfs) removeBlockDevice(_ context.Context, blockDevicePath string) error {
+func (fs *mockfs) removeBlockDevice(ctx context.Context, blockDevicePath string) error {
+	if err := mockFault(ctx, "removeBlockDevice", GOFSMockSchedules.RemoveBlockDevice, &GOFSMockCalls.RemoveBlockDevice); err != nil {
+		return err
+	}
 	fmt.Printf(">>>removeBlockDevice %s %#v", blockDevicePath, GOFSMockWWNToDevice)
-	for key, value := range GOFSMockWWNToDevice {
-		if value == blockDevicePath {
-			// Remove from the device table
-			delete(GOFSMockWWNToDevice, key)
-		}
+	if mockDeviceWWN(blockDevicePath) != "" {
 		_ = os.Remove(blockDevicePath)
 	}
+	mockRemoveDevice(blockDevicePath)
 	return nil
 }
 
@@ -474,7 +815,10 @@ func getDevice(path string) string {
 // Execute the multipath command with a timeout and various arguments.
 // Optionally a chroot directory can be specified for changing root directory.
 // This only works in a container or another environment where it can chroot to /noderoot.
-func (fs *mockfs) multipathCommand(_ context.Context, _ time.Duration, _ string, _ ...string) ([]byte, error) {
+func (fs *mockfs) multipathCommand(ctx context.Context, _ time.Duration, _ string, _ ...string) ([]byte, error) {
+	if err := mockFault(ctx, "multipathCommand", GOFSMockSchedules.MultipathCommand, &GOFSMockCalls.MultipathCommand); err != nil {
+		return nil, err
+	}
 	if GOFSMock.InduceMultipathCommandError {
 		return make([]byte, 0), errors.New("multipath command induced error")
 	}
@@ -567,3 +911,1430 @@ func (fs *mockfs) getNVMeController(device string) (string, error) {
 	}
 	return "", fmt.Errorf("controller not found for device %s", device)
 }
+
+// ConnectNVMeTarget connects to an NVMe over fabrics subsystem.
+func (fs *mockfs) ConnectNVMeTarget(ctx context.Context, transport, traddr, trsvcid, nqn string, opts NVMeConnectOptions) error {
+	return fs.connectNVMeTarget(ctx, transport, traddr, trsvcid, nqn, opts)
+}
+
+func (fs *mockfs) connectNVMeTarget(ctx context.Context, transport, traddr, _, nqn string, _ NVMeConnectOptions) error {
+	if err := mockFault(ctx, "connectNVMeTarget", GOFSMockSchedules.ConnectNVMeTarget, &GOFSMockCalls.ConnectNVMeTarget); err != nil {
+		return err
+	}
+	if GOFSMock.InduceNVMeConnectError {
+		return &NVMeCommandError{Op: "connect", NQN: nqn, ExitCode: 1, Err: errors.New("induced error")}
+	}
+	if GOFSMockNVMeTargets == nil {
+		GOFSMockNVMeTargets = make(map[string]string)
+	}
+	GOFSMockNVMeTargets[nqn] = transport + ":" + traddr
+	return nil
+}
+
+// DisconnectNVMeTarget disconnects all controllers of an NVMe subsystem.
+func (fs *mockfs) DisconnectNVMeTarget(ctx context.Context, nqn string) error {
+	return fs.disconnectNVMeTarget(ctx, nqn)
+}
+
+func (fs *mockfs) disconnectNVMeTarget(ctx context.Context, nqn string) error {
+	if err := mockFault(ctx, "disconnectNVMeTarget", GOFSMockSchedules.DisconnectNVMeTarget, &GOFSMockCalls.DisconnectNVMeTarget); err != nil {
+		return err
+	}
+	if GOFSMock.InduceNVMeDisconnectError {
+		return &NVMeCommandError{Op: "disconnect", NQN: nqn, ExitCode: 1, Err: errors.New("induced error")}
+	}
+	delete(GOFSMockNVMeTargets, nqn)
+	return nil
+}
+
+// GetNVMeControllersForSubsystemNQN returns the controllers of an NVMe subsystem.
+func (fs *mockfs) GetNVMeControllersForSubsystemNQN(ctx context.Context, nqn string) ([]NVMeController, error) {
+	return fs.getNVMeControllersForSubsystemNQN(ctx, nqn)
+}
+
+func (fs *mockfs) getNVMeControllersForSubsystemNQN(_ context.Context, nqn string) ([]NVMeController, error) {
+	if GOFSMock.InduceGetNVMeControllerError {
+		return nil, errors.New("getNVMeControllersForSubsystemNQN induced error")
+	}
+	controllers := make([]NVMeController, 0)
+	if target, ok := GOFSMockNVMeTargets[nqn]; ok {
+		transport, traddr, _ := strings.Cut(target, ":")
+		controllers = append(controllers, NVMeController{
+			Name:      "nvme0",
+			Transport: transport,
+			Address:   "traddr=" + traddr,
+			TrAddr:    traddr,
+			State:     "live",
+		})
+	}
+	return controllers, nil
+}
+
+// DMSuspend suspends I/O to a device mapper device.
+func (fs *mockfs) DMSuspend(ctx context.Context, name string) error {
+	return fs.dmSuspend(ctx, name)
+}
+
+func (fs *mockfs) dmSuspend(_ context.Context, name string) error {
+	if GOFSMock.InduceDMSuspendError {
+		return errors.New("dmSuspend induced error")
+	}
+	name, err := dmName(name)
+	if err != nil {
+		return err
+	}
+	if GOFSMockDMSuspended == nil {
+		GOFSMockDMSuspended = make(map[string]bool)
+	}
+	GOFSMockDMSuspended[name] = true
+	return nil
+}
+
+// DMResume resumes I/O to a device mapper device.
+func (fs *mockfs) DMResume(ctx context.Context, name string) error {
+	return fs.dmResume(ctx, name)
+}
+
+func (fs *mockfs) dmResume(_ context.Context, name string) error {
+	if GOFSMock.InduceDMResumeError {
+		return errors.New("dmResume induced error")
+	}
+	name, err := dmName(name)
+	if err != nil {
+		return err
+	}
+	delete(GOFSMockDMSuspended, name)
+	return nil
+}
+
+// GetDMTable returns the table of a device mapper device.
+func (fs *mockfs) GetDMTable(ctx context.Context, name string) (*DMTable, error) {
+	return fs.getDMTable(ctx, name)
+}
+
+func (fs *mockfs) getDMTable(_ context.Context, name string) (*DMTable, error) {
+	if GOFSMock.InduceGetDMTableError {
+		return nil, errors.New("getDMTable induced error")
+	}
+	name, err := dmName(name)
+	if err != nil {
+		return nil, err
+	}
+	table, ok := GOFSMockDMTables[name]
+	if !ok {
+		return nil, fmt.Errorf("device mapper device %s not found", name)
+	}
+	return parseDMTable(name, table)
+}
+
+// IsMountPoint returns true if path is a mount point.
+func (fs *mockfs) IsMountPoint(ctx context.Context, path string) (bool, error) {
+	return fs.isMountPoint(ctx, path)
+}
+
+func (fs *mockfs) isMountPoint(_ context.Context, path string) (bool, error) {
+	if GOFSMock.InduceIsMountPointError {
+		return false, errors.New("isMountPoint induced error")
+	}
+	for _, info := range mockMountsSnapshot() {
+		if info.Path == path {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsLikelyNotMountPoint quickly determines whether path is not a mount point.
+func (fs *mockfs) IsLikelyNotMountPoint(ctx context.Context, path string) (bool, error) {
+	return fs.isLikelyNotMountPoint(ctx, path)
+}
+
+func (fs *mockfs) isLikelyNotMountPoint(ctx context.Context, path string) (bool, error) {
+	mnt, err := fs.isMountPoint(ctx, path)
+	return !mnt, err
+}
+
+// IsCorruptedMount returns true if the mount at target is corrupted or stale.
+func (fs *mockfs) IsCorruptedMount(ctx context.Context, target string) (bool, error) {
+	return fs.isCorruptedMount(ctx, target)
+}
+
+func (fs *mockfs) isCorruptedMount(_ context.Context, target string) (bool, error) {
+	if GOFSMock.InduceIsCorruptedMountError {
+		return false, errors.New("isCorruptedMount induced error")
+	}
+	if GOFSMock.InduceCorruptedMount || GOFSMockCorruptedMounts[target] {
+		return true, nil
+	}
+	return false, nil
+}
+
+func (fs *mockfs) MultipathdStatus(ctx context.Context) (*MultipathdHealth, error) {
+	return fs.multipathdStatus(ctx)
+}
+
+func (fs *mockfs) multipathdStatus(_ context.Context) (*MultipathdHealth, error) {
+	if GOFSMock.InduceMultipathdStatusError {
+		return nil, errors.New("multipathdStatus induced error")
+	}
+	if GOFSMock.InduceMultipathdUnhealthy {
+		return &MultipathdHealth{
+			Installed:  true,
+			PathStates: map[string]int{},
+			Problem:    "multipathd is not running, start it with systemctl start multipathd",
+		}, nil
+	}
+	return &MultipathdHealth{
+		Installed:  true,
+		Running:    true,
+		Responsive: true,
+		PathStates: map[string]int{},
+	}, nil
+}
+
+func (fs *mockfs) RegenerateXFSUUID(ctx context.Context, device string) error {
+	return fs.regenerateXFSUUID(ctx, device)
+}
+
+func (fs *mockfs) regenerateXFSUUID(_ context.Context, _ string) error {
+	if GOFSMock.InduceRegenerateXFSUUIDError {
+		return errors.New("regenerateXFSUUID induced error")
+	}
+	return nil
+}
+
+func (fs *mockfs) RegenerateExtUUID(ctx context.Context, device string) error {
+	return fs.regenerateExtUUID(ctx, device)
+}
+
+func (fs *mockfs) regenerateExtUUID(_ context.Context, _ string) error {
+	if GOFSMock.InduceRegenerateExtUUIDError {
+		return errors.New("regenerateExtUUID induced error")
+	}
+	return nil
+}
+
+func (fs *mockfs) MakeBlockFile(ctx context.Context, target string) error {
+	return fs.makeBlockFile(ctx, target)
+}
+
+func (fs *mockfs) makeBlockFile(_ context.Context, _ string) error {
+	if GOFSMock.InduceMakeBlockFileError {
+		return errors.New("makeBlockFile induced error")
+	}
+	return nil
+}
+
+func (fs *mockfs) BindMountBlockDevice(ctx context.Context, device, target string, options ...string) error {
+	return fs.bindMountBlockDevice(ctx, device, target, options...)
+}
+
+func (fs *mockfs) bindMountBlockDevice(ctx context.Context, device, target string, opts ...string) error {
+	if err := fs.makeBlockFile(ctx, target); err != nil {
+		return err
+	}
+	return fs.bindMount(ctx, device, target, opts...)
+}
+
+func (fs *mockfs) UnmountBlockDevice(ctx context.Context, target string) error {
+	return fs.unmountBlockDevice(ctx, target)
+}
+
+func (fs *mockfs) unmountBlockDevice(ctx context.Context, target string) error {
+	return fs.unmount(ctx, target)
+}
+
+func (fs *mockfs) CheckNFSExport(ctx context.Context, server, exportPath string) (*NFSExportStatus, error) {
+	return fs.checkNFSExport(ctx, server, exportPath)
+}
+
+func (fs *mockfs) checkNFSExport(_ context.Context, server, exportPath string) (*NFSExportStatus, error) {
+	status := &NFSExportStatus{
+		Server:         server,
+		ExportPath:     exportPath,
+		AllowedClients: []string{},
+	}
+	if GOFSMock.InduceNFSUnreachable {
+		status.Problem = "NFS service of " + server + " is not reachable: induced error"
+		return status, nil
+	}
+	status.Reachable = true
+	status.ExportListAvailable = true
+	status.Exported = true
+	status.AllowedClients = []string{"*"}
+	status.ClientAllowed = true
+	return status, nil
+}
+
+// MountMany performs the mount requests one after another.
+func (fs *mockfs) MountMany(ctx context.Context, reqs []MountRequest) []MountResult {
+	return mountMany(ctx, reqs, 1, func(ctx context.Context, req MountRequest) error {
+		if req.Bind {
+			return fs.BindMount(ctx, req.Source, req.Target, req.Options...)
+		}
+		return fs.Mount(ctx, req.Source, req.Target, req.FSType, req.Options...)
+	})
+}
+
+func (fs *mockfs) DiskUsage(ctx context.Context, path string) (*DiskUsageInfo, error) {
+	return fs.diskUsage(ctx, path)
+}
+
+func (fs *mockfs) diskUsage(_ context.Context, _ string) (*DiskUsageInfo, error) {
+	if GOFSMock.InduceDiskUsageError {
+		return nil, errors.New("diskUsage induced error")
+	}
+	usage := GOFSMockDiskUsage
+	return &usage, nil
+}
+
+func (fs *mockfs) SetProjectQuota(ctx context.Context, path string, projID uint32, limitBytes int64) error {
+	return fs.setProjectQuota(ctx, path, projID, limitBytes)
+}
+
+func (fs *mockfs) setProjectQuota(_ context.Context, _ string, projID uint32, limitBytes int64) error {
+	if GOFSMock.InduceProjectQuotaError {
+		return errors.New("setProjectQuota induced error")
+	}
+	if GOFSMockProjectQuotas == nil {
+		GOFSMockProjectQuotas = make(map[uint32]*ProjectQuota)
+	}
+	GOFSMockProjectQuotas[projID] = &ProjectQuota{ProjectID: projID, LimitBytes: limitBytes}
+	return nil
+}
+
+func (fs *mockfs) GetProjectQuota(ctx context.Context, path string, projID uint32) (*ProjectQuota, error) {
+	return fs.getProjectQuota(ctx, path, projID)
+}
+
+func (fs *mockfs) getProjectQuota(_ context.Context, _ string, projID uint32) (*ProjectQuota, error) {
+	if GOFSMock.InduceProjectQuotaError {
+		return nil, errors.New("getProjectQuota induced error")
+	}
+	if quota, ok := GOFSMockProjectQuotas[projID]; ok {
+		q := *quota
+		return &q, nil
+	}
+	return &ProjectQuota{ProjectID: projID}, nil
+}
+
+func (fs *mockfs) GetPartitions(ctx context.Context, device string) (*PartitionTable, error) {
+	return fs.getPartitions(ctx, device)
+}
+
+func (fs *mockfs) getPartitions(_ context.Context, device string) (*PartitionTable, error) {
+	if GOFSMock.InducePartitionError {
+		return nil, errors.New("getPartitions induced error")
+	}
+	if table, ok := GOFSMockPartitions[device]; ok {
+		t := *table
+		t.Partitions = append([]Partition(nil), table.Partitions...)
+		return &t, nil
+	}
+	return &PartitionTable{Device: device, SectorSize: 512, Partitions: []Partition{}}, nil
+}
+
+func (fs *mockfs) CreatePartition(ctx context.Context, device string, spec PartitionSpec) (*Partition, error) {
+	return fs.createPartition(ctx, device, spec)
+}
+
+func (fs *mockfs) createPartition(_ context.Context, device string, spec PartitionSpec) (*Partition, error) {
+	if GOFSMock.InducePartitionError {
+		return nil, errors.New("createPartition induced error")
+	}
+	if GOFSMockPartitions == nil {
+		GOFSMockPartitions = make(map[string]*PartitionTable)
+	}
+	table, ok := GOFSMockPartitions[device]
+	if !ok {
+		table = &PartitionTable{Device: device, Label: "gpt", SectorSize: 512}
+		GOFSMockPartitions[device] = table
+	}
+	number := spec.Number
+	if number == 0 {
+		number = len(table.Partitions) + 1
+	}
+	if _, exists := table.Partition(number); exists {
+		return nil, fmt.Errorf("partition %d exists", number)
+	}
+	p := Partition{
+		Number: number,
+		Device: fmt.Sprintf("%s%d", device, number),
+		Start:  spec.Start,
+		Size:   spec.Size,
+		Type:   spec.Type,
+		Name:   spec.Name,
+	}
+	table.Partitions = append(table.Partitions, p)
+	return &p, nil
+}
+
+func (fs *mockfs) DeletePartition(ctx context.Context, device string, number int) error {
+	return fs.deletePartition(ctx, device, number)
+}
+
+func (fs *mockfs) deletePartition(_ context.Context, device string, number int) error {
+	if GOFSMock.InducePartitionError {
+		return errors.New("deletePartition induced error")
+	}
+	table, ok := GOFSMockPartitions[device]
+	if !ok {
+		return fmt.Errorf("partition %d not found", number)
+	}
+	for i, p := range table.Partitions {
+		if p.Number == number {
+			table.Partitions = append(table.Partitions[:i], table.Partitions[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("partition %d not found", number)
+}
+
+func (fs *mockfs) GrowPartition(ctx context.Context, device string, number int) error {
+	return fs.growPartition(ctx, device, number)
+}
+
+func (fs *mockfs) growPartition(_ context.Context, _ string, _ int) error {
+	if GOFSMock.InducePartitionError {
+		return errors.New("growPartition induced error")
+	}
+	return nil
+}
+
+// GetMountsInto returns the mock mounts in infos.
+func (fs *mockfs) GetMountsInto(ctx context.Context, infos []Info) ([]Info, error) {
+	return fs.getMountsInto(ctx, infos)
+}
+
+func (fs *mockfs) getMountsInto(_ context.Context, infos []Info) ([]Info, error) {
+	infos = infos[:0]
+	if GOFSMock.InduceGetMountsError {
+		return infos, errors.New("getMountsInto induced error")
+	}
+	mockMountsMu.Lock()
+	defer mockMountsMu.Unlock()
+	return append(infos, GOFSMockMounts...), nil
+}
+
+// GetNPIVPorts returns the mock NPIV ports.
+func (fs *mockfs) GetNPIVPorts(ctx context.Context) ([]NPIVPort, error) {
+	return fs.getNPIVPorts(ctx)
+}
+
+func (fs *mockfs) getNPIVPorts(_ context.Context) ([]NPIVPort, error) {
+	if GOFSMock.InduceNPIVPortError {
+		return nil, errors.New("getNPIVPorts induced error")
+	}
+	return append([]NPIVPort{}, GOFSMockNPIVPorts...), nil
+}
+
+// CreateNPIVPort adds a mock NPIV port.
+func (fs *mockfs) CreateNPIVPort(ctx context.Context, host, wwpn, wwnn string) (*NPIVPort, error) {
+	return fs.createNPIVPort(ctx, host, wwpn, wwnn)
+}
+
+func (fs *mockfs) createNPIVPort(_ context.Context, host, wwpn, wwnn string) (*NPIVPort, error) {
+	if GOFSMock.InduceNPIVPortError {
+		return nil, errors.New("createNPIVPort induced error")
+	}
+	host, err := fcHostName(host)
+	if err != nil {
+		return nil, err
+	}
+	for _, wwn := range []*string{&wwpn, &wwnn} {
+		if *wwn == "" {
+			*wwn, err = generateFCWWN()
+		} else {
+			*wwn, err = normalizeFCWWN(*wwn)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	vport := NPIVPort{
+		Name:     fmt.Sprintf("vport-%s:0-%d", strings.TrimPrefix(host, "host"), len(GOFSMockNPIVPorts)),
+		Host:     host,
+		PortName: "0x" + wwpn,
+		NodeName: "0x" + wwnn,
+		State:    "Active",
+	}
+	GOFSMockNPIVPorts = append(GOFSMockNPIVPorts, vport)
+	return &vport, nil
+}
+
+// DeleteNPIVPort removes a mock NPIV port.
+func (fs *mockfs) DeleteNPIVPort(ctx context.Context, host, wwpn string) error {
+	return fs.deleteNPIVPort(ctx, host, wwpn)
+}
+
+func (fs *mockfs) deleteNPIVPort(_ context.Context, host, wwpn string) error {
+	if GOFSMock.InduceNPIVPortError {
+		return errors.New("deleteNPIVPort induced error")
+	}
+	host, err := fcHostName(host)
+	if err != nil {
+		return err
+	}
+	if wwpn, err = normalizeFCWWN(wwpn); err != nil {
+		return err
+	}
+	for i, vport := range GOFSMockNPIVPorts {
+		if vport.Host == host && vport.PortName == "0x"+wwpn {
+			GOFSMockNPIVPorts = append(GOFSMockNPIVPorts[:i], GOFSMockNPIVPorts[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("NPIV port 0x%s not found", wwpn)
+}
+
+// GetFCHostInfo returns the mock FC hosts.
+func (fs *mockfs) GetFCHostInfo(ctx context.Context) ([]FCHostInfo, error) {
+	return fs.getFCHostInfo(ctx)
+}
+
+func (fs *mockfs) getFCHostInfo(_ context.Context) ([]FCHostInfo, error) {
+	if GOFSMock.InduceFCHostWWNsError {
+		return nil, errors.New("getFCHostInfo induced error")
+	}
+	return append([]FCHostInfo{}, GOFSMockFCHosts...), nil
+}
+
+// GetISCSISessionState returns the mock iSCSI sessions to the target.
+func (fs *mockfs) GetISCSISessionState(ctx context.Context, targetIQN string) ([]ISCSISession, error) {
+	return fs.getISCSISessionState(ctx, targetIQN)
+}
+
+func (fs *mockfs) getISCSISessionState(_ context.Context, targetIQN string) ([]ISCSISession, error) {
+	if GOFSMock.InduceISCSISessionError {
+		return nil, errors.New("getISCSISessionState induced error")
+	}
+	sessions := make([]ISCSISession, 0)
+	for _, s := range GOFSMockISCSISessions {
+		if targetIQN == "" || s.TargetName == targetIQN {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions, nil
+}
+
+// GetHostNQN returns the mock host NQN.
+func (fs *mockfs) GetHostNQN(ctx context.Context, create bool) (string, error) {
+	return fs.getHostNQN(ctx, create)
+}
+
+func (fs *mockfs) getHostNQN(_ context.Context, create bool) (string, error) {
+	return mockHostIdentity(&GOFSMockHostNQN, create, GenerateHostNQN)
+}
+
+// GetNQNHostID returns the mock host ID.
+func (fs *mockfs) GetNQNHostID(ctx context.Context, create bool) (string, error) {
+	return fs.getNQNHostID(ctx, create)
+}
+
+func (fs *mockfs) getNQNHostID(_ context.Context, create bool) (string, error) {
+	return mockHostIdentity(&GOFSMockHostID, create, newUUID)
+}
+
+// GetHostIQN returns the mock initiator name.
+func (fs *mockfs) GetHostIQN(ctx context.Context, create bool) (string, error) {
+	return fs.getHostIQN(ctx, create)
+}
+
+func (fs *mockfs) getHostIQN(_ context.Context, create bool) (string, error) {
+	return mockHostIdentity(&GOFSMockHostIQN, create, GenerateHostIQN)
+}
+
+// mockHostIdentity returns *value, generating it if it is empty and
+// create is set.
+func mockHostIdentity(value *string, create bool, generate func() (string, error)) (string, error) {
+	if GOFSMock.InduceHostIdentityError {
+		return "", errors.New("host identity induced error")
+	}
+	if *value == "" {
+		if !create {
+			return "", os.ErrNotExist
+		}
+		id, err := generate()
+		if err != nil {
+			return "", err
+		}
+		*value = id
+	}
+	return *value, nil
+}
+
+// EnsureDeviceUnused returns an InUseError if the device is mounted.
+func (fs *mockfs) EnsureDeviceUnused(ctx context.Context, device string) error {
+	return fs.ensureDeviceUnused(ctx, device)
+}
+
+func (fs *mockfs) ensureDeviceUnused(_ context.Context, device string) error {
+	if GOFSMock.InduceDeviceInUse {
+		return &InUseError{Device: device, Reason: DeviceOpenExclusive}
+	}
+	var mountPoints []string
+	for _, m := range mockDeviceMounts(mockDevice(device)) {
+		mountPoints = append(mountPoints, m.Path)
+	}
+	if len(mountPoints) > 0 {
+		return &InUseError{Device: device, Reason: DeviceMounted, Users: mountPoints}
+	}
+	return nil
+}
+
+// RegisterSupportedFsType adds filesystem types to the mock's list.
+func (fs *mockfs) RegisterSupportedFsType(fsTypes ...string) {
+	fs.fsTypes.register(fsTypes...)
+}
+
+// SetSupportedFsTypes replaces the mock's list of filesystem types.
+func (fs *mockfs) SetSupportedFsTypes(fsTypes ...string) {
+	fs.fsTypes.set(fsTypes...)
+}
+
+// SupportedFsTypes returns the mock's list of filesystem types.
+func (fs *mockfs) SupportedFsTypes() []string {
+	return fs.fsTypes.list()
+}
+
+// SetDefaultMountOptions replaces the default mount options of fsType.
+func (fs *mockfs) SetDefaultMountOptions(fsType string, opts ...string) {
+	fs.mountDefaults.set(fsType, opts...)
+}
+
+// DefaultMountOptions returns the default mount options of fsType.
+func (fs *mockfs) DefaultMountOptions(fsType string) []string {
+	return fs.mountDefaults.get(fsType)
+}
+
+// SMBMount adds a mock cifs mount of the share.
+func (fs *mockfs) SMBMount(ctx context.Context, source, target string, creds SMBCredentials, opts ...string) error {
+	return fs.smbMount(ctx, source, target, creds, opts...)
+}
+
+func (fs *mockfs) smbMount(_ context.Context, source, target string, _ SMBCredentials, opts ...string) error {
+	if GOFSMock.InduceMountError {
+		return errors.New("smbMount induced error")
+	}
+	mockAddMount(Info{
+		Device: source,
+		Path:   target,
+		Source: source,
+		Type:   "cifs",
+		Opts:   append([]string{}, opts...),
+		Kind:   MountKindOther,
+	})
+	return nil
+}
+
+// GetCommandHistory returns no commands as the mock runs none.
+func (fs *mockfs) GetCommandHistory() []CommandRecord {
+	return []CommandRecord{}
+}
+
+// UnmountTree unmounts the mocked mounts at or below root, deepest first.
+func (fs *mockfs) UnmountTree(ctx context.Context, root string) ([]UnmountResult, error) {
+	mounts, err := fs.getMounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return unmountTree(ctx, mountPathsUnder(mounts, root), 1, 0, fs.unmount, nil), nil
+}
+
+func (fs *mockfs) getMpathDeviceForWWN(_ context.Context, wwn string) (*MpathDevice, error) {
+	if GOFSMock.InduceGetMpathDeviceError {
+		return nil, errors.New("getMpathDeviceForWWN induced error")
+	}
+	w, err := NormalizeWWN(wwn)
+	if err != nil {
+		return nil, err
+	}
+	for _, dev := range GOFSMockMpathDevices {
+		if dev.WWN == w {
+			return &dev, nil
+		}
+	}
+	return nil, nil
+}
+
+func (fs *mockfs) GetMpathDeviceForWWN(ctx context.Context, wwn string) (*MpathDevice, error) {
+	return fs.getMpathDeviceForWWN(ctx, wwn)
+}
+
+func (fs *mockfs) blockDevSetRO(_ context.Context, device string, readOnly bool) error {
+	if readOnly && GOFSMock.InduceBlockDevSetROError {
+		return errors.New("blockDevSetRO induced error")
+	}
+	if !readOnly && GOFSMock.InduceBlockDevSetRWError {
+		return errors.New("blockDevSetRW induced error")
+	}
+	dev := mockDevice(device)
+	if !readOnly {
+		delete(GOFSMockReadOnlyDevices, dev)
+		return nil
+	}
+	if GOFSMockReadOnlyDevices == nil {
+		GOFSMockReadOnlyDevices = make(map[string]bool)
+	}
+	GOFSMockReadOnlyDevices[dev] = true
+	return nil
+}
+
+func (fs *mockfs) blockDevGetRO(_ context.Context, device string) (bool, error) {
+	if GOFSMock.InduceBlockDevGetROError {
+		return false, errors.New("blockDevGetRO induced error")
+	}
+	return GOFSMockReadOnlyDevices[mockDevice(device)], nil
+}
+
+func (fs *mockfs) BlockDevSetRO(ctx context.Context, device string, readOnly bool) error {
+	return fs.blockDevSetRO(ctx, device, readOnly)
+}
+
+func (fs *mockfs) BlockDevGetRO(ctx context.Context, device string) (bool, error) {
+	return fs.blockDevGetRO(ctx, device)
+}
+
+func (fs *mockfs) cleanupDeviceForWWN(_ context.Context, wwn string) (*DeviceCleanupReport, error) {
+	report := &DeviceCleanupReport{WWN: wwn}
+	if GOFSMock.InduceCleanupDeviceError {
+		return report, errors.New("cleanupDeviceForWWN induced error")
+	}
+	dev, ok := GOFSMockWWNToDevice[wwn]
+	if !ok || dev == "" {
+		return report, nil
+	}
+	report.Devices = []string{filepath.Base(dev)}
+	if mounts := mockDeviceMounts(dev); len(mounts) > 0 {
+		users := make([]string, 0, len(mounts))
+		for _, m := range mounts {
+			users = append(users, m.Path)
+		}
+		return report, &InUseError{Device: wwn, Reason: DeviceMounted, Users: users}
+	}
+	mockRemoveDevice(dev)
+	report.Removed = report.Devices
+	return report, nil
+}
+
+func (fs *mockfs) CleanupDeviceForWWN(ctx context.Context, wwn string) (*DeviceCleanupReport, error) {
+	return fs.cleanupDeviceForWWN(ctx, wwn)
+}
+
+func (fs *mockfs) isPMEMDevice(_ context.Context, device string) (bool, error) {
+	return isPMEMDeviceName(filepath.Base(mockDevice(device))), nil
+}
+
+func (fs *mockfs) mountDAX(ctx context.Context, source, target, fsType string, opts ...string) error {
+	if GOFSMock.InduceDAXError || !stringInSlice(fsType, daxFsTypes) {
+		return &DAXError{Device: source, FsType: fsType, Reason: "DAX induced error"}
+	}
+	daxOpts, _ := withDAXOption(opts)
+	return fs.mount(ctx, source, target, fsType, daxOpts...)
+}
+
+func (fs *mockfs) IsPMEMDevice(ctx context.Context, device string) (bool, error) {
+	return fs.isPMEMDevice(ctx, device)
+}
+
+func (fs *mockfs) MountDAX(ctx context.Context, source, target, fsType string, opts ...string) error {
+	return fs.mountDAX(ctx, source, target, fsType, opts...)
+}
+
+func (fs *mockfs) getISCSITargetsFromByPath(_ context.Context) ([]ISCSITargetDevice, error) {
+	if GOFSMock.InduceISCSITargetsError {
+		return nil, errors.New("getISCSITargetsFromByPath induced error")
+	}
+	return append([]ISCSITargetDevice{}, GOFSMockISCSITargetDevices...), nil
+}
+
+func (fs *mockfs) GetISCSITargetsFromByPath(ctx context.Context) ([]ISCSITargetDevice, error) {
+	return fs.getISCSITargetsFromByPath(ctx)
+}
+
+func (fs *mockfs) FCTargetWWPNLUNToDevicePath(ctx context.Context, wwpn string, lunID int) (map[string]string, error) {
+	return fs.fcTargetWWPNLUNToDevicePath(ctx, wwpn, lunID)
+}
+
+func (fs *mockfs) fcTargetWWPNLUNToDevicePath(_ context.Context, wwpn string, lunID int) (map[string]string, error) {
+	result := make(map[string]string)
+	if GOFSMock.InduceFCTargetLUNToDeviceError {
+		return result, errors.New("fcTargetWWPNLUNToDevicePath induced error")
+	}
+	wwpn, err := normalizeFCWWN(wwpn)
+	if err != nil {
+		return result, err
+	}
+	key := fmt.Sprintf("%s-lun-%d", wwpn, lunID)
+	if path, ok := GOFSMockFCTargetLUNToDevice[key]; ok {
+		result[key] = path
+	}
+	return result, nil
+}
+
+func (fs *mockfs) RescanSCSIHostsForLUNs(ctx context.Context, targets []string, luns []string) (*RescanReport, error) {
+	return fs.rescanSCSIHostsForLUNs(ctx, targets, luns)
+}
+
+func (fs *mockfs) rescanSCSIHostsForLUNs(ctx context.Context, _ []string, luns []string) (*RescanReport, error) {
+	if err := mockFault(ctx, "rescanSCSIHostsForLUNs", GOFSMockSchedules.RescanSCSIHost, &GOFSMockCalls.RescanSCSIHost); err != nil {
+		return nil, err
+	}
+	report := &RescanReport{ScanStrings: make(map[string][]string)}
+	if GOFSMock.InduceRescanError {
+		return report, errors.New("induced rescan error")
+	}
+	if GOFSRescanCallback != nil {
+		for _, lun := range luns {
+			GOFSRescanCallback(lun)
+		}
+	}
+	return report, nil
+}
+
+func (fs *mockfs) SetTargetPermissions(ctx context.Context, target string, uid, gid int, mode os.FileMode, recursive bool) error {
+	return fs.setTargetPermissions(ctx, target, uid, gid, mode, recursive)
+}
+
+func (fs *mockfs) setTargetPermissions(_ context.Context, _ string, _, _ int, _ os.FileMode, _ bool) error {
+	if GOFSMock.InduceSetTargetPermissionsError {
+		return errors.New("setTargetPermissions induced error")
+	}
+	return nil
+}
+
+func (fs *mockfs) SetTargetImmutable(ctx context.Context, target string, immutable bool) error {
+	return fs.setTargetImmutable(ctx, target, immutable)
+}
+
+func (fs *mockfs) setTargetImmutable(_ context.Context, target string, immutable bool) error {
+	if GOFSMock.InduceSetTargetImmutableError {
+		return errors.New("setTargetImmutable induced error")
+	}
+	if !immutable {
+		delete(GOFSMockImmutableTargets, target)
+		return nil
+	}
+	for _, m := range mockMountsSnapshot() {
+		if m.Path == target {
+			return fmt.Errorf("%s is a mount point, not making it immutable", target)
+		}
+	}
+	if GOFSMockImmutableTargets == nil {
+		GOFSMockImmutableTargets = make(map[string]bool)
+	}
+	GOFSMockImmutableTargets[target] = true
+	return nil
+}
+
+func (fs *mockfs) GetLoopDeviceForFile(ctx context.Context, file string) (string, error) {
+	return fs.getLoopDeviceForFile(ctx, file)
+}
+
+func (fs *mockfs) getLoopDeviceForFile(_ context.Context, file string) (string, error) {
+	if GOFSMock.InduceLoopDeviceError {
+		return "", errors.New("getLoopDeviceForFile induced error")
+	}
+	return GOFSMockLoopDevices[file], nil
+}
+
+func (fs *mockfs) AttachLoopDevice(ctx context.Context, file string, readOnly bool) (string, error) {
+	device, _, err := fs.attachLoopDevice(ctx, file, readOnly)
+	return device, err
+}
+
+func (fs *mockfs) attachLoopDevice(_ context.Context, file string, _ bool) (string, bool, error) {
+	if GOFSMock.InduceLoopDeviceError {
+		return "", false, errors.New("attachLoopDevice induced error")
+	}
+	if device, ok := GOFSMockLoopDevices[file]; ok {
+		return device, false, nil
+	}
+	if GOFSMockLoopDevices == nil {
+		GOFSMockLoopDevices = make(map[string]string)
+	}
+	device := fmt.Sprintf("/dev/loop%d", len(GOFSMockLoopDevices))
+	GOFSMockLoopDevices[file] = device
+	return device, true, nil
+}
+
+func (fs *mockfs) DetachLoopDevice(ctx context.Context, device string) error {
+	return fs.detachLoopDevice(ctx, device)
+}
+
+func (fs *mockfs) detachLoopDevice(_ context.Context, device string) error {
+	if GOFSMock.InduceLoopDeviceError {
+		return errors.New("detachLoopDevice induced error")
+	}
+	for file, d := range GOFSMockLoopDevices {
+		if d == device {
+			delete(GOFSMockLoopDevices, file)
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not attached", device)
+}
+
+func (fs *mockfs) FormatAndMountFile(ctx context.Context, file, target, fsType string, opts ...string) (string, error) {
+	return fs.formatAndMountFile(ctx, file, target, fsType, opts...)
+}
+
+func (fs *mockfs) formatAndMountFile(ctx context.Context, file, target, fsType string, opts ...string) (string, error) {
+	device, attached, err := fs.attachLoopDevice(ctx, file, false)
+	if err != nil {
+		return "", err
+	}
+	if err := fs.formatAndMount(ctx, device, target, fsType, opts...); err != nil {
+		if attached {
+			_ = fs.detachLoopDevice(ctx, device)
+		}
+		return "", err
+	}
+	return device, nil
+}
+
+func (fs *mockfs) MountTmpfs(ctx context.Context, target string, sizeBytes int64, mode os.FileMode, opts ...string) error {
+	return fs.mountTmpfs(ctx, target, sizeBytes, mode, opts...)
+}
+
+func (fs *mockfs) mountTmpfs(ctx context.Context, target string, sizeBytes int64, mode os.FileMode, opts ...string) error {
+	if err := mockFault(ctx, "mountTmpfs", GOFSMockSchedules.Mount, &GOFSMockCalls.Mount); err != nil {
+		return err
+	}
+	if GOFSMock.InduceMountError {
+		return errors.New("mountTmpfs induced error")
+	}
+	tmpfsOpts, err := tmpfsOptions(sizeBytes, mode, opts)
+	if err != nil {
+		return err
+	}
+	mockAddMount(Info{
+		Device: "tmpfs",
+		Path:   target,
+		Source: "tmpfs",
+		Type:   "tmpfs",
+		Opts:   fs.mountDefaults.merge("tmpfs", tmpfsOpts),
+		Kind:   MountKindTmpfs,
+	})
+	return nil
+}
+
+func (fs *mockfs) GetMultipathKind(ctx context.Context, wwn string) (MultipathKind, error) {
+	return fs.getMultipathKind(ctx, wwn)
+}
+
+func (fs *mockfs) getMultipathKind(_ context.Context, wwn string) (MultipathKind, error) {
+	if GOFSMock.InduceGetMultipathKindError {
+		return "", errors.New("getMultipathKind induced error")
+	}
+	if kind, ok := GOFSMockMultipathKinds[wwn]; ok {
+		return kind, nil
+	}
+	return MultipathNone, nil
+}
+
+func (fs *mockfs) FsHealthCheck(ctx context.Context, mountpoint string) (*FsHealth, error) {
+	return fs.fsHealthCheck(ctx, mountpoint)
+}
+
+func (fs *mockfs) fsHealthCheck(_ context.Context, mountpoint string) (*FsHealth, error) {
+	health := &FsHealth{Path: mountpoint}
+	if GOFSMock.InduceFsHealthCheckAbnormal {
+		health.fail("write", errors.New("fsHealthCheck induced error"))
+		return health, nil
+	}
+	health.Writable = true
+	return health, nil
+}
+
+func (fs *mockfs) CheckClusterStack(ctx context.Context, fsType string, opts ...string) error {
+	return fs.checkClusterStack(ctx, fsType, opts...)
+}
+
+func (fs *mockfs) checkClusterStack(_ context.Context, fsType string, _ ...string) error {
+	if GOFSMock.InduceClusterStackError && isClusterFsType(fsType) {
+		return fmt.Errorf("%w: checkClusterStack induced error", ErrClusterStackNotRunning)
+	}
+	return nil
+}
+
+// GetDryRunActions returns no actions as the mock takes none.
+func (fs *mockfs) GetDryRunActions() []DryRunAction {
+	return []DryRunAction{}
+}
+
+// ClearDryRunActions does nothing as the mock records no actions.
+func (fs *mockfs) ClearDryRunActions() {}
+
+// SetTracerProvider does nothing as the mock creates no spans.
+func (fs *mockfs) SetTracerProvider(_ trace.TracerProvider) {}
+
+func (fs *mockfs) GetDeviceTopology(ctx context.Context, device string) (*DeviceTopology, error) {
+	return fs.getDeviceTopology(ctx, device)
+}
+
+func (fs *mockfs) getDeviceTopology(_ context.Context, device string) (*DeviceTopology, error) {
+	if GOFSMock.InduceDeviceTopologyError {
+		return nil, errors.New("getDeviceTopology induced error")
+	}
+	if t, ok := GOFSMockDeviceTopologies[device]; ok {
+		return t, nil
+	}
+	return nil, fmt.Errorf("device %s not found", device)
+}
+
+func (fs *mockfs) GetBlockDeviceHolders(ctx context.Context, device string) ([]string, error) {
+	return fs.getBlockDeviceHolders(ctx, device)
+}
+
+func (fs *mockfs) getBlockDeviceHolders(ctx context.Context, device string) ([]string, error) {
+	t, err := fs.getDeviceTopology(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+	return t.holderPaths(), nil
+}
+
+func (fs *mockfs) GetNVMePathStates(ctx context.Context, nqn string) ([]NVMePathState, error) {
+	return fs.getNVMePathStates(ctx, nqn)
+}
+
+func (fs *mockfs) getNVMePathStates(_ context.Context, nqn string) ([]NVMePathState, error) {
+	if GOFSMock.InduceGetNVMePathStatesError {
+		return nil, errors.New("getNVMePathStates induced error")
+	}
+	paths := make([]NVMePathState, 0)
+	return append(paths, GOFSMockNVMePathStates[nqn]...), nil
+}
+
+func (fs *mockfs) MountSensitive(ctx context.Context, source, target, fsType string, opts, sensitiveOpts []string) error {
+	return fs.mountSensitive(ctx, source, target, fsType, opts, sensitiveOpts)
+}
+
+func (fs *mockfs) mountSensitive(ctx context.Context, source, target, fsType string, opts, sensitiveOpts []string) error {
+	ctx = withSensitiveOptions(ctx, sensitiveOpts)
+	allOpts := append(opts[:len(opts):len(opts)], sensitiveOpts...)
+	return redactError(fs.mount(ctx, source, target, fsType, allOpts...), sensitiveOpts)
+}
+
+func (fs *mockfs) MountWithSpec(ctx context.Context, spec MountSpec) error {
+	return fs.mountWithSpec(ctx, spec)
+}
+
+func (fs *mockfs) mountWithSpec(ctx context.Context, spec MountSpec) error {
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	return fs.mountSensitive(ctx, spec.Source, spec.Target, spec.FsType, spec.Options(), spec.SensitiveData)
+}
+
+// StageBlockVolume stages the volume with the operations of the mock.
+func (fs *mockfs) StageBlockVolume(ctx context.Context, req StageRequest) (*StageResult, error) {
+	return stageBlockVolume(ctx, fs, req, nil)
+}
+
+// UnstageBlockVolume unstages the volume with the operations of the mock.
+func (fs *mockfs) UnstageBlockVolume(ctx context.Context, req UnstageRequest) (*UnstageResult, error) {
+	return unstageBlockVolume(ctx, fs, req)
+}
+
+func (fs *mockfs) GetHostSCSIHosts(ctx context.Context) ([]SCSIHost, error) {
+	return fs.getHostSCSIHosts(ctx)
+}
+
+func (fs *mockfs) getHostSCSIHosts(_ context.Context) ([]SCSIHost, error) {
+	if GOFSMock.InduceGetSCSIHostsError {
+		return nil, errors.New("getHostSCSIHosts induced error")
+	}
+	return append([]SCSIHost{}, GOFSMockSCSIHosts...), nil
+}
+
+func (fs *mockfs) DMNameToDevPath(ctx context.Context, name string) (*DMDevice, error) {
+	return fs.dmNameToDevPath(ctx, name)
+}
+
+func (fs *mockfs) dmNameToDevPath(_ context.Context, name string) (*DMDevice, error) {
+	if GOFSMock.InduceDMNameToDevPathError {
+		return nil, errors.New("dmNameToDevPath induced error")
+	}
+	name, err := dmName(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range GOFSMockDMDevices {
+		if d.Name == name {
+			return &d, nil
+		}
+	}
+	return nil, fmt.Errorf("device mapper device %s not found", name)
+}
+
+func (fs *mockfs) DevPathToDMName(ctx context.Context, devPath string) (*DMDevice, error) {
+	return fs.devPathToDMName(ctx, devPath)
+}
+
+func (fs *mockfs) devPathToDMName(ctx context.Context, devPath string) (*DMDevice, error) {
+	if GOFSMock.InduceDevPathToDMNameError {
+		return nil, errors.New("devPathToDMName induced error")
+	}
+	if strings.HasPrefix(devPath, "/dev/mapper/") {
+		return fs.dmNameToDevPath(ctx, devPath)
+	}
+	for _, d := range GOFSMockDMDevices {
+		if d.DevPath == devPath || filepath.Base(d.DevPath) == devPath {
+			return &d, nil
+		}
+	}
+	return nil, fmt.Errorf("device mapper device %s not found", devPath)
+}
+
+func (fs *mockfs) GetDevicePathsForMountPoint(ctx context.Context, target string) ([]string, error) {
+	return fs.getDevicePathsForMountPoint(ctx, target)
+}
+
+func (fs *mockfs) getDevicePathsForMountPoint(_ context.Context, target string) ([]string, error) {
+	if GOFSMock.InduceDevicePathsForMountError {
+		return nil, errors.New("getDevicePathsForMountPoint induced error")
+	}
+	var paths []string
+	for _, info := range mockMountsSnapshot() {
+		if info.Path == target && info.Device != "" && !stringInSlice(info.Device, paths) {
+			paths = append(paths, info.Device)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no block device mounted at %s: %w", target, os.ErrNotExist)
+	}
+	return paths, nil
+}
+
+func (fs *mockfs) GetMountsByDevID(ctx context.Context, major, minor uint32) ([]Info, error) {
+	return fs.getMountsByDevID(ctx, major, minor)
+}
+
+func (fs *mockfs) getMountsByDevID(_ context.Context, major, minor uint32) ([]Info, error) {
+	if GOFSMock.InduceGetMountsByDevIDError {
+		return nil, errors.New("getMountsByDevID induced error")
+	}
+	return mountsWithDevID(mockMountsSnapshot(), DevID{Major: major, Minor: minor}), nil
+}
+
+func (fs *mockfs) GetNFSCapabilities(ctx context.Context) (*NFSCapabilities, error) {
+	return fs.getNFSCapabilities(ctx)
+}
+
+func (fs *mockfs) getNFSCapabilities(_ context.Context) (*NFSCapabilities, error) {
+	if GOFSMock.InduceGetNFSCapabilitiesError {
+		return nil, errors.New("getNFSCapabilities induced error")
+	}
+	if GOFSMockNFSCapabilities != nil {
+		return GOFSMockNFSCapabilities, nil
+	}
+	return &NFSCapabilities{KernelVersion: "mock", NConnect: true, RDMA: true}, nil
+}
+
+// MountNFS mounts the NFS export with the operations of the mock.
+func (fs *mockfs) MountNFS(ctx context.Context, source, target string, nfsOpts NFSMountOptions, opts ...string) error {
+	return mountNFS(ctx, fs, source, target, nfsOpts, opts...)
+}
+
+func (fs *mockfs) GetISCSIHostsForTargetPortal(ctx context.Context, portal string) ([]ISCSIPortalHost, error) {
+	return fs.getISCSIHostsForTargetPortal(ctx, portal)
+}
+
+func (fs *mockfs) getISCSIHostsForTargetPortal(_ context.Context, portal string) ([]ISCSIPortalHost, error) {
+	if GOFSMock.InduceISCSIPortalHostsError {
+		return nil, errors.New("getISCSIHostsForTargetPortal induced error")
+	}
+	addr, port := splitPortal(portal)
+	hosts := make([]ISCSIPortalHost, 0)
+	for _, h := range GOFSMockISCSIPortalHosts {
+		if sameAddress(h.Address, addr) && (port == "" || port == h.Port) {
+			hosts = append(hosts, h)
+		}
+	}
+	return hosts, nil
+}
+
+func (fs *mockfs) ReplayIncompleteOperations(ctx context.Context) ([]JournalReplay, error) {
+	return fs.replayIncompleteOperations(ctx)
+}
+
+func (fs *mockfs) replayIncompleteOperations(_ context.Context) ([]JournalReplay, error) {
+	if GOFSMock.InduceReplayOperationsError {
+		return nil, errors.New("replayIncompleteOperations induced error")
+	}
+	replays := make([]JournalReplay, 0, len(GOFSMockJournalReplays))
+	return append(replays, GOFSMockJournalReplays...), nil
+}
+
+func (fs *mockfs) RemoveBlockDeviceWithOptions(ctx context.Context, blockDevicePath string, opts RemoveBlockDeviceOptions) error {
+	if GOFSMock.InduceRemoveBlockDeviceError {
+		return errors.New("remove block device induced error")
+	}
+	return fs.removeBlockDeviceWithOptions(ctx, blockDevicePath, opts)
+}
+
+func (fs *mockfs) removeBlockDeviceWithOptions(ctx context.Context, blockDevicePath string, opts RemoveBlockDeviceOptions) error {
+	if busy := GOFSMockBusyDevices[blockDevicePath]; busy != nil {
+		err := &DeviceBusyError{Device: blockDevicePath}
+		if opts.CheckHolders {
+			err.Holders = busy.Holders
+		}
+		if opts.CheckOpenHandles {
+			err.Processes = busy.Processes
+		}
+		if len(err.Holders) > 0 || len(err.Processes) > 0 {
+			return err
+		}
+	}
+	return fs.removeBlockDevice(ctx, blockDevicePath)
+}
+
+func (fs *mockfs) GetNativeMultipathSetting(ctx context.Context) (bool, error) {
+	return fs.getNativeMultipathSetting(ctx)
+}
+
+func (fs *mockfs) getNativeMultipathSetting(_ context.Context) (bool, error) {
+	if GOFSMock.InduceNativeMultipathError {
+		return false, errors.New("getNativeMultipathSetting induced error")
+	}
+	return GOFSMockNVMeNativeMultipath, nil
+}
+
+func (fs *mockfs) CheckNVMeMultipathMode(ctx context.Context, mode NVMeMultipathMode) error {
+	return checkNVMeMultipathMode(ctx, fs, mode)
+}
+
+func (fs *mockfs) SubscribeMountChanges(ctx context.Context) (<-chan MountEvent, error) {
+	return fs.subscribeMountChanges(ctx)
+}
+
+// mockMountWatchInterval is how often the mock reads its mount table for
+// SubscribeMountChanges.
+const mockMountWatchInterval = 10 * time.Millisecond
+
+func (fs *mockfs) subscribeMountChanges(ctx context.Context) (<-chan MountEvent, error) {
+	if GOFSMock.InduceSubscribeMountChangesError {
+		return nil, errors.New("subscribeMountChanges induced error")
+	}
+	// The mounts are copied so that the snapshots are not changed by
+	// later mounts and unmounts.
+	getMounts := func(ctx context.Context) ([]Info, error) {
+		return fs.getMountsInto(ctx, nil)
+	}
+	return watchMounts(ctx, getMounts, sleepWait(mockMountWatchInterval), mockMountWatchInterval, nil)
+}
+
+func (fs *mockfs) SysfsReadAttr(ctx context.Context, attr string) (string, error) {
+	return fs.sysfsReadAttr(ctx, attr)
+}
+
+func (fs *mockfs) sysfsReadAttr(_ context.Context, attr string) (string, error) {
+	if GOFSMock.InduceSysfsReadAttrError {
+		return "", errors.New("sysfsReadAttr induced error")
+	}
+	name, err := sysfsAttrName(attr)
+	if err != nil {
+		return "", err
+	}
+	value, ok := GOFSMockSysfsAttrs[name]
+	if !ok {
+		return "", &os.PathError{Op: "open", Path: attr, Err: os.ErrNotExist}
+	}
+	return value, nil
+}
+
+func (fs *mockfs) SysfsWriteAttr(ctx context.Context, attr, value string) error {
+	return fs.sysfsWriteAttr(ctx, attr, value)
+}
+
+func (fs *mockfs) sysfsWriteAttr(_ context.Context, attr, value string) error {
+	if GOFSMock.InduceSysfsWriteAttrError {
+		return errors.New("sysfsWriteAttr induced error")
+	}
+	name, err := sysfsAttrName(attr)
+	if err != nil {
+		return err
+	}
+	if _, ok := GOFSMockSysfsAttrs[name]; !ok {
+		return &os.PathError{Op: "open", Path: attr, Err: os.ErrNotExist}
+	}
+	GOFSMockSysfsAttrs[name] = value
+	return nil
+}
+
+func (fs *mockfs) GetDeviceForPublishPath(ctx context.Context, publishPath string) (*PublishPathDevice, error) {
+	return fs.getDeviceForPublishPath(ctx, publishPath)
+}
+
+func (fs *mockfs) getDeviceForPublishPath(_ context.Context, publishPath string) (*PublishPathDevice, error) {
+	if GOFSMock.InduceDeviceForPublishPathError {
+		return nil, errors.New("getDeviceForPublishPath induced error")
+	}
+	mounts := mockMountsSnapshot()
+	var publish *Info
+	for i := range mounts {
+		if mounts[i].Path == publishPath {
+			publish = &mounts[i]
+		}
+	}
+	if publish == nil {
+		return nil, fmt.Errorf("%s is not mounted: %w", publishPath, os.ErrNotExist)
+	}
+	d := &PublishPathDevice{PublishPath: publishPath, Device: publish.Device, FsType: publish.Type}
+	// The mock records the device of the mount a bind mount is made from
+	// as its source, or the mount point itself as its device.
+	if publish.Source != "" {
+		d.Device = publish.Source
+	}
+	for _, m := range mockMountsSnapshot() {
+		if m.Path == publishPath || isPodVolumePath(m.Path) {
+			continue
+		}
+		if m.Path == d.Device || m.Device == d.Device {
+			d.StagingPath = m.Path
+			d.Device = m.Device
+			d.FsType = m.Type
+			break
+		}
+	}
+	d.DeviceNames = []string{filepath.Base(d.Device)}
+	d.WWN = mockDeviceWWN(d.Device)
+	return d, nil
+}
+
+func (fs *mockfs) GetFSGeometry(ctx context.Context, path string) (*FSGeometry, error) {
+	return fs.getFSGeometry(ctx, path)
+}
+
+func (fs *mockfs) getFSGeometry(_ context.Context, path string) (*FSGeometry, error) {
+	if GOFSMock.InduceGetFSGeometryError {
+		return nil, errors.New("getFSGeometry induced error")
+	}
+	g, ok := GOFSMockFSGeometry[path]
+	if !ok {
+		return nil, fmt.Errorf("no filesystem found on %s", path)
+	}
+	geometry := *g
+	return &geometry, nil
+}
+
+func (fs *mockfs) ShrinkFS(ctx context.Context, device string, newSizeBytes int64) error {
+	return fs.shrinkFS(ctx, device, newSizeBytes)
+}
+
+func (fs *mockfs) shrinkFS(_ context.Context, device string, newSizeBytes int64) error {
+	if GOFSMock.InduceShrinkFSError {
+		return errors.New("shrinkFS induced error")
+	}
+	g, ok := GOFSMockFSGeometry[device]
+	if !ok {
+		return fmt.Errorf("no filesystem found on %s", device)
+	}
+	if g.FsType == "xfs" {
+		return fmt.Errorf("%s filesystem on %s cannot be shrunk: %w", g.FsType, device, errors.ErrUnsupported)
+	}
+	if blocks := newSizeBytes / g.BlockSize; blocks > 0 && blocks < g.BlockCount {
+		g.BlockCount = blocks
+		return nil
+	}
+	return fmt.Errorf("invalid size %d to shrink %s to", newSizeBytes, device)
+}
+
+func (fs *mockfs) MultipathReconfigure(ctx context.Context) (*MultipathdResult, error) {
+	return fs.multipathReconfigure(ctx)
+}
+
+func (fs *mockfs) multipathReconfigure(_ context.Context) (*MultipathdResult, error) {
+	r := &MultipathdResult{Command: "reconfigure", Output: "ok"}
+	if GOFSMock.InduceMultipathReconfigureError {
+		r.Output = "fail"
+		return r, errors.New("multipathReconfigure induced error")
+	}
+	return r, nil
+}
+
+func (fs *mockfs) ReinstatePath(ctx context.Context, device string) (*MultipathdResult, error) {
+	return fs.reinstatePath(ctx, device)
+}
+
+func (fs *mockfs) reinstatePath(_ context.Context, device string) (*MultipathdResult, error) {
+	if GOFSMock.InduceReinstatePathError {
+		return nil, errors.New("reinstatePath induced error")
+	}
+	return mockSetMultipathPathState("reinstate", device, "active", "ready")
+}
+
+func (fs *mockfs) FailPath(ctx context.Context, device string) (*MultipathdResult, error) {
+	return fs.failPath(ctx, device)
+}
+
+func (fs *mockfs) failPath(_ context.Context, device string) (*MultipathdResult, error) {
+	if GOFSMock.InduceFailPathError {
+		return nil, errors.New("failPath induced error")
+	}
+	return mockSetMultipathPathState("fail", device, "failed", "faulty")
+}
+
+// mockSetMultipathPathState sets the state of the path device in
+// GOFSMockMultipathPaths.
+func mockSetMultipathPathState(action, device, dmState, checkerState string) (*MultipathdResult, error) {
+	name := filepath.Base(device)
+	r := &MultipathdResult{Command: action + " path " + name, Output: "ok"}
+	p, ok := GOFSMockMultipathPaths[name]
+	if !ok {
+		r.Output = "fail"
+		return r, fmt.Errorf("multipathd %s failed: %s", r.Command, r.Output)
+	}
+	p.DMState, p.CheckerState = dmState, checkerState
+	state := *p
+	r.Path = &state
+	return r, nil
+}
+
+func (fs *mockfs) VerifyDeviceReadable(ctx context.Context, device string, offset, length int64) error {
+	return fs.verifyDeviceReadable(ctx, device, offset, length)
+}
+
+func (fs *mockfs) verifyDeviceReadable(_ context.Context, device string, offset, length int64) error {
+	if GOFSMock.InduceVerifyDeviceReadableError {
+		return errors.New("verifyDeviceReadable induced error")
+	}
+	if length <= 0 {
+		length = DefaultDeviceProbeLength
+	}
+	if GOFSMockUnreadableDevices[device] {
+		return &DeviceReadError{Device: device, Offset: offset, Length: length, Err: errors.New("input/output error")}
+	}
+	return nil
+}
+
+// UnmountDevice unmounts the mocked mounts of device, deepest first.
+func (fs *mockfs) UnmountDevice(ctx context.Context, device string) ([]UnmountResult, error) {
+	return unmountDevice(ctx, device, 1, 0, fs.getDevMounts, fs.unmount)
+}
+
+func (fs *mockfs) VerifyMountedCapacity(ctx context.Context, target string, expected int64, tolerance float64) error {
+	return fs.verifyMountedCapacity(ctx, target, expected, tolerance)
+}
+
+func (fs *mockfs) verifyMountedCapacity(ctx context.Context, target string, expected int64, tolerance float64) error {
+	if GOFSMock.InduceVerifyMountedCapacityError {
+		return errors.New("verifyMountedCapacity induced error")
+	}
+	mounted, err := fs.isMountPoint(ctx, target)
+	if err != nil {
+		return err
+	}
+	if !mounted {
+		return fmt.Errorf("%s is not a mount point", target)
+	}
+	capacity, ok := GOFSMockMountCapacities[target]
+	if !ok {
+		if _, capacity, _, _, _, _, err = fs.fsInfo(ctx, target); err != nil {
+			return err
+		}
+	}
+	return checkCapacity(target, capacity, expected, tolerance)
+}
@@ -0,0 +1,159 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// FaultSchedule decides whether an invocation of a mocked operation
+// fails. It is called with the number of the invocation, starting at 1.
+type FaultSchedule func(call int) bool
+
+// FailTimes returns a schedule that fails the first n invocations and
+// lets the following ones succeed.
+func FailTimes(n int) FaultSchedule {
+	return func(call int) bool {
+		return call <= n
+	}
+}
+
+// FailAfter returns a schedule that lets the first n invocations succeed
+// and fails the following ones.
+func FailAfter(n int) FaultSchedule {
+	return func(call int) bool {
+		return call > n
+	}
+}
+
+// FailEvery returns a schedule that fails every nth invocation.
+func FailEvery(n int) FaultSchedule {
+	return func(call int) bool {
+		return n > 0 && call%n == 0
+	}
+}
+
+// FailOnCalls returns a schedule that fails the given invocations.
+func FailOnCalls(calls ...int) FaultSchedule {
+	return func(call int) bool {
+		for _, c := range calls {
+			if c == call {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MockSchedules are the fault schedules of the operations of the mock,
+// nil for operations that do not fail. A scheduled failure is returned
+// in addition to the errors induced with GOFSMock.
+type MockSchedules struct {
+	Mount                  FaultSchedule
+	BindMount              FaultSchedule
+	Unmount                FaultSchedule
+	Format                 FaultSchedule
+	FormatAndMount         FaultSchedule
+	GetDiskFormat          FaultSchedule
+	GetMounts              FaultSchedule
+	GetDevMounts           FaultSchedule
+	GetMountInfoFromDevice FaultSchedule
+	ResizeFS               FaultSchedule
+	ResizeMultipath        FaultSchedule
+	DeviceRescan           FaultSchedule
+	WWNToDevicePath        FaultSchedule
+	RescanSCSIHost         FaultSchedule
+	RemoveBlockDevice      FaultSchedule
+	MultipathCommand       FaultSchedule
+	ConnectNVMeTarget      FaultSchedule
+	DisconnectNVMeTarget   FaultSchedule
+}
+
+// MockCallCounts are the numbers of invocations of the operations of the
+// mock, including invocations by other operations of the mock, e.g. the
+// mount of FormatAndMount.
+type MockCallCounts struct {
+	Mount                  int
+	BindMount              int
+	Unmount                int
+	Format                 int
+	FormatAndMount         int
+	GetDiskFormat          int
+	GetMounts              int
+	GetDevMounts           int
+	GetMountInfoFromDevice int
+	ResizeFS               int
+	ResizeMultipath        int
+	DeviceRescan           int
+	WWNToDevicePath        int
+	RescanSCSIHost         int
+	RemoveBlockDevice      int
+	MultipathCommand       int
+	ConnectNVMeTarget      int
+	DisconnectNVMeTarget   int
+}
+
+// MockLatency is the latency added to the operations of the mock that
+// have a fault schedule field in MockSchedules.
+type MockLatency struct {
+	// Min is the minimum latency.
+	Min time.Duration
+	// Max is the maximum latency. The latency is chosen at random between
+	// Min and Max for each invocation.
+	Max time.Duration
+}
+
+var (
+	// GOFSMockSchedules are the fault schedules of the mock operations.
+	GOFSMockSchedules MockSchedules
+	// GOFSMockCalls counts the invocations of the mock operations.
+	GOFSMockCalls MockCallCounts
+	// GOFSMockLatency is the latency injected into the mock operations.
+	GOFSMockLatency MockLatency
+
+	// mockFaultMu serializes the updates of GOFSMockCalls.
+	mockFaultMu sync.Mutex
+)
+
+// mockFault counts an invocation of the operation op, waits for the
+// injected latency, and returns an error if schedule fails the
+// invocation.
+func mockFault(ctx context.Context, op string, schedule FaultSchedule, calls *int) error {
+	mockFaultMu.Lock()
+	*calls++
+	call := *calls
+	latency := GOFSMockLatency
+	mockFaultMu.Unlock()
+
+	if latency.Max > 0 || latency.Min > 0 {
+		d := latency.Min
+		if latency.Max > latency.Min {
+			d += rand.N(latency.Max - latency.Min + 1) // #nosec G404
+		}
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+	if schedule != nil && schedule(call) {
+		return fmt.Errorf("%s scheduled error on call %d", op, call)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dell/gofsutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultSchedules(t *testing.T) {
+	var calls []bool
+	for _, s := range []gofsutil.FaultSchedule{
+		gofsutil.FailTimes(2),
+		gofsutil.FailAfter(2),
+		gofsutil.FailEvery(2),
+		gofsutil.FailOnCalls(1, 3),
+	} {
+		for call := 1; call <= 4; call++ {
+			calls = append(calls, s(call))
+		}
+	}
+	assert.Equal(t, []bool{
+		true, true, false, false,
+		false, false, true, true,
+		false, true, false, true,
+		true, false, true, false,
+	}, calls)
+}
+
+func TestMockFaultInjection(t *testing.T) {
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+	ctx := context.Background()
+
+	// The mount fails twice, then succeeds.
+	gofsutil.GOFSMockSchedules.Mount = gofsutil.FailTimes(2)
+	assert.EqualError(t, gofsutil.Mount(ctx, "/dev/sdx", "/mnt/sdx", "xfs"), "mount scheduled error on call 1")
+	assert.Error(t, gofsutil.Mount(ctx, "/dev/sdx", "/mnt/sdx", "xfs"))
+	require.NoError(t, gofsutil.Mount(ctx, "/dev/sdx", "/mnt/sdx", "xfs"))
+	assert.Equal(t, 3, gofsutil.GOFSMockCalls.Mount)
+	assert.Len(t, gofsutil.GOFSMockMounts, 1)
+
+	gofsutil.GOFSMockSchedules.GetMounts = gofsutil.FailOnCalls(2)
+	_, err := gofsutil.GetMounts(ctx)
+	require.NoError(t, err)
+	_, err = gofsutil.GetMounts(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, 2, gofsutil.GOFSMockCalls.GetMounts)
+
+	// Latency is injected and honors the context.
+	gofsutil.GOFSMockLatency = gofsutil.MockLatency{Min: 20 * time.Millisecond, Max: 30 * time.Millisecond}
+	start := time.Now()
+	require.NoError(t, gofsutil.Unmount(ctx, "/mnt/sdx"))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, gofsutil.Unmount(cancelled, "/mnt/sdx"), context.Canceled)
+	assert.Equal(t, 2, gofsutil.GOFSMockCalls.Unmount)
+
+	gofsutil.ResetMockFS()
+	assert.Zero(t, gofsutil.GOFSMockCalls)
+	assert.Nil(t, gofsutil.GOFSMockSchedules.Mount)
+}
@@ -0,0 +1,188 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// The mock keeps its node model in the GOFSMock* variables. The helpers in
+// this file keep them consistent with each other: a device is known by its
+// WWN in GOFSMockWWNToDevice, mounting a WWN path mounts the device it maps
+// to, and removing a device forgets it everywhere and leaves its mounts
+// stale, as on a real node.
+
+// ResetMockFS clears the state of the mock file system and the induced
+// errors, e.g. between tests.
+func ResetMockFS() {
+	mockMountsMu.Lock()
+	clearValue(&GOFSMockMounts)
+	mockMountsMu.Unlock()
+	clearValue(&GOFSMockFCHostWWNs)
+	clearValue(&GOFSMockWWNToDevice)
+	clearValue(&GOFSWWNPath)
+	clearValue(&GOFSMockTargetIPLUNToDevice)
+	clearValue(&GOFSRescanCallback)
+	clearValue(&GOFSMockMountInfo)
+	clearValue(&GONVMEDeviceToControllerMap)
+	clearValue(&GONVMEValidDevices)
+	clearValue(&GOFSMockNVMeTargets)
+	clearValue(&GOFSMockDMTables)
+	clearValue(&GOFSMockDMSuspended)
+	clearValue(&GOFSMockCorruptedMounts)
+	clearValue(&GOFSMockFilesystemSize)
+	clearValue(&GOFSMockDiskUsage)
+	clearValue(&GOFSMockProjectQuotas)
+	clearValue(&GOFSMockPartitions)
+	clearValue(&GOFSMockNPIVPorts)
+	clearValue(&GOFSMockFCHosts)
+	clearValue(&GOFSMockISCSISessions)
+	clearValue(&GOFSMockHostNQN)
+	clearValue(&GOFSMockHostID)
+	clearValue(&GOFSMockHostIQN)
+	clearValue(&GOFSMockMpathDevices)
+	clearValue(&GOFSMockReadOnlyDevices)
+	clearValue(&GOFSMockSchedules)
+	clearValue(&GOFSMockCalls)
+	clearValue(&GOFSMockLatency)
+	clearValue(&GOFSMockISCSITargetDevices)
+	clearValue(&GOFSMockFCTargetLUNToDevice)
+	clearValue(&GOFSMockImmutableTargets)
+	clearValue(&GOFSMockLoopDevices)
+	clearValue(&GOFSMockMultipathKinds)
+	clearValue(&GOFSMockDeviceTopologies)
+	clearValue(&GOFSMockNVMePathStates)
+	clearValue(&GOFSMockSCSIHosts)
+	clearValue(&GOFSMockDMDevices)
+	clearValue(&GOFSMockNFSCapabilities)
+	clearValue(&GOFSMockISCSIPortalHosts)
+	clearValue(&GOFSMockJournalReplays)
+	clearValue(&GOFSMockBusyDevices)
+	clearValue(&GOFSMockNVMeNativeMultipath)
+	clearValue(&GOFSMockSysfsAttrs)
+	clearValue(&GOFSMockFSGeometry)
+	clearValue(&GOFSMockMultipathPaths)
+	clearValue(&GOFSMockUnreadableDevices)
+	clearValue(&GOFSMockMountCapacities)
+	clearValue(&GOFSMock)
+}
+
+// clearValue sets *v to its zero value.
+func clearValue[T any](v *T) {
+	var zero T
+	*v = zero
+}
+
+// mockDevice returns the device a mount source refers to. WWN paths, i.e.
+// GOFSWWNPath followed by a WWN, are resolved with GOFSMockWWNToDevice.
+func mockDevice(source string) string {
+	if GOFSWWNPath != "" && strings.HasPrefix(source, GOFSWWNPath) {
+		if dev, ok := GOFSMockWWNToDevice[strings.TrimPrefix(source, GOFSWWNPath)]; ok && dev != "" {
+			return dev
+		}
+	}
+	return getDevice(source)
+}
+
+// mockDeviceWWN returns the WWN of the device, or "" if it has none.
+func mockDeviceWWN(dev string) string {
+	for wwn, d := range GOFSMockWWNToDevice {
+		if d == dev {
+			return wwn
+		}
+	}
+	return ""
+}
+
+// mockMountsMu serializes the accesses to GOFSMockMounts of the mock
+// operations, which may run concurrently, e.g. in the mount watchers of
+// SubscribeMountChanges or in MountMany.
+var mockMountsMu sync.Mutex
+
+// mockMountsSnapshot returns a copy of GOFSMockMounts.
+func mockMountsSnapshot() []Info {
+	mockMountsMu.Lock()
+	defer mockMountsMu.Unlock()
+	return append([]Info{}, GOFSMockMounts...)
+}
+
+// mockAddMount adds the mount to GOFSMockMounts.
+func mockAddMount(info Info) {
+	mockMountsMu.Lock()
+	defer mockMountsMu.Unlock()
+	GOFSMockMounts = append(GOFSMockMounts, info)
+}
+
+// mockDeviceMounts returns the mounts of the device, including the bind
+// mounts of its mount points.
+func mockDeviceMounts(dev string) []Info {
+	mounts := make([]Info, 0)
+	for _, m := range mockMountsSnapshot() {
+		if m.Device == dev || m.Source == dev {
+			mounts = append(mounts, m)
+		}
+	}
+	return mounts
+}
+
+// mockRemoveDevice forgets the device. Its mounts are left in place, but
+// are stale until they are unmounted.
+func mockRemoveDevice(dev string) {
+	for wwn, d := range GOFSMockWWNToDevice {
+		if d == dev {
+			delete(GOFSMockWWNToDevice, wwn)
+		}
+	}
+	for key, d := range GOFSMockTargetIPLUNToDevice {
+		if d == dev {
+			delete(GOFSMockTargetIPLUNToDevice, key)
+		}
+	}
+	delete(GONVMEValidDevices, dev)
+	delete(GOFSMockPartitions, dev)
+	delete(GOFSMockReadOnlyDevices, dev)
+	for _, m := range mockDeviceMounts(dev) {
+		if GOFSMockCorruptedMounts == nil {
+			GOFSMockCorruptedMounts = make(map[string]bool)
+		}
+		GOFSMockCorruptedMounts[m.Path] = true
+	}
+}
+
+// mockDeviceMountInfo returns the mount information of a mounted device,
+// or nil if the device is not mounted.
+func mockDeviceMountInfo(dev string) *DeviceMountInfo {
+	for _, m := range mockMountsSnapshot() {
+		if m.Device != dev {
+			continue
+		}
+		info := &DeviceMountInfo{
+			DeviceNames: []string{filepath.Base(dev)},
+			MountPoint:  m.Path,
+			FsType:      m.Type,
+			MountOpts:   m.Opts,
+			WWN:         mockDeviceWWN(dev),
+		}
+		if strings.HasPrefix(dev, "/dev/mapper/") {
+			info.MPathName = filepath.Base(dev)
+			info.DeviceNames = nil
+			if info.WWN != "" {
+				info.DMUUID = "mpath-3" + info.WWN
+			}
+		}
+		return info
+	}
+	return nil
+}
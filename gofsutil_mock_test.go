@@ -0,0 +1,956 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dell/gofsutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockNodeModel(t *testing.T) {
+	const (
+		wwn    = "60000970000120001263533030313434"
+		device = "/dev/sdx"
+		target = "/var/lib/kubelet/plugins/staging/pv-1"
+		pod    = "/var/lib/kubelet/pods/pod-1/volumes/pv-1/mount"
+	)
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSWWNPath = "/dev/disk/by-id/wwn-0x"
+	gofsutil.GOFSMockWWNToDevice = map[string]string{wwn: device}
+
+	// Mounting the WWN path mounts the device it maps to.
+	require.NoError(t, gofsutil.FormatAndMount(ctx, gofsutil.GOFSWWNPath+wwn, target, "xfs"))
+	require.NoError(t, gofsutil.BindMount(ctx, target, pod))
+	mounts, err := gofsutil.GetDevMounts(ctx, device)
+	require.NoError(t, err)
+	assert.Len(t, mounts, 2)
+	format, err := gofsutil.GetDiskFormat(ctx, gofsutil.GOFSWWNPath+wwn)
+	require.NoError(t, err)
+	assert.Equal(t, "xfs", format)
+
+	info, err := gofsutil.GetMountInfoFromDevice(ctx, device)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sdx"}, info.DeviceNames)
+	assert.Equal(t, target, info.MountPoint)
+	assert.Equal(t, wwn, info.WWN)
+
+	// Removing the device forgets it and leaves its mounts stale.
+	require.NoError(t, gofsutil.RemoveBlockDevice(ctx, device))
+	assert.Empty(t, gofsutil.GOFSMockWWNToDevice)
+	devices, err := gofsutil.GetSysBlockDevicesForVolumeWWN(ctx, wwn)
+	require.NoError(t, err)
+	assert.Empty(t, devices)
+	for _, path := range []string{target, pod} {
+		corrupted, err := gofsutil.IsCorruptedMount(ctx, path)
+		require.NoError(t, err)
+		assert.True(t, corrupted, path)
+	}
+
+	// Unmounting clears the stale mounts.
+	require.NoError(t, gofsutil.Unmount(ctx, pod))
+	require.NoError(t, gofsutil.Unmount(ctx, target))
+	mounts, err = gofsutil.GetMounts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, mounts)
+	corrupted, err := gofsutil.IsCorruptedMount(ctx, target)
+	require.NoError(t, err)
+	assert.False(t, corrupted)
+}
+
+func TestResetMockFS(t *testing.T) {
+	gofsutil.GOFSMockMounts = []gofsutil.Info{{Path: "/mnt"}}
+	gofsutil.GOFSMock.InduceMountError = true
+	gofsutil.GOFSMockFilesystemSize = 1
+	gofsutil.ResetMockFS()
+	assert.Nil(t, gofsutil.GOFSMockMounts)
+	assert.False(t, gofsutil.GOFSMock.InduceMountError)
+	assert.Zero(t, gofsutil.GOFSMockFilesystemSize)
+}
+
+func TestMockUnmountDevice(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockMounts = []gofsutil.Info{
+		{Device: "/dev/sdb", Path: "/var/lib/kubelet/plugins/csi/globalmount"},
+		{Device: "/dev/sdb", Path: "/var/lib/kubelet/pods/a/volumes/csi/v1/mount"},
+		{Device: "/dev/sdc", Path: "/mnt/other"},
+	}
+	results, err := gofsutil.UnmountDevice(ctx, "/dev/sdb")
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "/var/lib/kubelet/pods/a/volumes/csi/v1/mount", results[0].Path)
+	assert.Equal(t, "/var/lib/kubelet/plugins/csi/globalmount", results[1].Path)
+	assert.Equal(t, []gofsutil.Info{{Device: "/dev/sdc", Path: "/mnt/other"}}, gofsutil.GOFSMockMounts)
+
+	gofsutil.GOFSMock.InduceUnmountError = true
+	_, err = gofsutil.UnmountDevice(ctx, "/dev/sdc")
+	assert.Error(t, err)
+
+	gofsutil.GOFSMock.InduceDevMountsError = true
+	_, err = gofsutil.UnmountDevice(ctx, "/dev/sdc")
+	assert.Error(t, err)
+}
+
+func TestMockUnmountTree(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockMounts = []gofsutil.Info{
+		{Device: "/dev/sda", Path: "/var/lib/kubelet/pods/a/volumes/v1"},
+		{Device: "/dev/sdb", Path: "/var/lib/kubelet/pods/a/volumes/v1/sub"},
+		{Device: "/dev/sdc", Path: "/mnt/other"},
+	}
+	results, err := gofsutil.UnmountTree(ctx, "/var/lib/kubelet/pods")
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "/var/lib/kubelet/pods/a/volumes/v1/sub", results[0].Path)
+	assert.Equal(t, "/var/lib/kubelet/pods/a/volumes/v1", results[1].Path)
+	assert.NoError(t, results[0].Err)
+	assert.NoError(t, results[1].Err)
+	assert.Equal(t, []gofsutil.Info{{Device: "/dev/sdc", Path: "/mnt/other"}}, gofsutil.GOFSMockMounts)
+
+	gofsutil.GOFSMock.InduceUnmountError = true
+	results, err = gofsutil.UnmountTree(ctx, "/mnt")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Error(t, results[0].Err)
+
+	gofsutil.GOFSMock.InduceGetMountsError = true
+	_, err = gofsutil.UnmountTree(ctx, "/mnt")
+	assert.Error(t, err)
+}
+
+func TestMockSetTargetImmutable(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockMounts = []gofsutil.Info{{Device: "/dev/sda", Path: "/mnt/mounted"}}
+	require.NoError(t, gofsutil.SetTargetImmutable(ctx, "/mnt/staging", true))
+	assert.True(t, gofsutil.GOFSMockImmutableTargets["/mnt/staging"])
+	assert.Error(t, gofsutil.SetTargetImmutable(ctx, "/mnt/mounted", true))
+	require.NoError(t, gofsutil.SetTargetImmutable(ctx, "/mnt/staging", false))
+	assert.Empty(t, gofsutil.GOFSMockImmutableTargets)
+
+	gofsutil.GOFSMock.InduceSetTargetImmutableError = true
+	assert.Error(t, gofsutil.SetTargetImmutable(ctx, "/mnt/staging", true))
+	gofsutil.GOFSMock.InduceSetTargetPermissionsError = true
+	assert.Error(t, gofsutil.SetTargetPermissions(ctx, "/mnt/mounted", 0, 0, 0o755, false))
+}
+
+func TestMockFormatAndMountFile(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	device, err := gofsutil.FormatAndMountFile(ctx, "/var/lib/images/a.img", "/mnt/a", "ext4")
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/loop0", device)
+	found, err := gofsutil.GetLoopDeviceForFile(ctx, "/var/lib/images/a.img")
+	require.NoError(t, err)
+	assert.Equal(t, device, found)
+	require.Len(t, gofsutil.GOFSMockMounts, 1)
+	assert.Equal(t, "/mnt/a", gofsutil.GOFSMockMounts[0].Path)
+
+	gofsutil.GOFSMock.InduceBindMountError = true
+	_, err = gofsutil.FormatAndMountFile(ctx, "/var/lib/images/b.img", "/mnt/b", "ext4")
+	assert.Error(t, err)
+	assert.NotContains(t, gofsutil.GOFSMockLoopDevices, "/var/lib/images/b.img")
+
+	require.NoError(t, gofsutil.DetachLoopDevice(ctx, device))
+	assert.Empty(t, gofsutil.GOFSMockLoopDevices)
+}
+
+func TestMockMountTmpfs(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	require.NoError(t, gofsutil.MountTmpfs(ctx, "/mnt/scratch", 1<<20, 0o750))
+	require.Len(t, gofsutil.GOFSMockMounts, 1)
+	assert.Equal(t, gofsutil.MountKindTmpfs, gofsutil.GOFSMockMounts[0].Kind)
+	assert.Equal(t, []string{"size=1048576", "mode=0750"}, gofsutil.GOFSMockMounts[0].Opts)
+	assert.Error(t, gofsutil.MountTmpfs(ctx, "/mnt/scratch", -1, 0))
+}
+
+func TestMockGetMultipathKind(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockMultipathKinds = map[string]gofsutil.MultipathKind{
+		"68ccf098001111a2222b3d4444a1b23c": gofsutil.MultipathNVMeNative,
+	}
+	kind, err := gofsutil.GetMultipathKind(ctx, "68ccf098001111a2222b3d4444a1b23c")
+	require.NoError(t, err)
+	assert.Equal(t, gofsutil.MultipathNVMeNative, kind)
+	kind, err = gofsutil.GetMultipathKind(ctx, "60000970000120001263533030313434")
+	require.NoError(t, err)
+	assert.Equal(t, gofsutil.MultipathNone, kind)
+
+	gofsutil.GOFSMock.InduceGetMultipathKindError = true
+	_, err = gofsutil.GetMultipathKind(ctx, "68ccf098001111a2222b3d4444a1b23c")
+	assert.Error(t, err)
+}
+
+func TestMockFsHealthCheck(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	health, err := gofsutil.FsHealthCheck(ctx, "/mnt/a")
+	require.NoError(t, err)
+	assert.False(t, health.Abnormal)
+	assert.True(t, health.Writable)
+
+	gofsutil.GOFSMock.InduceFsHealthCheckAbnormal = true
+	health, err = gofsutil.FsHealthCheck(ctx, "/mnt/a")
+	require.NoError(t, err)
+	assert.True(t, health.Abnormal)
+	assert.Contains(t, health.Message, "/mnt/a")
+}
+
+func TestMockCheckClusterStack(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	require.NoError(t, gofsutil.CheckClusterStack(ctx, "gfs2"))
+	gofsutil.GOFSMock.InduceClusterStackError = true
+	assert.ErrorIs(t, gofsutil.CheckClusterStack(ctx, "gfs2"), gofsutil.ErrClusterStackNotRunning)
+	assert.NoError(t, gofsutil.CheckClusterStack(ctx, "xfs"))
+	assert.ErrorIs(t, gofsutil.Mount(ctx, "/dev/sdb", "/mnt/shared", "ocfs2"), gofsutil.ErrClusterStackNotRunning)
+	assert.Empty(t, gofsutil.GOFSMockMounts)
+}
+
+func TestMockGetDeviceTopology(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockDeviceTopologies = map[string]*gofsutil.DeviceTopology{
+		"/dev/sdb": {
+			Name: "sdb",
+			Path: "/dev/sdb",
+			Kind: gofsutil.DeviceKindDisk,
+			Partitions: []*gofsutil.DeviceTopology{{
+				Name:    "sdb1",
+				Path:    "/dev/sdb1",
+				Kind:    gofsutil.DeviceKindPartition,
+				Holders: []*gofsutil.DeviceTopology{{Name: "dm-1", Path: "/dev/dm-1", Kind: gofsutil.DeviceKindDM}},
+			}},
+			Holders: []*gofsutil.DeviceTopology{{Name: "dm-0", Path: "/dev/dm-0", Kind: gofsutil.DeviceKindMultipath}},
+		},
+	}
+	topo, err := gofsutil.GetDeviceTopology(ctx, "/dev/sdb")
+	require.NoError(t, err)
+	assert.Equal(t, "sdb", topo.Name)
+	holders, err := gofsutil.GetBlockDeviceHolders(ctx, "/dev/sdb")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/dev/dm-0", "/dev/dm-1"}, holders)
+	_, err = gofsutil.GetDeviceTopology(ctx, "/dev/sdc")
+	assert.Error(t, err)
+
+	gofsutil.GOFSMock.InduceDeviceTopologyError = true
+	_, err = gofsutil.GetBlockDeviceHolders(ctx, "/dev/sdb")
+	assert.Error(t, err)
+}
+
+func TestMockGetNVMePathStates(t *testing.T) {
+	const nqn = "nqn.1988-11.com.dell:powerstore:00:a1b2c3d4e5f6"
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	paths, err := gofsutil.GetNVMePathStates(ctx, nqn)
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+
+	gofsutil.GOFSMockNVMePathStates = map[string][]gofsutil.NVMePathState{
+		nqn: {{Path: "nvme0c1n1", ControllerState: "live", ANAState: gofsutil.NVMeANAOptimized}},
+	}
+	paths, err = gofsutil.GetNVMePathStates(ctx, nqn)
+	require.NoError(t, err)
+	assert.True(t, gofsutil.HasOptimizedNVMePath(paths))
+
+	gofsutil.GOFSMock.InduceGetNVMePathStatesError = true
+	_, err = gofsutil.GetNVMePathStates(ctx, nqn)
+	assert.Error(t, err)
+}
+
+func TestMockMountSensitive(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	require.NoError(t, gofsutil.MountSensitive(ctx, "//nas/share", "/mnt/share", "cifs",
+		[]string{"vers=3.0"}, []string{"password=s3cret"}))
+	mounts, err := gofsutil.GetMounts(ctx)
+	require.NoError(t, err)
+	require.Len(t, mounts, 1)
+	assert.Contains(t, mounts[0].Opts, "password=s3cret")
+
+	gofsutil.GOFSMock.InduceMountError = true
+	err = gofsutil.MountSensitive(ctx, "//nas/share", "/mnt/share", "cifs", nil, []string{"password=s3cret"})
+	assert.Error(t, err)
+}
+
+func TestMockMountWithSpec(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	require.NoError(t, gofsutil.MountWithSpec(ctx, gofsutil.MountSpec{
+		Source:   "/dev/sdb",
+		Target:   "/mnt/vol",
+		FsType:   "xfs",
+		Flags:    []gofsutil.MountFlag{gofsutil.MountFlagNoExec},
+		ReadOnly: true,
+	}))
+	mounts, err := gofsutil.GetMounts(ctx)
+	require.NoError(t, err)
+	require.Len(t, mounts, 1)
+	assert.Subset(t, mounts[0].Opts, []string{"ro", "noexec"})
+
+	err = gofsutil.MountWithSpec(ctx, gofsutil.MountSpec{Source: "/dev/sdb"})
+	assert.ErrorIs(t, err, gofsutil.ErrInvalidMountSpec)
+}
+
+func TestMockStageBlockVolume(t *testing.T) {
+	const wwn = "60000970000120001263533030313434"
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockWWNToDevice = map[string]string{wwn: "/dev/dm-3"}
+	gofsutil.GOFSMockMultipathKinds = map[string]gofsutil.MultipathKind{wwn: gofsutil.MultipathDM}
+	req := gofsutil.StageRequest{
+		WWN:              wwn,
+		Target:           "/var/lib/kubelet/staging/vol1",
+		FsType:           "xfs",
+		RequireMultipath: true,
+	}
+	result, err := gofsutil.StageBlockVolume(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/dm-3", result.Device)
+	assert.Equal(t, gofsutil.MultipathDM, result.MultipathKind)
+	assert.False(t, result.AlreadyStaged)
+	require.NotNil(t, result.Mount)
+	assert.Equal(t, req.Target, result.Mount.Path)
+	var steps []string
+	for _, s := range result.Steps {
+		steps = append(steps, s.Name)
+	}
+	assert.Equal(t, []string{
+		gofsutil.StageStepWaitDevice,
+		gofsutil.StageStepSettle,
+		gofsutil.StageStepCheckUnused,
+		gofsutil.StageStepMount,
+		gofsutil.StageStepVerify,
+	}, steps)
+
+	// Staging again finds the mount.
+	result, err = gofsutil.StageBlockVolume(ctx, req)
+	require.NoError(t, err)
+	assert.True(t, result.AlreadyStaged)
+
+	// The device is in use on another target.
+	req.Target = "/var/lib/kubelet/staging/vol2"
+	result, err = gofsutil.StageBlockVolume(ctx, req)
+	var inUse *gofsutil.InUseError
+	require.ErrorAs(t, err, &inUse)
+	assert.Equal(t, gofsutil.StageStepCheckUnused, result.Steps[len(result.Steps)-1].Name)
+
+	// A raw block volume is bind mounted.
+	result, err = gofsutil.StageBlockVolume(ctx, gofsutil.StageRequest{
+		DevicePath: "/dev/sdc",
+		Target:     "/var/lib/kubelet/staging/block1",
+		Block:      true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/sdc", result.Device)
+	require.NotNil(t, result.Mount)
+
+	// The volume never becomes multipathed.
+	gofsutil.GOFSMockMultipathKinds = nil
+	shortCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	req.Target = "/var/lib/kubelet/staging/vol3"
+	_, err = gofsutil.StageBlockVolume(shortCtx, req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = gofsutil.StageBlockVolume(ctx, gofsutil.StageRequest{Target: "/mnt"})
+	assert.Error(t, err)
+}
+
+func TestMockUnstageBlockVolume(t *testing.T) {
+	const wwn = "60000970000120001263533030313434"
+	const target = "/var/lib/kubelet/staging/vol1"
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockWWNToDevice = map[string]string{wwn: "/dev/sdb"}
+	_, err := gofsutil.StageBlockVolume(ctx, gofsutil.StageRequest{WWN: wwn, Target: target, FsType: "ext4"})
+	require.NoError(t, err)
+
+	req := gofsutil.UnstageRequest{WWN: wwn, Targets: []string{target}, Rescan: true, RescanLUN: "3"}
+	result, err := gofsutil.UnstageBlockVolume(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{target}, result.Unmounted)
+	require.NotNil(t, result.Cleanup)
+	assert.Equal(t, []string{"sdb"}, result.Cleanup.Removed)
+	assert.True(t, result.Rescanned)
+	mounts, err := gofsutil.GetMounts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, mounts)
+
+	// Unstaging again finds nothing to do.
+	result, err = gofsutil.UnstageBlockVolume(ctx, req)
+	require.NoError(t, err)
+	assert.Empty(t, result.Unmounted)
+	assert.Empty(t, result.Cleanup.Removed)
+
+	gofsutil.GOFSMock.InduceCleanupDeviceError = true
+	result, err = gofsutil.UnstageBlockVolume(ctx, req)
+	assert.Error(t, err)
+	assert.Equal(t, gofsutil.StageStepCleanup, result.Steps[len(result.Steps)-1].Name)
+	assert.False(t, result.Rescanned)
+
+	_, err = gofsutil.UnstageBlockVolume(ctx, gofsutil.UnstageRequest{})
+	assert.Error(t, err)
+}
+
+func TestMockGetHostSCSIHosts(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockSCSIHosts = []gofsutil.SCSIHost{{Name: "host3", Kind: gofsutil.SCSIHostFC, ProcName: "lpfc"}}
+	hosts, err := gofsutil.GetHostSCSIHosts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, gofsutil.GOFSMockSCSIHosts, hosts)
+
+	gofsutil.GOFSMock.InduceGetSCSIHostsError = true
+	_, err = gofsutil.GetHostSCSIHosts(ctx)
+	assert.Error(t, err)
+}
+
+func TestMockDMNameResolution(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockDMDevices = []gofsutil.DMDevice{{
+		Name:       "mpathb",
+		DevPath:    "/dev/dm-3",
+		MapperPath: "/dev/mapper/mpathb",
+		UUID:       "mpath-360000970000120000549533030354435",
+	}}
+	dev, err := gofsutil.DMNameToDevPath(ctx, "/dev/mapper/mpathb")
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/dm-3", dev.DevPath)
+
+	dev, err = gofsutil.DevPathToDMName(ctx, "dm-3")
+	require.NoError(t, err)
+	assert.Equal(t, "mpathb", dev.Name)
+
+	_, err = gofsutil.DevPathToDMName(ctx, "/dev/dm-4")
+	assert.Error(t, err)
+
+	gofsutil.GOFSMock.InduceDMNameToDevPathError = true
+	_, err = gofsutil.DMNameToDevPath(ctx, "mpathb")
+	assert.Error(t, err)
+	gofsutil.GOFSMock.InduceDevPathToDMNameError = true
+	_, err = gofsutil.DevPathToDMName(ctx, "/dev/dm-3")
+	assert.Error(t, err)
+}
+
+func TestMockGetDevicePathsForMountPoint(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockMounts = []gofsutil.Info{
+		{Device: "/dev/sdc", Path: "/publish/vol-1", Type: "devtmpfs"},
+	}
+	paths, err := gofsutil.GetDevicePathsForMountPoint(ctx, "/publish/vol-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/dev/sdc"}, paths)
+
+	_, err = gofsutil.GetDevicePathsForMountPoint(ctx, "/publish/vol-2")
+	assert.Error(t, err)
+
+	gofsutil.GOFSMock.InduceDevicePathsForMountError = true
+	_, err = gofsutil.GetDevicePathsForMountPoint(ctx, "/publish/vol-1")
+	assert.Error(t, err)
+}
+
+func TestMockGetMountsByDevID(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockMounts = []gofsutil.Info{
+		{Device: "/dev/sdc", Path: "/mnt/a", DevID: gofsutil.DevID{Major: 8, Minor: 32}},
+		{Device: "/dev/sdd", Path: "/mnt/b", DevID: gofsutil.DevID{Major: 8, Minor: 48}},
+	}
+	mounts, err := gofsutil.GetMountsByDevID(ctx, 8, 48)
+	require.NoError(t, err)
+	require.Len(t, mounts, 1)
+	assert.Equal(t, "/mnt/b", mounts[0].Path)
+
+	gofsutil.GOFSMock.InduceGetMountsByDevIDError = true
+	_, err = gofsutil.GetMountsByDevID(ctx, 8, 48)
+	assert.Error(t, err)
+}
+
+func TestMockMountNFS(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	nfsOpts := gofsutil.NFSMountOptions{NConnect: 4, Proto: gofsutil.NFSProtoRDMA}
+	require.NoError(t, gofsutil.MountNFS(ctx, "nfs:/ifs/a", "/mnt/a", nfsOpts, "vers=4.1"))
+	require.Len(t, gofsutil.GOFSMockMounts, 1)
+	assert.Equal(t, []string{"vers=4.1", "nconnect=4", "proto=rdma"}, gofsutil.GOFSMockMounts[0].Opts)
+
+	// Unsupported options are dropped.
+	gofsutil.GOFSMockNFSCapabilities = &gofsutil.NFSCapabilities{KernelVersion: "4.18.0"}
+	require.NoError(t, gofsutil.MountNFS(ctx, "nfs:/ifs/b", "/mnt/b", nfsOpts))
+	assert.Equal(t, []string{"proto=tcp"}, gofsutil.GOFSMockMounts[1].Opts)
+
+	nfsOpts.Strict = true
+	err := gofsutil.MountNFS(ctx, "nfs:/ifs/c", "/mnt/c", nfsOpts)
+	assert.ErrorIs(t, err, gofsutil.ErrNFSOptionNotSupported)
+
+	// A failed mount with nconnect is tried once more without it.
+	gofsutil.GOFSMockNFSCapabilities = nil
+	calls := gofsutil.GOFSMockCalls.Mount
+	gofsutil.GOFSMockSchedules.Mount = gofsutil.FailOnCalls(calls + 1)
+	require.NoError(t, gofsutil.MountNFS(ctx, "nfs:/ifs/d", "/mnt/d", gofsutil.NFSMountOptions{NConnect: 8}))
+	assert.Equal(t, calls+2, gofsutil.GOFSMockCalls.Mount)
+	assert.Empty(t, gofsutil.GOFSMockMounts[len(gofsutil.GOFSMockMounts)-1].Opts)
+
+	gofsutil.GOFSMock.InduceGetNFSCapabilitiesError = true
+	assert.Error(t, gofsutil.MountNFS(ctx, "nfs:/ifs/e", "/mnt/e", nfsOpts))
+	err = gofsutil.MountNFS(ctx, "nfs:/ifs/e", "/mnt/e", gofsutil.NFSMountOptions{Port: -1})
+	assert.ErrorIs(t, err, gofsutil.ErrInvalidNFSOptions)
+}
+
+func TestMockGetISCSIHostsForTargetPortal(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockISCSIPortalHosts = []gofsutil.ISCSIPortalHost{
+		{Host: "host3", Session: "session1", Address: "10.0.0.1", Port: "3260"},
+		{Host: "host4", Session: "session2", Address: "10.0.0.2", Port: "3260"},
+	}
+	hosts, err := gofsutil.GetISCSIHostsForTargetPortal(ctx, "10.0.0.2:3260")
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+	assert.Equal(t, "host4", hosts[0].Host)
+
+	gofsutil.GOFSMock.InduceISCSIPortalHostsError = true
+	_, err = gofsutil.GetISCSIHostsForTargetPortal(ctx, "10.0.0.2")
+	assert.Error(t, err)
+}
+
+func TestMockReplayIncompleteOperations(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockJournalReplays = []gofsutil.JournalReplay{{
+		Entry:  gofsutil.JournalEntry{ID: "a", Op: gofsutil.JournalOpDeviceCleanup, WWN: "60000970"},
+		Action: gofsutil.JournalCompleted,
+	}}
+	replays, err := gofsutil.ReplayIncompleteOperations(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, gofsutil.GOFSMockJournalReplays, replays)
+
+	gofsutil.GOFSMock.InduceReplayOperationsError = true
+	_, err = gofsutil.ReplayIncompleteOperations(ctx)
+	assert.Error(t, err)
+}
+
+func TestMockFormatAndMountForeignSignature(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMock.InduceGetDiskFormatType = gofsutil.DiskFormatLUKS
+	err := gofsutil.FormatAndMount(ctx, "/dev/sdb", "/mnt/a", "ext4")
+	assert.ErrorIs(t, err, gofsutil.ErrForeignSignature)
+	assert.Empty(t, gofsutil.GOFSMockMounts)
+}
+
+func TestMockRemoveBlockDeviceWithOptions(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockBusyDevices = map[string]*gofsutil.DeviceBusyError{
+		"/dev/sdb": {Holders: []string{"dm-0"}},
+	}
+	opts := gofsutil.RemoveBlockDeviceOptions{Flush: true, CheckHolders: true, CheckOpenHandles: true}
+	var busy *gofsutil.DeviceBusyError
+	require.ErrorAs(t, gofsutil.RemoveBlockDeviceWithOptions(ctx, "/dev/sdb", opts), &busy)
+	assert.Equal(t, "/dev/sdb", busy.Device)
+	assert.Equal(t, []string{"dm-0"}, busy.Holders)
+
+	// The holders are ignored when not checked.
+	require.NoError(t, gofsutil.RemoveBlockDeviceWithOptions(ctx, "/dev/sdb", gofsutil.RemoveBlockDeviceOptions{CheckOpenHandles: true}))
+	require.NoError(t, gofsutil.RemoveBlockDeviceWithOptions(ctx, "/dev/sdc", opts))
+	assert.Equal(t, 2, gofsutil.GOFSMockCalls.RemoveBlockDevice)
+
+	gofsutil.GOFSMock.InduceRemoveBlockDeviceError = true
+	assert.Error(t, gofsutil.RemoveBlockDeviceWithOptions(ctx, "/dev/sdc", opts))
+}
+
+func TestMockNativeMultipathSetting(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	native, err := gofsutil.GetNativeMultipathSetting(ctx)
+	require.NoError(t, err)
+	assert.False(t, native)
+	assert.NoError(t, gofsutil.CheckNVMeMultipathMode(ctx, gofsutil.NVMeMultipathDM))
+
+	gofsutil.GOFSMockNVMeNativeMultipath = true
+	assert.NoError(t, gofsutil.CheckNVMeMultipathMode(ctx, gofsutil.NVMeMultipathNative))
+	assert.ErrorIs(t, gofsutil.CheckNVMeMultipathMode(ctx, gofsutil.NVMeMultipathDM), gofsutil.ErrNVMeMultipathConflict)
+
+	gofsutil.GOFSMock.InduceNativeMultipathError = true
+	_, err = gofsutil.GetNativeMultipathSetting(ctx)
+	assert.Error(t, err)
+	assert.Error(t, gofsutil.CheckNVMeMultipathMode(ctx, gofsutil.NVMeMultipathNative))
+}
+
+func TestMockFormatUUID(t *testing.T) {
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	ctx := gofsutil.WithFormatOptions(context.Background(), gofsutil.FormatOptions{UUID: "3f1b2c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d"})
+	require.NoError(t, gofsutil.FormatAndMount(ctx, "/dev/sdb", "/mnt/a", "xfs"))
+	assert.ErrorIs(t, gofsutil.Format(ctx, "/dev/sdc", "/mnt/b", "nfs"), gofsutil.ErrFormatOptionNotSupported)
+
+	ctx = gofsutil.WithFormatOptions(context.Background(), gofsutil.FormatOptions{UUID: "1234"})
+	assert.ErrorIs(t, gofsutil.FormatAndMount(ctx, "/dev/sdc", "/mnt/b", "ext4"), gofsutil.ErrInvalidFormatOptions)
+	assert.Len(t, gofsutil.GOFSMockMounts, 1)
+}
+
+func TestMockSubscribeMountChanges(t *testing.T) {
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := gofsutil.SubscribeMountChanges(ctx)
+	require.NoError(t, err)
+	require.NoError(t, gofsutil.Mount(ctx, "/dev/sdb", "/mnt/a", "ext4"))
+	select {
+	case e := <-events:
+		assert.Equal(t, gofsutil.MountAdded, e.Type)
+		assert.Equal(t, "/mnt/a", e.Info.Path)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no mount event")
+	}
+	cancel()
+	for range events {
+	}
+
+	gofsutil.GOFSMock.InduceSubscribeMountChangesError = true
+	_, err = gofsutil.SubscribeMountChanges(context.Background())
+	assert.Error(t, err)
+}
+
+func TestMockMountsConcurrentAccess(t *testing.T) {
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	// Run with -race: the mock operations read the mount table while
+	// other operations change it.
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			assert.NoError(t, gofsutil.Mount(ctx, "/dev/sdb", "/mnt/a", "ext4"))
+			assert.NoError(t, gofsutil.Unmount(ctx, "/mnt/a"))
+		}
+	}()
+	readers := []func() error{
+		func() error { _, err := gofsutil.GetMounts(ctx); return err },
+		func() error { _, err := gofsutil.GetDevMounts(ctx, "/dev/sdb"); return err },
+		func() error { _, err := gofsutil.IsMountPoint(ctx, "/mnt/a"); return err },
+		func() error { _, err := gofsutil.GetDiskFormat(ctx, "/dev/sdb"); return err },
+		func() error { _, err := gofsutil.GetMountsByDevID(ctx, 8, 16); return err },
+	}
+	for _, read := range readers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				assert.NoError(t, read())
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Empty(t, gofsutil.GOFSMockMounts)
+}
+
+func TestMockSysfsAttr(t *testing.T) {
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	ctx := context.Background()
+	gofsutil.GOFSMockSysfsAttrs = map[string]string{"block/sdb/device/state": "running"}
+	value, err := gofsutil.SysfsReadAttr(ctx, "/sys/block/sdb/device/state")
+	require.NoError(t, err)
+	assert.Equal(t, "running", value)
+	require.NoError(t, gofsutil.SysfsWriteAttr(ctx, "block/sdb/device/state", "offline"))
+	assert.Equal(t, "offline", gofsutil.GOFSMockSysfsAttrs["block/sdb/device/state"])
+
+	assert.ErrorIs(t, gofsutil.SysfsWriteAttr(ctx, "block/sdb/device/delete", "1"), os.ErrNotExist)
+	_, err = gofsutil.SysfsReadAttr(ctx, "../etc/passwd")
+	assert.ErrorIs(t, err, gofsutil.ErrOutsideRoot)
+
+	gofsutil.GOFSMock.InduceSysfsReadAttrError = true
+	gofsutil.GOFSMock.InduceSysfsWriteAttrError = true
+	_, err = gofsutil.SysfsReadAttr(ctx, "block/sdb/device/state")
+	assert.Error(t, err)
+	assert.Error(t, gofsutil.SysfsWriteAttr(ctx, "block/sdb/device/state", "running"))
+}
+
+func TestMockGetDeviceForPublishPath(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	staging := "/var/lib/kubelet/plugins/kubernetes.io/csi/pv/vol-1/globalmount"
+	publish := "/var/lib/kubelet/pods/6f1c/volumes/kubernetes.io~csi/vol-1/mount"
+	gofsutil.GOFSMockWWNToDevice = map[string]string{"60000970000120001263533030313434": "/dev/sdc"}
+	require.NoError(t, gofsutil.Mount(ctx, "/dev/sdc", staging, "ext4"))
+	require.NoError(t, gofsutil.BindMount(ctx, staging, publish))
+
+	d, err := gofsutil.GetDeviceForPublishPath(ctx, publish)
+	require.NoError(t, err)
+	assert.Equal(t, publish, d.PublishPath)
+	assert.Equal(t, staging, d.StagingPath)
+	assert.Equal(t, "/dev/sdc", d.Device)
+	assert.Equal(t, "60000970000120001263533030313434", d.WWN)
+
+	_, err = gofsutil.GetDeviceForPublishPath(ctx, "/var/lib/kubelet/pods/6f1c/volumes/kubernetes.io~csi/vol-2/mount")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	gofsutil.GOFSMock.InduceDeviceForPublishPathError = true
+	_, err = gofsutil.GetDeviceForPublishPath(ctx, publish)
+	assert.Error(t, err)
+}
+
+func TestMockGetFSGeometry(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockFSGeometry = map[string]*gofsutil.FSGeometry{
+		"/mnt/data": {FsType: "xfs", BlockSize: 4096, BlockCount: 262144, LogBlockSize: 4096, LogBlocks: 2560},
+	}
+	g, err := gofsutil.GetFSGeometry(ctx, "/mnt/data")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1<<30), g.Size())
+	assert.Equal(t, int64(2560*4096), g.LogSize())
+
+	_, err = gofsutil.GetFSGeometry(ctx, "/mnt/other")
+	assert.Error(t, err)
+
+	gofsutil.GOFSMock.InduceGetFSGeometryError = true
+	_, err = gofsutil.GetFSGeometry(ctx, "/mnt/data")
+	assert.Error(t, err)
+}
+
+func TestMockShrinkFS(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockFSGeometry = map[string]*gofsutil.FSGeometry{
+		"/dev/sdb": {FsType: "ext4", BlockSize: 4096, BlockCount: 262144},
+		"/dev/sdc": {FsType: "xfs", BlockSize: 4096, BlockCount: 262144},
+	}
+	require.NoError(t, gofsutil.ShrinkFS(ctx, "/dev/sdb", 512<<20))
+	g, err := gofsutil.GetFSGeometry(ctx, "/dev/sdb")
+	require.NoError(t, err)
+	assert.Equal(t, int64(512<<20), g.Size())
+	assert.Error(t, gofsutil.ShrinkFS(ctx, "/dev/sdb", 1<<30))
+	assert.ErrorIs(t, gofsutil.ShrinkFS(ctx, "/dev/sdc", 512<<20), errors.ErrUnsupported)
+
+	gofsutil.GOFSMock.InduceShrinkFSError = true
+	assert.Error(t, gofsutil.ShrinkFS(ctx, "/dev/sdb", 256<<20))
+}
+
+func TestMockMultipathPathCommands(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockMultipathPaths = map[string]*gofsutil.MultipathPathState{
+		"sdb": {Device: "sdb", DMState: "active", CheckerState: "ready"},
+	}
+	r, err := gofsutil.FailPath(ctx, "/dev/sdb")
+	require.NoError(t, err)
+	assert.Equal(t, "failed", r.Path.DMState)
+	assert.Equal(t, "failed", gofsutil.GOFSMockMultipathPaths["sdb"].DMState)
+	r, err = gofsutil.ReinstatePath(ctx, "sdb")
+	require.NoError(t, err)
+	assert.Equal(t, "ready", r.Path.CheckerState)
+	_, err = gofsutil.FailPath(ctx, "sdc")
+	assert.Error(t, err)
+	_, err = gofsutil.MultipathReconfigure(ctx)
+	require.NoError(t, err)
+
+	gofsutil.GOFSMock.InduceMultipathReconfigureError = true
+	gofsutil.GOFSMock.InduceReinstatePathError = true
+	gofsutil.GOFSMock.InduceFailPathError = true
+	_, err = gofsutil.MultipathReconfigure(ctx)
+	assert.Error(t, err)
+	_, err = gofsutil.ReinstatePath(ctx, "sdb")
+	assert.Error(t, err)
+	_, err = gofsutil.FailPath(ctx, "sdb")
+	assert.Error(t, err)
+}
+
+func TestMockVerifyDeviceReadable(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockUnreadableDevices = map[string]bool{"/dev/sdc": true}
+	assert.NoError(t, gofsutil.VerifyDeviceReadable(ctx, "/dev/sdb", 0, 0))
+	err := gofsutil.VerifyDeviceReadable(ctx, "/dev/sdc", 0, 0)
+	assert.ErrorIs(t, err, gofsutil.ErrDeviceUnreadable)
+	var readErr *gofsutil.DeviceReadError
+	require.ErrorAs(t, err, &readErr)
+	assert.Equal(t, int64(gofsutil.DefaultDeviceProbeLength), readErr.Length)
+
+	gofsutil.GOFSMock.InduceVerifyDeviceReadableError = true
+	assert.Error(t, gofsutil.VerifyDeviceReadable(ctx, "/dev/sdb", 0, 0))
+}
+
+func TestMockVerifyMountedCapacity(t *testing.T) {
+	ctx := context.Background()
+	gofsutil.UseMockFS()
+	gofsutil.ResetMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	defer gofsutil.ResetMockFS()
+
+	gofsutil.GOFSMockMounts = []gofsutil.Info{
+		{Device: "/dev/sdb", Path: "/mnt/a"},
+		{Device: "/dev/sdc", Path: "/mnt/b"},
+	}
+	gofsutil.GOFSMockMountCapacities = map[string]int64{"/mnt/a": 1 << 30}
+	assert.NoError(t, gofsutil.VerifyMountedCapacity(ctx, "/mnt/a", 1<<30, 0))
+	assert.ErrorIs(t, gofsutil.VerifyMountedCapacity(ctx, "/mnt/a", 2<<30, 0), gofsutil.ErrCapacityMismatch)
+	// The capacity of FsInfo is used for the other mounts.
+	assert.NoError(t, gofsutil.VerifyMountedCapacity(ctx, "/mnt/b", 2000, 0))
+	assert.Error(t, gofsutil.VerifyMountedCapacity(ctx, "/mnt/c", 1<<30, 0))
+
+	gofsutil.GOFSMock.InduceVerifyMountedCapacityError = true
+	assert.Error(t, gofsutil.VerifyMountedCapacity(ctx, "/mnt/a", 1<<30, 0))
+}
@@ -15,12 +15,16 @@ package gofsutil
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"io"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 )
 
 // ProcMountsFields is fields per line in procMountsPath as per
@@ -34,10 +38,10 @@ const ProcMountsFields = 9
 type Info struct {
 	// Device is the filesystem path of the device to which the filesystem is
 	// mounted.
-	Device string
+	Device string `json:"device" yaml:"device"`
 
 	// Path is the filesystem path to which Device is mounted.
-	Path string
+	Path string `json:"path" yaml:"path"`
 
 	// Source may be set to one of two values:
 	//
@@ -57,23 +61,91 @@ type Info struct {
 	// path bind mounted to Path for native bind mounts, the value of
 	// the Source field can in no way be used to determine *if* a mount
 	// is a bind mount.
-	Source string
+	Source string `json:"source" yaml:"source"`
 
 	// Type is the filesystem type.
-	Type string
+	Type string `json:"type" yaml:"type"`
 
 	// Opts are the mount options (https://linux.die.net/man/8/mount)
 	// used to mount the filesystem.
-	Opts []string
+	Opts []string `json:"opts" yaml:"opts"`
+
+	// Kind is the kind of the mount, derived from its filesystem type
+	// and source.
+	Kind MountKind `json:"kind,omitempty" yaml:"kind,omitempty"`
+
+	// DevID is the device number of the mounted filesystem, zero if it
+	// is not known.
+	DevID DevID `json:"devID,omitempty" yaml:"devID,omitempty"`
+}
+
+// DevID is a device number (dev_t) split into its major and minor
+// numbers, e.g. 253:3.
+type DevID struct {
+	Major uint32 `json:"major" yaml:"major"`
+	Minor uint32 `json:"minor" yaml:"minor"`
+}
+
+// String returns the device number as major:minor.
+func (d DevID) String() string {
+	return fmt.Sprintf("%d:%d", d.Major, d.Minor)
+}
+
+// IsZero returns true if the device number is 0:0.
+func (d DevID) IsZero() bool {
+	return d.Major == 0 && d.Minor == 0
+}
+
+// parseDevID parses a device number of the form major:minor.
+func parseDevID(s string) (DevID, bool) {
+	major, minor, ok := strings.Cut(s, ":")
+	if !ok {
+		return DevID{}, false
+	}
+	ma, err := strconv.ParseUint(major, 10, 32)
+	if err != nil {
+		return DevID{}, false
+	}
+	mi, err := strconv.ParseUint(minor, 10, 32)
+	if err != nil {
+		return DevID{}, false
+	}
+	return DevID{Major: uint32(ma), Minor: uint32(mi)}, true
 }
 
 // DeviceMountInfo describes the filesystem mount information
 // related to the mounted CSI device
 type DeviceMountInfo struct {
-	DeviceNames []string
-	MPathName   string
-	PPathName   string
-	MountPoint  string
+	DeviceNames []string `json:"deviceNames" yaml:"deviceNames"`
+	MPathName   string   `json:"mpathName" yaml:"mpathName"`
+	PPathName   string   `json:"ppathName" yaml:"ppathName"`
+	MountPoint  string   `json:"mountPoint" yaml:"mountPoint"`
+	// FsType is the filesystem type of the mount, if mounted.
+	FsType string `json:"fsType,omitempty" yaml:"fsType,omitempty"`
+	// MountOpts are the mount options of the mount, if mounted.
+	MountOpts []string `json:"mountOpts,omitempty" yaml:"mountOpts,omitempty"`
+	// WWN is the WWN of the volume in canonical form, if known.
+	WWN string `json:"wwn,omitempty" yaml:"wwn,omitempty"`
+	// DMUUID is the device-mapper UUID of the multipath device, e.g.
+	// mpath-3600601..., if any.
+	DMUUID string `json:"dmUUID,omitempty" yaml:"dmUUID,omitempty"`
+}
+
+// RescanReport describes the outcome of a SCSI host rescan.
+type RescanReport struct {
+	// Hosts are the scsi hosts that were rescanned, e.g. host3.
+	Hosts []string
+	// ScanStrings maps each scan file that was written to the strings
+	// written to it in order, e.g. "- 0 1".
+	ScanStrings map[string][]string
+	// NewDevices are the block devices, e.g. sdx, that appeared in
+	// /sys/block while the hosts were rescanned.
+	NewDevices []string
+	// FullRescanSkipped is set if no host is related to the targets and
+	// the rescan of all the hosts was skipped, because it is disabled
+	// with FSOptions.DisableFullRescan or the last one was less than
+	// FSOptions.FullRescanInterval ago.
+	FullRescanSkipped bool
 }
 
 // Entry is a superset of Info and maps to the fields of a mount table
@@ -91,6 +163,9 @@ type DeviceMountInfo struct {
 //	(10) mount source:  filesystem specific information or "none"
 //	(11) super options:  per super block options
 type Entry struct {
+	// DevID is the value of st_dev for files on the filesystem.
+	DevID DevID
+
 	// Root of the mount within the filesystem.
 	Root string
 
@@ -122,7 +197,7 @@ func DefaultEntryScanFunc() EntryScanFunc {
 }
 
 func defaultEntryScanFunc(
-	_ context.Context,
+	ctx context.Context,
 	entry Entry,
 	cache map[string]Entry,
 ) (info Info, valid bool, failed error) {
@@ -133,6 +208,16 @@ func defaultEntryScanFunc(
 	if valid = validFSType || sourceHasSlashPrefix; !valid {
 		return
 	}
+	return allEntryScanFunc(ctx, entry, cache)
+}
+
+// allEntryScanFunc accepts all the mount table entries.
+func allEntryScanFunc(
+	_ context.Context,
+	entry Entry,
+	cache map[string]Entry,
+) (info Info, valid bool, failed error) {
+	valid = true
 
 	// Copy the Entry object's fields to the Info object.
 	info.Device = entry.MountSource
@@ -141,6 +226,7 @@ func defaultEntryScanFunc(
 	info.Path = entry.MountPoint
 	info.Type = entry.FSType
 	info.Source = entry.MountSource
+	info.DevID = entry.DevID
 
 	// If this is the first time a source is encountered in the
 	// output then cache its mountPoint field as the filesystem path
@@ -199,24 +285,78 @@ func ReadProcMountsFrom(
 	_ bool,
 	expectedFields int,
 	scanEntry EntryScanFunc,
+) ([]Info, uint32, error) {
+	return readProcMountsInto(ctx, file, nil, expectedFields, scanEntry)
+}
+
+// maxProcMountsLine is the maximum length of a mount table entry, which
+// can be long for overlay mounts with many lower directories.
+const maxProcMountsLine = 1 << 20
+
+// procMountsScratch holds the buffers used to parse a mount table.
+type procMountsScratch struct {
+	line   []byte
+	fields []string
+}
+
+// procMountsScratchPool reuses the parse buffers across mount table reads,
+// which happen often on busy nodes.
+var procMountsScratchPool = sync.Pool{
+	New: func() any {
+		return &procMountsScratch{line: make([]byte, bufio.MaxScanTokenSize)}
+	},
+}
+
+// appendFields appends the space-separated fields of s to dst, like
+// strings.Fields but without allocating a new slice.
+func appendFields(dst []string, s string) []string {
+	start := -1
+	for i := 0; i < len(s); i++ {
+		if s[i] == ' ' || s[i] == '\t' {
+			if start >= 0 {
+				dst = append(dst, s[start:i])
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		dst = append(dst, s[start:])
+	}
+	return dst
+}
+
+// readProcMountsInto parses a mount table like ReadProcMountsFrom and
+// appends the mounts to infos.
+func readProcMountsInto(
+	ctx context.Context,
+	file io.Reader,
+	infos []Info,
+	expectedFields int,
+	scanEntry EntryScanFunc,
 ) ([]Info, uint32, error) {
 	if scanEntry == nil {
 		scanEntry = defaultEntryScanFunc
 	}
 
+	scratch := procMountsScratchPool.Get().(*procMountsScratch)
+	defer procMountsScratchPool.Put(scratch)
+
 	var (
-		infos []Info
 		hash  = fnv.New32a()
 		fscan = bufio.NewScanner(file)
 		cache = map[string]Entry{}
 	)
+	fscan.Buffer(scratch.line, maxProcMountsLine)
 
 	for fscan.Scan() {
 
 		// Read the next line of text and attempt to parse it into
 		// distinct, space-separated fields.
 		line := fscan.Text()
-		fields := strings.Fields(line)
+		scratch.fields = appendFields(scratch.fields[:0], line)
+		fields := scratch.fields
 
 		// Remove the optional fields that should be ignored.
 		for {
@@ -234,7 +374,9 @@ func ReadProcMountsFrom(
 		}
 
 		// Create a new Entry object from the mount table entry.
+		devID, _ := parseDevID(fields[2])
 		e := Entry{
+			DevID:       devID,
 			Root:        fields[3],
 			MountPoint:  fields[4],
 			MountOpts:   strings.Split(fields[5], ","),
@@ -252,6 +394,12 @@ func ReadProcMountsFrom(
 		if !valid {
 			continue
 		}
+		if i.Kind == "" {
+			i.Kind = entryMountKind(e)
+		}
+		if i.DevID.IsZero() {
+			i.DevID = e.DevID
+		}
 
 		fmt.Fprint(hash, line)
 		infos = append(infos, i)
@@ -260,6 +408,124 @@ func ReadProcMountsFrom(
 	return infos, hash.Sum32(), nil
 }
 
+// MountEntryField selects the fields of a mount table entry that
+// ScanProcMounts fills in.
+type MountEntryField uint
+
+const (
+	// MountEntryRoot selects Entry.Root.
+	MountEntryRoot MountEntryField = 1 << iota
+	// MountEntryMountPoint selects Entry.MountPoint.
+	MountEntryMountPoint
+	// MountEntryMountOpts selects Entry.MountOpts.
+	MountEntryMountOpts
+	// MountEntryFSType selects Entry.FSType.
+	MountEntryFSType
+	// MountEntryMountSource selects Entry.MountSource.
+	MountEntryMountSource
+	// MountEntryDevID selects Entry.DevID.
+	MountEntryDevID
+
+	// MountEntryAllFields selects all the fields of Entry.
+	MountEntryAllFields = MountEntryRoot | MountEntryMountPoint |
+		MountEntryMountOpts | MountEntryFSType | MountEntryMountSource |
+		MountEntryDevID
+)
+
+// MountEntryFunc is called by ScanProcMounts for each mount table entry.
+// Scanning stops when it returns false or an error.
+type MountEntryFunc func(ctx context.Context, entry Entry) (bool, error)
+
+// ScanProcMountsFrom parses a mount table file, typically
+// "/proc/self/mountinfo", and calls fn for each entry with the selected
+// fields filled in, until fn returns false or an error. Unlike
+// ReadProcMountsFrom it keeps no entries in memory, and it reverses the
+// octal escaping of Root and MountPoint.
+func ScanProcMountsFrom(
+	ctx context.Context,
+	file io.Reader,
+	fields MountEntryField,
+	fn MountEntryFunc,
+) error {
+	fscan := bufio.NewScanner(file)
+	for fscan.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := fscan.Text()
+		lineFields := strings.Fields(line)
+
+		// The optional fields end with a separator, after which the
+		// filesystem type, mount source and super options follow.
+		sep := -1
+		for i := 6; i < len(lineFields); i++ {
+			if lineFields[i] == "-" {
+				sep = i
+				break
+			}
+		}
+		if sep < 0 || len(lineFields) < sep+3 {
+			return fmt.Errorf("scanProcMountsFrom: invalid entry: %s", line)
+		}
+
+		var e Entry
+		if fields&MountEntryDevID != 0 {
+			e.DevID, _ = parseDevID(lineFields[2])
+		}
+		if fields&MountEntryRoot != 0 {
+			e.Root = unescapeMountPath(lineFields[3])
+		}
+		if fields&MountEntryMountPoint != 0 {
+			e.MountPoint = unescapeMountPath(lineFields[4])
+		}
+		if fields&MountEntryMountOpts != 0 {
+			e.MountOpts = strings.Split(lineFields[5], ",")
+		}
+		if fields&MountEntryFSType != 0 {
+			e.FSType = lineFields[sep+1]
+		}
+		if fields&MountEntryMountSource != 0 {
+			e.MountSource = lineFields[sep+2]
+		}
+
+		more, err := fn(ctx, e)
+		if err != nil || !more {
+			return err
+		}
+	}
+	return fscan.Err()
+}
+
+// IsCorruptedMountError returns true if err indicates that the mount it was
+// returned for is corrupted or stale, e.g. an NFS mount whose server went
+// away (ESTALE), a FUSE mount whose daemon died (ENOTCONN) or a mount whose
+// block device disappeared (EIO).
+func IsCorruptedMountError(err error) bool {
+	return errors.Is(err, syscall.ENOTCONN) ||
+		errors.Is(err, syscall.ESTALE) ||
+		errors.Is(err, syscall.EIO)
+}
+
+// unescapeMountPath reverses the octal escaping of spaces, tabs, newlines
+// and backslashes the kernel applies to paths in the mount table.
+func unescapeMountPath(p string) string {
+	if !strings.Contains(p, "\\") {
+		return p
+	}
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		if p[i] == '\\' && i+3 < len(p) {
+			if v, err := strconv.ParseUint(p[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(p[i])
+	}
+	return b.String()
+}
+
 // MakeMountArgs makes the arguments to the mount(8) command.
 //
 // The argument list returned is built as follows:
@@ -289,3 +555,24 @@ func MakeMountArgs(
 
 	return args
 }
+
+// getMountsByDevID returns the mounts of the filesystem with the given
+// device number.
+func (fs *FS) getMountsByDevID(ctx context.Context, major, minor uint32) ([]Info, error) {
+	mounts, err := fs.getMounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mountsWithDevID(mounts, DevID{Major: major, Minor: minor}), nil
+}
+
+// mountsWithDevID returns the mounts in mounts with the device number id.
+func mountsWithDevID(mounts []Info, id DevID) []Info {
+	matches := make([]Info, 0)
+	for _, m := range mounts {
+		if m.DevID == id {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
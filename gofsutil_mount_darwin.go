@@ -13,21 +13,16 @@
 package gofsutil
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"os/exec"
-	"regexp"
-	"strings"
-)
+	"os"
 
-var (
-	bindRemountOpts = []string{}
-	mountRX         = regexp.MustCompile(`^(.+) on (.+) \((.+)\)$`)
+	"golang.org/x/sys/unix"
 )
 
+var bindRemountOpts = []string{}
+
 // getDiskFormat uses 'lsblk' to see if the given disk is unformated
 func (fs *FS) getDiskFormat(ctx context.Context, disk string) (string, error) {
 	mps, err := fs.getMounts(ctx)
@@ -60,54 +55,108 @@ func (fs *FS) format(
 	return ErrNotImplemented
 }
 
-// getMounts returns a slice of all the mounted filesystems
+// mountFlagOpts maps the flags of a mounted filesystem to the mount
+// options reported by mount(8).
+var mountFlagOpts = []struct {
+	flag uint32
+	opt  string
+}{
+	{unix.MNT_NOSUID, "nosuid"},
+	{unix.MNT_NODEV, "nodev"},
+	{unix.MNT_NOEXEC, "noexec"},
+	{unix.MNT_SYNCHRONOUS, "sync"},
+	{unix.MNT_ASYNC, "async"},
+	{unix.MNT_NOATIME, "noatime"},
+	{unix.MNT_UNION, "union"},
+	{unix.MNT_LOCAL, "local"},
+	{unix.MNT_QUOTA, "quota"},
+	{unix.MNT_ROOTFS, "rootfs"},
+	{unix.MNT_DONTBROWSE, "nobrowse"},
+	{unix.MNT_AUTOMOUNTED, "automounted"},
+	{unix.MNT_JOURNALED, "journaled"},
+	{unix.MNT_SNAPSHOT, "snapshot"},
+}
+
+// mountFlagsToOpts returns the mount options of a filesystem mounted with
+// flags. The first option is either ro or rw.
+func mountFlagsToOpts(flags uint32) []string {
+	opts := []string{"rw"}
+	if flags&unix.MNT_RDONLY != 0 {
+		opts[0] = "ro"
+	}
+	for _, f := range mountFlagOpts {
+		if flags&f.flag != 0 {
+			opts = append(opts, f.opt)
+		}
+	}
+	return opts
+}
+
+// getMounts returns a slice of all the mounted filesystems, as reported by
+// getfsstat(2). The entries are processed by fs.ScanEntry like the entries
+// of the Linux mount table.
 func (fs *FS) getMounts(ctx context.Context) ([]Info, error) {
-	out, err := exec.Command("mount").CombinedOutput()
-	if err != nil {
-		return nil, err
+	var stats []unix.Statfs_t
+	for {
+		n, err := unix.Getfsstat(nil, unix.MNT_NOWAIT)
+		if err != nil {
+			return nil, err
+		}
+		// Leave room for filesystems mounted in the meantime.
+		stats = make([]unix.Statfs_t, n+4)
+		m, err := unix.Getfsstat(stats, unix.MNT_NOWAIT)
+		if err != nil {
+			return nil, err
+		}
+		if m < len(stats) {
+			stats = stats[:m]
+			break
+		}
 	}
 
+	scanEntry := fs.ScanEntry
+	if scanEntry == nil {
+		scanEntry = defaultEntryScanFunc
+	}
 	var mountInfos []Info
-	scan := bufio.NewScanner(bytes.NewReader(out))
-
-	for scan.Scan() {
-		m := mountRX.FindStringSubmatch(scan.Text())
-		if len(m) != 4 {
-			continue
-		}
-		device := m[1]
-		if !strings.HasPrefix(device, "/") {
-			continue
+	cache := make(map[string]Entry)
+	for i := range stats {
+		entry := Entry{
+			Root:        "/",
+			MountPoint:  unix.ByteSliceToString(stats[i].Mntonname[:]),
+			FSType:      unix.ByteSliceToString(stats[i].Fstypename[:]),
+			MountSource: unix.ByteSliceToString(stats[i].Mntfromname[:]),
+			MountOpts:   mountFlagsToOpts(stats[i].Flags),
 		}
-		var (
-			path    = m[2]
-			source  = device
-			options = strings.Split(m[3], ",")
-		)
-		if len(options) == 0 {
-			return nil, fmt.Errorf(
-				"getMounts: invalid mount options: %s", device)
+		info, valid, err := scanEntry(ctx, entry, cache)
+		if err != nil {
+			return nil, err
 		}
-		for i, v := range options {
-			options[i] = strings.TrimSpace(v)
+		if !valid {
+			continue
 		}
-		fsType := options[0]
-		if len(options) > 1 {
-			options = options[1:]
-		} else {
-			options = nil
+		if info.Kind == "" {
+			info.Kind = entryMountKind(entry)
 		}
-		mountInfos = append(mountInfos, Info{
-			Device: device,
-			Path:   path,
-			Source: source,
-			Type:   fsType,
-			Opts:   options,
-		})
+		mountInfos = append(mountInfos, info)
 	}
 	return mountInfos, nil
 }
 
+// mountTableHasPath returns true if a mount is mounted at path
+func (fs *FS) mountTableHasPath(ctx context.Context, path string) (bool, error) {
+	mps, err := fs.getMounts(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, i := range mps {
+		if i.Path == path {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // bindMount performs a bind mount
 func (fs *FS) bindMount(
 	ctx context.Context,
@@ -123,3 +172,189 @@ func (fs *FS) readProcMounts(ctx context.Context,
 ) ([]Info, uint32, error) {
 	return nil, 0, errors.New("not implemented")
 }
+
+// scanProcMounts is not implemented for darwin
+func (fs *FS) scanProcMounts(ctx context.Context, fields MountEntryField, fn MountEntryFunc) error {
+	return ErrNotImplemented
+}
+
+// getMountsByKind returns the mounted filesystems of the given kinds. Only
+// filesystems with a device path are listed.
+func (fs *FS) getMountsByKind(ctx context.Context, kinds ...MountKind) ([]Info, error) {
+	mounts, err := fs.getMounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filterMountsByKind(mounts, kinds...), nil
+}
+
+// waitForDeviceToSettle is not implemented for darwin
+func (fs *FS) waitForDeviceToSettle(ctx context.Context, device string) error {
+	return ErrNotImplemented
+}
+
+// setProjectQuota is not implemented for darwin
+func (fs *FS) setProjectQuota(ctx context.Context, path string, projID uint32, limitBytes int64) error {
+	return ErrNotImplemented
+}
+
+// getProjectQuota is not implemented for darwin
+func (fs *FS) getProjectQuota(ctx context.Context, path string, projID uint32) (*ProjectQuota, error) {
+	return nil, ErrNotImplemented
+}
+
+// getPartitions is not implemented for darwin
+func (fs *FS) getPartitions(ctx context.Context, device string) (*PartitionTable, error) {
+	return nil, ErrNotImplemented
+}
+
+// createPartition is not implemented for darwin
+func (fs *FS) createPartition(ctx context.Context, device string, spec PartitionSpec) (*Partition, error) {
+	return nil, ErrNotImplemented
+}
+
+// deletePartition is not implemented for darwin
+func (fs *FS) deletePartition(ctx context.Context, device string, number int) error {
+	return ErrNotImplemented
+}
+
+// growPartition is not implemented for darwin
+func (fs *FS) growPartition(ctx context.Context, device string, number int) error {
+	return ErrNotImplemented
+}
+
+// getMountsInto returns the mounted filesystems in infos.
+func (fs *FS) getMountsInto(ctx context.Context, infos []Info) ([]Info, error) {
+	mounts, err := fs.getMounts(ctx)
+	return append(infos[:0], mounts...), err
+}
+
+// ensureDeviceUnused is not implemented for darwin
+func (fs *FS) ensureDeviceUnused(ctx context.Context, device string) error {
+	return ErrNotImplemented
+}
+
+// smbMount is not implemented for darwin
+func (fs *FS) smbMount(ctx context.Context, source, target string, creds SMBCredentials, opts ...string) error {
+	return ErrNotImplemented
+}
+
+// lazyUnmount is not implemented for darwin
+func (fs *FS) lazyUnmount(ctx context.Context, target string) error {
+	return ErrNotImplemented
+}
+
+// blockDevSetRO is not implemented for darwin
+func (fs *FS) blockDevSetRO(ctx context.Context, device string, readOnly bool) error {
+	return ErrNotImplemented
+}
+
+// blockDevGetRO is not implemented for darwin
+func (fs *FS) blockDevGetRO(ctx context.Context, device string) (bool, error) {
+	return false, ErrNotImplemented
+}
+
+// cleanupDeviceForWWN is not implemented for darwin
+func (fs *FS) cleanupDeviceForWWN(ctx context.Context, wwn string) (*DeviceCleanupReport, error) {
+	return nil, ErrNotImplemented
+}
+
+// isPMEMDevice is not implemented for darwin
+func (fs *FS) isPMEMDevice(ctx context.Context, device string) (bool, error) {
+	return false, ErrNotImplemented
+}
+
+// mountDAX is not implemented for darwin
+func (fs *FS) mountDAX(ctx context.Context, source, target, fsType string, opts ...string) error {
+	return ErrNotImplemented
+}
+
+// setTargetPermissions is not implemented for darwin
+func (fs *FS) setTargetPermissions(ctx context.Context, target string, uid, gid int, mode os.FileMode, recursive bool) error {
+	return ErrNotImplemented
+}
+
+// setTargetImmutable is not implemented for darwin
+func (fs *FS) setTargetImmutable(ctx context.Context, target string, immutable bool) error {
+	return ErrNotImplemented
+}
+
+// getLoopDeviceForFile is not implemented for darwin
+func (fs *FS) getLoopDeviceForFile(ctx context.Context, file string) (string, error) {
+	return "", ErrNotImplemented
+}
+
+// attachLoopDevice is not implemented for darwin
+func (fs *FS) attachLoopDevice(ctx context.Context, file string, readOnly bool) (string, bool, error) {
+	return "", false, ErrNotImplemented
+}
+
+// detachLoopDevice is not implemented for darwin
+func (fs *FS) detachLoopDevice(ctx context.Context, device string) error {
+	return ErrNotImplemented
+}
+
+// formatAndMountFile is not implemented for darwin
+func (fs *FS) formatAndMountFile(ctx context.Context, file, target, fsType string, opts ...string) (string, error) {
+	return "", ErrNotImplemented
+}
+
+// mountTmpfs is not implemented for darwin
+func (fs *FS) mountTmpfs(ctx context.Context, target string, sizeBytes int64, mode os.FileMode, opts ...string) error {
+	return ErrNotImplemented
+}
+
+// fsHealthCheck is not implemented for darwin
+func (fs *FS) fsHealthCheck(ctx context.Context, mountpoint string) (*FsHealth, error) {
+	return nil, ErrNotImplemented
+}
+
+// getDeviceTopology is not implemented for darwin
+func (fs *FS) getDeviceTopology(ctx context.Context, device string) (*DeviceTopology, error) {
+	return nil, ErrNotImplemented
+}
+
+// getBlockDeviceHolders is not implemented for darwin
+func (fs *FS) getBlockDeviceHolders(ctx context.Context, device string) ([]string, error) {
+	return nil, ErrNotImplemented
+}
+
+// getDevicePathsForMountPoint is not implemented for darwin
+func (fs *FS) getDevicePathsForMountPoint(ctx context.Context, target string) ([]string, error) {
+	return nil, ErrNotImplemented
+}
+
+// removeBlockDeviceWithOptions is not implemented for darwin.
+func (fs *FS) removeBlockDeviceWithOptions(_ context.Context, _ string, _ RemoveBlockDeviceOptions) error {
+	return ErrNotImplemented
+}
+
+// subscribeMountChanges is not implemented for darwin.
+func (fs *FS) subscribeMountChanges(_ context.Context) (<-chan MountEvent, error) {
+	return nil, ErrNotImplemented
+}
+
+// getDeviceForPublishPath is not implemented for darwin.
+func (fs *FS) getDeviceForPublishPath(_ context.Context, _ string) (*PublishPathDevice, error) {
+	return nil, ErrNotImplemented
+}
+
+// getFSGeometry is not implemented for darwin.
+func (fs *FS) getFSGeometry(_ context.Context, _ string) (*FSGeometry, error) {
+	return nil, ErrNotImplemented
+}
+
+// shrinkFS is not implemented for darwin.
+func (fs *FS) shrinkFS(_ context.Context, _ string, _ int64) error {
+	return ErrNotImplemented
+}
+
+// verifyDeviceReadable is not implemented for darwin.
+func (fs *FS) verifyDeviceReadable(_ context.Context, _ string, _, _ int64) error {
+	return ErrNotImplemented
+}
+
+// deviceWWN is not implemented for darwin.
+func (fs *FS) deviceWWN(_ string) (string, error) {
+	return "", ErrNotImplemented
+}
@@ -15,19 +15,25 @@ package gofsutil
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
 )
 
 const (
 	procMountsPath = "/proc/self/mountinfo"
+	procSwapsPath  = "/proc/swaps"
 	// procMountsRetries is number of times to retry for a consistent
 	// read of procMountsPath.
 	procMountsRetries = 30
@@ -36,6 +42,15 @@ const (
 
 var bindRemountOpts = []string{"remount"}
 
+// mountInfoPath returns the location of the mountinfo file of the mount
+// namespace the commands run in.
+func (fs *FS) mountInfoPath() string {
+	if fs.HostMountNamespace {
+		return fs.procPath(fmt.Sprintf("/proc/%d/mountinfo", fs.hostNamespacePID()))
+	}
+	return fs.procPath(procMountsPath)
+}
+
 // getDiskFormat uses 'lsblk' to see if the given disk is unformatted
 func (fs *FS) getDiskFormat(_ context.Context, disk string) (string, error) {
 	path := filepath.Clean(disk)
@@ -43,6 +58,13 @@ func (fs *FS) getDiskFormat(_ context.Context, disk string) (string, error) {
 		return "", err
 	}
 
+	if fs.DiskFormatCacheTTL > 0 {
+		if format, ok := fs.cachedDiskFormat(disk); ok {
+			log.WithField("disk", disk).Debugf("using cached disk format %q", format)
+			return format, nil
+		}
+	}
+
 	args := []string{"-n", "-o", "FSTYPE", disk}
 
 	f := log.Fields{
@@ -51,7 +73,7 @@ func (fs *FS) getDiskFormat(_ context.Context, disk string) (string, error) {
 	log.WithFields(f).WithField("args", args).Info(
 		"checking if disk is formatted using lsblk")
 	/* #nosec G204 */
-	buf, err := exec.Command("lsblk", args...).CombinedOutput()
+	buf, err := fs.command("lsblk", args...).CombinedOutput()
 	out := string(buf)
 	log.WithField("output", out).Debug("lsblk output")
 
@@ -65,8 +87,19 @@ func (fs *FS) getDiskFormat(_ context.Context, disk string) (string, error) {
 	// "\n". Beware of "\n\n", that's a device with one empty partition.
 	out = strings.TrimSuffix(out, "\n") // Avoid last empty line
 	lines := strings.Split(out, "\n")
+	if lines[0] == "" {
+		// lsblk relies on the udev database, which is missing e.g. in
+		// containers, so look for the LUKS and LVM signatures directly.
+		lines[0] = deviceSignature(disk)
+	}
 	if lines[0] != "" {
-		// The device is formatted
+		// The device is formatted. Unformatted results are never cached
+		// so that a device is never formatted based on stale information.
+		if fs.DiskFormatCacheTTL > 0 {
+			if dev, err := deviceNumber(disk); err == nil {
+				fs.formatCache.put(dev, lines[0], fs.DiskFormatCacheTTL)
+			}
+		}
 		return lines[0], nil
 	}
 
@@ -80,6 +113,51 @@ func (fs *FS) getDiskFormat(_ context.Context, disk string) (string, error) {
 	return "unknown data, probably partitions", nil
 }
 
+// deviceSignature returns the LUKS or LVM signature found on disk, if
+// any. The device is only read, and errors are ignored.
+func deviceSignature(disk string) string {
+	f, err := os.Open(filepath.Clean(disk))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	signature, err := probeSignature(f)
+	if err != nil {
+		log.WithField("disk", disk).WithError(err).Debug("failed to probe device signature")
+	}
+	return signature
+}
+
+// deviceNumber returns the device number (major:minor) of the device disk.
+// An error is returned if disk is not a block device, e.g. an image file,
+// whose device number is 0 like that of any other file.
+func deviceNumber(disk string) (uint64, error) {
+	var st unix.Stat_t
+	if err := unix.Stat(disk, &st); err != nil {
+		return 0, err
+	}
+	if st.Mode&unix.S_IFMT != unix.S_IFBLK {
+		return 0, fmt.Errorf("%s is not a block device", disk)
+	}
+	return st.Rdev, nil
+}
+
+// cachedDiskFormat returns the cached format of disk.
+func (fs *FS) cachedDiskFormat(disk string) (string, bool) {
+	dev, err := deviceNumber(disk)
+	if err != nil {
+		return "", false
+	}
+	return fs.formatCache.get(dev)
+}
+
+// invalidateDiskFormat forgets the cached format of disk.
+func (fs *FS) invalidateDiskFormat(disk string) {
+	if dev, err := deviceNumber(disk); err == nil {
+		fs.formatCache.invalidate(dev)
+	}
+}
+
 // RequestID is for logging the CSI or other type of Request ID
 const RequestID = "RequestID"
 
@@ -94,21 +172,31 @@ func (fs *FS) formatAndMount(
 		return err
 	}
 
+	// A shared disk must not be formatted because the mount failed for
+	// lack of a cluster stack.
+	if err := fs.checkClusterStack(ctx, fsType, opts...); err != nil {
+		return err
+	}
+
 	reqID := ctx.Value(ContextKey(RequestID))
 	noDiscard := ctx.Value(ContextKey(NoDiscard))
 
 	// retrive and remove fsFormatOption from opts if it is passed in
-	var fsFormatOptionString string
-	var fsFormatOption []string
-	if len(opts) > 0 {
-		fsFormatOptionString = opts[len(opts)-1]
-		if strings.HasPrefix(fsFormatOptionString, "fsFormatOption:") {
-			fsFormatOptionString = strings.TrimPrefix(fsFormatOptionString, "fsFormatOption:")
-			fsFormatOption = strings.Split(fsFormatOptionString, " ")
-			opts = opts[0 : len(opts)-1]
-		}
+	opts, fsFormatOption := splitFormatOption(opts)
+
+	// Invalid format options are reported before anything is done,
+	// rather than after a format was attempted.
+	if err := checkFormatOptions(ctx, fsType); err != nil {
+		return err
 	}
 
+	if fs.EnableQuotaOnMount {
+		quotaFsType := fsType
+		if len(quotaFsType) == 0 {
+			quotaFsType = "ext4"
+		}
+		opts = append(opts, ProjectQuotaMountOptions(quotaFsType)...)
+	}
 	opts = append(opts, "defaults")
 	f := log.Fields{
 		"reqID":   reqID,
@@ -118,13 +206,17 @@ func (fs *FS) formatAndMount(
 		"options": opts,
 	}
 
-	// Try to mount the disk
-	log.WithFields(f).Info("attempting to mount disk")
-	mountErr := fs.mount(ctx, source, target, fsType, opts...)
-	if mountErr == nil {
-		return nil
+	// Try to mount the disk. A dry run cannot learn from a failed mount
+	// that the disk is unformatted, so it checks the format first.
+	mountErr := errors.New("dry run: disk is unformatted")
+	if !fs.DryRun || !fs.diskUnformatted(ctx, source) {
+		log.WithFields(f).Info("attempting to mount disk")
+		mountErr = fs.mount(ctx, source, target, fsType, opts...)
+		if mountErr == nil {
+			return nil
+		}
+		log.WithField("mountErr", mountErr.Error()).Info("Mount attempt failed")
 	}
-	log.WithField("mountErr", mountErr.Error()).Info("Mount attempt failed")
 
 	// Mount failed. This indicates either that the disk is unformatted or
 	// it contains an unexpected filesystem.
@@ -140,6 +232,10 @@ func (fs *FS) formatAndMount(
 		"existingFormat": existingFormat,
 	}
 	log.WithFields(f).Info("getDiskFormat returned after initial mount failed")
+	if isForeignSignature(existingFormat) {
+		log.WithFields(f).Error("disk holds a foreign signature, not formatting or mounting it")
+		return &ForeignSignatureError{Device: source, Signature: existingFormat}
+	}
 	if existingFormat == "" {
 		log.WithFields(f).Info("disk is unformatted")
 		// Disk is unformatted so format it.
@@ -149,8 +245,13 @@ func (fs *FS) formatAndMount(
 			fsType = "ext4"
 		}
 
-		// if no fs format option is provided
-		if len(fsFormatOption) == 0 {
+		if isClusterFsType(fsType) {
+			args, err = clusterMkfsArgs(fsType, source, fsFormatOption, noDiscard == NoDiscard)
+			if err != nil {
+				return err
+			}
+		} else if len(fsFormatOption) == 0 {
+			// if no fs format option is provided
 			if fsType == "ext4" || fsType == "ext3" {
 				args = []string{"-F", source}
 				if noDiscard == NoDiscard {
@@ -167,6 +268,10 @@ func (fs *FS) formatAndMount(
 			if fsType == "xfs" {
 				args = append(args, "-m", "crc=0")
 			}
+
+			if fsType == "ext4" && fs.EnableQuotaOnMount {
+				args = append(args[:len(args)-1], "-O", "quota,project", source)
+			}
 		} else {
 			// user provides format option
 			if noDiscard == NoDiscard {
@@ -188,18 +293,48 @@ func (fs *FS) formatAndMount(
 
 		log.Printf("mkfs args: %v", args)
 
-		mkfsCmd := fmt.Sprintf("mkfs.%s", fsType)
-		err := exec.Command(mkfsCmd, args...).Run() // #nosec G204
-		if err != nil {
-			log.WithFields(f).WithError(err).Error(
+		if fs.CheckDeviceUnused {
+			if err := fs.ensureDeviceUnused(ctx, source); err != nil {
+				log.WithFields(f).WithError(err).Error("not formatting disk")
+				return err
+			}
+		}
+		intent := JournalEntry{
+			Op:      JournalOpFormatAndMount,
+			Step:    JournalStepFormatting,
+			Device:  source,
+			Target:  target,
+			FsType:  fsType,
+			Options: opts,
+		}
+		if fs.JournalDir != "" {
+			var err error
+			if intent.WWN, err = fs.deviceWWN(source); err != nil {
+				log.WithFields(f).WithError(err).Warn("failed to identify device, the format cannot be replayed")
+			}
+		}
+		entry := fs.journalBegin(intent)
+		defer fs.journalEnd(entry)
+		formatErr := fs.runMkfs(ctx, source, fsType, args)
+		if formatErr != nil {
+			log.WithFields(f).WithError(formatErr).Error(
 				"format of disk failed")
+			if fs.AbortOnFormatError {
+				return formatErr
+			}
 		} else {
 			log.WithFields(f).Info("disk successfully formatted")
+			fs.journalStep(entry, JournalStepFormatted)
 		}
 
 		// a format of the disk has been attempted, so try mounting it again
 		log.WithFields(f).Info("re-attempting disk mount")
-		return fs.mount(ctx, source, target, fsType, opts...)
+		err := fs.mount(ctx, source, target, fsType, opts...)
+		if err != nil && formatErr != nil {
+			formatErr.MountErr = err
+			return formatErr
+		}
+		return err
 	}
 
 	// Disk is already formatted and failed to mount
@@ -215,6 +350,25 @@ func (fs *FS) formatAndMount(
 		fsType, existingFormat, mountErr)
 }
 
+// diskUnformatted returns true if getDiskFormat finds no format on disk.
+func (fs *FS) diskUnformatted(ctx context.Context, disk string) bool {
+	format, err := fs.getDiskFormat(ctx, disk)
+	return err == nil && format == ""
+}
+
+// splitFormatOption removes the mkfs options, passed as the last option
+// prefixed with fsFormatOption:, from opts and returns them separately.
+func splitFormatOption(opts []string) ([]string, []string) {
+	if len(opts) == 0 {
+		return opts, nil
+	}
+	last, ok := strings.CutPrefix(opts[len(opts)-1], "fsFormatOption:")
+	if !ok {
+		return opts, nil
+	}
+	return opts[:len(opts)-1], strings.Fields(last)
+}
+
 // format uses unix utils to format and mount the given disk
 func (fs *FS) format(
 	ctx context.Context,
@@ -229,6 +383,8 @@ func (fs *FS) format(
 	reqID := ctx.Value(ContextKey("RequestID"))
 	noDiscard := ctx.Value(ContextKey(NoDiscard))
 
+	// The mkfs options are only used for cluster filesystems.
+	opts, fsFormatOption := splitFormatOption(opts)
 	opts = append(opts, "defaults")
 	f := log.Fields{
 		"reqID":   reqID,
@@ -258,15 +414,27 @@ func (fs *FS) format(
 		args = []string{"-K", source}
 	}
 
+	if isClusterFsType(fsType) {
+		if err := fs.checkClusterStack(ctx, fsType, opts...); err != nil {
+			return err
+		}
+		if args, err = clusterMkfsArgs(fsType, source, fsFormatOption, noDiscard == NoDiscard); err != nil {
+			return err
+		}
+	}
+
 	f["fsType"] = fsType
 	log.WithFields(f).Info(
 		"disk appears unformatted, attempting format")
 
-	mkfsCmd := fmt.Sprintf("mkfs.%s", fsType)
-	log.Printf("formatting with command: %s %v", mkfsCmd, args)
-	/* #nosec G204 */
-	err = exec.Command(mkfsCmd, args...).Run()
-	if err != nil {
+	log.Printf("formatting with command: mkfs.%s %v", fsType, args)
+	if fs.CheckDeviceUnused {
+		if err := fs.ensureDeviceUnused(ctx, source); err != nil {
+			log.WithFields(f).WithError(err).Error("not formatting disk")
+			return err
+		}
+	}
+	if err := fs.runMkfs(ctx, source, fsType, args); err != nil {
 		log.WithFields(f).WithError(err).Error(
 			"format of disk failed")
 		return err
@@ -274,24 +442,86 @@ func (fs *FS) format(
 	return nil
 }
 
+// runMkfs runs mkfs for fsType with args to format source, applying the
+// FormatOptions of ctx. A failure is returned as a FormatError holding the
+// standard error of mkfs.
+func (fs *FS) runMkfs(ctx context.Context, source, fsType string, args []string) *FormatError {
+	opts := formatOptionsFromContext(ctx)
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = fs.FormatTimeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	mkfsCmd := fmt.Sprintf("mkfs.%s", fsType)
+	optArgs, err := opts.mkfsArgs(fsType)
+	if err != nil {
+		return &FormatError{Device: source, Command: mkfsCmd, Args: args, ExitCode: -1, Err: err}
+	}
+	if len(optArgs) > 0 {
+		// The options go before the device, which xfs options may follow.
+		i := slices.Index(args, source)
+		if i < 0 {
+			i = len(args)
+		}
+		args = slices.Concat(args[:i], optArgs, args[i:])
+	}
+	var stderr bytes.Buffer
+	cmd := fs.commandContext(ctx, mkfsCmd, args...) // #nosec G204
+	cmd.Stderr = &stderr
+	if opts.Output != nil {
+		mu := &sync.Mutex{}
+		stdoutLines := &lineWriter{mu: mu, fn: opts.Output}
+		stderrLines := &lineWriter{mu: mu, fn: opts.Output}
+		defer stdoutLines.Flush()
+		defer stderrLines.Flush()
+		cmd.Stdout = stdoutLines
+		cmd.Stderr = io.MultiWriter(&stderr, stderrLines)
+	}
+	err = cmd.Run()
+	fs.invalidateDiskFormat(source)
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		err = fmt.Errorf("%w: %v", ctx.Err(), err)
+	}
+	formatErr := &FormatError{
+		Device:   source,
+		Command:  mkfsCmd,
+		Args:     args,
+		ExitCode: -1,
+		Stderr:   strings.TrimSpace(stderr.String()),
+		Err:      err,
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		formatErr.ExitCode = exitErr.ExitCode()
+	}
+	return formatErr
+}
+
 // bindMount performs a bind mount
 func (fs *FS) bindMount(
 	ctx context.Context,
 	source, target string,
 	opts ...string,
 ) error {
-	err := fs.doMount(ctx, "mount", source, target, "", "bind")
+	err := fs.doMount(ctx, fs.mountBinary(), source, target, "", "bind")
 	if err != nil {
 		return err
 	}
-	return fs.doMount(ctx, "mount", source, target, "", opts...)
+	return fs.doMount(ctx, fs.mountBinary(), source, target, "", opts...)
 }
 
 // isLsblkNew returns true if lsblk version is greater than 2.3 and false otherwise
 func (fs *FS) isLsblkNew() (bool, error) {
 	lsblkNew := false
-	checkVersCmd := "lsblk -V"
-	bufcheck, errcheck := exec.Command("bash", "-c", checkVersCmd).Output()
+	bufcheck, errcheck := fs.command("lsblk", "-V").Output()
 	if errcheck != nil {
 		return lsblkNew, errcheck
 	}
@@ -300,7 +530,7 @@ func (fs *FS) isLsblkNew() (bool, error) {
 	match := versionRegx.FindStringSubmatch(outputcheck)
 	subMatchMap := make(map[string]string)
 	for i, name := range versionRegx.SubexpNames() {
-		if i != 0 {
+		if i != 0 && match != nil {
 			subMatchMap[name] = match[i]
 		}
 	}
@@ -313,6 +543,9 @@ func (fs *FS) isLsblkNew() (bool, error) {
 	return lsblkNew, nil
 }
 
+// getMpathNameFromDevice returns the name of the multipath device, e.g.
+// mpatha, holding the device, e.g. sdc, found in the holders of the device
+// in sysfs. lsblk is only used if the device is not in sysfs.
 func (fs *FS) getMpathNameFromDevice(
 	_ context.Context, device string,
 ) (string, error) {
@@ -321,27 +554,63 @@ func (fs *FS) getMpathNameFromDevice(
 		return "", err
 	}
 
-	var cmd string
+	if name, ok := fs.mpathHolderName(filepath.Base(path)); ok {
+		return name, nil
+	}
+	log.WithField("device", device).Debug("device not found in sysfs, using lsblk")
+
 	lsblkNew, err := fs.isLsblkNew()
 	if err != nil {
 		return "", err
 	}
+	args := []string{"-P"}
 	if lsblkNew {
-		cmd = "lsblk -Px MODE | awk '/" + device + "/{c=2}c&&c--' | grep TYPE=\\\"mpath\\\""
-	} else {
-		cmd = "lsblk -P | awk '/" + device + "/{c=2}c&&c--' | grep TYPE=\\\"mpath\\\""
+		args = []string{"-Px", "MODE"}
 	}
-	fmt.Println(cmd)
+	buf, _ := fs.command("lsblk", args...).Output()
+	return lsblkMpathName(string(buf), device), nil
+}
 
-	buf, _ := exec.Command("bash", "-c", cmd).Output() // #nosec G204
-	output := string(buf)
-	mpathDeviceRegx := regexp.MustCompile(`NAME="\S+"`)
-	mpath := mpathDeviceRegx.FindString(output)
-	if mpath != "" {
-		return strings.Split(mpath, "\"")[1], nil
+// mpathHolderName returns the name of the multipath device that holds the
+// block device name, empty if there is none. It returns false if the
+// device is not in sysfs.
+func (fs *FS) mpathHolderName(name string) (string, bool) {
+	classBlockDir := fs.sysPath("/sys/class/block")
+	holders, err := os.ReadDir(filepath.Join(classBlockDir, name, "holders"))
+	if err != nil {
+		return "", false
+	}
+	for _, holder := range holders {
+		dmDir := filepath.Join(classBlockDir, holder.Name(), "dm")
+		if strings.HasPrefix(readSysfsAttr(filepath.Join(dmDir, "uuid")), "mpath-") {
+			return readSysfsAttr(filepath.Join(dmDir, "name")), true
+		}
 	}
+	return "", true
+}
 
-	return "", nil
+// lsblkNameRegx matches the NAME column of lsblk -P output.
+var lsblkNameRegx = regexp.MustCompile(`(?:^|\s)NAME="([^"]*)"`)
+
+// lsblkMpathName returns the name of the first mpath device in the lsblk
+// -P output that is listed on the line of device or the line after it,
+// where lsblk lists the holder of a device.
+func lsblkMpathName(output, device string) string {
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		if !strings.Contains(line, device) {
+			continue
+		}
+		for _, l := range lines[i:min(i+2, len(lines))] {
+			if !strings.Contains(l, `TYPE="mpath"`) {
+				continue
+			}
+			if m := lsblkNameRegx.FindStringSubmatch(l); m != nil {
+				return m[1]
+			}
+		}
+	}
+	return ""
 }
 
 func (fs *FS) getNativeDevicesFromPpath(
@@ -355,7 +624,7 @@ func (fs *FS) getNativeDevicesFromPpath(
 	cmd := fmt.Sprintf("%s/%s", "/noderoot/sbin", ppinqtool)
 	log.Debug("pp_inq cmd:", cmd)
 	args := []string{"-wwn", "-dev", deviceName}
-	out, err := exec.Command(cmd, args...).CombinedOutput() // #nosec G204
+	out, err := fs.command(cmd, args...).CombinedOutput() // #nosec G204
 	if err != nil {
 		log.Errorf("Error powermt display %s: %v", deviceName, err)
 		return devices, err
@@ -395,54 +664,41 @@ func (fs *FS) getMountInfoFromDevice(
 		return nil, err
 	}
 
-	var cmd string
-	var output string
 	lsblkNew, err := fs.isLsblkNew()
 	if err != nil {
 		return nil, err
 	}
-	//check if devID has powerpath devices
-	/* #nosec G204 */
-	checkCmd := "lsblk --pairs --output NAME,MAJ:MIN,RM,SIZE,RO,TYPE,MOUNTPOINT | awk '/emcpower.+" + devID + "/ {print $0}'"
-	log.Debugf("ppath checkcommand values is %s", checkCmd)
-	/* #nosec G204 */
-	buf, err := exec.Command("bash", "-c", checkCmd).Output()
+	lsblkArgs := []string{"--pairs", "--output", "NAME,MAJ:MIN,RM,SIZE,RO,TYPE,MOUNTPOINT"}
+	buf, err := fs.command("lsblk", lsblkArgs...).Output()
 	if err != nil {
 		return nil, err
 	}
-	output = string(buf)
+	lsblkLines := strings.Split(string(buf), "\n")
+	// devID is matched literally.
+	quotedDevID := regexp.QuoteMeta(devID)
+	devIDRegx := regexp.MustCompile(quotedDevID)
+
+	//check if devID has powerpath devices
+	output := matchingLines(lsblkLines, regexp.MustCompile(`emcpower.+`+quotedDevID))
 	if output == "" {
 		// output is nil, powerpath device not found, continuing for multipath or single device
 		log.Info("powerpath command output is nil, continuing for multipath or single device")
-		/* #nosec G204 */
-		checkCmd = "lsblk --pairs --output NAME,MAJ:MIN,RM,SIZE,RO,TYPE,MOUNTPOINT | awk '/mpath.+" + devID + "/ {print $0}'"
-		log.Debugf("mpath checkcommand values is %s", checkCmd)
-
-		/* #nosec G204 */
-		buf, err = exec.Command("bash", "-c", checkCmd).Output()
-		if err != nil {
-			return nil, err
-		}
-		output = string(buf)
+		output = matchingLines(lsblkLines, regexp.MustCompile(`mpath.+`+quotedDevID))
 		log.Debugf("multipath exec command output is : %+v", output)
 		if output != "" {
 			if lsblkNew {
-				cmd = "lsblk --pairs --sort MODE --output NAME,MAJ:MIN,RM,SIZE,RO,TYPE,MOUNTPOINT | awk '/" + devID + "/{if (a && a !~ /" + devID + "/) print a; print} {a=$0}'"
-			} else {
-				cmd = "lsblk --pairs --output NAME,MAJ:MIN,RM,SIZE,RO,TYPE,MOUNTPOINT | awk '/" + devID + "/{if (a && a !~ /" + devID + "/) print a; print} {a=$0}'"
+				/* #nosec G204 */
+				buf, err = fs.command("lsblk", append([]string{"--pairs", "--sort", "MODE"}, lsblkArgs[1:]...)...).Output()
+				if err != nil {
+					return nil, err
+				}
+				lsblkLines = strings.Split(string(buf), "\n")
 			}
+			output = matchingLinesWithHolder(lsblkLines, devIDRegx)
 		} else {
 			// multipath device not found, continue as single device
-			/* #nosec G204 */
-			cmd = "lsblk --pairs --output NAME,MAJ:MIN,RM,SIZE,RO,TYPE,MOUNTPOINT | awk '/" + devID + "/ {print $0}'"
-		}
-		log.Debugf("command value is %s", cmd)
-		/* #nosec G204 */
-		buf, err = exec.Command("bash", "-c", cmd).Output()
-		if err != nil {
-			return nil, err
+			output = matchingLines(lsblkLines, devIDRegx)
 		}
-		output = string(buf)
 		log.Debugf("command output is : %+v", output)
 	}
 	if output == "" {
@@ -450,6 +706,7 @@ func (fs *FS) getMountInfoFromDevice(
 	}
 	sdDeviceRegx := regexp.MustCompile(`NAME=\"sd\S+\"`)
 	nvmeDeviceRegx := regexp.MustCompile(`NAME=\"nvme\S+\"`)
+	sciniDeviceRegx := regexp.MustCompile(`NAME=\"scini[a-z]+\"`)
 	mpathDeviceRegx := regexp.MustCompile(`NAME=\"mpath\S+\"`)
 	ppathDeviceRegx := regexp.MustCompile(`NAME=\"emcpower\S+\"`)
 	mountRegx := regexp.MustCompile(`MOUNTPOINT=\"\S+\"`)
@@ -458,6 +715,7 @@ func (fs *FS) getMountInfoFromDevice(
 	mountPoint := mountRegx.FindString(output)
 	devices := sdDeviceRegx.FindAllString(output, 99999)
 	nvmeDevices := nvmeDeviceRegx.FindAllString(output, 99999)
+	sciniDevices := sciniDeviceRegx.FindAllString(output, 99999)
 	mpath := mpathDeviceRegx.FindString(output)
 	ppath := ppathDeviceRegx.FindString(output)
 	mountInfo := new(DeviceMountInfo)
@@ -468,6 +726,9 @@ func (fs *FS) getMountInfoFromDevice(
 	for _, device := range nvmeDevices {
 		mountInfo.DeviceNames = append(mountInfo.DeviceNames, strings.Split(device, "\"")[1])
 	}
+	for _, device := range sciniDevices {
+		mountInfo.DeviceNames = append(mountInfo.DeviceNames, strings.Split(device, "\"")[1])
+	}
 	if ppath != "" {
 		log.Infof("found ppath: %s", ppath)
 		mountInfo.PPathName = strings.Split(ppath, "\"")[1]
@@ -492,9 +753,98 @@ func (fs *FS) getMountInfoFromDevice(
 			}
 		}
 	}
+	fs.fillDeviceMountInfo(ctx, mountInfo)
 	return mountInfo, nil
 }
 
+// fillDeviceMountInfo sets the filesystem type, mount options, WWN and
+// device-mapper UUID of the device mount info from the mount table and
+// sysfs. Missing information is left empty.
+func (fs *FS) fillDeviceMountInfo(ctx context.Context, mountInfo *DeviceMountInfo) {
+	if mountInfo.MountPoint != "" {
+		fields := MountEntryMountPoint | MountEntryFSType | MountEntryMountOpts
+		err := fs.scanProcMounts(ctx, fields, func(_ context.Context, e Entry) (bool, error) {
+			if e.MountPoint != mountInfo.MountPoint {
+				return true, nil
+			}
+			mountInfo.FsType = e.FSType
+			mountInfo.MountOpts = e.MountOpts
+			return false, nil
+		})
+		if err != nil {
+			log.WithError(err).Debug("failed to read the mount table")
+		}
+	}
+
+	if mountInfo.MPathName != "" {
+		mountInfo.DMUUID = fs.dmUUID(mountInfo.MPathName)
+		if w, ok := wwnFromDMUUID(mountInfo.DMUUID); ok {
+			mountInfo.WWN = string(w)
+			return
+		}
+	}
+	for _, device := range mountInfo.DeviceNames {
+		blockDir := filepath.Join(fs.sysBlockDir(), device)
+		wwid := readSysfsAttr(filepath.Join(blockDir, "device", "wwid"))
+		if wwid == "" {
+			// NVMe namespaces have the attribute on the block device.
+			wwid = readSysfsAttr(filepath.Join(blockDir, "wwid"))
+		}
+		if w, ok := wwnFromWWID(wwid); ok {
+			mountInfo.WWN = string(w)
+			return
+		}
+	}
+}
+
+// dmUUID returns the UUID of the device-mapper device with the given
+// name, or an empty string if there is none.
+func (fs *FS) dmUUID(name string) string {
+	entries, err := os.ReadDir(fs.sysBlockDir())
+	if err != nil {
+		return ""
+	}
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), "dm-") {
+			continue
+		}
+		dmDir := filepath.Join(fs.sysBlockDir(), e.Name(), "dm")
+		if readSysfsAttr(filepath.Join(dmDir, "name")) == name {
+			return readSysfsAttr(filepath.Join(dmDir, "uuid"))
+		}
+	}
+	return ""
+}
+
+// matchingLines returns the lines that match re, each followed by a newline.
+func matchingLines(lines []string, re *regexp.Regexp) string {
+	var b strings.Builder
+	for _, line := range lines {
+		if line != "" && re.MatchString(line) {
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// matchingLinesWithHolder returns the lines that match re, each preceded
+// by the line before it unless that line matches as well, e.g. the
+// multipath device listed before the devices it holds.
+func matchingLinesWithHolder(lines []string, re *regexp.Regexp) string {
+	var b strings.Builder
+	prev := ""
+	for _, line := range lines {
+		if line != "" && re.MatchString(line) {
+			if prev != "" && !re.MatchString(prev) {
+				b.WriteString(prev + "\n")
+			}
+			b.WriteString(line + "\n")
+		}
+		prev = line
+	}
+	return b.String()
+}
+
 // FindFSType fetches the filesystem type on mountpoint
 func (fs *FS) findFSType(
 	_ context.Context, mountpoint string,
@@ -504,9 +854,8 @@ func (fs *FS) findFSType(
 		return "", fmt.Errorf("Failed to validate path: %s error %v", mountpoint, err)
 	}
 
-	cmd := "findmnt -n \"" + path + "\" | awk '{print $3}'"
 	/* #nosec G204 */
-	buf, err := exec.Command("bash", "-c", cmd).Output()
+	buf, err := fs.command("findmnt", "-n", "-o", "FSTYPE", path).Output()
 	if err != nil {
 		return "", fmt.Errorf("Failed to find mount information for (%s) error (%v)", mountpoint, err)
 	}
@@ -522,7 +871,7 @@ func (fs *FS) resizeMultipath(_ context.Context, deviceName string) error {
 
 	args := []string{"resize", "map", path}
 	/* #nosec G204 */
-	out, err := exec.Command("multipathd", args...).CombinedOutput()
+	out, err := fs.command("multipathd", args...).CombinedOutput()
 	log.WithField("output", string(out)).Debug("Multipath resize output")
 	if err != nil {
 		return fmt.Errorf("Failed to resize multipath mount device on (%s) error (%v)", deviceName, err)
@@ -541,11 +890,83 @@ func (fs *FS) resizeFS(
 	ctx context.Context, mountpoint,
 	devicePath, ppathDevice, mpathDevice, fsType string,
 ) error {
+	mountpoint, devicePath, err := fs.resizeTargets(ctx, mountpoint, devicePath, ppathDevice, mpathDevice)
+	if err != nil {
+		return err
+	}
+	return fs.expandFS(mountpoint, devicePath, fsType)
+}
+
+// resizeFSWithOptions expands the filesystem like resizeFS if it does not
+// already fill the device and returns the size of the filesystem in bytes.
+func (fs *FS) resizeFSWithOptions(
+	ctx context.Context, mountpoint,
+	devicePath, ppathDevice, mpathDevice, fsType string,
+	opts ResizeFSOptions,
+) (int64, error) {
+	mountpoint, devicePath, err := fs.resizeTargets(ctx, mountpoint, devicePath, ppathDevice, mpathDevice)
+	if err != nil {
+		return 0, err
+	}
+	fsSize, err := fs.filesystemSize(ctx, mountpoint, devicePath, fsType)
+	if err != nil {
+		return 0, err
+	}
+	devSize, err := fs.blockDeviceSize(devicePath)
+	if err != nil {
+		return 0, err
+	}
+	f := log.Fields{
+		"device":         devicePath,
+		"filesystemSize": fsSize,
+		"deviceSize":     devSize,
+	}
+	if devSize-fsSize <= opts.tolerance() {
+		log.WithFields(f).Info("filesystem already fills the device")
+		return fsSize, ErrNoResizeNeeded
+	}
+	if opts.DryRun {
+		log.WithFields(f).Info("filesystem needs to be resized")
+		return fsSize, nil
+	}
+	if err := fs.expandFS(mountpoint, devicePath, fsType); err != nil {
+		return 0, err
+	}
+	newSize, err := fs.filesystemSize(ctx, mountpoint, devicePath, fsType)
+	if err != nil {
+		return 0, err
+	}
+	if newSize <= fsSize {
+		return newSize, fmt.Errorf("filesystem on %s did not grow: size %d, device size %d", devicePath, newSize, devSize)
+	}
+	log.WithFields(f).WithField("newFilesystemSize", newSize).Info("filesystem resized")
+	return newSize, nil
+}
+
+// resizeTargets returns the mount point and device to resize, taking a
+// powerpath or multipath device into account. An NVMe namespace with
+// native multipathing has no multipath map to resize; the controllers of
+// its subsystem are rescanned instead so that the namespace gets its new
+// size.
+func (fs *FS) resizeTargets(
+	ctx context.Context, mountpoint,
+	devicePath, ppathDevice, mpathDevice string,
+) (string, string, error) {
+	if ppathDevice == "" && mpathDevice == "" {
+		name := filepath.Base(devicePath)
+		if fs.nvmeNativeMultipath(name) {
+			log.Infof("Rescanning NVMe multipath namespace (%s) before resize", devicePath)
+			if err := fs.nvmeRescan(ctx, filepath.Join(fs.sysBlockDir(), name)); err != nil {
+				return "", "", err
+			}
+		}
+	}
+
 	if ppathDevice != "" {
 		devicePath = "/dev/" + ppathDevice
-		err := reReadPartitionTable(ctx, devicePath)
+		err := fs.reReadPartitionTable(ctx, devicePath)
 		if err != nil {
-			return err
+			return "", "", err
 		}
 	}
 
@@ -553,6 +974,11 @@ func (fs *FS) resizeFS(
 		devicePath = "/dev/mapper/" + mpathDevice
 		mountpoint = devicePath
 	}
+	return mountpoint, devicePath, nil
+}
+
+// expandFS grows the filesystem of type fsType to the size of its device.
+func (fs *FS) expandFS(mountpoint, devicePath, fsType string) error {
 	var err error
 	switch fsType {
 	case "ext4":
@@ -567,14 +993,64 @@ func (fs *FS) resizeFS(
 	return err
 }
 
+// filesystemSize returns the size in bytes of the filesystem of type
+// fsType, as reported by dumpe2fs or xfs_info.
+func (fs *FS) filesystemSize(ctx context.Context, mountpoint, devicePath, fsType string) (int64, error) {
+	var name, path string
+	var parse func(string) (int64, error)
+	switch fsType {
+	case "ext3", "ext4":
+		name, path, parse = "dumpe2fs", devicePath, parseDumpe2fsSize
+	case "xfs":
+		name, path, parse = "xfs_info", mountpoint, parseXFSInfoSize
+	default:
+		return 0, fmt.Errorf("Filesystem not supported to resize")
+	}
+	path = filepath.Clean(path)
+	if err := validatePath(path); err != nil {
+		return 0, fmt.Errorf("Failed to validate path: %s error %v", path, err)
+	}
+	args := []string{path}
+	if name == "dumpe2fs" {
+		args = []string{"-h", path}
+	}
+	/* #nosec G204 */
+	out, err := fs.commandContext(ctx, name, args...).Output()
+	if err != nil {
+		return 0, fmt.Errorf("%s failed for (%s) error (%v)", name, path, err)
+	}
+	return parse(string(out))
+}
+
+// blockDeviceSize returns the size in bytes of the block device at
+// devicePath from sysfs.
+func (fs *FS) blockDeviceSize(devicePath string) (int64, error) {
+	dev, err := filepath.EvalSymlinks(devicePath)
+	if err != nil {
+		return 0, err
+	}
+	sizePath := fs.sysPath(filepath.Join("/sys/class/block", filepath.Base(dev), "size"))
+	buf, err := os.ReadFile(filepath.Clean(sizePath))
+	if err != nil {
+		return 0, err
+	}
+	sectors, err := strconv.ParseInt(strings.TrimSpace(string(buf)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %v", sizePath, err)
+	}
+	// sysfs reports the size in 512 byte sectors regardless of the
+	// logical block size of the device.
+	return sectors * 512, nil
+}
+
 // reReadPartitionTable re-read the partition table of the pseudo device.
-func reReadPartitionTable(_ context.Context, devicePath string) error {
+func (fs *FS) reReadPartitionTable(_ context.Context, devicePath string) error {
 	path := filepath.Clean(devicePath)
 	if err := validatePath(path); err != nil {
 		return fmt.Errorf("Failed to validate path: %s error %v", devicePath, err)
 	}
 	args := []string{path}
-	_, err := exec.Command("partprobe", args...).CombinedOutput() // #nosec G204
+	_, err := fs.command("partprobe", args...).CombinedOutput() // #nosec G204
 	if err != nil {
 		log.Errorf("Failed to execute partprobe on %s: %s", devicePath, err.Error())
 		return err
@@ -588,7 +1064,7 @@ func (fs *FS) expandExtFs(devicePath string) error {
 		return fmt.Errorf("Failed to validate path: %s error %v", devicePath, err)
 	}
 	/* #nosec G204 */
-	out, err := exec.Command("resize2fs", path).CombinedOutput()
+	out, err := fs.command("resize2fs", path).CombinedOutput()
 	log.WithField("output", string(out)).Debug("Ext fs resize output")
 	if err != nil {
 		return fmt.Errorf("Ext fs: Failed to resize device (%s) error (%v)", devicePath, err)
@@ -604,7 +1080,7 @@ func (fs *FS) expandXfs(volumePath string) error {
 	}
 	args := []string{"-d", path}
 	/* #nosec G204 */
-	out, err := exec.Command("xfs_growfs", args...).CombinedOutput()
+	out, err := fs.command("xfs_growfs", args...).CombinedOutput()
 	log.WithField("output", string(out)).Debug("XFS resize output")
 	if err != nil {
 		return fmt.Errorf("Xfs: Failed to resize device (%s) error (%v)", volumePath, err)
@@ -614,59 +1090,150 @@ func (fs *FS) expandXfs(volumePath string) error {
 }
 
 // DeviceRescan rescan the device for size alterations
-func (fs *FS) deviceRescan(_ context.Context,
+func (fs *FS) deviceRescan(ctx context.Context,
 	devicePath string,
 ) error {
 	path := filepath.Clean(devicePath)
 	if err := validatePath(path); err != nil {
 		return err
 	}
-	device := path + "/device/rescan"
-	args := []string{"-c", "echo 1 > " + device}
-	log.Infof("Executing rescan command on device (%s)", devicePath)
-	/* #nosec G204 */
-	buf, err := exec.Command("bash", args...).CombinedOutput()
-	out := string(buf)
-	log.WithField("output", out).Debug("Rescan output")
+	if isNVMeNamespace(path) {
+		log.Infof("Executing NVMe rescan on device (%s)", devicePath)
+		if err := fs.nvmeRescan(ctx, path); err != nil {
+			log.Errorf("Failed to rescan device with error (%s)", err.Error())
+			return err
+		}
+		log.Infof("Successful rescan on device (%s)", devicePath)
+		return nil
+	}
+	device, err := fs.sysfsAttrPath(ctx, path+"/device/rescan")
 	if err != nil {
 		log.Errorf("Failed to rescan device with error (%s)", err.Error())
 		return err
 	}
+	log.Infof("Executing rescan command on device (%s)", devicePath)
+	if err := fs.writeSysfsFile(device, "1"); err != nil {
+		log.Errorf("Failed to rescan device with error (%s)", err.Error())
+		return err
+	}
 	log.Infof("Successful rescan on device (%s)", devicePath)
 	return nil
 }
 
-func (fs *FS) consistentRead(filename string, retry int) ([]byte, error) {
-	oldContent, err := os.ReadFile(filepath.Clean(filename))
+// mountsBufferPool holds the buffers the mount table is read into.
+var mountsBufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// readFileInto replaces the content of buf with the content of filename.
+func readFileInto(filename string, buf *bytes.Buffer) error {
+	buf.Reset()
+	file, err := os.Open(filepath.Clean(filename))
 	if err != nil {
+		return err
+	}
+	defer file.Close() // #nosec G307
+	_, err = buf.ReadFrom(file)
+	return err
+}
+
+// consistentRead reads filename until two consecutive reads return the
+// same content. The returned buffer comes from mountsBufferPool, to which
+// the caller returns it.
+func (fs *FS) consistentRead(filename string, retry int) (*bytes.Buffer, error) {
+	oldContent := mountsBufferPool.Get().(*bytes.Buffer)
+	newContent := mountsBufferPool.Get().(*bytes.Buffer)
+	if err := readFileInto(filename, oldContent); err != nil {
+		mountsBufferPool.Put(oldContent)
+		mountsBufferPool.Put(newContent)
 		return nil, err
 	}
 	for i := 0; i < retry; i++ {
-		newContent, err := os.ReadFile(filepath.Clean(filename))
-		if err != nil {
+		if err := readFileInto(filename, newContent); err != nil {
+			mountsBufferPool.Put(oldContent)
+			mountsBufferPool.Put(newContent)
 			return nil, err
 		}
-		if bytes.Compare(oldContent, newContent) == 0 {
+		if bytes.Equal(oldContent.Bytes(), newContent.Bytes()) {
+			mountsBufferPool.Put(oldContent)
 			return newContent, nil
 		}
 		// Files are different, continue reading
-		oldContent = newContent
+		oldContent, newContent = newContent, oldContent
 	}
+	mountsBufferPool.Put(oldContent)
+	mountsBufferPool.Put(newContent)
 	return nil, fmt.Errorf("could not get consistent content of %s after %d attempts", filename, retry)
 }
 
 // getMounts returns a slice of all the mounted filesystems
 func (fs *FS) getMounts(ctx context.Context) ([]Info, error) {
-	infos := make([]Info, 0)
-	content, err := fs.consistentRead(procMountsPath, procMountsRetries)
+	return fs.getMountsInto(ctx, make([]Info, 0))
+}
+
+// getMountsInto returns the mounted filesystems in infos, reusing its
+// capacity.
+func (fs *FS) getMountsInto(ctx context.Context, infos []Info) ([]Info, error) {
+	infos = infos[:0]
+	buffer, err := fs.consistentRead(fs.mountInfoPath(), procMountsRetries)
 	if err != nil {
 		return infos, err
 	}
-	buffer := bytes.NewBuffer(content)
-	infos, _, err = ReadProcMountsFrom(ctx, buffer, true, ProcMountsFields, fs.ScanEntry)
+	defer mountsBufferPool.Put(buffer)
+	infos, _, err = readProcMountsInto(ctx, buffer, infos, ProcMountsFields, fs.ScanEntry)
 	return infos, err
 }
 
+// getMountsByKind returns all the entries of the mount table, and the
+// active swaps, of the given kinds.
+func (fs *FS) getMountsByKind(ctx context.Context, kinds ...MountKind) ([]Info, error) {
+	buffer, err := fs.consistentRead(fs.mountInfoPath(), procMountsRetries)
+	if err != nil {
+		return nil, err
+	}
+	defer mountsBufferPool.Put(buffer)
+	infos, _, err := ReadProcMountsFrom(ctx, buffer, true, ProcMountsFields, allEntryScanFunc)
+	if err != nil {
+		return nil, err
+	}
+	mounts := filterMountsByKind(infos, kinds...)
+	if !wantsMountKind(MountKindSwap, kinds) {
+		return mounts, nil
+	}
+	file, err := os.Open(filepath.Clean(fs.procPath(procSwapsPath)))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() // #nosec G307
+	swaps, err := parseProcSwaps(file)
+	if err != nil {
+		return nil, err
+	}
+	return append(mounts, swaps...), nil
+}
+
+// mountTableHasPath returns true if the mount table contains an entry
+// whose mount point is path. The search stops at the first match.
+func (fs *FS) mountTableHasPath(ctx context.Context, path string) (bool, error) {
+	found := false
+	err := fs.scanProcMounts(ctx, MountEntryMountPoint, func(_ context.Context, e Entry) (bool, error) {
+		found = e.MountPoint == path
+		return !found, nil
+	})
+	return found, err
+}
+
+// scanProcMounts calls fn for each entry of the mount table until fn
+// returns false or an error.
+func (fs *FS) scanProcMounts(ctx context.Context, fields MountEntryField, fn MountEntryFunc) error {
+	file, err := os.Open(filepath.Clean(fs.mountInfoPath()))
+	if err != nil {
+		return err
+	}
+	defer file.Close() // #nosec G307
+	return ScanProcMountsFrom(ctx, file, fields, fn)
+}
+
 // readProcMounts reads procMountsInfo and produce a hash
 // of the contents and a list of the mounts as Info objects.
 func (fs *FS) readProcMounts(
@@ -683,3 +1250,40 @@ func (fs *FS) readProcMounts(
 	}()
 	return ReadProcMountsFrom(ctx, file, !info, ProcMountsFields, fs.ScanEntry)
 }
+
+// lazyUnmount detaches the mount at target even if it is busy, leaving
+// it to be cleaned up once it is no longer in use.
+func (fs *FS) lazyUnmount(_ context.Context, target string) error {
+	f := log.Fields{
+		"path": target,
+		"cmd":  "umount -l",
+	}
+	log.WithFields(f).Info("lazy unmount syscall")
+	path := filepath.Clean(target)
+	if err := validatePath(path); err != nil {
+		return err
+	}
+
+	if umount := fs.umountBinary(); umount != "" {
+		f["cmd"] = umount + " -l"
+		out, err := fs.command(umount, "-l", path).CombinedOutput()
+		if err != nil {
+			log.WithFields(f).WithField("output", string(out)).WithError(err).Error("lazy unmount failed")
+			return fmt.Errorf(
+				"lazy unmount failed: %v\nunmounting arguments: %s\noutput: %s",
+				err, target, out)
+		}
+		return nil
+	}
+
+	if fs.dryRun(DryRunUnmount, path, "MNT_DETACH") {
+		return nil
+	}
+	if err := unix.Unmount(path, unix.MNT_DETACH); err != nil {
+		log.WithFields(f).WithError(err).Error("lazy unmount failed")
+		return fmt.Errorf(
+			"lazy unmount failed: %v\nunmounting arguments: %s",
+			err, target)
+	}
+	return nil
+}
@@ -125,6 +125,65 @@ func TestReadProcMountsFrom(t *testing.T) {
 	chk(success4)
 }
 
+func TestScanProcMountsFrom(t *testing.T) {
+	var all []gofsutil.Entry
+	err := gofsutil.ScanProcMountsFrom(
+		context.TODO(),
+		strings.NewReader(procMountInfoData),
+		gofsutil.MountEntryAllFields,
+		func(_ context.Context, e gofsutil.Entry) (bool, error) {
+			all = append(all, e)
+			return true, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != len(strings.Split(strings.TrimSpace(procMountInfoData), "\n")) {
+		t.Fatalf("unexpected entry count: %d", len(all))
+	}
+	if e := all[0]; e.MountPoint != "/sys" || e.FSType != "sysfs" ||
+		e.MountSource != "sysfs" || e.Root != "/" || len(e.MountOpts) != 5 ||
+		e.DevID != (gofsutil.DevID{Major: 0, Minor: 16}) {
+		t.Errorf("unexpected first entry: %+v", e)
+	}
+
+	// Stop at the first match and only fill in the mount point.
+	visited := 0
+	var found gofsutil.Entry
+	err = gofsutil.ScanProcMountsFrom(
+		context.TODO(),
+		strings.NewReader(procMountInfoData),
+		gofsutil.MountEntryMountPoint,
+		func(_ context.Context, e gofsutil.Entry) (bool, error) {
+			visited++
+			if e.MountPoint == "/dev/shm" {
+				found = e
+				return false, nil
+			}
+			return true, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if visited != 5 {
+		t.Errorf("expected the scan to stop after 5 entries, visited %d", visited)
+	}
+	if found.MountPoint != "/dev/shm" || found.FSType != "" || found.MountOpts != nil {
+		t.Errorf("unexpected fields selected: %+v", found)
+	}
+
+	err = gofsutil.ScanProcMountsFrom(
+		context.TODO(),
+		strings.NewReader("17 60 0:16 / /sys rw\n"),
+		gofsutil.MountEntryAllFields,
+		func(_ context.Context, _ gofsutil.Entry) (bool, error) {
+			return true, nil
+		})
+	if err == nil {
+		t.Error("expected an error for an entry without a separator")
+	}
+}
+
 const procMountInfoData = `17 60 0:16 / /sys rw,nosuid,nodev,noexec,relatime shared:6 - sysfs sysfs rw,seclabel
 18 60 0:3 / /proc rw,nosuid,nodev,noexec,relatime shared:5 - proc proc rw
 19 60 0:5 / /dev rw,nosuid shared:2 - devtmpfs devtmpfs rw,seclabel,size=1930460k,nr_inodes=482615,mode=755
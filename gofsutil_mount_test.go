@@ -18,6 +18,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"testing"
 
 	"github.com/dell/gofsutil"
@@ -215,3 +216,47 @@ func TestGetNVMeController(t *testing.T) {
 		})
 	}
 }
+
+func TestIsCorruptedMountError(t *testing.T) {
+	tests := []struct {
+		err    error
+		result bool
+	}{
+		{err: &os.PathError{Op: "stat", Path: "/mnt/nfs", Err: syscall.ESTALE}, result: true},
+		{err: &os.PathError{Op: "stat", Path: "/mnt/fuse", Err: syscall.ENOTCONN}, result: true},
+		{err: &os.PathError{Op: "stat", Path: "/mnt/blk", Err: syscall.EIO}, result: true},
+		{err: &os.PathError{Op: "stat", Path: "/mnt/none", Err: syscall.ENOENT}, result: false},
+		{err: nil, result: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.result, gofsutil.IsCorruptedMountError(tt.err), "%v", tt.err)
+	}
+}
+
+func TestIsCorruptedMount(t *testing.T) {
+	corrupted, err := gofsutil.IsCorruptedMount(context.Background(), t.TempDir())
+	assert.NoError(t, err)
+	assert.False(t, corrupted)
+
+	_, err = gofsutil.IsCorruptedMount(context.Background(), filepath.Join(t.TempDir(), "missing"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRegenerateXFSUUID(t *testing.T) {
+	err := gofsutil.RegenerateXFSUUID(context.Background(), "/")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to validate path")
+}
+
+func TestRegenerateExtUUID(t *testing.T) {
+	err := gofsutil.RegenerateExtUUID(context.Background(), "/")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to validate path")
+
+	gofsutil.UseMockFS()
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+	assert.NoError(t, gofsutil.RegenerateExtUUID(context.Background(), "/dev/sdb"))
+	gofsutil.GOFSMock.InduceRegenerateExtUUIDError = true
+	defer func() { gofsutil.GOFSMock.InduceRegenerateExtUUIDError = false }()
+	assert.Error(t, gofsutil.RegenerateExtUUID(context.Background(), "/dev/sdb"))
+}
@@ -19,14 +19,16 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
 )
 
 // mount mounts source to target as fsType with given options.
@@ -38,13 +40,6 @@ import (
 // The 'options' parameter is a list of options. Please see mount(8) for
 // more information. If no options are required then please invoke Mount
 // with an empty or nil argument.
-
-// PowerMaxOUIPrefix - PowerMax format 6 OUI prefix
-var PowerMaxOUIPrefix = "6000097"
-
-// PowerStoreOUIPrefix - PowerStore format 6 OUI prefix
-var PowerStoreOUIPrefix = "68ccf09"
-
 func (fs *FS) mount(
 	ctx context.Context,
 	source, target, fsType string,
@@ -54,7 +49,58 @@ func (fs *FS) mount(
 	if opts, ok := fs.isBind(ctx, opts...); ok {
 		return fs.bindMount(ctx, source, target, opts...)
 	}
-	return fs.doMount(ctx, "mount", source, target, fsType, opts...)
+	if err := fs.checkClusterStack(ctx, fsType, opts...); err != nil {
+		return err
+	}
+	opts = fs.mountDefaults.merge(fsType, opts)
+	err := fs.doMount(ctx, fs.mountBinary(), source, target, fsType, opts...)
+	if err != nil && fs.XFSNoUUIDRetry && fsType == "xfs" && !stringInSlice("nouuid", opts) &&
+		fs.xfsUUIDMounted(ctx, source) {
+		// A clone or snapshot of a mounted xfs filesystem has the same
+		// UUID and cannot be mounted alongside it without nouuid.
+		log.WithField("target", target).WithError(err).Info("retrying xfs mount with nouuid")
+		err = fs.doMount(ctx, fs.mountBinary(), source, target, fsType, append(opts[:len(opts):len(opts)], "nouuid")...)
+	}
+	return err
+}
+
+// xfsUUIDMounted returns true if another mounted xfs filesystem has the
+// UUID of the filesystem on source. The kernel refuses to mount such a
+// filesystem, e.g. a clone or snapshot, without nouuid, but mount(8) only
+// reports an invalid argument.
+func (fs *FS) xfsUUIDMounted(ctx context.Context, source string) bool {
+	uuid := fs.filesystemUUID(ctx, source)
+	if uuid == "" {
+		return false
+	}
+	var sources []string
+	err := fs.scanProcMounts(ctx, MountEntryFSType|MountEntryMountSource, func(_ context.Context, e Entry) (bool, error) {
+		if e.FSType == "xfs" && !sameDevice(e.MountSource, source) {
+			sources = append(sources, e.MountSource)
+		}
+		return true, nil
+	})
+	if err != nil {
+		log.WithField("source", source).WithError(err).Warn("cannot read the mounted xfs filesystems")
+		return false
+	}
+	for _, s := range sources {
+		if fs.filesystemUUID(ctx, s) == uuid {
+			return true
+		}
+	}
+	return false
+}
+
+// filesystemUUID returns the UUID of the filesystem on device, or an
+// empty string if it has none or cannot be probed.
+func (fs *FS) filesystemUUID(ctx context.Context, device string) string {
+	/* #nosec G204 */
+	out, err := fs.commandContext(ctx, "blkid", "-p", "-s", "UUID", "-o", "value", device).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
 }
 
 // validateMountArgs validates the arguments for mount operation.
@@ -71,7 +117,7 @@ func (fs *FS) validateMountArgs(source, target, fsType string, opts ...string) e
 	}
 
 	if fsType != "" {
-		if err := validateFsType(fsType); err != nil {
+		if err := fs.fsTypes.validate(fsType); err != nil {
 			return err
 		}
 	}
@@ -88,19 +134,40 @@ func (fs *FS) doMount(
 	if err := fs.validateMountArgs(source, target, fsType, opts...); err != nil {
 		return err
 	}
+	return fs.runMount(ctx, mntCmd, source, target, fsType, opts...)
+}
 
-	mountArgs := MakeMountArgs(ctx, source, target, fsType, opts...)
-	args := strings.Join(mountArgs, " ")
+// runMount runs the mount command without validating its arguments.
+func (fs *FS) runMount(
+	ctx context.Context,
+	mntCmd, source, target, fsType string,
+	opts ...string,
+) error {
+	mountArgs := MakeMountArgs(ctx, source, target, fsType, filterSystemdMountOptions(opts)...)
+	// args is only logged and reported, and must not disclose passwords.
+	sensitive := sensitiveOptions(ctx)
+	args := redactOptions(strings.Join(scrubMountArgs(mountArgs), " "), sensitive)
+
+	cmdName, cmdArgs := mntCmd, mountArgs
+	if fs.SystemdRunScope {
+		if fs.systemdRunAvailable() {
+			cmdName, cmdArgs = systemdRunCmd, systemdScopeArgs(target, mntCmd, mountArgs)
+		} else {
+			log.WithField("target", target).Warn("systemd-run is not available, mounting without a scope")
+		}
+	}
 
 	f := log.Fields{
-		"cmd":  mntCmd,
+		"cmd":  cmdName,
 		"args": args,
 	}
 	log.WithFields(f).Info("mount command")
 	/* #nosec G204 */
-	buf, err := exec.Command(mntCmd, mountArgs...).CombinedOutput()
+	cmd := fs.command(cmdName, cmdArgs...)
+	cmd.sensitive = sensitive
+	buf, err := cmd.CombinedOutput()
 	if err != nil {
-		out := string(buf)
+		out := redactOptions(string(buf), sensitive)
 		// check is explicitly placed for PowerScale driver only
 		if !(strings.Contains(args, "/ifs") && (strings.Contains(strings.ToLower(out), "access denied by server while mounting") || strings.Contains(strings.ToLower(out), "no such file or directory"))) {
 			log.WithFields(f).WithField("output", out).WithError(
@@ -113,6 +180,30 @@ func (fs *FS) doMount(
 	return nil
 }
 
+const systemdRunCmd = "systemd-run"
+
+// systemdRunAvailable returns true if the host runs systemd and
+// systemd-run can be executed.
+func (fs *FS) systemdRunAvailable() bool {
+	if _, err := os.Stat("/run/systemd/system"); err != nil {
+		return false
+	}
+	return fs.commandAvailable(systemdRunCmd)
+}
+
+// systemdScopeArgs returns the systemd-run arguments that run the command
+// name with args in a transient scope.
+func systemdScopeArgs(target, name string, args []string) []string {
+	scopeArgs := []string{
+		"--description=gofsutil transient mount for " + target,
+		"--scope",
+		"--quiet",
+		"--",
+		name,
+	}
+	return append(scopeArgs, args...)
+}
+
 // unmount unmounts the target.
 func (fs *FS) unmount(_ context.Context, target string) error {
 	f := log.Fields{
@@ -125,6 +216,21 @@ func (fs *FS) unmount(_ context.Context, target string) error {
 		return err
 	}
 
+	if umount := fs.umountBinary(); umount != "" {
+		f["cmd"] = umount
+		out, err := fs.command(umount, path).CombinedOutput()
+		if err != nil {
+			log.WithFields(f).WithField("output", string(out)).WithError(err).Error("unmount failed")
+			return fmt.Errorf(
+				"unmount failed: %v\nunmounting arguments: %s\noutput: %s",
+				err, target, out)
+		}
+		return nil
+	}
+
+	if fs.dryRun(DryRunUnmount, path, "") {
+		return nil
+	}
 	err := syscall.Unmount(path, 0)
 	if err != nil {
 		log.WithFields(f).WithError(err).Error("unmount failed")
@@ -178,6 +284,59 @@ func (fs *FS) getDevMounts(ctx context.Context, dev string) ([]Info, error) {
 	return mountInfos, nil
 }
 
+// isLikelyNotMountPoint returns true if path and its parent directory are
+// on the same device. This is fast but does not detect bind mounts of a
+// directory from the same filesystem.
+func (fs *FS) isLikelyNotMountPoint(ctx context.Context, path string) (bool, error) {
+	if err := EvalSymlinks(ctx, &path); err != nil {
+		return true, err
+	}
+	st, err := os.Stat(path)
+	if err != nil {
+		return true, err
+	}
+	parent, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		return true, err
+	}
+	// The root directory is always a mount point.
+	if path == "/" {
+		return false, nil
+	}
+	return st.Sys().(*syscall.Stat_t).Dev == parent.Sys().(*syscall.Stat_t).Dev, nil
+}
+
+// isMountPoint returns true if path is a mount point. The device
+// comparison of isLikelyNotMountPoint is used to detect most mount points
+// without reading the mount table, which is then searched for bind mounts.
+func (fs *FS) isMountPoint(ctx context.Context, path string) (bool, error) {
+	notMnt, err := fs.isLikelyNotMountPoint(ctx, path)
+	if err != nil {
+		return false, err
+	}
+	if !notMnt {
+		return true, nil
+	}
+	if err := EvalSymlinks(ctx, &path); err != nil {
+		return false, err
+	}
+	return fs.mountTableHasPath(ctx, path)
+}
+
+// isCorruptedMount returns true if stating target fails with an error
+// that indicates a corrupted or stale mount.
+func (fs *FS) isCorruptedMount(_ context.Context, target string) (bool, error) {
+	_, err := os.Stat(target)
+	if err == nil {
+		return false, nil
+	}
+	if IsCorruptedMountError(err) {
+		log.WithField("target", target).WithError(err).Warn("corrupted mount detected")
+		return true, nil
+	}
+	return false, err
+}
+
 func (fs *FS) validateDevice(
 	ctx context.Context, source string,
 ) (string, error) {
@@ -209,17 +368,24 @@ func (fs *FS) wwnToDevicePath(
 	_ context.Context, wwn string,
 ) (string, string, error) {
 	// Look for multipath device.
-	symlinkPath := fmt.Sprintf("%s%s", MultipathDevDiskByIDPrefix, wwn)
+	symlinkPath := fs.devPath(fmt.Sprintf("%s%s", MultipathDevDiskByIDPrefix, wwn))
 	devPath, err := os.Readlink(symlinkPath)
 
 	// Look for nvme path device.
 	if err != nil || devPath == "" {
-		symlinkPath = fmt.Sprintf("/dev/disk/by-id/nvme-eui.%s", wwn)
+		symlinkPath = fs.devPath(fmt.Sprintf("/dev/disk/by-id/nvme-eui.%s", wwn))
 		devPath, err = os.Readlink(symlinkPath)
 		if err != nil || devPath == "" {
 			// Look for normal path device
-			symlinkPath = fmt.Sprintf("/dev/disk/by-id/wwn-0x%s", wwn)
+			symlinkPath = fs.devPath(fmt.Sprintf("/dev/disk/by-id/wwn-0x%s", wwn))
 			devPath, err = os.Readlink(symlinkPath)
+			if err != nil {
+				// Look for PowerFlex device
+				if link, ok := fs.powerFlexVolumeLink(wwn); ok {
+					symlinkPath = link
+					devPath, err = os.Readlink(symlinkPath)
+				}
+			}
 			if err != nil {
 				log.Printf("Check for disk path %s not found", symlinkPath)
 				return "", "", err
@@ -228,15 +394,46 @@ func (fs *FS) wwnToDevicePath(
 	}
 	components := strings.Split(devPath, "/")
 	lastPart := components[len(components)-1]
-	devPath = "/dev/" + lastPart
+	devPath = fs.devPath("/dev/" + lastPart)
 	log.Printf("Check for disk path %s found: %s", symlinkPath, devPath)
 	return symlinkPath, devPath, err
 }
 
+// powerFlexVolumeLink returns the /dev/disk/by-id link of the PowerFlex
+// volume id, which is either the volume ID or <system ID>-<volume ID>.
+func (fs *FS) powerFlexVolumeLink(id string) (string, bool) {
+	byIDDir := fs.devPath("/dev/disk/by-id")
+	entries, err := os.ReadDir(byIDDir)
+	if err != nil {
+		return "", false
+	}
+	for _, entry := range entries {
+		if matchesPowerFlexVolume(entry.Name(), id) {
+			return filepath.Join(byIDDir, entry.Name()), true
+		}
+	}
+	return "", false
+}
+
+// powerFlexVolumeDevice returns the name of the scini device of the
+// PowerFlex volume id, e.g. scinia, or an empty string if the volume is
+// not mapped.
+func (fs *FS) powerFlexVolumeDevice(id string) string {
+	link, ok := fs.powerFlexVolumeLink(id)
+	if !ok {
+		return ""
+	}
+	dev, err := filepath.EvalSymlinks(link)
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(dev)
+}
+
 // targetIPLUNToDevicePath returns all the /dev/disk/by-path entries for a give targetIP and lunID
 func (fs *FS) targetIPLUNToDevicePath(_ context.Context, targetIP string, lunID int) (map[string]string, error) {
 	result := make(map[string]string, 0)
-	bypathdir := "/dev/disk/by-path"
+	bypathdir := fs.devPath("/dev/disk/by-path")
 	entries, err := os.ReadDir(bypathdir)
 	if err != nil {
 		log.Printf("/dev/disk/by-path not found: %s", err.Error())
@@ -264,7 +461,7 @@ func (fs *FS) targetIPLUNToDevicePath(_ context.Context, targetIP string, lunID
 		}
 		components := strings.Split(devPath, "/")
 		lastPart := components[len(components)-1]
-		devPath = "/dev/" + lastPart
+		devPath = fs.devPath("/dev/" + lastPart)
 		log.Printf("Check for disk path %s found: %s", path, devPath)
 		result[path] = devPath
 	}
@@ -286,87 +483,241 @@ func (td *targetdev) String() string {
 // If targets are specified, only hosts who are related to the specified
 // iqn target(s) are rescanned.
 // If lun is specified, then the rescan is for that particular volume.
-func (fs *FS) rescanSCSIHost(_ context.Context, targets []string, lun string) error {
-	var err error
-	// If no lun is specifed, the "-" character is a wildcard that will update all LUNs.
+func (fs *FS) rescanSCSIHost(ctx context.Context, targets []string, lun string) error {
+	_, err := fs.rescanSCSIHostX(ctx, targets, lun)
+	return err
+}
+
+// rescanSCSIHostX performs the same rescan as rescanSCSIHost and reports
+// the hosts that were scanned and the block devices that appeared.
+func (fs *FS) rescanSCSIHostX(ctx context.Context, targets []string, lun string) (*RescanReport, error) {
+	report := &RescanReport{ScanStrings: make(map[string][]string)}
+	lun = scsiScanLUN(lun)
+
+	targetDevices, skipped, err := fs.rescanTargetDevices(targets)
+	report.FullRescanSkipped = skipped
+	if err != nil {
+		return report, err
+	}
+
+	// Remember the existing devices so the new ones can be reported.
+	before := fs.listSysBlockDevices("sd")
+
+	// For each of the matching hosts, perform a rescan.
+	for _, entry := range targetDevices {
+		scanfile := fs.scsiHostScanFile(entry.host)
+		scanstring := fmt.Sprintf("%s %s %s", entry.channel, entry.target, lun)
+		log.Printf("rescanning %s with: "+scanstring, scanfile)
+		if fs.writeScanString(ctx, entry.host, scanstring) {
+			if _, ok := report.ScanStrings[scanfile]; !ok {
+				report.Hosts = append(report.Hosts, entry.host)
+			}
+			report.ScanStrings[scanfile] = append(report.ScanStrings[scanfile], scanstring)
+		}
+	}
+
+	fs.reportNewDevices(report, before)
+	return report, nil
+}
+
+// rescanSCSIHostsForLUNs rescans the hosts related to targets for each of
+// luns, writing the scan strings of a host in one pass and scanning the
+// hosts in parallel.
+func (fs *FS) rescanSCSIHostsForLUNs(ctx context.Context, targets []string, luns []string) (*RescanReport, error) {
+	report := &RescanReport{ScanStrings: make(map[string][]string)}
+	scanLUNs := scsiScanLUNs(luns)
+
+	targetDevices, skipped, err := fs.rescanTargetDevices(targets)
+	report.FullRescanSkipped = skipped
+	if err != nil {
+		return report, err
+	}
+
+	// Group the scan strings by host, dropping duplicate targets.
+	var hosts []string
+	scanStrings := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, entry := range targetDevices {
+		if seen[entry.String()] {
+			continue
+		}
+		seen[entry.String()] = true
+		if _, ok := scanStrings[entry.host]; !ok {
+			hosts = append(hosts, entry.host)
+		}
+		for _, lun := range scanLUNs {
+			scanStrings[entry.host] = append(scanStrings[entry.host],
+				fmt.Sprintf("%s %s %s", entry.channel, entry.target, lun))
+		}
+	}
+
+	before := fs.listSysBlockDevices("sd")
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+			scanfile := fs.scsiHostScanFile(host)
+			var written []string
+			for _, scanstring := range scanStrings[host] {
+				if ctx.Err() != nil {
+					break
+				}
+				log.Printf("rescanning %s with: "+scanstring, scanfile)
+				if fs.writeScanString(ctx, host, scanstring) {
+					written = append(written, scanstring)
+				}
+			}
+			if len(written) == 0 {
+				return
+			}
+			mu.Lock()
+			report.Hosts = append(report.Hosts, host)
+			report.ScanStrings[scanfile] = written
+			mu.Unlock()
+		}(host)
+	}
+	wg.Wait()
+	sort.Strings(report.Hosts)
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+
+	fs.reportNewDevices(report, before)
+	return report, nil
+}
+
+// scsiScanLUN returns the LUN of a scan string for lun, which is specified
+// as a hex string by Powermax. The "-" wildcard, which updates all LUNs, is
+// returned if no lun is specified or it is not valid.
+func scsiScanLUN(lun string) string {
 	if lun == "" {
-		lun = "-"
-	} else {
-		// The lun string is speficied as a hex string by Powermax.
-		// We need decimal for the scan, so do the conversion.
-		val, err := strconv.ParseInt(lun, 16, 32)
-		if err == nil {
-			lun = strconv.Itoa(int(val))
-		} else {
-			lun = "-"
+		return "-"
+	}
+	// We need decimal for the scan, so do the conversion.
+	val, err := strconv.ParseInt(lun, 16, 32)
+	if err != nil {
+		return "-"
+	}
+	return strconv.Itoa(int(val))
+}
+
+// scsiScanLUNs returns the distinct scan LUNs of luns. Only the wildcard is
+// returned if luns is empty or any of them is the wildcard.
+func scsiScanLUNs(luns []string) []string {
+	var scanLUNs []string
+	for _, lun := range luns {
+		scanLUN := scsiScanLUN(lun)
+		if scanLUN == "-" {
+			return []string{"-"}
+		}
+		if !stringInSlice(scanLUN, scanLUNs) {
+			scanLUNs = append(scanLUNs, scanLUN)
 		}
 	}
+	if len(scanLUNs) == 0 {
+		return []string{"-"}
+	}
+	return scanLUNs
+}
 
+// rescanTargetDevices returns the target devices of the hosts related to
+// targets, or the wildcard targets of all hosts if none are found. The
+// rescan of all hosts is skipped, and true returned, if it is disabled or
+// rate limited.
+func (fs *FS) rescanTargetDevices(targets []string) ([]*targetdev, bool, error) {
 	iscsiTargets, fcTargets := splitTargets(targets)
-	targetDevices, err := getFCTargetHosts(fcTargets)
+	targetDevices, err := fs.getFCTargetHosts(fcTargets)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 	log.Printf("iscsiTargets: %s; fcTargets: %s", iscsiTargets, targetDevices)
 
-	iscsiTargetDevices, err := getIscsiTargetHosts(iscsiTargets)
+	iscsiTargetDevices, err := fs.getIscsiTargetHosts(iscsiTargets)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 	targetDevices = append(targetDevices, iscsiTargetDevices...)
-
-	hostsdir := "/sys/class/scsi_host"
 	if len(targetDevices) > 0 {
-		for _, entry := range targetDevices {
-			scanfile := fmt.Sprintf("%s/%s/scan", hostsdir, entry.host)
-			scanstring := fmt.Sprintf("%s %s %s", entry.channel, entry.target, lun)
-			log.Printf("rescanning %s with: "+scanstring, scanfile)
-			f, err := os.OpenFile(filepath.Clean(scanfile), os.O_APPEND|os.O_WRONLY, 0o200)
-			if err != nil {
-				log.WithFields(log.Fields{"file": scanfile, "error": err}).Error("Failed to open scanfile")
-				continue
-			}
-			if _, err := f.WriteString(scanstring); err != nil {
-				log.WithFields(log.Fields{"file": scanfile, "error": err}).Error("Failed to write rescan file")
-			}
-			errs := f.Close()
-			if errs != nil {
-				return err
-			}
-		}
-		return nil
+		return targetDevices, false, nil
 	}
 
 	// Fallback... we didn't find any target devices... so rescan all the hosts
 	// Gather up the host devices.
+	if fs.DisableFullRescan {
+		log.Warn("No targeted devices found and rescanning all the hosts is disabled")
+		return nil, true, nil
+	}
+	if ok, wait := fs.fullRescans.allow(fs.FullRescanInterval); !ok {
+		log.WithField("retryIn", wait).Warn("No targeted devices found, skipping rescan of all the hosts that ran recently")
+		return nil, true, nil
+	}
 	log.Printf("No targeted devices found... rescanning all the hosts")
+	hostsdir := fs.sysPath("/sys/class/scsi_host")
 	hosts, err := os.ReadDir(hostsdir)
 	if err != nil {
 		log.WithField("error", err).Error("Cannot read directory: " + hostsdir)
-		return err
+		return nil, false, err
 	}
-	// For each of the matching hosts, perform a rescan.
 	for _, host := range hosts {
 		if !strings.HasPrefix(host.Name(), "host") {
 			continue
 		}
-		scanfile := fmt.Sprintf("%s/%s/scan", hostsdir, host.Name())
-		scanstring := fmt.Sprintf("- - %s", lun)
-		log.Printf("rescanning %s with: "+scanstring, scanfile)
-		f, err := os.OpenFile(filepath.Clean(scanfile), os.O_APPEND|os.O_WRONLY, 0o200)
-		if err != nil {
-			log.WithFields(log.Fields{"file": scanfile, "error": err}).Error("Failed to open scanfile")
-			continue
-		}
-		if _, err := f.WriteString(scanstring); err != nil {
-			log.WithFields(log.Fields{"file": scanfile, "error": err}).Error("Failed to write rescan file")
+		targetDevices = append(targetDevices, &targetdev{host: host.Name(), channel: "-", target: "-"})
+	}
+	return targetDevices, false, nil
+}
+
+// scsiHostScanFile returns the scan file of a scsi host, e.g. host3.
+func (fs *FS) scsiHostScanFile(host string) string {
+	return fmt.Sprintf("%s/%s/scan", fs.sysPath("/sys/class/scsi_host"), host)
+}
+
+// writeScanString writes scanstring to the scan file of host. A scan file
+// that cannot be resolved within sysfs or written is logged and reported
+// as not written.
+func (fs *FS) writeScanString(ctx context.Context, host, scanstring string) bool {
+	scanfile, err := fs.sysfsAttrPath(ctx, "/sys/class/scsi_host/"+host+"/scan")
+	if err == nil {
+		err = fs.writeSysfsFile(scanfile, scanstring)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{"host": host, "error": err}).Error("Failed to write rescan file")
+		return false
+	}
+	return true
+}
+
+// reportNewDevices adds the block devices that are not in before to the
+// report.
+func (fs *FS) reportNewDevices(report *RescanReport, before []string) {
+	after := fs.listSysBlockDevices("sd")
+	for _, name := range after {
+		if !stringInSlice(name, before) {
+			report.NewDevices = append(report.NewDevices, name)
 		}
-		errs := f.Close()
-		if errs != nil {
-			return err
+	}
+	log.Printf("rescanned hosts %v, new devices %v", report.Hosts, report.NewDevices)
+}
+
+// listSysBlockDevices returns the names of the entries in the block
+// directory that have the given prefix.
+func (fs *FS) listSysBlockDevices(prefix string) []string {
+	names := make([]string, 0)
+	entries, err := os.ReadDir(fs.sysBlockDir())
+	if err != nil {
+		return names
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), prefix) {
+			names = append(names, entry.Name())
 		}
 	}
-	return nil
+	return names
 }
 
 // FCPortPrefix has the required port prefix for FCTargetHosts
@@ -376,14 +727,14 @@ const FCPortPrefix = "0x50"
 // The targets are a list of array port WWNs in the port group used. They must start with 0x50 and
 // be of the form 0x50000973b000b804 as an example.
 // along with the channel and target, to the targetdev list.
-func getFCTargetHosts(targets []string) ([]*targetdev, error) {
+func (fs *FS) getFCTargetHosts(targets []string) ([]*targetdev, error) {
 	targetDev := make([]*targetdev, 0)
 	duplicates := make(map[string]bool)
 	if len(targets) == 0 {
 		return targetDev, nil
 	}
 	// Read the directory entries for fc_remote_ports
-	fcRemotePortsDir := "/sys/class/fc_remote_ports"
+	fcRemotePortsDir := fs.sysPath("/sys/class/fc_remote_ports")
 	remotePortEntries, err := os.ReadDir(fcRemotePortsDir)
 	if err != nil {
 		log.WithField("error", err).Error("Cannot read directory: " + fcRemotePortsDir)
@@ -432,13 +783,13 @@ func getFCTargetHosts(targets []string) ([]*targetdev, error) {
 
 // getIscsiTargetHosts adds the list of the scsi hosts in /sys/class/scsi_host to be rescanned,
 // along with the channel and target, to the targetdev list.
-func getIscsiTargetHosts(targets []string) ([]*targetdev, error) {
+func (fs *FS) getIscsiTargetHosts(targets []string) ([]*targetdev, error) {
 	targetDev := make([]*targetdev, 0)
 	if len(targets) == 0 {
 		return targetDev, nil
 	}
 	// Read the sessions.
-	sessionsdir := "/sys/class/iscsi_session"
+	sessionsdir := fs.sysPath("/sys/class/iscsi_session")
 	sessions, err := os.ReadDir(sessionsdir)
 	if err != nil {
 		log.WithField("error", err).Error("Cannot read directory: " + sessionsdir)
@@ -468,29 +819,34 @@ func getIscsiTargetHosts(targets []string) ([]*targetdev, error) {
 		}
 		// Read device/target entry to get the data for rescan.
 		devicedir := sessionsdir + "/" + session.Name() + "/" + "device"
-		devices, err := os.ReadDir(devicedir)
-		if err != nil {
-			log.WithField("error", err).Error("Cannot read directory: " + devicedir)
-			continue
+		if entry, ok := iscsiSessionTarget(devicedir); ok {
+			targetDev = append(targetDev, entry)
+			log.Debug(fmt.Sprintf("Adding target: %s", entry))
 		}
-		// Loop through the devices for the target* one
-		for _, device := range devices {
-			if strings.HasPrefix(device.Name(), "target") {
-				name := device.Name()[6:]
-				split := strings.Split(name, ":")
-				if len(split) >= 3 {
-					entry := new(targetdev)
-					entry.host = "host" + split[0]
-					entry.channel = split[1]
-					entry.target = split[2]
-					targetDev = append(targetDev, entry)
-					log.Debug(fmt.Sprintf("Adding target: %s", entry))
-				}
-				break
+	}
+	return targetDev, nil
+}
+
+// iscsiSessionTarget returns the host, channel and target of the SCSI
+// target of a session from the targetH:C:T entry of its device directory.
+func iscsiSessionTarget(devicedir string) (*targetdev, bool) {
+	devices, err := os.ReadDir(devicedir)
+	if err != nil {
+		log.WithField("error", err).Error("Cannot read directory: " + devicedir)
+		return nil, false
+	}
+	// Loop through the devices for the target* one
+	for _, device := range devices {
+		if strings.HasPrefix(device.Name(), "target") {
+			name := device.Name()[6:]
+			split := strings.Split(name, ":")
+			if len(split) >= 3 {
+				return &targetdev{host: "host" + split[0], channel: split[1], target: split[2]}, true
 			}
+			break
 		}
 	}
-	return targetDev, nil
+	return nil, false
 }
 
 // Splits the targeets into those for iscsi or fibre channel
@@ -512,13 +868,13 @@ func splitTargets(targets []string) ([]string, []string) {
 // removeBlockDevice removes a block device by getting the device name
 // from the last component of the blockDevicePath and then removing the
 // device by writing '1' to /sys/block{deviceName}/device/delete
-func (fs *FS) removeBlockDevice(_ context.Context, blockDevicePath string) error {
+func (fs *FS) removeBlockDevice(ctx context.Context, blockDevicePath string) error {
 	// Here we want to remove /sys/block/{deviceName} by writing a 1 to
 	// /sys/block{deviceName}/device/delete
 	devicePathComponents := strings.Split(blockDevicePath, "/")
 	if len(devicePathComponents) > 1 {
 		deviceName := devicePathComponents[len(devicePathComponents)-1]
-		statePath := fmt.Sprintf("/sys/block/%s/device/state", deviceName)
+		statePath := fmt.Sprintf("%s/%s/device/state", fs.sysBlockDir(), deviceName)
 		stateBytes, err := os.ReadFile(filepath.Clean(statePath))
 		if err != nil {
 			return fmt.Errorf("Cannot read %s: %s", statePath, err)
@@ -527,18 +883,13 @@ func (fs *FS) removeBlockDevice(_ context.Context, blockDevicePath string) error
 		if deviceState == "blocked" {
 			return fmt.Errorf("Device %s is in blocked state", deviceName)
 		}
-		blockDeletePath := fmt.Sprintf("/sys/block/%s/device/delete", deviceName)
-		f, err := os.OpenFile(filepath.Clean(blockDeletePath), os.O_APPEND|os.O_WRONLY, 0o200)
+		blockDeletePath, err := fs.sysBlockAttrPath(ctx, deviceName, "device/delete")
 		if err != nil {
-			log.WithField("BlockDeletePath", blockDeletePath).Error("Could not open delete block device delete path")
-			return err
+			return fmt.Errorf("Cannot resolve the delete path of %s: %w", deviceName, err)
 		}
 		log.WithField("BlockDeletePath", blockDeletePath).Info("Writing '1' to block device delete path")
-		if _, err := f.WriteString("1"); err != nil {
+		if err := fs.writeSysfsFile(blockDeletePath, "1"); err != nil {
 			log.WithField("BlockDeletePath", blockDeletePath).Error("Could not write to block device delete path")
-		}
-		err = f.Close()
-		if err != nil {
 			return err
 		}
 	}
@@ -551,27 +902,32 @@ func (fs *FS) removeBlockDevice(_ context.Context, blockDevicePath string) error
 // When the -f <dev-name> option has been specified, the flush seems to happen but the
 // command seems to hang. The reason is currently unknown.
 func (fs *FS) multipathCommand(ctx context.Context, timeoutSeconds time.Duration, chroot string, arguments ...string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutSeconds*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, timeoutSeconds*time.Second)
 	defer cancel()
-	var cmd *exec.Cmd
+	var cmd *execCmd
 	args := make([]string, 0)
 
 	if err := validateMultipathArgs(arguments...); err != nil {
 		return nil, err
 	}
 
+	if chroot != "" && fs.runsOnHost() {
+		// The commands already run in the root of the host.
+		log.WithField("chroot", chroot).Debug("ignoring chroot, commands run on the host")
+		chroot = ""
+	}
 	if chroot == "" {
 		args = append(args, arguments...)
 		log.Printf("/usr/sbin/multipath %v", args)
 		/* #nosec G204 */
-		cmd = exec.CommandContext(ctx, "/usr/sbin/multipath", args...)
+		cmd = fs.commandContext(ctx, "/usr/sbin/multipath", args...)
 	} else {
 		args = append(args, chroot)
 		args = append(args, "/usr/sbin/multipath")
 		args = append(args, arguments...)
 		log.Printf("/usr/sbin/chroot %v", args)
 		/* #nosec G204 */
-		cmd = exec.CommandContext(ctx, "/usr/sbin/chroot", args...)
+		cmd = fs.commandContext(ctx, "/usr/sbin/chroot", args...)
 	}
 	textBytes, err := cmd.CombinedOutput()
 	if err != nil {
@@ -587,7 +943,7 @@ func (fs *FS) multipathCommand(ctx context.Context, timeoutSeconds time.Duration
 func (fs *FS) getFCHostPortWWNs(_ context.Context) ([]string, error) {
 	portWWNs := make([]string, 0)
 	// Read the directory entries for fc_remote_ports
-	fcHostsDir := "/sys/class/fc_host"
+	fcHostsDir := fs.sysPath("/sys/class/fc_host")
 	hostEntries, err := os.ReadDir(fcHostsDir)
 	if err != nil {
 		log.WithField("error", err).Error("Cannot read directory: " + fcHostsDir)
@@ -611,10 +967,10 @@ func (fs *FS) getFCHostPortWWNs(_ context.Context) ([]string, error) {
 }
 
 // issueLIPToAllFCHosts issues the LIP command to all FC hosts.
-func (fs *FS) issueLIPToAllFCHosts(_ context.Context) error {
+func (fs *FS) issueLIPToAllFCHosts(ctx context.Context) error {
 	var savedError error
 	// Read the directory entries for fc_remote_ports
-	fcHostsDir := "/sys/class/fc_host"
+	fcHostsDir := fs.sysPath("/sys/class/fc_host")
 	fcHostEntries, err := os.ReadDir(fcHostsDir)
 	if err != nil {
 		log.WithField("error", err).Error("Cannot read directory: " + fcHostsDir)
@@ -626,22 +982,18 @@ func (fs *FS) issueLIPToAllFCHosts(_ context.Context) error {
 			continue
 		}
 
-		lipFile := fmt.Sprintf("%s/%s/issue_lip", fcHostsDir, hostEntry.Name())
-		lipString := fmt.Sprintf("%s", "1")
-		log.Printf("issuing lip command %s to %s", lipString, lipFile)
-		f, err := os.OpenFile(filepath.Clean(lipFile), os.O_APPEND|os.O_WRONLY, 0o200)
+		lipFile, err := fs.sysfsAttrPath(ctx, "/sys/class/fc_host/"+hostEntry.Name()+"/issue_lip")
 		if err != nil {
-			log.Error("Could not open issue_lip file at: " + lipFile)
+			log.Error(fmt.Sprintf("Error resolving lip file of %s: %s", hostEntry.Name(), err))
+			savedError = err
 			continue
 		}
-		if _, err := f.WriteString(lipString); err != nil {
+		lipString := fmt.Sprintf("%s", "1")
+		log.Printf("issuing lip command %s to %s", lipString, lipFile)
+		if err := fs.writeSysfsFile(lipFile, lipString); err != nil {
 			log.Error(fmt.Sprintf("Error issuing lip at %s: %s", lipFile, err))
 			savedError = err
 		}
-		errs := f.Close()
-		if errs != nil {
-			return err
-		}
 	}
 	return savedError
 }
@@ -650,13 +1002,25 @@ func (fs *FS) issueLIPToAllFCHosts(_ context.Context) error {
 func (fs *FS) getSysBlockDevicesForVolumeWWN(_ context.Context, volumeWWN string) ([]string, error) {
 	start := time.Now()
 	result := make([]string, 0)
-	sysBlocks, err := os.ReadDir(fs.SysBlockDir)
+	sysBlockDir := fs.sysBlockDir()
+	sysBlocks, err := os.ReadDir(sysBlockDir)
 	if err != nil {
-		return result, fmt.Errorf("Error reading %s: %s", fs.SysBlockDir, err)
+		return result, fmt.Errorf("Error reading %s: %s", sysBlockDir, err)
 	}
 
+	powerFlexDevice := sync.OnceValue(func() string {
+		return fs.powerFlexVolumeDevice(volumeWWN)
+	})
 	for _, sysBlock := range sysBlocks {
 		name := sysBlock.Name()
+		// PowerFlex devices have no wwid, they are matched by their
+		// /dev/disk/by-id links.
+		if isSciniDevice(name) {
+			if name == powerFlexDevice() {
+				result = append(result, name)
+			}
+			continue
+		}
 		// Check for both "sd" and "nvme" prefixes
 		if !strings.HasPrefix(name, "sd") && !strings.HasPrefix(name, "nvme") {
 			continue
@@ -665,9 +1029,9 @@ func (fs *FS) getSysBlockDevicesForVolumeWWN(_ context.Context, volumeWWN string
 		// Set the WWID path based on the device type
 		var wwidPath string
 		if strings.HasPrefix(name, "nvme") {
-			wwidPath = fs.SysBlockDir + "/" + name + "/wwid" // For NVMe devices
+			wwidPath = sysBlockDir + "/" + name + "/wwid" // For NVMe devices
 		} else {
-			wwidPath = fs.SysBlockDir + "/" + name + "/device/wwid" // For SCSI devices
+			wwidPath = sysBlockDir + "/" + name + "/device/wwid" // For SCSI devices
 		}
 
 		bytes, err := os.ReadFile(filepath.Clean(wwidPath))
@@ -680,8 +1044,8 @@ func (fs *FS) getSysBlockDevicesForVolumeWWN(_ context.Context, volumeWWN string
 		// Replace "eui." for NVMe devices and "naa." for others
 		if strings.HasPrefix(name, "nvme") {
 			wwid = strings.Replace(wwid, "eui.", "", 1)
-			// Use wwnMatches for NVMe comparison
-			if wwnMatches(wwid, volumeWWN) {
+			// Use MatchesWWN for NVMe comparison
+			if MatchesWWN(wwid, volumeWWN) {
 				result = append(result, name)
 			}
 		} else {
@@ -699,54 +1063,9 @@ func (fs *FS) getSysBlockDevicesForVolumeWWN(_ context.Context, volumeWWN string
 	return result, nil
 }
 
-func wwnMatches(nguid, wwn string) bool {
-	/*
-			// PowerStore
-			Sample wwn : naa.68ccf098001111a2222b3d4444a1b23c
-			token1: 1111a2222b3d4444
-			token2: a1b23c
-			Sample nguid : 1111a2222b3d44448ccf096800a1b23c
-
-			// PowerMax
-			nguid: 12635330303134340000976000012000
-			wwn:   60000970000120001263533030313434
-		           11aaa111111111a11a111a1111aa1111
-		           1a111a1111aa1111 1aaa11 1 1111111a1
-			nguid: wwn[last16] 		+ wwn[1:6] 	+ wwn[0] + wwn[7:15]
-				   1263533030313434 + 000097 	+ 6		 + 000012000
-	*/
-	if len(wwn) < 32 {
-		return false
-	}
-
-	wwn = strings.ToLower(wwn)
-	if strings.HasPrefix(wwn, "naa.") {
-		wwn = wwn[4:]
-	}
-
-	var token1, token2 string
-	if strings.HasPrefix(wwn, PowerStoreOUIPrefix) {
-		token1 = wwn[13 : len(wwn)-7]
-		token2 = wwn[len(wwn)-6 : len(wwn)-1]
-		log.Infof("PowerStore: %s %s %s %t", token1, token2, nguid, strings.Contains(nguid, token2))
-		if strings.Contains(nguid, token1) && strings.Contains(nguid, token2) {
-			return true
-		}
-	} else if strings.HasPrefix(wwn, PowerMaxOUIPrefix) {
-		token1 = wwn[16:]
-		token2 = wwn[1:7]
-		log.Infof("Powermax: %s %s %s %t", token1, token2, nguid, strings.HasPrefix(nguid, token1+token2))
-		if strings.HasPrefix(nguid, token1+token2) {
-			return true
-		}
-	}
-
-	return false
-}
-
 // GetNVMeController retrieves the NVMe controller for a given NVMe device.
 func (fs *FS) getNVMeController(device string) (string, error) {
-	devicePath := filepath.Join(fs.SysBlockDir, device)
+	devicePath := filepath.Join(fs.sysBlockDir(), device)
 
 	// Check if the device path exists
 	if _, err := os.Stat(devicePath); os.IsNotExist(err) {
@@ -780,3 +1099,34 @@ func (fs *FS) getNVMeController(device string) (string, error) {
 
 	return "", fmt.Errorf("controller not found for device %s", device)
 }
+
+// fsInfo linux returns (available bytes, byte capacity, byte usage, total inodes, inodes free, inode usage, error)
+// for the filesystem that path resides upon.
+func (fs *FS) fsInfo(_ context.Context, path string) (int64, int64, int64, int64, int64, int64, error) {
+	statfs := &unix.Statfs_t{}
+	err := unix.Statfs(path, statfs)
+	if err != nil {
+		return 0, 0, 0, 0, 0, 0, err
+	}
+
+	// Available is blocks available * fragment size
+	// #nosec G115
+	available := int64(statfs.Bavail) * statfs.Bsize
+
+	// Capacity is total block count * fragment size
+	// #nosec G115
+	capacity := int64(statfs.Blocks) * statfs.Bsize
+
+	// Usage is block being used * fragment size (aka block size).
+	// #nosec G115
+	usage := (int64(statfs.Blocks) - int64(statfs.Bfree)) * statfs.Bsize
+
+	// #nosec G115
+	inodes := int64(statfs.Files)
+
+	// #nosec G115
+	inodesFree := int64(statfs.Ffree)
+	inodesUsed := inodes - inodesFree
+
+	return available, capacity, usage, inodes, inodesFree, inodesUsed, nil
+}
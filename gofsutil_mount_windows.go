@@ -15,7 +15,10 @@ package gofsutil
 import (
 	"context"
 	"errors"
+	"os"
 	"time"
+
+	"golang.org/x/sys/windows"
 )
 
 var info []Info
@@ -28,6 +31,10 @@ func (fs *FS) formatAndMount(ctx context.Context, source, target, fsType string,
 	return errors.New("not implemented")
 }
 
+func (fs *FS) formatAndMountMPath(ctx context.Context, mpathDevice, target, fsType string, opts MPathFormatOptions) error {
+	return errors.New("not implemented")
+}
+
 func (fs *FS) format(ctx context.Context, source, target, fsType string, opts ...string) error {
 	return errors.New("not implemented")
 }
@@ -41,6 +48,11 @@ func (fs *FS) resizeFS(ctx context.Context, volumePath, devicePath, ppathDevice,
 	return errors.New("not implemented")
 }
 
+// resizeFSWithOptions expands the filesystem if it does not fill the device
+func (fs *FS) resizeFSWithOptions(ctx context.Context, volumePath, devicePath, ppathDevice, mpathDevice, fsType string, opts ResizeFSOptions) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
 // findFSType fetches the filesystem type on mountpoint
 func (fs *FS) findFSType(
 	ctx context.Context, mountpoint string,
@@ -80,7 +92,6 @@ func (fs *FS) readProcMounts(ctx context.Context,
 
 func (fs *FS) mount(ctx context.Context, source, target, fsType string, opts ...string) error {
 	return errors.New("not implemented")
-	return nil
 }
 
 func (fs *FS) unmount(ctx context.Context, target string) error {
@@ -97,33 +108,12 @@ func (fs *FS) validateDevice(
 	return "", errors.New("not implemented")
 }
 
-func (fs *FS) wwnToDevicePath(
-	ctx context.Context, wwn string,
-) (string, string, error) {
-	return "", "", errors.New("not implemented")
-}
-
 // targetIPLUNToDevicePath returns all the /dev/disk/by-path entries for a give targetIP and lunID
 func (fs *FS) targetIPLUNToDevicePath(ctx context.Context, targetIP string, lunID int) (map[string]string, error) {
 	result := make(map[string]string, 0)
 	return result, errors.New("not implemented")
 }
 
-// rescanSCSIHost will rescan scsi hosts for a specified lun.
-// If targets are specified, only hosts who are related to the specified
-// iqn target(s) are rescanned.
-// If lun is specified, then the rescan is for that particular volume.
-func (fs *FS) rescanSCSIHost(ctx context.Context, targets []string, lun string) error {
-	return errors.New("not implemented")
-}
-
-// RemoveBlockDevice removes a block device by getting the device name
-// from the last component of the blockDevicePath and then removing the
-// device by writing '1' to /sys/block{deviceName}/device/delete
-func (fs *FS) removeBlockDevice(ctx context.Context, blockDevicePath string) error {
-	return errors.New("not implemented")
-}
-
 // Execute the multipath command with a timeout and various arguments.
 // Optionally a chroot directory can be specified for changing root directory.
 // This only works in a container or another environment where it can chroot to /noderoot.
@@ -132,18 +122,334 @@ func (fs *FS) multipathCommand(ctx context.Context, timeoutSeconds time.Duration
 	return result, errors.New("not implemented")
 }
 
-func (fs *FS) getFCHostPortWWNs(context.Context) ([]string, error) {
-	result := make([]string, 0)
-	return result, errors.New("not implemented")
+func (fs *FS) connectNVMeTarget(ctx context.Context, transport, traddr, trsvcid, nqn string, opts NVMeConnectOptions) error {
+	return errors.New("not implemented")
 }
 
-// issueLIPToAllFCHosts issues the LIP command to all FC hosts.
-func (fs *FS) issueLIPToAllFCHosts(ctx context.Context) error {
+func (fs *FS) disconnectNVMeTarget(ctx context.Context, nqn string) error {
 	return errors.New("not implemented")
 }
 
-// getSysBlockDevicesForVolumeWWN given a volumeWWN will return a list of devices in /sys/block for that WWN (e.g. sdx, sdaa)
-func (fs *FS) getSysBlockDevicesForVolumeWWN(ctx context.Context, volumeWWN string) ([]string, error) {
-	result := make([]string, 0)
-	return result, errors.New("not implemented")
+func (fs *FS) getNVMeControllersForSubsystemNQN(ctx context.Context, nqn string) ([]NVMeController, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) dmSuspend(ctx context.Context, name string) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) dmResume(ctx context.Context, name string) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) getDMTable(ctx context.Context, name string) (*DMTable, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) isMountPoint(ctx context.Context, path string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (fs *FS) isLikelyNotMountPoint(ctx context.Context, path string) (bool, error) {
+	return true, errors.New("not implemented")
+}
+
+func (fs *FS) isCorruptedMount(ctx context.Context, target string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (fs *FS) multipathdStatus(ctx context.Context) (*MultipathdHealth, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) regenerateXFSUUID(ctx context.Context, device string) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) regenerateExtUUID(ctx context.Context, device string) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) makeBlockFile(ctx context.Context, target string) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) bindMountBlockDevice(ctx context.Context, device, target string, opts ...string) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) unmountBlockDevice(ctx context.Context, target string) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) checkNFSExport(ctx context.Context, server, exportPath string) (*NFSExportStatus, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) diskUsage(ctx context.Context, path string) (*DiskUsageInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) setProjectQuota(ctx context.Context, path string, projID uint32, limitBytes int64) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) getProjectQuota(ctx context.Context, path string, projID uint32) (*ProjectQuota, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) scanProcMounts(ctx context.Context, fields MountEntryField, fn MountEntryFunc) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) getNVMeController(device string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+// fsInfo windows returns (available bytes, byte capacity, byte usage, total inodes, inodes free, inode usage, error)
+// for the volume that path resides upon. Windows volumes have no inodes,
+// so the inode counts are -1.
+func (fs *FS) fsInfo(ctx context.Context, path string) (int64, int64, int64, int64, int64, int64, error) {
+	dir, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, 0, 0, 0, 0, 0, err
+	}
+	var available, capacity, free uint64
+	if err := windows.GetDiskFreeSpaceEx(dir, &available, &capacity, &free); err != nil {
+		return 0, 0, 0, 0, 0, 0, &os.PathError{Op: "GetDiskFreeSpaceEx", Path: path, Err: err}
+	}
+	// #nosec G115
+	return int64(available), int64(capacity), int64(capacity - free), -1, -1, -1, nil
+}
+
+func (fs *FS) mountWithOptions(ctx context.Context, source, target, fsType string, opts MountOptions) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) waitForDeviceToSettle(ctx context.Context, device string) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) getMountsByKind(ctx context.Context, kinds ...MountKind) ([]Info, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) getPartitions(ctx context.Context, device string) (*PartitionTable, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) createPartition(ctx context.Context, device string, spec PartitionSpec) (*Partition, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) deletePartition(ctx context.Context, device string, number int) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) growPartition(ctx context.Context, device string, number int) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) getMountsInto(ctx context.Context, infos []Info) ([]Info, error) {
+	return infos[:0], errors.New("not implemented")
+}
+
+func (fs *FS) getNPIVPorts(ctx context.Context) ([]NPIVPort, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) createNPIVPort(ctx context.Context, host, wwpn, wwnn string) (*NPIVPort, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) deleteNPIVPort(ctx context.Context, host, wwpn string) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) getFCHostInfo(ctx context.Context) ([]FCHostInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) getISCSISessionState(ctx context.Context, targetIQN string) ([]ISCSISession, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) getHostNQN(ctx context.Context, create bool) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (fs *FS) getNQNHostID(ctx context.Context, create bool) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (fs *FS) getHostIQN(ctx context.Context, create bool) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (fs *FS) ensureDeviceUnused(ctx context.Context, device string) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) smbMount(ctx context.Context, source, target string, creds SMBCredentials, opts ...string) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) lazyUnmount(ctx context.Context, target string) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) getMpathDeviceForWWN(ctx context.Context, wwn string) (*MpathDevice, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) blockDevSetRO(ctx context.Context, device string, readOnly bool) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) blockDevGetRO(ctx context.Context, device string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (fs *FS) cleanupDeviceForWWN(ctx context.Context, wwn string) (*DeviceCleanupReport, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) isPMEMDevice(ctx context.Context, device string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (fs *FS) mountDAX(ctx context.Context, source, target, fsType string, opts ...string) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) getISCSITargetsFromByPath(ctx context.Context) ([]ISCSITargetDevice, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) fcTargetWWPNLUNToDevicePath(ctx context.Context, wwpn string, lunID int) (map[string]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) rescanSCSIHostsForLUNs(ctx context.Context, targets []string, luns []string) (*RescanReport, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) setTargetPermissions(ctx context.Context, target string, uid, gid int, mode os.FileMode, recursive bool) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) setTargetImmutable(ctx context.Context, target string, immutable bool) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) getLoopDeviceForFile(ctx context.Context, file string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (fs *FS) attachLoopDevice(ctx context.Context, file string, readOnly bool) (string, bool, error) {
+	return "", false, errors.New("not implemented")
+}
+
+func (fs *FS) detachLoopDevice(ctx context.Context, device string) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) formatAndMountFile(ctx context.Context, file, target, fsType string, opts ...string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (fs *FS) mountTmpfs(ctx context.Context, target string, sizeBytes int64, mode os.FileMode, opts ...string) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) getMultipathKind(ctx context.Context, wwn string) (MultipathKind, error) {
+	return "", errors.New("not implemented")
+}
+
+func (fs *FS) fsHealthCheck(ctx context.Context, mountpoint string) (*FsHealth, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) checkClusterStack(ctx context.Context, fsType string, args ...string) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) getDeviceTopology(ctx context.Context, device string) (*DeviceTopology, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) getBlockDeviceHolders(ctx context.Context, device string) ([]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) getNVMePathStates(ctx context.Context, nqn string) ([]NVMePathState, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) getHostSCSIHosts(ctx context.Context) ([]SCSIHost, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) dmNameToDevPath(ctx context.Context, name string) (*DMDevice, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) devPathToDMName(ctx context.Context, devPath string) (*DMDevice, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) getDevicePathsForMountPoint(ctx context.Context, target string) ([]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) getNFSCapabilities(ctx context.Context) (*NFSCapabilities, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) getISCSIHostsForTargetPortal(ctx context.Context, portal string) ([]ISCSIPortalHost, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) removeBlockDeviceWithOptions(ctx context.Context, blockDevicePath string, opts RemoveBlockDeviceOptions) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) getNativeMultipathSetting(ctx context.Context) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (fs *FS) subscribeMountChanges(ctx context.Context) (<-chan MountEvent, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) getDeviceForPublishPath(ctx context.Context, publishPath string) (*PublishPathDevice, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) getFSGeometry(ctx context.Context, path string) (*FSGeometry, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) shrinkFS(ctx context.Context, device string, newSizeBytes int64) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) multipathReconfigure(ctx context.Context) (*MultipathdResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) reinstatePath(ctx context.Context, device string) (*MultipathdResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) failPath(ctx context.Context, device string) (*MultipathdResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (fs *FS) verifyDeviceReadable(ctx context.Context, device string, offset, length int64) error {
+	return errors.New("not implemented")
+}
+
+func (fs *FS) deviceWWN(device string) (string, error) {
+	return "", errors.New("not implemented")
 }
@@ -0,0 +1,98 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"strings"
+	"sync"
+)
+
+// conflictingMountOptions are pairs of mount options that override each
+// other, in addition to an option and its no prefixed form, e.g. atime
+// and noatime.
+var conflictingMountOptions = map[string]string{
+	"ro":    "rw",
+	"rw":    "ro",
+	"hard":  "soft",
+	"soft":  "hard",
+	"sync":  "async",
+	"async": "sync",
+}
+
+// mountOptionDefaults holds the default mount options per filesystem
+// type. The zero value has no defaults.
+type mountOptionDefaults struct {
+	mu   sync.RWMutex
+	opts map[string][]string
+}
+
+// set replaces the default options of fsType, or removes them if none are
+// given.
+func (d *mountOptionDefaults) set(fsType string, opts ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(opts) == 0 {
+		delete(d.opts, fsType)
+		return
+	}
+	if d.opts == nil {
+		d.opts = make(map[string][]string)
+	}
+	d.opts[fsType] = append([]string{}, opts...)
+}
+
+// get returns the default options of fsType.
+func (d *mountOptionDefaults) get(fsType string) []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return append([]string(nil), d.opts[fsType]...)
+}
+
+// merge returns opts followed by the default options of fsType that are
+// not overridden by opts.
+func (d *mountOptionDefaults) merge(fsType string, opts []string) []string {
+	if fsType == "" {
+		return opts
+	}
+	defaults := d.get(fsType)
+	if len(defaults) == 0 {
+		return opts
+	}
+	merged := opts[:len(opts):len(opts)]
+	for _, def := range defaults {
+		if !mountOptionOverridden(def, opts) {
+			merged = append(merged, def)
+		}
+	}
+	return merged
+}
+
+// mountOptionOverridden returns true if opts set the option of def, e.g.
+// vers=4 for vers=3, or an option that conflicts with it, e.g. soft for
+// hard or atime for noatime.
+func mountOptionOverridden(def string, opts []string) bool {
+	name := mountOptionName(def)
+	for _, opt := range opts {
+		o := mountOptionName(opt)
+		if o == name || o == "no"+name || name == "no"+o || conflictingMountOptions[name] == o {
+			return true
+		}
+	}
+	return false
+}
+
+// mountOptionName returns the name of a mount option, without its value.
+func mountOptionName(opt string) string {
+	name, _, _ := strings.Cut(opt, "=")
+	return name
+}
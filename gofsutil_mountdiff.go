@@ -0,0 +1,110 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// MountChange is a mount whose entry changed between two snapshots of the
+// mount table.
+type MountChange struct {
+	Before Info `json:"before" yaml:"before"`
+	After  Info `json:"after" yaml:"after"`
+}
+
+// MountDiff are the differences between two snapshots of the mount table.
+type MountDiff struct {
+	// Added are the mounts only in the second snapshot.
+	Added []Info `json:"added" yaml:"added"`
+	// Removed are the mounts only in the first snapshot.
+	Removed []Info `json:"removed" yaml:"removed"`
+	// Changed are the mounts in both snapshots with a different device,
+	// source, type, options or kind.
+	Changed []MountChange `json:"changed" yaml:"changed"`
+}
+
+// Empty returns true if the snapshots have the same mounts.
+func (d MountDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// MarshalJSON encodes the mount with the field names of its struct tags.
+// Options are always encoded as an array, empty if there are none, and
+// the device number is left out if it is not known.
+func (i Info) MarshalJSON() ([]byte, error) {
+	type info Info
+	v := struct {
+		info
+		DevID *DevID `json:"devID,omitempty"`
+	}{info: info(i)}
+	if v.Opts == nil {
+		v.Opts = []string{}
+	}
+	if !i.DevID.IsZero() {
+		v.DevID = &i.DevID
+	}
+	return json.Marshal(v)
+}
+
+// MarshalJSON encodes the device mount information with the field names of
+// its struct tags. Device names are always encoded as an array, empty if
+// there are none.
+func (d DeviceMountInfo) MarshalJSON() ([]byte, error) {
+	type deviceMountInfo DeviceMountInfo
+	v := deviceMountInfo(d)
+	if v.DeviceNames == nil {
+		v.DeviceNames = []string{}
+	}
+	return json.Marshal(v)
+}
+
+// DiffMounts returns the mounts added, removed and changed between the
+// snapshots before and after, e.g. results of GetMounts. Mounts are
+// matched by path; mounts stacked on the same path are matched in the
+// order they were mounted.
+func DiffMounts(before, after []Info) MountDiff {
+	var diff MountDiff
+	remaining := make(map[string][]int)
+	for i, m := range before {
+		remaining[m.Path] = append(remaining[m.Path], i)
+	}
+	matched := make([]bool, len(before))
+	for _, m := range after {
+		indexes := remaining[m.Path]
+		if len(indexes) == 0 {
+			diff.Added = append(diff.Added, m)
+			continue
+		}
+		i := indexes[0]
+		remaining[m.Path] = indexes[1:]
+		matched[i] = true
+		if !sameMount(before[i], m) {
+			diff.Changed = append(diff.Changed, MountChange{Before: before[i], After: m})
+		}
+	}
+	for i, m := range before {
+		if !matched[i] {
+			diff.Removed = append(diff.Removed, m)
+		}
+	}
+	return diff
+}
+
+// sameMount returns true if the mounts a and b have the same fields.
+func sameMount(a, b Info) bool {
+	return a.Device == b.Device && a.Path == b.Path && a.Source == b.Source &&
+		a.Type == b.Type && a.Kind == b.Kind && a.DevID == b.DevID &&
+		slices.Equal(a.Opts, b.Opts)
+}
@@ -0,0 +1,57 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfoJSON(t *testing.T) {
+	b, err := json.Marshal(Info{Device: "/dev/sdc", Path: "/mnt", Type: "ext4", Kind: MountKindBlock})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"device":"/dev/sdc","path":"/mnt","source":"","type":"ext4","opts":[],"kind":"block"}`, string(b))
+
+	var info Info
+	require.NoError(t, json.Unmarshal(b, &info))
+	assert.Equal(t, Info{Device: "/dev/sdc", Path: "/mnt", Type: "ext4", Opts: []string{}, Kind: MountKindBlock}, info)
+
+	b, err = json.Marshal(&DeviceMountInfo{MPathName: "mpatha", MountPoint: "/mnt", WWN: "60000970"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"deviceNames":[],"mpathName":"mpatha","ppathName":"","mountPoint":"/mnt","wwn":"60000970"}`, string(b))
+}
+
+func TestDiffMounts(t *testing.T) {
+	before := []Info{
+		{Device: "/dev/sda", Path: "/mnt/a", Type: "ext4", Opts: []string{"rw"}},
+		{Device: "/dev/sdb", Path: "/mnt/b", Type: "xfs", Opts: []string{"rw"}},
+		{Device: "/dev/sdc", Path: "/mnt/c", Type: "ext4"},
+		{Device: "/dev/sdd", Path: "/mnt/c", Type: "ext4"},
+	}
+	after := []Info{
+		{Device: "/dev/sda", Path: "/mnt/a", Type: "ext4", Opts: []string{"ro"}},
+		{Device: "/dev/sdc", Path: "/mnt/c", Type: "ext4"},
+		{Device: "/dev/sde", Path: "/mnt/e", Type: "xfs"},
+	}
+	diff := DiffMounts(before, after)
+	assert.Equal(t, []Info{after[2]}, diff.Added)
+	assert.Equal(t, []Info{before[1], before[3]}, diff.Removed)
+	assert.Equal(t, []MountChange{{Before: before[0], After: after[0]}}, diff.Changed)
+	assert.False(t, diff.Empty())
+
+	assert.True(t, DiffMounts(before, before).Empty())
+	assert.Equal(t, before, DiffMounts(before, nil).Removed)
+}
@@ -0,0 +1,152 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWWNFromDMUUIDAndWWID(t *testing.T) {
+	w, ok := wwnFromDMUUID("mpath-360000970000120001263533030313434")
+	assert.True(t, ok)
+	assert.Equal(t, CanonicalWWN("60000970000120001263533030313434"), w)
+	_, ok = wwnFromDMUUID("LVM-abcdef")
+	assert.False(t, ok)
+
+	w, ok = wwnFromWWID("naa.68ccf098001111a2222b3d4444a1b23c")
+	assert.True(t, ok)
+	assert.Equal(t, CanonicalWWN("68ccf098001111a2222b3d4444a1b23c"), w)
+	// The NGUID of an NVMe namespace is converted to the volume WWN.
+	w, ok = wwnFromWWID("eui.1111a2222b3d44448ccf096800a1b23c")
+	assert.True(t, ok)
+	assert.Equal(t, CanonicalWWN("68ccf098001111a2222b3d4444a1b23c"), w)
+	_, ok = wwnFromWWID("t10.ATA     QEMU HARDDISK")
+	assert.False(t, ok)
+}
+
+func TestFillDeviceMountInfo(t *testing.T) {
+	tmp := t.TempDir()
+	writeAttr := func(path, value string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(value+"\n"), 0o600))
+	}
+	mountinfo := "40 22 253:3 / /var/lib/kubelet/plugins/vol-1 rw,relatime shared:30 - xfs /dev/mapper/mpatha rw,nouuid\n"
+	writeAttr(filepath.Join(tmp, "proc", "self", "mountinfo"), mountinfo)
+	writeAttr(filepath.Join(tmp, "sys", "block", "dm-3", "dm", "name"), "mpatha")
+	writeAttr(filepath.Join(tmp, "sys", "block", "dm-3", "dm", "uuid"), "mpath-360000970000120001263533030313434")
+	writeAttr(filepath.Join(tmp, "sys", "block", "sdc", "device", "wwid"), "naa.68ccf098001111a2222b3d4444a1b23c")
+	writeAttr(filepath.Join(tmp, "sys", "block", "nvme0n1", "wwid"), "eui.1111a2222b3d44448ccf096800a1b23c")
+
+	fs := NewFS(FSOptions{SysRoot: filepath.Join(tmp, "sys"), ProcRoot: filepath.Join(tmp, "proc")})
+	ctx := context.Background()
+
+	info := &DeviceMountInfo{
+		DeviceNames: []string{"sda", "sdb"},
+		MPathName:   "mpatha",
+		MountPoint:  "/var/lib/kubelet/plugins/vol-1",
+	}
+	fs.fillDeviceMountInfo(ctx, info)
+	assert.Equal(t, "xfs", info.FsType)
+	assert.Equal(t, []string{"rw", "relatime"}, info.MountOpts)
+	assert.Equal(t, "mpath-360000970000120001263533030313434", info.DMUUID)
+	assert.Equal(t, "60000970000120001263533030313434", info.WWN)
+
+	info = &DeviceMountInfo{DeviceNames: []string{"sdc"}}
+	fs.fillDeviceMountInfo(ctx, info)
+	assert.Empty(t, info.FsType)
+	assert.Empty(t, info.DMUUID)
+	assert.Equal(t, "68ccf098001111a2222b3d4444a1b23c", info.WWN)
+
+	info = &DeviceMountInfo{DeviceNames: []string{"nvme0n1"}}
+	fs.fillDeviceMountInfo(ctx, info)
+	assert.Equal(t, "68ccf098001111a2222b3d4444a1b23c", info.WWN)
+}
+
+func TestMountInfoPath(t *testing.T) {
+	assert.Equal(t, "/proc/self/mountinfo", NewFS(FSOptions{}).mountInfoPath())
+	assert.Equal(t, "/proc/1/mountinfo", NewFS(FSOptions{HostMountNamespace: true}).mountInfoPath())
+	fs := NewFS(FSOptions{HostMountNamespace: true, HostNamespacePID: 7, ProcRoot: "/noderoot/proc"})
+	assert.Equal(t, "/noderoot/proc/7/mountinfo", fs.mountInfoPath())
+}
+
+func TestGetMpathNameFromDevice(t *testing.T) {
+	sysRoot := t.TempDir()
+	classBlock := filepath.Join(sysRoot, "class", "block")
+	require.NoError(t, os.MkdirAll(filepath.Join(classBlock, "sdc", "holders", "dm-3"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(classBlock, "sdd", "holders", "dm-4"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(classBlock, "sde", "holders"), 0o750))
+	writeSysfsAttrs(t, filepath.Join(classBlock, "dm-3", "dm"), map[string]string{
+		"name": "mpatha", "uuid": "mpath-3600601601234",
+	})
+	writeSysfsAttrs(t, filepath.Join(classBlock, "dm-4", "dm"), map[string]string{
+		"name": "vg-lv", "uuid": "LVM-abc",
+	})
+	fs := NewFS(FSOptions{SysRoot: sysRoot})
+	ctx := context.Background()
+
+	for device, want := range map[string]string{"sdc": "mpatha", "/dev/sdc": "mpatha", "sdd": "", "sde": ""} {
+		name, err := fs.GetMpathNameFromDevice(ctx, device)
+		require.NoError(t, err)
+		assert.Equal(t, want, name, device)
+	}
+}
+
+func TestLsblkMpathName(t *testing.T) {
+	output := `NAME="sdb" MAJ:MIN="8:16" RM="0" SIZE="8G" RO="0" TYPE="disk" MOUNTPOINT=""
+NAME="mpathb" MAJ:MIN="253:1" RM="0" SIZE="8G" RO="0" TYPE="mpath" MOUNTPOINT=""
+NAME="sdc" MAJ:MIN="8:32" RM="0" SIZE="8G" RO="0" TYPE="disk" MOUNTPOINT=""
+NAME="mpatha" MAJ:MIN="253:0" RM="0" SIZE="8G" RO="0" TYPE="mpath" MOUNTPOINT=""
+NAME="sdd" MAJ:MIN="8:48" RM="0" SIZE="8G" RO="0" TYPE="disk" MOUNTPOINT=""
+`
+	assert.Equal(t, "mpatha", lsblkMpathName(output, "sdc"))
+	assert.Equal(t, "mpathb", lsblkMpathName(output, "sdb"))
+	assert.Empty(t, lsblkMpathName(output, "sdd"))
+	assert.Empty(t, lsblkMpathName(output, "sdx"))
+}
+
+func TestGetDevicePathsForMountPoint(t *testing.T) {
+	procRoot := t.TempDir()
+	devRoot := t.TempDir()
+	mountinfo := "3000 29 0:5 /sdc /var/lib/kubelet/pods/p1/volumeDevices/publish/vol-1 rw,nosuid - devtmpfs udev rw,size=8G\n" +
+		"3001 29 0:5 /dm-3 /var/lib/kubelet/plugins/staging/vol-2 rw,nosuid - devtmpfs udev rw,size=8G\n" +
+		"3002 29 253:3 / /var/lib/kubelet/pods/p2/volumes/vol-2/mount rw,relatime - xfs /dev/mapper/mpatha rw\n" +
+		"3003 29 0:5 / /dev rw,nosuid - devtmpfs udev rw,size=8G\n"
+	require.NoError(t, os.MkdirAll(filepath.Join(procRoot, "self"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(procRoot, "self", "mountinfo"), []byte(mountinfo), 0o600))
+	fs := NewFS(FSOptions{ProcRoot: procRoot, DevRoot: devRoot})
+	ctx := context.Background()
+
+	paths, err := fs.GetDevicePathsForMountPoint(ctx, "/var/lib/kubelet/pods/p1/volumeDevices/publish/vol-1/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(devRoot, "sdc")}, paths)
+
+	paths, err = fs.GetDevicePathsForMountPoint(ctx, "/var/lib/kubelet/plugins/staging/vol-2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(devRoot, "dm-3")}, paths)
+
+	paths, err = fs.GetDevicePathsForMountPoint(ctx, "/var/lib/kubelet/pods/p2/volumes/vol-2/mount")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/dev/mapper/mpatha"}, paths)
+
+	_, err = fs.GetDevicePathsForMountPoint(ctx, "/dev")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,116 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// MountKind is the kind of a mount.
+type MountKind string
+
+const (
+	// MountKindBlock is a filesystem on a block device, or a block
+	// device bind mounted onto a file.
+	MountKindBlock MountKind = "block"
+	// MountKindNFS is an NFS mount.
+	MountKindNFS MountKind = "nfs"
+	// MountKindOverlay is an overlay filesystem, e.g. a container root.
+	MountKindOverlay MountKind = "overlay"
+	// MountKindTmpfs is a tmpfs filesystem.
+	MountKindTmpfs MountKind = "tmpfs"
+	// MountKindSwap is an active swap device or file.
+	MountKindSwap MountKind = "swap"
+	// MountKindOther is any other mount, e.g. proc, sysfs or cgroup.
+	MountKindOther MountKind = "other"
+)
+
+// mountTableKinds are the kinds of the entries of the mount table, i.e.
+// all the kinds but swaps.
+var mountTableKinds = []MountKind{MountKindBlock, MountKindNFS, MountKindOverlay, MountKindTmpfs, MountKindOther}
+
+var nfsFSTypeRegex = regexp.MustCompile(`(?i)^nfs\d?$`)
+
+// entryMountKind returns the kind of a mount table entry.
+func entryMountKind(e Entry) MountKind {
+	switch {
+	case e.FSType == "swap":
+		return MountKindSwap
+	case nfsFSTypeRegex.MatchString(e.FSType):
+		return MountKindNFS
+	case e.FSType == "overlay":
+		return MountKindOverlay
+	case e.FSType == "tmpfs":
+		return MountKindTmpfs
+	case e.FSType == "devtmpfs" && e.Root != "" && e.Root != "/":
+		// A device node bind mounted elsewhere, e.g. a raw block volume.
+		return MountKindBlock
+	case strings.HasPrefix(e.MountSource, "/dev/"):
+		return MountKindBlock
+	}
+	return MountKindOther
+}
+
+// filterMountsByKind returns the mounts of the given kinds, all of them if
+// no kind is given.
+func filterMountsByKind(mounts []Info, kinds ...MountKind) []Info {
+	result := make([]Info, 0)
+	for _, m := range mounts {
+		if wantsMountKind(m.Kind, kinds) {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// wantsMountKind returns true if kind is in kinds, or kinds is empty.
+func wantsMountKind(kind MountKind, kinds []MountKind) bool {
+	if len(kinds) == 0 {
+		return true
+	}
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// parseProcSwaps parses /proc/swaps into mounts with an empty Path.
+//
+//	Filename                Type            Size    Used    Priority
+//	/dev/dm-1               partition       8388604 0       -2
+//	/swapfile               file            2097148 0       -3
+func parseProcSwaps(r io.Reader) ([]Info, error) {
+	swaps := make([]Info, 0)
+	scan := bufio.NewScanner(r)
+	for scan.Scan() {
+		fields := strings.Fields(scan.Text())
+		if len(fields) < 2 || fields[0] == "Filename" {
+			continue
+		}
+		// Spaces in the file name are escaped as \040.
+		name := unescapeMountPath(fields[0])
+		swaps = append(swaps, Info{
+			Device: name,
+			Source: name,
+			Type:   "swap",
+			Opts:   []string{fields[1]},
+			Kind:   MountKindSwap,
+		})
+	}
+	return swaps, scan.Err()
+}
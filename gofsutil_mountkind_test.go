@@ -0,0 +1,122 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mountKindMountInfo = `22 1 253:0 / / rw,relatime shared:1 - xfs /dev/mapper/rhel-root rw
+23 22 0:5 / /dev rw,nosuid shared:2 - devtmpfs devtmpfs rw,size=4096k
+24 22 0:18 / /run rw,nosuid,nodev shared:23 - tmpfs tmpfs rw,mode=755
+25 22 0:3 / /proc rw,nosuid,nodev,noexec,relatime shared:5 - proc proc rw
+26 22 0:52 / /var/lib/containers/overlay/merged rw,relatime - overlay overlay rw,lowerdir=/l,upperdir=/u,workdir=/w
+27 22 0:53 / /mnt/nfs rw,relatime shared:60 - nfs4 10.0.0.1:/export rw,vers=4.1
+28 22 0:5 /sdb /var/lib/kubelet/plugins/volumeDevices/pvc-1 rw,nosuid shared:2 - devtmpfs devtmpfs rw,size=4096k
+`
+
+const mountKindSwaps = `Filename				Type		Size		Used		Priority
+/dev/dm-1                               partition	8388604		0		-2
+/swap\040file                           file		2097148		0		-3
+`
+
+func TestGetMountsByKind(t *testing.T) {
+	procRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(procRoot, "self"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(procRoot, "self", "mountinfo"), []byte(mountKindMountInfo), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(procRoot, "swaps"), []byte(mountKindSwaps), 0o600))
+	fs := NewFS(FSOptions{ProcRoot: procRoot})
+	ctx := context.Background()
+
+	kinds := func(mounts []Info) map[string]MountKind {
+		result := map[string]MountKind{}
+		for _, m := range mounts {
+			result[m.Path+m.Device] = m.Kind
+		}
+		return result
+	}
+
+	all, err := fs.GetMountsByKind(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]MountKind{
+		"//dev/mapper/rhel-root":   MountKindBlock,
+		"/devdevtmpfs":             MountKindOther,
+		"/runtmpfs":                MountKindTmpfs,
+		"/procproc":                MountKindOther,
+		"/mnt/nfs10.0.0.1:/export": MountKindNFS,
+		"/dev/dm-1":                MountKindSwap,
+		"/swap file":               MountKindSwap,
+		"/var/lib/containers/overlay/mergedoverlay":            MountKindOverlay,
+		"/var/lib/kubelet/plugins/volumeDevices/pvc-1devtmpfs": MountKindBlock,
+	}, kinds(all))
+
+	overlays, err := fs.GetMountsByKind(ctx, MountKindOverlay, MountKindNFS)
+	require.NoError(t, err)
+	assert.Len(t, overlays, 2)
+
+	// GetMounts keeps its filtering and now reports the kind.
+	mounts, err := fs.GetMounts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]MountKind{
+		"//dev/mapper/rhel-root":   MountKindBlock,
+		"/devdevtmpfs":             MountKindOther,
+		"/mnt/nfs10.0.0.1:/export": MountKindNFS,
+		"/var/lib/kubelet/plugins/volumeDevices/pvc-1devtmpfs": MountKindBlock,
+	}, kinds(mounts))
+
+	// The swaps are only read when requested.
+	require.NoError(t, os.Remove(filepath.Join(procRoot, "swaps")))
+	tmpfs, err := fs.GetMountsByKind(ctx, MountKindTmpfs)
+	require.NoError(t, err)
+	assert.Len(t, tmpfs, 1)
+	_, err = fs.GetMountsByKind(ctx, MountKindSwap)
+	assert.Error(t, err)
+}
+
+func TestGetMountsByDevID(t *testing.T) {
+	procRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(procRoot, "self"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(procRoot, "self", "mountinfo"), []byte(mountKindMountInfo), 0o600))
+	fs := NewFS(FSOptions{ProcRoot: procRoot})
+	ctx := context.Background()
+
+	mounts, err := fs.GetMountsByDevID(ctx, 253, 0)
+	require.NoError(t, err)
+	require.Len(t, mounts, 1)
+	assert.Equal(t, "/", mounts[0].Path)
+	assert.Equal(t, "253:0", mounts[0].DevID.String())
+
+	mounts, err = fs.GetMountsByDevID(ctx, 0, 5)
+	require.NoError(t, err)
+	require.Len(t, mounts, 2)
+	assert.Equal(t, "/dev", mounts[0].Path)
+	assert.Equal(t, "/var/lib/kubelet/plugins/volumeDevices/pvc-1", mounts[1].Path)
+
+	mounts, err = fs.GetMountsByDevID(ctx, 8, 16)
+	require.NoError(t, err)
+	assert.Empty(t, mounts)
+
+	for s, ok := range map[string]bool{"8:16": true, "8": false, "a:1": false, "1:-1": false} {
+		_, valid := parseDevID(s)
+		assert.Equal(t, ok, valid, s)
+	}
+}
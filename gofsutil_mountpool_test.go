@@ -0,0 +1,109 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMountTableFS(t testing.TB) *FS {
+	procRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(procRoot, "self"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(procRoot, "self", "mountinfo"), []byte(mountKindMountInfo), 0o600))
+	return NewFS(FSOptions{ProcRoot: procRoot})
+}
+
+func TestAppendFields(t *testing.T) {
+	line := " 22 1\t253:0  / /  rw "
+	assert.Equal(t, strings.Fields(line), appendFields(nil, line))
+	assert.Equal(t, []string{"a", "b"}, appendFields([]string{"a"}, "b"))
+	assert.Empty(t, appendFields(nil, " \t "))
+}
+
+func TestGetMountsInto(t *testing.T) {
+	fs := newMountTableFS(t)
+	ctx := context.Background()
+
+	want, err := fs.GetMounts(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, want)
+
+	infos := make([]Info, 1, 64)
+	infos[0] = Info{Path: "/stale"}
+	got, err := fs.GetMountsInto(ctx, infos)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Same(t, &infos[:1][0], &got[0], "capacity of infos not reused")
+
+	// A second read into the returned slice yields the same mounts.
+	got, err = fs.GetMountsInto(ctx, got)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	fs.ProcRoot = t.TempDir()
+	got, err = fs.GetMountsInto(ctx, got)
+	assert.Error(t, err)
+	assert.Empty(t, got)
+}
+
+func BenchmarkGetMounts(b *testing.B) {
+	fs := newMountTableFS(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := fs.GetMounts(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetMountsInto(b *testing.B) {
+	fs := newMountTableFS(b)
+	ctx := context.Background()
+	var infos []Info
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var err error
+		if infos, err = fs.GetMountsInto(ctx, infos); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGetMountsIntoParallel reads the mount table from several
+// goroutines, as node plugins serving concurrent requests do.
+func BenchmarkGetMountsIntoParallel(b *testing.B) {
+	fs := newMountTableFS(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		var infos []Info
+		for pb.Next() {
+			var err error
+			if infos, err = fs.GetMountsInto(ctx, infos); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
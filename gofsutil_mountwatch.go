@@ -0,0 +1,131 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultMountWatchInterval is how often SubscribeMountChanges reads the
+// mount table when the kernel does not signal a change.
+const DefaultMountWatchInterval = 10 * time.Second
+
+// MountEventType is the type of a change of the mount table.
+type MountEventType string
+
+const (
+	// MountAdded is the event of a new mount.
+	MountAdded MountEventType = "added"
+	// MountRemoved is the event of a mount that went away.
+	MountRemoved MountEventType = "removed"
+	// MountChanged is the event of a mount whose entry changed, e.g. when
+	// it was remounted read-only.
+	MountChanged MountEventType = "changed"
+)
+
+// MountEvent is a change of the mount table delivered by
+// SubscribeMountChanges.
+type MountEvent struct {
+	// Type is the type of the change.
+	Type MountEventType
+	// Info is the mount, as it is after the change for MountAdded and
+	// MountChanged and as it was for MountRemoved.
+	Info Info
+	// Previous is the mount before the change for MountChanged.
+	Previous Info
+}
+
+// mountEvents returns the events of the differences of the mount table.
+func mountEvents(diff MountDiff) []MountEvent {
+	events := make([]MountEvent, 0, len(diff.Added)+len(diff.Removed)+len(diff.Changed))
+	for _, m := range diff.Removed {
+		events = append(events, MountEvent{Type: MountRemoved, Info: m})
+	}
+	for _, c := range diff.Changed {
+		events = append(events, MountEvent{Type: MountChanged, Info: c.After, Previous: c.Before})
+	}
+	for _, m := range diff.Added {
+		events = append(events, MountEvent{Type: MountAdded, Info: m})
+	}
+	return events
+}
+
+// watchMounts reads the mount table with getMounts and returns a channel
+// of its changes, read again each time wait returns true and at least
+// every interval. wait returns false when it gives up waiting for a
+// change, and should not block long so that ctx is checked. The channel
+// is closed once ctx is done, after done, if not nil, is called.
+func watchMounts(
+	ctx context.Context,
+	getMounts func(context.Context) ([]Info, error),
+	wait func(context.Context) bool,
+	interval time.Duration,
+	done func(),
+) (<-chan MountEvent, error) {
+	if interval <= 0 {
+		interval = DefaultMountWatchInterval
+	}
+	mounts, err := getMounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan MountEvent)
+	go func() {
+		defer close(events)
+		if done != nil {
+			defer done()
+		}
+		last := time.Now()
+		for {
+			changed := wait(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			if !changed && time.Since(last) < interval {
+				continue
+			}
+			last = time.Now()
+			current, err := getMounts(ctx)
+			if err != nil {
+				log.WithError(err).Warn("failed to read the mount table, retrying")
+				continue
+			}
+			for _, e := range mountEvents(DiffMounts(mounts, current)) {
+				select {
+				case events <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+			mounts = current
+		}
+	}()
+	return events, nil
+}
+
+// sleepWait returns a wait function for watchMounts that waits for d,
+// or until ctx is done, and never reports a change.
+func sleepWait(d time.Duration) func(context.Context) bool {
+	return func(ctx context.Context) bool {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+		return false
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// mountWatchPollTimeout is how long a poll of the mountinfo file waits
+// for a change before ctx is checked again.
+const mountWatchPollTimeout = 500 * time.Millisecond
+
+// subscribeMountChanges watches the mountinfo file, which the kernel
+// marks with POLLPRI when the mount table of the namespace changes.
+func (fs *FS) subscribeMountChanges(ctx context.Context) (<-chan MountEvent, error) {
+	f, err := os.Open(fs.mountInfoPath())
+	if err != nil {
+		return nil, err
+	}
+	fallback := sleepWait(mountWatchPollTimeout)
+	wait := func(ctx context.Context) bool {
+		fds := []unix.PollFd{{Fd: int32(f.Fd()), Events: unix.POLLPRI}} // #nosec G115
+		n, err := unix.Poll(fds, int(mountWatchPollTimeout.Milliseconds()))
+		if err != nil && !errors.Is(err, unix.EINTR) {
+			return fallback(ctx)
+		}
+		if n == 0 {
+			return false
+		}
+		if fds[0].Revents&(unix.POLLPRI|unix.POLLERR) == 0 {
+			// A regular file, e.g. in tests, is always readable and
+			// never signals changes.
+			return fallback(ctx)
+		}
+		return true
+	}
+	events, err := watchMounts(ctx, fs.getMountTable, wait, fs.MountWatchInterval, func() { f.Close() })
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,99 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nextMountEvent returns the next event of events, failing the test if
+// none arrives in time.
+func nextMountEvent(t *testing.T, events <-chan MountEvent) MountEvent {
+	t.Helper()
+	select {
+	case e, ok := <-events:
+		require.True(t, ok, "mount events closed")
+		return e
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no mount event")
+	}
+	return MountEvent{}
+}
+
+func TestMountEvents(t *testing.T) {
+	a := Info{Device: "/dev/sdb", Path: "/mnt/a", Type: "ext4", Opts: []string{"rw"}}
+	b := Info{Device: "/dev/sdc", Path: "/mnt/b", Type: "xfs", Opts: []string{"rw"}}
+	roA := a
+	roA.Opts = []string{"ro"}
+	events := mountEvents(DiffMounts([]Info{a, b}, []Info{roA, {Device: "/dev/sdd", Path: "/mnt/c"}}))
+	assert.Equal(t, []MountEvent{
+		{Type: MountRemoved, Info: b},
+		{Type: MountChanged, Info: roA, Previous: a},
+		{Type: MountAdded, Info: Info{Device: "/dev/sdd", Path: "/mnt/c"}},
+	}, events)
+	assert.Empty(t, mountEvents(DiffMounts([]Info{a}, []Info{a})))
+}
+
+func TestSubscribeMountChanges(t *testing.T) {
+	procRoot := t.TempDir()
+	mountinfo := filepath.Join(procRoot, "self", "mountinfo")
+	root := "21 1 8:1 / / rw,relatime - ext4 /dev/sda1 rw\n"
+	data := "3002 21 8:16 / /mnt/data rw,relatime - xfs /dev/sdb rw\n"
+	secret := "3003 3002 0:52 / /mnt/data/secret rw,relatime - tmpfs tmpfs rw\n"
+	require.NoError(t, os.MkdirAll(filepath.Dir(mountinfo), 0o750))
+	require.NoError(t, os.WriteFile(mountinfo, []byte(root), 0o600))
+	fs := NewFS(FSOptions{ProcRoot: procRoot, MountWatchInterval: 10 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := fs.subscribeMountChanges(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(mountinfo, []byte(root+data), 0o600))
+	e := nextMountEvent(t, events)
+	assert.Equal(t, MountAdded, e.Type)
+	assert.Equal(t, "/mnt/data", e.Info.Path)
+	assert.Equal(t, "/dev/sdb", e.Info.Device)
+
+	// Mounts that GetMounts filters are reported as well.
+	require.NoError(t, os.WriteFile(mountinfo, []byte(root+data+secret), 0o600))
+	e = nextMountEvent(t, events)
+	assert.Equal(t, MountAdded, e.Type)
+	assert.Equal(t, "/mnt/data/secret", e.Info.Path)
+	assert.Equal(t, MountKindTmpfs, e.Info.Kind)
+
+	require.NoError(t, os.WriteFile(mountinfo, []byte(root), 0o600))
+	e = nextMountEvent(t, events)
+	assert.Equal(t, MountRemoved, e.Type)
+	assert.Equal(t, "/mnt/data", e.Info.Path)
+	e = nextMountEvent(t, events)
+	assert.Equal(t, MountRemoved, e.Type)
+	assert.Equal(t, "/mnt/data/secret", e.Info.Path)
+
+	cancel()
+	for range events {
+	}
+
+	_, err = NewFS(FSOptions{ProcRoot: t.TempDir()}).subscribeMountChanges(context.Background())
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
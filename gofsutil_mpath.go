@@ -0,0 +1,101 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMPathMinPaths is the default number of active paths a
+	// multipath device needs before it is formatted.
+	DefaultMPathMinPaths = 1
+	// DefaultMPathTimeout is the default time to wait for the paths of a
+	// multipath device.
+	DefaultMPathTimeout = 30 * time.Second
+	// DefaultMPathPollInterval is the default interval between checks of
+	// the paths of a multipath device.
+	DefaultMPathPollInterval = time.Second
+)
+
+// MPathFormatOptions are the options of FormatAndMountMPath.
+type MPathFormatOptions struct {
+	// MinPaths is the number of active paths required before formatting,
+	// DefaultMPathMinPaths if zero.
+	MinPaths int
+	// Timeout is how long to wait for MinPaths active paths,
+	// DefaultMPathTimeout if zero.
+	Timeout time.Duration
+	// PollInterval is the interval between path checks,
+	// DefaultMPathPollInterval if zero.
+	PollInterval time.Duration
+	// Reconfigure runs multipathd reconfigure before waiting for paths.
+	Reconfigure bool
+	// MountOptions are the options passed to the mount.
+	MountOptions []string
+}
+
+func (o MPathFormatOptions) withDefaults() MPathFormatOptions {
+	if o.MinPaths <= 0 {
+		o.MinPaths = DefaultMPathMinPaths
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultMPathTimeout
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = DefaultMPathPollInterval
+	}
+	return o
+}
+
+// mpathDevicePath returns the device path of a multipath device given
+// either its name, e.g. mpatha, or a device path.
+func mpathDevicePath(mpathDevice string) string {
+	if strings.HasPrefix(mpathDevice, "/") {
+		return filepath.Clean(mpathDevice)
+	}
+	return "/dev/mapper/" + mpathDevice
+}
+
+// MpathDevice is a multipath device.
+type MpathDevice struct {
+	// DMNode is the device mapper node, e.g. /dev/dm-3.
+	DMNode string
+	// Name is the multipath map name, e.g. mpatha or the WWID when
+	// user friendly names are disabled.
+	Name string
+	// MapperPath is the device path of the map, e.g. /dev/mapper/mpatha.
+	MapperPath string
+	// UUID is the device mapper UUID, e.g. mpath-3<wwn>.
+	UUID string
+	// WWN is the WWN of the volume.
+	WWN CanonicalWWN
+}
+
+// MultipathKind is how the paths of a volume are combined into one
+// device.
+type MultipathKind string
+
+const (
+	// MultipathNone is the kind of a volume with a single path device.
+	MultipathNone MultipathKind = "none"
+	// MultipathDM is the kind of a volume with a device mapper multipath
+	// map, e.g. /dev/mapper/mpatha.
+	MultipathDM MultipathKind = "dm"
+	// MultipathNVMeNative is the kind of an NVMe namespace with native
+	// NVMe multipathing (ANA), where the kernel combines the paths into
+	// /dev/nvmeXnY and there is no device mapper map.
+	MultipathNVMeNative MultipathKind = "nvme"
+)
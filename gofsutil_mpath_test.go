@@ -0,0 +1,191 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMPathTestFS returns an FS rooted in a temporary directory holding
+// the multipath device mpatha (dm-3) with the paths sdb, sdc and sdd, of
+// which dmsetup reports sdc as failed.
+func newMPathTestFS(t *testing.T) *FS {
+	tmp := t.TempDir()
+	devDir := filepath.Join(tmp, "dev")
+	require.NoError(t, os.MkdirAll(filepath.Join(devDir, "mapper"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(devDir, "dm-3"), nil, 0o600))
+	require.NoError(t, os.Symlink("../dm-3", filepath.Join(devDir, "mapper", "mpatha")))
+	require.NoError(t, os.WriteFile(filepath.Join(devDir, "dm-4"), nil, 0o600))
+	require.NoError(t, os.Symlink("../dm-4", filepath.Join(devDir, "mapper", "vg-root")))
+
+	sysBlock := filepath.Join(tmp, "sys", "block")
+	for dm, name := range map[string]string{"dm-3": "mpatha", "dm-4": "vg-root"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(sysBlock, dm, "dm"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(sysBlock, dm, "dm", "name"), []byte(name+"\n"), 0o600))
+	}
+	for name, state := range map[string]string{"sdb": "running", "sdc": "running", "sdd": ""} {
+		require.NoError(t, os.MkdirAll(filepath.Join(sysBlock, "dm-3", "slaves", name), 0o755))
+		require.NoError(t, os.MkdirAll(filepath.Join(sysBlock, name, "device"), 0o755))
+		if state != "" {
+			require.NoError(t, os.WriteFile(filepath.Join(sysBlock, name, "device", "state"), []byte(state+"\n"), 0o600))
+		}
+	}
+
+	bin := filepath.Join(tmp, "bin")
+	require.NoError(t, os.MkdirAll(bin, 0o755))
+	dmsetup := "#!/bin/sh\ncase $2 in\n" +
+		"mpatha) echo '0 2097152 multipath 2 0 0 0 2 1 A 0 2 2 8:16 A 0 0 1 8:32 F 3 0 1 E 0 1 2 8:48 A 0 0 1' ;;\n" +
+		"vg-root) echo '0 2097152 linear' ;;\n" +
+		"*) exit 1 ;;\nesac\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "dmsetup"), []byte(dmsetup), 0o700)) // #nosec G306
+	return NewFS(FSOptions{SysRoot: filepath.Join(tmp, "sys"), DevRoot: devDir, ExtraEnv: []string{"PATH=" + bin}})
+}
+
+func TestMPathActivePaths(t *testing.T) {
+	fs := newMPathTestFS(t)
+	ctx := context.Background()
+
+	// The SCSI device of sdc is running but its path is failed.
+	active, err := fs.mpathActivePaths(ctx, "/dev/mapper/mpatha")
+	require.NoError(t, err)
+	assert.Equal(t, 2, active)
+
+	_, err = fs.mpathActivePaths(ctx, "/dev/mapper/mpathb")
+	assert.Error(t, err)
+	_, err = fs.mpathActivePaths(ctx, "/dev/mapper/vg-root")
+	assert.ErrorContains(t, err, "not a multipath device")
+}
+
+func TestFormatAndMountMPathNotReady(t *testing.T) {
+	fs := newMPathTestFS(t)
+
+	err := fs.formatAndMountMPath(context.Background(), "mpatha", "/mnt/target", "xfs", MPathFormatOptions{
+		MinPaths:     3,
+		Timeout:      50 * time.Millisecond,
+		PollInterval: 10 * time.Millisecond,
+	})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Contains(t, err.Error(), "2 of 3")
+}
+
+func TestMPathFormatOptionsDefaults(t *testing.T) {
+	opts := MPathFormatOptions{}.withDefaults()
+	assert.Equal(t, DefaultMPathMinPaths, opts.MinPaths)
+	assert.Equal(t, DefaultMPathTimeout, opts.Timeout)
+	assert.Equal(t, DefaultMPathPollInterval, opts.PollInterval)
+
+	assert.Equal(t, "/dev/mapper/mpatha", mpathDevicePath("mpatha"))
+	assert.Equal(t, "/dev/dm-3", mpathDevicePath("/dev/dm-3"))
+}
+
+func TestGetMpathDeviceForWWN(t *testing.T) {
+	sys := filepath.Join(t.TempDir(), "sys", "block")
+	writeAttr := func(path, value string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(value+"\n"), 0o600))
+	}
+	writeAttr(filepath.Join(sys, "dm-0", "dm", "uuid"), "LVM-abcdef")
+	writeAttr(filepath.Join(sys, "dm-0", "dm", "name"), "vg-root")
+	writeAttr(filepath.Join(sys, "dm-3", "dm", "uuid"), "mpath-360000970000120001263533030313434")
+	writeAttr(filepath.Join(sys, "dm-3", "dm", "name"), "mpatha")
+	writeAttr(filepath.Join(sys, "sda", "device", "wwid"), "naa.60000970000120001263533030313434")
+	fs := NewFS(FSOptions{SysRoot: filepath.Dir(sys)})
+	ctx := context.Background()
+
+	dev, err := fs.GetMpathDeviceForWWN(ctx, "naa.60000970000120001263533030313434")
+	require.NoError(t, err)
+	assert.Equal(t, &MpathDevice{
+		DMNode:     "/dev/dm-3",
+		Name:       "mpatha",
+		MapperPath: "/dev/mapper/mpatha",
+		UUID:       "mpath-360000970000120001263533030313434",
+		WWN:        "60000970000120001263533030313434",
+	}, dev)
+
+	dev, err = fs.GetMpathDeviceForWWN(ctx, "68ccf098001111a2222b3d4444a1b23c")
+	assert.NoError(t, err)
+	assert.Nil(t, dev)
+
+	_, err = fs.GetMpathDeviceForWWN(ctx, "not-a-wwn")
+	assert.Error(t, err)
+}
+
+func TestGetMultipathKind(t *testing.T) {
+	tmp := t.TempDir()
+	sys := filepath.Join(tmp, "sys", "block")
+	dev := filepath.Join(tmp, "dev")
+	writeAttr := func(path, value string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(value+"\n"), 0o600))
+	}
+	link := func(name, target string) {
+		path := filepath.Join(dev, "disk", "by-id", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.Symlink("../../"+target, path))
+	}
+	writeAttr(filepath.Join(sys, "dm-3", "dm", "uuid"), "mpath-360000970000120001263533030313434")
+	writeAttr(filepath.Join(sys, "dm-3", "dm", "name"), "mpatha")
+	// nvme1n1 is a native multipath namespace of a subsystem with the
+	// controllers nvme1 and nvme2, nvme0n1 a namespace of controller nvme0.
+	for _, ctrl := range []string{"nvme1", "nvme2"} {
+		writeAttr(filepath.Join(sys, "nvme1n1", "device", ctrl, "rescan_controller"), "")
+	}
+	writeAttr(filepath.Join(sys, "nvme0n1", "device", "rescan_controller"), "")
+	link("nvme-eui.68ccf098001111a2222b3d4444a1b23c", "nvme1n1")
+	link("nvme-eui.68ccf098005555a6666b3d4444a1b23c", "nvme0n1")
+	link("wwn-0x60000970000120001263533030319999", "sdb")
+	fs := NewFS(FSOptions{SysRoot: filepath.Join(tmp, "sys"), DevRoot: dev})
+	ctx := context.Background()
+
+	for wwn, want := range map[string]MultipathKind{
+		"60000970000120001263533030313434": MultipathDM,
+		"68ccf098001111a2222b3d4444a1b23c": MultipathNVMeNative,
+		"68ccf098005555a6666b3d4444a1b23c": MultipathNone,
+		"60000970000120001263533030319999": MultipathNone,
+	} {
+		kind, err := fs.GetMultipathKind(ctx, wwn)
+		require.NoError(t, err, wwn)
+		assert.Equal(t, want, kind, wwn)
+	}
+	_, err := fs.GetMultipathKind(ctx, "60000970000120001263533030310000")
+	assert.Error(t, err)
+
+	// The controllers of a native multipath namespace are rescanned
+	// before its filesystem is resized.
+	mountpoint, devicePath, err := fs.resizeTargets(ctx, "/mnt", "/dev/nvme1n1", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "/mnt", mountpoint)
+	assert.Equal(t, "/dev/nvme1n1", devicePath)
+	for _, ctrl := range []string{"nvme1", "nvme2"} {
+		buf, err := os.ReadFile(filepath.Join(sys, "nvme1n1", "device", ctrl, "rescan_controller"))
+		require.NoError(t, err)
+		assert.Equal(t, "1", string(buf))
+	}
+	_, _, err = fs.resizeTargets(ctx, "/mnt", "/dev/nvme0n1", "", "")
+	require.NoError(t, err)
+	buf, err := os.ReadFile(filepath.Join(sys, "nvme0n1", "device", "rescan_controller"))
+	require.NoError(t, err)
+	assert.Equal(t, "\n", string(buf))
+}
@@ -0,0 +1,156 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// mpathActivePaths returns the number of paths of the multipath device
+// at devicePath that device mapper reports as active, not the state of
+// the SCSI devices, which stay running while the paths are failed.
+func (fs *FS) mpathActivePaths(ctx context.Context, devicePath string) (int, error) {
+	dev, err := filepath.EvalSymlinks(fs.devPath(devicePath))
+	if err != nil {
+		return 0, err
+	}
+	dm := filepath.Base(dev)
+	if !strings.HasPrefix(dm, "dm-") {
+		return 0, fmt.Errorf("%s is not a device mapper device", devicePath)
+	}
+	name := readSysfsAttr(filepath.Join(fs.sysBlockDir(), dm, "dm", "name"))
+	if name == "" {
+		return 0, fmt.Errorf("no device mapper name of %s in sysfs", devicePath)
+	}
+	out, err := fs.dmsetup(ctx, "status", name)
+	if err != nil {
+		return 0, err
+	}
+	return parseMultipathActivePaths(name, string(out))
+}
+
+// getMpathDeviceForWWN returns the multipath device of the volume with
+// the given WWN by matching the device mapper UUIDs in sysfs. It returns
+// nil if there is no multipath device for the volume.
+func (fs *FS) getMpathDeviceForWWN(_ context.Context, wwn string) (*MpathDevice, error) {
+	w, err := NormalizeWWN(wwn)
+	if err != nil {
+		return nil, err
+	}
+	sysBlockDir := fs.sysBlockDir()
+	entries, err := os.ReadDir(sysBlockDir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", sysBlockDir, err)
+	}
+	for _, e := range entries {
+		dm := e.Name()
+		if !strings.HasPrefix(dm, "dm-") {
+			continue
+		}
+		uuid := readSysfsAttr(filepath.Join(sysBlockDir, dm, "dm", "uuid"))
+		if id, ok := wwnFromDMUUID(uuid); !ok || id != w {
+			continue
+		}
+		name := readSysfsAttr(filepath.Join(sysBlockDir, dm, "dm", "name"))
+		dev := &MpathDevice{
+			DMNode: "/dev/" + dm,
+			Name:   name,
+			UUID:   uuid,
+			WWN:    w,
+		}
+		if name != "" {
+			dev.MapperPath = mpathDevicePath(name)
+		}
+		return dev, nil
+	}
+	return nil, nil
+}
+
+// formatAndMountMPath waits until the multipath device has enough active
+// paths, then formats and mounts it.
+func (fs *FS) formatAndMountMPath(
+	ctx context.Context,
+	mpathDevice, target, fsType string,
+	opts MPathFormatOptions,
+) error {
+	opts = opts.withDefaults()
+	devicePath := mpathDevicePath(mpathDevice)
+	if err := validatePath(devicePath); err != nil {
+		return err
+	}
+	f := log.Fields{
+		"device":   devicePath,
+		"minPaths": opts.MinPaths,
+	}
+
+	if opts.Reconfigure {
+		if _, err := fs.multipathReconfigure(ctx); err != nil {
+			return err
+		}
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		active, err := fs.mpathActivePaths(ctx, devicePath)
+		if err == nil && active >= opts.MinPaths {
+			log.WithFields(f).WithField("activePaths", active).Info("multipath device is ready")
+			break
+		}
+		if err != nil {
+			log.WithFields(f).WithError(err).Debug("failed to check multipath device paths")
+		}
+		select {
+		case <-ticker.C:
+		case <-waitCtx.Done():
+			if err != nil {
+				return fmt.Errorf("multipath device %s is not ready: %v: %w", devicePath, err, waitCtx.Err())
+			}
+			return fmt.Errorf("multipath device %s has %d of %d required active paths: %w",
+				devicePath, active, opts.MinPaths, waitCtx.Err())
+		}
+	}
+	return fs.FormatAndMount(ctx, devicePath, target, fsType, opts.MountOptions...)
+}
+
+// getMultipathKind returns whether the volume with the given WWN uses a
+// device mapper multipath map, native NVMe multipathing or neither.
+func (fs *FS) getMultipathKind(ctx context.Context, wwn string) (MultipathKind, error) {
+	dev, err := fs.getMpathDeviceForWWN(ctx, wwn)
+	if err != nil {
+		return "", err
+	}
+	if dev != nil {
+		return MultipathDM, nil
+	}
+	_, devicePath, err := fs.wwnToDevicePath(ctx, wwn)
+	if err != nil {
+		return "", err
+	}
+	if fs.nvmeNativeMultipath(filepath.Base(devicePath)) {
+		return MultipathNVMeNative, nil
+	}
+	return MultipathNone, nil
+}
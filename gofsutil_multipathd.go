@@ -0,0 +1,161 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// multipathPathRegex matches the path names accepted by multipathd, e.g.
+// sdb, nvme0c1n1 or 8:16.
+var multipathPathRegex = regexp.MustCompile(`^[A-Za-z0-9:]+$`)
+
+// MultipathdResult is the result of a multipathd command run by
+// MultipathReconfigure, ReinstatePath or FailPath.
+type MultipathdResult struct {
+	// Command is the multipathd command, e.g. fail path sdb.
+	Command string `json:"command" yaml:"command"`
+	// Output is the reply of multipathd, e.g. ok.
+	Output string `json:"output" yaml:"output"`
+	// Path is the state of the path after FailPath or ReinstatePath, nil
+	// after MultipathReconfigure or if multipathd did not report it.
+	Path *MultipathPathState `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// MultipathPathState is the state of a path as reported by multipathd
+// show paths.
+type MultipathPathState struct {
+	// Device is the name of the path device, e.g. sdb.
+	Device string `json:"device" yaml:"device"`
+	// DMState is the state of the path in its device-mapper table, active
+	// or failed.
+	DMState string `json:"dmState" yaml:"dmState"`
+	// CheckerState is the state reported by the path checker, e.g. ready
+	// or faulty.
+	CheckerState string `json:"checkerState" yaml:"checkerState"`
+}
+
+// MultipathdHealth reports the state of the multipath daemon.
+type MultipathdHealth struct {
+	// Installed is true if the multipathd binary was found.
+	Installed bool
+	// Running is true if a multipathd process is running.
+	Running bool
+	// Responsive is true if multipathd answered a status request.
+	Responsive bool
+	// Paths is the number of paths multipathd monitors.
+	Paths int
+	// Busy is true if multipathd is busy, e.g. still adding paths.
+	Busy bool
+	// PathStates is the number of paths per path checker state, e.g. up.
+	PathStates map[string]int
+	// Problem describes why multipathd is not healthy.
+	Problem string
+}
+
+// Healthy returns true if multipathd is running and responsive.
+func (h *MultipathdHealth) Healthy() bool {
+	return h.Installed && h.Running && h.Responsive
+}
+
+// parseMultipathdStatus parses the output of multipathd show status, e.g.
+//
+//	path checker states:
+//	up                  4
+//
+//	paths: 4
+//	busy: False
+func parseMultipathdStatus(out string, h *MultipathdHealth) {
+	h.PathStates = make(map[string]int)
+	checkerStates := false
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			checkerStates = false
+			continue
+		}
+		if line == "path checker states:" {
+			checkerStates = true
+			continue
+		}
+		if checkerStates {
+			fields := strings.Fields(line)
+			if len(fields) == 2 {
+				if n, err := strconv.Atoi(fields[1]); err == nil {
+					h.PathStates[fields[0]] = n
+				}
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "paths":
+			if n, err := strconv.Atoi(value); err == nil {
+				h.Paths = n
+			}
+		case "busy":
+			h.Busy = strings.EqualFold(value, "true")
+		}
+	}
+}
+
+// multipathPathName returns the name multipathd knows the path device by,
+// e.g. sdb for /dev/sdb or a /dev/disk/by-id link to it.
+func multipathPathName(device string) (string, error) {
+	name := device
+	if strings.Contains(device, "/") {
+		dev, err := filepath.EvalSymlinks(device)
+		if err != nil {
+			return "", err
+		}
+		name = filepath.Base(dev)
+	}
+	if !multipathPathRegex.MatchString(name) {
+		return "", fmt.Errorf("invalid multipath path device %q", device)
+	}
+	return name, nil
+}
+
+// multipathdReplyFailed returns true if out is the reply of multipathd to
+// a command it did not carry out. Older versions exit with 0 even then.
+func multipathdReplyFailed(out string) bool {
+	out = strings.TrimSpace(out)
+	return out == "fail" || out == "timeout" || strings.HasPrefix(out, "error")
+}
+
+// parseMultipathdPathState returns the state of the path device from the
+// output of multipathd show paths format "%d %t %T", e.g.
+//
+//	dev dm_st  chk_st
+//	sdb active ready
+//	sdc failed faulty
+func parseMultipathdPathState(out, device string) (*MultipathPathState, bool) {
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 3 && fields[0] == device {
+			return &MultipathPathState{Device: fields[0], DMState: fields[1], CheckerState: fields[2]}, true
+		}
+	}
+	return nil, false
+}
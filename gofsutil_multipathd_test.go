@@ -0,0 +1,124 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMultipathdStatus(t *testing.T) {
+	out := `path checker states:
+up                  3
+down                1
+
+paths: 4
+busy: False
+`
+	h := &MultipathdHealth{}
+	parseMultipathdStatus(out, h)
+	assert.Equal(t, 4, h.Paths)
+	assert.False(t, h.Busy)
+	assert.Equal(t, map[string]int{"up": 3, "down": 1}, h.PathStates)
+
+	h = &MultipathdHealth{}
+	parseMultipathdStatus("paths: 0\nbusy: True\n", h)
+	assert.Equal(t, 0, h.Paths)
+	assert.True(t, h.Busy)
+	assert.Empty(t, h.PathStates)
+}
+
+func TestMultipathdRunning(t *testing.T) {
+	tmp := t.TempDir()
+	proc := filepath.Join(tmp, "proc")
+	for pid, comm := range map[string]string{"1": "systemd", "42": "kworker/0:1"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(proc, pid), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(proc, pid, "comm"), []byte(comm+"\n"), 0o600))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(proc, "sys"), 0o755))
+	fs := NewFS(FSOptions{ProcRoot: proc})
+
+	running, err := fs.multipathdRunning()
+	require.NoError(t, err)
+	assert.False(t, running)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(proc, "977"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(proc, "977", "comm"), []byte("multipathd\n"), 0o600))
+	running, err = fs.multipathdRunning()
+	require.NoError(t, err)
+	assert.True(t, running)
+
+	assert.False(t, (&MultipathdHealth{Installed: true, Running: true}).Healthy())
+	assert.True(t, (&MultipathdHealth{Installed: true, Running: true, Responsive: true}).Healthy())
+}
+
+func TestParseMultipathdPathState(t *testing.T) {
+	out := "dev dm_st  chk_st\nsdb active ready\nsdc failed faulty\n"
+	p, ok := parseMultipathdPathState(out, "sdc")
+	require.True(t, ok)
+	assert.Equal(t, &MultipathPathState{Device: "sdc", DMState: "failed", CheckerState: "faulty"}, p)
+	_, ok = parseMultipathdPathState(out, "sdd")
+	assert.False(t, ok)
+
+	assert.True(t, multipathdReplyFailed("fail\n"))
+	assert.True(t, multipathdReplyFailed("timeout"))
+	assert.False(t, multipathdReplyFailed("ok\n"))
+	assert.False(t, multipathdReplyFailed(""))
+}
+
+func TestMultipathdPathCommands(t *testing.T) {
+	bin := t.TempDir()
+	log := filepath.Join(t.TempDir(), "log")
+	script := `#!/bin/sh
+echo "$*" >> ` + log + `
+case "$1" in
+show) printf 'dev dm_st  chk_st\nsdb failed faulty\n' ;;
+reinstate) echo fail ;;
+*) echo ok ;;
+esac
+`
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "multipathd"), []byte(script), 0o700)) // #nosec G306
+	dev := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dev, "sdb"), nil, 0o600))
+	require.NoError(t, os.Symlink(filepath.Join(dev, "sdb"), filepath.Join(dev, "wwn-0x60000970000120001263533030313434")))
+	fs := NewFS(FSOptions{ExtraEnv: []string{"PATH=" + bin}})
+	ctx := context.Background()
+
+	r, err := fs.MultipathReconfigure(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &MultipathdResult{Command: "reconfigure", Output: "ok"}, r)
+
+	r, err = fs.FailPath(ctx, filepath.Join(dev, "wwn-0x60000970000120001263533030313434"))
+	require.NoError(t, err)
+	assert.Equal(t, "fail path sdb", r.Command)
+	assert.Equal(t, &MultipathPathState{Device: "sdb", DMState: "failed", CheckerState: "faulty"}, r.Path)
+
+	r, err = fs.ReinstatePath(ctx, "sdb")
+	assert.Error(t, err)
+	assert.Equal(t, "fail", r.Output)
+
+	_, err = fs.FailPath(ctx, "sdb map mpatha")
+	assert.Error(t, err)
+
+	out, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, "reconfigure\nfail path sdb\nshow paths format %d %t %T\nreinstate path sdb\n", string(out))
+}
@@ -0,0 +1,152 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	multipathdCmd = "multipathd"
+
+	// multipathdStatusTimeout bounds the status request when ctx has no
+	// deadline, as an unresponsive daemon never answers.
+	multipathdStatusTimeout = 10 * time.Second
+)
+
+// multipathdRunning returns true if a process named multipathd is found
+// in /proc.
+func (fs *FS) multipathdRunning() (bool, error) {
+	entries, err := os.ReadDir(fs.procPath("/proc"))
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.Trim(entry.Name(), "0123456789") != "" {
+			continue
+		}
+		comm, err := os.ReadFile(filepath.Join(fs.procPath("/proc"), entry.Name(), "comm"))
+		if err != nil {
+			continue
+		}
+		if strings.TrimSpace(string(comm)) == multipathdCmd {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// multipathdStatus checks whether multipathd is installed, running and
+// responsive.
+func (fs *FS) multipathdStatus(ctx context.Context) (*MultipathdHealth, error) {
+	h := &MultipathdHealth{PathStates: make(map[string]int)}
+
+	if !fs.commandAvailable(multipathdCmd) {
+		h.Problem = "multipathd is not installed, install device-mapper-multipath or multipath-tools"
+		return h, nil
+	}
+	h.Installed = true
+
+	running, err := fs.multipathdRunning()
+	if err != nil {
+		return h, fmt.Errorf("failed to check for multipathd process: %v", err)
+	}
+	if !running {
+		h.Problem = "multipathd is not running, start it with systemctl start multipathd"
+		return h, nil
+	}
+	h.Running = true
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, multipathdStatusTimeout)
+		defer cancel()
+	}
+	/* #nosec G204 */
+	out, err := fs.commandContext(ctx, multipathdCmd, "show", "status").CombinedOutput()
+	if err != nil {
+		h.Problem = fmt.Sprintf("multipathd is not responding: %v: %s", err, strings.TrimSpace(string(out)))
+		log.WithError(err).Error("multipathd show status failed")
+		return h, nil
+	}
+	h.Responsive = true
+	parseMultipathdStatus(string(out), h)
+	return h, nil
+}
+
+// runMultipathd runs the multipathd command with args and returns its
+// result. An error is returned if multipathd fails or rejects the
+// command.
+func (fs *FS) runMultipathd(ctx context.Context, args ...string) (*MultipathdResult, error) {
+	r := &MultipathdResult{Command: strings.Join(args, " ")}
+	/* #nosec G204 */
+	out, err := fs.commandContext(ctx, multipathdCmd, args...).CombinedOutput()
+	r.Output = strings.TrimSpace(string(out))
+	if err != nil {
+		return r, fmt.Errorf("multipathd %s failed: %v: %s", r.Command, err, r.Output)
+	}
+	if multipathdReplyFailed(r.Output) {
+		return r, fmt.Errorf("multipathd %s failed: %s", r.Command, r.Output)
+	}
+	return r, nil
+}
+
+// multipathReconfigure makes multipathd reload its configuration and
+// rebuild the multipath maps.
+func (fs *FS) multipathReconfigure(ctx context.Context) (*MultipathdResult, error) {
+	log.Info("reconfiguring multipathd")
+	return fs.runMultipathd(ctx, "reconfigure")
+}
+
+// setMultipathPathState runs multipathd action path on the path device,
+// where action is fail or reinstate, and reports the state of the path
+// afterwards.
+func (fs *FS) setMultipathPathState(ctx context.Context, action, device string) (*MultipathdResult, error) {
+	name, err := multipathPathName(device)
+	if err != nil {
+		return nil, err
+	}
+	log.WithField("path", name).Infof("%s multipath path", action)
+	r, err := fs.runMultipathd(ctx, action, "path", name)
+	if err != nil {
+		return r, err
+	}
+	/* #nosec G204 */
+	out, err := fs.commandContext(ctx, multipathdCmd, "show", "paths", "format", "%d %t %T").CombinedOutput()
+	if err != nil {
+		log.WithError(err).WithField("path", name).Debug("failed to show multipath path state")
+		return r, nil
+	}
+	r.Path, _ = parseMultipathdPathState(string(out), name)
+	return r, nil
+}
+
+// reinstatePath makes multipathd reinstate the failed path device.
+func (fs *FS) reinstatePath(ctx context.Context, device string) (*MultipathdResult, error) {
+	return fs.setMultipathPathState(ctx, "reinstate", device)
+}
+
+// failPath makes multipathd fail the path device.
+func (fs *FS) failPath(ctx context.Context, device string) (*MultipathdResult, error) {
+	return fs.setMultipathPathState(ctx, "fail", device)
+}
@@ -0,0 +1,400 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// NFSPort is the well known port of the NFS service.
+const NFSPort = "2049"
+
+const (
+	// NFSRDMAPort is the well known port of the NFS service over RDMA.
+	NFSRDMAPort = 20049
+
+	// MaxNFSNConnect is the largest number of connections the nconnect
+	// option accepts.
+	MaxNFSNConnect = 16
+)
+
+// NFS transport protocols of the proto mount option.
+const (
+	NFSProtoTCP  = "tcp"
+	NFSProtoTCP6 = "tcp6"
+	NFSProtoUDP  = "udp"
+	NFSProtoUDP6 = "udp6"
+	NFSProtoRDMA = "rdma"
+	// NFSProtoRDMA6 is RDMA over IPv6.
+	NFSProtoRDMA6 = "rdma6"
+)
+
+// nconnectKernel is the first kernel version, major and minor, that
+// supports the nconnect option.
+var nconnectKernel = [2]int{5, 3}
+
+var (
+	// ErrInvalidNFSOptions is returned when NFSMountOptions are not valid.
+	ErrInvalidNFSOptions = errors.New("invalid NFS mount options")
+
+	// ErrNFSOptionNotSupported is returned by MountNFS when an option is
+	// not supported by the host and NFSMountOptions.Strict is set.
+	ErrNFSOptionNotSupported = errors.New("NFS mount option not supported")
+)
+
+// NFSMountOptions are the performance options of an NFS mount.
+type NFSMountOptions struct {
+	// NConnect is the number of TCP connections to the server, between 1
+	// and MaxNFSNConnect, or zero for the default single connection. It
+	// requires Linux 5.3.
+	NConnect int
+	// Port is the port of the NFS service on the server, or zero for the
+	// default.
+	Port int
+	// Proto is the transport protocol, e.g. NFSProtoTCP or NFSProtoRDMA,
+	// or empty for the default. RDMA requires the rpcrdma kernel module.
+	Proto string
+	// Strict makes MountNFS fail with ErrNFSOptionNotSupported when an
+	// option is not supported by the host, instead of mounting without
+	// it.
+	Strict bool
+}
+
+// NFSCapabilities are the NFS mount options supported by the host.
+type NFSCapabilities struct {
+	// KernelVersion is the release of the running kernel, e.g.
+	// 5.14.0-284.el9.x86_64.
+	KernelVersion string
+	// NConnect is true if the kernel supports the nconnect option.
+	NConnect bool
+	// RDMA is true if the RDMA transport is available.
+	RDMA bool
+}
+
+// Validate checks that the options are within their ranges.
+func (o NFSMountOptions) Validate() error {
+	if o.NConnect < 0 || o.NConnect > MaxNFSNConnect {
+		return fmt.Errorf("%w: nconnect %d is not between 1 and %d", ErrInvalidNFSOptions, o.NConnect, MaxNFSNConnect)
+	}
+	if o.Port < 0 || o.Port > 65535 {
+		return fmt.Errorf("%w: port %d is not valid", ErrInvalidNFSOptions, o.Port)
+	}
+	switch o.Proto {
+	case "", NFSProtoTCP, NFSProtoTCP6, NFSProtoUDP, NFSProtoUDP6, NFSProtoRDMA, NFSProtoRDMA6:
+	default:
+		return fmt.Errorf("%w: proto %q is not valid", ErrInvalidNFSOptions, o.Proto)
+	}
+	if o.NConnect > 0 && (o.Proto == NFSProtoUDP || o.Proto == NFSProtoUDP6) {
+		return fmt.Errorf("%w: nconnect requires a connection oriented proto, not %s", ErrInvalidNFSOptions, o.Proto)
+	}
+	return nil
+}
+
+// Options returns the mount options, e.g. nconnect=4 and proto=rdma.
+func (o NFSMountOptions) Options() []string {
+	opts := make([]string, 0, 3)
+	if o.NConnect > 0 {
+		opts = append(opts, "nconnect="+strconv.Itoa(o.NConnect))
+	}
+	if o.Port > 0 {
+		opts = append(opts, "port="+strconv.Itoa(o.Port))
+	}
+	if o.Proto != "" {
+		opts = append(opts, "proto="+o.Proto)
+	}
+	return opts
+}
+
+// isRDMA returns true if the options select the RDMA transport.
+func (o NFSMountOptions) isRDMA() bool {
+	return o.Proto == NFSProtoRDMA || o.Proto == NFSProtoRDMA6
+}
+
+// withoutTuning returns the options without nconnect and with RDMA
+// replaced by TCP, which any host supports.
+func (o NFSMountOptions) withoutTuning() NFSMountOptions {
+	o.NConnect = 0
+	if o.isRDMA() {
+		o.Proto = strings.Replace(o.Proto, NFSProtoRDMA, NFSProtoTCP, 1)
+		if o.Port == NFSRDMAPort {
+			o.Port = 0
+		}
+	}
+	return o
+}
+
+// forCapabilities returns the options the host supports. Unsupported
+// options are dropped, or RDMA replaced by TCP, unless the options are
+// strict, which is an error.
+func (o NFSMountOptions) forCapabilities(caps NFSCapabilities) (NFSMountOptions, error) {
+	if o.NConnect > 0 && !caps.NConnect {
+		if o.Strict {
+			return o, fmt.Errorf("%w: nconnect requires Linux %d.%d, running %s",
+				ErrNFSOptionNotSupported, nconnectKernel[0], nconnectKernel[1], caps.KernelVersion)
+		}
+		log.WithField("kernel", caps.KernelVersion).Warn("nconnect is not supported by the kernel, mounting without it")
+		o.NConnect = 0
+	}
+	if o.isRDMA() && !caps.RDMA {
+		if o.Strict {
+			return o, fmt.Errorf("%w: proto %s requires the rpcrdma module", ErrNFSOptionNotSupported, o.Proto)
+		}
+		log.WithField("proto", o.Proto).Warn("RDMA is not available, mounting over TCP")
+		nconnect := o.NConnect
+		o = o.withoutTuning()
+		o.NConnect = nconnect
+	}
+	return o, nil
+}
+
+// kernelAtLeast returns true if the kernel release, e.g.
+// 5.14.0-284.el9.x86_64, is at least version major.minor.
+func kernelAtLeast(release string, major, minor int) bool {
+	fields := strings.FieldsFunc(release, func(r rune) bool { return r < '0' || r > '9' })
+	if len(fields) < 2 {
+		return false
+	}
+	ma, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return false
+	}
+	mi, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return false
+	}
+	return ma > major || (ma == major && mi >= minor)
+}
+
+// mountNFS mounts the NFS export source onto target with opts and the
+// options of nfsOpts the host supports, as found by fsi. Unless nfsOpts
+// are strict, a mount that fails with nconnect or RDMA is tried once
+// more without them.
+func mountNFS(ctx context.Context, fsi FSinterface, source, target string, nfsOpts NFSMountOptions, opts ...string) error {
+	if err := nfsOpts.Validate(); err != nil {
+		return err
+	}
+	tuned := nfsOpts
+	if nfsOpts.NConnect > 0 || nfsOpts.isRDMA() {
+		caps, err := fsi.GetNFSCapabilities(ctx)
+		if err != nil {
+			if nfsOpts.Strict {
+				return err
+			}
+			// Let the mount find out if the options are supported.
+			log.WithError(err).Warn("NFS capabilities are not known")
+		} else if tuned, err = nfsOpts.forCapabilities(*caps); err != nil {
+			return err
+		}
+	}
+
+	mountOpts := append(opts[:len(opts):len(opts)], tuned.Options()...)
+	err := fsi.Mount(ctx, source, target, "nfs", mountOpts...)
+	fallback := tuned.withoutTuning()
+	if err == nil || nfsOpts.Strict || fallback == tuned || ctx.Err() != nil {
+		return err
+	}
+	log.WithFields(log.Fields{
+		"source":  source,
+		"target":  target,
+		"options": tuned.Options(),
+	}).WithError(err).Warn("NFS mount failed, trying without nconnect and RDMA")
+	mountOpts = append(opts[:len(opts):len(opts)], fallback.Options()...)
+	return fsi.Mount(ctx, source, target, "nfs", mountOpts...)
+}
+
+// NFSExportStatus reports the reachability of an NFS export.
+type NFSExportStatus struct {
+	// Server is the NFS server.
+	Server string
+	// ExportPath is the path of the export.
+	ExportPath string
+	// Reachable is true if the NFS service of the server accepted a TCP
+	// connection.
+	Reachable bool
+	// Latency is the time it took to connect to the NFS service.
+	Latency time.Duration
+	// ExportListAvailable is true if the server returned its export list.
+	// NFSv4 only servers usually do not provide one.
+	ExportListAvailable bool
+	// Exported is true if ExportPath, or a parent of it, is in the export
+	// list. It is only meaningful if ExportListAvailable is true.
+	Exported bool
+	// AllowedClients are the clients the export is restricted to.
+	AllowedClients []string
+	// ClientAllowed is true if this host is one of AllowedClients, by
+	// one of its addresses or its hostname. It is only meaningful if
+	// Exported is true.
+	ClientAllowed bool
+	// Problem describes why the export cannot be used.
+	Problem string
+}
+
+// NFSExport is an entry of the export list of an NFS server.
+type NFSExport struct {
+	// Path is the exported path.
+	Path string
+	// Clients are the clients the export is restricted to, e.g. * or
+	// 10.0.0.0/24.
+	Clients []string
+}
+
+// parseShowmountExports parses the output of showmount -e, e.g.
+//
+//	Export list for nfs.example.com:
+//	/ifs/data/csi 10.0.0.1,10.0.0.2
+//	/export/home  *
+func parseShowmountExports(out string) []NFSExport {
+	exports := make([]NFSExport, 0)
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "Export list for") {
+			continue
+		}
+		fields := strings.Fields(line)
+		export := NFSExport{Path: fields[0], Clients: make([]string, 0)}
+		for _, field := range fields[1:] {
+			for _, client := range strings.Split(field, ",") {
+				if client != "" {
+					export.Clients = append(export.Clients, client)
+				}
+			}
+		}
+		exports = append(exports, export)
+	}
+	return exports
+}
+
+// findNFSExport returns the export that exportPath is shared by, i.e. the
+// export of exportPath itself or of its closest parent.
+func findNFSExport(exports []NFSExport, exportPath string) (NFSExport, bool) {
+	exportPath = path.Clean("/" + exportPath)
+	var best NFSExport
+	found := false
+	for _, export := range exports {
+		p := path.Clean(export.Path)
+		if exportPath != p && !strings.HasPrefix(exportPath, strings.TrimSuffix(p, "/")+"/") {
+			continue
+		}
+		if !found || len(p) > len(best.Path) {
+			best, found = export, true
+			best.Path = p
+		}
+	}
+	return best, found
+}
+
+// nfsHost is how an NFS server may see this host.
+type nfsHost struct {
+	// IPs are the addresses of the host.
+	IPs []net.IP
+	// Names are the hostnames of the host.
+	Names []string
+}
+
+// nfsClientAllowed returns true if host is one of the clients an export
+// is restricted to, given as in showmount -e: * or (everyone), an
+// address, a network as CIDR or address/netmask, a hostname, possibly
+// with * and ? wildcards, or an @netgroup. Netgroups cannot be checked
+// and are assumed to include the host. Hostnames without wildcards are
+// also resolved with lookup and matched by address.
+func nfsClientAllowed(
+	ctx context.Context, clients []string, host nfsHost,
+	lookup func(context.Context, string) ([]net.IPAddr, error),
+) bool {
+	for _, client := range clients {
+		if nfsClientMatches(client, host) {
+			return true
+		}
+	}
+	for _, client := range clients {
+		if lookup == nil || net.ParseIP(client) != nil || strings.ContainsAny(client, "*?/@()") {
+			continue
+		}
+		addrs, err := lookup(ctx, client)
+		if err != nil {
+			log.WithField("client", client).WithError(err).Debug("failed to resolve NFS client")
+			continue
+		}
+		for _, addr := range addrs {
+			if nfsHostHasIP(host, addr.IP) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nfsClientMatches returns true if the client of an export matches host
+// without resolving it.
+func nfsClientMatches(client string, host nfsHost) bool {
+	client = strings.ToLower(client)
+	switch {
+	case client == "*" || client == "(everyone)" || strings.HasPrefix(client, "@"):
+		return true
+	case strings.Contains(client, "/"):
+		network, ok := parseNFSClientNetwork(client)
+		if !ok {
+			return false
+		}
+		for _, ip := range host.IPs {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+	if ip := net.ParseIP(client); ip != nil {
+		return nfsHostHasIP(host, ip)
+	}
+	for _, name := range host.Names {
+		if ok, _ := path.Match(client, strings.ToLower(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNFSClientNetwork parses a network given as CIDR, e.g.
+// 10.0.0.0/24, or as address and netmask, e.g. 10.0.0.0/255.255.255.0.
+func parseNFSClientNetwork(client string) (*net.IPNet, bool) {
+	if _, network, err := net.ParseCIDR(client); err == nil {
+		return network, true
+	}
+	addr, mask, _ := strings.Cut(client, "/")
+	ip, m := net.ParseIP(addr).To4(), net.ParseIP(mask).To4()
+	if ip == nil || m == nil {
+		return nil, false
+	}
+	return &net.IPNet{IP: ip.Mask(net.IPMask(m)), Mask: net.IPMask(m)}, true
+}
+
+// nfsHostHasIP returns true if ip is one of the addresses of host.
+func nfsHostHasIP(host nfsHost, ip net.IP) bool {
+	for _, hostIP := range host.IPs {
+		if hostIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseShowmountExports(t *testing.T) {
+	out := `Export list for nfs.example.com:
+/ifs/data/csi 10.0.0.1,10.0.0.2
+/export/home  *
+/export/multi 10.0.0.0/24 192.168.1.0/24
+`
+	exports := parseShowmountExports(out)
+	require.Len(t, exports, 3)
+	assert.Equal(t, NFSExport{Path: "/ifs/data/csi", Clients: []string{"10.0.0.1", "10.0.0.2"}}, exports[0])
+	assert.Equal(t, NFSExport{Path: "/export/home", Clients: []string{"*"}}, exports[1])
+	assert.Equal(t, []string{"10.0.0.0/24", "192.168.1.0/24"}, exports[2].Clients)
+
+	tests := []struct {
+		path  string
+		found bool
+		share string
+	}{
+		{path: "/ifs/data/csi", found: true, share: "/ifs/data/csi"},
+		{path: "/ifs/data/csi/pvc-1/", found: true, share: "/ifs/data/csi"},
+		{path: "/ifs/data/csi2", found: false},
+		{path: "/export", found: false},
+	}
+	for _, tt := range tests {
+		export, found := findNFSExport(exports, tt.path)
+		assert.Equal(t, tt.found, found, tt.path)
+		assert.Equal(t, tt.share, export.Path, tt.path)
+	}
+	_, found := findNFSExport(append(exports, NFSExport{Path: "/"}), "/srv/any")
+	assert.True(t, found)
+}
+
+func TestCheckNFSExport(t *testing.T) {
+	ctx := context.Background()
+	fs := NewFS(FSOptions{})
+
+	_, err := fs.checkNFSExport(ctx, "", "/export")
+	assert.Error(t, err)
+	_, err = fs.checkNFSExport(ctx, "127.0.0.1", "export")
+	assert.Error(t, err)
+
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", NFSPort))
+	if err == nil {
+		conn.Close()
+		t.Skip("an NFS server is running on this host")
+	}
+	status, err := fs.checkNFSExport(ctx, "127.0.0.1", "/export")
+	require.NoError(t, err)
+	assert.False(t, status.Reachable)
+	assert.False(t, status.Exported)
+	assert.Contains(t, status.Problem, "not reachable")
+}
+
+func TestNFSClientAllowed(t *testing.T) {
+	host := nfsHost{
+		IPs:   []net.IP{net.ParseIP("10.0.0.5"), net.ParseIP("fd00::5")},
+		Names: []string{"Node1.example.com"},
+	}
+	lookup := func(_ context.Context, name string) ([]net.IPAddr, error) {
+		if name == "node1-storage" {
+			return []net.IPAddr{{IP: net.ParseIP("10.0.0.5")}}, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	tests := []struct {
+		clients []string
+		allowed bool
+	}{
+		{clients: []string{"*"}, allowed: true},
+		{clients: []string{"(everyone)"}, allowed: true},
+		{clients: []string{"10.0.0.1", "10.0.0.5"}, allowed: true},
+		{clients: []string{"10.0.0.1", "10.0.0.2"}, allowed: false},
+		{clients: []string{"10.0.0.0/24"}, allowed: true},
+		{clients: []string{"10.0.1.0/24", "192.168.1.0/24"}, allowed: false},
+		{clients: []string{"10.0.0.0/255.255.255.0"}, allowed: true},
+		{clients: []string{"10.0.1.0/255.255.255.0"}, allowed: false},
+		{clients: []string{"fd00::/64"}, allowed: true},
+		{clients: []string{"node1.example.com"}, allowed: true},
+		{clients: []string{"*.example.com"}, allowed: true},
+		{clients: []string{"node?.example.com"}, allowed: true},
+		{clients: []string{"*.example.org", "node2.example.com"}, allowed: false},
+		{clients: []string{"node1-storage"}, allowed: true},
+		{clients: []string{"@k8s-nodes"}, allowed: true},
+		{clients: []string{}, allowed: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.allowed, nfsClientAllowed(context.Background(), tt.clients, host, lookup), "%v", tt.clients)
+	}
+	assert.False(t, nfsClientAllowed(context.Background(), []string{"node1-storage"}, host, nil))
+}
+
+func TestNFSMountOptions(t *testing.T) {
+	opts := NFSMountOptions{NConnect: 8, Port: NFSRDMAPort, Proto: NFSProtoRDMA}
+	require.NoError(t, opts.Validate())
+	assert.Equal(t, []string{"nconnect=8", "port=20049", "proto=rdma"}, opts.Options())
+	assert.Empty(t, NFSMountOptions{}.Options())
+
+	for _, invalid := range []NFSMountOptions{
+		{NConnect: MaxNFSNConnect + 1},
+		{NConnect: -1},
+		{Port: 70000},
+		{Proto: "sctp"},
+		{NConnect: 2, Proto: NFSProtoUDP},
+	} {
+		assert.ErrorIs(t, invalid.Validate(), ErrInvalidNFSOptions, "%+v", invalid)
+	}
+
+	tuned, err := opts.forCapabilities(NFSCapabilities{KernelVersion: "4.18.0", RDMA: false})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"proto=tcp"}, tuned.Options())
+	tuned, err = NFSMountOptions{NConnect: 4, Proto: NFSProtoRDMA6}.forCapabilities(NFSCapabilities{NConnect: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nconnect=4", "proto=tcp6"}, tuned.Options())
+	tuned, err = opts.forCapabilities(NFSCapabilities{NConnect: true, RDMA: true})
+	require.NoError(t, err)
+	assert.Equal(t, opts, tuned)
+
+	opts.Strict = true
+	_, err = opts.forCapabilities(NFSCapabilities{KernelVersion: "4.18.0", RDMA: true})
+	assert.ErrorIs(t, err, ErrNFSOptionNotSupported)
+	_, err = opts.forCapabilities(NFSCapabilities{NConnect: true})
+	assert.ErrorIs(t, err, ErrNFSOptionNotSupported)
+	assert.Equal(t, CodeNotSupported, ErrorCodeOf(err))
+}
+
+func TestKernelAtLeast(t *testing.T) {
+	for release, want := range map[string]bool{
+		"5.14.0-284.el9.x86_64":    true,
+		"5.3.0":                    true,
+		"5.2.21":                   false,
+		"4.18.0-553.el8_10.x86_64": false,
+		"6.1.0-18-amd64":           true,
+		"3.10.0-1160.el7.x86_64":   false,
+		"":                         false,
+		"generic":                  false,
+	} {
+		assert.Equal(t, want, kernelAtLeast(release, 5, 3), release)
+	}
+}
+
+func TestGetNFSCapabilities(t *testing.T) {
+	sysRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "module", "rpcrdma"), 0o755))
+	fs := NewFS(FSOptions{SysRoot: sysRoot})
+
+	caps, err := fs.GetNFSCapabilities(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, caps.KernelVersion)
+	assert.True(t, caps.RDMA)
+	assert.Equal(t, kernelAtLeast(caps.KernelVersion, 5, 3), caps.NConnect)
+}
@@ -0,0 +1,137 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// nfsCheckTimeout bounds each probe of CheckNFSExport when ctx has no
+// deadline.
+const nfsCheckTimeout = 5 * time.Second
+
+// checkNFSExport probes the NFS service of server and, if the server
+// provides an export list, whether exportPath is exported.
+func (fs *FS) checkNFSExport(ctx context.Context, server, exportPath string) (*NFSExportStatus, error) {
+	if server == "" {
+		return nil, errors.New("NFS server must be specified")
+	}
+	if !strings.HasPrefix(exportPath, "/") {
+		return nil, fmt.Errorf("NFS export path: %s is invalid", exportPath)
+	}
+	status := &NFSExportStatus{
+		Server:         server,
+		ExportPath:     exportPath,
+		AllowedClients: make([]string, 0),
+	}
+	f := log.Fields{
+		"server":     server,
+		"exportPath": exportPath,
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, nfsCheckTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(server, NFSPort))
+	if err != nil {
+		status.Problem = fmt.Sprintf("NFS service of %s is not reachable: %v", server, err)
+		log.WithFields(f).WithError(err).Info("NFS server is not reachable")
+		return status, nil
+	}
+	status.Latency = time.Since(start)
+	status.Reachable = true
+	local := conn.LocalAddr()
+	_ = conn.Close()
+
+	/* #nosec G204 */
+	out, err := fs.commandContext(ctx, "showmount", "-e", server).Output()
+	if err != nil {
+		// The export list is optional, e.g. NFSv4 only servers do not run
+		// mountd, so this is not a problem.
+		log.WithFields(f).WithError(err).Info("NFS export list is not available")
+		return status, nil
+	}
+	status.ExportListAvailable = true
+	export, ok := findNFSExport(parseShowmountExports(string(out)), exportPath)
+	if !ok {
+		status.Problem = fmt.Sprintf("%s is not exported by %s", exportPath, server)
+		return status, nil
+	}
+	status.Exported = true
+	status.AllowedClients = export.Clients
+	status.ClientAllowed = nfsClientAllowed(ctx, export.Clients, localNFSHost(local), net.DefaultResolver.LookupIPAddr)
+	if !status.ClientAllowed {
+		status.Problem = fmt.Sprintf("%s of %s is restricted to %s, which do not include this host",
+			exportPath, server, strings.Join(export.Clients, ","))
+	}
+	log.WithFields(f).WithField("clients", export.Clients).WithField("allowed", status.ClientAllowed).Info("NFS export found")
+	return status, nil
+}
+
+// localNFSHost returns the addresses of this host, starting with local,
+// the address of the connection to the NFS server, and its hostname.
+func localNFSHost(local net.Addr) nfsHost {
+	host := nfsHost{IPs: make([]net.IP, 0), Names: make([]string, 0)}
+	if tcp, ok := local.(*net.TCPAddr); ok {
+		host.IPs = append(host.IPs, tcp.IP)
+	}
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok {
+				host.IPs = append(host.IPs, ipNet.IP)
+			}
+		}
+	}
+	if name, err := os.Hostname(); err == nil {
+		host.Names = append(host.Names, name)
+	}
+	return host
+}
+
+// getNFSCapabilities checks the kernel version for nconnect support and
+// whether the rpcrdma module is loaded or installed for RDMA support.
+func (fs *FS) getNFSCapabilities(_ context.Context) (*NFSCapabilities, error) {
+	var uts unix.Utsname
+	if err := unix.Uname(&uts); err != nil {
+		return nil, fmt.Errorf("failed to get the kernel version: %w", err)
+	}
+	release := unix.ByteSliceToString(uts.Release[:])
+	caps := &NFSCapabilities{
+		KernelVersion: release,
+		NConnect:      kernelAtLeast(release, nconnectKernel[0], nconnectKernel[1]),
+		RDMA:          sysfsEntryExists(fs.sysPath("/sys/module/rpcrdma")),
+	}
+	if !caps.RDMA {
+		modules, _ := filepath.Glob(filepath.Join("/lib/modules", release, "kernel/net/sunrpc/xprtrdma/rpcrdma.ko*"))
+		caps.RDMA = len(modules) > 0
+	}
+	return caps, nil
+}
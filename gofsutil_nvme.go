@@ -0,0 +1,244 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// NVMeTransportTCP is the NVMe over TCP transport.
+	NVMeTransportTCP = "tcp"
+	// NVMeTransportFC is the NVMe over Fibre Channel transport.
+	NVMeTransportFC = "fc"
+	// NVMeTransportRDMA is the NVMe over RDMA transport.
+	NVMeTransportRDMA = "rdma"
+	// NVMeDefaultPort is the transport service id used for TCP and RDMA
+	// when none is specified.
+	NVMeDefaultPort = "4420"
+)
+
+// NVMeConnectOptions are the optional settings of an NVMe over fabrics
+// connect. Zero values leave the nvme-cli defaults in place.
+type NVMeConnectOptions struct {
+	// HostNQN overrides the host NQN read from /etc/nvme/hostnqn.
+	HostNQN string
+	// HostTraddr is the host transport address. It is required for FC,
+	// e.g. nn-0x200000109b123456:pn-0x100000109b123456.
+	HostTraddr string
+	// CtrlLossTmo is the controller loss timeout in seconds, -1 for none.
+	CtrlLossTmo int
+	// ReconnectDelay is the delay in seconds before a reconnect attempt.
+	ReconnectDelay int
+	// KeepAliveTmo is the keep alive timeout in seconds.
+	KeepAliveTmo int
+	// NrIOQueues is the number of I/O queues to create.
+	NrIOQueues int
+}
+
+// NVMeCommandError is returned when an nvme-cli command fails.
+type NVMeCommandError struct {
+	// Op is the nvme operation, e.g. connect or disconnect.
+	Op string
+	// NQN is the subsystem NQN the operation was for.
+	NQN string
+	// Args are the arguments passed to nvme.
+	Args []string
+	// ExitCode is the exit code of nvme, -1 if it did not run.
+	ExitCode int
+	// Output is the combined output of the command.
+	Output string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *NVMeCommandError) Error() string {
+	return fmt.Sprintf("nvme %s failed for %s: exit code %d: %v: %s",
+		e.Op, e.NQN, e.ExitCode, e.Err, e.Output)
+}
+
+func (e *NVMeCommandError) Unwrap() error {
+	return e.Err
+}
+
+// alreadyConnected returns true if the connect failed because the
+// controller already exists.
+func (e *NVMeCommandError) alreadyConnected() bool {
+	out := strings.ToLower(e.Output)
+	return e.Op == "connect" &&
+		(strings.Contains(out, "already connected") ||
+			strings.Contains(out, "operation already in progress"))
+}
+
+// NVMeController describes a controller of an NVMe subsystem.
+type NVMeController struct {
+	// Name is the controller name, e.g. nvme0.
+	Name string
+	// Transport is the transport, e.g. tcp, fc or rdma.
+	Transport string
+	// Address is the raw transport address as reported by sysfs, e.g.
+	// traddr=10.0.0.1,trsvcid=4420.
+	Address string
+	// TrAddr is the target transport address.
+	TrAddr string
+	// TrSvcID is the target transport service id, e.g. the TCP port.
+	TrSvcID string
+	// HostTrAddr is the host transport address, if any.
+	HostTrAddr string
+	// State is the controller state, e.g. live, connecting or deleting.
+	State string
+}
+
+// IsLive returns true if the controller is connected.
+func (c NVMeController) IsLive() bool {
+	return c.State == "live"
+}
+
+// parseNVMeAddress fills in the address fields of the controller from
+// its raw sysfs address.
+func (c *NVMeController) parseNVMeAddress() {
+	for _, field := range strings.Split(c.Address, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "traddr":
+			c.TrAddr = value
+		case "trsvcid":
+			c.TrSvcID = value
+		case "host_traddr":
+			c.HostTrAddr = value
+		}
+	}
+}
+
+// NVMeANAState is the Asymmetric Namespace Access (ANA) state of a path
+// of an NVMe namespace, as reported by the controller of the path.
+type NVMeANAState string
+
+const (
+	// NVMeANAOptimized is the state of a path with optimal performance.
+	NVMeANAOptimized NVMeANAState = "optimized"
+	// NVMeANANonOptimized is the state of a usable path with lower
+	// performance, e.g. through the non-owning node of an array.
+	NVMeANANonOptimized NVMeANAState = "non-optimized"
+	// NVMeANAInaccessible is the state of a path that cannot be used for
+	// I/O.
+	NVMeANAInaccessible NVMeANAState = "inaccessible"
+	// NVMeANAPersistentLoss is the state of a path that will not become
+	// usable again.
+	NVMeANAPersistentLoss NVMeANAState = "persistent-loss"
+	// NVMeANAChange is the state of a path that is transitioning between
+	// states.
+	NVMeANAChange NVMeANAState = "change"
+)
+
+// NVMePathState is the state of a path of an NVMe namespace through one
+// controller of its subsystem.
+type NVMePathState struct {
+	// Path is the name of the path device, e.g. nvme0c1n1.
+	Path string
+	// Namespace is the name of the multipath namespace device, e.g.
+	// nvme0n1.
+	Namespace string
+	// Controller is the name of the controller of the path, e.g. nvme1.
+	Controller string
+	// ControllerState is the state of the controller, e.g. live.
+	ControllerState string
+	// TrAddr is the target transport address of the controller.
+	TrAddr string
+	// ANAGroupID is the ANA group of the namespace, 0 if not reported.
+	ANAGroupID int
+	// ANAState is the ANA state of the path, empty if the controller does
+	// not report ANA.
+	ANAState NVMeANAState
+}
+
+// IsOptimized returns true if I/O can be sent through the path with
+// optimal performance: its controller is live and the path is optimized,
+// or its controller does not report ANA.
+func (p NVMePathState) IsOptimized() bool {
+	return p.ControllerState == "live" && (p.ANAState == NVMeANAOptimized || p.ANAState == "")
+}
+
+// HasOptimizedNVMePath returns true if one of the paths is optimized, so
+// that a volume using them can be considered ready.
+func HasOptimizedNVMePath(paths []NVMePathState) bool {
+	for _, p := range paths {
+		if p.IsOptimized() {
+			return true
+		}
+	}
+	return false
+}
+
+// NVMeMultipathMode is the multipathing an NVMe flow relies on.
+type NVMeMultipathMode string
+
+const (
+	// NVMeMultipathNative is the native multipathing of the nvme_core
+	// kernel module, with a single namespace device per volume.
+	NVMeMultipathNative NVMeMultipathMode = "native"
+	// NVMeMultipathDM is dm-multipath on top of a namespace device per
+	// path, which needs native multipathing to be disabled.
+	NVMeMultipathDM NVMeMultipathMode = "dm-multipath"
+)
+
+// ErrNVMeMultipathConflict is matched by the NVMeMultipathConflictError
+// returned by CheckNVMeMultipathMode.
+var ErrNVMeMultipathConflict = errors.New("NVMe multipath mode conflicts with the kernel setting")
+
+// NVMeMultipathConflictError is returned by CheckNVMeMultipathMode when
+// the requested multipath mode conflicts with the native multipath
+// setting of the kernel. Its message tells how to change the setting.
+type NVMeMultipathConflictError struct {
+	// Requested is the requested multipath mode.
+	Requested NVMeMultipathMode
+	// NativeEnabled is the native multipath setting of the kernel.
+	NativeEnabled bool
+}
+
+func (e *NVMeMultipathConflictError) Error() string {
+	setting, want := "disabled", "Y"
+	if e.NativeEnabled {
+		setting, want = "enabled", "N"
+	}
+	return fmt.Sprintf("%s multipathing was requested for NVMe but native NVMe multipathing is %s: "+
+		"set nvme_core.multipath=%s on the kernel command line, or options nvme_core multipath=%s "+
+		"in /etc/modprobe.d, and reboot", e.Requested, setting, want, want)
+}
+
+// Is returns true if target is ErrNVMeMultipathConflict.
+func (e *NVMeMultipathConflictError) Is(target error) bool {
+	return target == ErrNVMeMultipathConflict
+}
+
+// checkNVMeMultipathMode returns an NVMeMultipathConflictError if mode
+// conflicts with the native multipath setting read with fsi.
+func checkNVMeMultipathMode(ctx context.Context, fsi FSinterface, mode NVMeMultipathMode) error {
+	if mode != NVMeMultipathNative && mode != NVMeMultipathDM {
+		return fmt.Errorf("unknown NVMe multipath mode %q", mode)
+	}
+	native, err := fsi.GetNativeMultipathSetting(ctx)
+	if err != nil {
+		return err
+	}
+	if native != (mode == NVMeMultipathNative) {
+		return &NVMeMultipathConflictError{Requested: mode, NativeEnabled: native}
+	}
+	return nil
+}
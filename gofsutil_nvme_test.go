@@ -0,0 +1,298 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeNVMeConnectArgs(t *testing.T) {
+	nqn := "nqn.1988-11.com.dell:powerstore:00:a1b2c3d4e5f6"
+	tests := []struct {
+		name      string
+		transport string
+		traddr    string
+		trsvcid   string
+		nqn       string
+		opts      NVMeConnectOptions
+		result    string
+		errString string
+	}{
+		{
+			name:      "tcp with default port",
+			transport: NVMeTransportTCP,
+			traddr:    "10.0.0.1",
+			nqn:       nqn,
+			result:    "connect -t tcp -a 10.0.0.1 -s 4420 -n " + nqn,
+		},
+		{
+			name:      "tcp with options",
+			transport: NVMeTransportTCP,
+			traddr:    "10.0.0.1",
+			trsvcid:   "8009",
+			nqn:       nqn,
+			opts:      NVMeConnectOptions{CtrlLossTmo: -1, NrIOQueues: 4},
+			result:    "connect -t tcp -a 10.0.0.1 -s 8009 -n " + nqn + " --ctrl-loss-tmo -1 --nr-io-queues 4",
+		},
+		{
+			name:      "fc",
+			transport: NVMeTransportFC,
+			traddr:    "nn-0x58ccf090c9200c22:pn-0x58ccf098492008e9",
+			nqn:       nqn,
+			opts:      NVMeConnectOptions{HostTraddr: "nn-0x200000109b123456:pn-0x100000109b123456"},
+			result: "connect -t fc -a nn-0x58ccf090c9200c22:pn-0x58ccf098492008e9 -n " + nqn +
+				" --host-traddr nn-0x200000109b123456:pn-0x100000109b123456",
+		},
+		{
+			name:      "fc without host address",
+			transport: NVMeTransportFC,
+			traddr:    "nn-0x58ccf090c9200c22:pn-0x58ccf098492008e9",
+			nqn:       nqn,
+			errString: "NVMe/FC connect requires the host transport address",
+		},
+		{
+			name:      "unknown transport",
+			transport: "loop",
+			traddr:    "10.0.0.1",
+			nqn:       nqn,
+			errString: "NVMe transport: loop is invalid",
+		},
+		{
+			name:      "invalid nqn",
+			transport: NVMeTransportTCP,
+			traddr:    "10.0.0.1",
+			nqn:       "iqn.1992-04.com.emc:600009700bcbb70e3287017400000000",
+			errString: "NVMe subsystem NQN: iqn.1992-04.com.emc:600009700bcbb70e3287017400000000 is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := makeNVMeConnectArgs(tt.transport, tt.traddr, tt.trsvcid, tt.nqn, tt.opts)
+			if tt.errString != "" {
+				assert.EqualError(t, err, tt.errString)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.result, strings.Join(args, " "))
+		})
+	}
+}
+
+func TestNVMeCommandErrorAlreadyConnected(t *testing.T) {
+	err := &NVMeCommandError{Op: "connect", Output: "Failed to write to /dev/nvme-fabrics: Operation already in progress"}
+	assert.True(t, err.alreadyConnected())
+	err = &NVMeCommandError{Op: "connect", Output: "Failed to write to /dev/nvme-fabrics: Connection refused"}
+	assert.False(t, err.alreadyConnected())
+}
+
+func TestGetNVMeControllersForSubsystemNQN(t *testing.T) {
+	nqn := "nqn.1988-11.com.dell:powerstore:00:a1b2c3d4e5f6"
+	tmp := t.TempDir()
+	subsysDir := filepath.Join(tmp, "sys", "class", "nvme-subsystem")
+	writeAttr := func(path, value string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(value+"\n"), 0o600))
+	}
+	writeAttr(filepath.Join(subsysDir, "nvme-subsys0", "subsysnqn"), nqn)
+	writeAttr(filepath.Join(subsysDir, "nvme-subsys0", "nvme0", "transport"), "tcp")
+	writeAttr(filepath.Join(subsysDir, "nvme-subsys0", "nvme0", "address"), "traddr=10.0.0.1,trsvcid=4420,src_addr=10.0.0.100")
+	writeAttr(filepath.Join(subsysDir, "nvme-subsys0", "nvme0", "state"), "live")
+	writeAttr(filepath.Join(subsysDir, "nvme-subsys0", "nvme1", "transport"), "fc")
+	writeAttr(filepath.Join(subsysDir, "nvme-subsys0", "nvme1", "address"),
+		"traddr=nn-0x58ccf090c9200c22:pn-0x58ccf098c9200c22,host_traddr=nn-0x200000109b123456:pn-0x100000109b123456")
+	writeAttr(filepath.Join(subsysDir, "nvme-subsys0", "nvme1", "state"), "connecting")
+	writeAttr(filepath.Join(subsysDir, "nvme-subsys0", "nvme0n1", "size"), "2097152")
+	writeAttr(filepath.Join(subsysDir, "nvme-subsys1", "subsysnqn"), "nqn.2014-08.org.nvmexpress:other")
+	writeAttr(filepath.Join(subsysDir, "nvme-subsys1", "nvme2", "transport"), "tcp")
+
+	fs := NewFS(FSOptions{SysRoot: filepath.Join(tmp, "sys")})
+	controllers, err := fs.getNVMeControllersForSubsystemNQN(context.Background(), nqn)
+	require.NoError(t, err)
+	require.Len(t, controllers, 2)
+
+	assert.Equal(t, "nvme0", controllers[0].Name)
+	assert.Equal(t, "tcp", controllers[0].Transport)
+	assert.Equal(t, "10.0.0.1", controllers[0].TrAddr)
+	assert.Equal(t, "4420", controllers[0].TrSvcID)
+	assert.True(t, controllers[0].IsLive())
+
+	assert.Equal(t, "nvme1", controllers[1].Name)
+	assert.Equal(t, "fc", controllers[1].Transport)
+	assert.Equal(t, "nn-0x58ccf090c9200c22:pn-0x58ccf098c9200c22", controllers[1].TrAddr)
+	assert.Equal(t, "nn-0x200000109b123456:pn-0x100000109b123456", controllers[1].HostTrAddr)
+	assert.False(t, controllers[1].IsLive())
+
+	controllers, err = fs.getNVMeControllersForSubsystemNQN(context.Background(), "nqn.2014-08.org.nvmexpress:missing")
+	require.NoError(t, err)
+	assert.Empty(t, controllers)
+
+	fs = NewFS(FSOptions{SysRoot: filepath.Join(tmp, "missing")})
+	controllers, err = fs.getNVMeControllersForSubsystemNQN(context.Background(), nqn)
+	require.NoError(t, err)
+	assert.Empty(t, controllers)
+}
+
+func TestGetNVMePathStates(t *testing.T) {
+	nqn := "nqn.1988-11.com.dell:powerstore:00:a1b2c3d4e5f6"
+	sysRoot := t.TempDir()
+	subsysDir := filepath.Join(sysRoot, "class", "nvme-subsystem", "nvme-subsys0")
+	writeSysfsAttrs(t, subsysDir, map[string]string{"subsysnqn": nqn})
+	writeSysfsAttrs(t, filepath.Join(subsysDir, "nvme1"), map[string]string{
+		"address": "traddr=10.0.0.1,trsvcid=4420",
+		"state":   "live",
+	})
+	writeSysfsAttrs(t, filepath.Join(subsysDir, "nvme1", "nvme0c1n1"), map[string]string{
+		"ana_grpid": "2",
+		"ana_state": "non-optimized",
+	})
+	writeSysfsAttrs(t, filepath.Join(subsysDir, "nvme2"), map[string]string{
+		"address": "traddr=10.0.0.2,trsvcid=4420",
+		"state":   "live",
+	})
+	writeSysfsAttrs(t, filepath.Join(subsysDir, "nvme2", "nvme0c2n1"), map[string]string{
+		"ana_grpid": "2",
+		"ana_state": "optimized",
+	})
+	writeSysfsAttrs(t, filepath.Join(subsysDir, "nvme0n1"), map[string]string{"size": "2097152"})
+
+	fs := NewFS(FSOptions{SysRoot: sysRoot})
+	ctx := context.Background()
+	paths, err := fs.getNVMePathStates(ctx, nqn)
+	require.NoError(t, err)
+	assert.Equal(t, []NVMePathState{
+		{
+			Path:            "nvme0c1n1",
+			Namespace:       "nvme0n1",
+			Controller:      "nvme1",
+			ControllerState: "live",
+			TrAddr:          "10.0.0.1",
+			ANAGroupID:      2,
+			ANAState:        NVMeANANonOptimized,
+		},
+		{
+			Path:            "nvme0c2n1",
+			Namespace:       "nvme0n1",
+			Controller:      "nvme2",
+			ControllerState: "live",
+			TrAddr:          "10.0.0.2",
+			ANAGroupID:      2,
+			ANAState:        NVMeANAOptimized,
+		},
+	}, paths)
+	assert.True(t, HasOptimizedNVMePath(paths))
+	assert.False(t, HasOptimizedNVMePath(paths[:1]))
+
+	paths[1].ControllerState = "connecting"
+	assert.False(t, HasOptimizedNVMePath(paths))
+	assert.True(t, NVMePathState{ControllerState: "live"}.IsOptimized())
+
+	paths, err = fs.getNVMePathStates(ctx, "nqn.2014-08.org.nvmexpress:missing")
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+}
+
+func TestNVMeRescan(t *testing.T) {
+	assert.True(t, isNVMeNamespace("/sys/block/nvme0n1"))
+	assert.True(t, isNVMeNamespace("/sys/block/nvme1c2n3"))
+	assert.False(t, isNVMeNamespace("/sys/block/nvme0"))
+	assert.False(t, isNVMeNamespace("/sys/block/sdb"))
+
+	tmp := t.TempDir()
+	writeAttr := func(path string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+	}
+	readAttr := func(path string) string {
+		buf, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(buf)
+	}
+	fs := NewFS(FSOptions{})
+	ctx := context.Background()
+
+	// The device link of a namespace points to its controller.
+	ctrl := filepath.Join(tmp, "nvme0n1", "device", "rescan_controller")
+	writeAttr(ctrl)
+	require.NoError(t, fs.nvmeRescan(ctx, filepath.Join(tmp, "nvme0n1")))
+	assert.Equal(t, "1", readAttr(ctrl))
+
+	// With native multipathing it points to the subsystem.
+	ctrl1 := filepath.Join(tmp, "nvme1n1", "device", "nvme1", "rescan_controller")
+	ctrl2 := filepath.Join(tmp, "nvme1n1", "device", "nvme2", "rescan_controller")
+	writeAttr(ctrl1)
+	writeAttr(ctrl2)
+	writeAttr(filepath.Join(tmp, "nvme1n1", "device", "nvme1n1", "rescan_controller"))
+	require.NoError(t, fs.nvmeRescan(ctx, filepath.Join(tmp, "nvme1n1")))
+	assert.Equal(t, "1", readAttr(ctrl1))
+	assert.Equal(t, "1", readAttr(ctrl2))
+	assert.Empty(t, readAttr(filepath.Join(tmp, "nvme1n1", "device", "nvme1n1", "rescan_controller")))
+
+	// Without the attribute nvme ns-rescan is run.
+	bin := t.TempDir()
+	log := filepath.Join(t.TempDir(), "log")
+	writeRecorder(t, filepath.Join(bin, "nvme"), log)
+	fs = NewFS(FSOptions{DevRoot: filepath.Join(tmp, "dev"), ExtraEnv: []string{"PATH=" + bin}})
+	require.NoError(t, fs.nvmeRescan(ctx, filepath.Join(tmp, "nvme3n2")))
+	assert.Equal(t, " ns-rescan "+filepath.Join(tmp, "dev", "nvme3")+"\n", readAttr(log))
+}
+
+func TestGetNativeMultipathSetting(t *testing.T) {
+	ctx := context.Background()
+	sys := t.TempDir()
+	fs := NewFS(FSOptions{SysRoot: sys})
+
+	// The nvme_core module is not loaded.
+	_, err := fs.getNativeMultipathSetting(ctx)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	param := filepath.Join(sys, "module", "nvme_core", "parameters", "multipath")
+	require.NoError(t, os.MkdirAll(filepath.Dir(param), 0o755))
+	for value, want := range map[string]bool{"Y": true, "N": false} {
+		require.NoError(t, os.WriteFile(param, []byte(value+"\n"), 0o600))
+		native, err := fs.getNativeMultipathSetting(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, want, native)
+
+		err = checkNVMeMultipathMode(ctx, fs, NVMeMultipathNative)
+		assert.Equal(t, want, err == nil)
+		err = checkNVMeMultipathMode(ctx, fs, NVMeMultipathDM)
+		assert.Equal(t, !want, err == nil)
+	}
+
+	require.NoError(t, os.WriteFile(param, []byte("N\n"), 0o600))
+	err = checkNVMeMultipathMode(ctx, fs, NVMeMultipathNative)
+	require.ErrorIs(t, err, ErrNVMeMultipathConflict)
+	var conflict *NVMeMultipathConflictError
+	require.True(t, errors.As(err, &conflict))
+	assert.Equal(t, NVMeMultipathNative, conflict.Requested)
+	assert.False(t, conflict.NativeEnabled)
+	assert.Contains(t, err.Error(), "nvme_core.multipath=Y")
+	assert.Equal(t, CodeNotSupported, classifyError(err))
+	assert.Error(t, checkNVMeMultipathMode(ctx, fs, "other"))
+
+	require.NoError(t, os.WriteFile(param, []byte("maybe\n"), 0o600))
+	_, err = fs.getNativeMultipathSetting(ctx)
+	assert.Error(t, err)
+}
@@ -0,0 +1,354 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const nvmeCmd = "nvme"
+
+// nvmeControllerRegex matches controller entries, e.g. nvme0, but not
+// namespaces, e.g. nvme0n1, of an NVMe subsystem in sysfs.
+var nvmeControllerRegex = regexp.MustCompile(`^nvme[0-9]+$`)
+
+// nvmePathRegex matches the path devices of a multipath namespace, e.g.
+// nvme0c1n1 for namespace 1 of subsystem 0 through controller 1.
+var nvmePathRegex = regexp.MustCompile(`^nvme([0-9]+)c[0-9]+n([0-9]+)$`)
+
+// nvmeNamespaceRegex matches NVMe namespace block devices, e.g. nvme0n1,
+// or nvme0c1n1 for a path of a multipath namespace, and captures the
+// instance number of the controller or subsystem.
+var nvmeNamespaceRegex = regexp.MustCompile(`^nvme([0-9]+)(?:c[0-9]+)?n[0-9]+$`)
+
+// isNVMeNamespace returns true if the sysfs block device path, e.g.
+// /sys/block/nvme0n1, is an NVMe namespace.
+func isNVMeNamespace(devicePath string) bool {
+	return nvmeNamespaceRegex.MatchString(filepath.Base(devicePath))
+}
+
+// nvmeRescan rescans the namespaces of the controllers of the NVMe
+// namespace with the given sysfs block device path, so that a change of
+// its size is detected. The device link of the namespace points either to
+// its controller or, with native multipathing, to its subsystem, in which
+// case all the controllers of the subsystem are rescanned. When sysfs has
+// no rescan_controller attribute nvme ns-rescan is used.
+func (fs *FS) nvmeRescan(ctx context.Context, devicePath string) error {
+	deviceDir := filepath.Join(devicePath, "device")
+	attrs := []string{filepath.Join(deviceDir, "rescan_controller")}
+	if _, err := os.Stat(attrs[0]); err != nil {
+		attrs = nil
+		for _, ctrl := range nvmeSubsystemControllers(deviceDir) {
+			attr := filepath.Join(deviceDir, ctrl, "rescan_controller")
+			if _, err := os.Stat(attr); err == nil {
+				attrs = append(attrs, attr)
+			}
+		}
+	}
+
+	if len(attrs) == 0 {
+		m := nvmeNamespaceRegex.FindStringSubmatch(filepath.Base(devicePath))
+		if m == nil {
+			return fmt.Errorf("%s is not an NVMe namespace", devicePath)
+		}
+		ctrl := fs.devPath("/dev/nvme" + m[1])
+		log.Infof("Executing nvme ns-rescan on controller (%s)", ctrl)
+		out, err := fs.commandContext(ctx, nvmeCmd, "ns-rescan", ctrl).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("nvme ns-rescan %s failed: %v: %s", ctrl, err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	for _, attr := range attrs {
+		log.Infof("Rescanning NVMe controller (%s)", filepath.Dir(attr))
+		if err := fs.writeSysfsFile(attr, "1"); err != nil {
+			return fmt.Errorf("failed to rescan NVMe controller: %v", err)
+		}
+	}
+	return nil
+}
+
+// nvmeSubsystemControllers returns the names of the controllers, e.g.
+// nvme0, in the sysfs directory of an NVMe subsystem, none if dir is the
+// directory of a controller.
+func nvmeSubsystemControllers(dir string) []string {
+	entries, _ := os.ReadDir(dir)
+	var controllers []string
+	for _, e := range entries {
+		if nvmeControllerRegex.MatchString(e.Name()) {
+			controllers = append(controllers, e.Name())
+		}
+	}
+	return controllers
+}
+
+// nvmeNativeMultipath returns true if the block device name, e.g.
+// nvme0n1, is an NVMe namespace with native multipathing. The device link
+// of such a namespace points to its subsystem instead of a controller.
+func (fs *FS) nvmeNativeMultipath(name string) bool {
+	if !isNVMeNamespace(name) {
+		return false
+	}
+	return len(nvmeSubsystemControllers(filepath.Join(fs.sysBlockDir(), name, "device"))) > 0
+}
+
+// nvmeCoreMultipathParam is the parameter of the nvme_core module that
+// enables native NVMe multipathing.
+const nvmeCoreMultipathParam = "/sys/module/nvme_core/parameters/multipath"
+
+// getNativeMultipathSetting returns true if native NVMe multipathing is
+// enabled in the nvme_core module. An error wrapping os.ErrNotExist is
+// returned if the module is not loaded.
+func (fs *FS) getNativeMultipathSetting(_ context.Context) (bool, error) {
+	buf, err := os.ReadFile(fs.sysPath(nvmeCoreMultipathParam))
+	if err != nil {
+		return false, err
+	}
+	switch value := strings.TrimSpace(string(buf)); value {
+	case "Y", "y", "1":
+		return true, nil
+	case "N", "n", "0":
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected value %q of %s", value, nvmeCoreMultipathParam)
+	}
+}
+
+// makeNVMeConnectArgs makes the arguments to the nvme connect command.
+func makeNVMeConnectArgs(transport, traddr, trsvcid, nqn string, opts NVMeConnectOptions) ([]string, error) {
+	switch transport {
+	case NVMeTransportTCP, NVMeTransportRDMA:
+		if trsvcid == "" {
+			trsvcid = NVMeDefaultPort
+		}
+	case NVMeTransportFC:
+		if opts.HostTraddr == "" {
+			return nil, errors.New("NVMe/FC connect requires the host transport address")
+		}
+	default:
+		return nil, fmt.Errorf("NVMe transport: %s is invalid", transport)
+	}
+	if traddr == "" {
+		return nil, errors.New("NVMe target address must be specified")
+	}
+	if !strings.HasPrefix(nqn, "nqn.") {
+		return nil, fmt.Errorf("NVMe subsystem NQN: %s is invalid", nqn)
+	}
+
+	args := []string{"connect", "-t", transport, "-a", traddr}
+	if trsvcid != "" {
+		args = append(args, "-s", trsvcid)
+	}
+	args = append(args, "-n", nqn)
+	if opts.HostNQN != "" {
+		args = append(args, "--hostnqn", opts.HostNQN)
+	}
+	if opts.HostTraddr != "" {
+		args = append(args, "--host-traddr", opts.HostTraddr)
+	}
+	if opts.CtrlLossTmo != 0 {
+		args = append(args, "--ctrl-loss-tmo", strconv.Itoa(opts.CtrlLossTmo))
+	}
+	if opts.ReconnectDelay > 0 {
+		args = append(args, "--reconnect-delay", strconv.Itoa(opts.ReconnectDelay))
+	}
+	if opts.KeepAliveTmo > 0 {
+		args = append(args, "--keep-alive-tmo", strconv.Itoa(opts.KeepAliveTmo))
+	}
+	if opts.NrIOQueues > 0 {
+		args = append(args, "--nr-io-queues", strconv.Itoa(opts.NrIOQueues))
+	}
+	return args, nil
+}
+
+// runNVMeCommand runs nvme with the given arguments and converts a failure
+// into an NVMeCommandError.
+func (fs *FS) runNVMeCommand(ctx context.Context, op, nqn string, args ...string) error {
+	log.Printf("%s %v", nvmeCmd, args)
+	/* #nosec G204 */
+	out, err := fs.commandContext(ctx, nvmeCmd, args...).CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	cmdErr := &NVMeCommandError{
+		Op:       op,
+		NQN:      nqn,
+		Args:     args,
+		ExitCode: -1,
+		Output:   strings.TrimSpace(string(out)),
+		Err:      err,
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		cmdErr.ExitCode = exitErr.ExitCode()
+	}
+	return cmdErr
+}
+
+// connectNVMeTarget connects to an NVMe over fabrics subsystem.
+func (fs *FS) connectNVMeTarget(ctx context.Context, transport, traddr, trsvcid, nqn string, opts NVMeConnectOptions) error {
+	args, err := makeNVMeConnectArgs(transport, traddr, trsvcid, nqn, opts)
+	if err != nil {
+		return err
+	}
+	f := log.Fields{
+		"transport": transport,
+		"traddr":    traddr,
+		"nqn":       nqn,
+	}
+	log.WithFields(f).Info("connecting to NVMe target")
+	err = fs.runNVMeCommand(ctx, "connect", nqn, args...)
+	if err != nil {
+		var cmdErr *NVMeCommandError
+		if errors.As(err, &cmdErr) && cmdErr.alreadyConnected() {
+			log.WithFields(f).Info("NVMe target is already connected")
+			return nil
+		}
+		log.WithFields(f).WithError(err).Error("NVMe connect failed")
+		return err
+	}
+	return nil
+}
+
+// disconnectNVMeTarget disconnects all the controllers of an NVMe subsystem.
+func (fs *FS) disconnectNVMeTarget(ctx context.Context, nqn string) error {
+	if !strings.HasPrefix(nqn, "nqn.") {
+		return fmt.Errorf("NVMe subsystem NQN: %s is invalid", nqn)
+	}
+	log.WithField("nqn", nqn).Info("disconnecting NVMe target")
+	err := fs.runNVMeCommand(ctx, "disconnect", nqn, "disconnect", "-n", nqn)
+	if err != nil {
+		log.WithField("nqn", nqn).WithError(err).Error("NVMe disconnect failed")
+	}
+	return err
+}
+
+// readSysfsAttr returns the trimmed content of a sysfs attribute, or an
+// empty string if it cannot be read.
+func readSysfsAttr(path string) string {
+	value, err := readSysfsFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+// sysfsEntryExists returns true if the sysfs file or directory exists.
+func sysfsEntryExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// getNVMeControllersForSubsystemNQN returns the controllers of the NVMe
+// subsystem with the given NQN. It returns no controllers if the host is
+// not connected to the subsystem.
+func (fs *FS) getNVMeControllersForSubsystemNQN(_ context.Context, nqn string) ([]NVMeController, error) {
+	controllers := make([]NVMeController, 0)
+	subsysDir := fs.sysPath("/sys/class/nvme-subsystem")
+	subsystems, err := os.ReadDir(subsysDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return controllers, nil
+		}
+		return controllers, fmt.Errorf("Error reading %s: %s", subsysDir, err)
+	}
+	for _, subsys := range subsystems {
+		dir := filepath.Join(subsysDir, subsys.Name())
+		if readSysfsAttr(filepath.Join(dir, "subsysnqn")) != nqn {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return controllers, fmt.Errorf("Error reading %s: %s", dir, err)
+		}
+		for _, entry := range entries {
+			if !nvmeControllerRegex.MatchString(entry.Name()) {
+				continue
+			}
+			ctrlDir := filepath.Join(dir, entry.Name())
+			c := NVMeController{
+				Name:      entry.Name(),
+				Transport: readSysfsAttr(filepath.Join(ctrlDir, "transport")),
+				Address:   readSysfsAttr(filepath.Join(ctrlDir, "address")),
+				State:     readSysfsAttr(filepath.Join(ctrlDir, "state")),
+			}
+			c.parseNVMeAddress()
+			controllers = append(controllers, c)
+		}
+	}
+	return controllers, nil
+}
+
+// getNVMePathStates returns the ANA state of the paths of the namespaces
+// of the NVMe subsystem with the given NQN, through each of its
+// controllers. It returns no paths if the host is not connected to the
+// subsystem.
+func (fs *FS) getNVMePathStates(_ context.Context, nqn string) ([]NVMePathState, error) {
+	paths := make([]NVMePathState, 0)
+	subsysDir := fs.sysPath("/sys/class/nvme-subsystem")
+	subsystems, err := os.ReadDir(subsysDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return paths, nil
+		}
+		return paths, fmt.Errorf("Error reading %s: %s", subsysDir, err)
+	}
+	for _, subsys := range subsystems {
+		dir := filepath.Join(subsysDir, subsys.Name())
+		if readSysfsAttr(filepath.Join(dir, "subsysnqn")) != nqn {
+			continue
+		}
+		for _, ctrl := range nvmeSubsystemControllers(dir) {
+			ctrlDir := filepath.Join(dir, ctrl)
+			c := NVMeController{Address: readSysfsAttr(filepath.Join(ctrlDir, "address"))}
+			c.parseNVMeAddress()
+			state := readSysfsAttr(filepath.Join(ctrlDir, "state"))
+			entries, err := os.ReadDir(ctrlDir)
+			if err != nil {
+				return paths, fmt.Errorf("Error reading %s: %s", ctrlDir, err)
+			}
+			for _, entry := range entries {
+				m := nvmePathRegex.FindStringSubmatch(entry.Name())
+				if m == nil {
+					continue
+				}
+				pathDir := filepath.Join(ctrlDir, entry.Name())
+				groupID, _ := strconv.Atoi(readSysfsAttr(filepath.Join(pathDir, "ana_grpid")))
+				paths = append(paths, NVMePathState{
+					Path:            entry.Name(),
+					Namespace:       "nvme" + m[1] + "n" + m[2],
+					Controller:      ctrl,
+					ControllerState: state,
+					TrAddr:          c.TrAddr,
+					ANAGroupID:      groupID,
+					ANAState:        NVMeANAState(readSysfsAttr(filepath.Join(pathDir, "ana_state"))),
+				})
+			}
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,165 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// multipathFlushTimeout is the timeout, in seconds, of the multipath
+// command of MultipathFlushOperation.
+const multipathFlushTimeout = 60
+
+// OperationFunc is a long-running operation run by StartOperation. It
+// should return once ctx is done.
+type OperationFunc func(ctx context.Context) error
+
+// OperationState is the state of an operation started by StartOperation.
+type OperationState string
+
+const (
+	// OperationRunning is the state of an operation that has not
+	// completed yet.
+	OperationRunning OperationState = "running"
+	// OperationSucceeded is the state of an operation that completed
+	// without error.
+	OperationSucceeded OperationState = "succeeded"
+	// OperationFailed is the state of an operation that completed with
+	// an error.
+	OperationFailed OperationState = "failed"
+	// OperationCanceled is the state of an operation that completed after
+	// it was canceled.
+	OperationCanceled OperationState = "canceled"
+)
+
+// OperationStatus is the status of an operation started by
+// StartOperation.
+type OperationStatus struct {
+	// State is the state of the operation.
+	State OperationState
+	// Started is when the operation was started.
+	Started time.Time
+	// Finished is when the operation completed, zero while it is running.
+	Finished time.Time
+	// Err is the error of the operation once it completed.
+	Err error
+}
+
+// Operation is a handle of an operation started by StartOperation.
+type Operation struct {
+	cancel   context.CancelFunc
+	done     chan struct{}
+	mu       sync.Mutex
+	status   OperationStatus
+	canceled bool
+}
+
+// StartOperation runs op in a goroutine and returns a handle to follow
+// it. The operation gets the values of ctx, but is not canceled with ctx,
+// so that it can outlive the request that started it; use Cancel to stop
+// it.
+func StartOperation(ctx context.Context, op OperationFunc) *Operation {
+	opCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	o := &Operation{
+		cancel: cancel,
+		done:   make(chan struct{}),
+		status: OperationStatus{
+			State:   OperationRunning,
+			Started: time.Now(),
+		},
+	}
+	go func() {
+		defer cancel()
+		err := op(opCtx)
+		o.mu.Lock()
+		o.status.Finished = time.Now()
+		o.status.Err = err
+		switch {
+		case err == nil:
+			o.status.State = OperationSucceeded
+		case o.canceled && errors.Is(err, context.Canceled):
+			o.status.State = OperationCanceled
+		default:
+			o.status.State = OperationFailed
+		}
+		o.mu.Unlock()
+		close(o.done)
+	}()
+	return o
+}
+
+// Status returns the current status of the operation.
+func (o *Operation) Status() OperationStatus {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.status
+}
+
+// Done returns a channel that is closed once the operation completed.
+func (o *Operation) Done() <-chan struct{} {
+	return o.done
+}
+
+// Wait waits until the operation completed and returns its error. It
+// returns the error of ctx if ctx is done first, leaving the operation
+// running.
+func (o *Operation) Wait(ctx context.Context) error {
+	select {
+	case <-o.done:
+		return o.Status().Err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Cancel cancels the context of the operation. The operation is canceled
+// once it returns, which can be checked with Status or Wait.
+func (o *Operation) Cancel() {
+	o.mu.Lock()
+	o.canceled = true
+	o.mu.Unlock()
+	o.cancel()
+}
+
+// FormatOperation returns an operation that formats source like Format.
+func FormatOperation(source, target, fsType string, opts ...string) OperationFunc {
+	return func(ctx context.Context) error {
+		return Format(ctx, source, target, fsType, opts...)
+	}
+}
+
+// ResizeFSOperation returns an operation that expands the filesystem
+// like ResizeFS.
+func ResizeFSOperation(volumePath, devicePath, ppathDevice, mpathDevice, fsType string) OperationFunc {
+	return func(ctx context.Context) error {
+		return ResizeFS(ctx, volumePath, devicePath, ppathDevice, mpathDevice, fsType)
+	}
+}
+
+// MultipathFlushOperation returns an operation that flushes the
+// multipath map with the given name with multipath -f.
+func MultipathFlushOperation(name string) OperationFunc {
+	return func(ctx context.Context) error {
+		out, err := MultipathCommand(ctx, multipathFlushTimeout, "", "-f", name)
+		if err != nil {
+			return fmt.Errorf("failed to flush multipath device %s: %v: %s",
+				name, err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+}
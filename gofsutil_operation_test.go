@@ -0,0 +1,87 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type operationKey struct{}
+
+func TestStartOperation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), operationKey{}, "value"))
+	release := make(chan struct{})
+	op := StartOperation(ctx, func(ctx context.Context) error {
+		if ctx.Value(operationKey{}) != "value" {
+			return errors.New("context value lost")
+		}
+		<-release
+		return nil
+	})
+	// The operation outlives the context that started it.
+	cancel()
+	status := op.Status()
+	assert.Equal(t, OperationRunning, status.State)
+	assert.False(t, status.Started.IsZero())
+	assert.True(t, status.Finished.IsZero())
+
+	short, cancelShort := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShort()
+	assert.ErrorIs(t, op.Wait(short), context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, op.Wait(context.Background()))
+	<-op.Done()
+	status = op.Status()
+	assert.Equal(t, OperationSucceeded, status.State)
+	assert.False(t, status.Finished.Before(status.Started))
+
+	op = StartOperation(context.Background(), func(context.Context) error {
+		return errors.New("mkfs failed")
+	})
+	assert.EqualError(t, op.Wait(context.Background()), "mkfs failed")
+	assert.Equal(t, OperationFailed, op.Status().State)
+
+	op = StartOperation(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	op.Cancel()
+	assert.ErrorIs(t, op.Wait(context.Background()), context.Canceled)
+	assert.Equal(t, OperationCanceled, op.Status().State)
+}
+
+func TestFormatOperation(t *testing.T) {
+	UseMockFS()
+	ResetMockFS()
+	defer UseFSOptions(FSOptions{})
+	defer ResetMockFS()
+	ctx := context.Background()
+
+	op := StartOperation(ctx, FormatOperation("/dev/sdx", "/mnt/sdx", "xfs"))
+	require.NoError(t, op.Wait(ctx))
+	GOFSMock.InduceFormatError = true
+	op = StartOperation(ctx, FormatOperation("/dev/sdx", "/mnt/sdx", "xfs"))
+	assert.Error(t, op.Wait(ctx))
+	assert.Equal(t, OperationFailed, op.Status().State)
+
+	GOFSMock.InduceResizeFSError = true
+	op = StartOperation(ctx, ResizeFSOperation("/mnt/sdx", "/dev/sdx", "", "", "xfs"))
+	assert.Error(t, op.Wait(ctx))
+}
@@ -0,0 +1,186 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultPartitionType is the sgdisk type code of the partitions created
+// by CreatePartition when PartitionSpec.Type is not set: Linux filesystem.
+const DefaultPartitionType = "8300"
+
+// PartitionSpec describes a GPT partition to create.
+type PartitionSpec struct {
+	// Number is the partition number, the first free number if zero.
+	Number int
+	// Start is the offset of the partition in bytes, the start of the
+	// largest free block if zero.
+	Start int64
+	// Size is the size of the partition in bytes, the rest of the free
+	// block if zero.
+	Size int64
+	// Type is the sgdisk type code, e.g. 8300, or the type GUID of the
+	// partition, DefaultPartitionType if empty.
+	Type string
+	// Name is the GPT partition name.
+	Name string
+}
+
+// Partition is a partition of a disk.
+type Partition struct {
+	// Number is the partition number.
+	Number int
+	// Device is the device path of the partition, e.g. /dev/sdb1.
+	Device string
+	// Start is the offset of the partition in bytes.
+	Start int64
+	// Size is the size of the partition in bytes.
+	Size int64
+	// Type is the partition type GUID.
+	Type string
+	// UUID is the unique partition GUID.
+	UUID string
+	// Name is the GPT partition name.
+	Name string
+}
+
+// PartitionTable is the partition table of a disk.
+type PartitionTable struct {
+	// Device is the device path of the disk.
+	Device string
+	// Label is the type of the partition table, e.g. gpt or dos.
+	Label string
+	// ID is the disk GUID of a GPT partition table.
+	ID string
+	// SectorSize is the logical sector size of the disk in bytes.
+	SectorSize int64
+	// Partitions are the partitions, in the order of the table.
+	Partitions []Partition
+}
+
+// Partition returns the partition with the given number.
+func (t *PartitionTable) Partition(number int) (Partition, bool) {
+	for _, p := range t.Partitions {
+		if p.Number == number {
+			return p, true
+		}
+	}
+	return Partition{}, false
+}
+
+// parseSfdiskJSON parses the output of sfdisk --json.
+func parseSfdiskJSON(data []byte) (*PartitionTable, error) {
+	var dump struct {
+		PartitionTable struct {
+			Label      string `json:"label"`
+			ID         string `json:"id"`
+			Device     string `json:"device"`
+			Unit       string `json:"unit"`
+			SectorSize int64  `json:"sectorsize"`
+			Partitions []struct {
+				Node  string `json:"node"`
+				Start int64  `json:"start"`
+				Size  int64  `json:"size"`
+				Type  string `json:"type"`
+				UUID  string `json:"uuid"`
+				Name  string `json:"name"`
+			} `json:"partitions"`
+		} `json:"partitiontable"`
+	}
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return nil, fmt.Errorf("failed to parse partition table: %v", err)
+	}
+	pt := dump.PartitionTable
+	if pt.Unit != "" && pt.Unit != "sectors" {
+		return nil, fmt.Errorf("partition table unit: %s is not supported", pt.Unit)
+	}
+	if pt.SectorSize == 0 {
+		// Older versions of sfdisk do not report the sector size.
+		pt.SectorSize = 512
+	}
+	table := &PartitionTable{
+		Device:     pt.Device,
+		Label:      pt.Label,
+		ID:         pt.ID,
+		SectorSize: pt.SectorSize,
+		Partitions: make([]Partition, 0, len(pt.Partitions)),
+	}
+	for _, p := range pt.Partitions {
+		number, err := partitionNumber(p.Node)
+		if err != nil {
+			return nil, err
+		}
+		table.Partitions = append(table.Partitions, Partition{
+			Number: number,
+			Device: p.Node,
+			Start:  p.Start * pt.SectorSize,
+			Size:   p.Size * pt.SectorSize,
+			Type:   p.Type,
+			UUID:   p.UUID,
+			Name:   p.Name,
+		})
+	}
+	return table, nil
+}
+
+// partitionNumber returns the number of a partition given its device
+// path, e.g. 2 for /dev/sdb2 or /dev/nvme0n1p2.
+func partitionNumber(device string) (int, error) {
+	i := len(device)
+	for i > 0 && device[i-1] >= '0' && device[i-1] <= '9' {
+		i--
+	}
+	n, err := strconv.Atoi(device[i:])
+	if err != nil {
+		return 0, fmt.Errorf("invalid partition device: %s", device)
+	}
+	return n, nil
+}
+
+// makeSgdiskNewArgs makes the sgdisk arguments that create the partition
+// described by spec on a disk with the given sector size.
+func makeSgdiskNewArgs(device string, spec PartitionSpec, sectorSize int64) ([]string, error) {
+	if spec.Number < 0 || spec.Start < 0 || spec.Size < 0 {
+		return nil, fmt.Errorf("invalid partition spec: %+v", spec)
+	}
+	if sectorSize <= 0 {
+		return nil, fmt.Errorf("invalid sector size: %d", sectorSize)
+	}
+	if spec.Start%sectorSize != 0 || spec.Size%sectorSize != 0 {
+		return nil, fmt.Errorf("partition start and size must be multiples of the sector size %d", sectorSize)
+	}
+	start, end := "0", "0"
+	if spec.Start > 0 {
+		start = strconv.FormatInt(spec.Start/sectorSize, 10)
+	}
+	if spec.Size > 0 {
+		end = "+" + strconv.FormatInt(spec.Size/sectorSize, 10)
+	}
+	partType := spec.Type
+	if partType == "" {
+		partType = DefaultPartitionType
+	}
+	number := strconv.Itoa(spec.Number)
+	args := []string{
+		"--new=" + strings.Join([]string{number, start, end}, ":"),
+		"--typecode=" + number + ":" + partType,
+	}
+	if spec.Name != "" {
+		args = append(args, "--change-name="+number+":"+spec.Name)
+	}
+	return append(args, device), nil
+}
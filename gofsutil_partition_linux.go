@@ -0,0 +1,162 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	sfdiskCmd   = "sfdisk"
+	sgdiskCmd   = "sgdisk"
+	growpartCmd = "growpart"
+)
+
+// logicalSectorSize returns the logical sector size of the disk, 512 if
+// sysfs does not report it.
+func (fs *FS) logicalSectorSize(device string) int64 {
+	attr := fs.sysPath(filepath.Join("/sys/class/block", filepath.Base(device), "queue", "logical_block_size"))
+	buf, err := os.ReadFile(filepath.Clean(attr))
+	if err != nil {
+		return 512
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(string(buf)), 10, 64)
+	if err != nil || size <= 0 {
+		return 512
+	}
+	return size
+}
+
+// getPartitions returns the partition table of the disk. A disk without
+// a partition table has an empty Label.
+func (fs *FS) getPartitions(ctx context.Context, device string) (*PartitionTable, error) {
+	dev, err := fs.validateDevice(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+	out, err := fs.commandContext(ctx, sfdiskCmd, "--json", dev).Output()
+	if err != nil {
+		var stderr string
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			stderr = string(exitErr.Stderr)
+		}
+		if strings.Contains(stderr, "does not contain a recognized partition table") {
+			return &PartitionTable{Device: dev, SectorSize: fs.logicalSectorSize(dev), Partitions: []Partition{}}, nil
+		}
+		return nil, fmt.Errorf("%s --json %s failed: %v: %s", sfdiskCmd, dev, err, strings.TrimSpace(stderr))
+	}
+	return parseSfdiskJSON(out)
+}
+
+// createPartition creates a GPT partition, and the partition table if the
+// disk has none, and returns it.
+func (fs *FS) createPartition(ctx context.Context, device string, spec PartitionSpec) (*Partition, error) {
+	before, err := fs.getPartitions(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+	if before.Label != "" && before.Label != "gpt" {
+		return nil, fmt.Errorf("partition table of %s is %s, not gpt", before.Device, before.Label)
+	}
+	args, err := makeSgdiskNewArgs(before.Device, spec, fs.logicalSectorSize(before.Device))
+	if err != nil {
+		return nil, err
+	}
+	if err := fs.runPartitionCommand(ctx, sgdiskCmd, args...); err != nil {
+		return nil, err
+	}
+	if err := fs.reReadPartitionTable(ctx, before.Device); err != nil {
+		return nil, err
+	}
+
+	after, err := fs.getPartitions(ctx, before.Device)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range after.Partitions {
+		if spec.Number > 0 && p.Number != spec.Number {
+			continue
+		}
+		if _, existed := before.Partition(p.Number); spec.Number == 0 && existed {
+			continue
+		}
+		log.WithFields(log.Fields{
+			"device":    before.Device,
+			"partition": p.Device,
+		}).Info("partition created")
+		return &p, nil
+	}
+	return nil, fmt.Errorf("partition created on %s not found", before.Device)
+}
+
+// deletePartition deletes the partition with the given number.
+func (fs *FS) deletePartition(ctx context.Context, device string, number int) error {
+	dev, err := fs.validateDevice(ctx, device)
+	if err != nil {
+		return err
+	}
+	if number <= 0 {
+		return fmt.Errorf("invalid partition number: %d", number)
+	}
+	if err := fs.runPartitionCommand(ctx, sgdiskCmd, "--delete="+strconv.Itoa(number), dev); err != nil {
+		return err
+	}
+	return fs.reReadPartitionTable(ctx, dev)
+}
+
+// growPartition grows the partition with the given number into the free
+// space that follows it. Growing a partition that cannot grow is not an
+// error.
+func (fs *FS) growPartition(ctx context.Context, device string, number int) error {
+	dev, err := fs.validateDevice(ctx, device)
+	if err != nil {
+		return err
+	}
+	if number <= 0 {
+		return fmt.Errorf("invalid partition number: %d", number)
+	}
+	out, err := fs.commandContext(ctx, growpartCmd, dev, strconv.Itoa(number)).CombinedOutput()
+	if err != nil {
+		// growpart exits with 1 and reports NOCHANGE when the partition
+		// already fills the free space.
+		if strings.HasPrefix(strings.TrimSpace(string(out)), "NOCHANGE") {
+			return nil
+		}
+		return fmt.Errorf("%s %s %d failed: %v: %s", growpartCmd, dev, number, err, strings.TrimSpace(string(out)))
+	}
+	log.WithField("device", dev).WithField("partition", number).Info("partition grown")
+	return nil
+}
+
+// runPartitionCommand runs a partitioning tool.
+func (fs *FS) runPartitionCommand(ctx context.Context, name string, args ...string) error {
+	log.Printf("%s %v", name, args)
+	out, err := fs.commandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %v failed: %v: %s", name, args, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sfdiskJSON = `{
+   "partitiontable": {
+      "label": "gpt",
+      "id": "5A1E7C2B-6B0F-4E0C-9C6E-1F2D3C4B5A69",
+      "device": "/dev/loop0",
+      "unit": "sectors",
+      "firstlba": 2048,
+      "lastlba": 4194270,
+      "sectorsize": 512,
+      "partitions": [
+         {
+            "node": "/dev/loop0p1",
+            "start": 2048,
+            "size": 2097152,
+            "type": "0FC63DAF-8483-4772-8E79-3D69D8477DE4",
+            "uuid": "E3B0C442-98FC-1C14-9AFB-F4C8996FB924",
+            "name": "data"
+         }
+      ]
+   }
+}`
+
+func TestParseSfdiskJSON(t *testing.T) {
+	table, err := parseSfdiskJSON([]byte(sfdiskJSON))
+	require.NoError(t, err)
+	assert.Equal(t, "gpt", table.Label)
+	assert.Equal(t, int64(512), table.SectorSize)
+	require.Len(t, table.Partitions, 1)
+	p, ok := table.Partition(1)
+	require.True(t, ok)
+	assert.Equal(t, "/dev/loop0p1", p.Device)
+	assert.Equal(t, int64(1<<20), p.Start)
+	assert.Equal(t, int64(1<<30), p.Size)
+	assert.Equal(t, "data", p.Name)
+	_, ok = table.Partition(2)
+	assert.False(t, ok)
+
+	_, err = parseSfdiskJSON([]byte(`{"partitiontable": {"unit": "cylinders"}}`))
+	assert.Error(t, err)
+	_, err = parseSfdiskJSON([]byte(`sfdisk: cannot open /dev/sdz`))
+	assert.Error(t, err)
+
+	for device, number := range map[string]int{"/dev/sdb2": 2, "/dev/nvme0n1p12": 12, "/dev/mapper/mpatha3": 3} {
+		n, err := partitionNumber(device)
+		assert.NoError(t, err)
+		assert.Equal(t, number, n, device)
+	}
+	_, err = partitionNumber("/dev/sdb")
+	assert.Error(t, err)
+}
+
+func TestMakeSgdiskNewArgs(t *testing.T) {
+	args, err := makeSgdiskNewArgs("/dev/sdb", PartitionSpec{}, 512)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--new=0:0:0", "--typecode=0:8300", "/dev/sdb"}, args)
+
+	args, err = makeSgdiskNewArgs("/dev/sdb", PartitionSpec{Number: 2, Start: 1 << 20, Size: 1 << 30, Type: "8e00", Name: "lvm"}, 4096)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--new=2:256:+262144", "--typecode=2:8e00", "--change-name=2:lvm", "/dev/sdb"}, args)
+
+	_, err = makeSgdiskNewArgs("/dev/sdb", PartitionSpec{Size: 1000}, 512)
+	assert.Error(t, err)
+	_, err = makeSgdiskNewArgs("/dev/sdb", PartitionSpec{Number: -1}, 512)
+	assert.Error(t, err)
+}
+
+func TestPartitionCommands(t *testing.T) {
+	if _, err := os.Stat("/dev/loop0"); err != nil {
+		t.Skip("no loop device available")
+	}
+	bin := t.TempDir()
+	state := t.TempDir()
+	log := filepath.Join(state, "log")
+	table := filepath.Join(state, "table")
+	// sfdisk prints the table written by the fake sgdisk, if any.
+	sfdisk := "#!/bin/sh\nif [ -f " + table + " ]; then cat " + table +
+		"; else echo 'sfdisk: /dev/loop0: does not contain a recognized partition table' >&2; exit 1; fi\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "sfdisk"), []byte(sfdisk), 0o700)) // #nosec G306
+	require.NoError(t, os.WriteFile(filepath.Join(state, "json"), []byte(sfdiskJSON), 0o600))
+	sgdisk := "#!/bin/sh\necho \"sgdisk $*\" >> " + log + "\ncp " + filepath.Join(state, "json") + " " + table + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "sgdisk"), []byte(sgdisk), 0o700)) // #nosec G306
+	writeRecorder(t, filepath.Join(bin, "partprobe"), log)
+	growpart := "#!/bin/sh\necho 'NOCHANGE: partition 1 could only be grown by 0' \nexit 1\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "growpart"), []byte(growpart), 0o700)) // #nosec G306
+
+	fs := NewFS(FSOptions{ExtraEnv: []string{"PATH=" + bin + ":/usr/bin:/bin"}})
+	ctx := context.Background()
+
+	empty, err := fs.GetPartitions(ctx, "/dev/loop0")
+	require.NoError(t, err)
+	assert.Empty(t, empty.Label)
+	assert.Empty(t, empty.Partitions)
+
+	p, err := fs.CreatePartition(ctx, "/dev/loop0", PartitionSpec{Name: "data"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, p.Number)
+	assert.Equal(t, "/dev/loop0p1", p.Device)
+
+	// The partition exists now, so the same number cannot be found as new.
+	_, err = fs.CreatePartition(ctx, "/dev/loop0", PartitionSpec{})
+	assert.Error(t, err)
+
+	require.NoError(t, fs.GrowPartition(ctx, "/dev/loop0", 1))
+	require.NoError(t, fs.DeletePartition(ctx, "/dev/loop0", 1))
+	assert.Error(t, fs.DeletePartition(ctx, "/dev/loop0", 0))
+
+	out, err := os.ReadFile(log)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	assert.Equal(t, []string{
+		"sgdisk --new=0:0:0 --typecode=0:8300 --change-name=0:data /dev/loop0",
+		" /dev/loop0",
+		"sgdisk --new=0:0:0 --typecode=0:8300 /dev/loop0",
+		" /dev/loop0",
+		"sgdisk --delete=1 /dev/loop0",
+		" /dev/loop0",
+	}, lines)
+}
@@ -0,0 +1,137 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// fsImmutableFlag is FS_IMMUTABLE_FL, the inode flag set by chattr +i.
+const fsImmutableFlag = 0x00000010
+
+// setTargetPermissions sets the ownership and mode of target, and of the
+// files below it if recursive is set. Ownership that cannot be changed on
+// NFS, e.g. because of root squashing, is logged and skipped.
+func (fs *FS) setTargetPermissions(ctx context.Context, target string, uid, gid int, mode os.FileMode, recursive bool) error {
+	path := filepath.Clean(target)
+	if err := validatePath(path); err != nil {
+		return err
+	}
+	var statfs unix.Statfs_t
+	if err := unix.Statfs(path, &statfs); err != nil {
+		return err
+	}
+	nfs := statfs.Type == unix.NFS_SUPER_MAGIC
+	log.WithFields(log.Fields{
+		"target":    path,
+		"uid":       uid,
+		"gid":       gid,
+		"mode":      mode,
+		"recursive": recursive,
+	}).Info("setting target permissions")
+	if !recursive {
+		return fs.setPathPermissions(path, uid, gid, mode, nfs)
+	}
+	return filepath.WalkDir(path, func(p string, _ os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return fs.setPathPermissions(p, uid, gid, mode, nfs)
+	})
+}
+
+// setPathPermissions sets the ownership and mode of path if they differ.
+// Symlinks are chowned but not chmodded.
+func (fs *FS) setPathPermissions(path string, uid, gid int, mode os.FileMode, nfs bool) error {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok &&
+		((uid >= 0 && int(st.Uid) != uid) || (gid >= 0 && int(st.Gid) != gid)) &&
+		!fs.dryRun(DryRunChown, path, fmt.Sprintf("%d:%d", uid, gid)) {
+		if err := os.Lchown(path, uid, gid); err != nil {
+			if !nfs || !errors.Is(err, unix.EPERM) {
+				return err
+			}
+			log.WithField("path", path).WithError(err).Warn("cannot change ownership on NFS, the export may squash root")
+		}
+	}
+	const modeBits = os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky
+	if mode == 0 || fi.Mode()&os.ModeSymlink != 0 || fi.Mode()&modeBits == mode&modeBits {
+		return nil
+	}
+	if fs.dryRun(DryRunChmod, path, (mode & modeBits).String()) {
+		return nil
+	}
+	return os.Chmod(path, mode&modeBits)
+}
+
+// setTargetImmutable sets or clears the immutable attribute of target,
+// like chattr +i and chattr -i. A mount point is not made immutable.
+func (fs *FS) setTargetImmutable(ctx context.Context, target string, immutable bool) error {
+	path := filepath.Clean(target)
+	if err := validatePath(path); err != nil {
+		return err
+	}
+	if immutable {
+		mnt, err := fs.isMountPoint(ctx, path)
+		if err != nil {
+			return err
+		}
+		if mnt {
+			return fmt.Errorf("%s is a mount point, not making it immutable", path)
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close() // #nosec G307
+	flags, err := unix.IoctlGetUint32(int(f.Fd()), unix.FS_IOC_GETFLAGS)
+	if err != nil {
+		return fmt.Errorf("failed to get attributes of %s: %v", path, err)
+	}
+	newFlags := flags &^ fsImmutableFlag
+	if immutable {
+		newFlags |= fsImmutableFlag
+	}
+	if newFlags == flags {
+		return nil
+	}
+	log.WithFields(log.Fields{
+		"target":    path,
+		"immutable": immutable,
+	}).Info("setting target immutable attribute")
+	if fs.dryRun(DryRunIoctl, path, fmt.Sprintf("FS_IOC_SETFLAGS %#x", newFlags)) {
+		return nil
+	}
+	if err := unix.IoctlSetPointerInt(int(f.Fd()), unix.FS_IOC_SETFLAGS, int(newFlags)); err != nil {
+		return fmt.Errorf("failed to set immutable attribute of %s to %t: %v", path, immutable, err)
+	}
+	return nil
+}
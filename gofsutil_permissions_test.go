@@ -0,0 +1,84 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTargetPermissions(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("changing ownership requires root")
+	}
+	ctx := context.Background()
+	fs := NewFS(FSOptions{})
+	target := t.TempDir()
+	sub := filepath.Join(target, "sub")
+	file := filepath.Join(sub, "file")
+	require.NoError(t, os.Mkdir(sub, 0o700))
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+	require.NoError(t, os.Symlink("file", filepath.Join(sub, "link")))
+
+	owner := func(p string) (uint32, uint32, os.FileMode) {
+		fi, err := os.Lstat(p)
+		require.NoError(t, err)
+		st := fi.Sys().(*syscall.Stat_t)
+		return st.Uid, st.Gid, fi.Mode() & (os.ModePerm | os.ModeSetgid)
+	}
+
+	require.NoError(t, fs.SetTargetPermissions(ctx, target, 1000, 2000, 0o775|os.ModeSetgid, false))
+	uid, gid, mode := owner(target)
+	assert.Equal(t, []any{uint32(1000), uint32(2000), 0o775 | os.ModeSetgid}, []any{uid, gid, mode})
+	uid, _, _ = owner(sub)
+	assert.Equal(t, uint32(0), uid)
+
+	require.NoError(t, fs.SetTargetPermissions(ctx, target, -1, 3000, 0, true))
+	for _, p := range []string{target, sub, file, filepath.Join(sub, "link")} {
+		_, gid, _ := owner(p)
+		assert.Equal(t, uint32(3000), gid, p)
+	}
+	uid, _, mode = owner(file)
+	assert.Equal(t, uint32(0), uid)
+	assert.Equal(t, os.FileMode(0o600), mode)
+
+	assert.Error(t, fs.SetTargetPermissions(ctx, filepath.Join(target, "missing"), 0, 0, 0, false))
+}
+
+func TestSetTargetImmutable(t *testing.T) {
+	ctx := context.Background()
+	fs := NewFS(FSOptions{})
+	target := t.TempDir()
+
+	if err := fs.SetTargetImmutable(ctx, target, true); err != nil {
+		t.Skipf("immutable attribute not supported: %v", err)
+	}
+	defer fs.SetTargetImmutable(ctx, target, false) // nolint
+	assert.Error(t, os.Mkdir(filepath.Join(target, "dir"), 0o700))
+	require.NoError(t, fs.SetTargetImmutable(ctx, target, false))
+	assert.NoError(t, os.Mkdir(filepath.Join(target, "dir"), 0o700))
+}
+
+func TestSetTargetImmutableMountPoint(t *testing.T) {
+	err := NewFS(FSOptions{}).SetTargetImmutable(context.Background(), "/proc", true)
+	assert.ErrorContains(t, err, "mount point")
+}
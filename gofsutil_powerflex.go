@@ -0,0 +1,74 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import "strings"
+
+const (
+	// PowerFlexDevDiskByIDPrefix is the prefix of the /dev/disk/by-id
+	// links of PowerFlex (ScaleIO) volumes, which are followed by
+	// <system ID>-<volume ID>.
+	PowerFlexDevDiskByIDPrefix = "/dev/disk/by-id/" + powerFlexLinkPrefix
+
+	// powerFlexLinkPrefix is the prefix of the link names of PowerFlex
+	// volumes in /dev/disk/by-id.
+	powerFlexLinkPrefix = "emc-vol-"
+
+	// sciniDevicePrefix is the prefix of the names of the block devices
+	// of the PowerFlex SDC driver, e.g. scinia.
+	sciniDevicePrefix = "scini"
+)
+
+// isSciniDevice returns true if name is the name of a PowerFlex SDC block
+// device, e.g. scinia, and not of one of its partitions.
+func isSciniDevice(name string) bool {
+	suffix, ok := strings.CutPrefix(name, sciniDevicePrefix)
+	if !ok || suffix == "" {
+		return false
+	}
+	for _, c := range suffix {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// parsePowerFlexVolumeLink returns the system and volume IDs of the
+// /dev/disk/by-id link name of a PowerFlex volume, e.g.
+// emc-vol-7f5d8fc72f3d8d0f-e3ce1fb600000001.
+func parsePowerFlexVolumeLink(name string) (systemID, volumeID string, ok bool) {
+	id, ok := strings.CutPrefix(name, powerFlexLinkPrefix)
+	if !ok {
+		return "", "", false
+	}
+	systemID, volumeID, ok = strings.Cut(id, "-")
+	if !ok || systemID == "" || volumeID == "" || strings.Contains(volumeID, "-") {
+		return "", "", false
+	}
+	return systemID, volumeID, true
+}
+
+// matchesPowerFlexVolume returns true if the link name of a PowerFlex
+// volume matches id, which is either the volume ID or
+// <system ID>-<volume ID>. IDs are compared case insensitively.
+func matchesPowerFlexVolume(name, id string) bool {
+	systemID, volumeID, ok := parsePowerFlexVolumeLink(name)
+	if !ok {
+		return false
+	}
+	if wantSystem, wantVolume, full := strings.Cut(id, "-"); full {
+		return strings.EqualFold(systemID, wantSystem) && strings.EqualFold(volumeID, wantVolume)
+	}
+	return strings.EqualFold(volumeID, id)
+}
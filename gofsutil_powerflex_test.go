@@ -0,0 +1,48 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSciniDevice(t *testing.T) {
+	assert.True(t, isSciniDevice("scinia"))
+	assert.True(t, isSciniDevice("sciniaa"))
+	assert.False(t, isSciniDevice("scinia1"))
+	assert.False(t, isSciniDevice("scini"))
+	assert.False(t, isSciniDevice("sda"))
+}
+
+func TestParsePowerFlexVolumeLink(t *testing.T) {
+	systemID, volumeID, ok := parsePowerFlexVolumeLink("emc-vol-7f5d8fc72f3d8d0f-e3ce1fb600000001")
+	assert.True(t, ok)
+	assert.Equal(t, "7f5d8fc72f3d8d0f", systemID)
+	assert.Equal(t, "e3ce1fb600000001", volumeID)
+
+	for _, name := range []string{
+		"emc-vol-7f5d8fc72f3d8d0f-e3ce1fb600000001-part1",
+		"emc-vol-7f5d8fc72f3d8d0f",
+		"wwn-0x60570970000197900046533030394146",
+	} {
+		_, _, ok = parsePowerFlexVolumeLink(name)
+		assert.False(t, ok, name)
+	}
+
+	assert.True(t, matchesPowerFlexVolume("emc-vol-7f5d8fc72f3d8d0f-e3ce1fb600000001", "E3CE1FB600000001"))
+	assert.True(t, matchesPowerFlexVolume("emc-vol-7f5d8fc72f3d8d0f-e3ce1fb600000001", "7f5d8fc72f3d8d0f-e3ce1fb600000001"))
+	assert.False(t, matchesPowerFlexVolume("emc-vol-7f5d8fc72f3d8d0f-e3ce1fb600000001", "0000000000000000-e3ce1fb600000001"))
+	assert.False(t, matchesPowerFlexVolume("emc-vol-7f5d8fc72f3d8d0f-e3ce1fb600000001", "e3ce1fb600000002"))
+}
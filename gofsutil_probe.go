@@ -0,0 +1,60 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// DefaultDeviceProbeLength is the number of bytes VerifyDeviceReadable
+	// reads when length is not positive.
+	DefaultDeviceProbeLength = 4096
+
+	// maxDeviceProbeLength is the largest number of bytes
+	// VerifyDeviceReadable reads.
+	maxDeviceProbeLength = 16 << 20
+)
+
+// ErrDeviceUnreadable is matched by the errors of VerifyDeviceReadable
+// for a device that could not be read.
+var ErrDeviceUnreadable = errors.New("device is not readable")
+
+// DeviceReadError is the error returned by VerifyDeviceReadable when the
+// device could not be read. It matches ErrDeviceUnreadable and wraps the
+// error of the read.
+type DeviceReadError struct {
+	// Device is the device.
+	Device string
+	// Offset is the offset of the range that was read.
+	Offset int64
+	// Length is the length of the range that was read.
+	Length int64
+	// Err is the error of the read.
+	Err error
+}
+
+func (e *DeviceReadError) Error() string {
+	return fmt.Sprintf("failed to read %d bytes at offset %d of %s: %v", e.Length, e.Offset, e.Device, e.Err)
+}
+
+// Unwrap returns the error of the read.
+func (e *DeviceReadError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is ErrDeviceUnreadable.
+func (e *DeviceReadError) Is(target error) bool {
+	return target == ErrDeviceUnreadable
+}
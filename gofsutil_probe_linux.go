@@ -0,0 +1,91 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// verifyDeviceReadable reads length bytes at offset of the device with
+// O_DIRECT. The read runs in a goroutine so that a device that hangs,
+// e.g. a multipath device queueing IO without paths, is reported once ctx
+// is done instead of blocking the caller.
+func (fs *FS) verifyDeviceReadable(ctx context.Context, device string, offset, length int64) error {
+	path := filepath.Clean(device)
+	if err := validatePath(path); err != nil {
+		return fmt.Errorf("Failed to validate path: %s error %v", device, err)
+	}
+	if length <= 0 {
+		length = DefaultDeviceProbeLength
+	}
+	if offset < 0 || length > maxDeviceProbeLength {
+		return fmt.Errorf("invalid range of %d bytes at offset %d to read from %s", length, offset, device)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- readDeviceDirect(path, offset, length)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("device did not respond: %w", ctx.Err())
+	}
+	if err != nil {
+		return &DeviceReadError{Device: device, Offset: offset, Length: length, Err: err}
+	}
+	return nil
+}
+
+// readDeviceDirect reads length bytes at offset of the device at path
+// with O_DIRECT, so that the data comes from the device rather than the
+// page cache. The range is widened to the logical block size of the
+// device, as O_DIRECT requires.
+func readDeviceDirect(path string, offset, length int64) error {
+	fd, err := unix.Open(path, unix.O_RDONLY|unix.O_DIRECT|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	defer unix.Close(fd) // #nosec G104
+
+	blockSize := int64(512)
+	if size, err := unix.IoctlGetInt(fd, unix.BLKSSZGET); err == nil && size > 0 {
+		blockSize = int64(size)
+	}
+	start := offset / blockSize * blockSize
+	end := (offset + length + blockSize - 1) / blockSize * blockSize
+	// Mapped memory is page aligned, as O_DIRECT requires.
+	buf, err := unix.Mmap(-1, 0, int(end-start), unix.PROT_READ|unix.PROT_WRITE, unix.MAP_ANON|unix.MAP_PRIVATE)
+	if err != nil {
+		return err
+	}
+	defer unix.Munmap(buf) // #nosec G104
+
+	for n := 0; n < len(buf); {
+		m, err := unix.Pread(fd, buf[n:], start+int64(n))
+		if err != nil {
+			return &os.PathError{Op: "read", Path: path, Err: err}
+		}
+		if m == 0 {
+			return &os.PathError{Op: "read", Path: path, Err: io.ErrUnexpectedEOF}
+		}
+		n += m
+	}
+	return nil
+}
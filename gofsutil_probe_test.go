@@ -0,0 +1,68 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyDeviceReadable(t *testing.T) {
+	ctx := context.Background()
+	image := filepath.Join(t.TempDir(), "disk.img")
+	require.NoError(t, os.WriteFile(image, make([]byte, 8192), 0o600))
+	fs := NewFS(FSOptions{})
+
+	err := fs.VerifyDeviceReadable(ctx, image, 0, 0)
+	if errors.Is(err, syscall.EINVAL) {
+		t.Skip("O_DIRECT is not supported by the filesystem of the temporary directory")
+	}
+	require.NoError(t, err)
+	// An unaligned range is widened to the block size.
+	assert.NoError(t, fs.VerifyDeviceReadable(ctx, image, 100, 1000))
+
+	err = fs.VerifyDeviceReadable(ctx, image, 8000, 1000)
+	assert.ErrorIs(t, err, ErrDeviceUnreadable)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	var readErr *DeviceReadError
+	require.ErrorAs(t, err, &readErr)
+	assert.Equal(t, image, readErr.Device)
+	assert.Equal(t, int64(8000), readErr.Offset)
+	assert.Equal(t, int64(1000), readErr.Length)
+
+	err = fs.VerifyDeviceReadable(ctx, filepath.Join(filepath.Dir(image), "missing.img"), 0, 0)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.Equal(t, CodeDeviceNotFound, ErrorCodeOf(err))
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	// The read either completes or is abandoned, it never blocks.
+	if err := fs.VerifyDeviceReadable(canceled, image, 0, 0); err != nil {
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+
+	assert.Error(t, fs.VerifyDeviceReadable(ctx, image, -1, 0))
+	assert.Error(t, fs.VerifyDeviceReadable(ctx, image, 0, maxDeviceProbeLength+1))
+	assert.Error(t, fs.VerifyDeviceReadable(ctx, "/dev/sdb;ls", 0, 0))
+}
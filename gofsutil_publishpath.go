@@ -0,0 +1,83 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"path"
+	"strings"
+)
+
+// PublishPathDevice describes the device backing a volume published at a
+// kubelet pod volume path, as returned by GetDeviceForPublishPath.
+type PublishPathDevice struct {
+	// PublishPath is the publish path, e.g.
+	// /var/lib/kubelet/pods/<uid>/volumes/kubernetes.io~csi/<pv>/mount.
+	PublishPath string `json:"publishPath" yaml:"publishPath"`
+	// StagingPath is the mount point the publish path is bind mounted
+	// from, empty if there is none, e.g. for a volume mounted directly at
+	// the publish path.
+	StagingPath string `json:"stagingPath,omitempty" yaml:"stagingPath,omitempty"`
+	// Device is the device node of the volume, e.g. /dev/dm-3.
+	Device string `json:"device" yaml:"device"`
+	// DevID is the device number of the volume.
+	DevID DevID `json:"devID" yaml:"devID"`
+	// Block is set for a raw block volume, published by bind mounting its
+	// device file.
+	Block bool `json:"block,omitempty" yaml:"block,omitempty"`
+	// FsType is the filesystem type of a filesystem volume.
+	FsType string `json:"fsType,omitempty" yaml:"fsType,omitempty"`
+	// MPathName is the name of the multipath device, e.g. mpatha, if
+	// the volume is a multipath device or a partition of one.
+	MPathName string `json:"mpathName,omitempty" yaml:"mpathName,omitempty"`
+	// DeviceNames are the path devices of the volume, e.g. sdb and sdc,
+	// or the device itself if it is not a multipath device.
+	DeviceNames []string `json:"deviceNames,omitempty" yaml:"deviceNames,omitempty"`
+	// WWN is the WWN of the volume in canonical form, if known.
+	WWN string `json:"wwn,omitempty" yaml:"wwn,omitempty"`
+	// DMUUID is the device-mapper UUID of the multipath device, if any.
+	DMUUID string `json:"dmUUID,omitempty" yaml:"dmUUID,omitempty"`
+}
+
+// isPodVolumePath returns true if p is a volume path of a kubelet pod,
+// i.e. below pods/<uid>/volumes or pods/<uid>/volumeDevices.
+func isPodVolumePath(p string) bool {
+	_, rest, ok := strings.Cut(path.Clean(p), "/pods/")
+	if !ok {
+		return false
+	}
+	_, rest, ok = strings.Cut(rest, "/")
+	return ok && (strings.HasPrefix(rest, "volumes/") || strings.HasPrefix(rest, "volumeDevices/"))
+}
+
+// stagingEntry returns the entry of the mount the publish mount is bind
+// mounted from: the mount of the same filesystem, not a pod volume
+// itself, whose root is the closest ancestor of the root of the publish
+// mount. The device file of a block volume, bind mounted from devtmpfs,
+// must have the same root.
+func stagingEntry(publish Entry, entries []Entry) (Entry, bool) {
+	var staging Entry
+	found := false
+	for _, e := range entries {
+		if e.DevID != publish.DevID || e.MountPoint == publish.MountPoint || isPodVolumePath(e.MountPoint) {
+			continue
+		}
+		within := e.Root == publish.Root
+		if publish.FSType != "devtmpfs" {
+			within = within || e.Root == "/" || strings.HasPrefix(publish.Root, e.Root+"/")
+		}
+		if within && (!found || len(e.Root) > len(staging.Root)) {
+			staging, found = e, true
+		}
+	}
+	return staging, found
+}
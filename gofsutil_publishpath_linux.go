@@ -0,0 +1,92 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// getDeviceForPublishPath returns the staging mount, device, multipath
+// device and WWN of the volume published at publishPath, found from the
+// mount table and sysfs.
+func (fs *FS) getDeviceForPublishPath(ctx context.Context, publishPath string) (*PublishPathDevice, error) {
+	publishPath = filepath.Clean(publishPath)
+	var (
+		entries []Entry
+		publish Entry
+		found   bool
+	)
+	fields := MountEntryRoot | MountEntryMountPoint | MountEntryFSType | MountEntryDevID
+	err := fs.scanProcMounts(ctx, fields, func(_ context.Context, e Entry) (bool, error) {
+		entries = append(entries, e)
+		// The last mount at the publish path hides the earlier ones.
+		if filepath.Clean(e.MountPoint) == publishPath {
+			publish, found = e, true
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%s is not mounted: %w", publishPath, os.ErrNotExist)
+	}
+
+	d := &PublishPathDevice{PublishPath: publishPath, DevID: publish.DevID}
+	if staging, ok := stagingEntry(publish, entries); ok {
+		d.StagingPath = staging.MountPoint
+	}
+	var name string
+	if publish.FSType == "devtmpfs" && publish.Root != "/" {
+		d.Block = true
+		name = path.Base(publish.Root)
+	} else {
+		d.FsType = publish.FSType
+		dir, err := filepath.EvalSymlinks(fs.sysPath("/sys/dev/block/" + publish.DevID.String()))
+		if err != nil {
+			return nil, fmt.Errorf("no block device backs %s: %w", publishPath, err)
+		}
+		name = filepath.Base(dir)
+	}
+
+	t, err := fs.getDeviceTopology(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	d.Device = fs.devPath(path.Join(defaultDevRoot, t.Name))
+	// A partition, or a device mapper device such as the partition of a
+	// multipath device, is resolved through the device it is built on.
+	for (t.Kind == DeviceKindPartition || t.Kind == DeviceKindDM) && len(t.Slaves) == 1 {
+		t = t.Slaves[0]
+	}
+	info := new(DeviceMountInfo)
+	switch t.Kind {
+	case DeviceKindMultipath:
+		info.MPathName = t.DMName
+		for _, s := range t.Slaves {
+			info.DeviceNames = append(info.DeviceNames, s.Name)
+		}
+	case DeviceKindDisk:
+		info.DeviceNames = []string{t.Name}
+	}
+	fs.fillDeviceMountInfo(ctx, info)
+	d.MPathName = info.MPathName
+	d.DeviceNames = info.DeviceNames
+	d.WWN = info.WWN
+	d.DMUUID = info.DMUUID
+	return d, nil
+}
@@ -0,0 +1,118 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	podVolume      = "/var/lib/kubelet/pods/6f1c/volumes/kubernetes.io~csi/pvc-1/mount"
+	podBlockVolume = "/var/lib/kubelet/pods/6f1c/volumeDevices/kubernetes.io~csi/pvc-2"
+	stagingVolume  = "/var/lib/kubelet/plugins/kubernetes.io/csi/csi-powermax/abc/globalmount"
+	stagingBlock   = "/var/lib/kubelet/plugins/kubernetes.io/csi/volumeDevices/staging/pvc-2"
+)
+
+func TestIsPodVolumePath(t *testing.T) {
+	assert.True(t, isPodVolumePath(podVolume))
+	assert.True(t, isPodVolumePath(podBlockVolume))
+	assert.True(t, isPodVolumePath("/noderoot"+podVolume))
+	assert.False(t, isPodVolumePath(stagingVolume))
+	assert.False(t, isPodVolumePath("/var/lib/kubelet/pods/6f1c/etc-hosts"))
+}
+
+func TestGetDeviceForPublishPath(t *testing.T) {
+	tmp := t.TempDir()
+	sysRoot := filepath.Join(tmp, "sys")
+	procRoot := filepath.Join(tmp, "proc")
+
+	// Multipath device dm-0 over sdb and sdc with a partition dm-1, and a
+	// single path NVMe namespace.
+	writeTopologySysfs(t, sysRoot, "block/sdb", nil, []string{"dm-0"}, nil)
+	writeTopologySysfs(t, sysRoot, "block/sdc", nil, []string{"dm-0"}, nil)
+	writeTopologySysfs(t, sysRoot, "block/dm-0", nil, []string{"dm-1"}, []string{"sdb", "sdc"})
+	writeTopologySysfs(t, sysRoot, "block/dm-1", nil, nil, []string{"dm-0"})
+	writeTopologySysfs(t, sysRoot, "block/nvme0n1", map[string]string{
+		"wwid": "eui.68ccf098001111a2222b3d4444a1b23c",
+	}, nil, nil)
+	for dev, dm := range map[string]map[string]string{
+		"dm-0": {"name": "mpatha", "uuid": "mpath-360000970000120001263533030313434"},
+		"dm-1": {"name": "mpatha1", "uuid": "part1-mpath-360000970000120001263533030313434"},
+	} {
+		writeSysfsAttrs(t, filepath.Join(sysRoot, "devices/block", dev, "dm"), dm)
+	}
+	require.NoError(t, os.Symlink(filepath.Join(sysRoot, "devices/block"), filepath.Join(sysRoot, "block")))
+	devBlock := filepath.Join(sysRoot, "dev", "block")
+	require.NoError(t, os.MkdirAll(devBlock, 0o750))
+	for devID, dev := range map[string]string{"253:0": "dm-0", "253:1": "dm-1", "259:0": "nvme0n1"} {
+		require.NoError(t, os.Symlink(filepath.Join(sysRoot, "devices/block", dev), filepath.Join(devBlock, devID)))
+	}
+
+	require.NoError(t, os.MkdirAll(filepath.Join(procRoot, "self"), 0o750))
+	mountInfo := mountKindMountInfo +
+		"40 22 253:1 / " + stagingVolume + " rw - ext4 /dev/mapper/mpatha1 rw\n" +
+		"41 22 253:1 / " + podVolume + " rw - ext4 /dev/mapper/mpatha1 rw\n" +
+		"42 22 253:1 /data /var/lib/kubelet/pods/77aa/volumes/kubernetes.io~csi/pvc-1/mount rw - ext4 /dev/mapper/mpatha1 rw\n" +
+		"43 22 0:5 /nvme0n1 " + stagingBlock + " rw - devtmpfs devtmpfs rw\n" +
+		"44 22 0:5 /nvme0n1 " + podBlockVolume + " rw - devtmpfs devtmpfs rw\n" +
+		"45 22 0:50 / /var/lib/kubelet/pods/6f1c/volumes/kubernetes.io~csi/pvc-3/mount rw - nfs 10.0.0.1:/export rw\n"
+	require.NoError(t, os.WriteFile(filepath.Join(procRoot, "self", "mountinfo"), []byte(mountInfo), 0o600))
+	fs := NewFS(FSOptions{SysRoot: sysRoot, ProcRoot: procRoot})
+	ctx := context.Background()
+
+	d, err := fs.GetDeviceForPublishPath(ctx, podVolume+"/")
+	require.NoError(t, err)
+	assert.Equal(t, &PublishPathDevice{
+		PublishPath: podVolume,
+		StagingPath: stagingVolume,
+		Device:      "/dev/dm-1",
+		DevID:       DevID{Major: 253, Minor: 1},
+		FsType:      "ext4",
+		MPathName:   "mpatha",
+		DeviceNames: []string{"sdb", "sdc"},
+		WWN:         "60000970000120001263533030313434",
+		DMUUID:      "mpath-360000970000120001263533030313434",
+	}, d)
+
+	// A sub directory of the staging mount.
+	d, err = fs.GetDeviceForPublishPath(ctx, "/var/lib/kubelet/pods/77aa/volumes/kubernetes.io~csi/pvc-1/mount")
+	require.NoError(t, err)
+	assert.Equal(t, stagingVolume, d.StagingPath)
+
+	d, err = fs.GetDeviceForPublishPath(ctx, podBlockVolume)
+	require.NoError(t, err)
+	assert.Equal(t, &PublishPathDevice{
+		PublishPath: podBlockVolume,
+		StagingPath: stagingBlock,
+		Device:      "/dev/nvme0n1",
+		DevID:       DevID{Major: 0, Minor: 5},
+		Block:       true,
+		DeviceNames: []string{"nvme0n1"},
+		WWN:         "68ccf098001111a2222b3d4444a1b23c",
+	}, d)
+
+	_, err = fs.GetDeviceForPublishPath(ctx, "/var/lib/kubelet/pods/6f1c/volumes/kubernetes.io~csi/pvc-3/mount")
+	assert.Error(t, err)
+	_, err = fs.GetDeviceForPublishPath(ctx, "/var/lib/kubelet/pods/6f1c/volumes/kubernetes.io~csi/pvc-4/mount")
+	assert.True(t, errors.Is(err, os.ErrNotExist), err)
+}
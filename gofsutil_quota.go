@@ -0,0 +1,108 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ProjectQuota is the usage and limit of a filesystem project.
+type ProjectQuota struct {
+	// ProjectID is the project id.
+	ProjectID uint32
+	// UsedBytes is the disk space used by the project.
+	UsedBytes int64
+	// LimitBytes is the hard limit of the disk space of the project, zero
+	// if the project is not limited.
+	LimitBytes int64
+}
+
+// ProjectQuotaMountOptions returns the mount options that enable project
+// quotas for the filesystem type.
+func ProjectQuotaMountOptions(fsType string) []string {
+	switch fsType {
+	case "xfs", "ext4":
+		return []string{"prjquota"}
+	}
+	return nil
+}
+
+// quotaFlagsRX matches the limit flags column of repquota, e.g. -- or +-.
+var quotaFlagsRX = regexp.MustCompile(`^[-+]{2}$`)
+
+// parseProjectQuotaReport returns the quota of the project projID from
+// the output of xfs_quota report -p -n -N -b, e.g.
+//
+//	#1001    4096     0  1048576     00 [--------]
+//
+// or of repquota -P -n, e.g.
+//
+//	#1001     --    4096     0  1048576      2     0     0
+//
+// Both report the disk space in KiB.
+func parseProjectQuotaReport(out string, projID uint32) (*ProjectQuota, error) {
+	id := fmt.Sprintf("#%d", projID)
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != id {
+			continue
+		}
+		fields = fields[1:]
+		if len(fields) > 0 && quotaFlagsRX.MatchString(fields[0]) {
+			fields = fields[1:]
+		}
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("quota report line %q is invalid", scanner.Text())
+		}
+		used, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("quota report line %q is invalid: %v", scanner.Text(), err)
+		}
+		hard, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("quota report line %q is invalid: %v", scanner.Text(), err)
+		}
+		return &ProjectQuota{ProjectID: projID, UsedBytes: used << 10, LimitBytes: hard << 10}, nil
+	}
+	return &ProjectQuota{ProjectID: projID}, nil
+}
+
+// findMountForPath returns the mount that path is on, i.e. the mount with
+// the longest mount point that is path or a parent of it. Of the mounts
+// stacked on the same mount point, the last one in mounts is on top and
+// is returned.
+func findMountForPath(mounts []Info, path string) (Info, bool) {
+	path = filepath.Clean(path)
+	var best Info
+	found := false
+	for _, m := range mounts {
+		mp := filepath.Clean(m.Path)
+		if path != mp && !strings.HasPrefix(path, strings.TrimSuffix(mp, "/")+"/") {
+			continue
+		}
+		bestLen := len(filepath.Clean(best.Path))
+		if !found || len(mp) > bestLen {
+			best, found = m, true
+		} else if len(mp) == bestLen {
+			// m is mounted over best.
+			best = m
+		}
+	}
+	return best, found
+}
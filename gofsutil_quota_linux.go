@@ -0,0 +1,130 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// quotaMount returns the mount of path and checks that its filesystem
+// supports project quotas.
+func (fs *FS) quotaMount(ctx context.Context, path string) (Info, error) {
+	mounts, err := fs.getMounts(ctx)
+	if err != nil {
+		return Info{}, err
+	}
+	m, ok := findMountForPath(mounts, path)
+	if !ok {
+		return Info{}, fmt.Errorf("no mount found for %s", path)
+	}
+	if m.Type != "xfs" && m.Type != "ext4" {
+		return Info{}, fmt.Errorf("project quotas are not supported on %s filesystem of %s", m.Type, path)
+	}
+	return m, nil
+}
+
+// runQuotaCommand runs a quota tool and returns its output.
+func (fs *FS) runQuotaCommand(ctx context.Context, name string, args ...string) (string, error) {
+	log.Printf("%s %v", name, args)
+	/* #nosec G204 */
+	out, err := fs.commandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s %v failed: %v: %s", name, args, err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// setProjectQuota assigns the directory path to the project projID, so
+// that everything created beneath it is accounted to the project, and
+// limits the disk space of the project to limitBytes.
+func (fs *FS) setProjectQuota(ctx context.Context, path string, projID uint32, limitBytes int64) error {
+	path = filepath.Clean(path)
+	if err := validatePath(path); err != nil {
+		return err
+	}
+	if projID == 0 {
+		return fmt.Errorf("project id 0 is reserved")
+	}
+	if limitBytes < 0 {
+		return fmt.Errorf("quota limit %d is invalid", limitBytes)
+	}
+	m, err := fs.quotaMount(ctx, path)
+	if err != nil {
+		return err
+	}
+	id := strconv.FormatUint(uint64(projID), 10)
+	// Quota tools use KiB, round the limit up.
+	limitKiB := strconv.FormatInt((limitBytes+1023)>>10, 10)
+
+	switch m.Type {
+	case "xfs":
+		// xfs_quota splits its commands on blanks and not all its
+		// versions honour quotes, reject what could change the command.
+		if strings.ContainsAny(path, " \t\n\"'\\") {
+			return fmt.Errorf("path %q cannot be given to xfs_quota", path)
+		}
+		if _, err := fs.runQuotaCommand(ctx, "xfs_quota", "-x", "-c",
+			fmt.Sprintf("project -s -p %s %s", path, id), m.Path); err != nil {
+			return err
+		}
+		if _, err := fs.runQuotaCommand(ctx, "xfs_quota", "-x", "-c",
+			fmt.Sprintf("limit -p bhard=%sk %s", limitKiB, id), m.Path); err != nil {
+			return err
+		}
+	case "ext4":
+		// Unlike xfs_quota project -s, chattr only sets the project of
+		// the directory itself unless it recurses.
+		if _, err := fs.runQuotaCommand(ctx, "chattr", "-R", "-p", id, "+P", path); err != nil {
+			return err
+		}
+		if _, err := fs.runQuotaCommand(ctx, "setquota", "-P", id, "0", limitKiB, "0", "0", m.Path); err != nil {
+			return err
+		}
+	}
+	log.WithFields(log.Fields{
+		"path":       path,
+		"projectID":  projID,
+		"limitBytes": limitBytes,
+	}).Info("project quota set")
+	return nil
+}
+
+// getProjectQuota returns the usage and limit of the project projID on
+// the filesystem of path.
+func (fs *FS) getProjectQuota(ctx context.Context, path string, projID uint32) (*ProjectQuota, error) {
+	path = filepath.Clean(path)
+	if err := validatePath(path); err != nil {
+		return nil, err
+	}
+	m, err := fs.quotaMount(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var out string
+	switch m.Type {
+	case "xfs":
+		out, err = fs.runQuotaCommand(ctx, "xfs_quota", "-x", "-c", "report -p -n -N -b", m.Path)
+	case "ext4":
+		out, err = fs.runQuotaCommand(ctx, "repquota", "-P", "-n", m.Path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseProjectQuotaReport(out, projID)
+}
@@ -0,0 +1,57 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetProjectQuota(t *testing.T) {
+	tmp := t.TempDir()
+	procRoot := filepath.Join(tmp, "proc")
+	bin := filepath.Join(tmp, "bin")
+	log := filepath.Join(tmp, "log")
+	require.NoError(t, os.MkdirAll(filepath.Join(procRoot, "self"), 0o750))
+	require.NoError(t, os.MkdirAll(bin, 0o750))
+	mountinfo := "40 22 8:16 / /export/xfs rw,relatime - xfs /dev/sdb rw,prjquota\n" +
+		"41 22 8:32 / /export/ext4 rw,relatime - ext4 /dev/sdc rw,prjquota\n"
+	require.NoError(t, os.WriteFile(filepath.Join(procRoot, "self", "mountinfo"), []byte(mountinfo), 0o600))
+	for _, name := range []string{"xfs_quota", "chattr", "setquota"} {
+		writeRecorder(t, filepath.Join(bin, name), log)
+	}
+	fs := NewFS(FSOptions{ProcRoot: procRoot, ExtraEnv: []string{"PATH=" + bin}})
+	ctx := context.Background()
+
+	require.NoError(t, fs.setProjectQuota(ctx, "/export/xfs/pvc-1", 1001, 1<<20))
+	require.NoError(t, fs.setProjectQuota(ctx, "/export/ext4/pvc-2", 1002, 1<<20))
+	out, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		" -x -c project -s -p /export/xfs/pvc-1 1001 /export/xfs",
+		" -x -c limit -p bhard=1024k 1001 /export/xfs",
+		// The existing content of the directory joins the project.
+		" -R -p 1002 +P /export/ext4/pvc-2",
+		" -P 1002 0 1024 0 0 /export/ext4",
+	}, strings.Split(strings.TrimSuffix(string(out), "\n"), "\n"))
+
+	for _, path := range []string{"/export/xfs/pvc 1", `/export/xfs/pvc"1`, "/export/xfs/pvc'1"} {
+		assert.ErrorContains(t, fs.setProjectQuota(ctx, path, 1001, 1<<20), "cannot be given to xfs_quota", path)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProjectQuotaReport(t *testing.T) {
+	xfsReport := `#0          0          0          0     00 [--------]
+#1001     4096          0    1048576     00 [--------]
+#1002        8          0          0     00 [--------]
+`
+	q, err := parseProjectQuotaReport(xfsReport, 1001)
+	require.NoError(t, err)
+	assert.Equal(t, &ProjectQuota{ProjectID: 1001, UsedBytes: 4096 << 10, LimitBytes: 1 << 30}, q)
+
+	repquota := `*** Report for project quotas on device /dev/sdb
+Block grace time: 7days; Inode grace time: 7days
+                        Block limits                File limits
+Project         used    soft    hard  grace    used  soft  hard  grace
+----------------------------------------------------------------------
+#0        --      20       0       0              2     0     0
+#1001     +-    2052       0    2048  6days       3     0     0
+`
+	q, err = parseProjectQuotaReport(repquota, 1001)
+	require.NoError(t, err)
+	assert.Equal(t, &ProjectQuota{ProjectID: 1001, UsedBytes: 2052 << 10, LimitBytes: 2048 << 10}, q)
+
+	// A project without usage is not reported.
+	q, err = parseProjectQuotaReport(repquota, 1003)
+	require.NoError(t, err)
+	assert.Equal(t, &ProjectQuota{ProjectID: 1003}, q)
+
+	_, err = parseProjectQuotaReport("#1001 -- 12\n", 1001)
+	assert.Error(t, err)
+	_, err = parseProjectQuotaReport("#1001 12 0 lots\n", 1001)
+	assert.Error(t, err)
+}
+
+func TestFindMountForPath(t *testing.T) {
+	mounts := []Info{
+		{Device: "/dev/sda1", Path: "/", Type: "ext4"},
+		{Device: "/dev/sdb", Path: "/export", Type: "xfs"},
+		{Device: "/dev/sdc", Path: "/export/nested", Type: "ext4"},
+	}
+	m, ok := findMountForPath(mounts, "/export/pvc-1")
+	require.True(t, ok)
+	assert.Equal(t, "/dev/sdb", m.Device)
+	m, ok = findMountForPath(mounts, "/export/nested/pvc-2/")
+	require.True(t, ok)
+	assert.Equal(t, "/dev/sdc", m.Device)
+	m, ok = findMountForPath(mounts, "/exports")
+	require.True(t, ok)
+	assert.Equal(t, "/dev/sda1", m.Device)
+	_, ok = findMountForPath(mounts[1:], "/var")
+	assert.False(t, ok)
+
+	// The last of the mounts on the same mount point is on top.
+	stacked := append(mounts, Info{Device: "/dev/sdd", Path: "/export/", Type: "xfs"})
+	m, ok = findMountForPath(stacked, "/export/pvc-1")
+	require.True(t, ok)
+	assert.Equal(t, "/dev/sdd", m.Device)
+
+	assert.Equal(t, []string{"prjquota"}, ProjectQuotaMountOptions("xfs"))
+	assert.Nil(t, ProjectQuotaMountOptions("nfs"))
+}
@@ -0,0 +1,72 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RemoveBlockDeviceOptions are the options of RemoveBlockDeviceWithOptions.
+type RemoveBlockDeviceOptions struct {
+	// Flush flushes the buffers of the device, like blockdev --flushbufs,
+	// before it is deleted.
+	Flush bool
+	// CheckHolders refuses to delete the device if it or one of its
+	// partitions is held by another block device, e.g. a multipath or
+	// LVM device.
+	CheckHolders bool
+	// CheckOpenHandles refuses to delete the device if a process has it
+	// or one of its partitions open, like fuser.
+	CheckOpenHandles bool
+}
+
+// DeviceProcess is a process that has a device open.
+type DeviceProcess struct {
+	// PID is the process ID.
+	PID int
+	// Command is the command name of the process, e.g. mkfs.ext4.
+	Command string
+}
+
+func (p DeviceProcess) String() string {
+	return fmt.Sprintf("%d (%s)", p.PID, p.Command)
+}
+
+// DeviceBusyError is returned by RemoveBlockDeviceWithOptions when the
+// device is held by other block devices or open by processes.
+type DeviceBusyError struct {
+	// Device is the device.
+	Device string
+	// Holders are the names of the block devices holding the device or
+	// its partitions, e.g. dm-3.
+	Holders []string
+	// Processes are the processes having the device or its partitions
+	// open.
+	Processes []DeviceProcess
+}
+
+func (e *DeviceBusyError) Error() string {
+	var users []string
+	if len(e.Holders) > 0 {
+		users = append(users, "held by "+strings.Join(e.Holders, ", "))
+	}
+	if len(e.Processes) > 0 {
+		procs := make([]string, 0, len(e.Processes))
+		for _, p := range e.Processes {
+			procs = append(procs, p.String())
+		}
+		users = append(users, "open by "+strings.Join(procs, ", "))
+	}
+	return fmt.Sprintf("device %s is busy: %s", e.Device, strings.Join(users, "; "))
+}
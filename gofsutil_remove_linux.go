@@ -0,0 +1,148 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// removeBlockDeviceWithOptions removes the block device like
+// removeBlockDevice, after checking that it is not busy and flushing its
+// buffers as requested by opts.
+func (fs *FS) removeBlockDeviceWithOptions(ctx context.Context, blockDevicePath string, opts RemoveBlockDeviceOptions) error {
+	name := filepath.Base(blockDevicePath)
+	if dev, err := filepath.EvalSymlinks(blockDevicePath); err == nil {
+		name = filepath.Base(dev)
+	}
+	names := fs.sysBlockDeviceNames(name)
+
+	busy := &DeviceBusyError{Device: blockDevicePath}
+	if opts.CheckHolders {
+		busy.Holders = fs.sysBlockHolders(name, names)
+	}
+	if opts.CheckOpenHandles {
+		busy.Processes = fs.deviceProcesses(names)
+	}
+	if len(busy.Holders) > 0 || len(busy.Processes) > 0 {
+		log.WithField("device", blockDevicePath).WithError(busy).Error("not removing busy block device")
+		return busy
+	}
+
+	if opts.Flush {
+		if err := fs.flushBlockDevice(ctx, blockDevicePath); err != nil {
+			return err
+		}
+	}
+	return fs.removeBlockDevice(ctx, "/dev/"+name)
+}
+
+// sysBlockDeviceNames returns the name of the block device followed by
+// the names of its partitions, as found in the sysfs block directory.
+func (fs *FS) sysBlockDeviceNames(name string) []string {
+	names := []string{name}
+	entries, _ := os.ReadDir(filepath.Join(fs.sysBlockDir(), name))
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), name) &&
+			sysfsEntryExists(filepath.Join(fs.sysBlockDir(), name, e.Name(), "partition")) {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+// sysBlockHolders returns the holders of the block device name and of its
+// partitions, names[1:].
+func (fs *FS) sysBlockHolders(name string, names []string) []string {
+	var holders []string
+	for _, n := range names {
+		dir := filepath.Join(fs.sysBlockDir(), name, "holders")
+		if n != name {
+			dir = filepath.Join(fs.sysBlockDir(), name, n, "holders")
+		}
+		entries, _ := os.ReadDir(dir)
+		for _, e := range entries {
+			holders = append(holders, e.Name())
+		}
+	}
+	return holders
+}
+
+// deviceProcesses returns the processes with an open file descriptor of
+// one of the block devices with the given names, found by reading the fd
+// links of the processes in /proc. Processes that cannot be inspected are
+// skipped.
+func (fs *FS) deviceProcesses(names []string) []DeviceProcess {
+	isDevice := make(map[string]bool, len(names))
+	for _, name := range names {
+		isDevice["/dev/"+name] = true
+	}
+	proc := fs.procPath("/proc")
+	entries, err := os.ReadDir(proc)
+	if err != nil {
+		log.WithError(err).Warn("cannot list processes")
+		return nil
+	}
+	var processes []DeviceProcess
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join(proc, e.Name(), "fd")
+		fds, _ := os.ReadDir(fdDir)
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !isDevice[link] {
+				continue
+			}
+			comm, _ := os.ReadFile(filepath.Join(proc, e.Name(), "comm"))
+			processes = append(processes, DeviceProcess{PID: pid, Command: strings.TrimSpace(string(comm))})
+			break
+		}
+	}
+	return processes
+}
+
+// flushBlockDevice writes the dirty pages of the block device and
+// invalidates its buffers with the BLKFLSBUF ioctl, like blockdev
+// --flushbufs.
+func (fs *FS) flushBlockDevice(_ context.Context, device string) error {
+	path := filepath.Clean(device)
+	if err := validatePath(path); err != nil {
+		return err
+	}
+	log.WithField("device", path).Info("flushing block device buffers")
+	if fs.dryRun(DryRunIoctl, path, "BLKFLSBUF") {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close() // #nosec G307
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to flush %s: %v", path, err)
+	}
+	if err := unix.IoctlSetInt(int(f.Fd()), unix.BLKFLSBUF, 0); err != nil {
+		return fmt.Errorf("failed to flush buffers of %s: %v", path, err)
+	}
+	return nil
+}
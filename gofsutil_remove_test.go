@@ -0,0 +1,75 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveBlockDeviceWithOptions(t *testing.T) {
+	ctx := context.Background()
+	sys := t.TempDir()
+	proc := t.TempDir()
+	dev := filepath.Join(sys, "block", "sdz")
+	writeSysfsAttrs(t, filepath.Join(dev, "device"), map[string]string{"state": "running", "delete": ""})
+	writeSysfsAttrs(t, filepath.Join(dev, "sdz1"), map[string]string{"partition": "1"})
+	require.NoError(t, os.MkdirAll(filepath.Join(dev, "sdz1", "holders"), 0o755))
+	fs := NewFS(FSOptions{SysRoot: sys, ProcRoot: proc, DryRun: true})
+	all := RemoveBlockDeviceOptions{Flush: true, CheckHolders: true, CheckOpenHandles: true}
+
+	// An unused device is flushed and deleted.
+	require.NoError(t, fs.removeBlockDeviceWithOptions(ctx, "/dev/sdz", all))
+	assert.Equal(t, []DryRunAction{
+		{Op: DryRunIoctl, Path: "/dev/sdz", Data: "BLKFLSBUF"},
+		{Op: DryRunWrite, Path: filepath.Join(dev, "device", "delete"), Data: "1"},
+	}, fs.GetDryRunActions())
+	fs.ClearDryRunActions()
+
+	// A partition held by a device mapper device and open by a process.
+	require.NoError(t, os.WriteFile(filepath.Join(dev, "sdz1", "holders", "dm-3"), nil, 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(proc, "123", "fd"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(proc, "123", "comm"), []byte("mkfs.ext4\n"), 0o600))
+	require.NoError(t, os.Symlink("/dev/sdz1", filepath.Join(proc, "123", "fd", "3")))
+	require.NoError(t, os.MkdirAll(filepath.Join(proc, "456", "fd"), 0o755))
+	require.NoError(t, os.Symlink("/dev/sdy", filepath.Join(proc, "456", "fd", "3")))
+	require.NoError(t, os.MkdirAll(filepath.Join(proc, "self"), 0o755))
+
+	err := fs.removeBlockDeviceWithOptions(ctx, "/dev/sdz", all)
+	var busy *DeviceBusyError
+	require.True(t, errors.As(err, &busy))
+	assert.Equal(t, "/dev/sdz", busy.Device)
+	assert.Equal(t, []string{"dm-3"}, busy.Holders)
+	assert.Equal(t, []DeviceProcess{{PID: 123, Command: "mkfs.ext4"}}, busy.Processes)
+	assert.Equal(t, "device /dev/sdz is busy: held by dm-3; open by 123 (mkfs.ext4)", err.Error())
+	assert.Equal(t, CodeDeviceInUse, classifyError(err))
+	assert.Empty(t, fs.GetDryRunActions())
+
+	// Only the requested checks are done.
+	err = fs.removeBlockDeviceWithOptions(ctx, "/dev/sdz", RemoveBlockDeviceOptions{CheckOpenHandles: true})
+	require.True(t, errors.As(err, &busy))
+	assert.Empty(t, busy.Holders)
+	require.NoError(t, fs.removeBlockDeviceWithOptions(ctx, "/dev/sdz", RemoveBlockDeviceOptions{}))
+	assert.Equal(t, []DryRunAction{
+		{Op: DryRunWrite, Path: filepath.Join(dev, "device", "delete"), Data: "1"},
+	}, fs.GetDryRunActions())
+}
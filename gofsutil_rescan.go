@@ -0,0 +1,50 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultFullRescanInterval is the minimum time between two rescans of
+// all the scsi hosts when FSOptions.FullRescanInterval is zero.
+const DefaultFullRescanInterval = 30 * time.Second
+
+// fullRescanLimiter limits how often all the scsi hosts are rescanned
+// because no host matched the targets of a rescan. The zero value is
+// ready to use.
+type fullRescanLimiter struct {
+	mu   sync.Mutex
+	last time.Time
+}
+
+// allow returns true, and records the time of the rescan, if the last
+// full rescan was at least interval ago. A negative interval allows all
+// rescans, DefaultFullRescanInterval is used if interval is zero. When
+// false, it also returns how long to wait until the next full rescan.
+func (l *fullRescanLimiter) allow(interval time.Duration) (bool, time.Duration) {
+	if interval == 0 {
+		interval = DefaultFullRescanInterval
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if interval > 0 && !l.last.IsZero() {
+		if wait := l.last.Add(interval).Sub(now); wait > 0 {
+			return false, wait
+		}
+	}
+	l.last = now
+	return true, 0
+}
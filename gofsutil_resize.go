@@ -0,0 +1,110 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrNoResizeNeeded is returned by ResizeFSWithOptions when the
+// filesystem already fills the block device.
+var ErrNoResizeNeeded = errors.New("filesystem already fills the device")
+
+// DefaultResizeTolerance is the default number of bytes by which a
+// filesystem may be smaller than its device and still count as expanded.
+// Filesystems are sized in whole blocks or allocation groups, so they
+// rarely match the device size exactly.
+const DefaultResizeTolerance = 16 << 20
+
+// ResizeFSOptions are the options of ResizeFSWithOptions.
+type ResizeFSOptions struct {
+	// DryRun only compares the filesystem and device sizes and does not
+	// resize the filesystem.
+	DryRun bool
+	// Tolerance overrides DefaultResizeTolerance when positive.
+	Tolerance int64
+}
+
+func (o ResizeFSOptions) tolerance() int64 {
+	if o.Tolerance > 0 {
+		return o.Tolerance
+	}
+	return DefaultResizeTolerance
+}
+
+// parseDumpe2fsSize returns the size in bytes of an ext filesystem from
+// the output of dumpe2fs -h.
+func parseDumpe2fsSize(out string) (int64, error) {
+	var blockCount, blockSize int64
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		var err error
+		switch strings.TrimSpace(key) {
+		case "Block count":
+			blockCount, err = strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		case "Block size":
+			blockSize, err = strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse dumpe2fs output line %q: %v", scanner.Text(), err)
+		}
+	}
+	if blockCount == 0 || blockSize == 0 {
+		return 0, errors.New("block count or block size not found in dumpe2fs output")
+	}
+	return blockCount * blockSize, nil
+}
+
+// parseXFSInfoSize returns the size in bytes of the data section of an
+// xfs filesystem from the output of xfs_info, e.g.
+//
+//	data     =                       bsize=4096   blocks=262144, imaxpct=25
+func parseXFSInfoSize(out string) (int64, error) {
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(strings.TrimSpace(line), "data") {
+			continue
+		}
+		var blocks, bsize int64
+		for _, field := range strings.Fields(strings.ReplaceAll(line, ",", " ")) {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			var err error
+			switch key {
+			case "blocks":
+				blocks, err = strconv.ParseInt(value, 10, 64)
+			case "bsize":
+				bsize, err = strconv.ParseInt(value, 10, 64)
+			}
+			if err != nil {
+				return 0, fmt.Errorf("failed to parse xfs_info output line %q: %v", line, err)
+			}
+		}
+		if blocks == 0 || bsize == 0 {
+			return 0, fmt.Errorf("blocks or bsize not found in xfs_info output line %q", line)
+		}
+		return blocks * bsize, nil
+	}
+	return 0, errors.New("data section not found in xfs_info output")
+}
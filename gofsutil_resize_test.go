@@ -0,0 +1,86 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDumpe2fsSize(t *testing.T) {
+	out := `dumpe2fs 1.46.5 (30-Dec-2021)
+Filesystem volume name:   <none>
+Block count:              2621440
+Reserved block count:     131072
+Free blocks:              2554070
+Block size:               4096
+Fragment size:            4096
+`
+	size, err := parseDumpe2fsSize(out)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2621440*4096), size)
+
+	_, err = parseDumpe2fsSize("Block count: 10\n")
+	assert.Error(t, err)
+	_, err = parseDumpe2fsSize("Block count: ten\nBlock size: 4096\n")
+	assert.Error(t, err)
+}
+
+func TestParseXFSInfoSize(t *testing.T) {
+	out := `meta-data=/dev/sdb               isize=512    agcount=4, agsize=65536 blks
+         =                       sectsz=512   attr=2, projid32bit=1
+data     =                       bsize=4096   blocks=262144, imaxpct=25
+         =                       sunit=0      swidth=0 blks
+naming   =version 2              bsize=4096   ascii-ci=0, ftype=1
+log      =internal log           bsize=4096   blocks=2560, version=2
+`
+	size, err := parseXFSInfoSize(out)
+	require.NoError(t, err)
+	assert.Equal(t, int64(262144*4096), size)
+
+	_, err = parseXFSInfoSize("naming   =version 2              bsize=4096\n")
+	assert.Error(t, err)
+	_, err = parseXFSInfoSize("data     =                       bsize=4096   imaxpct=25\n")
+	assert.Error(t, err)
+}
+
+func TestBlockDeviceSize(t *testing.T) {
+	tmp := t.TempDir()
+	dev := filepath.Join(tmp, "dm-3")
+	require.NoError(t, os.WriteFile(dev, nil, 0o600))
+	link := filepath.Join(tmp, "mpatha")
+	require.NoError(t, os.Symlink(dev, link))
+	sizeDir := filepath.Join(tmp, "sys", "class", "block", "dm-3")
+	require.NoError(t, os.MkdirAll(sizeDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(sizeDir, "size"), []byte("20971520\n"), 0o600))
+
+	fs := NewFS(FSOptions{SysRoot: filepath.Join(tmp, "sys")})
+	size, err := fs.blockDeviceSize(link)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10<<30), size)
+
+	_, err = fs.blockDeviceSize(filepath.Join(tmp, "missing"))
+	assert.Error(t, err)
+}
+
+func TestResizeFSOptionsTolerance(t *testing.T) {
+	assert.Equal(t, int64(DefaultResizeTolerance), ResizeFSOptions{}.tolerance())
+	assert.Equal(t, int64(4096), ResizeFSOptions{Tolerance: 4096}.tolerance())
+}
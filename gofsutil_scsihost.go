@@ -0,0 +1,52 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+const (
+	scsiHostsPath  = "/sys/class/scsi_host"
+	iscsiHostsPath = "/sys/class/iscsi_host"
+	sasHostsPath   = "/sys/class/sas_host"
+)
+
+// SCSIHostKind is the transport of a SCSI host.
+type SCSIHostKind string
+
+const (
+	// SCSIHostFC is a Fibre Channel HBA, which has an fc_host.
+	SCSIHostFC SCSIHostKind = "fc"
+	// SCSIHostISCSI is an iSCSI host, e.g. of an iscsi_tcp session or an
+	// iSCSI offload adapter.
+	SCSIHostISCSI SCSIHostKind = "iscsi"
+	// SCSIHostSAS is a SAS controller.
+	SCSIHostSAS SCSIHostKind = "sas"
+	// SCSIHostOther is any other SCSI host, e.g. an AHCI or virtio-scsi
+	// controller.
+	SCSIHostOther SCSIHostKind = "other"
+)
+
+// SCSIHost describes a local SCSI host.
+type SCSIHost struct {
+	// Name is the name of the host, e.g. host3.
+	Name string
+	// Kind is the transport of the host.
+	Kind SCSIHostKind
+	// ProcName is the name of the driver of the host, e.g. qla2xxx, lpfc,
+	// iscsi_tcp or mpt3sas.
+	ProcName string
+	// Model is the model of the adapter, if the driver reports it.
+	Model string
+	// State is the state of the host, e.g. running or recovery.
+	State string
+	// FCHost is the FC host of a Fibre Channel HBA.
+	FCHost *FCHostInfo
+}
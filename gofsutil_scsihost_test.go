@@ -0,0 +1,86 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHostSCSIHosts(t *testing.T) {
+	sysRoot := t.TempDir()
+	hostsDir := filepath.Join(sysRoot, "class", "scsi_host")
+	writeSysfsAttrs(t, filepath.Join(hostsDir, "host0"), map[string]string{"proc_name": "ahci", "state": "running"})
+	writeSysfsAttrs(t, filepath.Join(hostsDir, "host3"), map[string]string{
+		"proc_name":  "qla2xxx",
+		"state":      "running",
+		"model_name": "QLE2742",
+	})
+	writeSysfsAttrs(t, filepath.Join(sysRoot, "class", "fc_host", "host3"), map[string]string{
+		"port_name":  "0x21000024ff7b1a2c",
+		"port_state": "Online",
+	})
+	writeSysfsAttrs(t, filepath.Join(hostsDir, "host4"), map[string]string{
+		"proc_name": "lpfc",
+		"state":     "recovery",
+		"modelname": "LPe32002-M2",
+	})
+	writeSysfsAttrs(t, filepath.Join(sysRoot, "class", "fc_host", "host4"), map[string]string{"port_state": "Linkdown"})
+	writeSysfsAttrs(t, filepath.Join(hostsDir, "host7"), map[string]string{"proc_name": "iscsi_tcp", "state": "running"})
+	require.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "class", "iscsi_host", "host7"), 0o750))
+	writeSysfsAttrs(t, filepath.Join(hostsDir, "host9"), map[string]string{"proc_name": "mpt3sas", "state": "running"})
+	require.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "class", "sas_host", "host9"), 0o750))
+
+	fs := NewFS(FSOptions{SysRoot: sysRoot})
+	hosts, err := fs.getHostSCSIHosts(context.Background())
+	require.NoError(t, err)
+	require.Len(t, hosts, 5)
+
+	assert.Equal(t, SCSIHost{Name: "host0", Kind: SCSIHostOther, ProcName: "ahci", State: "running"}, hosts[0])
+	assert.Equal(t, SCSIHostFC, hosts[1].Kind)
+	assert.Equal(t, "QLE2742", hosts[1].Model)
+	require.NotNil(t, hosts[1].FCHost)
+	assert.Equal(t, "0x21000024ff7b1a2c", hosts[1].FCHost.PortName)
+	assert.True(t, hosts[1].FCHost.Online())
+	assert.Equal(t, "LPe32002-M2", hosts[2].Model)
+	assert.Equal(t, "recovery", hosts[2].State)
+	assert.False(t, hosts[2].FCHost.Online())
+	assert.Equal(t, SCSIHostISCSI, hosts[3].Kind)
+	assert.Nil(t, hosts[3].FCHost)
+	assert.Equal(t, SCSIHostSAS, hosts[4].Kind)
+
+	fs = NewFS(FSOptions{SysRoot: filepath.Join(sysRoot, "missing")})
+	hosts, err = fs.getHostSCSIHosts(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, hosts)
+}
+
+func TestRescanSCSIHostOpError(t *testing.T) {
+	fs := NewFS(FSOptions{SysRoot: t.TempDir()})
+
+	// The error reports the targets of the rescan, not the LUN.
+	err := fs.RescanSCSIHost(context.Background(), []string{"iqn.1992-04.com.emc:a", "iqn.1992-04.com.emc:b"}, "1")
+	var opErr *OpError
+	require.ErrorAs(t, err, &opErr)
+	assert.Equal(t, "rescan", opErr.Op)
+	assert.Equal(t, "iqn.1992-04.com.emc:a,iqn.1992-04.com.emc:b", opErr.Target)
+	assert.True(t, os.IsNotExist(opErr.Err))
+}
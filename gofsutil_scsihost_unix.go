@@ -0,0 +1,67 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// getHostSCSIHosts returns the local SCSI hosts with their driver, model,
+// state and transport.
+func (fs *FS) getHostSCSIHosts(_ context.Context) ([]SCSIHost, error) {
+	hosts := make([]SCSIHost, 0)
+	hostsDir := fs.sysPath(scsiHostsPath)
+	entries, err := os.ReadDir(hostsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return hosts, nil
+		}
+		return hosts, err
+	}
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), "host") {
+			continue
+		}
+		dir := filepath.Join(hostsDir, e.Name())
+		h := SCSIHost{
+			Name:     e.Name(),
+			Kind:     SCSIHostOther,
+			ProcName: readSysfsAttr(filepath.Join(dir, "proc_name")),
+			State:    readSysfsAttr(filepath.Join(dir, "state")),
+		}
+		// qla2xxx reports model_name, lpfc modelname.
+		for _, attr := range []string{"model_name", "modelname"} {
+			if h.Model = readSysfsAttr(filepath.Join(dir, attr)); h.Model != "" {
+				break
+			}
+		}
+		switch {
+		case sysfsEntryExists(filepath.Join(fs.sysPath(fcHostsPath), e.Name())):
+			h.Kind = SCSIHostFC
+			fcHost := fs.readFCHostInfo(e.Name())
+			h.FCHost = &fcHost
+		case sysfsEntryExists(filepath.Join(fs.sysPath(iscsiHostsPath), e.Name())):
+			h.Kind = SCSIHostISCSI
+		case sysfsEntryExists(filepath.Join(fs.sysPath(sasHostsPath), e.Name())):
+			h.Kind = SCSIHostSAS
+		}
+		hosts = append(hosts, h)
+	}
+	return hosts, nil
+}
@@ -0,0 +1,117 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"strings"
+)
+
+// minRedactedValueLength is the length below which a value is only
+// redacted as part of its option, e.g. secret=ab, since replacing a short
+// value on its own rewrites unrelated text of the message.
+const minRedactedValueLength = 4
+
+// sensitiveOptionsKey is the context key of the sensitive mount options of
+// MountSensitive.
+type sensitiveOptionsKey struct{}
+
+// withSensitiveOptions returns ctx with the sensitive mount options, which
+// have to be redacted from the logs and errors of the mount.
+func withSensitiveOptions(ctx context.Context, sensitive []string) context.Context {
+	if len(sensitive) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, sensitiveOptionsKey{}, sensitive)
+}
+
+// sensitiveOptions returns the sensitive mount options of ctx.
+func sensitiveOptions(ctx context.Context) []string {
+	sensitive, _ := ctx.Value(sensitiveOptionsKey{}).([]string)
+	return sensitive
+}
+
+// redactOptions returns s with the sensitive options, and their values,
+// replaced, e.g. secret=AQD...== by secret=****.
+func redactOptions(s string, sensitive []string) string {
+	for _, opt := range sensitive {
+		if opt == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(opt, "=")
+		if !ok {
+			if len(opt) >= minRedactedValueLength {
+				s = strings.ReplaceAll(s, opt, "****")
+			}
+			continue
+		}
+		s = strings.ReplaceAll(s, opt, key+"=****")
+		if len(value) >= minRedactedValueLength {
+			s = strings.ReplaceAll(s, value, "****")
+		}
+	}
+	return s
+}
+
+// sensitiveError is an error with sensitive mount options redacted from
+// its message. It wraps the redacted copies of the errors it replaces, so
+// that unwrapping it does not disclose the options either.
+type sensitiveError struct {
+	errs []error
+	msg  string
+}
+
+func (e *sensitiveError) Error() string {
+	return e.msg
+}
+
+func (e *sensitiveError) Unwrap() []error {
+	return e.errs
+}
+
+// redactError returns err with the sensitive options redacted from its
+// message and from the errors it wraps. The errors that contain no
+// sensitive option, e.g. sentinel errors, are kept, while the others, e.g.
+// an *exec.ExitError, are replaced by redacted copies. It returns err if
+// there is nothing to redact.
+func redactError(err error, sensitive []string) error {
+	if err == nil {
+		return nil
+	}
+	msg := redactOptions(err.Error(), sensitive)
+	if msg == err.Error() {
+		return err
+	}
+	var wrapped []error
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		wrapped = []error{e.Unwrap()}
+	case interface{ Unwrap() []error }:
+		wrapped = e.Unwrap()
+	}
+	redacted := &sensitiveError{msg: msg}
+	for _, w := range wrapped {
+		if w != nil {
+			redacted.errs = append(redacted.errs, redactError(w, sensitive))
+		}
+	}
+	return redacted
+}
+
+// mountSensitive mounts source onto target with opts and sensitiveOpts,
+// keeping sensitiveOpts out of the logs, records and errors of the mount.
+func (fs *FS) mountSensitive(ctx context.Context, source, target, fsType string, opts, sensitiveOpts []string) error {
+	ctx = withSensitiveOptions(ctx, sensitiveOpts)
+	allOpts := append(opts[:len(opts):len(opts)], sensitiveOpts...)
+	return redactError(fs.mount(ctx, source, target, fsType, allOpts...), sensitiveOpts)
+}
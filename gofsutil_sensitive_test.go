@@ -0,0 +1,104 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactOptions(t *testing.T) {
+	sensitive := []string{"secret=AQDx0x==", "credsblob"}
+	assert.Equal(t, "-o name=admin,secret=****,**** src /mnt",
+		redactOptions("-o name=admin,secret=AQDx0x==,credsblob src /mnt", sensitive))
+	assert.Equal(t, "bad key ****", redactOptions("bad key AQDx0x==", sensitive))
+	assert.Equal(t, "unchanged", redactOptions("unchanged", nil))
+
+	// Short values are only redacted as part of their option.
+	assert.Equal(t, "uid=1000,pin=**** pin 12", redactOptions("uid=1000,pin=12 pin 12", []string{"pin=12"}))
+
+	err := errors.New("mount option: secret=AQDx0x== is invalid")
+	redacted := redactError(err, sensitive)
+	assert.Equal(t, "mount option: secret=**** is invalid", redacted.Error())
+	assert.Same(t, err, redactError(err, []string{"other"}))
+	assert.NoError(t, redactError(nil, sensitive))
+
+	// The wrapped errors are redacted as well, keeping the sentinels.
+	pathErr := &os.PathError{Op: "open", Path: "/run/secret=AQDx0x==", Err: os.ErrPermission}
+	redacted = redactError(fmt.Errorf("mount with secret=AQDx0x==: %w", pathErr), sensitive)
+	assert.Equal(t, "mount with secret=****: open /run/secret=****: permission denied", redacted.Error())
+	assert.ErrorIs(t, redacted, os.ErrPermission)
+	var asPathErr *os.PathError
+	assert.False(t, errors.As(redacted, &asPathErr))
+	for _, e := range redactedChain(redacted) {
+		assert.NotContains(t, e.Error(), "AQDx0x")
+	}
+}
+
+// redactedChain returns err and all the errors it wraps.
+func redactedChain(err error) []error {
+	chain := []error{err}
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		chain = append(chain, redactedChain(e.Unwrap())...)
+	case interface{ Unwrap() []error }:
+		for _, w := range e.Unwrap() {
+			chain = append(chain, redactedChain(w)...)
+		}
+	}
+	return chain
+}
+
+func TestMountSensitive(t *testing.T) {
+	dir := t.TempDir()
+	record := filepath.Join(dir, "record")
+	mount := filepath.Join(dir, "mount")
+	script := "#!/bin/sh\necho \"$*\" >> " + record + "\necho \"mount error: $*\" >&2\nexit 32\n"
+	require.NoError(t, os.WriteFile(mount, []byte(script), 0o700)) // #nosec G306
+	target := filepath.Join(dir, "target")
+	require.NoError(t, os.Mkdir(target, 0o750))
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	fs := NewFS(FSOptions{MountBinary: mount, CommandHistorySize: 2})
+	err := fs.MountSensitive(context.Background(), "10.0.0.1:/", target, "nfs",
+		[]string{"name=admin"}, []string{"secret=AQDx0x=="})
+	require.Error(t, err)
+	assert.Equal(t, CodeMountFailed, ErrorCodeOf(err))
+	assert.NotContains(t, err.Error(), "AQDx0x")
+	assert.Contains(t, err.Error(), "secret=****")
+	assert.NotContains(t, logs.String(), "AQDx0x")
+
+	history := fs.GetCommandHistory()
+	require.Len(t, history, 1)
+	assert.Contains(t, history[0].Args, "name=admin,secret=****")
+	assert.NotContains(t, history[0].Output, "AQDx0x")
+
+	buf, err := os.ReadFile(record)
+	require.NoError(t, err)
+	assert.Contains(t, string(buf), "name=admin,secret=AQDx0x==")
+}
@@ -0,0 +1,44 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import "time"
+
+const (
+	// DefaultDeviceSettleTimeout is how long WaitForDeviceToSettle waits
+	// when the context has no deadline.
+	DefaultDeviceSettleTimeout = 30 * time.Second
+	// DeviceSettlePollInterval is the interval between the checks of
+	// WaitForDeviceToSettle.
+	DeviceSettlePollInterval = 500 * time.Millisecond
+	// DeviceSettleStableChecks is the number of consecutive checks that
+	// must report the same device size for the device to be settled.
+	DeviceSettleStableChecks = 3
+)
+
+// sizeTracker tracks the size of a device across checks.
+type sizeTracker struct {
+	size   int64
+	stable int
+}
+
+// observe records a size and returns true once the same size has been
+// observed DeviceSettleStableChecks times in a row.
+func (t *sizeTracker) observe(size int64) bool {
+	if t.stable == 0 || size != t.size {
+		t.size, t.stable = size, 1
+	} else {
+		t.stable++
+	}
+	return t.stable >= DeviceSettleStableChecks
+}
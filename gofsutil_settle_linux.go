@@ -0,0 +1,110 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// udevQueuePath exists while udev has events queued.
+const udevQueuePath = "/run/udev/queue"
+
+// udevSettle waits for the udev event queue to drain, if udevadm is
+// available.
+func (fs *FS) udevSettle(ctx context.Context, timeout time.Duration) {
+	if !fs.commandAvailable("udevadm") {
+		return
+	}
+	secs := int(timeout.Seconds())
+	if secs < 1 {
+		secs = 1
+	}
+	/* #nosec G204 */
+	out, err := fs.commandContext(ctx, "udevadm", "settle", fmt.Sprintf("--timeout=%d", secs)).CombinedOutput()
+	if err != nil {
+		log.WithError(err).WithField("output", strings.TrimSpace(string(out))).Debug("udevadm settle failed")
+	}
+}
+
+// udevQueueEmpty returns true if udev has no pending events.
+func udevQueueEmpty() bool {
+	_, err := os.Stat(udevQueuePath)
+	return errors.Is(err, os.ErrNotExist)
+}
+
+// deviceOpenable returns an error if the device cannot be opened.
+func deviceOpenable(device string) error {
+	fd, err := unix.Open(device, unix.O_RDONLY|unix.O_CLOEXEC|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return err
+	}
+	return unix.Close(fd)
+}
+
+// waitForDeviceToSettle waits until the udev queue is empty, the size of
+// the device is stable and the device can be opened.
+func (fs *FS) waitForDeviceToSettle(ctx context.Context, device string) error {
+	devicePath, err := fs.validateDevice(ctx, device)
+	if err != nil {
+		return err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultDeviceSettleTimeout)
+		defer cancel()
+	}
+	deadline, _ := ctx.Deadline()
+	fs.udevSettle(ctx, time.Until(deadline))
+
+	var tracker sizeTracker
+	ticker := time.NewTicker(DeviceSettlePollInterval)
+	defer ticker.Stop()
+	for {
+		var reason error
+		size, err := fs.blockDeviceSize(devicePath)
+		switch {
+		case err != nil:
+			reason = err
+			tracker = sizeTracker{}
+		case !tracker.observe(size):
+			reason = fmt.Errorf("size %d observed %d times", size, tracker.stable)
+		case !udevQueueEmpty():
+			reason = errors.New("udev events are pending")
+		default:
+			reason = deviceOpenable(devicePath)
+		}
+		if reason == nil {
+			log.WithFields(log.Fields{
+				"device": devicePath,
+				"size":   size,
+			}).Info("device settled")
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("device %s did not settle: %v: %w", devicePath, reason, ctx.Err())
+		}
+	}
+}
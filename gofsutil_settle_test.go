@@ -0,0 +1,68 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeTracker(t *testing.T) {
+	var tracker sizeTracker
+	assert.False(t, tracker.observe(100))
+	assert.False(t, tracker.observe(100))
+	// A size change restarts the count.
+	assert.False(t, tracker.observe(200))
+	for i := 1; i < DeviceSettleStableChecks; i++ {
+		assert.Equal(t, i == DeviceSettleStableChecks-1, tracker.observe(200))
+	}
+}
+
+func TestWaitForDeviceToSettle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Only block devices are accepted.
+	assert.Error(t, WaitForDeviceToSettle(ctx, t.TempDir()))
+
+	if _, err := os.Stat("/dev/loop0"); err != nil {
+		t.Skip("no loop device available")
+	}
+	sysRoot := t.TempDir()
+	sizeDir := filepath.Join(sysRoot, "class", "block", "loop0")
+	require.NoError(t, os.MkdirAll(sizeDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(sizeDir, "size"), []byte("2048\n"), 0o600))
+
+	fs := NewFS(FSOptions{SysRoot: sysRoot})
+	start := time.Now()
+	if err := fs.WaitForDeviceToSettle(ctx, "/dev/loop0"); err != nil {
+		t.Skipf("loop device did not settle: %v", err)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), (DeviceSettleStableChecks-1)*DeviceSettlePollInterval)
+
+	// A missing size never settles.
+	require.NoError(t, os.Remove(filepath.Join(sizeDir, "size")))
+	shortCtx, shortCancel := context.WithTimeout(ctx, 2*DeviceSettlePollInterval)
+	defer shortCancel()
+	err := fs.WaitForDeviceToSettle(shortCtx, "/dev/loop0")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
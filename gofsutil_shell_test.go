@@ -0,0 +1,111 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const shellTestLsblkOutput = `NAME="sdc" MAJ:MIN="8:32" RM="0" SIZE="8G" RO="0" TYPE="disk" MOUNTPOINT=""
+NAME="mpatha" MAJ:MIN="253:0" RM="0" SIZE="8G" RO="0" TYPE="mpath" MOUNTPOINT="/mnt/mpatha"
+NAME="sdd" MAJ:MIN="8:48" RM="0" SIZE="8G" RO="0" TYPE="disk" MOUNTPOINT=""
+NAME="mpatha" MAJ:MIN="253:0" RM="0" SIZE="8G" RO="0" TYPE="mpath" MOUNTPOINT="/mnt/mpatha"
+NAME="sde" MAJ:MIN="8:64" RM="0" SIZE="4G" RO="0" TYPE="disk" MOUNTPOINT="/mnt/sde"
+`
+
+func TestDisallowShell(t *testing.T) {
+	fs := NewFS(FSOptions{DisallowShell: true})
+	for _, shell := range []string{"bash", "/bin/sh"} {
+		err := fs.command(shell, "-c", "true").Run()
+		assert.True(t, errors.Is(err, ErrShellDisallowed), shell)
+		_, err = fs.commandContext(context.Background(), shell, "-c", "true").Output()
+		assert.True(t, errors.Is(err, ErrShellDisallowed), shell)
+	}
+	assert.NoError(t, fs.checkShell("lsblk", nil))
+
+	var shells []string
+	SetShellCommandHook(func(name string, args []string) {
+		shells = append(shells, name+" "+strings.Join(args, " "))
+	})
+	defer SetShellCommandHook(nil)
+	_ = NewFS(FSOptions{}).command("/bin/sh", "-c", "true")
+	_ = fs.command("lsblk", "-n")
+	assert.Equal(t, []string{"/bin/sh -c true"}, shells)
+	SetShellCommandHook(nil)
+	_ = fs.command("bash", "-c", "true")
+	assert.Len(t, shells, 1)
+}
+
+// TestNoShell checks that the operations that used to run shell pipelines
+// run their commands directly.
+func TestNoShell(t *testing.T) {
+	SetShellCommandHook(func(name string, args []string) {
+		t.Errorf("shell command: %s %v", name, args)
+	})
+	defer SetShellCommandHook(nil)
+
+	tmp := t.TempDir()
+	bin := filepath.Join(tmp, "bin")
+	require.NoError(t, os.Mkdir(bin, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "lsblk.out"), []byte(shellTestLsblkOutput), 0o600))
+	lsblk := "#!/bin/sh\n[ \"$1\" = -V ] && echo 'lsblk from util-linux 2.37.2' && exit 0\ncat " + filepath.Join(tmp, "lsblk.out") + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "lsblk"), []byte(lsblk), 0o700))                                   // #nosec G306
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "findmnt"), []byte("#!/bin/sh\necho \"$*\"\necho ext4\n"), 0o700)) // #nosec G306
+	sysRoot := filepath.Join(tmp, "sys")
+	writeSysfsAttrs(t, filepath.Join(sysRoot, "block", "sdc", "device"), map[string]string{"rescan": ""})
+	fs := NewFS(FSOptions{
+		SysRoot:       sysRoot,
+		ProcRoot:      filepath.Join(tmp, "proc"),
+		ExtraEnv:      []string{"PATH=" + bin + ":/usr/bin:/bin"},
+		DisallowShell: true,
+	})
+	ctx := context.Background()
+
+	info, err := fs.GetMountInfoFromDevice(ctx, "sde")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sde"}, info.DeviceNames)
+	assert.Equal(t, "/mnt/sde", info.MountPoint)
+
+	info, err = fs.GetMountInfoFromDevice(ctx, "mpatha")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sdc", "sdd"}, info.DeviceNames)
+	assert.Equal(t, "mpatha", info.MPathName)
+	assert.Equal(t, "/mnt/mpatha", info.MountPoint)
+
+	_, err = fs.GetMountInfoFromDevice(ctx, "sdx")
+	assert.Error(t, err)
+
+	name, err := fs.GetMpathNameFromDevice(ctx, "sdd")
+	require.NoError(t, err)
+	assert.Equal(t, "mpatha", name)
+
+	fsType, err := fs.FindFSType(ctx, "/mnt/sde")
+	require.NoError(t, err)
+	assert.Equal(t, "-n -o FSTYPE /mnt/sde\next4", fsType)
+
+	require.NoError(t, fs.DeviceRescan(ctx, "/sys/block/sdc"))
+	rescan, err := os.ReadFile(filepath.Join(sysRoot, "block", "sdc", "device", "rescan"))
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(rescan))
+}
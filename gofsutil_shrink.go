@@ -0,0 +1,43 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrShrinkBelowMinimum is returned by ShrinkFS when the data of the
+// filesystem does not fit in the requested size.
+var ErrShrinkBelowMinimum = errors.New("size is below the minimum size of the filesystem")
+
+// parseResize2fsMinimum returns the minimum size in blocks of an ext
+// filesystem from the output of resize2fs -P.
+func parseResize2fsMinimum(out string) (int64, error) {
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || strings.TrimSpace(key) != "Estimated minimum size of the filesystem" {
+			continue
+		}
+		blocks, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse resize2fs output line %q: %v", scanner.Text(), err)
+		}
+		return blocks, nil
+	}
+	return 0, errors.New("minimum size not found in resize2fs output")
+}
@@ -0,0 +1,107 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// shrinkFS shrinks the unmounted ext filesystem on device to newSizeBytes,
+// rounded down to whole filesystem blocks.
+func (fs *FS) shrinkFS(ctx context.Context, device string, newSizeBytes int64) error {
+	path := filepath.Clean(device)
+	if err := validatePath(path); err != nil {
+		return fmt.Errorf("Failed to validate path: %s error %v", device, err)
+	}
+	if newSizeBytes <= 0 {
+		return fmt.Errorf("invalid size %d to shrink %s to", newSizeBytes, device)
+	}
+	fsType, err := fs.getDiskFormat(ctx, path)
+	if err != nil {
+		return err
+	}
+	switch fsType {
+	case "ext2", "ext3", "ext4":
+	case "":
+		return fmt.Errorf("no filesystem found on %s", device)
+	default:
+		return fmt.Errorf("%s filesystem on %s cannot be shrunk: %w", fsType, device, errors.ErrUnsupported)
+	}
+	// The geometry is read without changing the device, so that a size
+	// that needs no shrinking does not check the filesystem.
+	g, err := fs.getFSGeometry(ctx, path)
+	if err != nil {
+		return err
+	}
+	blocks := newSizeBytes / g.BlockSize
+	switch {
+	case blocks == g.BlockCount:
+		return ErrNoResizeNeeded
+	case blocks > g.BlockCount:
+		return fmt.Errorf("%d bytes is larger than the %d bytes filesystem on %s, use ResizeFS to grow it",
+			newSizeBytes, g.Size(), device)
+	}
+	if err := fs.ensureDeviceUnused(ctx, path); err != nil {
+		return err
+	}
+
+	// resize2fs refuses to shrink a filesystem that was not freshly
+	// checked.
+	log.WithField("device", path).Info("checking ext filesystem before shrinking it")
+	/* #nosec G204 */
+	out, err := fs.commandContext(ctx, "e2fsck", "-f", "-p", path).CombinedOutput()
+	// Exit code 1 means that errors were corrected.
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+		return fmt.Errorf("e2fsck failed for (%s) error (%v): %s", device, err, strings.TrimSpace(string(out)))
+	}
+	/* #nosec G204 */
+	out, err = fs.commandContext(ctx, "resize2fs", "-P", path).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("resize2fs failed for (%s) error (%v): %s", device, err, strings.TrimSpace(string(out)))
+	}
+	minBlocks, err := parseResize2fsMinimum(string(out))
+	if err != nil {
+		return err
+	}
+	if blocks < minBlocks {
+		return fmt.Errorf("%s cannot be shrunk to %d bytes, it needs at least %d bytes: %w",
+			device, newSizeBytes, minBlocks*g.BlockSize, ErrShrinkBelowMinimum)
+	}
+
+	log.WithFields(log.Fields{"device": path, "blocks": blocks}).Info("shrinking ext filesystem")
+	/* #nosec G204 */
+	out, err = fs.commandContext(ctx, "resize2fs", path, strconv.FormatInt(blocks, 10)).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("resize2fs failed for (%s) error (%v): %s", device, err, strings.TrimSpace(string(out)))
+	}
+	if fs.DryRun {
+		return nil
+	}
+	g, err = fs.getFSGeometry(ctx, path)
+	if err != nil {
+		return err
+	}
+	if g.BlockCount != blocks {
+		return fmt.Errorf("filesystem on %s has %d blocks after shrinking it to %d blocks", device, g.BlockCount, blocks)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResize2fsMinimum(t *testing.T) {
+	blocks, err := parseResize2fsMinimum("resize2fs 1.47.0 (5-Feb-2023)\nEstimated minimum size of the filesystem: 13694\n")
+	require.NoError(t, err)
+	assert.Equal(t, int64(13694), blocks)
+
+	_, err = parseResize2fsMinimum("resize2fs 1.47.0 (5-Feb-2023)\n")
+	assert.Error(t, err)
+	_, err = parseResize2fsMinimum("Estimated minimum size of the filesystem: lots\n")
+	assert.Error(t, err)
+}
+
+func TestShrinkFSChecksOnlyBeforeShrinking(t *testing.T) {
+	ctx := context.Background()
+	bin := t.TempDir()
+	log := filepath.Join(t.TempDir(), "log")
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "lsblk"), []byte("#!/bin/sh\necho ext4\n"), 0o700)) // #nosec G306
+	tune2fs := "#!/bin/sh\necho 'Block count:              16384'\necho 'Block size:               4096'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "tune2fs"), []byte(tune2fs), 0o700)) // #nosec G306
+	writeRecorder(t, filepath.Join(bin, "e2fsck"), log)
+	fs := NewFS(FSOptions{ExtraEnv: []string{"PATH=" + bin}})
+
+	// Neither a size that needs no shrinking nor a larger one checks the
+	// filesystem.
+	assert.ErrorIs(t, fs.ShrinkFS(ctx, "/dev/sdz", 64<<20), ErrNoResizeNeeded)
+	assert.ErrorContains(t, fs.ShrinkFS(ctx, "/dev/sdz", 128<<20), "use ResizeFS")
+	_, err := os.Stat(log)
+	assert.True(t, os.IsNotExist(err), err)
+}
+
+func TestShrinkFS(t *testing.T) {
+	for _, name := range []string{"mkfs.ext4", "e2fsck", "resize2fs", "tune2fs"} {
+		if _, err := exec.LookPath(name); err != nil {
+			t.Skipf("%s not available", name)
+		}
+	}
+	ctx := context.Background()
+	bin := t.TempDir()
+	// lsblk cannot report the format of an image file.
+	lsblk := filepath.Join(bin, "lsblk")
+	writeLsblk := func(format string) {
+		require.NoError(t, os.WriteFile(lsblk, []byte("#!/bin/sh\necho "+format+"\n"), 0o700)) // #nosec G306
+	}
+	writeLsblk("ext4")
+	env := []string{"PATH=" + bin + ":" + os.Getenv("PATH")}
+	image := filepath.Join(t.TempDir(), "disk.img")
+	require.NoError(t, os.WriteFile(image, nil, 0o600))
+	require.NoError(t, os.Truncate(image, 64<<20))
+	require.NoError(t, exec.Command("mkfs.ext4", "-q", "-F", "-b", "4096", image).Run()) // #nosec G204
+	fs := NewFS(FSOptions{ExtraEnv: env})
+
+	assert.ErrorIs(t, fs.ShrinkFS(ctx, image, 64<<20), ErrNoResizeNeeded)
+	assert.Error(t, fs.ShrinkFS(ctx, image, 128<<20))
+	assert.Error(t, fs.ShrinkFS(ctx, image, 0))
+	err := fs.ShrinkFS(ctx, image, 1<<20)
+	assert.ErrorIs(t, err, ErrShrinkBelowMinimum)
+	assert.Equal(t, CodeInvalidArgument, ErrorCodeOf(err))
+
+	// Nothing is changed in dry-run mode.
+	dryRun := NewFS(FSOptions{ExtraEnv: env, DryRun: true})
+	require.NoError(t, dryRun.ShrinkFS(ctx, image, 32<<20))
+	g, err := fs.GetFSGeometry(ctx, image)
+	require.NoError(t, err)
+	assert.Equal(t, int64(64<<20), g.Size())
+
+	require.NoError(t, fs.ShrinkFS(ctx, image, 32<<20+100))
+	g, err = fs.GetFSGeometry(ctx, image)
+	require.NoError(t, err)
+	assert.Equal(t, int64(32<<20), g.Size())
+
+	writeLsblk("xfs")
+	err = fs.ShrinkFS(ctx, image, 16<<20)
+	assert.True(t, errors.Is(err, errors.ErrUnsupported), err)
+	assert.Equal(t, CodeNotSupported, ErrorCodeOf(err))
+}
@@ -0,0 +1,96 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// DiskFormatLUKS is the format GetDiskFormat reports for a LUKS
+	// encrypted device.
+	DiskFormatLUKS = "crypto_LUKS"
+	// DiskFormatLVM is the format GetDiskFormat reports for an LVM
+	// physical volume.
+	DiskFormatLVM = "LVM2_member"
+
+	// lvmLabelSectors is the number of sectors at the start of a device
+	// the LVM label can be written to.
+	lvmLabelSectors = 4
+	// sectorSize is the size of the sectors the LVM label is looked for
+	// in.
+	sectorSize = 512
+)
+
+var (
+	// luksMagic is the magic of a LUKS header, at the start of the device.
+	luksMagic = []byte("LUKS\xba\xbe")
+	// lvmLabelID is the ID at the start of the sector of an LVM label.
+	lvmLabelID = []byte("LABELONE")
+	// lvmLabelType is the type of an LVM2 label, at offset 24 of its
+	// sector.
+	lvmLabelType = []byte("LVM2 001")
+)
+
+// ErrForeignSignature is returned by FormatAndMount when the device holds
+// a LUKS or LVM signature, which is neither mounted nor formatted over.
+var ErrForeignSignature = errors.New("device holds a foreign signature")
+
+// ForeignSignatureError is the error returned by FormatAndMount for a
+// device that holds a LUKS or LVM signature. It matches
+// ErrForeignSignature.
+type ForeignSignatureError struct {
+	// Device is the device.
+	Device string
+	// Signature is the signature found, DiskFormatLUKS or DiskFormatLVM.
+	Signature string
+}
+
+func (e *ForeignSignatureError) Error() string {
+	return fmt.Sprintf("%s holds a %s signature, refusing to format or mount it", e.Device, e.Signature)
+}
+
+// Is returns true if target is ErrForeignSignature.
+func (e *ForeignSignatureError) Is(target error) bool {
+	return target == ErrForeignSignature
+}
+
+// isForeignSignature returns true if format, as reported by
+// GetDiskFormat, is a LUKS or LVM signature.
+func isForeignSignature(format string) bool {
+	return format == DiskFormatLUKS || format == DiskFormatLVM
+}
+
+// probeSignature returns DiskFormatLUKS or DiskFormatLVM if r starts with
+// a LUKS header or an LVM2 label, and an empty string otherwise.
+func probeSignature(r io.ReaderAt) (string, error) {
+	buf := make([]byte, lvmLabelSectors*sectorSize)
+	n, err := r.ReadAt(buf, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	buf = buf[:n]
+	if bytes.HasPrefix(buf, luksMagic) {
+		return DiskFormatLUKS, nil
+	}
+	for off := 0; off+sectorSize <= len(buf); off += sectorSize {
+		sector := buf[off : off+sectorSize]
+		if bytes.HasPrefix(sector, lvmLabelID) && bytes.HasPrefix(sector[24:], lvmLabelType) {
+			return DiskFormatLVM, nil
+		}
+	}
+	return "", nil
+}
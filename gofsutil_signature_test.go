@@ -0,0 +1,63 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lvmLabel returns a device start with an LVM2 label in the given sector.
+func lvmLabel(sector int) []byte {
+	buf := make([]byte, 8192)
+	copy(buf[sector*sectorSize:], lvmLabelID)
+	copy(buf[sector*sectorSize+24:], lvmLabelType)
+	return buf
+}
+
+func TestProbeSignature(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"empty", nil, ""},
+		{"zeroes", make([]byte, 8192), ""},
+		{"luks", append([]byte("LUKS\xba\xbe\x00\x02"), make([]byte, 4096)...), DiskFormatLUKS},
+		{"lvm first sector", lvmLabel(0), DiskFormatLVM},
+		{"lvm second sector", lvmLabel(1), DiskFormatLVM},
+		{"lvm fifth sector", lvmLabel(4), ""},
+		{"lvm label without type", append([]byte("LABELONE"), make([]byte, 1024)...), ""},
+		{"short", []byte("LUKS"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := probeSignature(bytes.NewReader(tt.data))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestForeignSignatureError(t *testing.T) {
+	var err error = &ForeignSignatureError{Device: "/dev/sdb", Signature: DiskFormatLVM}
+	assert.ErrorIs(t, err, ErrForeignSignature)
+	assert.Equal(t, "/dev/sdb holds a LVM2_member signature, refusing to format or mount it", err.Error())
+	assert.False(t, errors.Is(errors.New("other"), ErrForeignSignature))
+	assert.True(t, isForeignSignature(DiskFormatLUKS))
+	assert.False(t, isForeignSignature("ext4"))
+}
@@ -0,0 +1,64 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SMBCredentials are the credentials used to mount an SMB share.
+type SMBCredentials struct {
+	// Username is the user the share is mounted as. When empty, no
+	// credentials are passed and the mount options must select another
+	// security mode, e.g. guest or sec=krb5.
+	Username string
+	// Password is the password of the user.
+	Password string
+	// Domain is the domain or workgroup of the user.
+	Domain string
+}
+
+// smbCredentialsFile returns the content of a mount.cifs credentials file.
+func smbCredentialsFile(creds SMBCredentials) (string, error) {
+	for _, v := range []string{creds.Username, creds.Password, creds.Domain} {
+		if strings.ContainsAny(v, "\n\r") {
+			return "", errors.New("SMB credentials must not contain line breaks")
+		}
+	}
+	content := fmt.Sprintf("username=%s\npassword=%s\n", creds.Username, creds.Password)
+	if creds.Domain != "" {
+		content += fmt.Sprintf("domain=%s\n", creds.Domain)
+	}
+	return content, nil
+}
+
+// secretMountOptions are the mount options whose values are secrets.
+var secretMountOptions = []string{"password", "password2", "pass"}
+
+// scrubMountArgs returns a copy of the mount arguments with the values of
+// secret options replaced, so that they can be logged.
+func scrubMountArgs(args []string) []string {
+	scrubbed := make([]string, len(args))
+	for i, arg := range args {
+		opts := strings.Split(arg, ",")
+		for j, opt := range opts {
+			if key, _, ok := strings.Cut(opt, "="); ok && stringInSlice(key, secretMountOptions) {
+				opts[j] = key + "=****"
+			}
+		}
+		scrubbed[i] = strings.Join(opts, ",")
+	}
+	return scrubbed
+}
@@ -0,0 +1,65 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// smbMount mounts the SMB share source, e.g. //server/share, on target
+// with -t cifs. The credentials are passed to mount.cifs in a temporary
+// file readable only by the owner, which is removed once mounted. With
+// Chroot or HostMountNamespace the file is created in the /tmp of the
+// host root, where mount.cifs runs.
+func (fs *FS) smbMount(ctx context.Context, source, target string, creds SMBCredentials, opts ...string) error {
+	source = strings.ReplaceAll(source, `\`, "/")
+	if !strings.HasPrefix(source, "//") {
+		return fmt.Errorf("SMB share %s is invalid, want //server/share", source)
+	}
+	opts = append([]string{}, opts...)
+	if creds.Username != "" {
+		content, err := smbCredentialsFile(creds)
+		if err != nil {
+			return err
+		}
+		// CreateTemp creates the file with mode 0600.
+		file, hostName, err := fs.createHostTemp("gofsutil-smb-")
+		if err != nil {
+			return fmt.Errorf("failed to create SMB credentials file: %v", err)
+		}
+		defer os.Remove(file.Name()) // #nosec G104
+		_, err = file.WriteString(content)
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write SMB credentials file: %v", err)
+		}
+		opts = append(opts, "credentials="+hostName)
+	}
+	log.WithFields(log.Fields{
+		"source": source,
+		"target": target,
+		"user":   creds.Username,
+		"domain": creds.Domain,
+	}).Info("mounting SMB share")
+	return fs.doMount(ctx, fs.mountBinary(), source, target, "cifs", opts...)
+}
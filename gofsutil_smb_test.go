@@ -0,0 +1,96 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrubMountArgs(t *testing.T) {
+	args := []string{"-t", "cifs", "-o", "vers=3.0,password=s3cret,pass=x,username=bob", "//nas/share", "/mnt"}
+	assert.Equal(t,
+		[]string{"-t", "cifs", "-o", "vers=3.0,password=****,pass=****,username=bob", "//nas/share", "/mnt"},
+		scrubMountArgs(args))
+	assert.Equal(t, "vers=3.0,password=s3cret,pass=x,username=bob", args[3])
+}
+
+func TestSMBMount(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "log")
+	mount := filepath.Join(dir, "mount")
+	// The fake mount records its arguments and the credentials file with
+	// its permissions, then fails if asked to.
+	script := "#!/bin/sh\n" +
+		"echo \"$*\" >> " + log + "\n" +
+		"creds=$(echo \"$*\" | sed -n 's/.*credentials=\\([^ ,]*\\).*/\\1/p')\n" +
+		"[ -n \"$creds\" ] && stat -c %a \"$creds\" >> " + log + " && cat \"$creds\" >> " + log + "\n" +
+		"case \"$*\" in *fail*) echo 'mount error(13): Permission denied' >&2; exit 32;; esac\n"
+	require.NoError(t, os.WriteFile(mount, []byte(script), 0o700)) // #nosec G306
+
+	fs := NewFS(FSOptions{MountBinary: mount})
+	ctx := context.Background()
+	creds := SMBCredentials{Username: "bob", Password: "s3cret", Domain: "CORP"}
+
+	require.NoError(t, fs.SMBMount(ctx, `\\nas\share`, "/mnt/smb", creds, "vers=3.0"))
+	out, err := os.ReadFile(log)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	require.Len(t, lines, 5)
+	assert.Regexp(t, regexp.MustCompile(`^-t cifs -o vers=3.0,credentials=\S+ //nas/share /mnt/smb$`), lines[0])
+	assert.Equal(t, []string{"600", "username=bob", "password=s3cret", "domain=CORP"}, lines[1:])
+	creds1 := regexp.MustCompile(`credentials=(\S+)`).FindStringSubmatch(lines[0])[1]
+	_, err = os.Stat(creds1)
+	assert.True(t, os.IsNotExist(err), "credentials file not removed")
+
+	// Secrets passed as options do not appear in errors.
+	err = fs.SMBMount(ctx, "//nas/share", "/mnt/smb", SMBCredentials{}, "fail", "password=s3cret")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "s3cret")
+	assert.Contains(t, err.Error(), "password=****")
+
+	assert.Error(t, fs.SMBMount(ctx, "nas/share", "/mnt/smb", creds))
+	assert.Error(t, fs.SMBMount(ctx, "//nas/share", "/mnt/smb", SMBCredentials{Username: "bob", Password: "a\nb"}))
+}
+
+func TestSMBMountOnHost(t *testing.T) {
+	// The credentials file is created in the /tmp of the host root, not
+	// in the temporary directory of the process.
+	t.Setenv("TMPDIR", t.TempDir())
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "tmp"), 0o750))
+	fs := NewFS(FSOptions{Chroot: root, DryRun: true})
+	ctx := context.Background()
+
+	require.NoError(t, fs.SMBMount(ctx, "//nas/share", "/mnt/smb", SMBCredentials{Username: "bob"}))
+	actions := fs.GetDryRunActions()
+	require.Len(t, actions, 1)
+	assert.Equal(t, chrootCmd, actions[0].Command)
+	assert.Regexp(t, regexp.MustCompile(`credentials=/tmp/gofsutil-smb-\d+ `), strings.Join(actions[0].Args, " "))
+	entries, err := os.ReadDir(filepath.Join(root, "tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, entries, "credentials file not removed")
+
+	require.NoError(t, os.Remove(filepath.Join(root, "tmp")))
+	assert.Error(t, fs.SMBMount(ctx, "//nas/share", "/mnt/smb", SMBCredentials{Username: "bob"}))
+}
@@ -0,0 +1,179 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidMountSpec is returned when a MountSpec is not valid.
+var ErrInvalidMountSpec = errors.New("invalid mount spec")
+
+// MountFlag is a filesystem independent mount option. Please see mount(8).
+type MountFlag string
+
+const (
+	// MountFlagNoExec does not allow the execution of binaries.
+	MountFlagNoExec MountFlag = "noexec"
+	// MountFlagNoSuid does not honor the set-user-ID and set-group-ID
+	// bits.
+	MountFlagNoSuid MountFlag = "nosuid"
+	// MountFlagNoDev does not interpret character or block special
+	// devices.
+	MountFlagNoDev MountFlag = "nodev"
+	// MountFlagNoAtime does not update the access times.
+	MountFlagNoAtime MountFlag = "noatime"
+	// MountFlagNoDirAtime does not update the access times of
+	// directories.
+	MountFlagNoDirAtime MountFlag = "nodiratime"
+	// MountFlagRelAtime updates the access times relative to the
+	// modification times.
+	MountFlagRelAtime MountFlag = "relatime"
+	// MountFlagStrictAtime always updates the access times.
+	MountFlagStrictAtime MountFlag = "strictatime"
+	// MountFlagLazyTime keeps time updates in memory.
+	MountFlagLazyTime MountFlag = "lazytime"
+	// MountFlagSync does all I/O synchronously.
+	MountFlagSync MountFlag = "sync"
+	// MountFlagDirSync does directory updates synchronously.
+	MountFlagDirSync MountFlag = "dirsync"
+	// MountFlagRemount changes the options of a mounted filesystem.
+	MountFlagRemount MountFlag = "remount"
+)
+
+// mountFlags are the known mount flags.
+var mountFlags = []MountFlag{
+	MountFlagNoExec, MountFlagNoSuid, MountFlagNoDev, MountFlagNoAtime,
+	MountFlagNoDirAtime, MountFlagRelAtime, MountFlagStrictAtime,
+	MountFlagLazyTime, MountFlagSync, MountFlagDirSync, MountFlagRemount,
+}
+
+// MountSpec describes a mount for MountWithSpec, as a typed alternative
+// to the mount options of Mount.
+type MountSpec struct {
+	// Source is the device, share or directory to mount.
+	Source string
+	// Target is the mount point.
+	Target string
+	// FsType is the filesystem type, empty to let mount detect it. It
+	// must be empty for bind mounts.
+	FsType string
+	// Flags are the filesystem independent mount options.
+	Flags []MountFlag
+	// Data are the filesystem specific mount options, one per entry,
+	// e.g. vers=4.1 for nfs.
+	Data []string
+	// SensitiveData are filesystem specific mount options that are
+	// redacted from the logs and errors like with MountSensitive, e.g.
+	// password=secret for cifs.
+	SensitiveData []string
+	// ReadOnly mounts the filesystem read-only.
+	ReadOnly bool
+	// Bind bind mounts Source onto Target.
+	Bind bool
+}
+
+// ParseMountSpec returns the spec of a mount done with Mount, to migrate
+// to MountWithSpec: ro and bind set ReadOnly and Bind, the known flags
+// are moved to Flags and the other options to Data.
+func ParseMountSpec(source, target, fsType string, opts ...string) MountSpec {
+	spec := MountSpec{Source: source, Target: target, FsType: fsType}
+	for _, opt := range opts {
+		switch {
+		case opt == "ro":
+			spec.ReadOnly = true
+		case opt == "bind":
+			spec.Bind = true
+		case opt == "rw" || opt == "":
+			// rw is the default.
+		case isMountFlag(MountFlag(opt)):
+			spec.Flags = append(spec.Flags, MountFlag(opt))
+		default:
+			spec.Data = append(spec.Data, opt)
+		}
+	}
+	return spec
+}
+
+// isMountFlag returns true if flag is a known mount flag.
+func isMountFlag(flag MountFlag) bool {
+	for _, f := range mountFlags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate returns an error wrapping ErrInvalidMountSpec if the spec is
+// not valid.
+func (s MountSpec) Validate() error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidMountSpec, fmt.Sprintf(format, args...))
+	}
+	if s.Source == "" {
+		return invalid("no source")
+	}
+	if s.Target == "" {
+		return invalid("no target")
+	}
+	if s.Bind && s.FsType != "" {
+		return invalid("bind mount with fs type %s", s.FsType)
+	}
+	for _, flag := range s.Flags {
+		if !isMountFlag(flag) {
+			return invalid("unknown flag %q", flag)
+		}
+	}
+	for _, opt := range s.Data {
+		if opt == "" || strings.Contains(opt, ",") {
+			return invalid("data option %q is not a single option", opt)
+		}
+		if opt == "ro" || opt == "rw" || opt == "bind" {
+			return invalid("data option %s has to be set with ReadOnly or Bind", opt)
+		}
+	}
+	for i, opt := range s.SensitiveData {
+		if opt == "" || strings.Contains(opt, ",") {
+			return invalid("sensitive data option %d is not a single option", i)
+		}
+	}
+	return nil
+}
+
+// Options returns the mount options of the spec, without its sensitive
+// data, as passed to Mount.
+func (s MountSpec) Options() []string {
+	opts := make([]string, 0, len(s.Flags)+len(s.Data)+2)
+	if s.Bind {
+		opts = append(opts, "bind")
+	}
+	if s.ReadOnly {
+		opts = append(opts, "ro")
+	}
+	for _, flag := range s.Flags {
+		opts = append(opts, string(flag))
+	}
+	return append(opts, s.Data...)
+}
+
+// mountWithSpec validates the spec and mounts it.
+func (fs *FS) mountWithSpec(ctx context.Context, spec MountSpec) error {
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	return fs.mountSensitive(ctx, spec.Source, spec.Target, spec.FsType, spec.Options(), spec.SensitiveData)
+}
@@ -0,0 +1,73 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMountSpecOptions(t *testing.T) {
+	spec := MountSpec{
+		Source:        "10.0.0.1:/export",
+		Target:        "/mnt/export",
+		FsType:        "nfs",
+		Flags:         []MountFlag{MountFlagNoExec, MountFlagNoAtime},
+		Data:          []string{"vers=4.1", "nconnect=4"},
+		SensitiveData: []string{"password=s3cret"},
+		ReadOnly:      true,
+	}
+	assert.NoError(t, spec.Validate())
+	assert.Equal(t, []string{"ro", "noexec", "noatime", "vers=4.1", "nconnect=4"}, spec.Options())
+
+	bind := MountSpec{Source: "/src", Target: "/dst", Bind: true}
+	assert.NoError(t, bind.Validate())
+	assert.Equal(t, []string{"bind"}, bind.Options())
+}
+
+func TestMountSpecValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		spec MountSpec
+	}{
+		{"no source", MountSpec{Target: "/dst"}},
+		{"no target", MountSpec{Source: "/src"}},
+		{"bind with fs type", MountSpec{Source: "/src", Target: "/dst", FsType: "ext4", Bind: true}},
+		{"unknown flag", MountSpec{Source: "/src", Target: "/dst", Flags: []MountFlag{"nosuch"}}},
+		{"joined data", MountSpec{Source: "/src", Target: "/dst", Data: []string{"vers=4.1,hard"}}},
+		{"empty data", MountSpec{Source: "/src", Target: "/dst", Data: []string{""}}},
+		{"ro as data", MountSpec{Source: "/src", Target: "/dst", Data: []string{"ro"}}},
+		{"joined sensitive data", MountSpec{Source: "/src", Target: "/dst", SensitiveData: []string{"user=u,password=p"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			assert.ErrorIs(t, err, ErrInvalidMountSpec)
+			assert.NotContains(t, err.Error(), "password=p")
+		})
+	}
+}
+
+func TestParseMountSpec(t *testing.T) {
+	spec := ParseMountSpec("/dev/sdb", "/mnt/vol", "xfs", "ro", "nosuid", "rw", "nouuid", "bind")
+	assert.Equal(t, MountSpec{
+		Source:   "/dev/sdb",
+		Target:   "/mnt/vol",
+		FsType:   "xfs",
+		Flags:    []MountFlag{MountFlagNoSuid},
+		Data:     []string{"nouuid"},
+		ReadOnly: true,
+		Bind:     true,
+	}, spec)
+}
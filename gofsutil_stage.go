@@ -0,0 +1,409 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultStageDeviceTimeout is how long StageBlockVolume waits for the
+	// device of the volume when the context has no deadline.
+	DefaultStageDeviceTimeout = 60 * time.Second
+	// StageDevicePollInterval is the interval between the lookups of
+	// StageBlockVolume for the device of the volume.
+	StageDevicePollInterval = time.Second
+)
+
+// The steps of StageBlockVolume and UnstageBlockVolume.
+const (
+	StageStepWaitDevice  = "wait-device"
+	StageStepSettle      = "settle"
+	StageStepCheckUnused = "check-unused"
+	StageStepMount       = "mount"
+	StageStepVerify      = "verify"
+	StageStepUnmount     = "unmount"
+	StageStepCleanup     = "cleanup"
+	StageStepRescan      = "rescan"
+)
+
+// StageRequest describes a volume to be staged by StageBlockVolume.
+type StageRequest struct {
+	// WWN is the WWN of the volume, used to find its device.
+	WWN string
+	// DevicePath is the device of the volume. If set the device is not
+	// looked up by WWN.
+	DevicePath string
+	// Target is the staging path: a directory for a filesystem volume or
+	// a file for a raw block volume.
+	Target string
+	// FsType is the filesystem type. It is required unless Block is set.
+	FsType string
+	// MountOptions are the mount options.
+	MountOptions []string
+	// Block stages a raw block volume: the device is bind mounted onto
+	// the Target file instead of being formatted and mounted.
+	Block bool
+	// RequireMultipath waits for the volume to be multipathed, either by
+	// a device mapper map or by native NVMe multipathing. It requires
+	// WWN.
+	RequireMultipath bool
+}
+
+// StageStep is a step of StageBlockVolume.
+type StageStep struct {
+	// Name is the name of the step, e.g. StageStepMount.
+	Name string
+	// Duration is how long the step took.
+	Duration time.Duration
+	// Err is the error of the step, nil if it succeeded.
+	Err error
+}
+
+// StageResult describes the outcome of StageBlockVolume.
+type StageResult struct {
+	// Device is the device that was staged, e.g. /dev/dm-3.
+	Device string
+	// SymlinkPath is the /dev/disk/by-id link the device was found
+	// with, if it was looked up by WWN.
+	SymlinkPath string
+	// MultipathKind is the kind of multipathing of the volume, if it was
+	// looked up by WWN.
+	MultipathKind MultipathKind
+	// MpathDevice is the device mapper multipath device of the volume,
+	// if any.
+	MpathDevice *MpathDevice
+	// AlreadyStaged is set if the volume was already staged on Target,
+	// in which case nothing was done.
+	AlreadyStaged bool
+	// Mount is the mount found on Target once the volume is staged.
+	Mount *Info
+	// Steps are the steps that were run, in order.
+	Steps []StageStep
+}
+
+// UnstageRequest describes a volume to be unstaged by
+// UnstageBlockVolume.
+type UnstageRequest struct {
+	// WWN is the WWN of the volume whose devices are removed. If empty
+	// the targets are only unmounted.
+	WWN string
+	// Targets are the staging paths, and any other paths the volume is
+	// still mounted on, to unmount.
+	Targets []string
+	// Block unstages a raw block volume: the targets are files, which are
+	// removed once unmounted.
+	Block bool
+	// Rescan rescans the SCSI hosts after the devices were removed, e.g.
+	// to let the host notice that the LUN was unmapped.
+	Rescan bool
+	// RescanTargets are the FC port WWNs or iSCSI IQNs of the targets
+	// whose hosts are rescanned, all hosts if empty.
+	RescanTargets []string
+	// RescanLUN is the LUN to rescan, all LUNs if empty.
+	RescanLUN string
+}
+
+// UnstageResult describes the outcome of UnstageBlockVolume.
+type UnstageResult struct {
+	// Unmounted are the targets that were unmounted. Targets that were
+	// not mounted are skipped.
+	Unmounted []string
+	// Cleanup is the report of the removal of the devices of the volume.
+	Cleanup *DeviceCleanupReport
+	// Rescanned is set if the SCSI hosts were rescanned.
+	Rescanned bool
+	// Steps are the steps that were run, in order.
+	Steps []StageStep
+}
+
+// runStep runs fn as the step name and appends it to steps.
+func runStep(steps *[]StageStep, name string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	*steps = append(*steps, StageStep{Name: name, Duration: time.Since(start), Err: err})
+	return err
+}
+
+// step runs fn as the step name of the result.
+func (r *StageResult) step(name string, fn func() error) error {
+	return runStep(&r.Steps, name, fn)
+}
+
+// step runs fn as the step name of the result.
+func (r *UnstageResult) step(name string, fn func() error) error {
+	return runStep(&r.Steps, name, fn)
+}
+
+// validate returns an error if the request is not valid.
+func (req StageRequest) validate() error {
+	switch {
+	case req.Target == "":
+		return errors.New("stage request has no target")
+	case req.WWN == "" && req.DevicePath == "":
+		return errors.New("stage request has neither a WWN nor a device path")
+	case req.RequireMultipath && req.WWN == "":
+		return errors.New("stage request requires multipath but has no WWN")
+	case !req.Block && req.FsType == "":
+		return errors.New("stage request of a filesystem volume has no fs type")
+	}
+	return nil
+}
+
+// stageBlockVolume stages the volume of the request with the operations
+// of fsi. Once the device is found, lockPaths, if not nil, locks the
+// target and the device until the volume is staged, so that checking the
+// target and mounting it cannot race with another stage of the target.
+func stageBlockVolume(
+	ctx context.Context,
+	fsi FSinterface,
+	req StageRequest,
+	lockPaths func(context.Context, ...string) (func(), error),
+) (*StageResult, error) {
+	result := &StageResult{Device: req.DevicePath}
+	if err := req.validate(); err != nil {
+		return result, err
+	}
+	f := log.Fields{
+		"wwn":    req.WWN,
+		"target": req.Target,
+		"block":  req.Block,
+	}
+
+	if req.DevicePath == "" || req.RequireMultipath {
+		if err := result.step(StageStepWaitDevice, func() error {
+			return waitForStageDevice(ctx, fsi, req, result)
+		}); err != nil {
+			return result, err
+		}
+	}
+	f["device"] = result.Device
+
+	if err := result.step(StageStepSettle, func() error {
+		return fsi.WaitForDeviceToSettle(ctx, result.Device)
+	}); err != nil {
+		return result, err
+	}
+
+	if lockPaths != nil {
+		unlock, err := lockPaths(ctx, req.Target, deviceKey(result.Device))
+		if err != nil {
+			return result, err
+		}
+		defer unlock()
+	}
+
+	mnt, err := stageTargetMount(ctx, fsi, req.Target)
+	if err != nil {
+		return result, err
+	}
+	if mnt != nil {
+		if !req.Block && !sameDevice(mnt.Device, result.Device) {
+			return result, fmt.Errorf("stage target %s is already mounted from %s, not %s",
+				req.Target, mnt.Device, result.Device)
+		}
+		log.WithFields(f).Info("volume is already staged")
+		result.AlreadyStaged = true
+		result.Mount = mnt
+		return result, nil
+	}
+
+	if req.Block {
+		err = result.step(StageStepMount, func() error {
+			return fsi.bindMountBlockDevice(ctx, result.Device, req.Target, req.MountOptions...)
+		})
+	} else {
+		err = result.step(StageStepCheckUnused, func() error {
+			return fsi.ensureDeviceUnused(ctx, result.Device)
+		})
+		if err == nil {
+			err = result.step(StageStepMount, func() (err error) {
+				defer wrapOpError(&err, "format and mount", req.Target, CodeMountFailed)
+				return fsi.formatAndMount(ctx, result.Device, req.Target, req.FsType, req.MountOptions...)
+			})
+		}
+	}
+	if err != nil {
+		return result, err
+	}
+
+	err = result.step(StageStepVerify, func() error {
+		mnt, err := stageTargetMount(ctx, fsi, req.Target)
+		if err != nil {
+			return err
+		}
+		if mnt == nil {
+			return fmt.Errorf("no mount found on %s after staging", req.Target)
+		}
+		result.Mount = mnt
+		return nil
+	})
+	if err == nil {
+		log.WithFields(f).Info("volume staged")
+	}
+	return result, err
+}
+
+// waitForStageDevice waits until the device of the volume, and with
+// RequireMultipath its multipathing, are present, and fills in the
+// device of result. It waits DefaultStageDeviceTimeout if ctx has no
+// deadline.
+func waitForStageDevice(ctx context.Context, fsi FSinterface, req StageRequest, result *StageResult) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultStageDeviceTimeout)
+		defer cancel()
+	}
+	ticker := time.NewTicker(StageDevicePollInterval)
+	defer ticker.Stop()
+	for {
+		err := lookupStageDevice(ctx, fsi, req, result)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("device of volume %s not ready: %v: %w", req.WWN, err, ctx.Err())
+		}
+	}
+}
+
+// lookupStageDevice looks the device of the volume up by WWN once.
+func lookupStageDevice(ctx context.Context, fsi FSinterface, req StageRequest, result *StageResult) error {
+	symlink, device, err := fsi.WWNToDevicePath(ctx, req.WWN)
+	if err != nil {
+		return err
+	}
+	if device == "" {
+		return fmt.Errorf("no device found for WWN %s", req.WWN)
+	}
+	kind, err := fsi.GetMultipathKind(ctx, req.WWN)
+	if err != nil {
+		return err
+	}
+	if req.RequireMultipath && kind == MultipathNone {
+		return fmt.Errorf("volume %s is not multipathed", req.WWN)
+	}
+	result.SymlinkPath, result.MultipathKind = symlink, kind
+	if req.DevicePath == "" {
+		result.Device = device
+	}
+	if kind != MultipathDM {
+		return nil
+	}
+	mpath, err := fsi.GetMpathDeviceForWWN(ctx, req.WWN)
+	if err != nil {
+		return err
+	}
+	result.MpathDevice = mpath
+	if mpath != nil && req.DevicePath == "" {
+		// The path device may have been found before the multipath
+		// device link was created.
+		result.Device = mpath.DMNode
+	}
+	return nil
+}
+
+// stageTargetMount returns the mount on target, nil if there is none.
+func stageTargetMount(ctx context.Context, fsi FSinterface, target string) (*Info, error) {
+	mounts, err := fsi.GetMounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target = filepath.Clean(target)
+	for i := len(mounts) - 1; i >= 0; i-- {
+		if filepath.Clean(mounts[i].Path) == target {
+			return &mounts[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// sameDevice returns true if the device paths a and b are the same
+// device, following symlinks.
+func sameDevice(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ra, errA := filepath.EvalSymlinks(a)
+	rb, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && ra == rb
+}
+
+// unstageBlockVolume unstages the volume of the request with the
+// operations of fsi. Every step tolerates what an earlier, partial
+// unstage already did.
+func unstageBlockVolume(ctx context.Context, fsi FSinterface, req UnstageRequest) (*UnstageResult, error) {
+	result := &UnstageResult{}
+	if len(req.Targets) == 0 && req.WWN == "" {
+		return result, errors.New("unstage request has neither targets nor a WWN")
+	}
+	f := log.Fields{
+		"wwn":     req.WWN,
+		"targets": req.Targets,
+		"block":   req.Block,
+	}
+
+	for _, target := range req.Targets {
+		err := result.step(StageStepUnmount, func() error {
+			mnt, err := stageTargetMount(ctx, fsi, target)
+			if err != nil {
+				return err
+			}
+			if req.Block {
+				// The block file is removed even if it is not mounted.
+				err = fsi.UnmountBlockDevice(ctx, target)
+			} else if mnt != nil {
+				err = fsi.Unmount(ctx, target)
+			}
+			if err == nil && mnt != nil {
+				result.Unmounted = append(result.Unmounted, target)
+			}
+			return err
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+	if req.WWN == "" {
+		return result, nil
+	}
+
+	if err := result.step(StageStepCleanup, func() error {
+		// The cleanup waits for the devices to disappear, and does
+		// nothing if they are already gone.
+		report, err := fsi.CleanupDeviceForWWN(ctx, req.WWN)
+		result.Cleanup = report
+		return err
+	}); err != nil {
+		return result, err
+	}
+
+	if req.Rescan {
+		if err := result.step(StageStepRescan, func() error {
+			return fsi.RescanSCSIHost(ctx, req.RescanTargets, req.RescanLUN)
+		}); err != nil {
+			return result, err
+		}
+		result.Rescanned = true
+	}
+	log.WithFields(f).Info("volume unstaged")
+	return result, nil
+}
@@ -0,0 +1,155 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// DefaultMaxSymlinkDepth is the number of symlinks EvalSymlinks follows
+// before it gives up, the MAXSYMLINKS limit of Linux.
+const DefaultMaxSymlinkDepth = 40
+
+var (
+	// ErrTooManySymlinks is returned when more symlinks than the maximum
+	// depth have to be followed to evaluate a path, e.g. because of a
+	// symlink loop.
+	ErrTooManySymlinks = errors.New("too many levels of symbolic links")
+
+	// ErrOutsideRoot is returned by EvalSymlinksWithin when a path
+	// resolves outside of its root.
+	ErrOutsideRoot = errors.New("path resolves outside of the root")
+)
+
+// EvalSymlinksWithDepth is EvalSymlinks following at most maxDepth
+// symlinks, DefaultMaxSymlinkDepth if maxDepth is not positive.
+func EvalSymlinksWithDepth(ctx context.Context, symPath *string, maxDepth int) error {
+	realPath, err := walkSymlinks(ctx, "", *symPath, maxDepth)
+	if err != nil {
+		return err
+	}
+	*symPath = realPath
+	return nil
+}
+
+// EvalSymlinksWithin evaluates the symlinks of path as if root were the
+// root directory, e.g. /noderoot: path and absolute symlinks are resolved
+// relative to root. A path that already starts with root is accepted as
+// well. An error wrapping ErrOutsideRoot is returned if the path resolves
+// outside of root. The returned path includes root.
+func EvalSymlinksWithin(ctx context.Context, root, path string) (string, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	if rel, err := filepath.Rel(root, filepath.Clean(path)); err == nil && filepath.IsAbs(path) && filepath.IsLocal(rel) {
+		path = rel
+	}
+	return walkSymlinks(ctx, root, string(filepath.Separator)+path, DefaultMaxSymlinkDepth)
+}
+
+// walkSymlinks returns path with its symlinks evaluated, following at
+// most maxDepth symlinks and checking ctx before each step. If root is
+// set, path is an absolute path within root, the evaluation may not leave
+// root and the result is prefixed with root.
+func walkSymlinks(ctx context.Context, root, path string, maxDepth int) (string, error) {
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxSymlinkDepth
+	}
+	const sep = string(filepath.Separator)
+	realPath := func(p string) string {
+		if root == "" {
+			return p
+		}
+		return filepath.Join(root, p)
+	}
+
+	vol := filepath.VolumeName(path)
+	pending := path[len(vol):]
+	dest := vol
+	if strings.HasPrefix(pending, sep) {
+		dest += sep
+	}
+	links := 0
+	for pending != "" {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		var elem string
+		elem, pending, _ = strings.Cut(strings.TrimLeft(pending, sep), sep)
+		switch {
+		case elem == "" || elem == ".":
+			continue
+		case elem == "..":
+			switch {
+			case dest == vol+sep:
+				// The parent of the root directory is itself.
+				if root != "" {
+					return "", fmt.Errorf("%s: %w", path, ErrOutsideRoot)
+				}
+			case dest == vol || filepath.Base(dest) == "..":
+				// A relative path above the current directory.
+				dest = filepath.Join(dest, elem)
+			default:
+				dest = filepath.Dir(dest)
+				if dest == "." {
+					dest = vol
+				}
+			}
+			continue
+		}
+
+		next := elem
+		if dest != vol {
+			next = filepath.Join(dest, elem)
+		} else if vol != "" {
+			next = vol + elem
+		}
+		fi, err := os.Lstat(realPath(next))
+		if err != nil {
+			return "", err
+		}
+		if fi.Mode()&os.ModeSymlink == 0 {
+			if !fi.IsDir() && strings.Trim(pending, sep) != "" {
+				return "", &os.PathError{Op: "lstat", Path: realPath(next) + sep + pending, Err: syscall.ENOTDIR}
+			}
+			dest = next
+			continue
+		}
+
+		links++
+		if links > maxDepth {
+			return "", &os.PathError{Op: "evalsymlinks", Path: path, Err: ErrTooManySymlinks}
+		}
+		target, err := os.Readlink(realPath(next))
+		if err != nil {
+			return "", err
+		}
+		if tvol := filepath.VolumeName(target); tvol != "" || filepath.IsAbs(target) {
+			vol = tvol
+			dest = vol + sep
+			target = target[len(tvol):]
+		}
+		pending = target + sep + pending
+	}
+	if dest == vol {
+		dest += "."
+	}
+	return realPath(filepath.Clean(dest)), nil
+}
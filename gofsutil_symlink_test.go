@@ -0,0 +1,125 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSymlinkTree creates a tree resembling /dev/disk/by-id under a
+// temporary directory, which is returned with its symlinks evaluated.
+func newSymlinkTree(t *testing.T) string {
+	tmp, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	byID := filepath.Join(tmp, "dev", "disk", "by-id")
+	require.NoError(t, os.MkdirAll(byID, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "dev", "sdc"), nil, 0o600))
+	require.NoError(t, os.Symlink("../../sdc", filepath.Join(byID, "wwn-0x1")))
+	require.NoError(t, os.Symlink("/dev/sdc", filepath.Join(byID, "wwn-0x2")))
+	require.NoError(t, os.Symlink("../../../../outside", filepath.Join(byID, "wwn-0x3")))
+	require.NoError(t, os.Symlink("loop-b", filepath.Join(byID, "loop-a")))
+	require.NoError(t, os.Symlink("loop-a", filepath.Join(byID, "loop-b")))
+	require.NoError(t, os.Symlink("disk", filepath.Join(tmp, "dev", "disk-link")))
+	return tmp
+}
+
+func TestEvalSymlinks(t *testing.T) {
+	tmp := newSymlinkTree(t)
+	ctx := context.Background()
+
+	for _, p := range []string{
+		filepath.Join(tmp, "dev", "disk", "by-id", "wwn-0x1"),
+		filepath.Join(tmp, "dev", "disk-link", "by-id", "wwn-0x1"),
+		filepath.Join(tmp, "dev", "disk-link", "..", "disk", "by-id", "..", "by-id", "wwn-0x1"),
+		filepath.Join(tmp, "dev", "sdc"),
+	} {
+		path := p
+		require.NoError(t, EvalSymlinks(ctx, &path), p)
+		assert.Equal(t, filepath.Join(tmp, "dev", "sdc"), path, p)
+		expected, err := filepath.EvalSymlinks(p)
+		require.NoError(t, err)
+		assert.Equal(t, expected, path, p)
+	}
+
+	path := filepath.Join(tmp, "dev", "disk", "by-id", "loop-a")
+	err := EvalSymlinks(ctx, &path)
+	assert.ErrorIs(t, err, ErrTooManySymlinks)
+	assert.Equal(t, filepath.Join(tmp, "dev", "disk", "by-id", "loop-a"), path)
+
+	path = filepath.Join(tmp, "dev", "disk-link", "by-id", "wwn-0x1")
+	assert.ErrorIs(t, EvalSymlinksWithDepth(ctx, &path, 1), ErrTooManySymlinks)
+	require.NoError(t, EvalSymlinksWithDepth(ctx, &path, 2))
+
+	path = filepath.Join(tmp, "dev", "sdc", "child")
+	assert.Error(t, EvalSymlinks(ctx, &path))
+	path = filepath.Join(tmp, "dev", "missing")
+	assert.ErrorIs(t, EvalSymlinks(ctx, &path), os.ErrNotExist)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	path = filepath.Join(tmp, "dev", "sdc")
+	assert.ErrorIs(t, EvalSymlinks(cancelled, &path), context.Canceled)
+
+	// Relative paths stay relative.
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(filepath.Join(tmp, "dev", "disk")))
+	defer os.Chdir(wd) // #nosec G104
+	for p, expected := range map[string]string{
+		"by-id/wwn-0x1": "../sdc",
+		"../disk-link":  "../disk",
+		".":             ".",
+	} {
+		path := p
+		require.NoError(t, EvalSymlinks(ctx, &path), p)
+		assert.Equal(t, expected, path, p)
+	}
+}
+
+func TestEvalSymlinksWithin(t *testing.T) {
+	root := newSymlinkTree(t)
+	ctx := context.Background()
+	sdc := filepath.Join(root, "dev", "sdc")
+
+	for _, p := range []string{
+		"/dev/disk/by-id/wwn-0x1",
+		"dev/disk/by-id/wwn-0x2",
+		filepath.Join(root, "dev", "disk-link", "by-id", "wwn-0x2"),
+	} {
+		path, err := EvalSymlinksWithin(ctx, root, p)
+		require.NoError(t, err, p)
+		assert.Equal(t, sdc, path, p)
+	}
+	path, err := EvalSymlinksWithin(ctx, root, "/")
+	require.NoError(t, err)
+	assert.Equal(t, root, path)
+
+	for _, p := range []string{"/dev/disk/by-id/wwn-0x3", "../outside", "/dev/../../outside"} {
+		_, err := EvalSymlinksWithin(ctx, root, p)
+		assert.ErrorIs(t, err, ErrOutsideRoot, p)
+	}
+	// Absolute paths outside of root are relative to root.
+	_, err = EvalSymlinksWithin(ctx, root, filepath.Join(filepath.Dir(root), "dev", "sdc"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = EvalSymlinksWithin(ctx, root, "/dev/disk/by-id/loop-a")
+	assert.ErrorIs(t, err, ErrTooManySymlinks)
+}
@@ -0,0 +1,145 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// sysfsRetries is the number of times a sysfs attribute is read or
+	// written when the kernel asks to try again.
+	sysfsRetries = 3
+	// sysfsRetryInterval is how long to wait before reading or writing a
+	// sysfs attribute again.
+	sysfsRetryInterval = 10 * time.Millisecond
+)
+
+// retrySysfs calls op until it does not fail with EAGAIN or EINTR, at
+// most sysfsRetries times.
+func retrySysfs(op func() error) error {
+	var err error
+	for i := 0; i < sysfsRetries; i++ {
+		if i > 0 {
+			time.Sleep(sysfsRetryInterval)
+		}
+		err = op()
+		if !errors.Is(err, syscall.EAGAIN) && !errors.Is(err, syscall.EINTR) {
+			return err
+		}
+	}
+	return err
+}
+
+// readSysfsFile returns the content of the sysfs attribute at path,
+// without the trailing newline and spaces.
+func readSysfsFile(path string) (string, error) {
+	var buf []byte
+	err := retrySysfs(func() error {
+		var err error
+		buf, err = os.ReadFile(filepath.Clean(path))
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(buf), " \t\n"), nil
+}
+
+// writeSysfsFile writes value to the sysfs attribute at path, which must
+// exist, in a single write as sysfs expects.
+func (fs *FS) writeSysfsFile(path, value string) error {
+	if fs.dryRun(DryRunWrite, path, value) {
+		return nil
+	}
+	return retrySysfs(func() error {
+		f, err := os.OpenFile(filepath.Clean(path), os.O_WRONLY|os.O_TRUNC, 0)
+		if err != nil {
+			return err
+		}
+		if _, err := f.WriteString(value); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	})
+}
+
+// sysfsAttrName returns the path of the sysfs attribute attr, e.g.
+// block/sdb/device/state or /sys/block/sdb/device/state, relative to
+// /sys. An error wrapping ErrOutsideRoot is returned if attr is not within
+// /sys.
+func sysfsAttrName(attr string) (string, error) {
+	p := filepath.ToSlash(attr)
+	if filepath.IsAbs(attr) || strings.HasPrefix(p, "/") {
+		rel, ok := strings.CutPrefix(p, defaultSysRoot)
+		if !ok || (rel != "" && !strings.HasPrefix(rel, "/")) {
+			return "", fmt.Errorf("%s: %w", attr, ErrOutsideRoot)
+		}
+		p = strings.TrimLeft(rel, "/")
+	}
+	p = filepath.Clean(p)
+	if !filepath.IsLocal(p) {
+		return "", fmt.Errorf("%s: %w", attr, ErrOutsideRoot)
+	}
+	return p, nil
+}
+
+// sysfsAttrPath returns the location of the sysfs attribute attr with its
+// symlinks evaluated. An error wrapping ErrOutsideRoot is returned if it
+// resolves outside of sysfs.
+func (fs *FS) sysfsAttrPath(ctx context.Context, attr string) (string, error) {
+	name, err := sysfsAttrName(attr)
+	if err != nil {
+		return "", err
+	}
+	return EvalSymlinksWithin(ctx, fs.sysPath(defaultSysRoot), string(filepath.Separator)+name)
+}
+
+// sysBlockAttrPath returns the location of the attribute attr, e.g.
+// device/delete, of the block device name with its symlinks evaluated
+// within sysfs, or within SysBlockDir if that is set outside of sysfs.
+func (fs *FS) sysBlockAttrPath(ctx context.Context, name, attr string) (string, error) {
+	if dir := fs.sysBlockDir(); dir != fs.sysPath("/sys/block") {
+		return EvalSymlinksWithin(ctx, dir, string(filepath.Separator)+filepath.Join(name, attr))
+	}
+	return fs.sysfsAttrPath(ctx, filepath.Join("/sys/block", name, attr))
+}
+
+// sysfsReadAttr returns the value of the sysfs attribute attr.
+func (fs *FS) sysfsReadAttr(ctx context.Context, attr string) (string, error) {
+	path, err := fs.sysfsAttrPath(ctx, attr)
+	if err != nil {
+		return "", err
+	}
+	return readSysfsFile(path)
+}
+
+// sysfsWriteAttr writes value to the sysfs attribute attr.
+func (fs *FS) sysfsWriteAttr(ctx context.Context, attr, value string) error {
+	path, err := fs.sysfsAttrPath(ctx, attr)
+	if err != nil {
+		return err
+	}
+	if err := fs.writeSysfsFile(path, value); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
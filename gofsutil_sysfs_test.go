@@ -0,0 +1,118 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSysfsAttr(t *testing.T) {
+	tmp := t.TempDir()
+	sys := filepath.Join(tmp, "sys")
+	devDir := filepath.Join(sys, "devices", "pci0000:00", "host0", "target0:0:0", "0:0:0:1")
+	require.NoError(t, os.MkdirAll(devDir, 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(sys, "block", "sdb"), 0o750))
+	require.NoError(t, os.Symlink("../../devices/pci0000:00/host0/target0:0:0/0:0:0:1",
+		filepath.Join(sys, "block", "sdb", "device")))
+	writeSysfsAttrs(t, devDir, map[string]string{"state": "running", "rescan": ""})
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "secret"), []byte("secret"), 0o600))
+	require.NoError(t, os.Symlink("../../secret", filepath.Join(sys, "block", "escape")))
+	fs := NewFS(FSOptions{SysRoot: sys})
+	ctx := context.Background()
+
+	for _, attr := range []string{"block/sdb/device/state", "/sys/block/sdb/device/state", "block/../block/sdb/device/state"} {
+		value, err := fs.SysfsReadAttr(ctx, attr)
+		require.NoError(t, err, attr)
+		assert.Equal(t, "running", value, attr)
+	}
+
+	for _, attr := range []string{"../secret", "/etc/passwd", "/sysfs/block", "block/escape", "/sys/../secret"} {
+		_, err := fs.SysfsReadAttr(ctx, attr)
+		assert.ErrorIs(t, err, ErrOutsideRoot, attr)
+		assert.ErrorIs(t, fs.SysfsWriteAttr(ctx, attr, "1"), ErrOutsideRoot, attr)
+	}
+
+	require.NoError(t, fs.SysfsWriteAttr(ctx, "block/sdb/device/rescan", "1"))
+	buf, err := os.ReadFile(filepath.Join(devDir, "rescan"))
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(buf))
+
+	// Attributes are not created.
+	assert.Error(t, fs.SysfsWriteAttr(ctx, "block/sdb/device/delete", "1"))
+	_, err = os.Stat(filepath.Join(devDir, "delete"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = fs.SysfsReadAttr(ctx, "block/sdb/device/delete")
+	assert.Error(t, err)
+
+	fs = NewFS(FSOptions{SysRoot: sys, DryRun: true})
+	require.NoError(t, fs.SysfsWriteAttr(ctx, "block/sdb/device/state", "offline"))
+	assert.Equal(t, []DryRunAction{{Op: DryRunWrite, Path: filepath.Join(devDir, "state"), Data: "offline"}}, fs.GetDryRunActions())
+	value, err := fs.SysfsReadAttr(ctx, "block/sdb/device/state")
+	require.NoError(t, err)
+	assert.Equal(t, "running", value)
+}
+
+func TestSysfsWritesConfined(t *testing.T) {
+	tmp := t.TempDir()
+	sys := filepath.Join(tmp, "sys")
+	devDir := filepath.Join(sys, "devices", "host0", "target0:0:0", "0:0:0:1")
+	writeSysfsAttrs(t, devDir, map[string]string{"state": "running", "rescan": "", "delete": ""})
+	require.NoError(t, os.MkdirAll(filepath.Join(sys, "block", "sdb"), 0o750))
+	require.NoError(t, os.Symlink("../../devices/host0/target0:0:0/0:0:0:1", filepath.Join(sys, "block", "sdb", "device")))
+	writeSysfsAttrs(t, filepath.Join(sys, "class", "scsi_host", "host0"), map[string]string{"scan": ""})
+	writeSysfsAttrs(t, filepath.Join(sys, "class", "fc_host", "host1"), map[string]string{"issue_lip": ""})
+
+	// The attributes of a device that escapes sysfs are not written.
+	outside := filepath.Join(tmp, "outside")
+	writeSysfsAttrs(t, outside, map[string]string{"state": "running", "rescan": "", "delete": "", "scan": "", "issue_lip": ""})
+	require.NoError(t, os.MkdirAll(filepath.Join(sys, "block", "sdx"), 0o750))
+	require.NoError(t, os.Symlink("../../../outside", filepath.Join(sys, "block", "sdx", "device")))
+	require.NoError(t, os.Symlink("../../../outside", filepath.Join(sys, "class", "scsi_host", "host9")))
+	require.NoError(t, os.Symlink("../../../outside", filepath.Join(sys, "class", "fc_host", "host8")))
+
+	fs := NewFS(FSOptions{SysRoot: sys, DryRun: true})
+	ctx := context.Background()
+
+	require.NoError(t, fs.deviceRescan(ctx, "/sys/block/sdb"))
+	require.NoError(t, fs.removeBlockDevice(ctx, "/dev/sdb"))
+	assert.True(t, fs.writeScanString(ctx, "host0", "- - -"))
+	assert.Equal(t, []DryRunAction{
+		{Op: DryRunWrite, Path: filepath.Join(devDir, "rescan"), Data: "1"},
+		{Op: DryRunWrite, Path: filepath.Join(devDir, "delete"), Data: "1"},
+		{Op: DryRunWrite, Path: filepath.Join(sys, "class", "scsi_host", "host0", "scan"), Data: "- - -"},
+	}, fs.GetDryRunActions())
+	fs.ClearDryRunActions()
+
+	assert.ErrorIs(t, fs.deviceRescan(ctx, "/sys/block/sdx"), ErrOutsideRoot)
+	assert.ErrorIs(t, fs.deviceRescan(ctx, "/tmp/sdx"), ErrOutsideRoot)
+	err := fs.DeviceRescan(ctx, "/tmp/sdx")
+	var opErr *OpError
+	require.ErrorAs(t, err, &opErr)
+	assert.Equal(t, "rescan", opErr.Op)
+	assert.Equal(t, CodeInvalidArgument, ErrorCodeOf(err))
+	assert.ErrorIs(t, fs.removeBlockDevice(ctx, "/dev/sdx"), ErrOutsideRoot)
+	assert.False(t, fs.writeScanString(ctx, "host9", "- - -"))
+	assert.ErrorIs(t, fs.issueLIPToAllFCHosts(ctx), ErrOutsideRoot)
+	assert.Equal(t, []DryRunAction{
+		{Op: DryRunWrite, Path: filepath.Join(sys, "class", "fc_host", "host1", "issue_lip"), Data: "1"},
+	}, fs.GetDryRunActions())
+}
@@ -0,0 +1,68 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"fmt"
+	"os"
+)
+
+// DefaultTargetMode is the permission of the target directories created
+// by MountWithOptions when CreateTargetOptions.Mode is not set.
+const DefaultTargetMode os.FileMode = 0o750
+
+// MountOptions configures MountWithOptions.
+type MountOptions struct {
+	// Options are the mount options. Please see mount(8).
+	Options []string
+	// CreateTarget, when not nil, creates the target directory and its
+	// missing parents before mounting. The directories created are
+	// removed if the mount fails.
+	CreateTarget *CreateTargetOptions
+}
+
+// CreateTargetOptions are the permissions of the target directories
+// created by MountWithOptions.
+type CreateTargetOptions struct {
+	// Mode is the permission of the directories created. Defaults to
+	// DefaultTargetMode.
+	Mode os.FileMode
+	// Owner, when not nil, is the owner of the directories created.
+	Owner *TargetOwner
+}
+
+// TargetOwner is the owner of a mount target.
+type TargetOwner struct {
+	UID int
+	GID int
+}
+
+// TargetNotDirectoryError is returned by MountWithOptions when the target
+// is expected to be a directory but is not.
+type TargetNotDirectoryError struct {
+	// Target is the mount target.
+	Target string
+	// Mode is the mode of the existing target.
+	Mode os.FileMode
+}
+
+func (e *TargetNotDirectoryError) Error() string {
+	return fmt.Sprintf("mount target %s exists but is not a directory: %v", e.Target, e.Mode)
+}
+
+func (o *CreateTargetOptions) mode() os.FileMode {
+	if o.Mode == 0 {
+		return DefaultTargetMode
+	}
+	return o.Mode.Perm()
+}
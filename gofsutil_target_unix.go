@@ -0,0 +1,130 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// createMountTarget creates the directory target and its missing parents
+// with the requested permissions. It returns the directories it created,
+// deepest first.
+func (fs *FS) createMountTarget(target string, opts *CreateTargetOptions) ([]string, error) {
+	st, err := os.Stat(target)
+	if err == nil {
+		if !st.IsDir() {
+			return nil, &TargetNotDirectoryError{Target: target, Mode: st.Mode()}
+		}
+		return nil, nil
+	}
+	if !isMissingPath(err) {
+		return nil, err
+	}
+
+	// Find the missing directories, deepest first.
+	var missing []string
+	for dir := target; ; dir = filepath.Dir(dir) {
+		st, err := os.Stat(dir)
+		if err == nil {
+			if !st.IsDir() {
+				return nil, &TargetNotDirectoryError{Target: dir, Mode: st.Mode()}
+			}
+			break
+		}
+		if !isMissingPath(err) {
+			return nil, err
+		}
+		missing = append(missing, dir)
+		if dir == filepath.Dir(dir) {
+			break
+		}
+	}
+
+	var created []string
+	for i := len(missing) - 1; i >= 0; i-- {
+		dir := missing[i]
+		if fs.dryRun(DryRunMkdir, dir, opts.mode().String()) {
+			created = append([]string{dir}, created...)
+			if opts.Owner != nil {
+				fs.dryRun(DryRunChown, dir, fmt.Sprintf("%d:%d", opts.Owner.UID, opts.Owner.GID))
+			}
+			continue
+		}
+		if err := os.Mkdir(dir, opts.mode()); err != nil && !os.IsExist(err) {
+			fs.removeMountTargets(created)
+			return nil, fmt.Errorf("failed to create mount target %s: %v", dir, err)
+		}
+		created = append([]string{dir}, created...)
+		// The mode passed to mkdir is subject to the umask.
+		if err := os.Chmod(dir, opts.mode()); err != nil {
+			fs.removeMountTargets(created)
+			return nil, err
+		}
+		if opts.Owner != nil {
+			if err := os.Lchown(dir, opts.Owner.UID, opts.Owner.GID); err != nil {
+				fs.removeMountTargets(created)
+				return nil, err
+			}
+		}
+	}
+	return created, nil
+}
+
+// isMissingPath returns true if err means a path does not exist, either
+// because its last element or one of its parents is missing.
+func isMissingPath(err error) bool {
+	return os.IsNotExist(err) || errors.Is(err, syscall.ENOTDIR)
+}
+
+// removeMountTargets removes the directories created by createMountTarget.
+func (fs *FS) removeMountTargets(dirs []string) {
+	for _, dir := range dirs {
+		if fs.dryRun(DryRunRemove, dir, "") {
+			continue
+		}
+		if err := os.Remove(dir); err != nil {
+			log.WithField("path", dir).WithError(err).Warn("failed to remove mount target")
+		}
+	}
+}
+
+// mountWithOptions mounts source onto target, creating the target first
+// if requested.
+func (fs *FS) mountWithOptions(ctx context.Context, source, target, fsType string, opts MountOptions) error {
+	path := filepath.Clean(target)
+	if err := validatePath(path); err != nil {
+		return err
+	}
+	var created []string
+	if opts.CreateTarget != nil {
+		var err error
+		if created, err = fs.createMountTarget(path, opts.CreateTarget); err != nil {
+			return err
+		}
+	}
+	if err := fs.mount(ctx, source, path, fsType, opts.Options...); err != nil {
+		fs.removeMountTargets(created)
+		return err
+	}
+	return nil
+}
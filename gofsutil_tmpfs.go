@@ -0,0 +1,63 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"fmt"
+	"os"
+)
+
+// tmpfsModeBits are the bits of the mode of a tmpfs root directory.
+const tmpfsModeBits = os.ModePerm | os.ModeSticky | os.ModeSetgid | os.ModeSetuid
+
+// tmpfsOptions returns the mount options of a tmpfs of sizeBytes with a
+// root directory of mode, followed by opts. A size or mode of 0 leaves the
+// kernel defaults, half of the memory and 1777.
+func tmpfsOptions(sizeBytes int64, mode os.FileMode, opts []string) ([]string, error) {
+	if sizeBytes < 0 {
+		return nil, fmt.Errorf("invalid tmpfs size: %d", sizeBytes)
+	}
+	if mode&^(tmpfsModeBits|os.ModeDir) != 0 {
+		return nil, fmt.Errorf("invalid tmpfs mode: %v", mode)
+	}
+	for _, opt := range opts {
+		switch mountOptionName(opt) {
+		case "size", "mode":
+			return nil, fmt.Errorf("tmpfs option %s conflicts with the size and mode arguments", opt)
+		}
+	}
+	tmpfsOpts := make([]string, 0, len(opts)+2)
+	if sizeBytes > 0 {
+		tmpfsOpts = append(tmpfsOpts, fmt.Sprintf("size=%d", sizeBytes))
+	}
+	if mode&tmpfsModeBits != 0 {
+		tmpfsOpts = append(tmpfsOpts, fmt.Sprintf("mode=%04o", unixMode(mode)))
+	}
+	return append(tmpfsOpts, opts...), nil
+}
+
+// unixMode returns the permission, sticky, setgid and setuid bits of mode
+// as a Unix mode, e.g. 01777.
+func unixMode(mode os.FileMode) uint32 {
+	m := uint32(mode.Perm())
+	if mode&os.ModeSticky != 0 {
+		m |= 0o1000
+	}
+	if mode&os.ModeSetgid != 0 {
+		m |= 0o2000
+	}
+	if mode&os.ModeSetuid != 0 {
+		m |= 0o4000
+	}
+	return m
+}
@@ -0,0 +1,45 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// mountTmpfs mounts a tmpfs of sizeBytes with a root directory of mode on
+// target.
+func (fs *FS) mountTmpfs(ctx context.Context, target string, sizeBytes int64, mode os.FileMode, opts ...string) error {
+	if err := validatePath(filepath.Clean(target)); err != nil {
+		return err
+	}
+	tmpfsOpts, err := tmpfsOptions(sizeBytes, mode, opts)
+	if err != nil {
+		return err
+	}
+	tmpfsOpts = fs.mountDefaults.merge("tmpfs", tmpfsOpts)
+	if err := validateMountOptions(tmpfsOpts...); err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{
+		"target":  target,
+		"options": tmpfsOpts,
+	}).Info("mounting tmpfs")
+	return fs.runMount(ctx, fs.mountBinary(), "tmpfs", target, "tmpfs", tmpfsOpts...)
+}
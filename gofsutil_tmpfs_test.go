@@ -0,0 +1,67 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTmpfsOptions(t *testing.T) {
+	opts, err := tmpfsOptions(64<<20, 0o777|os.ModeSticky|os.ModeDir, []string{"noexec"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"size=67108864", "mode=1777", "noexec"}, opts)
+
+	opts, err = tmpfsOptions(1024, 0o770|os.ModeSticky|os.ModeSetgid, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"size=1024", "mode=3770"}, opts)
+
+	opts, err = tmpfsOptions(0, 0, nil)
+	require.NoError(t, err)
+	assert.Empty(t, opts)
+
+	_, err = tmpfsOptions(-1, 0, nil)
+	assert.Error(t, err)
+	_, err = tmpfsOptions(0, os.ModeSymlink|0o777, nil)
+	assert.Error(t, err)
+	// Unix mode bits beyond the permissions are not FileMode bits.
+	_, err = tmpfsOptions(0, 0o1777, nil)
+	assert.Error(t, err)
+	_, err = tmpfsOptions(1024, 0, []string{"size=2g"})
+	assert.Error(t, err)
+}
+
+func TestMountTmpfs(t *testing.T) {
+	bin := t.TempDir()
+	log := filepath.Join(t.TempDir(), "log")
+	writeRecorder(t, filepath.Join(bin, "mount"), log)
+	target := t.TempDir()
+
+	fs := NewFS(FSOptions{MountBinary: filepath.Join(bin, "mount")})
+	fs.SetDefaultMountOptions("tmpfs", "nosuid")
+	ctx := context.Background()
+	require.NoError(t, fs.MountTmpfs(ctx, target, 1<<20, 0o750))
+	out, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, " -t tmpfs -o size=1048576,mode=0750,nosuid tmpfs "+target+"\n", string(out))
+
+	assert.Error(t, fs.MountTmpfs(ctx, "/", 1<<20, 0o750))
+}
@@ -0,0 +1,72 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+// DeviceKind is the kind of a block device of a DeviceTopology.
+type DeviceKind string
+
+const (
+	// DeviceKindDisk is a whole disk, e.g. sdb or nvme0n1.
+	DeviceKindDisk DeviceKind = "disk"
+	// DeviceKindPartition is a partition of a disk, e.g. sdb1.
+	DeviceKindPartition DeviceKind = "part"
+	// DeviceKindMultipath is a device mapper multipath device.
+	DeviceKindMultipath DeviceKind = "mpath"
+	// DeviceKindDM is any other device mapper device, e.g. an LVM volume
+	// or a dm-crypt device.
+	DeviceKindDM DeviceKind = "dm"
+	// DeviceKindMD is an MD RAID array.
+	DeviceKindMD DeviceKind = "md"
+	// DeviceKindLoop is a loop device.
+	DeviceKindLoop DeviceKind = "loop"
+)
+
+// DeviceTopology is a block device and the block devices stacked on it
+// or under it, as found in sysfs.
+type DeviceTopology struct {
+	// Name is the kernel name of the device, e.g. sdb or dm-3.
+	Name string `json:"name" yaml:"name"`
+	// Path is the device node, e.g. /dev/dm-3.
+	Path string `json:"path" yaml:"path"`
+	// Kind is the kind of device.
+	Kind DeviceKind `json:"kind" yaml:"kind"`
+	// DMName is the device mapper name of a dm device, e.g. mpatha.
+	DMName string `json:"dmName,omitempty" yaml:"dmName,omitempty"`
+	// BackingFile is the file a loop device is attached to.
+	BackingFile string `json:"backingFile,omitempty" yaml:"backingFile,omitempty"`
+	// Partitions are the partitions of a disk, with their holders.
+	Partitions []*DeviceTopology `json:"partitions,omitempty" yaml:"partitions,omitempty"`
+	// Holders are the devices built on the device, e.g. the multipath
+	// device of a path or the LVM volumes of a physical volume, with
+	// their own holders.
+	Holders []*DeviceTopology `json:"holders,omitempty" yaml:"holders,omitempty"`
+	// Slaves are the devices the device is built on, e.g. the paths of a
+	// multipath device, the members of an MD array or the disk of a
+	// partition, with their own slaves. They are only filled in for the
+	// device the topology was asked for and its slaves, so that the tree
+	// does not loop.
+	Slaves []*DeviceTopology `json:"slaves,omitempty" yaml:"slaves,omitempty"`
+}
+
+// holderPaths returns the device nodes of the devices that directly hold
+// the device or one of its partitions.
+func (t *DeviceTopology) holderPaths() []string {
+	var paths []string
+	for _, h := range t.Holders {
+		paths = append(paths, h.Path)
+	}
+	for _, p := range t.Partitions {
+		paths = append(paths, p.holderPaths()...)
+	}
+	return paths
+}
@@ -0,0 +1,164 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxTopologyDepth bounds the depth of a DeviceTopology.
+const maxTopologyDepth = 16
+
+// getDeviceTopology returns the topology of the block device, given as a
+// device path, e.g. /dev/sdb or /dev/mapper/mpatha, or as a kernel name.
+func (fs *FS) getDeviceTopology(_ context.Context, device string) (*DeviceTopology, error) {
+	name := device
+	if strings.Contains(device, "/") {
+		dev, err := filepath.EvalSymlinks(device)
+		if err != nil {
+			return nil, err
+		}
+		name = filepath.Base(dev)
+	}
+	if _, err := os.Stat(fs.classBlockPath(name)); err != nil {
+		return nil, err
+	}
+	t := fs.topologyNode(name)
+	fs.addHolders(t, 0)
+	fs.addSlaves(t, 0)
+	return t, nil
+}
+
+// getBlockDeviceHolders returns the device nodes of the devices that
+// directly hold the block device or one of its partitions.
+func (fs *FS) getBlockDeviceHolders(ctx context.Context, device string) ([]string, error) {
+	t, err := fs.getDeviceTopology(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+	return t.holderPaths(), nil
+}
+
+// classBlockPath returns the sysfs directory of the block device name.
+func (fs *FS) classBlockPath(name string) string {
+	return filepath.Join(fs.sysPath("/sys/class/block"), name)
+}
+
+// topologyNode returns the device name with its kind and attributes, but
+// without the devices stacked on or under it.
+func (fs *FS) topologyNode(name string) *DeviceTopology {
+	dir := fs.classBlockPath(name)
+	t := &DeviceTopology{Name: name, Path: "/dev/" + name, Kind: DeviceKindDisk}
+	switch {
+	case sysfsEntryExists(filepath.Join(dir, "partition")):
+		t.Kind = DeviceKindPartition
+	case sysfsEntryExists(filepath.Join(dir, "dm")):
+		t.Kind = DeviceKindDM
+		t.DMName = readSysfsAttr(filepath.Join(dir, "dm", "name"))
+		if strings.HasPrefix(readSysfsAttr(filepath.Join(dir, "dm", "uuid")), "mpath-") {
+			t.Kind = DeviceKindMultipath
+		}
+	case sysfsEntryExists(filepath.Join(dir, "md")):
+		t.Kind = DeviceKindMD
+	case strings.HasPrefix(name, "loop"):
+		t.Kind = DeviceKindLoop
+		t.BackingFile = readSysfsAttr(filepath.Join(dir, "loop", "backing_file"))
+	}
+	return t
+}
+
+// addHolders adds the partitions of t and the holders of t and of its
+// partitions, recursively.
+func (fs *FS) addHolders(t *DeviceTopology, depth int) {
+	if depth >= maxTopologyDepth {
+		return
+	}
+	dir := fs.classBlockPath(t.Name)
+	for _, holder := range readDirNames(filepath.Join(dir, "holders")) {
+		h := fs.topologyNode(holder)
+		fs.addHolders(h, depth+1)
+		t.Holders = append(t.Holders, h)
+	}
+	if t.Kind == DeviceKindPartition {
+		return
+	}
+	for _, entry := range readDirNames(dir) {
+		if !strings.HasPrefix(entry, t.Name) || !sysfsEntryExists(filepath.Join(dir, entry, "partition")) {
+			continue
+		}
+		p := fs.topologyNode(entry)
+		fs.addHolders(p, depth+1)
+		t.Partitions = append(t.Partitions, p)
+	}
+}
+
+// addSlaves adds the devices t is built on, recursively: the slaves of a
+// device mapper or MD device, or the disk of a partition.
+func (fs *FS) addSlaves(t *DeviceTopology, depth int) {
+	if depth >= maxTopologyDepth {
+		return
+	}
+	dir := fs.classBlockPath(t.Name)
+	slaves := readDirNames(filepath.Join(dir, "slaves"))
+	if t.Kind == DeviceKindPartition {
+		// The sysfs directory of a partition is in that of its disk.
+		if target, err := filepath.EvalSymlinks(dir); err == nil {
+			slaves = []string{filepath.Base(filepath.Dir(target))}
+		}
+	}
+	for _, slave := range slaves {
+		s := fs.topologyNode(slave)
+		fs.addSlaves(s, depth+1)
+		t.Slaves = append(t.Slaves, s)
+	}
+}
+
+// readDirNames returns the names of the entries of dir, none if it cannot
+// be read.
+func readDirNames(dir string) []string {
+	entries, _ := os.ReadDir(dir)
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+// deviceWWN returns the WWN of the volume of the block device, which
+// identifies it across restarts unlike its kernel name or device number.
+func (fs *FS) deviceWWN(device string) (string, error) {
+	dev, err := filepath.EvalSymlinks(fs.devPath(device))
+	if err != nil {
+		return "", err
+	}
+	dir := fs.classBlockPath(filepath.Base(dev))
+	if w, ok := wwnFromDMUUID(readSysfsAttr(filepath.Join(dir, "dm", "uuid"))); ok {
+		return string(w), nil
+	}
+	wwid := readSysfsAttr(filepath.Join(dir, "device", "wwid"))
+	if wwid == "" {
+		// NVMe namespaces have the attribute on the block device.
+		wwid = readSysfsAttr(filepath.Join(dir, "wwid"))
+	}
+	if w, ok := wwnFromWWID(wwid); ok {
+		return string(w), nil
+	}
+	return "", fmt.Errorf("no WWN of %s in sysfs", device)
+}
@@ -0,0 +1,143 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTopologySysfs creates the sysfs entries of a block device under
+// sysRoot, with the /sys/class/block link to its device directory.
+func writeTopologySysfs(t *testing.T, sysRoot, devDir string, attrs map[string]string, holders, slaves []string) {
+	dir := filepath.Join(sysRoot, "devices", devDir)
+	writeSysfsAttrs(t, dir, attrs)
+	for _, sub := range []struct {
+		name  string
+		links []string
+	}{{"holders", holders}, {"slaves", slaves}} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, sub.name), 0o750))
+		for _, l := range sub.links {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, sub.name, l), nil, 0o600))
+		}
+	}
+	classBlock := filepath.Join(sysRoot, "class", "block")
+	require.NoError(t, os.MkdirAll(classBlock, 0o750))
+	require.NoError(t, os.Symlink(dir, filepath.Join(classBlock, filepath.Base(devDir))))
+}
+
+func TestGetDeviceTopology(t *testing.T) {
+	sysRoot := t.TempDir()
+	fs := NewFS(FSOptions{SysRoot: sysRoot})
+	ctx := context.Background()
+
+	// LVM volume dm-1 on multipath device dm-0 over sdb and sdc, a
+	// partition sdb1 of sdb, and a loop device.
+	writeTopologySysfs(t, sysRoot, "block/sdb", map[string]string{"size": "8"}, []string{"dm-0"}, nil)
+	writeTopologySysfs(t, sysRoot, "block/sdb/sdb1", map[string]string{"partition": "1"}, nil, nil)
+	writeTopologySysfs(t, sysRoot, "block/sdc", map[string]string{"size": "8"}, []string{"dm-0"}, nil)
+	writeTopologySysfs(t, sysRoot, "block/dm-0", nil, []string{"dm-1"}, []string{"sdb", "sdc"})
+	writeSysfsAttrs(t, filepath.Join(sysRoot, "devices/block/dm-0/dm"), map[string]string{
+		"name": "mpatha",
+		"uuid": "mpath-368ccf098001111a2222b3d4444a1b23c",
+	})
+	writeTopologySysfs(t, sysRoot, "block/dm-1", nil, nil, []string{"dm-0"})
+	writeSysfsAttrs(t, filepath.Join(sysRoot, "devices/block/dm-1/dm"), map[string]string{
+		"name": "vg-lv",
+		"uuid": "LVM-abc",
+	})
+	writeTopologySysfs(t, sysRoot, "block/md0", nil, nil, []string{"sdc"})
+	writeSysfsAttrs(t, filepath.Join(sysRoot, "devices/block/md0/md"), map[string]string{"level": "raid1"})
+	writeTopologySysfs(t, sysRoot, "block/loop0", nil, nil, nil)
+	writeSysfsAttrs(t, filepath.Join(sysRoot, "devices/block/loop0/loop"), map[string]string{"backing_file": "/var/lib/disk.img"})
+
+	t.Run("disk", func(t *testing.T) {
+		topo, err := fs.GetDeviceTopology(ctx, "sdb")
+		require.NoError(t, err)
+		assert.Equal(t, DeviceKindDisk, topo.Kind)
+		assert.Equal(t, "/dev/sdb", topo.Path)
+		require.Len(t, topo.Partitions, 1)
+		assert.Equal(t, "sdb1", topo.Partitions[0].Name)
+		assert.Equal(t, DeviceKindPartition, topo.Partitions[0].Kind)
+		require.Len(t, topo.Holders, 1)
+		mpath := topo.Holders[0]
+		assert.Equal(t, DeviceKindMultipath, mpath.Kind)
+		assert.Equal(t, "mpatha", mpath.DMName)
+		assert.Empty(t, mpath.Slaves)
+		require.Len(t, mpath.Holders, 1)
+		assert.Equal(t, DeviceKindDM, mpath.Holders[0].Kind)
+		assert.Equal(t, "vg-lv", mpath.Holders[0].DMName)
+		assert.Empty(t, topo.Slaves)
+	})
+
+	t.Run("device path", func(t *testing.T) {
+		devDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(devDir, "dm-1"), nil, 0o600))
+		require.NoError(t, os.Symlink(filepath.Join(devDir, "dm-1"), filepath.Join(devDir, "vg-lv")))
+		topo, err := fs.GetDeviceTopology(ctx, filepath.Join(devDir, "vg-lv"))
+		require.NoError(t, err)
+		assert.Equal(t, "dm-1", topo.Name)
+		require.Len(t, topo.Slaves, 1)
+		mpath := topo.Slaves[0]
+		assert.Equal(t, "dm-0", mpath.Name)
+		assert.Empty(t, mpath.Holders)
+		require.Len(t, mpath.Slaves, 2)
+		assert.Equal(t, "sdb", mpath.Slaves[0].Name)
+		assert.Equal(t, "sdc", mpath.Slaves[1].Name)
+	})
+
+	t.Run("partition", func(t *testing.T) {
+		topo, err := fs.GetDeviceTopology(ctx, "sdb1")
+		require.NoError(t, err)
+		assert.Equal(t, DeviceKindPartition, topo.Kind)
+		require.Len(t, topo.Slaves, 1)
+		assert.Equal(t, "sdb", topo.Slaves[0].Name)
+	})
+
+	t.Run("md", func(t *testing.T) {
+		topo, err := fs.GetDeviceTopology(ctx, "md0")
+		require.NoError(t, err)
+		assert.Equal(t, DeviceKindMD, topo.Kind)
+		require.Len(t, topo.Slaves, 1)
+		assert.Equal(t, "sdc", topo.Slaves[0].Name)
+	})
+
+	t.Run("loop", func(t *testing.T) {
+		topo, err := fs.GetDeviceTopology(ctx, "loop0")
+		require.NoError(t, err)
+		assert.Equal(t, DeviceKindLoop, topo.Kind)
+		assert.Equal(t, "/var/lib/disk.img", topo.BackingFile)
+	})
+
+	t.Run("holders", func(t *testing.T) {
+		holders, err := fs.GetBlockDeviceHolders(ctx, "sdc")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/dev/dm-0"}, holders)
+		holders, err = fs.GetBlockDeviceHolders(ctx, "dm-1")
+		require.NoError(t, err)
+		assert.Empty(t, holders)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := fs.GetDeviceTopology(ctx, "sdz")
+		assert.Error(t, err)
+	})
+}
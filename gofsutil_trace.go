@@ -0,0 +1,74 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the tracer, the instrumentation scope, of the
+// spans created by the package.
+const TracerName = "github.com/dell/gofsutil"
+
+// The attributes of the spans of the operations.
+const (
+	attrDevice   = attribute.Key("gofsutil.device")
+	attrFsType   = attribute.Key("gofsutil.fs_type")
+	attrTarget   = attribute.Key("gofsutil.target")
+	attrTargets  = attribute.Key("gofsutil.targets")
+	attrLUN      = attribute.Key("gofsutil.lun")
+	attrArgs     = attribute.Key("gofsutil.args")
+	attrDuration = attribute.Key("gofsutil.duration_ms")
+)
+
+// fsTracer is the tracer of an FS.
+type fsTracer struct {
+	trace.Tracer
+}
+
+// setTracerProvider makes the FS create spans with a tracer of tp, or
+// no spans if tp is nil.
+func (fs *FS) setTracerProvider(tp trace.TracerProvider) {
+	if tp == nil {
+		fs.tracer.Store(nil)
+		return
+	}
+	fs.tracer.Store(&fsTracer{Tracer: tp.Tracer(TracerName)})
+}
+
+// startSpan starts the span of the operation op, if a tracer provider is
+// set, and returns the context of the span and the function that ends
+// it. The function records the operation's duration and its error, if
+// any, and is meant to be deferred with the address of the named error
+// result of the operation.
+func (fs *FS) startSpan(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, func(*error)) {
+	tracer := fs.tracer.Load()
+	if tracer == nil {
+		return ctx, func(*error) {}
+	}
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, "gofsutil."+op, trace.WithAttributes(attrs...))
+	return ctx, func(errp *error) {
+		span.SetAttributes(attrDuration.Int64(time.Since(start).Milliseconds()))
+		if err := *errp; err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,133 @@
+//go:build linux
+// +build linux
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordingProvider is a TracerProvider that records the spans started.
+type recordingProvider struct {
+	embedded.TracerProvider
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+func (p *recordingProvider) Tracer(name string, _ ...trace.TracerOption) trace.Tracer {
+	return &recordingTracer{provider: p, name: name}
+}
+
+type recordingTracer struct {
+	embedded.Tracer
+	provider *recordingProvider
+	name     string
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	span := &recordingSpan{
+		Span:   noop.Span{},
+		tracer: t.name,
+		name:   name,
+		attrs:  map[attribute.Key]attribute.Value{},
+	}
+	config := trace.NewSpanStartConfig(opts...)
+	for _, kv := range config.Attributes() {
+		span.attrs[kv.Key] = kv.Value
+	}
+	t.provider.mu.Lock()
+	t.provider.spans = append(t.provider.spans, span)
+	t.provider.mu.Unlock()
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+type recordingSpan struct {
+	trace.Span
+	tracer string
+	name   string
+	attrs  map[attribute.Key]attribute.Value
+	err    error
+	status codes.Code
+	ended  bool
+}
+
+func (s *recordingSpan) SetAttributes(kv ...attribute.KeyValue) {
+	for _, a := range kv {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error, _ ...trace.EventOption) { s.err = err }
+
+func (s *recordingSpan) SetStatus(code codes.Code, _ string) { s.status = code }
+
+func (s *recordingSpan) End(...trace.SpanEndOption) { s.ended = true }
+
+func TestTracing(t *testing.T) {
+	ctx := context.Background()
+	fs := NewFS(FSOptions{DryRun: true})
+
+	// No spans without a provider.
+	_, end := fs.startSpan(ctx, "Mount")
+	var err error
+	end(&err)
+
+	tp := &recordingProvider{}
+	fs.SetTracerProvider(tp)
+	require.NoError(t, fs.Mount(ctx, "/dev/sdz", "/mnt/a", "xfs"))
+	assert.Error(t, fs.Mount(ctx, "/dev/sdz", "/mnt/b", "bogus"))
+	require.NoError(t, fs.Unmount(ctx, "/mnt/a"))
+	require.Len(t, tp.spans, 3)
+
+	span := tp.spans[0]
+	assert.Equal(t, TracerName, span.tracer)
+	assert.Equal(t, "gofsutil.Mount", span.name)
+	assert.Equal(t, "/dev/sdz", span.attrs[attrDevice].AsString())
+	assert.Equal(t, "/mnt/a", span.attrs[attrTarget].AsString())
+	assert.Equal(t, "xfs", span.attrs[attrFsType].AsString())
+	assert.Contains(t, span.attrs, attrDuration)
+	assert.True(t, span.ended)
+	assert.NoError(t, span.err)
+	assert.Equal(t, codes.Unset, span.status)
+
+	span = tp.spans[1]
+	assert.Error(t, span.err)
+	assert.Equal(t, codes.Error, span.status)
+	assert.True(t, span.ended)
+
+	assert.Equal(t, "gofsutil.Unmount", tp.spans[2].name)
+
+	// The span is in the context passed on by the operation.
+	ctx, end = fs.startSpan(ctx, "Test")
+	assert.Same(t, tp.spans[3], trace.SpanFromContext(ctx))
+	err = errors.New("failed")
+	end(&err)
+	assert.Equal(t, err, tp.spans[3].err)
+
+	fs.SetTracerProvider(nil)
+	require.NoError(t, fs.Unmount(ctx, "/mnt/a"))
+	assert.Len(t, tp.spans, 4)
+}
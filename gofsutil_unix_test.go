@@ -19,10 +19,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dell/gofsutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFCRescanSCSIHost(t *testing.T) {
@@ -210,3 +214,340 @@ func TestWWNToDevicePath(t *testing.T) {
 		})
 	}
 }
+
+func TestFSOptionsRoots(t *testing.T) {
+	sysRoot := t.TempDir()
+	devRoot := t.TempDir()
+	gofsutil.UseFSOptions(gofsutil.FSOptions{SysRoot: sysRoot, DevRoot: devRoot})
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+
+	// sysfs entries
+	hostDir := filepath.Join(sysRoot, "class", "fc_host", "host3")
+	require.NoError(t, os.MkdirAll(hostDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(hostDir, "port_name"), []byte("0x10000090fa1b2c3d\n"), 0o600))
+
+	wwns, err := gofsutil.GetFCHostPortWWNs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0x10000090fa1b2c3d"}, wwns)
+
+	// devfs entries
+	byID := filepath.Join(devRoot, "disk", "by-id")
+	byPath := filepath.Join(devRoot, "disk", "by-path")
+	require.NoError(t, os.MkdirAll(byID, 0o755))
+	require.NoError(t, os.MkdirAll(byPath, 0o755))
+	wwn := "60570970000197900046533030394146"
+	require.NoError(t, os.Symlink("../../sdc", filepath.Join(byID, "wwn-0x"+wwn)))
+	lunPath := filepath.Join(byPath, "ip-1.1.1.1:3260-iscsi-iqn.1992-04.com.emc:600009700bcbb70e3287017400000000-lun-1")
+	require.NoError(t, os.Symlink("../../sdd", lunPath))
+
+	symlink, device, err := gofsutil.WWNToDevicePathX(context.Background(), wwn)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(byID, "wwn-0x"+wwn), symlink)
+	assert.Equal(t, filepath.Join(devRoot, "sdc"), device)
+
+	devices, err := gofsutil.TargetIPLUNToDevicePath(context.Background(), "1.1.1.1", 1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{lunPath: filepath.Join(devRoot, "sdd")}, devices)
+}
+
+func TestPowerFlexDeviceLookup(t *testing.T) {
+	sysRoot := t.TempDir()
+	devRoot := t.TempDir()
+	gofsutil.UseFSOptions(gofsutil.FSOptions{SysRoot: sysRoot, DevRoot: devRoot})
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+
+	const (
+		systemID = "7f5d8fc72f3d8d0f"
+		volumeID = "e3ce1fb600000001"
+	)
+	byID := filepath.Join(devRoot, "disk", "by-id")
+	require.NoError(t, os.MkdirAll(byID, 0o755))
+	link := filepath.Join(byID, "emc-vol-"+systemID+"-"+volumeID)
+	require.NoError(t, os.Symlink("../../scinia", link))
+	require.NoError(t, os.Symlink("../../scinia1", link+"-part1"))
+	require.NoError(t, os.Symlink("../../scinib", filepath.Join(byID, "emc-vol-"+systemID+"-e3ce1fb700000002")))
+	for _, dev := range []string{"scinia", "scinia1", "scinib"} {
+		require.NoError(t, os.WriteFile(filepath.Join(devRoot, dev), nil, 0o600))
+		require.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "block", dev), 0o755))
+	}
+
+	for _, id := range []string{volumeID, systemID + "-" + volumeID, strings.ToUpper(volumeID)} {
+		symlink, device, err := gofsutil.WWNToDevicePathX(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, link, symlink)
+		assert.Equal(t, filepath.Join(devRoot, "scinia"), device)
+
+		devices, err := gofsutil.GetSysBlockDevicesForVolumeWWN(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"scinia"}, devices)
+	}
+
+	_, _, err := gofsutil.WWNToDevicePathX(context.Background(), "e3ce1fb800000003")
+	assert.Error(t, err)
+}
+
+func TestRescanSCSIHostX(t *testing.T) {
+	sysRoot := t.TempDir()
+	gofsutil.UseFSOptions(gofsutil.FSOptions{SysRoot: sysRoot})
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+
+	for _, host := range []string{"host0", "host1"} {
+		hostDir := filepath.Join(sysRoot, "class", "scsi_host", host)
+		require.NoError(t, os.MkdirAll(hostDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(hostDir, "scan"), nil, 0o600))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "block", "sda"), 0o755))
+
+	report, err := gofsutil.RescanSCSIHostX(context.Background(), nil, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"host0", "host1"}, report.Hosts)
+	scanFile := filepath.Join(sysRoot, "class", "scsi_host", "host0", "scan")
+	assert.Equal(t, []string{"- - 10"}, report.ScanStrings[scanFile])
+	assert.Empty(t, report.NewDevices)
+
+	written, err := os.ReadFile(scanFile)
+	require.NoError(t, err)
+	assert.Equal(t, "- - 10", string(written))
+
+	// Two targets on the same host report the host once and both scan
+	// strings.
+	const iqn = "iqn.1992-04.com.emc:600009700bcbb70e3287017400000000"
+	for i, target := range []string{"target0:0:1", "target0:0:2"} {
+		session := filepath.Join(sysRoot, "class", "iscsi_session", fmt.Sprintf("session%d", i+1))
+		require.NoError(t, os.MkdirAll(filepath.Join(session, "device", target), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(session, "targetname"), []byte(iqn+"\n"), 0o600))
+	}
+	report, err = gofsutil.RescanSCSIHostX(context.Background(), []string{iqn}, "1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"host0"}, report.Hosts)
+	assert.Equal(t, map[string][]string{scanFile: {"0 1 1", "0 2 1"}}, report.ScanStrings)
+}
+
+func TestRescanSCSIHostsForLUNs(t *testing.T) {
+	sysRoot := t.TempDir()
+	gofsutil.UseFSOptions(gofsutil.FSOptions{SysRoot: sysRoot, FullRescanInterval: -1})
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+
+	for _, host := range []string{"host0", "host1"} {
+		hostDir := filepath.Join(sysRoot, "class", "scsi_host", host)
+		require.NoError(t, os.MkdirAll(hostDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(hostDir, "scan"), nil, 0o600))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "block"), 0o755))
+
+	report, err := gofsutil.RescanSCSIHostsForLUNs(context.Background(), nil, []string{"a", "1", "0a"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"host0", "host1"}, report.Hosts)
+	scanFile := filepath.Join(sysRoot, "class", "scsi_host", "host1", "scan")
+	assert.Equal(t, []string{"- - 10", "- - 1"}, report.ScanStrings[scanFile])
+
+	// Each scan string replaces the content of the scan file.
+	written, err := os.ReadFile(scanFile)
+	require.NoError(t, err)
+	assert.Equal(t, "- - 1", string(written))
+
+	report, err = gofsutil.RescanSCSIHostsForLUNs(context.Background(), nil, []string{"a", "zz"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"- - -"}, report.ScanStrings[scanFile])
+}
+
+func TestRescanSCSIHostFullRescanLimit(t *testing.T) {
+	sysRoot := t.TempDir()
+	hostDir := filepath.Join(sysRoot, "class", "scsi_host", "host0")
+	require.NoError(t, os.MkdirAll(hostDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(hostDir, "scan"), nil, 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "block"), 0o755))
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+
+	gofsutil.UseFSOptions(gofsutil.FSOptions{SysRoot: sysRoot})
+	report, err := gofsutil.RescanSCSIHostX(context.Background(), nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"host0"}, report.Hosts)
+	assert.False(t, report.FullRescanSkipped)
+
+	report, err = gofsutil.RescanSCSIHostsForLUNs(context.Background(), nil, []string{"1"})
+	require.NoError(t, err)
+	assert.Empty(t, report.Hosts)
+	assert.True(t, report.FullRescanSkipped)
+
+	gofsutil.UseFSOptions(gofsutil.FSOptions{SysRoot: sysRoot, FullRescanInterval: time.Millisecond})
+	for i := 0; i < 2; i++ {
+		report, err = gofsutil.RescanSCSIHostX(context.Background(), nil, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"host0"}, report.Hosts)
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	gofsutil.UseFSOptions(gofsutil.FSOptions{SysRoot: sysRoot, DisableFullRescan: true})
+	report, err = gofsutil.RescanSCSIHostX(context.Background(), nil, "")
+	require.NoError(t, err)
+	assert.Empty(t, report.Hosts)
+	assert.True(t, report.FullRescanSkipped)
+}
+
+func TestIsMountPoint(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	mnt, err := gofsutil.IsMountPoint(ctx, dir)
+	require.NoError(t, err)
+	assert.False(t, mnt)
+	notMnt, err := gofsutil.IsLikelyNotMountPoint(ctx, dir)
+	require.NoError(t, err)
+	assert.True(t, notMnt)
+
+	mnt, err = gofsutil.IsMountPoint(ctx, "/")
+	require.NoError(t, err)
+	assert.True(t, mnt)
+
+	_, err = gofsutil.IsMountPoint(ctx, filepath.Join(dir, "missing"))
+	assert.True(t, os.IsNotExist(err))
+
+	if os.Geteuid() != 0 {
+		t.Skip("bind mounts require root")
+	}
+	src := filepath.Join(dir, "src")
+	tgt := filepath.Join(dir, "tgt")
+	require.NoError(t, os.Mkdir(src, 0o755))
+	require.NoError(t, os.Mkdir(tgt, 0o755))
+	if err := gofsutil.BindMount(ctx, src, tgt); err != nil {
+		t.Skipf("bind mount not permitted: %v", err)
+	}
+	defer gofsutil.Unmount(ctx, tgt)
+
+	// A bind mount from the same filesystem has the same device as its
+	// parent so only the mount table lookup detects it.
+	notMnt, err = gofsutil.IsLikelyNotMountPoint(ctx, tgt)
+	require.NoError(t, err)
+	assert.True(t, notMnt)
+	mnt, err = gofsutil.IsMountPoint(ctx, tgt)
+	require.NoError(t, err)
+	assert.True(t, mnt)
+}
+
+func TestBlockFile(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	target := filepath.Join(tmp, "pods", "volumeDevices", "pvc-1")
+
+	require.NoError(t, gofsutil.MakeBlockFile(ctx, target))
+	st, err := os.Stat(target)
+	require.NoError(t, err)
+	assert.True(t, st.Mode().IsRegular())
+	assert.Equal(t, os.FileMode(0o600), st.Mode().Perm())
+	// An existing file is left in place.
+	require.NoError(t, gofsutil.MakeBlockFile(ctx, target))
+	assert.Error(t, gofsutil.MakeBlockFile(ctx, tmp))
+
+	// The device must be a device node.
+	err = gofsutil.BindMountBlockDevice(ctx, target, filepath.Join(tmp, "other"))
+	assert.Error(t, err)
+
+	require.NoError(t, gofsutil.UnmountBlockDevice(ctx, target))
+	_, err = os.Stat(target)
+	assert.True(t, os.IsNotExist(err))
+	require.NoError(t, gofsutil.UnmountBlockDevice(ctx, target))
+}
+
+func TestMountWithOptionsCreateTarget(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	target := filepath.Join(tmp, "pods", "pvc-1", "mount")
+	opts := gofsutil.MountOptions{
+		CreateTarget: &gofsutil.CreateTargetOptions{Mode: 0o700},
+	}
+
+	// The mount fails and the directories created are removed.
+	err := gofsutil.MountWithOptions(ctx, filepath.Join(tmp, "missing"), target, "ext4", opts)
+	require.Error(t, err)
+	_, err = os.Stat(filepath.Join(tmp, "pods"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(tmp)
+	assert.NoError(t, err)
+
+	file := filepath.Join(tmp, "file")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+	err = gofsutil.MountWithOptions(ctx, "/dev/null", file, "ext4", opts)
+	var notDir *gofsutil.TargetNotDirectoryError
+	require.ErrorAs(t, err, &notDir)
+	assert.Equal(t, file, notDir.Target)
+
+	err = gofsutil.MountWithOptions(ctx, "/dev/null", filepath.Join(file, "mount"), "ext4", opts)
+	assert.ErrorAs(t, err, &notDir)
+}
+
+func TestDiskUsage(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmp, "a", "b"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmp, "c"), 0o755))
+	data := make([]byte, 64<<10)
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "a", "b", "file1"), data, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "c", "file2"), data, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(tmp, "top"), []byte("x"), 0o600))
+	// A hard link is counted once.
+	require.NoError(t, os.Link(filepath.Join(tmp, "c", "file2"), filepath.Join(tmp, "a", "link")))
+
+	usage, err := gofsutil.DiskUsage(ctx, tmp)
+	require.NoError(t, err)
+	// tmp, a, a/b, c, file1, file2 and top
+	assert.Equal(t, int64(7), usage.Inodes)
+	assert.GreaterOrEqual(t, usage.Bytes, int64(2*len(data)))
+
+	parallel := gofsutil.NewFS(gofsutil.FSOptions{DiskUsageWorkers: 4})
+	parallelUsage, err := parallel.DiskUsage(ctx, tmp)
+	require.NoError(t, err)
+	assert.Equal(t, usage, parallelUsage)
+
+	file, err := gofsutil.DiskUsage(ctx, filepath.Join(tmp, "top"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), file.Inodes)
+
+	_, err = gofsutil.DiskUsage(ctx, filepath.Join(tmp, "missing"))
+	assert.Error(t, err)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = gofsutil.DiskUsage(canceled, tmp)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = parallel.DiskUsage(canceled, tmp)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDMNameResolution(t *testing.T) {
+	ctx := context.Background()
+	sysRoot := t.TempDir()
+	devRoot := t.TempDir()
+	gofsutil.UseFSOptions(gofsutil.FSOptions{SysRoot: sysRoot, DevRoot: devRoot})
+	defer gofsutil.UseFSOptions(gofsutil.FSOptions{})
+
+	dmDir := filepath.Join(sysRoot, "block", "dm-3", "dm")
+	require.NoError(t, os.MkdirAll(dmDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dmDir, "name"), []byte("mpathb\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dmDir, "uuid"), []byte("mpath-360000970000120000549533030354435\n"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(sysRoot, "block", "sda"), 0o755))
+
+	expect := &gofsutil.DMDevice{
+		Name:       "mpathb",
+		DevPath:    filepath.Join(devRoot, "dm-3"),
+		MapperPath: filepath.Join(devRoot, "mapper", "mpathb"),
+		UUID:       "mpath-360000970000120000549533030354435",
+	}
+	for _, name := range []string{"mpathb", "/dev/mapper/mpathb"} {
+		dev, err := gofsutil.DMNameToDevPath(ctx, name)
+		require.NoError(t, err)
+		assert.Equal(t, expect, dev)
+	}
+	for _, devPath := range []string{"/dev/dm-3", "dm-3", "/dev/mapper/mpathb"} {
+		dev, err := gofsutil.DevPathToDMName(ctx, devPath)
+		require.NoError(t, err)
+		assert.Equal(t, expect, dev)
+	}
+
+	_, err := gofsutil.DMNameToDevPath(ctx, "mpathc")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = gofsutil.DevPathToDMName(ctx, "/dev/dm-4")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = gofsutil.DevPathToDMName(ctx, "/dev/sda")
+	assert.Error(t, err)
+}
@@ -0,0 +1,188 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// UnmountTreeRetries is the number of times UnmountTree tries to
+	// unmount a mount before it falls back to a lazy unmount.
+	UnmountTreeRetries = 3
+
+	// UnmountTreeRetryInterval is how long UnmountTree waits between
+	// attempts to unmount a mount.
+	UnmountTreeRetryInterval = 500 * time.Millisecond
+)
+
+// UnmountResult is the outcome of unmounting a mount found by UnmountTree.
+type UnmountResult struct {
+	// Path is the mount point.
+	Path string
+	// Attempts is the number of regular unmounts tried.
+	Attempts int
+	// Lazy is set if the mount was detached with a lazy unmount after
+	// the regular unmounts failed.
+	Lazy bool
+	// Err is the error of the unmount, nil if it succeeded.
+	Err error
+}
+
+// mountPathsUnder returns the paths of the mounts at or below root,
+// deepest first. A path mounted more than once is returned once per
+// mount, and mounts on the same path or at the same depth are returned
+// most recent first.
+func mountPathsUnder(mounts []Info, root string) []string {
+	root = filepath.Clean(root)
+	prefix := root + "/"
+	if root == "/" {
+		prefix = root
+	}
+	var under []Info
+	for _, m := range mounts {
+		p := filepath.Clean(m.Path)
+		if p == root || strings.HasPrefix(p, prefix) {
+			under = append(under, m)
+		}
+	}
+	return mountPathsDeepestFirst(under)
+}
+
+// mountPathsDeepestFirst returns the paths of the mounts, deepest first.
+// Mounts on the same path or at the same depth are returned most recent
+// first.
+func mountPathsDeepestFirst(mounts []Info) []string {
+	// order holds the indexes of mounts, the later ones are more recent.
+	order := make([]int, len(mounts))
+	depths := make([]int, len(mounts))
+	for i, m := range mounts {
+		order[i] = i
+		depths[i] = pathDepth(filepath.Clean(m.Path))
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if depths[a] != depths[b] {
+			return depths[a] > depths[b]
+		}
+		return a > b
+	})
+	paths := make([]string, len(order))
+	for i, idx := range order {
+		paths[i] = filepath.Clean(mounts[idx].Path)
+	}
+	return paths
+}
+
+// pathDepth returns the number of elements of the clean absolute path p.
+func pathDepth(p string) int {
+	if p == "/" {
+		return 0
+	}
+	return strings.Count(p, "/")
+}
+
+// unmountTree unmounts paths in order. Each path is tried up to retries
+// times with unmountFunc, waiting interval between attempts, before it is
+// detached with lazyFunc, if not nil. It returns a result per path.
+func unmountTree(
+	ctx context.Context,
+	paths []string,
+	retries int,
+	interval time.Duration,
+	unmountFunc, lazyFunc func(context.Context, string) error,
+) []UnmountResult {
+	results := make([]UnmountResult, len(paths))
+	for i, p := range paths {
+		r := &results[i]
+		r.Path = p
+		if err := ctx.Err(); err != nil {
+			r.Err = err
+			continue
+		}
+		for r.Attempts < retries {
+			if r.Attempts > 0 {
+				select {
+				case <-ctx.Done():
+				case <-time.After(interval):
+				}
+				if ctx.Err() != nil {
+					break
+				}
+			}
+			r.Attempts++
+			if r.Err = unmountFunc(ctx, p); r.Err == nil {
+				break
+			}
+		}
+		if r.Err == nil || lazyFunc == nil || ctx.Err() != nil {
+			continue
+		}
+		log.WithField("path", p).WithError(r.Err).Warn("unmount failed, trying lazy unmount")
+		if err := lazyFunc(ctx, p); err != nil {
+			r.Err = fmt.Errorf("lazy unmount of %s failed: %v, after: %w", p, err, r.Err)
+			continue
+		}
+		r.Lazy = true
+		r.Err = nil
+	}
+	return results
+}
+
+// unmountDevice unmounts the mounts of device returned by getDevMounts,
+// deepest first, with unmountTree without lazy unmounts, as a detached
+// mount would still reference the device. It then checks that no mount
+// of the device remains, returning an InUseError otherwise.
+func unmountDevice(
+	ctx context.Context,
+	device string,
+	retries int,
+	interval time.Duration,
+	getDevMounts func(context.Context, string) ([]Info, error),
+	unmountFunc func(context.Context, string) error,
+) ([]UnmountResult, error) {
+	mounts, err := getDevMounts(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+	results := unmountTree(ctx, mountPathsDeepestFirst(mounts), retries, interval, unmountFunc, nil)
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("failed to unmount %s: %w", r.Path, r.Err))
+		}
+	}
+	if len(errs) > 0 {
+		return results, errors.Join(errs...)
+	}
+	remaining, err := getDevMounts(ctx, device)
+	if err != nil {
+		return results, err
+	}
+	if len(remaining) > 0 {
+		paths := make([]string, len(remaining))
+		for i, m := range remaining {
+			paths[i] = m.Path
+		}
+		return results, &InUseError{Device: device, Reason: DeviceMounted, Users: paths}
+	}
+	return results, nil
+}
@@ -0,0 +1,155 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMountPathsUnder(t *testing.T) {
+	mounts := []Info{
+		{Path: "/"},
+		{Path: "/var/lib/kubelet"},
+		{Path: "/var/lib/kubelet/pods/a/volumes/v1"},
+		{Path: "/var/lib/kubelet-other"},
+		{Path: "/var/lib/kubelet/pods/a/volumes/v1/sub"},
+		{Path: "/var/lib/kubelet/pods/b/volumes/v2"},
+		{Path: "/var/lib/kubelet/pods/a/volumes/v1"},
+	}
+	assert.Equal(t, []string{
+		"/var/lib/kubelet/pods/a/volumes/v1/sub",
+		"/var/lib/kubelet/pods/a/volumes/v1",
+		"/var/lib/kubelet/pods/b/volumes/v2",
+		"/var/lib/kubelet/pods/a/volumes/v1",
+		"/var/lib/kubelet",
+	}, mountPathsUnder(mounts, "/var/lib/kubelet/"))
+	assert.Equal(t, []string{"/var/lib/kubelet-other"}, mountPathsUnder(mounts, "/var/lib/kubelet-other"))
+
+	// A parent remounted after its children is unmounted after them.
+	assert.Equal(t, []string{"/mnt/a/b/c", "/mnt/a/b", "/mnt/a", "/mnt/a"}, mountPathsDeepestFirst([]Info{
+		{Path: "/mnt/a"},
+		{Path: "/mnt/a/b"},
+		{Path: "/mnt/a/b/c/"},
+		{Path: "/mnt/a"},
+	}))
+	assert.Len(t, mountPathsUnder(mounts, "/"), len(mounts))
+	assert.Empty(t, mountPathsUnder(mounts, "/mnt"))
+}
+
+func TestUnmountTree(t *testing.T) {
+	ctx := context.Background()
+	failures := map[string]int{"/a/busy": 5, "/a/flaky": 1, "/a/stuck": 5}
+	unmount := func(_ context.Context, p string) error {
+		if failures[p] > 0 {
+			failures[p]--
+			return errors.New("device busy")
+		}
+		return nil
+	}
+	lazy := func(_ context.Context, p string) error {
+		if p == "/a/stuck" {
+			return errors.New("invalid argument")
+		}
+		return nil
+	}
+
+	results := unmountTree(ctx, []string{"/a/busy", "/a/flaky", "/a/stuck", "/a"}, 3, 0, unmount, lazy)
+	require.Len(t, results, 4)
+	assert.Equal(t, UnmountResult{Path: "/a/busy", Attempts: 3, Lazy: true}, results[0])
+	assert.Equal(t, UnmountResult{Path: "/a/flaky", Attempts: 2}, results[1])
+	assert.Equal(t, "/a/stuck", results[2].Path)
+	assert.False(t, results[2].Lazy)
+	assert.ErrorContains(t, results[2].Err, "invalid argument")
+	assert.ErrorContains(t, results[2].Err, "device busy")
+	assert.Equal(t, UnmountResult{Path: "/a", Attempts: 1}, results[3])
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	results = unmountTree(cancelled, []string{"/a"}, 3, 0, unmount, lazy)
+	assert.ErrorIs(t, results[0].Err, context.Canceled)
+	assert.Zero(t, results[0].Attempts)
+}
+
+func TestUnmountDevice(t *testing.T) {
+	ctx := context.Background()
+	mounts := []Info{
+		{Device: "/dev/sdb", Path: "/var/lib/kubelet/plugins/csi/globalmount"},
+		{Device: "/dev/sdb", Path: "/var/lib/kubelet/pods/a/volumes/csi/v1/mount"},
+		{Device: "/dev/sdc", Path: "/mnt/other"},
+	}
+	getDevMounts := func(_ context.Context, dev string) ([]Info, error) {
+		var infos []Info
+		for _, m := range mounts {
+			if m.Device == dev {
+				infos = append(infos, m)
+			}
+		}
+		return infos, nil
+	}
+	var unmounted []string
+	busy := map[string]int{}
+	unmount := func(_ context.Context, p string) error {
+		if busy[p] > 0 {
+			busy[p]--
+			return errors.New("device busy")
+		}
+		unmounted = append(unmounted, p)
+		for i, m := range mounts {
+			if m.Path == p {
+				mounts = append(mounts[:i], mounts[i+1:]...)
+				break
+			}
+		}
+		return nil
+	}
+
+	busy["/var/lib/kubelet/plugins/csi/globalmount"] = 1
+	results, err := unmountDevice(ctx, "/dev/sdb", 3, 0, getDevMounts, unmount)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, []string{
+		"/var/lib/kubelet/pods/a/volumes/csi/v1/mount",
+		"/var/lib/kubelet/plugins/csi/globalmount",
+	}, unmounted)
+	assert.Equal(t, 2, results[1].Attempts)
+	assert.Len(t, mounts, 1)
+
+	results, err = unmountDevice(ctx, "/dev/sdd", 3, 0, getDevMounts, unmount)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	busy["/mnt/other"] = 5
+	results, err = unmountDevice(ctx, "/dev/sdc", 3, 0, getDevMounts, unmount)
+	assert.ErrorContains(t, err, "failed to unmount /mnt/other: device busy")
+	require.Len(t, results, 1)
+	assert.Equal(t, 3, results[0].Attempts)
+	assert.False(t, results[0].Lazy)
+
+	// A mount that reappears, e.g. mounted again concurrently, is reported.
+	remount := func(_ context.Context, _ string) error { return nil }
+	_, err = unmountDevice(ctx, "/dev/sdc", 3, 0, getDevMounts, remount)
+	var inUse *InUseError
+	require.ErrorAs(t, err, &inUse)
+	assert.Equal(t, DeviceMounted, inUse.Reason)
+	assert.Equal(t, []string{"/mnt/other"}, inUse.Users)
+
+	_, err = unmountDevice(ctx, "/dev/sdc", 3, 0, func(context.Context, string) ([]Info, error) {
+		return nil, errors.New("no mountinfo")
+	}, unmount)
+	assert.Error(t, err)
+}
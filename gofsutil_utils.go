@@ -16,6 +16,7 @@ import (
 	"errors"
 	"path/filepath"
 	"regexp"
+	"strings"
 )
 
 func validatePath(path string) error {
@@ -27,8 +28,7 @@ func validatePath(path string) error {
 }
 
 func validateFsType(fsType string) error {
-	if fsType != "ext4" && fsType != "ext3" &&
-		fsType != "xfs" && fsType != "nfs" {
+	if !stringInSlice(fsType, DefaultSupportedFsTypes) {
 		return errors.New("FsType: " + fsType + " is invalid")
 	}
 
@@ -46,6 +46,20 @@ func validateMountOptions(mountOptions ...string) error {
 	return nil
 }
 
+// filterSystemdMountOptions removes the x-systemd.* options from the mount
+// options. They are only meaningful in fstab, where systemd interprets
+// them, and must not be passed on to the mount.
+func filterSystemdMountOptions(opts []string) []string {
+	filtered := make([]string, 0, len(opts))
+	for _, opt := range opts {
+		if strings.HasPrefix(opt, "x-systemd.") {
+			continue
+		}
+		filtered = append(filtered, opt)
+	}
+	return filtered
+}
+
 func validateMultipathArgs(options ...string) error {
 	for _, opt := range options {
 		// check for options
@@ -64,3 +78,12 @@ func validateMultipathArgs(options ...string) error {
 
 	return nil
 }
+
+func stringInSlice(s string, list []string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
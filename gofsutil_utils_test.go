@@ -16,6 +16,8 @@ import (
 	"errors"
 	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestValidatePath(t *testing.T) {
@@ -196,3 +198,94 @@ func TestValidateMultipathArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestUnescapeMountPath(t *testing.T) {
+	tests := []struct {
+		path   string
+		result string
+	}{
+		{path: "/mnt/plain", result: "/mnt/plain"},
+		{path: `/mnt/with\040space`, result: "/mnt/with space"},
+		{path: `/mnt/tab\011and\134slash`, result: "/mnt/tab\tand\\slash"},
+		{path: `/mnt/trailing\04`, result: `/mnt/trailing\04`},
+	}
+	for _, tt := range tests {
+		if got := unescapeMountPath(tt.path); got != tt.result {
+			t.Errorf("unescapeMountPath(%q) = %q, want %q", tt.path, got, tt.result)
+		}
+	}
+}
+
+func TestFilterSystemdMountOptions(t *testing.T) {
+	tests := []struct {
+		opts   []string
+		result string
+	}{
+		{opts: nil, result: ""},
+		{opts: []string{"rw", "noatime"}, result: "rw,noatime"},
+		{opts: []string{"x-systemd.automount", "ro", "x-systemd.device-timeout=10"}, result: "ro"},
+		{opts: []string{"x-mount.mkdir", "nofail"}, result: "x-mount.mkdir,nofail"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(filterSystemdMountOptions(tt.opts), ","); got != tt.result {
+			t.Errorf("filterSystemdMountOptions(%v) = %q, want %q", tt.opts, got, tt.result)
+		}
+	}
+}
+
+func TestSupportedFsTypes(t *testing.T) {
+	fs := NewFS(FSOptions{})
+	assert.Equal(t, []string{"cifs", "ext3", "ext4", "gfs2", "nfs", "ocfs2", "xfs"}, fs.SupportedFsTypes())
+	assert.Error(t, fs.fsTypes.validate("btrfs"))
+
+	fs.RegisterSupportedFsType("btrfs", "f2fs")
+	assert.Equal(t, []string{"btrfs", "cifs", "ext3", "ext4", "f2fs", "gfs2", "nfs", "ocfs2", "xfs"}, fs.SupportedFsTypes())
+	assert.NoError(t, fs.fsTypes.validate("btrfs"))
+	assert.NoError(t, fs.fsTypes.validate("xfs"))
+
+	fs.SetSupportedFsTypes("xfs")
+	assert.Equal(t, []string{"xfs"}, fs.SupportedFsTypes())
+	assert.Error(t, fs.fsTypes.validate("ext4"))
+	assert.NoError(t, fs.fsTypes.validate("xfs"))
+
+	fs.SetSupportedFsTypes()
+	assert.Equal(t, []string{"cifs", "ext3", "ext4", "gfs2", "nfs", "ocfs2", "xfs"}, fs.SupportedFsTypes())
+
+	// Other instances keep the defaults.
+	assert.Error(t, NewFS(FSOptions{}).fsTypes.validate("btrfs"))
+}
+
+func TestDefaultMountOptions(t *testing.T) {
+	fs := NewFS(FSOptions{})
+	assert.Empty(t, fs.DefaultMountOptions("nfs"))
+
+	fs.SetDefaultMountOptions("nfs", "hard", "vers=4.1", "noatime")
+	fs.SetDefaultMountOptions("xfs", "nouuid")
+	assert.Equal(t, []string{"hard", "vers=4.1", "noatime"}, fs.DefaultMountOptions("nfs"))
+
+	tests := []struct {
+		fsType string
+		opts   []string
+		want   []string
+	}{
+		{"nfs", nil, []string{"hard", "vers=4.1", "noatime"}},
+		{"nfs", []string{"ro"}, []string{"ro", "hard", "vers=4.1", "noatime"}},
+		{"nfs", []string{"soft", "vers=3", "atime"}, []string{"soft", "vers=3", "atime"}},
+		{"xfs", []string{"rw"}, []string{"rw", "nouuid"}},
+		{"ext4", []string{"rw"}, []string{"rw"}},
+		{"", []string{"rw"}, []string{"rw"}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, fs.mountDefaults.merge(tt.fsType, tt.opts), "%s %v", tt.fsType, tt.opts)
+	}
+
+	// The options of the caller are not modified.
+	opts := make([]string, 1, 4)
+	opts[0] = "ro"
+	fs.mountDefaults.merge("xfs", opts)
+	assert.Empty(t, opts[:2][1])
+
+	fs.SetDefaultMountOptions("nfs")
+	assert.Empty(t, fs.DefaultMountOptions("nfs"))
+	assert.Empty(t, NewFS(FSOptions{}).DefaultMountOptions("xfs"))
+}
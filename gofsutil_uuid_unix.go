@@ -0,0 +1,90 @@
+//go:build linux || darwin
+// +build linux darwin
+
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// regenerateXFSUUID gives the unmounted xfs filesystem on device a new
+// random UUID.
+func (fs *FS) regenerateXFSUUID(ctx context.Context, device string) error {
+	path := filepath.Clean(device)
+	if err := validatePath(path); err != nil {
+		return fmt.Errorf("Failed to validate path: %s error %v", device, err)
+	}
+	log.WithField("device", path).Info("generating new xfs UUID")
+	/* #nosec G204 */
+	out, err := fs.commandContext(ctx, "xfs_admin", "-U", "generate", path).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("xfs_admin failed to generate UUID for (%s) error (%v): %s",
+			device, err, strings.TrimSpace(string(out)))
+	}
+	log.WithField("device", path).WithField("output", strings.TrimSpace(string(out))).Info("xfs UUID generated")
+	return nil
+}
+
+// regenerateExtUUID gives the unmounted ext3 or ext4 filesystem on device
+// a new random UUID. tune2fs refuses to change the UUID of a filesystem
+// with metadata checksums unless it was freshly checked, in which case
+// the filesystem is checked with e2fsck first.
+func (fs *FS) regenerateExtUUID(ctx context.Context, device string) error {
+	path := filepath.Clean(device)
+	if err := validatePath(path); err != nil {
+		return fmt.Errorf("Failed to validate path: %s error %v", device, err)
+	}
+	log.WithField("device", path).Info("generating new ext UUID")
+	out, err := fs.tune2fsRandomUUID(ctx, path)
+	if err != nil && needsFsck(out) {
+		log.WithField("device", path).Info("checking ext filesystem before changing its UUID")
+		/* #nosec G204 */
+		fsckOut, fsckErr := fs.commandContext(ctx, "e2fsck", "-f", "-p", path).CombinedOutput()
+		// Exit code 1 means that errors were corrected.
+		var exitErr *exec.ExitError
+		if fsckErr != nil && !(errors.As(fsckErr, &exitErr) && exitErr.ExitCode() == 1) {
+			return fmt.Errorf("e2fsck failed for (%s) error (%v): %s",
+				device, fsckErr, strings.TrimSpace(string(fsckOut)))
+		}
+		out, err = fs.tune2fsRandomUUID(ctx, path)
+	}
+	if err != nil {
+		return fmt.Errorf("tune2fs failed to generate UUID for (%s) error (%v): %s",
+			device, err, strings.TrimSpace(out))
+	}
+	log.WithField("device", path).Info("ext UUID generated")
+	return nil
+}
+
+// tune2fsRandomUUID runs tune2fs -U random on the device.
+func (fs *FS) tune2fsRandomUUID(ctx context.Context, path string) (string, error) {
+	/* #nosec G204 */
+	out, err := fs.commandContext(ctx, "tune2fs", "-U", "random", path).CombinedOutput()
+	return string(out), err
+}
+
+// needsFsck returns true if the tune2fs output asks for the filesystem
+// to be checked first.
+func needsFsck(out string) bool {
+	out = strings.ToLower(out)
+	return strings.Contains(out, "freshly checked") || strings.Contains(out, "e2fsck -f")
+}
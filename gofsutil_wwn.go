@@ -0,0 +1,374 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// PowerMaxOUIPrefix - PowerMax format 6 OUI prefix
+var PowerMaxOUIPrefix = "6000097"
+
+// PowerStoreOUIPrefix - PowerStore format 6 OUI prefix
+var PowerStoreOUIPrefix = "68ccf09"
+
+// WWNFamily is the array family a WWN was assigned by.
+type WWNFamily string
+
+const (
+	// WWNFamilyUnknown is a WWN of an unrecognized array family.
+	WWNFamilyUnknown WWNFamily = ""
+	// WWNFamilyPowerStore is a PowerStore WWN.
+	WWNFamilyPowerStore WWNFamily = "PowerStore"
+	// WWNFamilyPowerMax is a PowerMax WWN.
+	WWNFamilyPowerMax WWNFamily = "PowerMax"
+)
+
+// NGUIDTransform returns the NVMe NGUID an array assigns to the namespace
+// of the volume with the given WWN.
+type NGUIDTransform func(wwn CanonicalWWN) string
+
+// arrayWWNPattern is the WWN naming convention of an array family.
+type arrayWWNPattern struct {
+	family WWNFamily
+	// prefix is the prefix of the WWNs, e.g. the format 6 OUI prefix.
+	prefix string
+	// toNGUID converts a WWN to the NGUID of its namespace.
+	toNGUID NGUIDTransform
+	// fromNGUID gives, for each digit of a WWN, the position of the digit
+	// in the NGUID, nil if toNGUID does not only reorder the digits.
+	fromNGUID []int
+}
+
+var (
+	// registeredArrayWWNPatterns are the patterns added with
+	// RegisterArrayWWNPattern.
+	registeredArrayWWNPatterns []arrayWWNPattern
+	// arrayWWNPatternsMu protects registeredArrayWWNPatterns.
+	arrayWWNPatternsMu sync.RWMutex
+)
+
+// nguidDigitProbe is a WWN of distinct characters, used to find the
+// position each digit of a WWN is moved to in the NGUID.
+const nguidDigitProbe = "0123456789abcdefghijklmnopqrstuv"
+
+// nguidCheckDigits are the digits following the prefix of the WWNs that
+// the positions found with nguidDigitProbe are checked with.
+var nguidCheckDigits = []string{
+	"0123456789abcdeffedcba98765432100123456789abcdef",
+	"fedcba98765432100123456789abcdeffedcba9876543210",
+	"00112233445566778899aabbccddeeff0011223344556677",
+}
+
+// newArrayWWNPattern returns the pattern of family, finding how to invert
+// toNGUID if it only reorders the digits of the WWN.
+func newArrayWWNPattern(family WWNFamily, prefix string, toNGUID NGUIDTransform) arrayWWNPattern {
+	return arrayWWNPattern{
+		family:    family,
+		prefix:    prefix,
+		toNGUID:   toNGUID,
+		fromNGUID: nguidDigitPositions(prefix, toNGUID),
+	}
+}
+
+// nguidDigitPositions returns, for each digit of a WWN, the position of
+// the digit in the NGUID given by toNGUID, or nil if toNGUID does not only
+// reorder the digits. toNGUID may reject the non-hexadecimal probe, even
+// by panicking, so the positions are then checked with WWNs starting with
+// prefix.
+func nguidDigitPositions(prefix string, toNGUID NGUIDTransform) (from []int) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Debugf("NGUID transform of WWN prefix %s cannot be inverted: %v", prefix, r)
+			from = nil
+		}
+	}()
+	probe := toNGUID(nguidDigitProbe)
+	if len(probe) != len(nguidDigitProbe) {
+		return nil
+	}
+	from = make([]int, len(nguidDigitProbe))
+	for i := range from {
+		from[i] = strings.IndexByte(probe, nguidDigitProbe[i])
+		if from[i] < 0 {
+			return nil
+		}
+	}
+	for _, digits := range nguidCheckDigits {
+		w := (prefix + digits)[:len(nguidDigitProbe)]
+		nguid := make([]byte, len(w))
+		for i, j := range from {
+			nguid[j] = w[i]
+		}
+		if toNGUID(CanonicalWWN(w)) != string(nguid) {
+			return nil
+		}
+	}
+	return from
+}
+
+// wwn returns the WWN of the namespace with the given canonical NGUID, if
+// it is one of the family.
+func (p arrayWWNPattern) wwn(nguid string) (CanonicalWWN, bool) {
+	if p.fromNGUID == nil || len(nguid) != len(p.fromNGUID) {
+		return "", false
+	}
+	w := make([]byte, len(nguid))
+	for i, j := range p.fromNGUID {
+		w[i] = nguid[j]
+	}
+	if !strings.HasPrefix(string(w), p.prefix) || p.toNGUID(CanonicalWWN(w)) != nguid {
+		return "", false
+	}
+	return CanonicalWWN(w), true
+}
+
+// builtinArrayWWNPatterns returns the patterns of the Dell arrays, which
+// use the current OUI prefixes.
+func builtinArrayWWNPatterns() []arrayWWNPattern {
+	return []arrayWWNPattern{
+		newArrayWWNPattern(WWNFamilyPowerStore, PowerStoreOUIPrefix, func(w CanonicalWWN) string {
+			s := string(w)
+			return s[10:26] + s[1:7] + s[0:1] + s[7:10] + s[26:32]
+		}),
+		newArrayWWNPattern(WWNFamilyPowerMax, PowerMaxOUIPrefix, func(w CanonicalWWN) string {
+			s := string(w)
+			return s[16:32] + s[1:7] + s[0:1] + s[7:16]
+		}),
+	}
+}
+
+// arrayWWNPatterns returns the built-in patterns followed by the
+// registered ones.
+func arrayWWNPatterns() []arrayWWNPattern {
+	patterns := builtinArrayWWNPatterns()
+	arrayWWNPatternsMu.RLock()
+	defer arrayWWNPatternsMu.RUnlock()
+	return append(patterns, registeredArrayWWNPatterns...)
+}
+
+// RegisterArrayWWNPattern adds the WWN naming convention of an array
+// family, so that WWNs starting with prefix, e.g. the format 6 OUI prefix
+// of the array, are of the family name and are matched to the NVMe
+// namespaces whose NGUID is given by nguidTransform. nguidTransform is
+// called with canonical 32 digit WWNs. If it only reorders the digits,
+// as for PowerStore and PowerMax, NGUIDToWWN inverts it as well; this is
+// found by calling it with a WWN of non-hexadecimal digits, which it may
+// reject by panicking, and checked with WWNs starting with prefix.
+func RegisterArrayWWNPattern(name WWNFamily, prefix string, nguidTransform NGUIDTransform) error {
+	prefix = strings.ToLower(prefix)
+	switch {
+	case name == WWNFamilyUnknown:
+		return errors.New("array family name is empty")
+	case prefix == "" || len(prefix) > 32 || strings.Trim(prefix, "0123456789abcdef") != "":
+		return fmt.Errorf("WWN prefix %q of %s is invalid", prefix, name)
+	case nguidTransform == nil:
+		return fmt.Errorf("NGUID transform of %s is nil", name)
+	}
+	pattern := newArrayWWNPattern(name, prefix, nguidTransform)
+	arrayWWNPatternsMu.Lock()
+	defer arrayWWNPatternsMu.Unlock()
+	for _, p := range append(builtinArrayWWNPatterns(), registeredArrayWWNPatterns...) {
+		if p.family == name {
+			return fmt.Errorf("array family %s is already registered", name)
+		}
+		if strings.HasPrefix(prefix, p.prefix) || strings.HasPrefix(p.prefix, prefix) {
+			return fmt.Errorf("WWN prefix %s of %s overlaps the prefix of %s", prefix, name, p.family)
+		}
+	}
+	registeredArrayWWNPatterns = append(registeredArrayWWNPatterns, pattern)
+	return nil
+}
+
+// CanonicalWWN is a WWN as lowercase hexadecimal digits without any
+// prefix or separators, e.g. 68ccf098001111a2222b3d4444a1b23c.
+type CanonicalWWN string
+
+// String returns the WWN.
+func (w CanonicalWWN) String() string {
+	return string(w)
+}
+
+// NAA returns the WWN in the naa. notation used by SCSI devices.
+func (w CanonicalWWN) NAA() string {
+	return "naa." + string(w)
+}
+
+// Family returns the array family of the WWN.
+func (w CanonicalWWN) Family() WWNFamily {
+	if p, ok := w.pattern(); ok {
+		return p.family
+	}
+	return WWNFamilyUnknown
+}
+
+// pattern returns the naming convention of the array family of the WWN.
+func (w CanonicalWWN) pattern() (arrayWWNPattern, bool) {
+	if len(w) != 32 {
+		return arrayWWNPattern{}, false
+	}
+	for _, p := range arrayWWNPatterns() {
+		if strings.HasPrefix(string(w), p.prefix) {
+			return p, true
+		}
+	}
+	return arrayWWNPattern{}, false
+}
+
+// NormalizeWWN converts a WWN, or an NVMe NGUID, in any of the common
+// notations, e.g. naa.6..., eui.1..., 0x6..., wwn-0x6... or with colon
+// separators, to its canonical form. Only 8 and 16 byte identifiers are
+// accepted.
+func NormalizeWWN(wwn string) (CanonicalWWN, error) {
+	w := strings.ToLower(strings.TrimSpace(wwn))
+	for _, prefix := range []string{"naa.", "eui.", "wwn-0x", "0x"} {
+		if strings.HasPrefix(w, prefix) {
+			w = strings.TrimPrefix(w, prefix)
+			break
+		}
+	}
+	w = strings.ReplaceAll(w, ":", "")
+	if len(w) != 16 && len(w) != 32 {
+		return "", fmt.Errorf("WWN: %s is invalid: unexpected length", wwn)
+	}
+	if _, err := hex.DecodeString(w); err != nil {
+		return "", fmt.Errorf("WWN: %s is invalid: not hexadecimal", wwn)
+	}
+	return CanonicalWWN(w), nil
+}
+
+// WWNToNGUID returns the NVMe NGUID the array assigns to the namespace of
+// the volume with the given WWN. Other families than the following can be
+// added with RegisterArrayWWNPattern.
+//
+//	PowerStore: wwn[10:26] + wwn[1:7] + wwn[0] + wwn[7:10] + wwn[26:32]
+//	PowerMax:   wwn[16:32] + wwn[1:7] + wwn[0] + wwn[7:16]
+func WWNToNGUID(wwn string) (string, error) {
+	w, err := NormalizeWWN(wwn)
+	if err != nil {
+		return "", err
+	}
+	if p, ok := w.pattern(); ok {
+		return p.toNGUID(w), nil
+	}
+	return "", fmt.Errorf("WWN: %s is not of a known array family", wwn)
+}
+
+// NGUIDToWWN returns the WWN of the volume with the given NVMe NGUID. It
+// is the inverse of WWNToNGUID.
+func NGUIDToWWN(nguid string) (CanonicalWWN, error) {
+	n, err := NormalizeWWN(nguid)
+	if err != nil {
+		return "", err
+	}
+	s := string(n)
+	if len(s) != 32 {
+		return "", fmt.Errorf("NGUID: %s is invalid: unexpected length", nguid)
+	}
+	for _, p := range arrayWWNPatterns() {
+		if w, ok := p.wwn(s); ok {
+			return w, nil
+		}
+	}
+	return "", fmt.Errorf("NGUID: %s is not of a known array family", nguid)
+}
+
+// wwnFromDMUUID returns the WWN of the volume of a multipath device given
+// its device-mapper UUID, e.g. mpath-3<wwn>, where 3 is the SCSI NAA
+// designator type.
+func wwnFromDMUUID(uuid string) (CanonicalWWN, bool) {
+	id, ok := strings.CutPrefix(uuid, "mpath-")
+	if !ok {
+		return "", false
+	}
+	id = strings.TrimPrefix(id, "3")
+	w, err := NormalizeWWN(id)
+	if err != nil {
+		return "", false
+	}
+	return w, true
+}
+
+// wwnFromWWID returns the WWN of the volume of a device given its sysfs
+// wwid attribute, e.g. naa.6..., or eui.<nguid> for NVMe namespaces.
+func wwnFromWWID(wwid string) (CanonicalWWN, bool) {
+	if strings.HasPrefix(wwid, "eui.") && len(wwid) == 36 {
+		if w, err := NGUIDToWWN(wwid[4:]); err == nil {
+			return w, true
+		}
+	}
+	w, err := NormalizeWWN(wwid)
+	if err != nil {
+		return "", false
+	}
+	return w, true
+}
+
+// MatchesWWN returns true if the NVMe NGUID nguid belongs to the volume
+// with the given WWN.
+func MatchesWWN(nguid, wwn string) bool {
+	/*
+			// PowerStore
+			Sample wwn : naa.68ccf098001111a2222b3d4444a1b23c
+			token1: 1111a2222b3d4444
+			token2: a1b23c
+			Sample nguid : 1111a2222b3d44448ccf096800a1b23c
+
+			// PowerMax
+			nguid: 12635330303134340000976000012000
+			wwn:   60000970000120001263533030313434
+		           11aaa111111111a11a111a1111aa1111
+		           1a111a1111aa1111 1aaa11 1 1111111a1
+			nguid: wwn[last16] 		+ wwn[1:6] 	+ wwn[0] + wwn[7:15]
+				   1263533030313434 + 000097 	+ 6		 + 000012000
+	*/
+	if len(wwn) < 32 {
+		return false
+	}
+
+	nguid = strings.TrimPrefix(strings.ToLower(nguid), "eui.")
+	wwn = strings.ToLower(wwn)
+	if strings.HasPrefix(wwn, "naa.") {
+		wwn = wwn[4:]
+	}
+
+	var token1, token2 string
+	if strings.HasPrefix(wwn, PowerStoreOUIPrefix) {
+		token1 = wwn[13 : len(wwn)-7]
+		token2 = wwn[len(wwn)-6 : len(wwn)-1]
+		log.Infof("PowerStore: %s %s %s %t", token1, token2, nguid, strings.Contains(nguid, token2))
+		if strings.Contains(nguid, token1) && strings.Contains(nguid, token2) {
+			return true
+		}
+	} else if strings.HasPrefix(wwn, PowerMaxOUIPrefix) {
+		token1 = wwn[16:]
+		token2 = wwn[1:7]
+		log.Infof("Powermax: %s %s %s %t", token1, token2, nguid, strings.HasPrefix(nguid, token1+token2))
+		if strings.HasPrefix(nguid, token1+token2) {
+			return true
+		}
+	} else if w, err := NormalizeWWN(wwn); err == nil {
+		// Registered array families
+		if want, err := WWNToNGUID(string(w)); err == nil {
+			return strings.ReplaceAll(nguid, ":", "") == want
+		}
+	}
+
+	return false
+}
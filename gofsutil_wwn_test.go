@@ -0,0 +1,183 @@
+// Copyright © 2025 Dell Inc. or its subsidiaries. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//      http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofsutil_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/dell/gofsutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	powerStoreWWN   = "68ccf098001111a2222b3d4444a1b23c"
+	powerStoreNGUID = "1111a2222b3d44448ccf096800a1b23c"
+	powerMaxWWN     = "60000970000120001263533030313434"
+	powerMaxNGUID   = "12635330303134340000976000012000"
+)
+
+func TestNormalizeWWN(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    gofsutil.CanonicalWWN
+		family  gofsutil.WWNFamily
+		wantErr bool
+	}{
+		{in: "naa." + powerStoreWWN, want: powerStoreWWN, family: gofsutil.WWNFamilyPowerStore},
+		{in: "  0x60000970000120001263533030313434\n", want: powerMaxWWN, family: gofsutil.WWNFamilyPowerMax},
+		{in: "wwn-0x68CCF098001111A2222B3D4444A1B23C", want: powerStoreWWN, family: gofsutil.WWNFamilyPowerStore},
+		{in: "eui." + powerStoreNGUID, want: powerStoreNGUID, family: gofsutil.WWNFamilyUnknown},
+		{in: "10:00:00:90:fa:12:34:56", want: "10000090fa123456", family: gofsutil.WWNFamilyUnknown},
+		{in: "naa.68ccf09800", wantErr: true},
+		{in: "naa.68ccf098001111a2222b3d4444a1b23z", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := gofsutil.NormalizeWWN(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.family, got.Family())
+			assert.Equal(t, "naa."+string(tt.want), got.NAA())
+		})
+	}
+}
+
+func TestWWNToNGUID(t *testing.T) {
+	tests := []struct {
+		name    string
+		wwn     string
+		nguid   string
+		wantErr bool
+	}{
+		{name: "PowerStore", wwn: "naa." + powerStoreWWN, nguid: powerStoreNGUID},
+		{name: "PowerMax", wwn: powerMaxWWN, nguid: powerMaxNGUID},
+		{name: "unknown family", wwn: "60060160a1b2c3d4e5f6a7b8c9d0e1f2", wantErr: true},
+		{name: "invalid", wwn: "naa.1234", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nguid, err := gofsutil.WWNToNGUID(tt.wwn)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.nguid, nguid)
+			assert.True(t, gofsutil.MatchesWWN(nguid, tt.wwn))
+
+			wwn, err := gofsutil.NGUIDToWWN("eui." + nguid)
+			require.NoError(t, err)
+			norm, err := gofsutil.NormalizeWWN(tt.wwn)
+			require.NoError(t, err)
+			assert.Equal(t, norm, wwn)
+		})
+	}
+
+	_, err := gofsutil.NGUIDToWWN("60060160a1b2c3d4e5f6a7b8c9d0e1f2")
+	assert.Error(t, err)
+	_, err = gofsutil.NGUIDToWWN("10000090fa123456")
+	assert.Error(t, err)
+}
+
+func TestMatchesWWN(t *testing.T) {
+	assert.True(t, gofsutil.MatchesWWN(powerStoreNGUID, "naa."+powerStoreWWN))
+	assert.True(t, gofsutil.MatchesWWN("eui."+powerMaxNGUID, powerMaxWWN))
+	assert.False(t, gofsutil.MatchesWWN(powerMaxNGUID, powerStoreWWN))
+	assert.False(t, gofsutil.MatchesWWN(powerStoreNGUID, "naa.68ccf09"))
+	assert.False(t, gofsutil.MatchesWWN(powerStoreNGUID, "60060160a1b2c3d4e5f6a7b8c9d0e1f2"))
+}
+
+func TestRegisterArrayWWNPattern(t *testing.T) {
+	const (
+		wwn   = "6f0e1d20123456789abcdef0123456ab"
+		nguid = "123456789abcdef0123456abf0e1d206"
+	)
+	// The NGUID moves the OUI after the volume ID, as for PowerMax.
+	transform := func(w gofsutil.CanonicalWWN) string {
+		s := string(w)
+		return s[8:32] + s[1:8] + s[0:1]
+	}
+	t.Cleanup(gofsutil.ResetArrayWWNPatterns)
+	require.NoError(t, gofsutil.RegisterArrayWWNPattern("PowerVault", "6f0e1d2", transform))
+
+	w, err := gofsutil.NormalizeWWN("naa." + wwn)
+	require.NoError(t, err)
+	assert.Equal(t, gofsutil.WWNFamily("PowerVault"), w.Family())
+	got, err := gofsutil.WWNToNGUID(wwn)
+	require.NoError(t, err)
+	assert.Equal(t, nguid, got)
+	back, err := gofsutil.NGUIDToWWN("eui." + nguid)
+	require.NoError(t, err)
+	assert.Equal(t, w, back)
+	assert.True(t, gofsutil.MatchesWWN("eui."+nguid, "naa."+wwn))
+	assert.False(t, gofsutil.MatchesWWN(powerStoreNGUID, "naa."+wwn))
+
+	// The built-in families are unchanged.
+	assert.True(t, gofsutil.MatchesWWN(powerStoreNGUID, "naa."+powerStoreWWN))
+
+	assert.Error(t, gofsutil.RegisterArrayWWNPattern("PowerVault", "6aaaaaa", transform))
+	assert.Error(t, gofsutil.RegisterArrayWWNPattern("Other", "6f0e1d2a", transform))
+	assert.Error(t, gofsutil.RegisterArrayWWNPattern("Other", gofsutil.PowerStoreOUIPrefix, transform))
+	assert.Error(t, gofsutil.RegisterArrayWWNPattern("", "6bbbbbb", transform))
+	assert.Error(t, gofsutil.RegisterArrayWWNPattern("Other", "6zz", transform))
+	assert.Error(t, gofsutil.RegisterArrayWWNPattern("Other", "6bbbbbb", nil))
+
+	// A transform that does not only reorder the digits is not inverted.
+	require.NoError(t, gofsutil.RegisterArrayWWNPattern("Hashed", "6ccccc", func(w gofsutil.CanonicalWWN) string {
+		return strings.Repeat("0", 16) + string(w[16:])
+	}))
+	hashed := "6ccccc00000000000000000000000001"
+	got, err = gofsutil.WWNToNGUID(hashed)
+	require.NoError(t, err)
+	assert.True(t, gofsutil.MatchesWWN(got, hashed))
+	_, err = gofsutil.NGUIDToWWN(got)
+	assert.Error(t, err)
+
+	// A transform that panics on the non-hexadecimal probe is registered
+	// but not inverted.
+	require.NoError(t, gofsutil.RegisterArrayWWNPattern("Decoded", "6ddddd", func(w gofsutil.CanonicalWWN) string {
+		b, err := hex.DecodeString(string(w))
+		if err != nil {
+			panic(err)
+		}
+		return hex.EncodeToString(b[8:]) + hex.EncodeToString(b[:8])
+	}))
+	decoded := "6ddddd0123456789abcdef0123456789"
+	got, err = gofsutil.WWNToNGUID(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, decoded[16:]+decoded[:16], got)
+	_, err = gofsutil.NGUIDToWWN(got)
+	assert.Error(t, err)
+
+	// A transform that only reorders the digits of the probe is checked
+	// with hexadecimal WWNs.
+	require.NoError(t, gofsutil.RegisterArrayWWNPattern("Masked", "6eeeee", func(w gofsutil.CanonicalWWN) string {
+		s := string(w[16:]) + string(w[:16])
+		if _, err := hex.DecodeString(s); err == nil {
+			s = strings.Repeat("0", 8) + s[8:]
+		}
+		return s
+	}))
+	got, err = gofsutil.WWNToNGUID("6eeeee0123456789abcdef0123456789")
+	require.NoError(t, err)
+	_, err = gofsutil.NGUIDToWWN(got)
+	assert.Error(t, err)
+}